@@ -6,10 +6,13 @@ import (
 	"github.com/wichai2002/his_v1/config"
 	"github.com/wichai2002/his_v1/internal/delivery/http"
 	"github.com/wichai2002/his_v1/internal/delivery/http/handler"
+	"github.com/wichai2002/his_v1/internal/infrastructure/analyzer"
 	"github.com/wichai2002/his_v1/internal/infrastructure/database"
+	"github.com/wichai2002/his_v1/internal/infrastructure/eligibility"
 	"github.com/wichai2002/his_v1/internal/repository"
 	"github.com/wichai2002/his_v1/internal/services"
 	"github.com/wichai2002/his_v1/pkg/jwt"
+	"github.com/wichai2002/his_v1/pkg/validation"
 )
 
 func main() {
@@ -33,28 +36,129 @@ func main() {
 	// Initialize tenant database manager
 	dbManager := database.NewTenantDBManager(db)
 
+	// Initialize the payer eligibility checker
+	eligibilityChecker, err := eligibility.NewChecker(cfg.Eligibility.Provider)
+	if err != nil {
+		log.Fatalf("Failed to initialize eligibility checker: %v", err)
+	}
+
+	// Initialize the source of lab analyzer results
+	analyzerSource, err := analyzer.NewSource(cfg.Analyzer.Provider, cfg.Analyzer.Dir)
+	if err != nil {
+		log.Fatalf("Failed to initialize analyzer source: %v", err)
+	}
+
 	// Initialize JWT service
-	jwtService := jwt.NewJWTService(cfg.JWT.SecretKey, cfg.JWT.ExpiresIn)
+	jwtService := jwt.NewJWTService(cfg.JWT.SecretKey, cfg.JWT.ExpiresIn, cfg.JWT.RefreshExpiresIn)
 
 	// Initialize repositories
 	tenantRepo := repository.NewTenantRepository(db)
 	staffRepo := repository.NewStaffRepository(db, dbManager)
 	patientRepo := repository.NewPatientRepository(db, dbManager)
+	tokenRepo := repository.NewTokenRepository(db, dbManager)
+	roleRepo := repository.NewRoleRepository(db, dbManager)
+	auditRepo := repository.NewAuditRepository(db, dbManager)
+	idempotencyRepo := repository.NewIdempotencyRepository(db, dbManager)
+	hnCounterRepo := repository.NewHNCounterRepository(db, dbManager)
+	contactRepo := repository.NewPatientContactRepository(db, dbManager)
+	addressRefRepo := repository.NewAddressReferenceRepository(db)
+	patientAddressRepo := repository.NewPatientAddressRepository(db, dbManager)
+	allergyRepo := repository.NewPatientAllergyRepository(db, dbManager)
+	coverageRepo := repository.NewPatientCoverageRepository(db, dbManager)
+	departmentRepo := repository.NewDepartmentRepository(db, dbManager)
+	encounterRepo := repository.NewEncounterRepository(db, dbManager)
+	availabilityRepo := repository.NewAvailabilityTemplateRepository(db, dbManager)
+	appointmentRepo := repository.NewAppointmentRepository(db, dbManager)
+	vitalRepo := repository.NewVitalSignRepository(db, dbManager)
+	icd10Repo := repository.NewICD10Repository(db)
+	diagnosisRepo := repository.NewDiagnosisRepository(db, dbManager)
+	drugRepo := repository.NewDrugRepository(db, dbManager)
+	medicationRepo := repository.NewMedicationOrderRepository(db, dbManager)
+	inventoryRepo := repository.NewInventoryRepository(db, dbManager)
+	labRepo := repository.NewLabRepository(db, dbManager)
 
 	// Initialize services
 	tenantService := services.NewTenantService(tenantRepo, dbManager, db)
-	staffService := services.NewStaffService(staffRepo, jwtService)
-	patientService := services.NewPatientService(patientRepo, tenantService)
+	staffService := services.NewStaffService(staffRepo, tokenRepo, jwtService)
+	patientService := services.NewPatientService(patientRepo, auditRepo, tenantService)
+	roleService := services.NewRoleService(roleRepo)
+	auditService := services.NewAuditService(auditRepo)
+	trashService := services.NewTrashService(patientRepo, staffRepo, cfg.Trash.Retention)
+	idempotencyService := services.NewIdempotencyService(idempotencyRepo)
+	hnService := services.NewHNService(tenantRepo, hnCounterRepo)
+	contactService := services.NewPatientContactService(contactRepo, patientRepo, auditRepo)
+	addressService := services.NewAddressService(addressRefRepo)
+	patientAddressService := services.NewPatientAddressService(patientAddressRepo, addressRefRepo, patientRepo, auditRepo)
+	allergyService := services.NewPatientAllergyService(allergyRepo, patientRepo, auditRepo)
+	coverageService := services.NewPatientCoverageService(coverageRepo, patientRepo, auditRepo, eligibilityChecker)
+	departmentService := services.NewDepartmentService(departmentRepo)
+	encounterService := services.NewEncounterService(encounterRepo, patientRepo, departmentRepo, auditRepo)
+	availabilityService := services.NewAvailabilityService(availabilityRepo, staffRepo, departmentRepo)
+	appointmentService := services.NewAppointmentService(appointmentRepo, patientRepo, auditRepo)
+	vitalService := services.NewVitalSignService(vitalRepo, encounterRepo, patientRepo, auditRepo)
+	icd10Service := services.NewICD10Service(icd10Repo)
+	diagnosisService := services.NewDiagnosisService(diagnosisRepo, icd10Repo, encounterRepo, patientRepo, auditRepo)
+	drugService := services.NewDrugService(drugRepo)
+	medicationService := services.NewMedicationOrderService(medicationRepo, drugRepo, encounterRepo, patientRepo, allergyRepo, inventoryRepo, auditRepo)
+	inventoryService := services.NewInventoryService(inventoryRepo, drugRepo)
+	labService := services.NewLabService(labRepo, encounterRepo, patientRepo, auditRepo, analyzerSource)
 
 	// Initialize handlers
 	staffHandler := handler.NewStaffHandler(staffService)
 	patientHandler := handler.NewPatientHandler(patientService)
+	roleHandler := handler.NewRoleHandler(roleService)
+	auditHandler := handler.NewAuditHandler(auditService)
+	trashHandler := handler.NewTrashHandler(trashService)
+	settingsHandler := handler.NewSettingsHandler(hnService)
+	contactHandler := handler.NewPatientContactHandler(contactService)
+	addressHandler := handler.NewAddressHandler(addressService)
+	patientAddressHandler := handler.NewPatientAddressHandler(patientAddressService)
+	allergyHandler := handler.NewPatientAllergyHandler(allergyService)
+	coverageHandler := handler.NewPatientCoverageHandler(coverageService)
+	departmentHandler := handler.NewDepartmentHandler(departmentService)
+	encounterHandler := handler.NewEncounterHandler(encounterService)
+	availabilityHandler := handler.NewAvailabilityHandler(availabilityService)
+	appointmentHandler := handler.NewAppointmentHandler(appointmentService)
+	vitalHandler := handler.NewVitalSignHandler(vitalService)
+	icd10Handler := handler.NewICD10Handler(icd10Service)
+	diagnosisHandler := handler.NewDiagnosisHandler(diagnosisService)
+	drugHandler := handler.NewDrugHandler(drugService)
+	medicationHandler := handler.NewMedicationOrderHandler(medicationService)
+	inventoryHandler := handler.NewInventoryHandler(inventoryService)
+	labHandler := handler.NewLabHandler(labService)
+
+	// Register custom request validators (Thai national ID, passport)
+	if err := validation.RegisterGinValidators(); err != nil {
+		log.Fatalf("Failed to register validators: %v", err)
+	}
 
 	// Setup router with tenant support
 	router := http.NewRouter(
 		staffHandler,
 		patientHandler,
+		roleHandler,
+		auditHandler,
+		trashHandler,
+		settingsHandler,
+		contactHandler,
+		addressHandler,
+		patientAddressHandler,
+		allergyHandler,
+		coverageHandler,
+		departmentHandler,
+		encounterHandler,
+		availabilityHandler,
+		appointmentHandler,
+		vitalHandler,
+		icd10Handler,
+		diagnosisHandler,
+		drugHandler,
+		medicationHandler,
+		inventoryHandler,
+		labHandler,
 		jwtService,
+		staffService,
+		idempotencyService,
 		tenantService,
 		dbManager,
 	)
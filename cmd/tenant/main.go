@@ -261,7 +261,7 @@ func truncate(s string, maxLen int) string {
 }
 
 func printUsage() {
-	fmt.Println(`
+	fmt.Print(`
 HIS Tenant Management Tool
 
 Usage:
@@ -9,9 +9,12 @@ import (
 )
 
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	JWT      JWTConfig
+	Server      ServerConfig
+	Database    DatabaseConfig
+	JWT         JWTConfig
+	Trash       TrashConfig
+	Eligibility EligibilityConfig
+	Analyzer    AnalyzerConfig
 }
 
 type ServerConfig struct {
@@ -29,7 +32,27 @@ type DatabaseConfig struct {
 
 type JWTConfig struct {
 	SecretKey string
+	// ExpiresIn is the lifetime of an access token
 	ExpiresIn time.Duration
+	// RefreshExpiresIn is the lifetime of a refresh token
+	RefreshExpiresIn time.Duration
+}
+
+type TrashConfig struct {
+	// Retention is how long soft-deleted patients and staff are kept before they can be purged
+	Retention time.Duration
+}
+
+type EligibilityConfig struct {
+	// Provider selects the payer eligibility checker; only "fake" is available so far
+	Provider string
+}
+
+type AnalyzerConfig struct {
+	// Provider selects where lab analyzer results are read from; only "filedrop" is available so far
+	Provider string
+	// Dir is the drop folder of the filedrop provider
+	Dir string
 }
 
 func LoadConfig() (*Config, error) {
@@ -37,7 +60,9 @@ func LoadConfig() (*Config, error) {
 		// .env file is optional, continue without it
 	}
 
-	expiresInHours, _ := strconv.Atoi(getEnv("JWT_EXPIRES_IN_HOURS", "24"))
+	expiresInMinutes, _ := strconv.Atoi(getEnv("JWT_EXPIRES_IN_MINUTES", "15"))
+	refreshExpiresInHours, _ := strconv.Atoi(getEnv("JWT_REFRESH_EXPIRES_IN_HOURS", "168"))
+	trashRetentionDays, _ := strconv.Atoi(getEnv("TRASH_RETENTION_DAYS", "90"))
 
 	return &Config{
 		Server: ServerConfig{
@@ -52,8 +77,19 @@ func LoadConfig() (*Config, error) {
 			SSLMode:  getEnv("DB_SSLMODE", "disable"),
 		},
 		JWT: JWTConfig{
-			SecretKey: getEnv("JWT_SECRET_KEY", "your-secret-key-change-in-production"),
-			ExpiresIn: time.Duration(expiresInHours) * time.Hour,
+			SecretKey:        getEnv("JWT_SECRET_KEY", "your-secret-key-change-in-production"),
+			ExpiresIn:        time.Duration(expiresInMinutes) * time.Minute,
+			RefreshExpiresIn: time.Duration(refreshExpiresInHours) * time.Hour,
+		},
+		Trash: TrashConfig{
+			Retention: time.Duration(trashRetentionDays) * 24 * time.Hour,
+		},
+		Eligibility: EligibilityConfig{
+			Provider: getEnv("ELIGIBILITY_PROVIDER", "fake"),
+		},
+		Analyzer: AnalyzerConfig{
+			Provider: getEnv("LAB_ANALYZER_PROVIDER", "filedrop"),
+			Dir:      getEnv("LAB_ANALYZER_DIR", "./data/analyzer"),
 		},
 	}, nil
 }
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AddressHandler struct {
+	addressService domain.AddressService
+}
+
+func NewAddressHandler(addressService domain.AddressService) *AddressHandler {
+	return &AddressHandler{
+		addressService: addressService,
+	}
+}
+
+// handleServiceError maps domain errors to appropriate HTTP status codes
+func (h *AddressHandler) handleServiceError(c *gin.Context, err error, resourceName string) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, resourceName+" not found")
+	case errors.Is(err, domain.ErrInvalidInput):
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+// ListProvinces handles GET requests for all provinces
+func (h *AddressHandler) ListProvinces(c *gin.Context) {
+	provinces, err := h.addressService.ListProvinces()
+	if err != nil {
+		h.handleServiceError(c, err, "province")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "success", provinces)
+}
+
+// ListDistricts handles GET requests for the districts of a province
+func (h *AddressHandler) ListDistricts(c *gin.Context) {
+	districts, err := h.addressService.ListDistricts(c.Param("code"))
+	if err != nil {
+		h.handleServiceError(c, err, "province")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "success", districts)
+}
+
+// ListSubdistricts handles GET requests for the subdistricts of a district
+func (h *AddressHandler) ListSubdistricts(c *gin.Context) {
+	subdistricts, err := h.addressService.ListSubdistricts(c.Param("code"))
+	if err != nil {
+		h.handleServiceError(c, err, "district")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "success", subdistricts)
+}
+
+// FindByPostalCode handles GET requests for the subdistricts served by a postal code
+func (h *AddressHandler) FindByPostalCode(c *gin.Context) {
+	locations, err := h.addressService.FindByPostalCode(c.Param("postal_code"))
+	if err != nil {
+		h.handleServiceError(c, err, "postal code")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "success", locations)
+}
+
+// Autocomplete handles GET requests suggesting subdistricts for a partial name or postal code
+func (h *AddressHandler) Autocomplete(c *gin.Context) {
+	var req domain.AddressSearchRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	locations, err := h.addressService.Autocomplete(&req)
+	if err != nil {
+		h.handleServiceError(c, err, "address")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "success", locations)
+}
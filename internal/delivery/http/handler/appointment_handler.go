@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/wichai2002/his_v1/internal/delivery/http/middleware"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AppointmentHandler struct {
+	appointmentService domain.AppointmentService
+}
+
+func NewAppointmentHandler(appointmentService domain.AppointmentService) *AppointmentHandler {
+	return &AppointmentHandler{
+		appointmentService: appointmentService,
+	}
+}
+
+// handleServiceError maps domain errors to appropriate HTTP status codes
+func (h *AppointmentHandler) handleServiceError(c *gin.Context, err error, resourceName string) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, resourceName+" not found")
+	case errors.Is(err, domain.ErrDuplicateEntry):
+		utils.ErrorResponse(c, http.StatusConflict, "slot is already booked or the patient has another appointment at that time")
+	case errors.Is(err, domain.ErrInvalidStatusTransition):
+		utils.ErrorResponse(c, http.StatusConflict, err.Error())
+	case errors.Is(err, domain.ErrPreconditionFailed):
+		utils.ErrorResponse(c, http.StatusConflict, "appointment was changed by another request")
+	case errors.Is(err, domain.ErrInvalidInput):
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, domain.ErrInvalidSchemaName):
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid tenant schema")
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+// Book handles POST requests booking a slot for a patient
+func (h *AppointmentHandler) Book(c *gin.Context) {
+	var req domain.AppointmentBookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	appointment, err := h.appointmentService.Book(&req, middleware.GetActor(c), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "patient")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "appointment booked successfully", appointment)
+}
+
+// GetByID handles GET requests for a single appointment
+func (h *AppointmentHandler) GetByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	appointment, err := h.appointmentService.GetByID(uint(id), middleware.GetActor(c), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "appointment")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "success", appointment)
+}
+
+// ListByPatient handles GET requests for the appointments of a patient, latest first
+func (h *AppointmentHandler) ListByPatient(c *gin.Context) {
+	patientID, _, ok := parsePatientChildPath(c, "", "")
+	if !ok {
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	appointments, err := h.appointmentService.ListByPatient(patientID, middleware.GetActor(c), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "patient")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "success", appointments)
+}
+
+// Reschedule handles POST requests moving a booked appointment to another slot
+func (h *AppointmentHandler) Reschedule(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	var req domain.AppointmentRescheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	appointment, err := h.appointmentService.Reschedule(uint(id), &req, middleware.GetActor(c), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "appointment")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "appointment rescheduled successfully", appointment)
+}
+
+// Cancel handles POST requests cancelling a booked appointment
+func (h *AppointmentHandler) Cancel(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	var req domain.AppointmentCancelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	appointment, err := h.appointmentService.Cancel(uint(id), &req, middleware.GetActor(c), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "appointment")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "appointment cancelled successfully", appointment)
+}
+
+// Calendar handles GET requests for the day or week calendar of a doctor or department
+func (h *AppointmentHandler) Calendar(c *gin.Context) {
+	var req domain.AppointmentCalendarRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	calendar, err := h.appointmentService.Calendar(&req, middleware.GetActor(c), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "calendar")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "success", calendar)
+}
@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/wichai2002/his_v1/internal/delivery/http/middleware"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuditHandler struct {
+	auditService domain.AuditService
+}
+
+func NewAuditHandler(auditService domain.AuditService) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+// AuditQueryResponse is a page of audit events with the total number of matches
+type AuditQueryResponse struct {
+	Events []domain.AuditEvent `json:"events"`
+	Total  int64               `json:"total"`
+}
+
+// handleServiceError maps domain errors to appropriate HTTP status codes
+func (h *AuditHandler) handleServiceError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidInput):
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid input")
+	case errors.Is(err, domain.ErrInvalidSchemaName):
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid tenant schema")
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+// Query godoc
+// @Summary Query the audit trail
+// @Description List audit events of patient records, filtered by patient or by staff member
+// @Tags audit
+// @Security BearerAuth
+// @Produce json
+// @Param patient_id query int false "Patient ID"
+// @Param staff_id query int false "Staff ID of the actor"
+// @Param action query string false "Action, e.g. patient.view"
+// @Param from query string false "From date (YYYY-MM-DD), inclusive"
+// @Param to query string false "To date (YYYY-MM-DD), inclusive"
+// @Param limit query int false "Page size (max 500)"
+// @Param offset query int false "Offset"
+// @Success 200 {object} utils.Response
+// @Router /audit/events [get]
+func (h *AuditHandler) Query(c *gin.Context) {
+	schemaName := middleware.GetTenantSchema(c)
+
+	filter := &domain.AuditFilter{Action: c.Query("action")}
+
+	var err error
+	if filter.PatientID, err = optionalUintQuery(c, "patient_id"); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid patient_id")
+		return
+	}
+	if filter.ActorID, err = optionalUintQuery(c, "staff_id"); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid staff_id")
+		return
+	}
+	if filter.From, err = optionalDateQuery(c, "from"); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid date format")
+		return
+	}
+	if filter.To, err = optionalDateQuery(c, "to"); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid date format")
+		return
+	}
+	if filter.To != nil {
+		// Make the end date inclusive
+		to := filter.To.AddDate(0, 0, 1)
+		filter.To = &to
+	}
+	if filter.Limit, err = strconv.Atoi(c.DefaultQuery("limit", "0")); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid limit")
+		return
+	}
+	if filter.Offset, err = strconv.Atoi(c.DefaultQuery("offset", "0")); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid offset")
+		return
+	}
+
+	events, total, err := h.auditService.Query(filter, schemaName)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "success", AuditQueryResponse{Events: events, Total: total})
+}
+
+// Verify godoc
+// @Summary Verify the audit trail
+// @Description Recompute the hash chain and report the first tampered event, if any
+// @Tags audit
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} utils.Response
+// @Router /audit/verify [get]
+func (h *AuditHandler) Verify(c *gin.Context) {
+	schemaName := middleware.GetTenantSchema(c)
+
+	result, err := h.auditService.Verify(schemaName)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "success", result)
+}
+
+// optionalUintQuery parses an optional unsigned integer query parameter
+func optionalUintQuery(c *gin.Context, name string) (*uint, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return nil, nil
+	}
+	value, err := strconv.ParseUint(raw, 10, 32)
+	if err != nil {
+		return nil, err
+	}
+	result := uint(value)
+	return &result, nil
+}
+
+// optionalDateQuery parses an optional YYYY-MM-DD query parameter
+func optionalDateQuery(c *gin.Context, name string) (*time.Time, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return nil, nil
+	}
+	value, err := time.Parse(domain.DateFormat, raw)
+	if err != nil {
+		return nil, err
+	}
+	return &value, nil
+}
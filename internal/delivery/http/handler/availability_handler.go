@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/wichai2002/his_v1/internal/delivery/http/middleware"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AvailabilityHandler struct {
+	availabilityService domain.AvailabilityService
+}
+
+func NewAvailabilityHandler(availabilityService domain.AvailabilityService) *AvailabilityHandler {
+	return &AvailabilityHandler{
+		availabilityService: availabilityService,
+	}
+}
+
+// handleServiceError maps domain errors to appropriate HTTP status codes
+func (h *AvailabilityHandler) handleServiceError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, "availability template not found")
+	case errors.Is(err, domain.ErrDuplicateEntry):
+		utils.ErrorResponse(c, http.StatusConflict, err.Error())
+	case errors.Is(err, domain.ErrInvalidInput):
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, domain.ErrInvalidSchemaName):
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid tenant schema")
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+// List handles GET requests for the availability templates, of one doctor with doctor_id
+func (h *AvailabilityHandler) List(c *gin.Context) {
+	doctorID, err := optionalUintQuery(c, "doctor_id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid doctor_id")
+		return
+	}
+	var id uint
+	if doctorID != nil {
+		id = *doctorID
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	templates, err := h.availabilityService.List(id, schemaName)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "success", templates)
+}
+
+// Create handles POST requests publishing a weekly availability template
+func (h *AvailabilityHandler) Create(c *gin.Context) {
+	var req domain.AvailabilityTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	template, err := h.availabilityService.Create(&req, schemaName)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "availability template created successfully", template)
+}
+
+// Expand handles POST requests creating the slots of a template up to a later day
+func (h *AvailabilityHandler) Expand(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	var req domain.AvailabilityExpandRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	result, err := h.availabilityService.Expand(uint(id), &req, schemaName)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "slots created successfully", result)
+}
+
+// Delete handles DELETE requests withdrawing a template and its free future slots
+func (h *AvailabilityHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	if err := h.availabilityService.Delete(uint(id), schemaName); err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "availability template deleted successfully", nil)
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/wichai2002/his_v1/internal/delivery/http/middleware"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DepartmentHandler struct {
+	departmentService domain.DepartmentService
+}
+
+func NewDepartmentHandler(departmentService domain.DepartmentService) *DepartmentHandler {
+	return &DepartmentHandler{
+		departmentService: departmentService,
+	}
+}
+
+// handleServiceError maps domain errors to appropriate HTTP status codes
+func (h *DepartmentHandler) handleServiceError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, "department not found")
+	case errors.Is(err, domain.ErrDuplicateEntry):
+		utils.ErrorResponse(c, http.StatusConflict, "department code already exists")
+	case errors.Is(err, domain.ErrInvalidInput):
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, domain.ErrInvalidSchemaName):
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid tenant schema")
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+// List handles GET requests for the departments, inactive ones included with include_inactive=true
+func (h *DepartmentHandler) List(c *gin.Context) {
+	includeInactive, err := strconv.ParseBool(c.DefaultQuery("include_inactive", "false"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid include_inactive")
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	departments, err := h.departmentService.List(includeInactive, schemaName)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "success", departments)
+}
+
+// Create handles POST requests adding a department
+func (h *DepartmentHandler) Create(c *gin.Context) {
+	var req domain.DepartmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	department, err := h.departmentService.Create(&req, schemaName)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "department created successfully", department)
+}
+
+// Update handles PUT requests replacing a department, including deactivating it
+func (h *DepartmentHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	var req domain.DepartmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	department, err := h.departmentService.Update(uint(id), &req, schemaName)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "department updated successfully", department)
+}
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/wichai2002/his_v1/internal/delivery/http/middleware"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DiagnosisHandler struct {
+	diagnosisService domain.DiagnosisService
+}
+
+func NewDiagnosisHandler(diagnosisService domain.DiagnosisService) *DiagnosisHandler {
+	return &DiagnosisHandler{
+		diagnosisService: diagnosisService,
+	}
+}
+
+// handleServiceError maps domain errors to appropriate HTTP status codes
+func (h *DiagnosisHandler) handleServiceError(c *gin.Context, err error, resourceName string) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, resourceName+" not found")
+	case errors.Is(err, domain.ErrDuplicateEntry):
+		utils.ErrorResponse(c, http.StatusConflict, err.Error())
+	case errors.Is(err, domain.ErrInvalidInput):
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, domain.ErrInvalidSchemaName):
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid tenant schema")
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+// Create handles POST requests recording a diagnosis for an encounter
+func (h *DiagnosisHandler) Create(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	var req domain.DiagnosisRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	diagnosis, err := h.diagnosisService.Create(uint(id), &req, middleware.GetActor(c), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "encounter")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "diagnosis created successfully", diagnosis)
+}
+
+// ListByEncounter handles GET requests for the diagnoses of an encounter, principal first
+func (h *DiagnosisHandler) ListByEncounter(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	diagnoses, err := h.diagnosisService.ListByEncounter(uint(id), middleware.GetActor(c), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "encounter")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "success", diagnoses)
+}
+
+// ListByPatient handles GET requests for the diagnoses of a patient across encounters
+func (h *DiagnosisHandler) ListByPatient(c *gin.Context) {
+	patientID, _, ok := parsePatientChildPath(c, "", "")
+	if !ok {
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	diagnoses, err := h.diagnosisService.ListByPatient(patientID, middleware.GetActor(c), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "patient")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "success", diagnoses)
+}
+
+// Delete handles DELETE requests removing a diagnosis recorded in error
+func (h *DiagnosisHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+	diagnosisID, err := strconv.ParseUint(c.Param("diagnosis_id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid diagnosis id")
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	if err := h.diagnosisService.Delete(uint(id), uint(diagnosisID), middleware.GetActor(c), schemaName); err != nil {
+		h.handleServiceError(c, err, "diagnosis")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "diagnosis deleted successfully", nil)
+}
@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/wichai2002/his_v1/internal/delivery/http/middleware"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DrugHandler struct {
+	drugService domain.DrugService
+}
+
+func NewDrugHandler(drugService domain.DrugService) *DrugHandler {
+	return &DrugHandler{
+		drugService: drugService,
+	}
+}
+
+// handleServiceError maps domain errors to appropriate HTTP status codes
+func (h *DrugHandler) handleServiceError(c *gin.Context, err error, resourceName string) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, resourceName+" not found")
+	case errors.Is(err, domain.ErrDuplicateEntry):
+		utils.ErrorResponse(c, http.StatusConflict, "duplicate "+resourceName+" entry")
+	case errors.Is(err, domain.ErrInvalidInput):
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, domain.ErrInvalidSchemaName):
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid tenant schema")
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+// List handles GET requests searching the drug master, inactive drugs included with include_inactive=true
+func (h *DrugHandler) List(c *gin.Context) {
+	var req domain.DrugListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	drugs, err := h.drugService.List(&req, schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "drug")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "success", drugs)
+}
+
+// GetByID handles GET requests for a single drug
+func (h *DrugHandler) GetByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	drug, err := h.drugService.GetByID(uint(id), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "drug")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "success", drug)
+}
+
+// Create handles POST requests adding a drug to the master
+func (h *DrugHandler) Create(c *gin.Context) {
+	var req domain.DrugRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	drug, err := h.drugService.Create(&req, schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "drug")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "drug created successfully", drug)
+}
+
+// Update handles PUT requests replacing a drug, including deactivating it
+func (h *DrugHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	var req domain.DrugRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	drug, err := h.drugService.Update(uint(id), &req, schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "drug")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "drug updated successfully", drug)
+}
+
+// ListInteractions handles GET requests for the known interactions of a drug
+func (h *DrugHandler) ListInteractions(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	interactions, err := h.drugService.ListInteractions(uint(id), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "drug")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "success", interactions)
+}
+
+// CreateInteraction handles POST requests recording an interaction of the drug with another one
+func (h *DrugHandler) CreateInteraction(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	var req domain.DrugInteractionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	interaction, err := h.drugService.CreateInteraction(uint(id), &req, schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "drug interaction")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "drug interaction created successfully", interaction)
+}
+
+// DeleteInteraction handles DELETE requests removing an interaction of the drug
+func (h *DrugHandler) DeleteInteraction(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+	interactionID, err := strconv.ParseUint(c.Param("interaction_id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid interaction id")
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	if err := h.drugService.DeleteInteraction(uint(id), uint(interactionID), schemaName); err != nil {
+		h.handleServiceError(c, err, "drug interaction")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "drug interaction deleted successfully", nil)
+}
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/wichai2002/his_v1/internal/delivery/http/middleware"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type EncounterHandler struct {
+	encounterService domain.EncounterService
+}
+
+func NewEncounterHandler(encounterService domain.EncounterService) *EncounterHandler {
+	return &EncounterHandler{
+		encounterService: encounterService,
+	}
+}
+
+// handleServiceError maps domain errors to appropriate HTTP status codes
+func (h *EncounterHandler) handleServiceError(c *gin.Context, err error, resourceName string) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, resourceName+" not found")
+	case errors.Is(err, domain.ErrInvalidStatusTransition):
+		utils.ErrorResponse(c, http.StatusConflict, err.Error())
+	case errors.Is(err, domain.ErrPreconditionFailed):
+		utils.ErrorResponse(c, http.StatusConflict, "encounter status was changed by another request")
+	case errors.Is(err, domain.ErrHNSequenceExhausted):
+		utils.ErrorResponse(c, http.StatusConflict, "VN sequence exhausted for today")
+	case errors.Is(err, domain.ErrInvalidInput):
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, domain.ErrInvalidSchemaName):
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid tenant schema")
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+// Create handles POST requests registering a visit, which issues the VN
+func (h *EncounterHandler) Create(c *gin.Context) {
+	var req domain.EncounterCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	encounter, err := h.encounterService.Create(&req, middleware.GetActor(c), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "patient")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "encounter created successfully", encounter)
+}
+
+// GetByID handles GET requests for a single encounter
+func (h *EncounterHandler) GetByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	encounter, err := h.encounterService.GetByID(uint(id), middleware.GetActor(c), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "encounter")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "success", encounter)
+}
+
+// ListByPatient handles GET requests for the visits of a patient, newest first
+func (h *EncounterHandler) ListByPatient(c *gin.Context) {
+	patientID, _, ok := parsePatientChildPath(c, "", "")
+	if !ok {
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	encounters, err := h.encounterService.ListByPatient(patientID, middleware.GetActor(c), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "patient")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "success", encounters)
+}
+
+// UpdateStatus handles POST requests moving an encounter to the next workflow step
+func (h *EncounterHandler) UpdateStatus(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	var req domain.EncounterStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	encounter, err := h.encounterService.UpdateStatus(uint(id), &req, middleware.GetActor(c), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "encounter")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "encounter status updated successfully", encounter)
+}
+
+// Queue handles GET requests for today's waiting patients of a department, in arrival order
+func (h *EncounterHandler) Queue(c *gin.Context) {
+	var req domain.EncounterQueueRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	entries, err := h.encounterService.Queue(&req, middleware.GetActor(c), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "department")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "success", entries)
+}
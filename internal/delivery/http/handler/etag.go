@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/pkg/utils"
+)
+
+// setETag sends the version of the returned record, to be echoed in If-Match on update
+func setETag(c *gin.Context, versioned domain.Versioned) {
+	c.Header("ETag", versioned.ETag())
+}
+
+// requireIfMatch returns the version in the If-Match header. It writes 428 when the header
+// is missing and 400 when it is malformed, and then reports false.
+func requireIfMatch(c *gin.Context) (uint, bool) {
+	header := c.GetHeader("If-Match")
+	if header == "" {
+		utils.ErrorResponse(c, http.StatusPreconditionRequired, "If-Match header required")
+		return 0, false
+	}
+
+	version, err := domain.ParseETag(header)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid If-Match header")
+		return 0, false
+	}
+	return version, true
+}
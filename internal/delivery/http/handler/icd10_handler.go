@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ICD10Handler struct {
+	icd10Service domain.ICD10Service
+}
+
+func NewICD10Handler(icd10Service domain.ICD10Service) *ICD10Handler {
+	return &ICD10Handler{
+		icd10Service: icd10Service,
+	}
+}
+
+// handleServiceError maps domain errors to appropriate HTTP status codes
+func (h *ICD10Handler) handleServiceError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, "ICD-10 code not found")
+	case errors.Is(err, domain.ErrInvalidInput):
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+// Search handles GET requests looking codes up by code prefix or description keywords
+func (h *ICD10Handler) Search(c *gin.Context) {
+	var req domain.ICD10SearchRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	codes, err := h.icd10Service.Search(&req)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "success", codes)
+}
+
+// GetByCode handles GET requests for a single code, with or without the dot
+func (h *ICD10Handler) GetByCode(c *gin.Context) {
+	icd10, err := h.icd10Service.GetByCode(c.Param("code"))
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "success", icd10)
+}
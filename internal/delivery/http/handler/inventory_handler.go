@@ -0,0 +1,292 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/wichai2002/his_v1/internal/delivery/http/middleware"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type InventoryHandler struct {
+	inventoryService domain.InventoryService
+}
+
+func NewInventoryHandler(inventoryService domain.InventoryService) *InventoryHandler {
+	return &InventoryHandler{
+		inventoryService: inventoryService,
+	}
+}
+
+// handleServiceError maps domain errors to appropriate HTTP status codes
+func (h *InventoryHandler) handleServiceError(c *gin.Context, err error, resourceName string) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, resourceName+" not found")
+	case errors.Is(err, domain.ErrDuplicateEntry):
+		utils.ErrorResponse(c, http.StatusConflict, "duplicate "+resourceName+" entry")
+	case errors.Is(err, domain.ErrInvalidInput):
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, domain.ErrInvalidSchemaName):
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid tenant schema")
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+// ListLocations handles GET requests for the stock locations, inactive ones included with include_inactive=true
+func (h *InventoryHandler) ListLocations(c *gin.Context) {
+	includeInactive, err := strconv.ParseBool(c.DefaultQuery("include_inactive", "false"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid include_inactive")
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	locations, err := h.inventoryService.ListLocations(includeInactive, schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "stock location")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "success", locations)
+}
+
+// CreateLocation handles POST requests adding a stock location
+func (h *InventoryHandler) CreateLocation(c *gin.Context) {
+	var req domain.StockLocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	location, err := h.inventoryService.CreateLocation(&req, schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "stock location")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "stock location created successfully", location)
+}
+
+// UpdateLocation handles PUT requests replacing a stock location, including deactivating it
+func (h *InventoryHandler) UpdateLocation(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	var req domain.StockLocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	location, err := h.inventoryService.UpdateLocation(uint(id), &req, schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "stock location")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "stock location updated successfully", location)
+}
+
+// ListReorderLevels handles GET requests for the reorder levels of a location
+func (h *InventoryHandler) ListReorderLevels(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	levels, err := h.inventoryService.ListReorderLevels(uint(id), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "stock location")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "success", levels)
+}
+
+// SetReorderLevel handles PUT requests setting the reorder level of a drug at a location
+func (h *InventoryHandler) SetReorderLevel(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	var req domain.StockReorderLevelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	level, err := h.inventoryService.SetReorderLevel(uint(id), &req, schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "stock location")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "reorder level saved successfully", level)
+}
+
+// ListStock handles GET requests for the lots with stock on hand, by location and drug
+func (h *InventoryHandler) ListStock(c *gin.Context) {
+	var req domain.StockListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	lots, err := h.inventoryService.ListStock(&req, schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "stock lot")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "success", lots)
+}
+
+// Receive handles POST requests booking a goods-received note into stock
+func (h *InventoryHandler) Receive(c *gin.Context) {
+	var req domain.GoodsReceiptRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	receipt, err := h.inventoryService.Receive(&req, middleware.GetActor(c), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "stock lot")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "goods received successfully", receipt)
+}
+
+// GetReceipt handles GET requests for a single goods-received note
+func (h *InventoryHandler) GetReceipt(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	receipt, err := h.inventoryService.GetReceipt(uint(id), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "goods receipt")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "success", receipt)
+}
+
+// ListReceipts handles GET requests for goods-received notes, newest first
+func (h *InventoryHandler) ListReceipts(c *gin.Context) {
+	var req domain.GoodsReceiptListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	receipts, err := h.inventoryService.ListReceipts(&req, schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "goods receipt")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "success", receipts)
+}
+
+// Adjust handles POST requests correcting the balance of a lot
+func (h *InventoryHandler) Adjust(c *gin.Context) {
+	var req domain.StockAdjustmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	movement, err := h.inventoryService.Adjust(&req, middleware.GetActor(c), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "stock lot")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "stock adjusted successfully", movement)
+}
+
+// StockCard handles GET requests for the ledger of a drug at a location with a running balance
+func (h *InventoryHandler) StockCard(c *gin.Context) {
+	var req domain.StockCardRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	card, err := h.inventoryService.StockCard(&req, schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "stock location")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "success", card)
+}
+
+// Alerts handles GET requests for the low-stock and near-expiry alerts
+func (h *InventoryHandler) Alerts(c *gin.Context) {
+	var req domain.StockAlertRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	alerts, err := h.inventoryService.Alerts(&req, schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "stock location")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "success", alerts)
+}
+
+// RebuildBalances handles POST requests recomputing every lot balance from the stock ledger
+func (h *InventoryHandler) RebuildBalances(c *gin.Context) {
+	schemaName := middleware.GetTenantSchema(c)
+
+	result, err := h.inventoryService.RebuildBalances(schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "stock lot")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "stock balances rebuilt successfully", result)
+}
@@ -0,0 +1,356 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/wichai2002/his_v1/internal/delivery/http/middleware"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LabHandler struct {
+	labService domain.LabService
+}
+
+func NewLabHandler(labService domain.LabService) *LabHandler {
+	return &LabHandler{
+		labService: labService,
+	}
+}
+
+// handleServiceError maps domain errors to appropriate HTTP status codes
+func (h *LabHandler) handleServiceError(c *gin.Context, err error, resourceName string) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, resourceName+" not found")
+	case errors.Is(err, domain.ErrDuplicateEntry):
+		utils.ErrorResponse(c, http.StatusConflict, "duplicate "+resourceName+" entry")
+	case errors.Is(err, domain.ErrInvalidStatusTransition):
+		utils.ErrorResponse(c, http.StatusConflict, err.Error())
+	case errors.Is(err, domain.ErrPreconditionFailed):
+		utils.ErrorResponse(c, http.StatusConflict, "lab order was changed by another request")
+	case errors.Is(err, domain.ErrLabBarcodeExhausted):
+		utils.ErrorResponse(c, http.StatusConflict, "specimen barcode sequence exhausted for today")
+	case errors.Is(err, domain.ErrAnalyzerUnavailable):
+		utils.ErrorResponse(c, http.StatusServiceUnavailable, domain.ErrAnalyzerUnavailable.Error())
+	case errors.Is(err, domain.ErrInvalidInput):
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, domain.ErrInvalidSchemaName):
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid tenant schema")
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+// ListTests handles GET requests searching the lab test catalogue by code or name
+func (h *LabHandler) ListTests(c *gin.Context) {
+	var req domain.LabTestListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	tests, err := h.labService.ListTests(&req, schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "lab test")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "success", tests)
+}
+
+// GetTest handles GET requests for a single lab test
+func (h *LabHandler) GetTest(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	test, err := h.labService.GetTest(uint(id), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "lab test")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "success", test)
+}
+
+// CreateTest handles POST requests adding a test to the catalogue
+func (h *LabHandler) CreateTest(c *gin.Context) {
+	var req domain.LabTestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	test, err := h.labService.CreateTest(&req, schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "lab test")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "lab test created successfully", test)
+}
+
+// UpdateTest handles PUT requests replacing a test of the catalogue
+func (h *LabHandler) UpdateTest(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	var req domain.LabTestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	test, err := h.labService.UpdateTest(uint(id), &req, schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "lab test")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "lab test updated successfully", test)
+}
+
+// Create handles POST requests ordering lab tests during an encounter
+func (h *LabHandler) Create(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	var req domain.LabOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	order, err := h.labService.Create(uint(id), &req, middleware.GetActor(c), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "encounter")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "lab order created successfully", order)
+}
+
+// GetByID handles GET requests for a single lab order with its specimens and results
+func (h *LabHandler) GetByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	order, err := h.labService.GetByID(uint(id), middleware.GetActor(c), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "lab order")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "success", order)
+}
+
+// ListByEncounter handles GET requests for the lab orders of an encounter
+func (h *LabHandler) ListByEncounter(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	orders, err := h.labService.ListByEncounter(uint(id), middleware.GetActor(c), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "encounter")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "success", orders)
+}
+
+// ListByPatient handles GET requests for the lab orders and results of a patient, optionally by status
+func (h *LabHandler) ListByPatient(c *gin.Context) {
+	patientID, _, ok := parsePatientChildPath(c, "", "")
+	if !ok {
+		return
+	}
+
+	var req domain.LabOrderListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	orders, err := h.labService.ListByPatient(patientID, &req, middleware.GetActor(c), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "patient")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "success", orders)
+}
+
+// Cancel handles POST requests cancelling an order before its specimens reach the lab
+func (h *LabHandler) Cancel(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	var req domain.LabCancelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	order, err := h.labService.Cancel(uint(id), &req, middleware.GetActor(c), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "lab order")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "lab order cancelled successfully", order)
+}
+
+// RecordResults handles POST requests recording results of an order by hand
+func (h *LabHandler) RecordResults(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	var req domain.LabResultRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	order, err := h.labService.RecordResults(uint(id), &req, middleware.GetActor(c), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "lab order")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "lab results recorded successfully", order)
+}
+
+// ListSpecimens handles GET requests for the specimen worklist of a day
+func (h *LabHandler) ListSpecimens(c *gin.Context) {
+	var req domain.LabSpecimenListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	entries, err := h.labService.ListSpecimens(&req, schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "specimen")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "success", entries)
+}
+
+// GetLabel handles GET requests for the barcode label of a specimen
+func (h *LabHandler) GetLabel(c *gin.Context) {
+	schemaName := middleware.GetTenantSchema(c)
+
+	label, err := h.labService.Label(c.Param("barcode"), middleware.GetActor(c), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "specimen")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "success", label)
+}
+
+// Collect handles POST requests recording that a specimen was taken from the patient
+func (h *LabHandler) Collect(c *gin.Context) {
+	schemaName := middleware.GetTenantSchema(c)
+
+	order, err := h.labService.Collect(c.Param("barcode"), middleware.GetActor(c), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "specimen")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "specimen collected successfully", order)
+}
+
+// Receive handles POST requests recording that a specimen reached the lab
+func (h *LabHandler) Receive(c *gin.Context) {
+	schemaName := middleware.GetTenantSchema(c)
+
+	order, err := h.labService.Receive(c.Param("barcode"), middleware.GetActor(c), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "specimen")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "specimen received successfully", order)
+}
+
+// Reject handles POST requests rejecting a specimen that cannot be tested, so it is collected again
+func (h *LabHandler) Reject(c *gin.Context) {
+	var req domain.LabSpecimenRejectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	order, err := h.labService.Reject(c.Param("barcode"), &req, middleware.GetActor(c), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "specimen")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "specimen rejected successfully", order)
+}
+
+// ImportResults handles POST requests importing the results waiting at the analyzer source
+func (h *LabHandler) ImportResults(c *gin.Context) {
+	schemaName := middleware.GetTenantSchema(c)
+
+	summary, err := h.labService.ImportFromAnalyzer(middleware.GetActor(c), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "lab order")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "analyzer results imported successfully", summary)
+}
@@ -0,0 +1,204 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/wichai2002/his_v1/internal/delivery/http/middleware"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MedicationOrderHandler struct {
+	orderService domain.MedicationOrderService
+}
+
+func NewMedicationOrderHandler(orderService domain.MedicationOrderService) *MedicationOrderHandler {
+	return &MedicationOrderHandler{
+		orderService: orderService,
+	}
+}
+
+// handleServiceError maps domain errors to appropriate HTTP status codes
+func (h *MedicationOrderHandler) handleServiceError(c *gin.Context, err error, resourceName string) {
+	var alertErr *domain.MedicationAlertError
+	if errors.As(err, &alertErr) {
+		utils.ErrorResponseWithData(c, http.StatusConflict,
+			"medication order has alerts, resend with acknowledge_alerts=true and an override_reason to order anyway", alertErr.Alerts)
+		return
+	}
+
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, resourceName+" not found")
+	case errors.Is(err, domain.ErrInvalidStatusTransition), errors.Is(err, domain.ErrInsufficientStock):
+		utils.ErrorResponse(c, http.StatusConflict, err.Error())
+	case errors.Is(err, domain.ErrPreconditionFailed):
+		utils.ErrorResponse(c, http.StatusConflict, "medication order was changed by another request")
+	case errors.Is(err, domain.ErrInvalidInput):
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, domain.ErrInvalidSchemaName):
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid tenant schema")
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+// Create handles POST requests prescribing a drug during an encounter
+func (h *MedicationOrderHandler) Create(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	var req domain.MedicationOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	order, err := h.orderService.Create(uint(id), &req, middleware.GetActor(c), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "encounter")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "medication order created successfully", order)
+}
+
+// GetByID handles GET requests for a single medication order
+func (h *MedicationOrderHandler) GetByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	order, err := h.orderService.GetByID(uint(id), middleware.GetActor(c), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "medication order")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "success", order)
+}
+
+// ListByEncounter handles GET requests for the medication orders of an encounter
+func (h *MedicationOrderHandler) ListByEncounter(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	orders, err := h.orderService.ListByEncounter(uint(id), middleware.GetActor(c), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "encounter")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "success", orders)
+}
+
+// ListByPatient handles GET requests for the medication orders of a patient, only running ones with active_only=true
+func (h *MedicationOrderHandler) ListByPatient(c *gin.Context) {
+	patientID, _, ok := parsePatientChildPath(c, "", "")
+	if !ok {
+		return
+	}
+
+	var req domain.MedicationOrderListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	orders, err := h.orderService.ListByPatient(patientID, &req, middleware.GetActor(c), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "patient")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "success", orders)
+}
+
+// Verify handles POST requests from a pharmacist verifying an order
+func (h *MedicationOrderHandler) Verify(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	order, err := h.orderService.Verify(uint(id), middleware.GetActor(c), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "medication order")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "medication order verified successfully", order)
+}
+
+// Dispense handles POST requests dispensing a verified order from the stock of a location
+func (h *MedicationOrderHandler) Dispense(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	var req domain.MedicationDispenseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	dispensing, err := h.orderService.Dispense(uint(id), &req, middleware.GetActor(c), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "medication order")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "medication order dispensed successfully", dispensing)
+}
+
+// Cancel handles POST requests cancelling an order that has not been dispensed
+func (h *MedicationOrderHandler) Cancel(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	var req domain.MedicationCancelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	order, err := h.orderService.Cancel(uint(id), &req, middleware.GetActor(c), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "medication order")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "medication order cancelled successfully", order)
+}
@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/wichai2002/his_v1/internal/delivery/http/middleware"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PatientAllergyHandler struct {
+	allergyService domain.PatientAllergyService
+}
+
+func NewPatientAllergyHandler(allergyService domain.PatientAllergyService) *PatientAllergyHandler {
+	return &PatientAllergyHandler{
+		allergyService: allergyService,
+	}
+}
+
+// handleServiceError maps domain errors to appropriate HTTP status codes
+func (h *PatientAllergyHandler) handleServiceError(c *gin.Context, err error, resourceName string) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, resourceName+" not found")
+	case errors.Is(err, domain.ErrDuplicateEntry):
+		utils.ErrorResponse(c, http.StatusConflict, "allergen is already recorded for this patient")
+	case errors.Is(err, domain.ErrInvalidInput):
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, domain.ErrInvalidSchemaName):
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid tenant schema")
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+// List handles GET requests for the allergies of a patient, most severe first
+func (h *PatientAllergyHandler) List(c *gin.Context) {
+	patientID, _, ok := parsePatientChildPath(c, "", "")
+	if !ok {
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	allergies, err := h.allergyService.List(patientID, middleware.GetActor(c), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "patient")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "success", allergies)
+}
+
+// Create handles POST requests recording an allergy of a patient
+func (h *PatientAllergyHandler) Create(c *gin.Context) {
+	patientID, _, ok := parsePatientChildPath(c, "", "")
+	if !ok {
+		return
+	}
+
+	var req domain.PatientAllergyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	allergy, err := h.allergyService.Create(patientID, &req, middleware.GetActor(c), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "patient")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "allergy recorded successfully", allergy)
+}
+
+// Update handles PUT requests replacing an allergy of a patient
+func (h *PatientAllergyHandler) Update(c *gin.Context) {
+	patientID, allergyID, ok := parsePatientChildPath(c, "allergy_id", "allergy")
+	if !ok {
+		return
+	}
+
+	var req domain.PatientAllergyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	allergy, err := h.allergyService.Update(patientID, allergyID, &req, middleware.GetActor(c), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "allergy")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "allergy updated successfully", allergy)
+}
+
+// Delete handles DELETE requests removing an allergy entered in error
+func (h *PatientAllergyHandler) Delete(c *gin.Context) {
+	patientID, allergyID, ok := parsePatientChildPath(c, "allergy_id", "allergy")
+	if !ok {
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	if err := h.allergyService.Delete(patientID, allergyID, middleware.GetActor(c), schemaName); err != nil {
+		h.handleServiceError(c, err, "allergy")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "allergy deleted successfully", nil)
+}
@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/wichai2002/his_v1/internal/delivery/http/middleware"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PatientContactHandler struct {
+	contactService domain.PatientContactService
+}
+
+func NewPatientContactHandler(contactService domain.PatientContactService) *PatientContactHandler {
+	return &PatientContactHandler{
+		contactService: contactService,
+	}
+}
+
+// handleServiceError maps domain errors to appropriate HTTP status codes
+func (h *PatientContactHandler) handleServiceError(c *gin.Context, err error, resourceName string) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, resourceName+" not found")
+	case errors.Is(err, domain.ErrDuplicateEntry):
+		utils.ErrorResponse(c, http.StatusConflict, "patient already has a primary contact")
+	case errors.Is(err, domain.ErrInvalidInput):
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, domain.ErrInvalidSchemaName):
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid tenant schema")
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+// parsePatientChildPath reads the patient ID and, when childParam is set, the ID of a record
+// nested under the patient such as a contact
+func parsePatientChildPath(c *gin.Context, childParam string, childName string) (uint, uint, bool) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid id")
+		return 0, 0, false
+	}
+	if childParam == "" {
+		return uint(patientID), 0, true
+	}
+	childID, err := strconv.ParseUint(c.Param(childParam), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid "+childName+" id")
+		return 0, 0, false
+	}
+	return uint(patientID), uint(childID), true
+}
+
+// List handles GET requests for the contacts of a patient, primary contact first
+func (h *PatientContactHandler) List(c *gin.Context) {
+	patientID, _, ok := parsePatientChildPath(c, "", "")
+	if !ok {
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	contacts, err := h.contactService.List(patientID, middleware.GetActor(c), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "patient")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "success", contacts)
+}
+
+// Create handles POST requests adding a contact to a patient
+func (h *PatientContactHandler) Create(c *gin.Context) {
+	patientID, _, ok := parsePatientChildPath(c, "", "")
+	if !ok {
+		return
+	}
+
+	var req domain.PatientContactRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	contact, err := h.contactService.Create(patientID, &req, middleware.GetActor(c), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "patient")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "contact created successfully", contact)
+}
+
+// Update handles PUT requests replacing a contact of a patient
+func (h *PatientContactHandler) Update(c *gin.Context) {
+	patientID, contactID, ok := parsePatientChildPath(c, "contact_id", "contact")
+	if !ok {
+		return
+	}
+
+	var req domain.PatientContactRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	contact, err := h.contactService.Update(patientID, contactID, &req, middleware.GetActor(c), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "contact")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "contact updated successfully", contact)
+}
+
+// Delete handles DELETE requests removing a contact of a patient
+func (h *PatientContactHandler) Delete(c *gin.Context) {
+	patientID, contactID, ok := parsePatientChildPath(c, "contact_id", "contact")
+	if !ok {
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	if err := h.contactService.Delete(patientID, contactID, middleware.GetActor(c), schemaName); err != nil {
+		h.handleServiceError(c, err, "contact")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "contact deleted successfully", nil)
+}
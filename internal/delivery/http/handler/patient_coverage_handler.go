@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/wichai2002/his_v1/internal/delivery/http/middleware"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PatientCoverageHandler struct {
+	coverageService domain.PatientCoverageService
+}
+
+func NewPatientCoverageHandler(coverageService domain.PatientCoverageService) *PatientCoverageHandler {
+	return &PatientCoverageHandler{
+		coverageService: coverageService,
+	}
+}
+
+// handleServiceError maps domain errors to appropriate HTTP status codes
+func (h *PatientCoverageHandler) handleServiceError(c *gin.Context, err error, resourceName string) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, resourceName+" not found")
+	case errors.Is(err, domain.ErrDuplicateEntry):
+		utils.ErrorResponse(c, http.StatusConflict, "coverage overlaps another coverage of the same scheme")
+	case errors.Is(err, domain.ErrEligibilityUnavailable):
+		utils.ErrorResponse(c, http.StatusServiceUnavailable, domain.ErrEligibilityUnavailable.Error())
+	case errors.Is(err, domain.ErrInvalidInput):
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, domain.ErrInvalidSchemaName):
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid tenant schema")
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+// List handles GET requests for all coverages of a patient, current and past
+func (h *PatientCoverageHandler) List(c *gin.Context) {
+	patientID, _, ok := parsePatientChildPath(c, "", "")
+	if !ok {
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	coverages, err := h.coverageService.List(patientID, middleware.GetActor(c), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "patient")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "success", coverages)
+}
+
+// Active handles GET requests for the coverages valid on the date query parameter, today by default
+func (h *PatientCoverageHandler) Active(c *gin.Context) {
+	patientID, _, ok := parsePatientChildPath(c, "", "")
+	if !ok {
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	coverages, err := h.coverageService.Active(patientID, c.Query("date"), middleware.GetActor(c), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "patient")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "success", coverages)
+}
+
+// Create handles POST requests adding a coverage to a patient
+func (h *PatientCoverageHandler) Create(c *gin.Context) {
+	patientID, _, ok := parsePatientChildPath(c, "", "")
+	if !ok {
+		return
+	}
+
+	var req domain.PatientCoverageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	coverage, err := h.coverageService.Create(patientID, &req, middleware.GetActor(c), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "patient")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "coverage created successfully", coverage)
+}
+
+// Update handles PUT requests replacing a coverage of a patient
+func (h *PatientCoverageHandler) Update(c *gin.Context) {
+	patientID, coverageID, ok := parsePatientChildPath(c, "coverage_id", "coverage")
+	if !ok {
+		return
+	}
+
+	var req domain.PatientCoverageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	coverage, err := h.coverageService.Update(patientID, coverageID, &req, middleware.GetActor(c), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "coverage")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "coverage updated successfully", coverage)
+}
+
+// Delete handles DELETE requests removing a coverage entered in error
+func (h *PatientCoverageHandler) Delete(c *gin.Context) {
+	patientID, coverageID, ok := parsePatientChildPath(c, "coverage_id", "coverage")
+	if !ok {
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	if err := h.coverageService.Delete(patientID, coverageID, middleware.GetActor(c), schemaName); err != nil {
+		h.handleServiceError(c, err, "coverage")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "coverage deleted successfully", nil)
+}
+
+// CheckEligibility handles POST requests checking a coverage with the payer on the date
+// query parameter, today by default
+func (h *PatientCoverageHandler) CheckEligibility(c *gin.Context) {
+	patientID, coverageID, ok := parsePatientChildPath(c, "coverage_id", "coverage")
+	if !ok {
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	result, err := h.coverageService.CheckEligibility(patientID, coverageID, c.Query("date"), middleware.GetActor(c), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "coverage")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "eligibility checked successfully", result)
+}
@@ -3,6 +3,7 @@ package handler
 import (
 	"errors"
 	"net/http"
+	"net/url"
 	"strconv"
 
 	"github.com/wichai2002/his_v1/internal/delivery/http/middleware"
@@ -24,11 +25,22 @@ func NewPatientHandler(patientService domain.PatientService) *PatientHandler {
 
 // handleServiceError maps domain errors to appropriate HTTP status codes
 func (h *PatientHandler) handleServiceError(c *gin.Context, err error, resourceName string) {
+	var dupErr *domain.DuplicatePatientError
+	if errors.As(err, &dupErr) {
+		utils.ErrorResponseWithData(c, http.StatusConflict,
+			"possible duplicate patient, resend with force=true to register anyway", dupErr.Candidates)
+		return
+	}
+
 	switch {
 	case errors.Is(err, domain.ErrNotFound):
 		utils.ErrorResponse(c, http.StatusNotFound, resourceName+" not found")
 	case errors.Is(err, domain.ErrDuplicateEntry):
 		utils.ErrorResponse(c, http.StatusConflict, "duplicate "+resourceName+" entry")
+	case errors.Is(err, domain.ErrPreconditionFailed):
+		utils.ErrorResponse(c, http.StatusPreconditionFailed, resourceName+" has been modified since it was read")
+	case errors.Is(err, domain.ErrHNSequenceExhausted):
+		utils.ErrorResponse(c, http.StatusConflict, "HN sequence exhausted for the current period, widen {SEQ:n} in the HN template")
 	case errors.Is(err, domain.ErrInvalidInput):
 		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 	case errors.Is(err, domain.ErrInvalidDateFormat):
@@ -48,10 +60,17 @@ func (h *PatientHandler) handleServiceError(c *gin.Context, err error, resourceN
 	}
 }
 
+// Search handles GET requests for paginated patient search
 func (h *PatientHandler) Search(c *gin.Context) {
+	var req domain.PatientSearchRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	schemaName := middleware.GetTenantSchema(c)
 
-	patients, err := h.patientService.Search(c.Query("query"), schemaName)
+	result, err := h.patientService.Search(&req, middleware.GetActor(c), schemaName)
 	if err != nil {
 		if errors.Is(err, domain.ErrNotFound) {
 			utils.ErrorResponse(c, http.StatusNotFound, "patient not found")
@@ -61,13 +80,42 @@ func (h *PatientHandler) Search(c *gin.Context) {
 		return
 	}
 
+	meta := &utils.PageMeta{
+		Total:      result.Total,
+		Limit:      result.Limit,
+		NextCursor: result.NextCursor,
+	}
+	if result.NextCursor != "" {
+		meta.Next = nextPageURL(c, result.NextCursor)
+	}
+
 	// Return empty array instead of error when no results found
-	if len(patients) == 0 {
-		utils.SuccessResponse(c, http.StatusOK, "success", []domain.Patient{})
+	if len(result.Patients) == 0 {
+		utils.PaginatedResponse(c, http.StatusOK, "success", []domain.Patient{}, meta)
 		return
 	}
 
-	utils.SuccessResponse(c, http.StatusOK, "success", patients)
+	utils.PaginatedResponse(c, http.StatusOK, "success", result.Patients, meta)
+}
+
+// nextPageURL returns the absolute URL of the current request with its cursor replaced.
+// The scheme honours X-Forwarded-Proto since TLS terminates at NGINX.
+func nextPageURL(c *gin.Context, cursor string) string {
+	query := c.Request.URL.Query()
+	query.Set("cursor", cursor)
+
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+
+	next := url.URL{
+		Scheme:   scheme,
+		Host:     c.Request.Host,
+		Path:     c.Request.URL.Path,
+		RawQuery: query.Encode(),
+	}
+	return next.String()
 }
 
 func (h *PatientHandler) Create(c *gin.Context) {
@@ -79,15 +127,36 @@ func (h *PatientHandler) Create(c *gin.Context) {
 
 	schemaName := middleware.GetTenantSchema(c)
 
-	patient, err := h.patientService.Create(&req, schemaName)
+	patient, err := h.patientService.Create(&req, middleware.GetActor(c), schemaName)
 	if err != nil {
 		h.handleServiceError(c, err, "patient")
 		return
 	}
 
+	setETag(c, patient.Versioned)
 	utils.SuccessResponse(c, http.StatusCreated, "patient created successfully", patient)
 }
 
+// GetByID handles GET requests for a single patient; a retired HN's ID resolves to the survivor
+func (h *PatientHandler) GetByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	patient, err := h.patientService.SearchByID(uint(id), middleware.GetActor(c), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "patient")
+		return
+	}
+
+	setETag(c, patient.Versioned)
+	utils.SuccessResponse(c, http.StatusOK, "success", patient)
+}
+
 // Update handles PUT requests for full patient update
 func (h *PatientHandler) Update(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
@@ -96,6 +165,11 @@ func (h *PatientHandler) Update(c *gin.Context) {
 		return
 	}
 
+	version, ok := requireIfMatch(c)
+	if !ok {
+		return
+	}
+
 	var req domain.PatientUpdateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
@@ -104,12 +178,14 @@ func (h *PatientHandler) Update(c *gin.Context) {
 
 	schemaName := middleware.GetTenantSchema(c)
 
-	patient, err := h.patientService.Update(uint(id), &req, schemaName)
+	patient, err := h.patientService.Update(uint(id), &req, version, middleware.GetActor(c), schemaName)
 	if err != nil {
 		h.handleServiceError(c, err, "patient")
 		return
 	}
 
+	setETag(c, patient.Versioned)
+
 	utils.SuccessResponse(c, http.StatusOK, "patient updated successfully", patient)
 }
 
@@ -121,6 +197,11 @@ func (h *PatientHandler) PartialUpdate(c *gin.Context) {
 		return
 	}
 
+	version, ok := requireIfMatch(c)
+	if !ok {
+		return
+	}
+
 	var req domain.PatientPartialUpdateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
@@ -129,12 +210,14 @@ func (h *PatientHandler) PartialUpdate(c *gin.Context) {
 
 	schemaName := middleware.GetTenantSchema(c)
 
-	patient, err := h.patientService.PartialUpdate(uint(id), &req, schemaName)
+	patient, err := h.patientService.PartialUpdate(uint(id), &req, version, middleware.GetActor(c), schemaName)
 	if err != nil {
 		h.handleServiceError(c, err, "patient")
 		return
 	}
 
+	setETag(c, patient.Versioned)
+
 	utils.SuccessResponse(c, http.StatusOK, "patient updated successfully", patient)
 }
 
@@ -147,10 +230,111 @@ func (h *PatientHandler) Delete(c *gin.Context) {
 
 	schemaName := middleware.GetTenantSchema(c)
 
-	if err := h.patientService.Delete(uint(id), schemaName); err != nil {
+	if err := h.patientService.Delete(uint(id), middleware.GetActor(c), schemaName); err != nil {
 		h.handleServiceError(c, err, "patient")
 		return
 	}
 
 	utils.SuccessResponse(c, http.StatusOK, "patient deleted successfully", nil)
 }
+
+// Merge handles POST requests to retire a duplicate patient into a surviving record
+func (h *PatientHandler) Merge(c *gin.Context) {
+	var req domain.PatientMergeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	merge, err := h.patientService.Merge(&req, middleware.GetActor(c), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "patient")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "patients merged successfully", merge)
+}
+
+// Unmerge handles POST requests to reverse a merge
+func (h *PatientHandler) Unmerge(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	merge, err := h.patientService.Unmerge(uint(id), middleware.GetActor(c), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "merge")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "merge reversed successfully", merge)
+}
+
+// ListMerges handles GET requests for the merge history, optionally of one patient
+func (h *PatientHandler) ListMerges(c *gin.Context) {
+	patientID, err := optionalUintQuery(c, "patient_id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid patient_id")
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	merges, err := h.patientService.ListMerges(patientID, schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "merge")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "success", merges)
+}
+
+// History handles GET requests for the demographic version history of a patient
+func (h *PatientHandler) History(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	versions, err := h.patientService.History(uint(id), middleware.GetActor(c), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "patient")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "success", versions)
+}
+
+// DiffVersions handles GET requests comparing two versions of a patient
+func (h *PatientHandler) DiffVersions(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	var req domain.PatientVersionDiffRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	diff, err := h.patientService.DiffVersions(uint(id), req.From, req.To, middleware.GetActor(c), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "patient version")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "success", diff)
+}
@@ -0,0 +1,224 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/wichai2002/his_v1/internal/delivery/http/middleware"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RoleHandler struct {
+	roleService domain.RoleService
+}
+
+func NewRoleHandler(roleService domain.RoleService) *RoleHandler {
+	return &RoleHandler{
+		roleService: roleService,
+	}
+}
+
+// handleServiceError maps domain errors to appropriate HTTP status codes
+func (h *RoleHandler) handleServiceError(c *gin.Context, err error, resourceName string) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, resourceName+" not found")
+	case errors.Is(err, domain.ErrDuplicateEntry):
+		utils.ErrorResponse(c, http.StatusConflict, "duplicate "+resourceName+" entry")
+	case errors.Is(err, domain.ErrInvalidInput):
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, domain.ErrInvalidSchemaName):
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid tenant schema")
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+// GetAll godoc
+// @Summary List roles
+// @Description List all roles with their permissions
+// @Tags role
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} utils.Response
+// @Router /role [get]
+func (h *RoleHandler) GetAll(c *gin.Context) {
+	schemaName := middleware.GetTenantSchema(c)
+
+	roles, err := h.roleService.GetAll(schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "role")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "success", roles)
+}
+
+// GetAllPermissions godoc
+// @Summary List permissions
+// @Description List every permission that can be granted to a role
+// @Tags role
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} utils.Response
+// @Router /role/permissions [get]
+func (h *RoleHandler) GetAllPermissions(c *gin.Context) {
+	schemaName := middleware.GetTenantSchema(c)
+
+	permissions, err := h.roleService.GetAllPermissions(schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "permission")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "success", permissions)
+}
+
+// GetByID godoc
+// @Summary Get role by ID
+// @Tags role
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Role ID"
+// @Success 200 {object} utils.Response
+// @Router /role/{id} [get]
+func (h *RoleHandler) GetByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	role, err := h.roleService.GetByID(uint(id), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "role")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "success", role)
+}
+
+// Create godoc
+// @Summary Create role
+// @Tags role
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body domain.RoleCreateRequest true "Role data"
+// @Success 201 {object} utils.Response
+// @Router /role/create [post]
+func (h *RoleHandler) Create(c *gin.Context) {
+	var req domain.RoleCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	role, err := h.roleService.Create(&req, schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "role")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "role created successfully", role)
+}
+
+// Update godoc
+// @Summary Update role
+// @Tags role
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Role ID"
+// @Param request body domain.RoleUpdateRequest true "Role data"
+// @Success 200 {object} utils.Response
+// @Router /role/update/{id} [put]
+func (h *RoleHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	var req domain.RoleUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	role, err := h.roleService.Update(uint(id), &req, schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "role")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "role updated successfully", role)
+}
+
+// Delete godoc
+// @Summary Delete role
+// @Description Delete a custom role (system roles cannot be deleted)
+// @Tags role
+// @Security BearerAuth
+// @Param id path int true "Role ID"
+// @Success 200 {object} utils.Response
+// @Router /role/delete/{id} [delete]
+func (h *RoleHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	if err := h.roleService.Delete(uint(id), schemaName); err != nil {
+		h.handleServiceError(c, err, "role")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "role deleted successfully", nil)
+}
+
+// AssignStaffRoles godoc
+// @Summary Assign roles to staff
+// @Description Replace the roles of a staff member (takes effect on next login or token refresh)
+// @Tags role
+// @Security BearerAuth
+// @Accept json
+// @Param id path int true "Staff ID"
+// @Param request body domain.StaffRolesRequest true "Role codes"
+// @Success 200 {object} utils.Response
+// @Router /role/staff/{id} [put]
+func (h *RoleHandler) AssignStaffRoles(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	var req domain.StaffRolesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	if err := h.roleService.AssignStaffRoles(uint(id), &req, schemaName); err != nil {
+		h.handleServiceError(c, err, "staff")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "staff roles updated successfully", nil)
+}
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/wichai2002/his_v1/internal/delivery/http/middleware"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SettingsHandler struct {
+	hnService domain.HNService
+}
+
+func NewSettingsHandler(hnService domain.HNService) *SettingsHandler {
+	return &SettingsHandler{
+		hnService: hnService,
+	}
+}
+
+// handleServiceError maps domain errors to appropriate HTTP status codes
+func (h *SettingsHandler) handleServiceError(c *gin.Context, err error) {
+	var collisionErr *domain.HNCollisionError
+	if errors.As(err, &collisionErr) {
+		utils.ErrorResponseWithData(c, http.StatusConflict,
+			"HN template would reissue existing HNs", collisionErr.Collisions)
+		return
+	}
+
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, "tenant not found")
+	case errors.Is(err, domain.ErrInvalidInput):
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, domain.ErrInvalidSchemaName):
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid tenant schema")
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+// GetHNTemplate godoc
+// @Summary Get the HN template
+// @Description Get the HN template of the tenant, its reset period and the next HN it will issue
+// @Tags settings
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} utils.Response
+// @Router /settings/hn-template [get]
+func (h *SettingsHandler) GetHNTemplate(c *gin.Context) {
+	schemaName := middleware.GetTenantSchema(c)
+
+	settings, err := h.hnService.GetSettings(schemaName)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "success", settings)
+}
+
+// PreviewHNTemplate godoc
+// @Summary Preview an HN template
+// @Description List the next HNs a template would issue and the existing HNs it would collide with, without saving it
+// @Tags settings
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body domain.HNPreviewRequest true "Template to preview, empty for the current one"
+// @Success 200 {object} utils.Response
+// @Router /settings/hn-template/preview [post]
+func (h *SettingsHandler) PreviewHNTemplate(c *gin.Context) {
+	var req domain.HNPreviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	preview, err := h.hnService.Preview(&req, schemaName)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "success", preview)
+}
+
+// UpdateHNTemplate godoc
+// @Summary Change the HN template
+// @Description Change the HN template of the tenant, 409 lists existing HNs the template would issue again
+// @Tags settings
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body domain.HNTemplateRequest true "New template"
+// @Success 200 {object} utils.Response
+// @Router /settings/hn-template [put]
+func (h *SettingsHandler) UpdateHNTemplate(c *gin.Context) {
+	var req domain.HNTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	settings, err := h.hnService.UpdateTemplate(&req, schemaName)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "HN template updated successfully", settings)
+}
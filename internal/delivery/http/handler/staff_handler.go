@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -49,18 +50,85 @@ func (h *StaffHandler) Login(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "login successful", response)
 }
 
+// RefreshToken godoc
+// @Summary Refresh access token
+// @Description Exchange a refresh token for a new access/refresh token pair (the old refresh token is revoked)
+// @Tags staff
+// @Accept json
+// @Produce json
+// @Param request body domain.TokenRefreshRequest true "Refresh token"
+// @Success 200 {object} domain.StaffLoginResponse
+// @Router /staff/token/refresh [post]
+func (h *StaffHandler) RefreshToken(c *gin.Context) {
+	var req domain.TokenRefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	response, err := h.staffService.RefreshToken(&req, schemaName)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidToken) {
+			utils.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "token refreshed", response)
+}
+
 // Logout godoc
 // @Summary Staff logout
-// @Description Logout staff (client should discard the token)
+// @Description Logout staff and revoke all of their outstanding access and refresh tokens
 // @Tags staff
 // @Security BearerAuth
 // @Success 200 {object} utils.Response
 // @Router /staff/logout [post]
 func (h *StaffHandler) Logout(c *gin.Context) {
-	// JWT is stateless, so logout is handled client-side by discarding the token
+	schemaName := middleware.GetTenantSchema(c)
+
+	if err := h.staffService.Logout(middleware.GetUserID(c), schemaName); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
 	utils.SuccessResponse(c, http.StatusOK, "logout successful", nil)
 }
 
+// ChangePassword godoc
+// @Summary Change own password
+// @Description Change the current staff member's password and revoke all of their tokens
+// @Tags staff
+// @Security BearerAuth
+// @Accept json
+// @Param request body domain.StaffChangePasswordRequest true "Current and new password"
+// @Success 200 {object} utils.Response
+// @Router /staff/password [put]
+func (h *StaffHandler) ChangePassword(c *gin.Context) {
+	var req domain.StaffChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	if err := h.staffService.ChangePassword(middleware.GetUserID(c), &req, schemaName); err != nil {
+		if errors.Is(err, domain.ErrInvalidCredentials) {
+			utils.ErrorResponse(c, http.StatusUnauthorized, "current password is incorrect")
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "password changed successfully, please login again", nil)
+}
+
 // GetAll godoc
 // @Summary Get all staff
 // @Description Get all staff members
@@ -105,6 +173,7 @@ func (h *StaffHandler) GetByID(c *gin.Context) {
 		return
 	}
 
+	setETag(c, staff.Versioned)
 	utils.SuccessResponse(c, http.StatusOK, "success", staff)
 }
 
@@ -164,10 +233,16 @@ func (h *StaffHandler) Update(c *gin.Context) {
 
 	staff, err := h.staffService.Update(uint(id), &req, schemaName)
 	if err != nil {
+		if errors.Is(err, domain.ErrPreconditionFailed) {
+			utils.ErrorResponse(c, http.StatusPreconditionFailed, "staff has been modified since it was read")
+			return
+		}
 		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
+	setETag(c, staff.Versioned)
+
 	utils.SuccessResponse(c, http.StatusOK, "staff updated successfully", staff)
 }
 
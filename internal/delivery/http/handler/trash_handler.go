@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/wichai2002/his_v1/internal/delivery/http/middleware"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TrashHandler struct {
+	trashService domain.TrashService
+}
+
+func NewTrashHandler(trashService domain.TrashService) *TrashHandler {
+	return &TrashHandler{
+		trashService: trashService,
+	}
+}
+
+// handleServiceError maps domain errors to appropriate HTTP status codes
+func (h *TrashHandler) handleServiceError(c *gin.Context, err error, resourceName string) {
+	var conflictErr *domain.RestoreConflictError
+	if errors.As(err, &conflictErr) {
+		utils.ErrorResponseWithData(c, http.StatusConflict,
+			"deleted "+resourceName+" conflicts with a live record", conflictErr.Conflicts)
+		return
+	}
+
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, "deleted "+resourceName+" not found")
+	case errors.Is(err, domain.ErrDuplicateEntry):
+		utils.ErrorResponse(c, http.StatusConflict, "deleted "+resourceName+" conflicts with a live record")
+	case errors.Is(err, domain.ErrInvalidSchemaName):
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid tenant schema")
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+// ListPatients godoc
+// @Summary List deleted patients
+// @Description List soft-deleted patients, newest deletion first. Merged patients are excluded.
+// @Tags trash
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} utils.Response
+// @Router /trash/patients [get]
+func (h *TrashHandler) ListPatients(c *gin.Context) {
+	schemaName := middleware.GetTenantSchema(c)
+
+	patients, err := h.trashService.ListPatients(schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "patient")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "success", patients)
+}
+
+// RestorePatient godoc
+// @Summary Restore a deleted patient
+// @Description Restore a soft-deleted patient, 409 lists the identifiers taken by live patients
+// @Tags trash
+// @Security BearerAuth
+// @Param id path int true "Patient ID"
+// @Success 200 {object} utils.Response
+// @Router /trash/patients/restore/{id} [post]
+func (h *TrashHandler) RestorePatient(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	patient, err := h.trashService.RestorePatient(uint(id), middleware.GetActor(c), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "patient")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "patient restored successfully", patient)
+}
+
+// PurgePatients godoc
+// @Summary Purge deleted patients
+// @Description Permanently remove patients deleted longer ago than the retention period
+// @Tags trash
+// @Security BearerAuth
+// @Success 200 {object} utils.Response
+// @Router /trash/patients/purge [delete]
+func (h *TrashHandler) PurgePatients(c *gin.Context) {
+	schemaName := middleware.GetTenantSchema(c)
+
+	result, err := h.trashService.PurgePatients(middleware.GetActor(c), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "patient")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "deleted patients purged", result)
+}
+
+// ListStaff godoc
+// @Summary List deleted staff
+// @Description List soft-deleted staff members, newest deletion first
+// @Tags trash
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} utils.Response
+// @Router /trash/staff [get]
+func (h *TrashHandler) ListStaff(c *gin.Context) {
+	schemaName := middleware.GetTenantSchema(c)
+
+	staffs, err := h.trashService.ListStaff(schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "staff")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "success", staffs)
+}
+
+// RestoreStaff godoc
+// @Summary Restore a deleted staff member
+// @Description Restore a soft-deleted staff member, 409 lists the fields taken by live staff
+// @Tags trash
+// @Security BearerAuth
+// @Param id path int true "Staff ID"
+// @Success 200 {object} utils.Response
+// @Router /trash/staff/restore/{id} [post]
+func (h *TrashHandler) RestoreStaff(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	schemaName := middleware.GetTenantSchema(c)
+
+	staff, err := h.trashService.RestoreStaff(uint(id), schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "staff")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "staff restored successfully", staff)
+}
+
+// PurgeStaff godoc
+// @Summary Purge deleted staff
+// @Description Permanently remove staff deleted longer ago than the retention period
+// @Tags trash
+// @Security BearerAuth
+// @Success 200 {object} utils.Response
+// @Router /trash/staff/purge [delete]
+func (h *TrashHandler) PurgeStaff(c *gin.Context) {
+	schemaName := middleware.GetTenantSchema(c)
+
+	result, err := h.trashService.PurgeStaff(schemaName)
+	if err != nil {
+		h.handleServiceError(c, err, "staff")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "deleted staff purged", result)
+}
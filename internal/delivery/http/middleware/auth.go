@@ -4,14 +4,15 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/wichai2002/his_v1/internal/domain"
 	"github.com/wichai2002/his_v1/pkg/jwt"
 	"github.com/wichai2002/his_v1/pkg/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
-// AuthMiddleware validates the JWT token and sets user context
-func AuthMiddleware(jwtService jwt.JWTService) gin.HandlerFunc {
+// AuthMiddleware validates the JWT token, rejects revoked tokens and sets user context
+func AuthMiddleware(jwtService jwt.JWTService, revocationChecker domain.TokenRevocationChecker) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// It checks if the Authorization header is present
 		authHeader := c.GetHeader("Authorization")
@@ -31,7 +32,7 @@ func AuthMiddleware(jwtService jwt.JWTService) gin.HandlerFunc {
 
 		// validates token
 		claims, err := jwtService.ValidateToken(parts[1])
-		if err != nil {
+		if err != nil || claims.ID == "" {
 			utils.ErrorResponse(c, http.StatusUnauthorized, "invalid or expired token")
 			c.Abort()
 			return
@@ -46,10 +47,26 @@ func AuthMiddleware(jwtService jwt.JWTService) gin.HandlerFunc {
 			}
 		}
 
+		// checks the tenant's revocation list (logout, password change, staff deletion)
+		revoked, err := revocationChecker.IsTokenRevoked(claims.ID, claims.SchemaName)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusInternalServerError, "failed to verify token")
+			c.Abort()
+			return
+		}
+		if revoked {
+			utils.ErrorResponse(c, http.StatusUnauthorized, "token has been revoked")
+			c.Abort()
+			return
+		}
+
 		c.Set("user_id", claims.UserID)
 		c.Set("username", claims.Username)
 		c.Set("is_admin", claims.IsAdmin)
+		c.Set("roles", claims.Roles)
+		c.Set("permissions", claims.Permissions)
 		c.Set("tenant_id", claims.TenantID)
+		c.Set("token_id", claims.ID)
 		c.Set("jwt_schema", claims.SchemaName) // Schema from JWT for tenant context
 
 		// If no tenant context from middleware, use JWT schema
@@ -61,13 +78,11 @@ func AuthMiddleware(jwtService jwt.JWTService) gin.HandlerFunc {
 	}
 }
 
-// AdminMiddleware checks if the user is an admin
-func AdminMiddleware() gin.HandlerFunc {
+// RequirePermission checks that the authenticated user's token grants the permission
+func RequirePermission(permission string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// checks if the user is an admin
-		isAdmin, exists := c.Get("is_admin")
-		if !exists || !isAdmin.(bool) {
-			utils.ErrorResponse(c, http.StatusForbidden, "admin access required")
+		if !HasPermission(c, permission) {
+			utils.ErrorResponse(c, http.StatusForbidden, "permission required: "+permission)
 			c.Abort()
 			return
 		}
@@ -76,6 +91,21 @@ func AdminMiddleware() gin.HandlerFunc {
 	}
 }
 
+// RequireAnyPermission checks that the authenticated user's token grants at least one of the permissions
+func RequireAnyPermission(permissions ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, permission := range permissions {
+			if HasPermission(c, permission) {
+				c.Next()
+				return
+			}
+		}
+
+		utils.ErrorResponse(c, http.StatusForbidden, "permission required: one of "+strings.Join(permissions, ", "))
+		c.Abort()
+	}
+}
+
 // GetUserID retrieves the current user ID from context
 func GetUserID(c *gin.Context) uint {
 	if id, exists := c.Get("user_id"); exists {
@@ -86,6 +116,16 @@ func GetUserID(c *gin.Context) uint {
 	return 0
 }
 
+// GetActor builds the audit actor for the current request from the auth and request ID context
+func GetActor(c *gin.Context) *domain.Actor {
+	return &domain.Actor{
+		StaffID:   GetUserID(c),
+		Username:  c.GetString("username"),
+		ClientIP:  c.ClientIP(),
+		RequestID: GetRequestID(c),
+	}
+}
+
 // IsAdmin checks if the current user is an admin
 func IsAdmin(c *gin.Context) bool {
 	if isAdmin, exists := c.Get("is_admin"); exists {
@@ -95,3 +135,17 @@ func IsAdmin(c *gin.Context) bool {
 	}
 	return false
 }
+
+// HasPermission checks if the current user holds the given permission
+func HasPermission(c *gin.Context, permission string) bool {
+	if value, exists := c.Get("permissions"); exists {
+		if permissions, ok := value.([]string); ok {
+			for _, p := range permissions {
+				if p == permission {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
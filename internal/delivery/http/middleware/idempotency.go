@@ -0,0 +1,160 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// IdempotencyKeyHeader carries the client's key for a create request
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on responses replayed from an earlier request
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+// replayedHeaders are the response headers stored with a key and sent again on replay
+var replayedHeaders = []string{"Content-Type", "ETag", "Location"}
+
+// responseRecorder copies the response body while it is written to the client
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// IdempotencyMiddleware makes create requests safe to retry. A request with an Idempotency-Key
+// header is processed once; retrying it returns the stored response, and sending the key with
+// a different request is rejected with 422. Only successful responses are stored, so a request
+// that failed can be corrected and retried with the same key. Requests without the header are
+// processed as usual. Must run after AuthMiddleware so keys are bound to the staff member.
+func IdempotencyMiddleware(idempotencyService domain.IdempotencyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "failed to read request body")
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		schemaName := GetTenantSchema(c)
+		record := &domain.IdempotencyKey{
+			Key:         key,
+			StaffID:     GetUserID(c),
+			RequestHash: hashRequest(c.Request.Method, c.Request.URL.Path, GetUserID(c), body),
+		}
+
+		stored, err := idempotencyService.Begin(record, schemaName)
+		if err != nil {
+			switch {
+			case errors.Is(err, domain.ErrIdempotencyKeyReused):
+				utils.ErrorResponse(c, http.StatusUnprocessableEntity, err.Error())
+			case errors.Is(err, domain.ErrIdempotencyKeyInUse):
+				utils.ErrorResponse(c, http.StatusConflict, err.Error())
+			case errors.Is(err, domain.ErrInvalidInput):
+				utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			default:
+				utils.ErrorResponse(c, http.StatusInternalServerError, "internal server error")
+			}
+			c.Abort()
+			return
+		}
+
+		if stored != nil {
+			replayResponse(c, stored)
+			c.Abort()
+			return
+		}
+
+		// A handler that panics never reaches the status check below; release the key before
+		// passing the panic on, or retries would be refused as in progress until it expires
+		defer func() {
+			if r := recover(); r != nil {
+				if err := idempotencyService.Release(record, schemaName); err != nil {
+					log.Printf("failed to release idempotency key: %v", err)
+				}
+				panic(r)
+			}
+		}()
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		status := recorder.Status()
+		if status < http.StatusOK || status >= http.StatusMultipleChoices {
+			if err := idempotencyService.Release(record, schemaName); err != nil {
+				log.Printf("failed to release idempotency key: %v", err)
+			}
+			return
+		}
+
+		record.StatusCode = status
+		record.ResponseBody = recorder.body.String()
+		record.ResponseHeaders = encodeReplayedHeaders(recorder.Header())
+		// The record was created; if its response cannot be stored the key stays reserved
+		// until it expires, which blocks retries rather than creating the record twice
+		if err := idempotencyService.Complete(record, schemaName); err != nil {
+			log.Printf("failed to store idempotent response: %v", err)
+		}
+	}
+}
+
+// hashRequest identifies a request by its method, path, staff member and body
+func hashRequest(method string, path string, staffID uint, body []byte) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s %s %d\n", method, path, staffID)
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func encodeReplayedHeaders(header http.Header) string {
+	values := make(map[string]string)
+	for _, name := range replayedHeaders {
+		if value := header.Get(name); value != "" {
+			values[name] = value
+		}
+	}
+	// Marshalling a map of strings cannot fail
+	encoded, _ := json.Marshal(values)
+	return string(encoded)
+}
+
+func replayResponse(c *gin.Context, stored *domain.IdempotencyKey) {
+	var headers map[string]string
+	if err := json.Unmarshal([]byte(stored.ResponseHeaders), &headers); err == nil {
+		for name, value := range headers {
+			c.Header(name, value)
+		}
+	}
+	c.Header(IdempotentReplayedHeader, "true")
+	c.Status(stored.StatusCode)
+	_, _ = c.Writer.WriteString(stored.ResponseBody)
+}
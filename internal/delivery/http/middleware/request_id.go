@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// RequestIDHeader is the header used to pass the request ID in and out
+	RequestIDHeader = "X-Request-ID"
+	// RequestIDKey is the key for the request ID in gin.Context
+	RequestIDKey = "request_id"
+)
+
+// requestIDRegex limits client supplied request IDs to safe, bounded values
+var requestIDRegex = regexp.MustCompile(`^[a-zA-Z0-9._-]{1,64}$`)
+
+// RequestIDMiddleware reuses the caller's X-Request-ID or generates one,
+// stores it in context for the audit trail and echoes it in the response
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !requestIDRegex.MatchString(requestID) {
+			requestID = newRequestID()
+		}
+
+		c.Set(RequestIDKey, requestID)
+		c.Header(RequestIDHeader, requestID)
+		c.Next()
+	}
+}
+
+// GetRequestID retrieves the request ID from context
+func GetRequestID(c *gin.Context) string {
+	if id, exists := c.Get(RequestIDKey); exists {
+		if requestID, ok := id.(string); ok {
+			return requestID
+		}
+	}
+	return ""
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
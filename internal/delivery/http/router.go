@@ -11,32 +11,101 @@ import (
 )
 
 type Router struct {
-	staffHandler   *handler.StaffHandler
-	patientHandler *handler.PatientHandler
-	jwtService     jwt.JWTService
-	tenantService  domain.TenantService
-	dbManager      *database.TenantDBManager
+	staffHandler        *handler.StaffHandler
+	patientHandler      *handler.PatientHandler
+	roleHandler         *handler.RoleHandler
+	auditHandler        *handler.AuditHandler
+	trashHandler        *handler.TrashHandler
+	settingsHandler     *handler.SettingsHandler
+	contactHandler      *handler.PatientContactHandler
+	addressHandler      *handler.AddressHandler
+	patientAddrHandler  *handler.PatientAddressHandler
+	allergyHandler      *handler.PatientAllergyHandler
+	coverageHandler     *handler.PatientCoverageHandler
+	departmentHandler   *handler.DepartmentHandler
+	encounterHandler    *handler.EncounterHandler
+	availabilityHandler *handler.AvailabilityHandler
+	appointmentHandler  *handler.AppointmentHandler
+	vitalHandler        *handler.VitalSignHandler
+	icd10Handler        *handler.ICD10Handler
+	diagnosisHandler    *handler.DiagnosisHandler
+	drugHandler         *handler.DrugHandler
+	medicationHandler   *handler.MedicationOrderHandler
+	inventoryHandler    *handler.InventoryHandler
+	labHandler          *handler.LabHandler
+	jwtService          jwt.JWTService
+	revocationChecker   domain.TokenRevocationChecker
+	idempotencyService  domain.IdempotencyService
+	tenantService       domain.TenantService
+	dbManager           *database.TenantDBManager
 }
 
 func NewRouter(
 	staffHandler *handler.StaffHandler,
 	patientHandler *handler.PatientHandler,
+	roleHandler *handler.RoleHandler,
+	auditHandler *handler.AuditHandler,
+	trashHandler *handler.TrashHandler,
+	settingsHandler *handler.SettingsHandler,
+	contactHandler *handler.PatientContactHandler,
+	addressHandler *handler.AddressHandler,
+	patientAddrHandler *handler.PatientAddressHandler,
+	allergyHandler *handler.PatientAllergyHandler,
+	coverageHandler *handler.PatientCoverageHandler,
+	departmentHandler *handler.DepartmentHandler,
+	encounterHandler *handler.EncounterHandler,
+	availabilityHandler *handler.AvailabilityHandler,
+	appointmentHandler *handler.AppointmentHandler,
+	vitalHandler *handler.VitalSignHandler,
+	icd10Handler *handler.ICD10Handler,
+	diagnosisHandler *handler.DiagnosisHandler,
+	drugHandler *handler.DrugHandler,
+	medicationHandler *handler.MedicationOrderHandler,
+	inventoryHandler *handler.InventoryHandler,
+	labHandler *handler.LabHandler,
 	jwtService jwt.JWTService,
+	revocationChecker domain.TokenRevocationChecker,
+	idempotencyService domain.IdempotencyService,
 	tenantService domain.TenantService,
 	dbManager *database.TenantDBManager,
 ) *Router {
 	return &Router{
-		staffHandler:   staffHandler,
-		patientHandler: patientHandler,
-		jwtService:     jwtService,
-		tenantService:  tenantService,
-		dbManager:      dbManager,
+		staffHandler:        staffHandler,
+		patientHandler:      patientHandler,
+		roleHandler:         roleHandler,
+		auditHandler:        auditHandler,
+		trashHandler:        trashHandler,
+		settingsHandler:     settingsHandler,
+		contactHandler:      contactHandler,
+		addressHandler:      addressHandler,
+		patientAddrHandler:  patientAddrHandler,
+		allergyHandler:      allergyHandler,
+		coverageHandler:     coverageHandler,
+		departmentHandler:   departmentHandler,
+		encounterHandler:    encounterHandler,
+		availabilityHandler: availabilityHandler,
+		appointmentHandler:  appointmentHandler,
+		vitalHandler:        vitalHandler,
+		icd10Handler:        icd10Handler,
+		diagnosisHandler:    diagnosisHandler,
+		drugHandler:         drugHandler,
+		medicationHandler:   medicationHandler,
+		inventoryHandler:    inventoryHandler,
+		labHandler:          labHandler,
+		jwtService:          jwtService,
+		revocationChecker:   revocationChecker,
+		idempotencyService:  idempotencyService,
+		tenantService:       tenantService,
+		dbManager:           dbManager,
 	}
 }
 
 func (r *Router) Setup() *gin.Engine {
 	router := gin.Default()
 
+	// Tag every request with an ID so audit events can be traced back to it
+	router.Use(middleware.RequestIDMiddleware())
+
 	// Health check - no tenant required
 	router.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{"status": "ok"})
@@ -50,10 +119,59 @@ func (r *Router) Setup() *gin.Engine {
 	routerV1.Use(middleware.TenantMiddleware(r.tenantService, r.dbManager))
 
 	// Staff routes - some public (login), some require auth
-	routes.RegisterStaffRoutes(routerV1, r.staffHandler, r.jwtService)
+	routes.RegisterStaffRoutes(routerV1, r.staffHandler, r.jwtService, r.revocationChecker, r.idempotencyService)
 
 	// Protected routes (require tenant context and auth)
-	routes.RegisterPatientRoutes(routerV1, r.patientHandler, r.jwtService)
+	routes.RegisterPatientRoutes(routerV1, r.patientHandler, r.jwtService, r.revocationChecker, r.idempotencyService)
+
+	// Next of kin and emergency contacts of patients
+	routes.RegisterPatientContactRoutes(routerV1, r.contactHandler, r.jwtService, r.revocationChecker)
+
+	// Structured Thai addresses of patients and the reference dataset behind them
+	routes.RegisterAddressRoutes(routerV1, r.addressHandler, r.jwtService, r.revocationChecker)
+	routes.RegisterPatientAddressRoutes(routerV1, r.patientAddrHandler, r.jwtService, r.revocationChecker)
+
+	// Allergy and adverse drug reaction registry
+	routes.RegisterPatientAllergyRoutes(routerV1, r.allergyHandler, r.jwtService, r.revocationChecker)
+
+	// Payer coverage of patients and eligibility checks
+	routes.RegisterPatientCoverageRoutes(routerV1, r.coverageHandler, r.jwtService, r.revocationChecker)
+
+	// Departments, patient visits and the department queues
+	routes.RegisterDepartmentRoutes(routerV1, r.departmentHandler, r.jwtService, r.revocationChecker)
+	routes.RegisterEncounterRoutes(routerV1, r.encounterHandler, r.jwtService, r.revocationChecker, r.idempotencyService)
+
+	// Doctor availability and appointment booking
+	routes.RegisterAppointmentRoutes(routerV1, r.availabilityHandler, r.appointmentHandler, r.jwtService, r.revocationChecker, r.idempotencyService)
+
+	// Vital signs and triage levels taken during visits
+	routes.RegisterVitalSignRoutes(routerV1, r.vitalHandler, r.jwtService, r.revocationChecker)
+
+	// ICD-10 catalogue and the diagnoses of visits
+	routes.RegisterICD10Routes(routerV1, r.icd10Handler, r.jwtService, r.revocationChecker)
+	routes.RegisterDiagnosisRoutes(routerV1, r.diagnosisHandler, r.jwtService, r.revocationChecker)
+
+	// Drug master and the medication orders of visits
+	routes.RegisterDrugRoutes(routerV1, r.drugHandler, r.jwtService, r.revocationChecker)
+	routes.RegisterMedicationOrderRoutes(routerV1, r.medicationHandler, r.jwtService, r.revocationChecker, r.idempotencyService)
+
+	// Pharmacy stock locations, goods-received notes and the stock ledger
+	routes.RegisterInventoryRoutes(routerV1, r.inventoryHandler, r.jwtService, r.revocationChecker, r.idempotencyService)
+
+	// Lab test catalogue, lab orders, specimens and results
+	routes.RegisterLabRoutes(routerV1, r.labHandler, r.jwtService, r.revocationChecker, r.idempotencyService)
+
+	// Role and permission management
+	routes.RegisterRoleRoutes(routerV1, r.roleHandler, r.jwtService, r.revocationChecker)
+
+	// Patient record audit trail
+	routes.RegisterAuditRoutes(routerV1, r.auditHandler, r.jwtService, r.revocationChecker)
+
+	// Deleted patients and staff
+	routes.RegisterTrashRoutes(routerV1, r.trashHandler, r.jwtService, r.revocationChecker)
+
+	// Tenant settings such as the HN template
+	routes.RegisterSettingsRoutes(routerV1, r.settingsHandler, r.jwtService, r.revocationChecker)
 
 	return router
 }
@@ -0,0 +1,37 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/wichai2002/his_v1/internal/delivery/http/handler"
+	"github.com/wichai2002/his_v1/internal/delivery/http/middleware"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/pkg/jwt"
+)
+
+// RegisterAddressRoutes registers the lookup routes of the Thai address reference dataset
+// Any authenticated staff member can use them; the dataset is shared by all tenants
+func RegisterAddressRoutes(router *gin.RouterGroup, addressHandler *handler.AddressHandler, jwtService jwt.JWTService, revocationChecker domain.TokenRevocationChecker) {
+	addressGroup := router.Group("/address")
+	addressGroup.Use(middleware.AuthMiddleware(jwtService, revocationChecker))
+	addressGroup.Use(middleware.TenantRequiredMiddleware())
+	{
+		addressGroup.GET("/provinces", addressHandler.ListProvinces)
+		addressGroup.GET("/provinces/:code/districts", addressHandler.ListDistricts)
+		addressGroup.GET("/districts/:code/subdistricts", addressHandler.ListSubdistricts)
+		addressGroup.GET("/postal-codes/:postal_code", addressHandler.FindByPostalCode)
+		addressGroup.GET("/autocomplete", addressHandler.Autocomplete)
+	}
+}
+
+// RegisterPatientAddressRoutes registers the address routes under /patient/:id/addresses
+// Reading addresses requires patient:read, changing them patient:write
+func RegisterPatientAddressRoutes(router *gin.RouterGroup, addressHandler *handler.PatientAddressHandler, jwtService jwt.JWTService, revocationChecker domain.TokenRevocationChecker) {
+	addressGroup := router.Group("/patient/:id/addresses")
+	addressGroup.Use(middleware.AuthMiddleware(jwtService, revocationChecker))
+	addressGroup.Use(middleware.TenantRequiredMiddleware())
+	{
+		addressGroup.GET("", middleware.RequirePermission(domain.PermPatientRead), addressHandler.List)
+		addressGroup.PUT("/:type", middleware.RequirePermission(domain.PermPatientWrite), addressHandler.Save)
+		addressGroup.DELETE("/:type", middleware.RequirePermission(domain.PermPatientWrite), addressHandler.Delete)
+	}
+}
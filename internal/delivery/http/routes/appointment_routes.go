@@ -0,0 +1,43 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/wichai2002/his_v1/internal/delivery/http/handler"
+	"github.com/wichai2002/his_v1/internal/delivery/http/middleware"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/pkg/jwt"
+)
+
+// RegisterAppointmentRoutes registers the doctor availability routes under /availability and the
+// booking routes under /appointments and /patient/:id/appointments.
+// Any signed-in staff member can list availability, publishing it requires schedule:manage.
+// Reading appointments and calendars requires patient:read, booking them appointment:write.
+func RegisterAppointmentRoutes(router *gin.RouterGroup, availabilityHandler *handler.AvailabilityHandler, appointmentHandler *handler.AppointmentHandler, jwtService jwt.JWTService, revocationChecker domain.TokenRevocationChecker, idempotencyService domain.IdempotencyService) {
+	availabilityGroup := router.Group("/availability")
+	availabilityGroup.Use(middleware.AuthMiddleware(jwtService, revocationChecker))
+	availabilityGroup.Use(middleware.TenantRequiredMiddleware())
+	{
+		availabilityGroup.GET("", availabilityHandler.List)
+		availabilityGroup.POST("", middleware.RequirePermission(domain.PermScheduleManage), availabilityHandler.Create)
+		availabilityGroup.POST("/:id/expand", middleware.RequirePermission(domain.PermScheduleManage), availabilityHandler.Expand)
+		availabilityGroup.DELETE("/:id", middleware.RequirePermission(domain.PermScheduleManage), availabilityHandler.Delete)
+	}
+
+	appointmentGroup := router.Group("/appointments")
+	appointmentGroup.Use(middleware.AuthMiddleware(jwtService, revocationChecker))
+	appointmentGroup.Use(middleware.TenantRequiredMiddleware())
+	{
+		appointmentGroup.POST("", middleware.RequirePermission(domain.PermAppointmentWrite), middleware.IdempotencyMiddleware(idempotencyService), appointmentHandler.Book)
+		appointmentGroup.GET("/calendar", middleware.RequirePermission(domain.PermPatientRead), appointmentHandler.Calendar)
+		appointmentGroup.GET("/:id", middleware.RequirePermission(domain.PermPatientRead), appointmentHandler.GetByID)
+		appointmentGroup.POST("/:id/reschedule", middleware.RequirePermission(domain.PermAppointmentWrite), appointmentHandler.Reschedule)
+		appointmentGroup.POST("/:id/cancel", middleware.RequirePermission(domain.PermAppointmentWrite), appointmentHandler.Cancel)
+	}
+
+	patientAppointmentGroup := router.Group("/patient/:id/appointments")
+	patientAppointmentGroup.Use(middleware.AuthMiddleware(jwtService, revocationChecker))
+	patientAppointmentGroup.Use(middleware.TenantRequiredMiddleware())
+	{
+		patientAppointmentGroup.GET("", middleware.RequirePermission(domain.PermPatientRead), appointmentHandler.ListByPatient)
+	}
+}
@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/wichai2002/his_v1/internal/delivery/http/handler"
+	"github.com/wichai2002/his_v1/internal/delivery/http/middleware"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/pkg/jwt"
+)
+
+// RegisterAuditRoutes registers audit trail routes
+// All audit routes require the audit:read permission
+func RegisterAuditRoutes(router *gin.RouterGroup, auditHandler *handler.AuditHandler, jwtService jwt.JWTService, revocationChecker domain.TokenRevocationChecker) {
+	auditGroup := router.Group("/audit")
+	auditGroup.Use(middleware.AuthMiddleware(jwtService, revocationChecker))
+	auditGroup.Use(middleware.TenantRequiredMiddleware())
+	auditGroup.Use(middleware.RequirePermission(domain.PermAuditRead))
+	{
+		auditGroup.GET("/events", auditHandler.Query)
+		auditGroup.GET("/verify", auditHandler.Verify)
+	}
+}
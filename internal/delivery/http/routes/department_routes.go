@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/wichai2002/his_v1/internal/delivery/http/handler"
+	"github.com/wichai2002/his_v1/internal/delivery/http/middleware"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/pkg/jwt"
+)
+
+// RegisterDepartmentRoutes registers the department routes under /departments
+// Any signed-in staff member can list departments, changing them requires settings:manage
+func RegisterDepartmentRoutes(router *gin.RouterGroup, departmentHandler *handler.DepartmentHandler, jwtService jwt.JWTService, revocationChecker domain.TokenRevocationChecker) {
+	departmentGroup := router.Group("/departments")
+	departmentGroup.Use(middleware.AuthMiddleware(jwtService, revocationChecker))
+	departmentGroup.Use(middleware.TenantRequiredMiddleware())
+	{
+		departmentGroup.GET("", departmentHandler.List)
+		departmentGroup.POST("", middleware.RequirePermission(domain.PermSettingsManage), departmentHandler.Create)
+		departmentGroup.PUT("/:id", middleware.RequirePermission(domain.PermSettingsManage), departmentHandler.Update)
+	}
+}
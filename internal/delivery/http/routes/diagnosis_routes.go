@@ -0,0 +1,41 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/wichai2002/his_v1/internal/delivery/http/handler"
+	"github.com/wichai2002/his_v1/internal/delivery/http/middleware"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/pkg/jwt"
+)
+
+// RegisterICD10Routes registers the lookup routes of the ICD-10 catalogue
+// Any authenticated staff member can use them; the catalogue is shared by all tenants
+func RegisterICD10Routes(router *gin.RouterGroup, icd10Handler *handler.ICD10Handler, jwtService jwt.JWTService, revocationChecker domain.TokenRevocationChecker) {
+	icd10Group := router.Group("/icd10")
+	icd10Group.Use(middleware.AuthMiddleware(jwtService, revocationChecker))
+	icd10Group.Use(middleware.TenantRequiredMiddleware())
+	{
+		icd10Group.GET("", icd10Handler.Search)
+		icd10Group.GET("/:code", icd10Handler.GetByCode)
+	}
+}
+
+// RegisterDiagnosisRoutes registers the routes under /encounters/:id/diagnoses and /patient/:id/diagnoses
+// Reading diagnoses requires patient:read, recording and removing them diagnosis:write
+func RegisterDiagnosisRoutes(router *gin.RouterGroup, diagnosisHandler *handler.DiagnosisHandler, jwtService jwt.JWTService, revocationChecker domain.TokenRevocationChecker) {
+	encounterDiagnosisGroup := router.Group("/encounters/:id/diagnoses")
+	encounterDiagnosisGroup.Use(middleware.AuthMiddleware(jwtService, revocationChecker))
+	encounterDiagnosisGroup.Use(middleware.TenantRequiredMiddleware())
+	{
+		encounterDiagnosisGroup.POST("", middleware.RequirePermission(domain.PermDiagnosisWrite), diagnosisHandler.Create)
+		encounterDiagnosisGroup.GET("", middleware.RequirePermission(domain.PermPatientRead), diagnosisHandler.ListByEncounter)
+		encounterDiagnosisGroup.DELETE("/:diagnosis_id", middleware.RequirePermission(domain.PermDiagnosisWrite), diagnosisHandler.Delete)
+	}
+
+	patientDiagnosisGroup := router.Group("/patient/:id/diagnoses")
+	patientDiagnosisGroup.Use(middleware.AuthMiddleware(jwtService, revocationChecker))
+	patientDiagnosisGroup.Use(middleware.TenantRequiredMiddleware())
+	{
+		patientDiagnosisGroup.GET("", middleware.RequirePermission(domain.PermPatientRead), diagnosisHandler.ListByPatient)
+	}
+}
@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/wichai2002/his_v1/internal/delivery/http/handler"
+	"github.com/wichai2002/his_v1/internal/delivery/http/middleware"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/pkg/jwt"
+)
+
+// RegisterEncounterRoutes registers the visit routes under /encounters and /patient/:id/encounters
+// Reading visits and queues requires patient:read, registering and advancing them encounter:write
+func RegisterEncounterRoutes(router *gin.RouterGroup, encounterHandler *handler.EncounterHandler, jwtService jwt.JWTService, revocationChecker domain.TokenRevocationChecker, idempotencyService domain.IdempotencyService) {
+	encounterGroup := router.Group("/encounters")
+	encounterGroup.Use(middleware.AuthMiddleware(jwtService, revocationChecker))
+	encounterGroup.Use(middleware.TenantRequiredMiddleware())
+	{
+		encounterGroup.POST("", middleware.RequirePermission(domain.PermEncounterWrite), middleware.IdempotencyMiddleware(idempotencyService), encounterHandler.Create)
+		encounterGroup.GET("/queue", middleware.RequirePermission(domain.PermPatientRead), encounterHandler.Queue)
+		encounterGroup.GET("/:id", middleware.RequirePermission(domain.PermPatientRead), encounterHandler.GetByID)
+		encounterGroup.POST("/:id/status", middleware.RequirePermission(domain.PermEncounterWrite), encounterHandler.UpdateStatus)
+	}
+
+	patientEncounterGroup := router.Group("/patient/:id/encounters")
+	patientEncounterGroup.Use(middleware.AuthMiddleware(jwtService, revocationChecker))
+	patientEncounterGroup.Use(middleware.TenantRequiredMiddleware())
+	{
+		patientEncounterGroup.GET("", middleware.RequirePermission(domain.PermPatientRead), encounterHandler.ListByPatient)
+	}
+}
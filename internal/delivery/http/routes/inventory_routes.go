@@ -0,0 +1,35 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/wichai2002/his_v1/internal/delivery/http/handler"
+	"github.com/wichai2002/his_v1/internal/delivery/http/middleware"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/pkg/jwt"
+)
+
+// RegisterInventoryRoutes registers the pharmacy stock routes under /pharmacy
+// Reading stock requires inventory:read, changing it inventory:manage. Dispensing takes stock
+// through POST /medication-orders/:id/dispense.
+func RegisterInventoryRoutes(router *gin.RouterGroup, inventoryHandler *handler.InventoryHandler, jwtService jwt.JWTService, revocationChecker domain.TokenRevocationChecker, idempotencyService domain.IdempotencyService) {
+	pharmacyGroup := router.Group("/pharmacy")
+	pharmacyGroup.Use(middleware.AuthMiddleware(jwtService, revocationChecker))
+	pharmacyGroup.Use(middleware.TenantRequiredMiddleware())
+	{
+		pharmacyGroup.GET("/locations", middleware.RequirePermission(domain.PermInventoryRead), inventoryHandler.ListLocations)
+		pharmacyGroup.POST("/locations", middleware.RequirePermission(domain.PermInventoryManage), inventoryHandler.CreateLocation)
+		pharmacyGroup.PUT("/locations/:id", middleware.RequirePermission(domain.PermInventoryManage), inventoryHandler.UpdateLocation)
+		pharmacyGroup.GET("/locations/:id/reorder-levels", middleware.RequirePermission(domain.PermInventoryRead), inventoryHandler.ListReorderLevels)
+		pharmacyGroup.PUT("/locations/:id/reorder-levels", middleware.RequirePermission(domain.PermInventoryManage), inventoryHandler.SetReorderLevel)
+
+		pharmacyGroup.GET("/stock", middleware.RequirePermission(domain.PermInventoryRead), inventoryHandler.ListStock)
+		pharmacyGroup.POST("/stock/rebuild", middleware.RequirePermission(domain.PermInventoryManage), inventoryHandler.RebuildBalances)
+		pharmacyGroup.GET("/stock-card", middleware.RequirePermission(domain.PermInventoryRead), inventoryHandler.StockCard)
+		pharmacyGroup.GET("/alerts", middleware.RequirePermission(domain.PermInventoryRead), inventoryHandler.Alerts)
+
+		pharmacyGroup.POST("/receipts", middleware.RequirePermission(domain.PermInventoryManage), middleware.IdempotencyMiddleware(idempotencyService), inventoryHandler.Receive)
+		pharmacyGroup.GET("/receipts", middleware.RequirePermission(domain.PermInventoryRead), inventoryHandler.ListReceipts)
+		pharmacyGroup.GET("/receipts/:id", middleware.RequirePermission(domain.PermInventoryRead), inventoryHandler.GetReceipt)
+		pharmacyGroup.POST("/adjustments", middleware.RequirePermission(domain.PermInventoryManage), inventoryHandler.Adjust)
+	}
+}
@@ -0,0 +1,58 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/wichai2002/his_v1/internal/delivery/http/handler"
+	"github.com/wichai2002/his_v1/internal/delivery/http/middleware"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/pkg/jwt"
+)
+
+// RegisterLabRoutes registers the lab routes under /lab, /encounters/:id/lab-orders,
+// /patient/:id/lab-orders and /lab-orders
+// Any signed-in staff member can look tests up and lab:manage changes the catalogue. Reading
+// orders requires patient:read, ordering and cancelling lab:order, collecting specimens and
+// printing their labels lab:collect, and receiving, rejecting and resulting them lab:result.
+func RegisterLabRoutes(router *gin.RouterGroup, labHandler *handler.LabHandler, jwtService jwt.JWTService, revocationChecker domain.TokenRevocationChecker, idempotencyService domain.IdempotencyService) {
+	labGroup := router.Group("/lab")
+	labGroup.Use(middleware.AuthMiddleware(jwtService, revocationChecker))
+	labGroup.Use(middleware.TenantRequiredMiddleware())
+	{
+		labGroup.GET("/tests", labHandler.ListTests)
+		labGroup.POST("/tests", middleware.RequirePermission(domain.PermLabManage), labHandler.CreateTest)
+		labGroup.GET("/tests/:id", labHandler.GetTest)
+		labGroup.PUT("/tests/:id", middleware.RequirePermission(domain.PermLabManage), labHandler.UpdateTest)
+
+		labGroup.GET("/specimens", middleware.RequireAnyPermission(domain.PermLabCollect, domain.PermLabResult), labHandler.ListSpecimens)
+		labGroup.GET("/specimens/:barcode/label", middleware.RequirePermission(domain.PermLabCollect), labHandler.GetLabel)
+		labGroup.POST("/specimens/:barcode/collect", middleware.RequirePermission(domain.PermLabCollect), labHandler.Collect)
+		labGroup.POST("/specimens/:barcode/receive", middleware.RequirePermission(domain.PermLabResult), labHandler.Receive)
+		labGroup.POST("/specimens/:barcode/reject", middleware.RequirePermission(domain.PermLabResult), labHandler.Reject)
+
+		labGroup.POST("/analyzer/import", middleware.RequirePermission(domain.PermLabResult), labHandler.ImportResults)
+	}
+
+	encounterLabGroup := router.Group("/encounters/:id/lab-orders")
+	encounterLabGroup.Use(middleware.AuthMiddleware(jwtService, revocationChecker))
+	encounterLabGroup.Use(middleware.TenantRequiredMiddleware())
+	{
+		encounterLabGroup.POST("", middleware.RequirePermission(domain.PermLabOrder), middleware.IdempotencyMiddleware(idempotencyService), labHandler.Create)
+		encounterLabGroup.GET("", middleware.RequirePermission(domain.PermPatientRead), labHandler.ListByEncounter)
+	}
+
+	patientLabGroup := router.Group("/patient/:id/lab-orders")
+	patientLabGroup.Use(middleware.AuthMiddleware(jwtService, revocationChecker))
+	patientLabGroup.Use(middleware.TenantRequiredMiddleware())
+	{
+		patientLabGroup.GET("", middleware.RequirePermission(domain.PermPatientRead), labHandler.ListByPatient)
+	}
+
+	orderGroup := router.Group("/lab-orders")
+	orderGroup.Use(middleware.AuthMiddleware(jwtService, revocationChecker))
+	orderGroup.Use(middleware.TenantRequiredMiddleware())
+	{
+		orderGroup.GET("/:id", middleware.RequirePermission(domain.PermPatientRead), labHandler.GetByID)
+		orderGroup.POST("/:id/cancel", middleware.RequirePermission(domain.PermLabOrder), labHandler.Cancel)
+		orderGroup.POST("/:id/results", middleware.RequirePermission(domain.PermLabResult), labHandler.RecordResults)
+	}
+}
@@ -0,0 +1,57 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/wichai2002/his_v1/internal/delivery/http/handler"
+	"github.com/wichai2002/his_v1/internal/delivery/http/middleware"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/pkg/jwt"
+)
+
+// RegisterDrugRoutes registers the drug master routes under /drugs
+// Any signed-in staff member can look drugs up, changing the master requires drug:manage
+func RegisterDrugRoutes(router *gin.RouterGroup, drugHandler *handler.DrugHandler, jwtService jwt.JWTService, revocationChecker domain.TokenRevocationChecker) {
+	drugGroup := router.Group("/drugs")
+	drugGroup.Use(middleware.AuthMiddleware(jwtService, revocationChecker))
+	drugGroup.Use(middleware.TenantRequiredMiddleware())
+	{
+		drugGroup.GET("", drugHandler.List)
+		drugGroup.POST("", middleware.RequirePermission(domain.PermDrugManage), drugHandler.Create)
+		drugGroup.GET("/:id", drugHandler.GetByID)
+		drugGroup.PUT("/:id", middleware.RequirePermission(domain.PermDrugManage), drugHandler.Update)
+		drugGroup.GET("/:id/interactions", drugHandler.ListInteractions)
+		drugGroup.POST("/:id/interactions", middleware.RequirePermission(domain.PermDrugManage), drugHandler.CreateInteraction)
+		drugGroup.DELETE("/:id/interactions/:interaction_id", middleware.RequirePermission(domain.PermDrugManage), drugHandler.DeleteInteraction)
+	}
+}
+
+// RegisterMedicationOrderRoutes registers the prescription routes under /encounters/:id/medications,
+// /patient/:id/medications and /medication-orders
+// Reading orders requires patient:read, prescribing medication:order and verifying and dispensing
+// medication:verify; either of the last two can cancel
+func RegisterMedicationOrderRoutes(router *gin.RouterGroup, orderHandler *handler.MedicationOrderHandler, jwtService jwt.JWTService, revocationChecker domain.TokenRevocationChecker, idempotencyService domain.IdempotencyService) {
+	encounterMedicationGroup := router.Group("/encounters/:id/medications")
+	encounterMedicationGroup.Use(middleware.AuthMiddleware(jwtService, revocationChecker))
+	encounterMedicationGroup.Use(middleware.TenantRequiredMiddleware())
+	{
+		encounterMedicationGroup.POST("", middleware.RequirePermission(domain.PermMedicationOrder), middleware.IdempotencyMiddleware(idempotencyService), orderHandler.Create)
+		encounterMedicationGroup.GET("", middleware.RequirePermission(domain.PermPatientRead), orderHandler.ListByEncounter)
+	}
+
+	patientMedicationGroup := router.Group("/patient/:id/medications")
+	patientMedicationGroup.Use(middleware.AuthMiddleware(jwtService, revocationChecker))
+	patientMedicationGroup.Use(middleware.TenantRequiredMiddleware())
+	{
+		patientMedicationGroup.GET("", middleware.RequirePermission(domain.PermPatientRead), orderHandler.ListByPatient)
+	}
+
+	orderGroup := router.Group("/medication-orders")
+	orderGroup.Use(middleware.AuthMiddleware(jwtService, revocationChecker))
+	orderGroup.Use(middleware.TenantRequiredMiddleware())
+	{
+		orderGroup.GET("/:id", middleware.RequirePermission(domain.PermPatientRead), orderHandler.GetByID)
+		orderGroup.POST("/:id/verify", middleware.RequirePermission(domain.PermMedicationVerify), orderHandler.Verify)
+		orderGroup.POST("/:id/dispense", middleware.RequirePermission(domain.PermMedicationVerify), orderHandler.Dispense)
+		orderGroup.POST("/:id/cancel", middleware.RequireAnyPermission(domain.PermMedicationOrder, domain.PermMedicationVerify), orderHandler.Cancel)
+	}
+}
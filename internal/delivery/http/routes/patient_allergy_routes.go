@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/wichai2002/his_v1/internal/delivery/http/handler"
+	"github.com/wichai2002/his_v1/internal/delivery/http/middleware"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/pkg/jwt"
+)
+
+// RegisterPatientAllergyRoutes registers the allergy routes under /patient/:id/allergies
+// Reading allergies requires patient:read, recording them allergy:write
+func RegisterPatientAllergyRoutes(router *gin.RouterGroup, allergyHandler *handler.PatientAllergyHandler, jwtService jwt.JWTService, revocationChecker domain.TokenRevocationChecker) {
+	allergyGroup := router.Group("/patient/:id/allergies")
+	allergyGroup.Use(middleware.AuthMiddleware(jwtService, revocationChecker))
+	allergyGroup.Use(middleware.TenantRequiredMiddleware())
+	{
+		allergyGroup.GET("", middleware.RequirePermission(domain.PermPatientRead), allergyHandler.List)
+		allergyGroup.POST("", middleware.RequirePermission(domain.PermAllergyWrite), allergyHandler.Create)
+		allergyGroup.PUT("/:allergy_id", middleware.RequirePermission(domain.PermAllergyWrite), allergyHandler.Update)
+		allergyGroup.DELETE("/:allergy_id", middleware.RequirePermission(domain.PermAllergyWrite), allergyHandler.Delete)
+	}
+}
@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/wichai2002/his_v1/internal/delivery/http/handler"
+	"github.com/wichai2002/his_v1/internal/delivery/http/middleware"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/pkg/jwt"
+)
+
+// RegisterPatientContactRoutes registers the contact routes under /patient/:id/contacts
+// Reading contacts requires patient:read, changing them patient:write
+func RegisterPatientContactRoutes(router *gin.RouterGroup, contactHandler *handler.PatientContactHandler, jwtService jwt.JWTService, revocationChecker domain.TokenRevocationChecker) {
+	contactGroup := router.Group("/patient/:id/contacts")
+	contactGroup.Use(middleware.AuthMiddleware(jwtService, revocationChecker))
+	contactGroup.Use(middleware.TenantRequiredMiddleware())
+	{
+		contactGroup.GET("", middleware.RequirePermission(domain.PermPatientRead), contactHandler.List)
+		contactGroup.POST("", middleware.RequirePermission(domain.PermPatientWrite), contactHandler.Create)
+		contactGroup.PUT("/:contact_id", middleware.RequirePermission(domain.PermPatientWrite), contactHandler.Update)
+		contactGroup.DELETE("/:contact_id", middleware.RequirePermission(domain.PermPatientWrite), contactHandler.Delete)
+	}
+}
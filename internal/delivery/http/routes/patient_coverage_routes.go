@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/wichai2002/his_v1/internal/delivery/http/handler"
+	"github.com/wichai2002/his_v1/internal/delivery/http/middleware"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/pkg/jwt"
+)
+
+// RegisterPatientCoverageRoutes registers the payer coverage routes under /patient/:id/coverages
+// Reading coverages requires patient:read, recording them and checking eligibility coverage:write
+func RegisterPatientCoverageRoutes(router *gin.RouterGroup, coverageHandler *handler.PatientCoverageHandler, jwtService jwt.JWTService, revocationChecker domain.TokenRevocationChecker) {
+	coverageGroup := router.Group("/patient/:id/coverages")
+	coverageGroup.Use(middleware.AuthMiddleware(jwtService, revocationChecker))
+	coverageGroup.Use(middleware.TenantRequiredMiddleware())
+	{
+		coverageGroup.GET("", middleware.RequirePermission(domain.PermPatientRead), coverageHandler.List)
+		coverageGroup.GET("/active", middleware.RequirePermission(domain.PermPatientRead), coverageHandler.Active)
+		coverageGroup.POST("", middleware.RequirePermission(domain.PermCoverageWrite), coverageHandler.Create)
+		coverageGroup.PUT("/:coverage_id", middleware.RequirePermission(domain.PermCoverageWrite), coverageHandler.Update)
+		coverageGroup.DELETE("/:coverage_id", middleware.RequirePermission(domain.PermCoverageWrite), coverageHandler.Delete)
+		coverageGroup.POST("/:coverage_id/eligibility", middleware.RequirePermission(domain.PermCoverageWrite), coverageHandler.CheckEligibility)
+	}
+}
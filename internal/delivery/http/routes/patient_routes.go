@@ -4,20 +4,27 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/wichai2002/his_v1/internal/delivery/http/handler"
 	"github.com/wichai2002/his_v1/internal/delivery/http/middleware"
+	"github.com/wichai2002/his_v1/internal/domain"
 	"github.com/wichai2002/his_v1/pkg/jwt"
 )
 
 // RegisterPatientRoutes registers all patient-related routes
 // All patient routes require authentication and tenant context
-func RegisterPatientRoutes(router *gin.RouterGroup, patientHandler *handler.PatientHandler, jwtService jwt.JWTService) {
+func RegisterPatientRoutes(router *gin.RouterGroup, patientHandler *handler.PatientHandler, jwtService jwt.JWTService, revocationChecker domain.TokenRevocationChecker, idempotencyService domain.IdempotencyService) {
 	patientGroup := router.Group("/patient")
-	patientGroup.Use(middleware.AuthMiddleware(jwtService))
+	patientGroup.Use(middleware.AuthMiddleware(jwtService, revocationChecker))
 	patientGroup.Use(middleware.TenantRequiredMiddleware())
 	{
-		patientGroup.GET("/search", patientHandler.Search)
-		patientGroup.POST("/create", patientHandler.Create)
-		patientGroup.PUT("/update/:id", patientHandler.Update)
-		patientGroup.PATCH("/update/:id", patientHandler.PartialUpdate)
-		patientGroup.DELETE("/delete/:id", patientHandler.Delete)
+		patientGroup.GET("/search", middleware.RequirePermission(domain.PermPatientRead), patientHandler.Search)
+		patientGroup.POST("/create", middleware.RequirePermission(domain.PermPatientWrite), middleware.IdempotencyMiddleware(idempotencyService), patientHandler.Create)
+		patientGroup.PUT("/update/:id", middleware.RequirePermission(domain.PermPatientWrite), patientHandler.Update)
+		patientGroup.PATCH("/update/:id", middleware.RequirePermission(domain.PermPatientWrite), patientHandler.PartialUpdate)
+		patientGroup.GET("/:id", middleware.RequirePermission(domain.PermPatientRead), patientHandler.GetByID)
+		patientGroup.GET("/:id/history", middleware.RequirePermission(domain.PermPatientRead), patientHandler.History)
+		patientGroup.GET("/:id/history/diff", middleware.RequirePermission(domain.PermPatientRead), patientHandler.DiffVersions)
+		patientGroup.DELETE("/delete/:id", middleware.RequirePermission(domain.PermPatientDelete), patientHandler.Delete)
+		patientGroup.GET("/merges", middleware.RequirePermission(domain.PermPatientMerge), patientHandler.ListMerges)
+		patientGroup.POST("/merge", middleware.RequirePermission(domain.PermPatientMerge), patientHandler.Merge)
+		patientGroup.POST("/unmerge/:id", middleware.RequirePermission(domain.PermPatientMerge), patientHandler.Unmerge)
 	}
 }
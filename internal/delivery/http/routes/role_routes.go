@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/wichai2002/his_v1/internal/delivery/http/handler"
+	"github.com/wichai2002/his_v1/internal/delivery/http/middleware"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/pkg/jwt"
+)
+
+// RegisterRoleRoutes registers role and permission management routes
+// All role routes require the role:manage permission
+func RegisterRoleRoutes(router *gin.RouterGroup, roleHandler *handler.RoleHandler, jwtService jwt.JWTService, revocationChecker domain.TokenRevocationChecker) {
+	roleGroup := router.Group("/role")
+	roleGroup.Use(middleware.AuthMiddleware(jwtService, revocationChecker))
+	roleGroup.Use(middleware.TenantRequiredMiddleware())
+	roleGroup.Use(middleware.RequirePermission(domain.PermRoleManage))
+	{
+		roleGroup.GET("/", roleHandler.GetAll)
+		roleGroup.GET("/permissions", roleHandler.GetAllPermissions)
+		roleGroup.GET("/:id", roleHandler.GetByID)
+		roleGroup.POST("/create", roleHandler.Create)
+		roleGroup.PUT("/update/:id", roleHandler.Update)
+		roleGroup.DELETE("/delete/:id", roleHandler.Delete)
+		roleGroup.PUT("/staff/:id", roleHandler.AssignStaffRoles)
+	}
+}
@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/wichai2002/his_v1/internal/delivery/http/handler"
+	"github.com/wichai2002/his_v1/internal/delivery/http/middleware"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/pkg/jwt"
+)
+
+// RegisterSettingsRoutes registers the routes for tenant settings
+// All settings routes require the settings:manage permission
+func RegisterSettingsRoutes(router *gin.RouterGroup, settingsHandler *handler.SettingsHandler, jwtService jwt.JWTService, revocationChecker domain.TokenRevocationChecker) {
+	settingsGroup := router.Group("/settings")
+	settingsGroup.Use(middleware.AuthMiddleware(jwtService, revocationChecker))
+	settingsGroup.Use(middleware.TenantRequiredMiddleware())
+	settingsGroup.Use(middleware.RequirePermission(domain.PermSettingsManage))
+	{
+		settingsGroup.GET("/hn-template", settingsHandler.GetHNTemplate)
+		settingsGroup.PUT("/hn-template", settingsHandler.UpdateHNTemplate)
+		settingsGroup.POST("/hn-template/preview", settingsHandler.PreviewHNTemplate)
+	}
+}
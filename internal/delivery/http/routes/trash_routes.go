@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/wichai2002/his_v1/internal/delivery/http/handler"
+	"github.com/wichai2002/his_v1/internal/delivery/http/middleware"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/pkg/jwt"
+)
+
+// RegisterTrashRoutes registers the routes for deleted patients and staff
+// All trash routes require the trash:manage permission
+func RegisterTrashRoutes(router *gin.RouterGroup, trashHandler *handler.TrashHandler, jwtService jwt.JWTService, revocationChecker domain.TokenRevocationChecker) {
+	trashGroup := router.Group("/trash")
+	trashGroup.Use(middleware.AuthMiddleware(jwtService, revocationChecker))
+	trashGroup.Use(middleware.TenantRequiredMiddleware())
+	trashGroup.Use(middleware.RequirePermission(domain.PermTrashManage))
+	{
+		trashGroup.GET("/patients", trashHandler.ListPatients)
+		trashGroup.POST("/patients/restore/:id", trashHandler.RestorePatient)
+		trashGroup.DELETE("/patients/purge", trashHandler.PurgePatients)
+		trashGroup.GET("/staff", trashHandler.ListStaff)
+		trashGroup.POST("/staff/restore/:id", trashHandler.RestoreStaff)
+		trashGroup.DELETE("/staff/purge", trashHandler.PurgeStaff)
+	}
+}
@@ -0,0 +1,29 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/wichai2002/his_v1/internal/delivery/http/handler"
+	"github.com/wichai2002/his_v1/internal/delivery/http/middleware"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/pkg/jwt"
+)
+
+// RegisterVitalSignRoutes registers the routes under /encounters/:id/vitals and /patient/:id/vitals
+// Reading vital signs requires patient:read, recording them vitals:write
+func RegisterVitalSignRoutes(router *gin.RouterGroup, vitalHandler *handler.VitalSignHandler, jwtService jwt.JWTService, revocationChecker domain.TokenRevocationChecker) {
+	encounterVitalGroup := router.Group("/encounters/:id/vitals")
+	encounterVitalGroup.Use(middleware.AuthMiddleware(jwtService, revocationChecker))
+	encounterVitalGroup.Use(middleware.TenantRequiredMiddleware())
+	{
+		encounterVitalGroup.POST("", middleware.RequirePermission(domain.PermVitalsWrite), vitalHandler.Record)
+		encounterVitalGroup.GET("", middleware.RequirePermission(domain.PermPatientRead), vitalHandler.ListByEncounter)
+	}
+
+	patientVitalGroup := router.Group("/patient/:id/vitals")
+	patientVitalGroup.Use(middleware.AuthMiddleware(jwtService, revocationChecker))
+	patientVitalGroup.Use(middleware.TenantRequiredMiddleware())
+	{
+		patientVitalGroup.GET("", middleware.RequirePermission(domain.PermPatientRead), vitalHandler.ListByPatient)
+		patientVitalGroup.GET("/trend", middleware.RequirePermission(domain.PermPatientRead), vitalHandler.Trend)
+	}
+}
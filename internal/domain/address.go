@@ -0,0 +1,170 @@
+package domain
+
+import (
+	"gorm.io/gorm"
+)
+
+// Address types, a patient has at most one address of each type
+const (
+	AddressTypeRegistered = "registered"
+	AddressTypeCurrent    = "current"
+	AddressTypeWork       = "work"
+)
+
+// AddressTypes lists the valid address types in display order
+var AddressTypes = []string{AddressTypeRegistered, AddressTypeCurrent, AddressTypeWork}
+
+// IsValidAddressType reports whether t is one of AddressTypes
+func IsValidAddressType(t string) bool {
+	for _, addressType := range AddressTypes {
+		if addressType == t {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	// DefaultAddressSearchLimit is the number of autocomplete suggestions when no limit is given
+	DefaultAddressSearchLimit = 10
+	// MaxAddressSearchLimit caps the limit parameter of the autocomplete endpoint
+	MaxAddressSearchLimit = 50
+	// MinAddressSearchLength is the shortest query the autocomplete endpoint searches for
+	MinAddressSearchLength = 2
+)
+
+// Province is a changwat of the reference dataset, identified by its two digit DOPA code.
+// The reference tables live in the public schema and are shared by all tenants.
+type Province struct {
+	Code   string `json:"code" gorm:"primaryKey;size:2"`
+	NameTH string `json:"name_th" gorm:"not null;size:100"`
+	NameEN string `json:"name_en" gorm:"not null;size:100"`
+}
+
+func (Province) TableName() string {
+	return "thai_provinces"
+}
+
+// District is an amphoe (khet in Bangkok), identified by its four digit DOPA code
+type District struct {
+	Code         string `json:"code" gorm:"primaryKey;size:4"`
+	ProvinceCode string `json:"province_code" gorm:"not null;size:2"`
+	NameTH       string `json:"name_th" gorm:"not null;size:100"`
+	NameEN       string `json:"name_en" gorm:"not null;size:100"`
+}
+
+func (District) TableName() string {
+	return "thai_districts"
+}
+
+// Subdistrict is a tambon (khwaeng in Bangkok), identified by its six digit DOPA code
+type Subdistrict struct {
+	Code         string `json:"code" gorm:"primaryKey;size:6"`
+	DistrictCode string `json:"district_code" gorm:"not null;size:4"`
+	NameTH       string `json:"name_th" gorm:"not null;size:100"`
+	NameEN       string `json:"name_en" gorm:"not null;size:100"`
+	PostalCode   string `json:"postal_code" gorm:"not null;size:5"`
+}
+
+func (Subdistrict) TableName() string {
+	return "thai_subdistricts"
+}
+
+// AddressLocation is a subdistrict with its district and province, as suggested by autocomplete
+type AddressLocation struct {
+	SubdistrictCode string `json:"subdistrict_code"`
+	TambonTH        string `json:"tambon_th"`
+	TambonEN        string `json:"tambon_en"`
+	DistrictCode    string `json:"district_code"`
+	AmphoeTH        string `json:"amphoe_th"`
+	AmphoeEN        string `json:"amphoe_en"`
+	ProvinceCode    string `json:"province_code"`
+	ChangwatTH      string `json:"changwat_th"`
+	ChangwatEN      string `json:"changwat_en"`
+	PostalCode      string `json:"postal_code"`
+}
+
+// AddressSearchRequest holds the query string of GET /address/autocomplete
+type AddressSearchRequest struct {
+	// Query matches tambon, amphoe and changwat names in Thai or English, or a postal code prefix
+	Query string `form:"q" binding:"required"`
+	Limit int    `form:"limit" binding:"omitempty,min=1,max=50"`
+}
+
+// PatientAddress is a structured Thai address of a patient. The tambon, amphoe, changwat
+// and postal code are copied from the reference subdistrict when the address is saved.
+type PatientAddress struct {
+	gorm.Model
+	PatientID       uint   `json:"patient_id" gorm:"not null;index"`
+	AddressType     string `json:"address_type" gorm:"not null;size:20"`
+	HouseNo         string `json:"house_no" gorm:"not null;size:20"`
+	Moo             string `json:"moo" gorm:"size:10"`
+	Soi             string `json:"soi" gorm:"size:100"`
+	Road            string `json:"road" gorm:"size:100"`
+	SubdistrictCode string `json:"subdistrict_code" gorm:"not null;size:6"`
+	Tambon          string `json:"tambon" gorm:"not null;size:100"`
+	Amphoe          string `json:"amphoe" gorm:"not null;size:100"`
+	Changwat        string `json:"changwat" gorm:"not null;size:100"`
+	PostalCode      string `json:"postal_code" gorm:"not null;size:5"`
+}
+
+// PatientAddressFieldValues returns the audited fields of an address keyed by JSON name
+func PatientAddressFieldValues(a *PatientAddress) map[string]interface{} {
+	return map[string]interface{}{
+		"house_no":         a.HouseNo,
+		"moo":              a.Moo,
+		"soi":              a.Soi,
+		"road":             a.Road,
+		"subdistrict_code": a.SubdistrictCode,
+		"postal_code":      a.PostalCode,
+	}
+}
+
+// PatientAddressRequest is the body of PUT /patient/:id/addresses/:type
+type PatientAddressRequest struct {
+	HouseNo         string `json:"house_no" binding:"required,max=20"`
+	Moo             string `json:"moo" binding:"omitempty,max=10,numeric"`
+	Soi             string `json:"soi" binding:"max=100"`
+	Road            string `json:"road" binding:"max=100"`
+	SubdistrictCode string `json:"subdistrict_code" binding:"required,len=6,numeric"`
+	// PostalCode must be the postal code of the subdistrict
+	PostalCode string `json:"postal_code" binding:"required,len=5,numeric"`
+}
+
+// AddressReferenceRepository interface - the reference dataset in the public schema
+type AddressReferenceRepository interface {
+	ListProvinces() ([]Province, error)
+	ListDistricts(provinceCode string) ([]District, error)
+	ListSubdistricts(districtCode string) ([]Subdistrict, error)
+	GetLocation(subdistrictCode string) (*AddressLocation, error)
+	FindByPostalCode(postalCode string) ([]AddressLocation, error)
+	Search(query string, limit int) ([]AddressLocation, error)
+}
+
+// PatientAddressRepository interface - addresses are stored per tenant schema
+type PatientAddressRepository interface {
+	ListByPatient(patientID uint, schemaName string) ([]PatientAddress, error)
+	// GetByType returns the address of a type, nil when the patient has none
+	GetByType(patientID uint, addressType string, schemaName string) (*PatientAddress, error)
+	// Save creates or updates the address and appends the audit event in the same transaction
+	Save(address *PatientAddress, event *AuditEvent, schemaName string) error
+	Delete(address *PatientAddress, event *AuditEvent, schemaName string) error
+}
+
+// AddressService interface - lookups and autocomplete over the reference dataset
+type AddressService interface {
+	ListProvinces() ([]Province, error)
+	ListDistricts(provinceCode string) ([]District, error)
+	ListSubdistricts(districtCode string) ([]Subdistrict, error)
+	FindByPostalCode(postalCode string) ([]AddressLocation, error)
+	Autocomplete(req *AddressSearchRequest) ([]AddressLocation, error)
+}
+
+// PatientAddressService interface - typed addresses of a live patient, audited like the patient record
+type PatientAddressService interface {
+	List(patientID uint, actor *Actor, schemaName string) ([]PatientAddress, error)
+	// Save sets the address of a type, replacing the current one. It fails with ErrInvalidInput
+	// when the subdistrict is unknown or the postal code does not match it.
+	Save(patientID uint, addressType string, req *PatientAddressRequest, actor *Actor, schemaName string) (*PatientAddress, error)
+	Delete(patientID uint, addressType string, actor *Actor, schemaName string) error
+}
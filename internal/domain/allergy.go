@@ -0,0 +1,146 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Allergen types
+const (
+	AllergenTypeDrug          = "drug"
+	AllergenTypeFood          = "food"
+	AllergenTypeEnvironmental = "environmental"
+)
+
+// Allergy severities, from least to most severe
+const (
+	AllergySeverityMild            = "mild"
+	AllergySeverityModerate        = "moderate"
+	AllergySeveritySevere          = "severe"
+	AllergySeverityLifeThreatening = "life_threatening"
+)
+
+// Allergy certainties, how sure the recording staff member is of the allergy
+const (
+	AllergyCertaintySuspected = "suspected"
+	AllergyCertaintyProbable  = "probable"
+	AllergyCertaintyConfirmed = "confirmed"
+)
+
+// allergySeverityRank orders severities for the allergy banner
+var allergySeverityRank = map[string]int{
+	AllergySeverityMild:            1,
+	AllergySeverityModerate:        2,
+	AllergySeveritySevere:          3,
+	AllergySeverityLifeThreatening: 4,
+}
+
+// AllergyBannerMaxAllergens is the number of allergens named in the banner summary
+const AllergyBannerMaxAllergens = 3
+
+// PatientAllergy is an allergy or adverse drug reaction of a patient. RecordedBy is the staff
+// member who recorded the entry; later changes are attributed in the audit trail. An allergen
+// is recorded once per patient, compared ignoring case.
+type PatientAllergy struct {
+	gorm.Model
+	PatientID          uint   `json:"patient_id" gorm:"not null;index"`
+	AllergenType       string `json:"allergen_type" gorm:"not null;size:20"`
+	Allergen           string `json:"allergen" gorm:"not null;size:255"`
+	Reaction           string `json:"reaction" gorm:"size:500"`
+	Severity           string `json:"severity" gorm:"not null;size:20"`
+	Certainty          string `json:"certainty" gorm:"not null;size:20"`
+	Note               string `json:"note" gorm:"type:text"`
+	RecordedBy         uint   `json:"recorded_by" gorm:"not null"`
+	RecordedByUsername string `json:"recorded_by_username" gorm:"size:100"`
+}
+
+// PatientAllergyFieldValues returns the audited fields of an allergy keyed by JSON name
+func PatientAllergyFieldValues(a *PatientAllergy) map[string]interface{} {
+	return map[string]interface{}{
+		"allergen_type": a.AllergenType,
+		"allergen":      a.Allergen,
+		"reaction":      a.Reaction,
+		"severity":      a.Severity,
+		"certainty":     a.Certainty,
+		"note":          a.Note,
+	}
+}
+
+// PatientAllergyRequest is the body of POST and PUT /patient/:id/allergies
+type PatientAllergyRequest struct {
+	AllergenType string `json:"allergen_type" binding:"required,oneof=drug food environmental"`
+	Allergen     string `json:"allergen" binding:"required,max=255"`
+	Reaction     string `json:"reaction" binding:"max=500"`
+	Severity     string `json:"severity" binding:"required,oneof=mild moderate severe life_threatening"`
+	Certainty    string `json:"certainty" binding:"required,oneof=suspected probable confirmed"`
+	Note         string `json:"note" binding:"max=1000"`
+}
+
+// AllergyBanner summarises the allergies of a patient for search results and the patient header.
+// Count is 0 when no allergy has been recorded, which is not the same as no known allergies.
+type AllergyBanner struct {
+	Count int `json:"count"`
+	// HighestSeverity is empty when Count is 0
+	HighestSeverity string `json:"highest_severity,omitempty"`
+	HasDrugAllergy  bool   `json:"has_drug_allergy"`
+	// Summary names the most severe allergens, drug allergies first among equals
+	Summary string `json:"summary"`
+}
+
+// BuildAllergyBanner summarises the allergies of one patient
+func BuildAllergyBanner(allergies []PatientAllergy) *AllergyBanner {
+	banner := &AllergyBanner{Count: len(allergies)}
+	if len(allergies) == 0 {
+		return banner
+	}
+
+	sorted := make([]PatientAllergy, len(allergies))
+	copy(sorted, allergies)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if rank := allergySeverityRank[sorted[i].Severity] - allergySeverityRank[sorted[j].Severity]; rank != 0 {
+			return rank > 0
+		}
+		return sorted[i].AllergenType == AllergenTypeDrug && sorted[j].AllergenType != AllergenTypeDrug
+	})
+
+	banner.HighestSeverity = sorted[0].Severity
+	names := make([]string, 0, AllergyBannerMaxAllergens)
+	for i, allergy := range sorted {
+		if allergy.AllergenType == AllergenTypeDrug {
+			banner.HasDrugAllergy = true
+		}
+		if i < AllergyBannerMaxAllergens {
+			names = append(names, fmt.Sprintf("%s (%s)", allergy.Allergen, strings.ReplaceAll(allergy.Severity, "_", "-")))
+		}
+	}
+	banner.Summary = strings.Join(names, ", ")
+	if more := len(sorted) - AllergyBannerMaxAllergens; more > 0 {
+		banner.Summary += fmt.Sprintf(" +%d more", more)
+	}
+	return banner
+}
+
+// PatientAllergyRepository interface - allergies are stored per tenant schema
+type PatientAllergyRepository interface {
+	// ListByPatient returns the allergies of a patient and of the duplicates merged into it,
+	// most severe first
+	ListByPatient(patientID uint, schemaName string) ([]PatientAllergy, error)
+	GetByID(patientID uint, id uint, schemaName string) (*PatientAllergy, error)
+	// Write methods append the audit event in the same transaction
+	Create(allergy *PatientAllergy, event *AuditEvent, schemaName string) error
+	Update(allergy *PatientAllergy, event *AuditEvent, schemaName string) error
+	Delete(allergy *PatientAllergy, event *AuditEvent, schemaName string) error
+}
+
+// PatientAllergyService interface - allergies of a live patient, audited like the patient record
+type PatientAllergyService interface {
+	List(patientID uint, actor *Actor, schemaName string) ([]PatientAllergy, error)
+	// Create records the actor as the staff member who recorded the allergy. Create and Update
+	// fail with ErrDuplicateEntry when the patient already has an entry for the allergen.
+	Create(patientID uint, req *PatientAllergyRequest, actor *Actor, schemaName string) (*PatientAllergy, error)
+	Update(patientID uint, id uint, req *PatientAllergyRequest, actor *Actor, schemaName string) (*PatientAllergy, error)
+	Delete(patientID uint, id uint, actor *Actor, schemaName string) error
+}
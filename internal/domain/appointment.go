@@ -0,0 +1,281 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ClockFormat is the HH:MM format of the start and end times of availability templates
+const ClockFormat = "15:04"
+
+// Slot horizons, in days from today
+const (
+	// DefaultSlotHorizonDays is how far ahead a new availability template is expanded into slots
+	DefaultSlotHorizonDays = 28
+	// MaxSlotHorizonDays is the furthest ahead slots can be expanded
+	MaxSlotHorizonDays = 180
+)
+
+// AvailabilityTemplate is a weekly session a doctor publishes, such as Monday 09:00-12:00 in
+// the medicine clinic with 15 minute slots, valid from ValidFrom through ValidTo (open-ended
+// when nil). Times are Thai local time. Templates are expanded into AppointmentSlots.
+type AvailabilityTemplate struct {
+	gorm.Model
+	DoctorID     uint `json:"doctor_id" gorm:"not null;index"`
+	DepartmentID uint `json:"department_id" gorm:"not null"`
+	// Weekday is 0 for Sunday through 6 for Saturday
+	Weekday     int        `json:"weekday" gorm:"not null"`
+	StartTime   string     `json:"start_time" gorm:"not null;size:5"`
+	EndTime     string     `json:"end_time" gorm:"not null;size:5"`
+	SlotMinutes int        `json:"slot_minutes" gorm:"not null"`
+	ValidFrom   time.Time  `json:"valid_from" gorm:"type:date;not null"`
+	ValidTo     *time.Time `json:"valid_to" gorm:"type:date"`
+}
+
+// Slots expands the template into the slots starting on the calendar days from through to, both
+// inclusive, within the validity of the template. A session ends with the last slot that fits
+// before EndTime.
+func (t *AvailabilityTemplate) Slots(from, to time.Time) ([]AppointmentSlot, error) {
+	start, err := time.Parse(ClockFormat, t.StartTime)
+	if err != nil {
+		return nil, fmt.Errorf("%w: start_time must be in HH:MM format", ErrInvalidInput)
+	}
+	end, err := time.Parse(ClockFormat, t.EndTime)
+	if err != nil {
+		return nil, fmt.Errorf("%w: end_time must be in HH:MM format", ErrInvalidInput)
+	}
+	if t.SlotMinutes <= 0 {
+		return nil, fmt.Errorf("%w: slot_minutes must be positive", ErrInvalidInput)
+	}
+	length := time.Duration(t.SlotMinutes) * time.Minute
+
+	first := civilDay(from)
+	if validFrom := civilDay(t.ValidFrom); validFrom.After(first) {
+		first = validFrom
+	}
+	last := civilDay(to)
+	if t.ValidTo != nil {
+		if validTo := civilDay(*t.ValidTo); validTo.Before(last) {
+			last = validTo
+		}
+	}
+
+	var slots []AppointmentSlot
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		if int(day.Weekday()) != t.Weekday {
+			continue
+		}
+		sessionStart := time.Date(day.Year(), day.Month(), day.Day(), start.Hour(), start.Minute(), 0, 0, HNTimeZone)
+		sessionEnd := time.Date(day.Year(), day.Month(), day.Day(), end.Hour(), end.Minute(), 0, 0, HNTimeZone)
+		for slotStart := sessionStart; !slotStart.Add(length).After(sessionEnd); slotStart = slotStart.Add(length) {
+			slots = append(slots, AppointmentSlot{
+				TemplateID:   t.ID,
+				DoctorID:     t.DoctorID,
+				DepartmentID: t.DepartmentID,
+				StartAt:      slotStart,
+				EndAt:        slotStart.Add(length),
+			})
+		}
+	}
+	return slots, nil
+}
+
+// civilDay returns the calendar date of at as midnight UTC, the way DATE columns are read
+func civilDay(at time.Time) time.Time {
+	return time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// AppointmentSlot is a bookable period of a doctor expanded from an availability template.
+// A doctor has at most one slot starting at a given time. Slots are removed when their
+// template is withdrawn unless an appointment refers to them.
+type AppointmentSlot struct {
+	ID           uint      `json:"id" gorm:"primarykey"`
+	CreatedAt    time.Time `json:"created_at"`
+	TemplateID   uint      `json:"template_id" gorm:"not null;index"`
+	DoctorID     uint      `json:"doctor_id" gorm:"not null;uniqueIndex:idx_appointment_slots_doctor_start"`
+	DepartmentID uint      `json:"department_id" gorm:"not null"`
+	StartAt      time.Time `json:"start_at" gorm:"not null;uniqueIndex:idx_appointment_slots_doctor_start"`
+	EndAt        time.Time `json:"end_at" gorm:"not null"`
+}
+
+// AvailabilityTemplateRequest is the body of POST /availability.
+// StartTime and EndTime are HH:MM in Thai time; ValidFrom and ValidTo are YYYY-MM-DD.
+type AvailabilityTemplateRequest struct {
+	DoctorID     uint   `json:"doctor_id" binding:"required"`
+	DepartmentID uint   `json:"department_id" binding:"required"`
+	Weekday      *int   `json:"weekday" binding:"required,min=0,max=6"`
+	StartTime    string `json:"start_time" binding:"required,datetime=15:04"`
+	EndTime      string `json:"end_time" binding:"required,datetime=15:04"`
+	SlotMinutes  int    `json:"slot_minutes" binding:"required,min=5,max=240"`
+	ValidFrom    string `json:"valid_from" binding:"required"`
+	ValidTo      string `json:"valid_to"`
+}
+
+// AvailabilityExpandRequest is the body of POST /availability/:id/expand
+type AvailabilityExpandRequest struct {
+	// Until is the last day (YYYY-MM-DD) to create slots for, at most MaxSlotHorizonDays ahead
+	Until string `json:"until" binding:"required"`
+}
+
+// AvailabilityExpandResult reports the slots added by an expansion
+type AvailabilityExpandResult struct {
+	TemplateID   uint   `json:"template_id"`
+	Until        string `json:"until"`
+	SlotsCreated int    `json:"slots_created"`
+}
+
+// Appointment statuses. A rescheduled appointment is replaced by a new booked one.
+const (
+	AppointmentStatusBooked      = "booked"
+	AppointmentStatusCancelled   = "cancelled"
+	AppointmentStatusRescheduled = "rescheduled"
+)
+
+// Appointment is a booking of a slot for a patient. The doctor, department and times are copied
+// from the slot. A slot holds one booked appointment and a patient one booked appointment per
+// start time, both enforced by unique indexes.
+type Appointment struct {
+	gorm.Model
+	PatientID    uint      `json:"patient_id" gorm:"not null;index"`
+	SlotID       uint      `json:"slot_id" gorm:"not null"`
+	DoctorID     uint      `json:"doctor_id" gorm:"not null"`
+	DepartmentID uint      `json:"department_id" gorm:"not null"`
+	StartAt      time.Time `json:"start_at" gorm:"not null"`
+	EndAt        time.Time `json:"end_at" gorm:"not null"`
+	Status       string    `json:"status" gorm:"not null;size:20"`
+	Reason       string    `json:"reason" gorm:"size:500"`
+	BookedBy     uint      `json:"booked_by" gorm:"not null"`
+
+	CancelledAt  *time.Time `json:"cancelled_at"`
+	CancelReason string     `json:"cancel_reason" gorm:"size:500"`
+	// RescheduledFromID is the appointment this one replaced
+	RescheduledFromID *uint `json:"rescheduled_from_id"`
+}
+
+// AppointmentBookRequest is the body of POST /appointments
+type AppointmentBookRequest struct {
+	PatientID uint   `json:"patient_id" binding:"required"`
+	SlotID    uint   `json:"slot_id" binding:"required"`
+	Reason    string `json:"reason" binding:"max=500"`
+}
+
+// AppointmentRescheduleRequest is the body of POST /appointments/:id/reschedule
+type AppointmentRescheduleRequest struct {
+	SlotID uint `json:"slot_id" binding:"required"`
+}
+
+// AppointmentCancelRequest is the body of POST /appointments/:id/cancel
+type AppointmentCancelRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+// Calendar views
+const (
+	CalendarViewDay  = "day"
+	CalendarViewWeek = "week"
+)
+
+// AppointmentCalendarRequest holds the query string of GET /appointments/calendar.
+// At least one of DoctorID and DepartmentID is required.
+type AppointmentCalendarRequest struct {
+	DoctorID     uint `form:"doctor_id"`
+	DepartmentID uint `form:"department_id"`
+	// Date is a day of the calendar (YYYY-MM-DD), today in Thai time when empty
+	Date string `form:"date"`
+	// View is day (the default) or week, Monday through Sunday
+	View string `form:"view" binding:"omitempty,oneof=day week"`
+}
+
+// AppointmentCalendarFilter selects the slots starting in [From, To)
+type AppointmentCalendarFilter struct {
+	DoctorID     uint
+	DepartmentID uint
+	From         time.Time
+	To           time.Time
+}
+
+// CalendarEntry is a slot of a calendar with its booked appointment, if any
+type CalendarEntry struct {
+	SlotID       uint      `json:"slot_id"`
+	DoctorID     uint      `json:"doctor_id"`
+	DoctorName   string    `json:"doctor_name"`
+	DepartmentID uint      `json:"department_id"`
+	StartAt      time.Time `json:"start_at"`
+	EndAt        time.Time `json:"end_at"`
+	Available    bool      `json:"available" gorm:"-"`
+
+	AppointmentID *uint   `json:"appointment_id"`
+	PatientID     *uint   `json:"patient_id"`
+	PatientHN     *string `json:"patient_hn"`
+	FirstNameTH   *string `json:"first_name_th"`
+	LastNameTH    *string `json:"last_name_th"`
+	Reason        *string `json:"reason"`
+}
+
+// AppointmentCalendar is a day or week of slots; From and To are the first and last day
+type AppointmentCalendar struct {
+	View    string          `json:"view"`
+	From    string          `json:"from"`
+	To      string          `json:"to"`
+	Entries []CalendarEntry `json:"entries"`
+}
+
+// AvailabilityTemplateRepository interface - templates and slots are stored per tenant schema
+type AvailabilityTemplateRepository interface {
+	// List returns the live templates ordered by weekday and start time, of one doctor unless doctorID is 0
+	List(doctorID uint, schemaName string) ([]AvailabilityTemplate, error)
+	GetByID(id uint, schemaName string) (*AvailabilityTemplate, error)
+	// Create inserts the template and its first slots in one transaction
+	Create(template *AvailabilityTemplate, slots []AppointmentSlot, schemaName string) error
+	// AddSlots inserts the slots the doctor does not have yet and returns how many were inserted
+	AddSlots(slots []AppointmentSlot, schemaName string) (int, error)
+	// Delete withdraws the template and removes its slots starting at or after from that no
+	// appointment refers to
+	Delete(template *AvailabilityTemplate, from time.Time, schemaName string) error
+}
+
+// AppointmentRepository interface - appointments are stored per tenant schema
+type AppointmentRepository interface {
+	// GetSlot returns a slot of a live template
+	GetSlot(id uint, schemaName string) (*AppointmentSlot, error)
+	GetByID(id uint, schemaName string) (*Appointment, error)
+	// ListByPatient returns the appointments of a patient, latest start first
+	ListByPatient(patientID uint, schemaName string) ([]Appointment, error)
+	// Write methods append the audit event in the same transaction; a slot or patient that is
+	// already booked fails on the unique indexes
+	Create(appointment *Appointment, event *AuditEvent, schemaName string) error
+	// Reschedule marks previous rescheduled and inserts replacement, failing with
+	// ErrPreconditionFailed when previous is no longer booked
+	Reschedule(previous *Appointment, replacement *Appointment, event *AuditEvent, schemaName string) error
+	// Cancel saves the cancellation, failing with ErrPreconditionFailed when the appointment is no longer booked
+	Cancel(appointment *Appointment, event *AuditEvent, schemaName string) error
+	// Calendar returns the matching slots in start order, with their booked appointment
+	Calendar(filter *AppointmentCalendarFilter, schemaName string) ([]CalendarEntry, error)
+}
+
+// AvailabilityService interface - the weekly availability doctors publish
+type AvailabilityService interface {
+	List(doctorID uint, schemaName string) ([]AvailabilityTemplate, error)
+	// Create publishes a template of a doctor in an active department and expands it
+	// DefaultSlotHorizonDays ahead. It fails with ErrDuplicateEntry when the template overlaps
+	// another template of the doctor.
+	Create(req *AvailabilityTemplateRequest, schemaName string) (*AvailabilityTemplate, error)
+	// Expand creates the slots of the template up to req.Until
+	Expand(id uint, req *AvailabilityExpandRequest, schemaName string) (*AvailabilityExpandResult, error)
+	// Delete withdraws the template; future slots that are booked are kept
+	Delete(id uint, schemaName string) error
+}
+
+// AppointmentService interface - bookings of live patients, audited like the patient record
+type AppointmentService interface {
+	// Book fails with ErrDuplicateEntry when the slot or the patient is already booked at that time
+	Book(req *AppointmentBookRequest, actor *Actor, schemaName string) (*Appointment, error)
+	GetByID(id uint, actor *Actor, schemaName string) (*Appointment, error)
+	ListByPatient(patientID uint, actor *Actor, schemaName string) ([]Appointment, error)
+	// Reschedule moves a booked appointment to another slot and returns the new appointment
+	Reschedule(id uint, req *AppointmentRescheduleRequest, actor *Actor, schemaName string) (*Appointment, error)
+	Cancel(id uint, req *AppointmentCancelRequest, actor *Actor, schemaName string) (*Appointment, error)
+	Calendar(req *AppointmentCalendarRequest, actor *Actor, schemaName string) (*AppointmentCalendar, error)
+}
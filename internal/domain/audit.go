@@ -0,0 +1,268 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Audit actions recorded for patient records
+const (
+	AuditActionPatientView          = "patient.view"
+	AuditActionPatientSearch        = "patient.search"
+	AuditActionPatientCreate        = "patient.create"
+	AuditActionPatientUpdate        = "patient.update"
+	AuditActionPatientPartialUpdate = "patient.partial_update"
+	AuditActionPatientDelete        = "patient.delete"
+	// AuditActionPatientDuplicateCheck records patients shown as duplicate candidates
+	AuditActionPatientDuplicateCheck = "patient.duplicate_check"
+	// AuditActionPatientDuplicateOverride records a registration forced past duplicate candidates
+	AuditActionPatientDuplicateOverride = "patient.duplicate_override"
+	// AuditActionPatientMerge and AuditActionPatientUnmerge are recorded on both merged patients
+	AuditActionPatientMerge   = "patient.merge"
+	AuditActionPatientUnmerge = "patient.unmerge"
+	AuditActionPatientRestore = "patient.restore"
+	// AuditActionPatientPurge records a patient permanently removed from the trash
+	AuditActionPatientPurge = "patient.purge"
+	// AuditActionPatientHistory records a read of the version history of a patient
+	AuditActionPatientHistory = "patient.history"
+	// Contact actions are recorded against the patient the contact belongs to
+	AuditActionPatientContactView   = "patient.contact.view"
+	AuditActionPatientContactCreate = "patient.contact.create"
+	AuditActionPatientContactUpdate = "patient.contact.update"
+	AuditActionPatientContactDelete = "patient.contact.delete"
+	// Address actions are recorded against the patient, with the address type in the changes
+	AuditActionPatientAddressView   = "patient.address.view"
+	AuditActionPatientAddressSave   = "patient.address.save"
+	AuditActionPatientAddressDelete = "patient.address.delete"
+	AuditActionPatientAllergyView   = "patient.allergy.view"
+	AuditActionPatientAllergyCreate = "patient.allergy.create"
+	AuditActionPatientAllergyUpdate = "patient.allergy.update"
+	AuditActionPatientAllergyDelete = "patient.allergy.delete"
+	// Coverage actions are recorded against the patient the coverage belongs to
+	AuditActionPatientCoverageView   = "patient.coverage.view"
+	AuditActionPatientCoverageCreate = "patient.coverage.create"
+	AuditActionPatientCoverageUpdate = "patient.coverage.update"
+	AuditActionPatientCoverageDelete = "patient.coverage.delete"
+	// AuditActionPatientCoverageEligibility records an eligibility check with its outcome
+	AuditActionPatientCoverageEligibility = "patient.coverage.eligibility_check"
+	// Encounter actions are recorded against the patient of the visit; the VN is in the changes
+	AuditActionPatientEncounterView   = "patient.encounter.view"
+	AuditActionPatientEncounterCreate = "patient.encounter.create"
+	AuditActionPatientEncounterStatus = "patient.encounter.status"
+	// Appointment actions are recorded against the patient of the booking
+	AuditActionPatientAppointmentView       = "patient.appointment.view"
+	AuditActionPatientAppointmentBook       = "patient.appointment.book"
+	AuditActionPatientAppointmentReschedule = "patient.appointment.reschedule"
+	AuditActionPatientAppointmentCancel     = "patient.appointment.cancel"
+	// Vital sign actions are recorded against the patient; the VN is in the changes of a recording
+	AuditActionPatientVitalsView   = "patient.vitals.view"
+	AuditActionPatientVitalsRecord = "patient.vitals.record"
+	// Diagnosis actions are recorded against the patient; the VN and code are in the changes
+	AuditActionPatientDiagnosisView   = "patient.diagnosis.view"
+	AuditActionPatientDiagnosisCreate = "patient.diagnosis.create"
+	AuditActionPatientDiagnosisDelete = "patient.diagnosis.delete"
+	// Medication actions are recorded against the patient; the order ID and drug are in the changes
+	AuditActionPatientMedicationView  = "patient.medication.view"
+	AuditActionPatientMedicationOrder = "patient.medication.order"
+	// AuditActionPatientMedicationAlertOverride records an order placed despite its alerts
+	AuditActionPatientMedicationAlertOverride = "patient.medication.alert_override"
+	AuditActionPatientMedicationStatus        = "patient.medication.status"
+	// Lab actions are recorded against the patient; the order ID, barcode and test codes are in the changes
+	AuditActionPatientLabView     = "patient.lab.view"
+	AuditActionPatientLabOrder    = "patient.lab.order"
+	AuditActionPatientLabSpecimen = "patient.lab.specimen"
+	AuditActionPatientLabResult   = "patient.lab.result"
+	AuditActionPatientLabCancel   = "patient.lab.cancel"
+)
+
+// Actor identifies who performed a request, taken from the auth middleware and request headers
+type Actor struct {
+	StaffID   uint
+	Username  string
+	ClientIP  string
+	RequestID string
+}
+
+// FieldChange holds the before and after value of a changed field
+type FieldChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// AuditEvent is an append-only, hash-chained record of an access to a patient record.
+// Hash covers PrevHash and every other field except ID, so editing or deleting
+// a row breaks the chain from that row onwards.
+type AuditEvent struct {
+	ID            uint      `json:"id" gorm:"primarykey"`
+	CreatedAt     time.Time `json:"created_at" gorm:"not null"`
+	ActorID       uint      `json:"actor_id" gorm:"not null;index"`
+	ActorUsername string    `json:"actor_username" gorm:"size:100"`
+	Action        string    `json:"action" gorm:"not null;size:50"`
+	PatientID     *uint     `json:"patient_id" gorm:"index"`
+	// Changes is the JSON encoded map of FieldChange, stored as text so the hashed bytes are preserved
+	Changes   string `json:"changes" gorm:"type:text"`
+	ClientIP  string `json:"client_ip" gorm:"size:45"`
+	RequestID string `json:"request_id" gorm:"size:64"`
+	PrevHash  string `json:"prev_hash" gorm:"not null;size:64"`
+	Hash      string `json:"hash" gorm:"uniqueIndex;not null;size:64"`
+}
+
+// NewAuditEvent builds an audit event for the actor; a nil actor is recorded as the system (ID 0)
+func NewAuditEvent(actor *Actor, action string, patientID *uint, changes map[string]FieldChange) (*AuditEvent, error) {
+	event := &AuditEvent{
+		Action:    action,
+		PatientID: patientID,
+	}
+	if actor != nil {
+		event.ActorID = actor.StaffID
+		event.ActorUsername = actor.Username
+		event.ClientIP = actor.ClientIP
+		event.RequestID = actor.RequestID
+	}
+
+	if len(changes) > 0 {
+		encoded, err := json.Marshal(changes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode audit changes: %w", err)
+		}
+		event.Changes = string(encoded)
+	}
+
+	return event, nil
+}
+
+// SetChange records a change known only once the write is under way, such as an issued number.
+// It must be called before the event is appended.
+func (e *AuditEvent) SetChange(name string, change FieldChange) error {
+	changes := make(map[string]FieldChange)
+	if e.Changes != "" {
+		if err := json.Unmarshal([]byte(e.Changes), &changes); err != nil {
+			return fmt.Errorf("failed to decode audit changes: %w", err)
+		}
+	}
+	changes[name] = change
+	encoded, err := json.Marshal(changes)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit changes: %w", err)
+	}
+	e.Changes = string(encoded)
+	return nil
+}
+
+// auditHashContent is the canonical encoding of an audit event that is hashed.
+// JSON keeps field boundaries unambiguous, so content cannot move between fields
+// without changing the hash, and a nil PatientID encodes differently from zero.
+type auditHashContent struct {
+	PrevHash      string `json:"prev_hash"`
+	CreatedAt     string `json:"created_at"`
+	ActorID       uint   `json:"actor_id"`
+	ActorUsername string `json:"actor_username"`
+	Action        string `json:"action"`
+	PatientID     *uint  `json:"patient_id"`
+	Changes       string `json:"changes"`
+	ClientIP      string `json:"client_ip"`
+	RequestID     string `json:"request_id"`
+}
+
+// ComputeHash returns the SHA-256 of the event content chained to PrevHash
+func (e *AuditEvent) ComputeHash() string {
+	// Marshalling a struct of strings and integers cannot fail
+	content, _ := json.Marshal(auditHashContent{
+		PrevHash:      e.PrevHash,
+		CreatedAt:     e.CreatedAt.UTC().Format(time.RFC3339Nano),
+		ActorID:       e.ActorID,
+		ActorUsername: e.ActorUsername,
+		Action:        e.Action,
+		PatientID:     e.PatientID,
+		Changes:       e.Changes,
+		ClientIP:      e.ClientIP,
+		RequestID:     e.RequestID,
+	})
+
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// AuditFilter is used to query the audit trail by patient or staff member
+type AuditFilter struct {
+	PatientID *uint
+	ActorID   *uint
+	Action    string
+	From      *time.Time
+	To        *time.Time
+	Limit     int
+	Offset    int
+}
+
+// AuditVerifyResult reports whether the hash chain of a tenant's audit trail is intact
+type AuditVerifyResult struct {
+	Valid         bool  `json:"valid"`
+	CheckedEvents int64 `json:"checked_events"`
+	// BrokenAtID is the first event whose hash or chain link does not match
+	BrokenAtID *uint `json:"broken_at_id,omitempty"`
+}
+
+// PatientFieldValues returns the auditable demographic fields of a patient keyed by column name.
+// Fields not stored on the patient row, such as PrimaryContact, are left out.
+func PatientFieldValues(p *Patient) map[string]interface{} {
+	values := make(map[string]interface{})
+	v := reflect.ValueOf(p).Elem()
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if field.Anonymous || name == "" || name == "-" || field.Tag.Get("gorm") == "-" {
+			continue
+		}
+		values[name] = auditValue(v.Field(i).Interface())
+	}
+	return values
+}
+
+// DiffPatientFields compares before values with the given after values and returns the changed fields
+func DiffPatientFields(before map[string]interface{}, after map[string]interface{}) map[string]FieldChange {
+	changes := make(map[string]FieldChange)
+	for name, afterValue := range after {
+		afterValue = auditValue(afterValue)
+		if before[name] != afterValue {
+			changes[name] = FieldChange{Before: before[name], After: afterValue}
+		}
+	}
+	return changes
+}
+
+// auditValue normalises values so they compare and encode consistently
+func auditValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case time.Time:
+		return v.Format(DateFormat)
+	case Gender:
+		return string(v)
+	case BloodGrp:
+		return string(v)
+	case NullableString:
+		return string(v)
+	default:
+		return value
+	}
+}
+
+// AuditRepository interface - audit events live in the tenant schema and are never updated
+type AuditRepository interface {
+	Append(events []*AuditEvent, schemaName string) error
+	Query(filter *AuditFilter, schemaName string) ([]AuditEvent, int64, error)
+	// GetChain returns up to limit events with ID greater than afterID in ID order
+	GetChain(afterID uint, limit int, schemaName string) ([]AuditEvent, error)
+}
+
+// AuditService interface for querying and verifying the audit trail
+type AuditService interface {
+	Query(filter *AuditFilter, schemaName string) ([]AuditEvent, int64, error)
+	Verify(schemaName string) (*AuditVerifyResult, error)
+}
@@ -0,0 +1,133 @@
+package domain
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Coverage schemes, the payer rights a patient can be treated under
+const (
+	// CoverageSchemeUC is the Universal Coverage (30 baht) scheme administered by NHSO
+	CoverageSchemeUC = "uc"
+	// CoverageSchemeSSS is the Social Security Scheme
+	CoverageSchemeSSS = "sss"
+	// CoverageSchemeCSMBS is the Civil Servant Medical Benefit Scheme
+	CoverageSchemeCSMBS = "csmbs"
+	// CoverageSchemePrivate is private health insurance
+	CoverageSchemePrivate = "private"
+	// CoverageSchemeSelfPay is treatment paid by the patient
+	CoverageSchemeSelfPay = "self_pay"
+)
+
+// CoverageSchemes lists the schemes in the order coverages are shown, public schemes first
+var CoverageSchemes = []string{CoverageSchemeCSMBS, CoverageSchemeSSS, CoverageSchemeUC, CoverageSchemePrivate, CoverageSchemeSelfPay}
+
+// Eligibility statuses stored on a coverage after a check
+const (
+	EligibilityStatusEligible    = "eligible"
+	EligibilityStatusNotEligible = "not_eligible"
+)
+
+// PatientCoverage is a payer right of a patient. The coverage is valid from ValidFrom through
+// ValidTo, both inclusive; a nil ValidTo means the coverage has no end date. The eligibility
+// fields hold the outcome of the last check and are empty until one has been run.
+type PatientCoverage struct {
+	gorm.Model
+	PatientID uint   `json:"patient_id" gorm:"not null;index"`
+	Scheme    string `json:"scheme" gorm:"not null;size:20"`
+	// MainHospitalCode is the 5 digit MOPH code of the registered hospital (hospmain)
+	MainHospitalCode string     `json:"main_hospital_code" gorm:"size:5"`
+	MainHospitalName string     `json:"main_hospital_name" gorm:"size:255"`
+	PolicyNumber     string     `json:"policy_number" gorm:"size:50"`
+	ValidFrom        time.Time  `json:"valid_from" gorm:"type:date;not null"`
+	ValidTo          *time.Time `json:"valid_to" gorm:"type:date"`
+
+	EligibilityStatus    string     `json:"eligibility_status" gorm:"size:20"`
+	EligibilityMessage   string     `json:"eligibility_message" gorm:"size:500"`
+	EligibilityCheckedAt *time.Time `json:"eligibility_checked_at"`
+}
+
+// PatientCoverageFieldValues returns the audited fields of a coverage keyed by JSON name
+func PatientCoverageFieldValues(c *PatientCoverage) map[string]interface{} {
+	values := map[string]interface{}{
+		"scheme":             c.Scheme,
+		"main_hospital_code": c.MainHospitalCode,
+		"main_hospital_name": c.MainHospitalName,
+		"policy_number":      c.PolicyNumber,
+		"valid_from":         c.ValidFrom.Format(DateFormat),
+		"valid_to":           "",
+		"eligibility_status": c.EligibilityStatus,
+	}
+	if c.ValidTo != nil {
+		values["valid_to"] = c.ValidTo.Format(DateFormat)
+	}
+	return values
+}
+
+// PatientCoverageRequest is the body of POST and PUT /patient/:id/coverages.
+// UC and SSS coverages need a main hospital, private insurance needs a policy number.
+type PatientCoverageRequest struct {
+	Scheme           string `json:"scheme" binding:"required,oneof=uc sss csmbs private self_pay"`
+	MainHospitalCode string `json:"main_hospital_code" binding:"omitempty,len=5,numeric"`
+	MainHospitalName string `json:"main_hospital_name" binding:"max=255"`
+	PolicyNumber     string `json:"policy_number" binding:"max=50"`
+	// ValidFrom and ValidTo are dates in YYYY-MM-DD format; ValidTo is optional
+	ValidFrom string `json:"valid_from" binding:"required"`
+	ValidTo   string `json:"valid_to"`
+}
+
+// EligibilityQuery asks a payer whether a person is covered by a scheme on a date
+type EligibilityQuery struct {
+	NationalID string
+	Scheme     string
+	On         time.Time
+}
+
+// EligibilityResult is the answer of an eligibility checker
+type EligibilityResult struct {
+	Eligible bool   `json:"eligible"`
+	Scheme   string `json:"scheme"`
+	// MainHospitalCode is the registered hospital the payer holds, empty when it has none
+	MainHospitalCode string    `json:"main_hospital_code"`
+	Message          string    `json:"message"`
+	CheckedAt        time.Time `json:"checked_at"`
+	// Source names the checker that answered, such as "fake"
+	Source string `json:"source"`
+}
+
+// EligibilityChecker checks payer rights against an external service such as the NHSO
+// right-check service. Implementations return an error when the service cannot answer,
+// and a result with Eligible false when the person is not covered.
+type EligibilityChecker interface {
+	Check(query *EligibilityQuery) (*EligibilityResult, error)
+}
+
+// PatientCoverageRepository interface - coverages are stored per tenant schema
+type PatientCoverageRepository interface {
+	// ListByPatient returns the coverages of a patient in CoverageSchemes order, newest first within a scheme
+	ListByPatient(patientID uint, schemaName string) ([]PatientCoverage, error)
+	// ListActive returns the coverages valid on the calendar day of on, in ListByPatient order
+	ListActive(patientID uint, on time.Time, schemaName string) ([]PatientCoverage, error)
+	GetByID(patientID uint, id uint, schemaName string) (*PatientCoverage, error)
+	// Write methods append the audit event in the same transaction
+	Create(coverage *PatientCoverage, event *AuditEvent, schemaName string) error
+	Update(coverage *PatientCoverage, event *AuditEvent, schemaName string) error
+	Delete(coverage *PatientCoverage, event *AuditEvent, schemaName string) error
+}
+
+// PatientCoverageService interface - payer coverage of a live patient, audited like the patient record
+type PatientCoverageService interface {
+	List(patientID uint, actor *Actor, schemaName string) ([]PatientCoverage, error)
+	// Active returns the coverages valid on date (YYYY-MM-DD), today in Thai time when date is empty
+	Active(patientID uint, date string, actor *Actor, schemaName string) ([]PatientCoverage, error)
+	// Create and Update fail with ErrDuplicateEntry when the period overlaps another coverage
+	// of the patient under the same scheme
+	Create(patientID uint, req *PatientCoverageRequest, actor *Actor, schemaName string) (*PatientCoverage, error)
+	Update(patientID uint, id uint, req *PatientCoverageRequest, actor *Actor, schemaName string) (*PatientCoverage, error)
+	Delete(patientID uint, id uint, actor *Actor, schemaName string) error
+	// CheckEligibility asks the eligibility checker about a coverage on date (YYYY-MM-DD, today
+	// when empty) and stores the outcome on the coverage. It fails with ErrEligibilityUnavailable
+	// when the checker cannot answer.
+	CheckEligibility(patientID uint, id uint, date string, actor *Actor, schemaName string) (*EligibilityResult, error)
+}
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"gorm.io/gorm"
+)
+
+// Department is a clinical service point patients are registered to, such as the general
+// outpatient clinic. Departments are kept per tenant schema; one that closes is deactivated
+// rather than deleted so its encounters keep their department.
+type Department struct {
+	gorm.Model
+	Code     string `json:"code" gorm:"uniqueIndex:idx_departments_code_live,where:deleted_at IS NULL;not null;size:20"`
+	NameTH   string `json:"name_th" gorm:"not null;size:255"`
+	NameEN   string `json:"name_en" gorm:"not null;size:255"`
+	IsActive bool   `json:"is_active" gorm:"not null"`
+}
+
+// DepartmentRequest is the body of POST and PUT /departments
+type DepartmentRequest struct {
+	Code   string `json:"code" binding:"required,max=20,alphanum"`
+	NameTH string `json:"name_th" binding:"required,max=255"`
+	NameEN string `json:"name_en" binding:"required,max=255"`
+	// IsActive defaults to true when omitted
+	IsActive *bool `json:"is_active"`
+}
+
+// DepartmentRepository interface - departments are stored per tenant schema
+type DepartmentRepository interface {
+	// List returns the departments ordered by code, only active ones unless includeInactive is set
+	List(includeInactive bool, schemaName string) ([]Department, error)
+	GetByID(id uint, schemaName string) (*Department, error)
+	Create(department *Department, schemaName string) error
+	Update(department *Department, schemaName string) error
+}
+
+// DepartmentService interface - the department list of a tenant
+type DepartmentService interface {
+	List(includeInactive bool, schemaName string) ([]Department, error)
+	// Create and Update fail with ErrDuplicateEntry when another department has the code
+	Create(req *DepartmentRequest, schemaName string) (*Department, error)
+	Update(id uint, req *DepartmentRequest, schemaName string) (*Department, error)
+}
@@ -0,0 +1,119 @@
+package domain
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// DefaultICD10SearchLimit is the number of codes a catalogue search returns when no limit is given
+	DefaultICD10SearchLimit = 20
+	// MaxICD10SearchLimit caps the limit parameter of the catalogue search
+	MaxICD10SearchLimit = 100
+	// MinICD10SearchLength is the shortest query the catalogue search accepts
+	MinICD10SearchLength = 2
+)
+
+// ICD10Code is an entry of the ICD-10-TM catalogue, e.g. J18.9. Three character categories
+// have no parent; a code without children is billable and is the only kind that can be the
+// principal diagnosis. The catalogue lives in the public schema and is shared by all tenants.
+type ICD10Code struct {
+	Code        string  `json:"code" gorm:"primaryKey;size:8"`
+	ParentCode  *string `json:"parent_code" gorm:"size:8"`
+	Description string  `json:"description" gorm:"not null;size:255"`
+	Billable    bool    `json:"billable" gorm:"not null"`
+}
+
+func (ICD10Code) TableName() string {
+	return "icd10_codes"
+}
+
+// NormalizeICD10Code upper-cases a code and puts the dot after the category, so J189, j18.9
+// and J18.9 are the same code
+func NormalizeICD10Code(code string) string {
+	code = strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(code), ".", ""))
+	if len(code) > 3 {
+		return code[:3] + "." + code[3:]
+	}
+	return code
+}
+
+// IsExternalCauseCode reports whether code is in chapter XX, external causes of morbidity (V01-Y98)
+func IsExternalCauseCode(code string) bool {
+	return code != "" && code[0] >= 'V' && code[0] <= 'Y'
+}
+
+// ICD10SearchRequest holds the query string of GET /icd10
+type ICD10SearchRequest struct {
+	Query        string `form:"q" binding:"required"`
+	BillableOnly bool   `form:"billable_only"`
+	Limit        int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// ICD10SearchFilter selects catalogue codes by code prefix or by words of the description
+type ICD10SearchFilter struct {
+	// CodePrefix matches codes starting with it, ignoring the dot
+	CodePrefix string
+	// Keywords must all appear in the description
+	Keywords     []string
+	BillableOnly bool
+	Limit        int
+}
+
+// Diagnosis types. An encounter has at most one principal diagnosis.
+const (
+	DiagnosisTypePrincipal     = "principal"
+	DiagnosisTypeComorbidity   = "comorbidity"
+	DiagnosisTypeComplication  = "complication"
+	DiagnosisTypeExternalCause = "external_cause"
+)
+
+// Diagnosis is an ICD-10 code recorded for an encounter. The description is copied from the
+// catalogue so the record reads the same if the catalogue is replaced.
+type Diagnosis struct {
+	gorm.Model
+	EncounterID uint   `json:"encounter_id" gorm:"not null;index"`
+	PatientID   uint   `json:"patient_id" gorm:"not null;index"`
+	Code        string `json:"code" gorm:"not null;size:8"`
+	Description string `json:"description" gorm:"not null;size:255"`
+	Type        string `json:"type" gorm:"not null;size:20"`
+	Note        string `json:"note" gorm:"size:500"`
+	DiagnosedBy uint   `json:"diagnosed_by" gorm:"not null"`
+}
+
+// DiagnosisRequest is the body of POST /encounters/:id/diagnoses
+type DiagnosisRequest struct {
+	Code string `json:"code" binding:"required,max=10"`
+	Type string `json:"type" binding:"required,oneof=principal comorbidity complication external_cause"`
+	Note string `json:"note" binding:"max=500"`
+}
+
+// ICD10Repository defines the interface for access to the public ICD-10 catalogue
+type ICD10Repository interface {
+	GetByCode(code string) (*ICD10Code, error)
+	Search(filter *ICD10SearchFilter) ([]ICD10Code, error)
+}
+
+// DiagnosisRepository defines the interface for diagnosis data access
+type DiagnosisRepository interface {
+	GetByID(encounterID uint, id uint, schemaName string) (*Diagnosis, error)
+	ListByEncounter(encounterID uint, schemaName string) ([]Diagnosis, error)
+	ListByPatient(patientID uint, schemaName string) ([]Diagnosis, error)
+	Create(diagnosis *Diagnosis, event *AuditEvent, schemaName string) error
+	Delete(diagnosis *Diagnosis, event *AuditEvent, schemaName string) error
+}
+
+// ICD10Service defines the interface for catalogue lookups
+type ICD10Service interface {
+	GetByCode(code string) (*ICD10Code, error)
+	Search(req *ICD10SearchRequest) ([]ICD10Code, error)
+}
+
+// DiagnosisService defines the interface for diagnosis business logic
+type DiagnosisService interface {
+	Create(encounterID uint, req *DiagnosisRequest, actor *Actor, schemaName string) (*Diagnosis, error)
+	ListByEncounter(encounterID uint, actor *Actor, schemaName string) ([]Diagnosis, error)
+	ListByPatient(patientID uint, actor *Actor, schemaName string) ([]Diagnosis, error)
+	Delete(encounterID uint, id uint, actor *Actor, schemaName string) error
+}
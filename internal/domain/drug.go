@@ -0,0 +1,139 @@
+package domain
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Drug interaction severities, from least to most severe
+const (
+	InteractionSeverityMinor           = "minor"
+	InteractionSeverityModerate        = "moderate"
+	InteractionSeverityMajor           = "major"
+	InteractionSeverityContraindicated = "contraindicated"
+)
+
+// drugAllergyMinMatchLength is the shortest name matched inside a longer allergen or drug name,
+// so that short abbreviations do not match unrelated drugs
+const drugAllergyMinMatchLength = 4
+
+// Drug is an entry of the drug master of a tenant. TherapeuticClass groups drugs that should not
+// be ordered together, such as two NSAIDs, and is stored lower case. Drugs that are no longer
+// stocked are deactivated rather than deleted so their orders keep their drug.
+type Drug struct {
+	gorm.Model
+	Code             string `json:"code" gorm:"uniqueIndex:idx_drugs_code_live,where:deleted_at IS NULL;not null;size:20"`
+	GenericName      string `json:"generic_name" gorm:"not null;size:255"`
+	TradeName        string `json:"trade_name" gorm:"size:255"`
+	Strength         string `json:"strength" gorm:"size:50"`
+	DosageForm       string `json:"dosage_form" gorm:"not null;size:50"`
+	DispensingUnit   string `json:"dispensing_unit" gorm:"not null;size:20"`
+	TherapeuticClass string `json:"therapeutic_class" gorm:"not null;size:100"`
+	IsActive         bool   `json:"is_active" gorm:"not null"`
+}
+
+// DisplayName is the generic name with the strength, e.g. Amoxicillin 500 mg
+func (d *Drug) DisplayName() string {
+	if d.Strength == "" {
+		return d.GenericName
+	}
+	return d.GenericName + " " + d.Strength
+}
+
+// MatchesAllergen reports whether a recorded allergen names the drug, its trade name or its
+// therapeutic class, ignoring case. Names of at least four letters also match inside each
+// other, so an allergy to "penicillins" matches the class "penicillin".
+func (d *Drug) MatchesAllergen(allergen string) bool {
+	allergen = strings.ToLower(strings.TrimSpace(allergen))
+	if allergen == "" {
+		return false
+	}
+	for _, name := range []string{d.GenericName, d.TradeName, d.TherapeuticClass} {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if name == allergen {
+			return true
+		}
+		if len(name) >= drugAllergyMinMatchLength && len(allergen) >= drugAllergyMinMatchLength &&
+			(strings.Contains(name, allergen) || strings.Contains(allergen, name)) {
+			return true
+		}
+	}
+	return false
+}
+
+// DrugRequest is the body of POST and PUT /drugs
+type DrugRequest struct {
+	Code             string `json:"code" binding:"required,max=20,alphanum"`
+	GenericName      string `json:"generic_name" binding:"required,max=255"`
+	TradeName        string `json:"trade_name" binding:"max=255"`
+	Strength         string `json:"strength" binding:"max=50"`
+	DosageForm       string `json:"dosage_form" binding:"required,max=50"`
+	DispensingUnit   string `json:"dispensing_unit" binding:"required,max=20"`
+	TherapeuticClass string `json:"therapeutic_class" binding:"required,max=100"`
+	// IsActive defaults to true when omitted
+	IsActive *bool `json:"is_active"`
+}
+
+// DrugListRequest holds the query string of GET /drugs
+type DrugListRequest struct {
+	// Query matches the code, generic name or trade name
+	Query           string `form:"q"`
+	IncludeInactive bool   `form:"include_inactive"`
+}
+
+// DrugInteraction is a known interaction between two drugs of the master. The pair is stored
+// once with DrugID below InteractingDrugID.
+type DrugInteraction struct {
+	gorm.Model
+	DrugID            uint   `json:"drug_id" gorm:"not null;index"`
+	InteractingDrugID uint   `json:"interacting_drug_id" gorm:"not null;index"`
+	Severity          string `json:"severity" gorm:"not null;size:20"`
+	Description       string `json:"description" gorm:"not null;size:500"`
+}
+
+// OtherDrugID returns the drug of the pair that is not drugID
+func (i *DrugInteraction) OtherDrugID(drugID uint) uint {
+	if i.DrugID == drugID {
+		return i.InteractingDrugID
+	}
+	return i.DrugID
+}
+
+// DrugInteractionRequest is the body of POST /drugs/:id/interactions
+type DrugInteractionRequest struct {
+	InteractingDrugID uint   `json:"interacting_drug_id" binding:"required"`
+	Severity          string `json:"severity" binding:"required,oneof=minor moderate major contraindicated"`
+	Description       string `json:"description" binding:"required,max=500"`
+}
+
+// DrugRepository interface - the drug master is stored per tenant schema
+type DrugRepository interface {
+	// List returns the drugs ordered by generic name, only active ones unless includeInactive is set
+	List(query string, includeInactive bool, schemaName string) ([]Drug, error)
+	GetByID(id uint, schemaName string) (*Drug, error)
+	Create(drug *Drug, schemaName string) error
+	Update(drug *Drug, schemaName string) error
+	// ListInteractions returns the interactions of a drug, with drugIDs narrowing them to
+	// interactions with those drugs when not empty
+	ListInteractions(drugID uint, drugIDs []uint, schemaName string) ([]DrugInteraction, error)
+	GetInteraction(drugID uint, id uint, schemaName string) (*DrugInteraction, error)
+	CreateInteraction(interaction *DrugInteraction, schemaName string) error
+	DeleteInteraction(interaction *DrugInteraction, schemaName string) error
+}
+
+// DrugService interface - the drug master of a tenant and its known interactions
+type DrugService interface {
+	List(req *DrugListRequest, schemaName string) ([]Drug, error)
+	GetByID(id uint, schemaName string) (*Drug, error)
+	// Create and Update fail with ErrDuplicateEntry when another drug has the code
+	Create(req *DrugRequest, schemaName string) (*Drug, error)
+	Update(id uint, req *DrugRequest, schemaName string) (*Drug, error)
+	ListInteractions(drugID uint, schemaName string) ([]DrugInteraction, error)
+	// CreateInteraction fails with ErrDuplicateEntry when the pair already has an interaction
+	CreateInteraction(drugID uint, req *DrugInteractionRequest, schemaName string) (*DrugInteraction, error)
+	DeleteInteraction(drugID uint, id uint, schemaName string) error
+}
@@ -0,0 +1,143 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DefaultVNTemplate issues visit numbers such as 69031500001, restarting every day.
+// VNs use the HN template grammar, with their own counters in vn_counters.
+const DefaultVNTemplate = "{BE_YY}{MM}{DD}{SEQ:5}"
+
+// vnTemplate is parsed once; DefaultVNTemplate is known to be valid
+var vnTemplate, _ = ParseHNTemplate(DefaultVNTemplate)
+
+// NewVNIssuer returns the issuer of visit numbers, used by EncounterRepository.Create
+func NewVNIssuer() *HNIssuer {
+	return &HNIssuer{Template: vnTemplate}
+}
+
+// Encounter statuses, in workflow order
+const (
+	EncounterStatusRegistered     = "registered"
+	EncounterStatusTriaged        = "triaged"
+	EncounterStatusInConsultation = "in_consultation"
+	EncounterStatusDone           = "done"
+)
+
+// EncounterStatuses lists the statuses in workflow order
+var EncounterStatuses = []string{EncounterStatusRegistered, EncounterStatusTriaged, EncounterStatusInConsultation, EncounterStatusDone}
+
+// ErrInvalidStatusTransition is returned when a status change skips or reverses a workflow step
+var ErrInvalidStatusTransition = errors.New("invalid status transition")
+
+// NextEncounterStatus returns the status that follows status in the workflow, "" after done
+func NextEncounterStatus(status string) string {
+	for i, s := range EncounterStatuses {
+		if s == status && i+1 < len(EncounterStatuses) {
+			return EncounterStatuses[i+1]
+		}
+	}
+	return ""
+}
+
+// Encounter is a visit of a patient to a department, identified by its visit number (VN).
+// Each status of the workflow registered → triaged → in consultation → done records when it
+// was reached.
+type Encounter struct {
+	gorm.Model
+	VN             string `json:"vn" gorm:"uniqueIndex;not null;size:50"`
+	PatientID      uint   `json:"patient_id" gorm:"not null;index"`
+	DepartmentID   uint   `json:"department_id" gorm:"not null;index"`
+	Status         string `json:"status" gorm:"not null;size:20"`
+	ChiefComplaint string `json:"chief_complaint" gorm:"size:500"`
+	RegisteredBy   uint   `json:"registered_by" gorm:"not null"`
+
+	RegisteredAt          time.Time  `json:"registered_at" gorm:"not null"`
+	TriagedAt             *time.Time `json:"triaged_at"`
+	ConsultationStartedAt *time.Time `json:"consultation_started_at"`
+	CompletedAt           *time.Time `json:"completed_at"`
+}
+
+// SetStatus moves the encounter to status and records when it was reached
+func (e *Encounter) SetStatus(status string, at time.Time) {
+	e.Status = status
+	switch status {
+	case EncounterStatusRegistered:
+		e.RegisteredAt = at
+	case EncounterStatusTriaged:
+		e.TriagedAt = &at
+	case EncounterStatusInConsultation:
+		e.ConsultationStartedAt = &at
+	case EncounterStatusDone:
+		e.CompletedAt = &at
+	}
+}
+
+// EncounterCreateRequest is the body of POST /encounters
+type EncounterCreateRequest struct {
+	PatientID      uint   `json:"patient_id" binding:"required"`
+	DepartmentID   uint   `json:"department_id" binding:"required"`
+	ChiefComplaint string `json:"chief_complaint" binding:"max=500"`
+}
+
+// EncounterStatusRequest is the body of POST /encounters/:id/status; Status must be the next
+// step of the workflow
+type EncounterStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=triaged in_consultation done"`
+}
+
+// EncounterQueueRequest holds the query string of GET /encounters/queue
+type EncounterQueueRequest struct {
+	DepartmentID uint `form:"department_id" binding:"required"`
+	// Status narrows the queue to one waiting status, such as triaged for the consultation room
+	Status string `form:"status" binding:"omitempty,oneof=registered triaged in_consultation"`
+}
+
+// EncounterQueueEntry is a waiting encounter with the patient details shown on a queue screen
+type EncounterQueueEntry struct {
+	// Position is the 1-based place in the queue
+	Position int `json:"position" gorm:"-"`
+	Encounter
+	PatientHN   string `json:"patient_hn"`
+	FirstNameTH string `json:"first_name_th"`
+	LastNameTH  string `json:"last_name_th"`
+}
+
+// EncounterQueueFilter selects the waiting encounters of a department registered in [From, To)
+type EncounterQueueFilter struct {
+	DepartmentID uint
+	// Statuses defaults to every status before done
+	Statuses []string
+	From     time.Time
+	To       time.Time
+}
+
+// EncounterRepository interface - encounters are stored per tenant schema
+type EncounterRepository interface {
+	// Create issues the VN from issuer and inserts the encounter in one transaction, with the audit event
+	Create(encounter *Encounter, issuer *HNIssuer, event *AuditEvent, schemaName string) error
+	GetByID(id uint, schemaName string) (*Encounter, error)
+	// ListByPatient returns the encounters of a patient, newest first
+	ListByPatient(patientID uint, schemaName string) ([]Encounter, error)
+	// UpdateStatus saves the status change only while the encounter still has fromStatus,
+	// failing with ErrPreconditionFailed when another request changed it first
+	UpdateStatus(encounter *Encounter, fromStatus string, event *AuditEvent, schemaName string) error
+	// Queue returns the matching encounters in registration order
+	Queue(filter *EncounterQueueFilter, schemaName string) ([]EncounterQueueEntry, error)
+}
+
+// EncounterService interface - visits of live patients, audited like the patient record
+type EncounterService interface {
+	// Create registers a visit of a patient to an active department
+	Create(req *EncounterCreateRequest, actor *Actor, schemaName string) (*Encounter, error)
+	GetByID(id uint, actor *Actor, schemaName string) (*Encounter, error)
+	ListByPatient(patientID uint, actor *Actor, schemaName string) ([]Encounter, error)
+	// UpdateStatus advances the encounter one workflow step; it fails with
+	// ErrInvalidStatusTransition when the requested status is not the next step
+	UpdateStatus(id uint, req *EncounterStatusRequest, actor *Actor, schemaName string) (*Encounter, error)
+	// Queue returns today's waiting encounters of a department, in Thai time, first come first
+	Queue(req *EncounterQueueRequest, actor *Actor, schemaName string) ([]EncounterQueueEntry, error)
+}
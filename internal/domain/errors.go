@@ -33,6 +33,30 @@ var (
 
 	// ErrDatabaseConnection is returned when database connection fails
 	ErrDatabaseConnection = errors.New("database connection error")
+
+	// ErrInvalidCredentials is returned when username or password is wrong
+	ErrInvalidCredentials = errors.New("invalid credentials")
+
+	// ErrPossibleDuplicate is returned when a new patient looks like an already registered one
+	ErrPossibleDuplicate = errors.New("possible duplicate patient")
+
+	// ErrPreconditionFailed is returned when a record has changed since the version the client read
+	ErrPreconditionFailed = errors.New("record has been modified since it was read")
+
+	// ErrInvalidToken is returned when a refresh token is unknown, expired or already used
+	ErrInvalidToken = errors.New("invalid or expired token")
+
+	// ErrUnacknowledgedAlerts is returned when a medication order raises alerts the prescriber has not acknowledged
+	ErrUnacknowledgedAlerts = errors.New("medication order has unacknowledged alerts")
+
+	// ErrInsufficientStock is returned when a location does not hold enough usable stock to dispense an order
+	ErrInsufficientStock = errors.New("insufficient stock")
+
+	// ErrEligibilityUnavailable is returned when the payer eligibility service cannot be reached
+	ErrEligibilityUnavailable = errors.New("eligibility service unavailable")
+
+	// ErrAnalyzerUnavailable is returned when the results of lab analyzers cannot be read
+	ErrAnalyzerUnavailable = errors.New("analyzer results unavailable")
 )
 
 // IsNotFoundError checks if the error is a not found error
@@ -0,0 +1,490 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultHNTemplate reproduces the original HOSPITALCODE-00000001 format
+	DefaultHNTemplate = "{HOSP}-{SEQ:8}"
+	// MaxHNTemplateLength bounds the stored template
+	MaxHNTemplateLength = 100
+	// MaxHNLength is the size of the patient_hn column
+	MaxHNLength = 50
+	// MaxHNSequenceWidth keeps every sequence within a uint64
+	MaxHNSequenceWidth = 12
+	// BuddhistEraOffset converts a Gregorian year to the Thai Buddhist era
+	BuddhistEraOffset = 543
+)
+
+// ErrHNSequenceExhausted is returned when a period has issued every number its sequence width allows
+var ErrHNSequenceExhausted = errors.New("HN sequence exhausted")
+
+// HNTimeZone decides the period an HN is issued in; Thailand observes no daylight saving
+var HNTimeZone = time.FixedZone("ICT", 7*60*60)
+
+// HN template tokens. Anything outside braces is copied literally.
+const (
+	HNTokenHospitalCode = "HOSP"
+	HNTokenBEYear       = "BE_YYYY"
+	HNTokenBEYearShort  = "BE_YY"
+	HNTokenCEYear       = "CE_YYYY"
+	HNTokenCEYearShort  = "CE_YY"
+	HNTokenMonth        = "MM"
+	HNTokenDay          = "DD"
+	HNTokenSequence     = "SEQ"
+	HNTokenCheckDigit   = "CHECK"
+)
+
+// HN sequence reset periods, derived from the date tokens of a template
+const (
+	HNResetNever   = "never"
+	HNResetYearly  = "yearly"
+	HNResetMonthly = "monthly"
+	HNResetDaily   = "daily"
+)
+
+var hnLiteralPattern = regexp.MustCompile(`^[A-Za-z0-9./_-]*$`)
+
+type hnSegment struct {
+	token   string
+	literal string
+	width   int
+}
+
+// HNTemplate is a parsed HN format such as {BE_YY}-{SEQ:6}{CHECK}. {SEQ:n} is the running
+// number zero padded to n digits, {CHECK} a Luhn check digit over every digit before it.
+// The sequence restarts each Buddhist-era year when the template has a year token, each
+// month when it also has {MM} and each day when it also has {DD}.
+type HNTemplate struct {
+	source   string
+	segments []hnSegment
+	seqWidth int
+	reset    string
+}
+
+// ParseHNTemplate validates a template; every error wraps ErrInvalidInput
+func ParseHNTemplate(source string) (*HNTemplate, error) {
+	if source == "" || len(source) > MaxHNTemplateLength {
+		return nil, fmt.Errorf("%w: HN template must be 1-%d characters", ErrInvalidInput, MaxHNTemplateLength)
+	}
+
+	t := &HNTemplate{source: source, reset: HNResetNever}
+	var hasYear, hasMonth, hasDay, hasCheck bool
+	rest := source
+	for rest != "" {
+		open := strings.IndexByte(rest, '{')
+		if open != 0 {
+			literal := rest
+			if open > 0 {
+				literal = rest[:open]
+			}
+			if !hnLiteralPattern.MatchString(literal) {
+				return nil, fmt.Errorf("%w: HN template text may only contain letters, digits and . / _ -", ErrInvalidInput)
+			}
+			t.segments = append(t.segments, hnSegment{literal: literal})
+			rest = rest[len(literal):]
+			continue
+		}
+
+		end := strings.IndexByte(rest, '}')
+		if end < 0 {
+			return nil, fmt.Errorf("%w: unclosed token in HN template", ErrInvalidInput)
+		}
+		token := rest[1:end]
+		rest = rest[end+1:]
+
+		if hasCheck {
+			return nil, fmt.Errorf("%w: {%s} must be the last token of the HN template", ErrInvalidInput, HNTokenCheckDigit)
+		}
+
+		switch token {
+		case HNTokenHospitalCode:
+		case HNTokenBEYear, HNTokenBEYearShort, HNTokenCEYear, HNTokenCEYearShort:
+			if hasYear {
+				return nil, fmt.Errorf("%w: HN template may have only one year token", ErrInvalidInput)
+			}
+			hasYear = true
+		case HNTokenMonth:
+			if hasMonth {
+				return nil, fmt.Errorf("%w: HN template may have only one month token", ErrInvalidInput)
+			}
+			hasMonth = true
+		case HNTokenDay:
+			if hasDay {
+				return nil, fmt.Errorf("%w: HN template may have only one day token", ErrInvalidInput)
+			}
+			hasDay = true
+		case HNTokenCheckDigit:
+			if t.seqWidth == 0 {
+				return nil, fmt.Errorf("%w: {%s} must follow the sequence", ErrInvalidInput, HNTokenCheckDigit)
+			}
+			hasCheck = true
+		default:
+			width, ok := parseSequenceToken(token)
+			if !ok {
+				return nil, fmt.Errorf("%w: unknown HN template token {%s}", ErrInvalidInput, token)
+			}
+			if t.seqWidth != 0 {
+				return nil, fmt.Errorf("%w: HN template may have only one sequence", ErrInvalidInput)
+			}
+			t.seqWidth = width
+			token = HNTokenSequence
+			t.segments = append(t.segments, hnSegment{token: token, width: width})
+			continue
+		}
+		t.segments = append(t.segments, hnSegment{token: token})
+	}
+
+	if t.seqWidth == 0 {
+		return nil, fmt.Errorf("%w: HN template needs a {%s:n} sequence", ErrInvalidInput, HNTokenSequence)
+	}
+	if hasMonth && !hasYear {
+		return nil, fmt.Errorf("%w: {%s} needs a year token, otherwise months of different years collide", ErrInvalidInput, HNTokenMonth)
+	}
+	if hasDay && !hasMonth {
+		return nil, fmt.Errorf("%w: {%s} needs a month token, otherwise days of different months collide", ErrInvalidInput, HNTokenDay)
+	}
+	switch {
+	case hasDay:
+		t.reset = HNResetDaily
+	case hasMonth:
+		t.reset = HNResetMonthly
+	case hasYear:
+		t.reset = HNResetYearly
+	}
+	if t.Length(HospitalCodeLength) > MaxHNLength {
+		return nil, fmt.Errorf("%w: HN template produces HNs longer than %d characters", ErrInvalidInput, MaxHNLength)
+	}
+	return t, nil
+}
+
+// parseSequenceToken reads SEQ:n
+func parseSequenceToken(token string) (int, bool) {
+	widthText, ok := strings.CutPrefix(token, HNTokenSequence+":")
+	if !ok {
+		return 0, false
+	}
+	width, err := strconv.Atoi(widthText)
+	if err != nil || width < 1 || width > MaxHNSequenceWidth {
+		return 0, false
+	}
+	return width, true
+}
+
+// String returns the template as written
+func (t *HNTemplate) String() string {
+	return t.source
+}
+
+// Reset returns how often the sequence restarts: never, yearly, monthly or daily
+func (t *HNTemplate) Reset() string {
+	return t.reset
+}
+
+// Length returns the length of every HN the template produces for a hospital code of codeLength
+func (t *HNTemplate) Length(codeLength int) int {
+	length := 0
+	for _, segment := range t.segments {
+		switch segment.token {
+		case "":
+			length += len(segment.literal)
+		case HNTokenHospitalCode:
+			length += codeLength
+		case HNTokenBEYear, HNTokenCEYear:
+			length += 4
+		case HNTokenBEYearShort, HNTokenCEYearShort, HNTokenMonth, HNTokenDay:
+			length += 2
+		case HNTokenSequence:
+			length += segment.width
+		case HNTokenCheckDigit:
+			length++
+		}
+	}
+	return length
+}
+
+// Period returns the counter an HN issued at the given time draws from: "" for a template
+// that never resets, otherwise the Buddhist-era year ("2569"), year and month ("2569-03")
+// or year, month and day ("2569-03-15")
+func (t *HNTemplate) Period(at time.Time) string {
+	at = at.In(HNTimeZone)
+	return hnPeriod(t.reset, at.Year()+BuddhistEraOffset, int(at.Month()), at.Day())
+}
+
+func hnPeriod(reset string, beYear int, month int, day int) string {
+	switch reset {
+	case HNResetYearly:
+		return fmt.Sprintf("%04d", beYear)
+	case HNResetMonthly:
+		return fmt.Sprintf("%04d-%02d", beYear, month)
+	case HNResetDaily:
+		return fmt.Sprintf("%04d-%02d-%02d", beYear, month, day)
+	default:
+		return ""
+	}
+}
+
+// MaxSequence is the largest running number that fits the sequence width
+func (t *HNTemplate) MaxSequence() uint64 {
+	limit := uint64(1)
+	for i := 0; i < t.seqWidth; i++ {
+		limit *= 10
+	}
+	return limit - 1
+}
+
+// Format renders the HN with running number seq issued at the given time
+func (t *HNTemplate) Format(hospitalCode string, at time.Time, seq uint64) (string, error) {
+	if seq == 0 || seq > t.MaxSequence() {
+		return "", fmt.Errorf("%w: HN sequence %d does not fit {%s:%d}", ErrHNSequenceExhausted, seq, HNTokenSequence, t.seqWidth)
+	}
+
+	at = at.In(HNTimeZone)
+	var hn strings.Builder
+	for _, segment := range t.segments {
+		switch segment.token {
+		case "":
+			hn.WriteString(segment.literal)
+		case HNTokenHospitalCode:
+			hn.WriteString(hospitalCode)
+		case HNTokenBEYear:
+			fmt.Fprintf(&hn, "%04d", at.Year()+BuddhistEraOffset)
+		case HNTokenBEYearShort:
+			fmt.Fprintf(&hn, "%02d", (at.Year()+BuddhistEraOffset)%100)
+		case HNTokenCEYear:
+			fmt.Fprintf(&hn, "%04d", at.Year())
+		case HNTokenCEYearShort:
+			fmt.Fprintf(&hn, "%02d", at.Year()%100)
+		case HNTokenMonth:
+			fmt.Fprintf(&hn, "%02d", int(at.Month()))
+		case HNTokenDay:
+			fmt.Fprintf(&hn, "%02d", at.Day())
+		case HNTokenSequence:
+			fmt.Fprintf(&hn, "%0*d", segment.width, seq)
+		case HNTokenCheckDigit:
+			hn.WriteByte(LuhnCheckDigit(hn.String()))
+		}
+	}
+	return hn.String(), nil
+}
+
+// Pattern returns an anchored regular expression matching every HN the template can produce
+// for hospitalCode. It is valid both in Go and in PostgreSQL.
+func (t *HNTemplate) Pattern(hospitalCode string) string {
+	var pattern strings.Builder
+	pattern.WriteString("^")
+	for _, segment := range t.segments {
+		switch segment.token {
+		case "":
+			pattern.WriteString(regexp.QuoteMeta(segment.literal))
+		case HNTokenHospitalCode:
+			pattern.WriteString(regexp.QuoteMeta(hospitalCode))
+		case HNTokenBEYear, HNTokenCEYear:
+			pattern.WriteString("[0-9]{4}")
+		case HNTokenBEYearShort, HNTokenCEYearShort, HNTokenMonth, HNTokenDay:
+			pattern.WriteString("[0-9]{2}")
+		case HNTokenSequence:
+			fmt.Fprintf(&pattern, "[0-9]{%d}", segment.width)
+		case HNTokenCheckDigit:
+			pattern.WriteString("[0-9]")
+		}
+	}
+	pattern.WriteString("$")
+	return pattern.String()
+}
+
+// Parse reads the period and running number back from an HN the template produced at
+// or after the given time; two-digit years are read in the century of that time.
+// ok is false when the template cannot produce hn.
+func (t *HNTemplate) Parse(hn string, hospitalCode string, now time.Time) (period string, seq uint64, ok bool) {
+	now = now.In(HNTimeZone)
+	nowBE := now.Year() + BuddhistEraOffset
+	// Without {DD} the day stays 1, so every month renders again without rolling over
+	beYear, month, day := nowBE, int(now.Month()), 1
+
+	rest := hn
+	for _, segment := range t.segments {
+		var width int
+		switch segment.token {
+		case "", HNTokenHospitalCode:
+			text := segment.literal
+			if segment.token == HNTokenHospitalCode {
+				text = hospitalCode
+			}
+			if !strings.HasPrefix(rest, text) {
+				return "", 0, false
+			}
+			rest = rest[len(text):]
+			continue
+		case HNTokenBEYear, HNTokenCEYear:
+			width = 4
+		case HNTokenSequence:
+			width = segment.width
+		case HNTokenCheckDigit:
+			width = 1
+		default:
+			width = 2
+		}
+		if len(rest) < width {
+			return "", 0, false
+		}
+		value, err := strconv.ParseUint(rest[:width], 10, 64)
+		if err != nil {
+			return "", 0, false
+		}
+		rest = rest[width:]
+
+		switch segment.token {
+		case HNTokenBEYear:
+			beYear = int(value)
+		case HNTokenCEYear:
+			beYear = int(value) + BuddhistEraOffset
+		case HNTokenBEYearShort:
+			beYear = nowBE - nowBE%100 + int(value)
+		case HNTokenCEYearShort:
+			ceYear := nowBE - BuddhistEraOffset
+			beYear = ceYear - ceYear%100 + int(value) + BuddhistEraOffset
+		case HNTokenMonth:
+			month = int(value)
+		case HNTokenDay:
+			day = int(value)
+		case HNTokenSequence:
+			seq = value
+		}
+	}
+	if rest != "" || seq == 0 || month < 1 || month > 12 || day < 1 || day > 31 {
+		return "", 0, false
+	}
+
+	// Rendering again rejects a wrong check digit and days past the end of the month
+	issued := time.Date(beYear-BuddhistEraOffset, time.Month(month), day, 0, 0, 0, 0, HNTimeZone)
+	if formatted, err := t.Format(hospitalCode, issued, seq); err != nil || formatted != hn {
+		return "", 0, false
+	}
+	return hnPeriod(t.reset, beYear, month, day), seq, true
+}
+
+// LuhnCheckDigit computes the Luhn (mod 10) check digit over the digits of s; other characters are skipped
+func LuhnCheckDigit(s string) byte {
+	sum := 0
+	double := true
+	for i := len(s) - 1; i >= 0; i-- {
+		if s[i] < '0' || s[i] > '9' {
+			continue
+		}
+		digit := int(s[i] - '0')
+		if double {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+		double = !double
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+// HNIssuer issues the HN of a new patient inside its registration transaction
+type HNIssuer struct {
+	Template     *HNTemplate
+	HospitalCode string
+	// LegacyRunning is the tenant's HNRunning, the running number kept before HN templates
+	LegacyRunning uint64
+}
+
+// NewHNIssuer reads the HN template and hospital code of a tenant
+func NewHNIssuer(tenant *Tenant) (*HNIssuer, error) {
+	template, err := ParseHNTemplate(tenant.HNTemplate)
+	if err != nil {
+		return nil, err
+	}
+	return &HNIssuer{Template: template, HospitalCode: tenant.HospitalCode, LegacyRunning: tenant.HNRunning}, nil
+}
+
+// Seed is the value a counter that does not exist yet starts after. The never-resetting
+// counter continues from LegacyRunning so upgraded tenants do not reissue HNs.
+func (i *HNIssuer) Seed(period string) uint64 {
+	if period == "" {
+		return i.LegacyRunning
+	}
+	return 0
+}
+
+// HNCounter is the last running number issued in a period, stored per tenant schema.
+// Period is "" for templates that never reset.
+type HNCounter struct {
+	Period    string    `json:"period" gorm:"primaryKey;size:20"`
+	LastValue uint64    `json:"last_value" gorm:"not null;default:0"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// HNCollisionError is returned when a template would issue HNs that already exist
+type HNCollisionError struct {
+	// Collisions lists existing HNs the template would issue again
+	Collisions []string
+}
+
+func (e *HNCollisionError) Error() string {
+	return fmt.Sprintf("%s: HN template would reissue %d existing HN(s)", ErrDuplicateEntry, len(e.Collisions))
+}
+
+func (e *HNCollisionError) Unwrap() error {
+	return ErrDuplicateEntry
+}
+
+// HNSettings describes the HN template of a tenant and where its sequence stands
+type HNSettings struct {
+	Template string `json:"template"`
+	Reset    string `json:"reset"`
+	// Period is the counter the next HN draws from, empty when the sequence never resets
+	Period       string `json:"period"`
+	LastSequence uint64 `json:"last_sequence"`
+	NextHN       string `json:"next_hn"`
+}
+
+// HNTemplateRequest holds the body of PUT /settings/hn-template
+type HNTemplateRequest struct {
+	Template string `json:"template" binding:"required,max=100"`
+}
+
+// HNPreviewRequest holds the body of POST /settings/hn-template/preview.
+// An empty template previews the current one.
+type HNPreviewRequest struct {
+	Template string `json:"template" binding:"max=100"`
+	Count    int    `json:"count" binding:"omitempty,min=1,max=20"`
+}
+
+// HNPreview lists the next HNs a template would issue without allocating them
+type HNPreview struct {
+	Template string   `json:"template"`
+	Reset    string   `json:"reset"`
+	Period   string   `json:"period"`
+	Next     []string `json:"next"`
+	// Collisions lists existing HNs the template would issue again; saving it is rejected
+	Collisions []string `json:"collisions"`
+}
+
+// HNCounterRepository interface - counters and HN lookups in the tenant schema.
+// Counters are incremented by PatientRepository.Create, in the registration transaction.
+type HNCounterRepository interface {
+	// Current returns the last value issued in period, 0 when none was
+	Current(period string, schemaName string) (uint64, error)
+	// FindHNs returns the HNs of live and deleted patients matching pattern
+	FindHNs(pattern string, schemaName string) ([]string, error)
+}
+
+// HNService interface - HN template settings of a tenant
+type HNService interface {
+	GetSettings(schemaName string) (*HNSettings, error)
+	Preview(req *HNPreviewRequest, schemaName string) (*HNPreview, error)
+	// UpdateTemplate saves a new template; it fails with HNCollisionError when the
+	// template would issue an HN that already exists
+	UpdateTemplate(req *HNTemplateRequest, schemaName string) (*HNSettings, error)
+}
@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	// IdempotencyKeyTTL is how long a key replays its response; an expired key can be used again
+	IdempotencyKeyTTL = 24 * time.Hour
+	// MaxIdempotencyKeyLength bounds client supplied keys, a UUID is recommended
+	MaxIdempotencyKeyLength = 255
+)
+
+var (
+	// ErrIdempotencyKeyReused is returned when a key is sent again with a different request
+	ErrIdempotencyKeyReused = errors.New("idempotency key was used with a different request")
+
+	// ErrIdempotencyKeyInUse is returned while the first request with a key is still being processed
+	ErrIdempotencyKeyInUse = errors.New("a request with this idempotency key is in progress")
+)
+
+// IdempotencyKey stores the outcome of a create request sent with an Idempotency-Key header,
+// so a retry returns the original response instead of creating the record again.
+// RequestHash covers the method, path, staff member and body of the request.
+type IdempotencyKey struct {
+	Key         string    `gorm:"primaryKey;size:255"`
+	CreatedAt   time.Time `gorm:"not null"`
+	StaffID     uint      `gorm:"not null"`
+	RequestHash string    `gorm:"not null;size:64"`
+	// StatusCode is 0 while the first request is being processed
+	StatusCode int
+	// ResponseBody and ResponseHeaders (JSON encoded) are replayed as sent the first time
+	ResponseBody    string `gorm:"type:text"`
+	ResponseHeaders string `gorm:"type:text"`
+}
+
+// IsCompleted reports whether the response of the first request has been stored
+func (k *IdempotencyKey) IsCompleted() bool {
+	return k.StatusCode != 0
+}
+
+// IdempotencyRepository interface - keys are stored per tenant schema
+type IdempotencyRepository interface {
+	// Reserve inserts record unless the key is already held by a record created after
+	// expiredBefore. It returns the holding record, or nil once record is reserved.
+	Reserve(record *IdempotencyKey, expiredBefore time.Time, schemaName string) (*IdempotencyKey, error)
+	// Complete stores the response of a reserved key
+	Complete(record *IdempotencyKey, schemaName string) error
+	// Release frees a key whose request did not complete, so it can be retried
+	Release(key string, schemaName string) error
+}
+
+// IdempotencyService interface - used by the idempotency middleware of create endpoints
+type IdempotencyService interface {
+	// Begin reserves the key of record for a new request and returns nil, or returns the
+	// completed earlier request to replay. It fails with ErrIdempotencyKeyReused when the key
+	// belongs to a different request and with ErrIdempotencyKeyInUse while it is processed.
+	Begin(record *IdempotencyKey, schemaName string) (*IdempotencyKey, error)
+	Complete(record *IdempotencyKey, schemaName string) error
+	Release(record *IdempotencyKey, schemaName string) error
+}
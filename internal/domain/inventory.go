@@ -0,0 +1,397 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// DefaultNearExpiryDays is how far ahead the stock alerts look for expiring lots when no window is given
+	DefaultNearExpiryDays = 90
+	// MaxNearExpiryDays caps the within_days parameter of the stock alerts
+	MaxNearExpiryDays = 730
+)
+
+// Stock movement types. Receipts add stock, dispensing takes it and adjustments correct it
+// either way after a count or write off damaged and expired stock.
+const (
+	StockMovementReceipt    = "receipt"
+	StockMovementDispense   = "dispense"
+	StockMovementAdjustment = "adjustment"
+)
+
+// StockLocation is a place pharmacy stock is kept, such as the main store or the outpatient
+// dispensary. Like departments, a location that closes is deactivated rather than deleted.
+type StockLocation struct {
+	gorm.Model
+	Code     string `json:"code" gorm:"uniqueIndex:idx_stock_locations_code_live,where:deleted_at IS NULL;not null;size:20"`
+	Name     string `json:"name" gorm:"not null;size:255"`
+	IsActive bool   `json:"is_active" gorm:"not null"`
+}
+
+// StockLocationRequest is the body of POST and PUT /pharmacy/locations
+type StockLocationRequest struct {
+	Code string `json:"code" binding:"required,max=20,alphanum"`
+	Name string `json:"name" binding:"required,max=255"`
+	// IsActive defaults to true when omitted
+	IsActive *bool `json:"is_active"`
+}
+
+// StockReorderLevel is the on-hand quantity of a drug at a location at or below which a low-stock
+// alert is raised
+type StockReorderLevel struct {
+	ID           uint      `json:"id" gorm:"primarykey"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	LocationID   uint      `json:"location_id" gorm:"not null;uniqueIndex:idx_stock_reorder_levels_location_drug"`
+	DrugID       uint      `json:"drug_id" gorm:"not null;uniqueIndex:idx_stock_reorder_levels_location_drug"`
+	ReorderLevel float64   `json:"reorder_level" gorm:"not null"`
+}
+
+// StockReorderLevelRequest is the body of PUT /pharmacy/locations/:id/reorder-levels.
+// A reorder level of 0 turns the low-stock alert off.
+type StockReorderLevelRequest struct {
+	DrugID       uint     `json:"drug_id" binding:"required"`
+	ReorderLevel *float64 `json:"reorder_level" binding:"required,min=0"`
+}
+
+// StockLot is a lot of a drug held at a location. Quantity is the balance on hand, kept in step
+// with the ledger: it always equals the sum of the lot's stock movements.
+type StockLot struct {
+	ID         uint      `json:"id" gorm:"primarykey"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	LocationID uint      `json:"location_id" gorm:"not null;uniqueIndex:idx_stock_lots_location_drug_lot"`
+	DrugID     uint      `json:"drug_id" gorm:"not null;uniqueIndex:idx_stock_lots_location_drug_lot"`
+	LotNumber  string    `json:"lot_number" gorm:"not null;size:50;uniqueIndex:idx_stock_lots_location_drug_lot"`
+	ExpiryDate time.Time `json:"expiry_date" gorm:"type:date;not null"`
+	Quantity   float64   `json:"quantity" gorm:"not null"`
+}
+
+// IsExpired reports whether the lot expired before today. A lot can still be used on its expiry date.
+func (l *StockLot) IsExpired(today time.Time) bool {
+	return l.ExpiryDate.Before(today)
+}
+
+// StockPick is the quantity taken from one lot when dispensing
+type StockPick struct {
+	Lot      *StockLot
+	Quantity float64
+}
+
+// PickFEFO picks quantity from the lots first-expiry-first-out, breaking ties by the oldest lot.
+// Empty lots and lots that expired before today are skipped. It fails with ErrInsufficientStock
+// when the usable lots hold less than quantity.
+func PickFEFO(lots []StockLot, quantity float64, today time.Time) ([]StockPick, error) {
+	usable := make([]*StockLot, 0, len(lots))
+	var available float64
+	for i := range lots {
+		if lots[i].Quantity <= 0 || lots[i].IsExpired(today) {
+			continue
+		}
+		usable = append(usable, &lots[i])
+		available += lots[i].Quantity
+	}
+	if available < quantity {
+		return nil, fmt.Errorf("%w: %g available, %g needed", ErrInsufficientStock, available, quantity)
+	}
+	sort.SliceStable(usable, func(i, j int) bool {
+		if !usable[i].ExpiryDate.Equal(usable[j].ExpiryDate) {
+			return usable[i].ExpiryDate.Before(usable[j].ExpiryDate)
+		}
+		return usable[i].ID < usable[j].ID
+	})
+
+	var picks []StockPick
+	remaining := quantity
+	for _, lot := range usable {
+		if remaining <= 0 {
+			break
+		}
+		take := lot.Quantity
+		if take > remaining {
+			take = remaining
+		}
+		picks = append(picks, StockPick{Lot: lot, Quantity: take})
+		remaining -= take
+	}
+	return picks, nil
+}
+
+// StockLotFilter selects lots for the stock on hand
+type StockLotFilter struct {
+	LocationID *uint
+	DrugID     *uint
+	// InStockOnly leaves out lots with nothing on hand
+	InStockOnly bool
+}
+
+// StockListRequest holds the query string of GET /pharmacy/stock
+type StockListRequest struct {
+	LocationID uint `form:"location_id"`
+	DrugID     uint `form:"drug_id"`
+}
+
+// StockMovement is an entry of the append-only stock ledger. Quantity is positive for stock
+// coming in and negative for stock going out; LotBalance is the balance of the lot after the
+// movement. Receipts refer to their goods-received note and dispensing to the medication order.
+type StockMovement struct {
+	ID                uint      `json:"id" gorm:"primarykey"`
+	CreatedAt         time.Time `json:"created_at" gorm:"not null"`
+	LocationID        uint      `json:"location_id" gorm:"not null"`
+	DrugID            uint      `json:"drug_id" gorm:"not null"`
+	LotID             uint      `json:"lot_id" gorm:"not null;index"`
+	LotNumber         string    `json:"lot_number" gorm:"not null;size:50"`
+	Type              string    `json:"type" gorm:"not null;size:20"`
+	Quantity          float64   `json:"quantity" gorm:"not null"`
+	LotBalance        float64   `json:"lot_balance" gorm:"not null"`
+	GoodsReceiptID    *uint     `json:"goods_receipt_id"`
+	MedicationOrderID *uint     `json:"medication_order_id"`
+	Reason            string    `json:"reason" gorm:"size:255"`
+	PerformedBy       uint      `json:"performed_by" gorm:"not null"`
+}
+
+// StockMovementFilter selects ledger entries for a stock card
+type StockMovementFilter struct {
+	LocationID uint
+	DrugID     uint
+	From       *time.Time
+	To         *time.Time
+}
+
+// GoodsReceipt is a goods-received note: a delivery of one or more lots into a location
+type GoodsReceipt struct {
+	ID         uint      `json:"id" gorm:"primarykey"`
+	CreatedAt  time.Time `json:"created_at"`
+	LocationID uint      `json:"location_id" gorm:"not null;index"`
+	Supplier   string    `json:"supplier" gorm:"not null;size:255"`
+	// ReferenceNumber is the supplier's invoice or delivery note number
+	ReferenceNumber string             `json:"reference_number" gorm:"not null;size:50"`
+	Note            string             `json:"note" gorm:"size:500"`
+	ReceivedBy      uint               `json:"received_by" gorm:"not null"`
+	ReceivedAt      time.Time          `json:"received_at" gorm:"not null"`
+	Items           []GoodsReceiptItem `json:"items" gorm:"foreignKey:GoodsReceiptID"`
+}
+
+// GoodsReceiptItem is a lot received on a goods-received note
+type GoodsReceiptItem struct {
+	ID             uint      `json:"id" gorm:"primarykey"`
+	GoodsReceiptID uint      `json:"goods_receipt_id" gorm:"not null;index"`
+	DrugID         uint      `json:"drug_id" gorm:"not null"`
+	LotID          uint      `json:"lot_id" gorm:"not null"`
+	LotNumber      string    `json:"lot_number" gorm:"not null;size:50"`
+	ExpiryDate     time.Time `json:"expiry_date" gorm:"type:date;not null"`
+	Quantity       float64   `json:"quantity" gorm:"not null"`
+	UnitCost       *float64  `json:"unit_cost"`
+}
+
+// GoodsReceiptRequest is the body of POST /pharmacy/receipts
+type GoodsReceiptRequest struct {
+	LocationID      uint                      `json:"location_id" binding:"required"`
+	Supplier        string                    `json:"supplier" binding:"required,max=255"`
+	ReferenceNumber string                    `json:"reference_number" binding:"required,max=50"`
+	Note            string                    `json:"note" binding:"max=500"`
+	Items           []GoodsReceiptItemRequest `json:"items" binding:"required,min=1,max=100,dive"`
+}
+
+// GoodsReceiptItemRequest is a line of a goods-received note; ExpiryDate is in YYYY-MM-DD format
+type GoodsReceiptItemRequest struct {
+	DrugID     uint     `json:"drug_id" binding:"required"`
+	LotNumber  string   `json:"lot_number" binding:"required,max=50"`
+	ExpiryDate string   `json:"expiry_date" binding:"required"`
+	Quantity   float64  `json:"quantity" binding:"required,gt=0"`
+	UnitCost   *float64 `json:"unit_cost" binding:"omitempty,min=0"`
+}
+
+// GoodsReceiptFilter selects goods-received notes, newest first
+type GoodsReceiptFilter struct {
+	LocationID *uint
+	From       *time.Time
+	To         *time.Time
+}
+
+// GoodsReceiptListRequest holds the query string of GET /pharmacy/receipts; from and to are
+// inclusive dates in YYYY-MM-DD format
+type GoodsReceiptListRequest struct {
+	LocationID uint   `form:"location_id"`
+	From       string `form:"from"`
+	To         string `form:"to"`
+}
+
+// StockAdjustmentRequest is the body of POST /pharmacy/adjustments. Quantity is added to the lot,
+// so write-offs are negative.
+type StockAdjustmentRequest struct {
+	LotID    uint    `json:"lot_id" binding:"required"`
+	Quantity float64 `json:"quantity" binding:"required"`
+	Reason   string  `json:"reason" binding:"required,max=255"`
+}
+
+// StockCardRequest holds the query string of GET /pharmacy/stock-card; from and to are
+// inclusive dates in YYYY-MM-DD format
+type StockCardRequest struct {
+	LocationID uint   `form:"location_id" binding:"required"`
+	DrugID     uint   `form:"drug_id" binding:"required"`
+	From       string `form:"from"`
+	To         string `form:"to"`
+}
+
+// StockCardEntry is a ledger entry with the balance of the drug at the location after it
+type StockCardEntry struct {
+	StockMovement
+	Balance float64 `json:"balance"`
+}
+
+// StockCard lists the movements of a drug at a location over a period with a running balance
+type StockCard struct {
+	LocationID     uint             `json:"location_id"`
+	DrugID         uint             `json:"drug_id"`
+	OpeningBalance float64          `json:"opening_balance"`
+	Received       float64          `json:"received"`
+	Dispensed      float64          `json:"dispensed"`
+	Adjusted       float64          `json:"adjusted"`
+	ClosingBalance float64          `json:"closing_balance"`
+	Entries        []StockCardEntry `json:"entries"`
+}
+
+// NewStockCard builds the stock card of a drug at a location from the balance before the period
+// and the movements of the period in ledger order. Dispensed is reported as a positive quantity.
+func NewStockCard(locationID uint, drugID uint, openingBalance float64, movements []StockMovement) *StockCard {
+	card := &StockCard{
+		LocationID:     locationID,
+		DrugID:         drugID,
+		OpeningBalance: openingBalance,
+		Entries:        make([]StockCardEntry, 0, len(movements)),
+	}
+	balance := openingBalance
+	for _, movement := range movements {
+		balance += movement.Quantity
+		switch movement.Type {
+		case StockMovementReceipt:
+			card.Received += movement.Quantity
+		case StockMovementDispense:
+			card.Dispensed -= movement.Quantity
+		case StockMovementAdjustment:
+			card.Adjusted += movement.Quantity
+		}
+		card.Entries = append(card.Entries, StockCardEntry{StockMovement: movement, Balance: balance})
+	}
+	card.ClosingBalance = balance
+	return card
+}
+
+// StockAlertRequest holds the query string of GET /pharmacy/alerts
+type StockAlertRequest struct {
+	LocationID uint `form:"location_id"`
+	// WithinDays is how far ahead to look for expiring lots, DefaultNearExpiryDays when omitted
+	WithinDays int `form:"within_days" binding:"omitempty,min=1,max=730"`
+}
+
+// LowStockAlert is a drug whose usable stock at a location is at or below its reorder level.
+// Expired lots do not count towards the stock on hand.
+type LowStockAlert struct {
+	LocationID     uint    `json:"location_id"`
+	LocationCode   string  `json:"location_code"`
+	DrugID         uint    `json:"drug_id"`
+	DrugCode       string  `json:"drug_code"`
+	DrugName       string  `json:"drug_name"`
+	DispensingUnit string  `json:"dispensing_unit"`
+	OnHand         float64 `json:"on_hand"`
+	ReorderLevel   float64 `json:"reorder_level"`
+}
+
+// NearExpiryAlert is a lot with stock on hand that has expired or expires within the window
+type NearExpiryAlert struct {
+	LotID        uint      `json:"lot_id"`
+	LocationID   uint      `json:"location_id"`
+	LocationCode string    `json:"location_code"`
+	DrugID       uint      `json:"drug_id"`
+	DrugCode     string    `json:"drug_code"`
+	DrugName     string    `json:"drug_name"`
+	LotNumber    string    `json:"lot_number"`
+	ExpiryDate   time.Time `json:"expiry_date"`
+	Quantity     float64   `json:"quantity"`
+	DaysToExpiry int       `json:"days_to_expiry"`
+	Expired      bool      `json:"expired"`
+}
+
+// StockAlerts are the low-stock and near-expiry alerts of the pharmacy
+type StockAlerts struct {
+	LowStock   []LowStockAlert   `json:"low_stock"`
+	NearExpiry []NearExpiryAlert `json:"near_expiry"`
+}
+
+// StockRebuildResult reports how many lot balances did not match the ledger and were corrected
+type StockRebuildResult struct {
+	CorrectedLots int64 `json:"corrected_lots"`
+}
+
+// InventoryRepository interface - pharmacy stock is stored per tenant schema. Every method that
+// changes a lot balance appends the matching ledger entries in the same transaction.
+type InventoryRepository interface {
+	// ListLocations returns the locations ordered by code, only active ones unless includeInactive is set
+	ListLocations(includeInactive bool, schemaName string) ([]StockLocation, error)
+	GetLocation(id uint, schemaName string) (*StockLocation, error)
+	CreateLocation(location *StockLocation, schemaName string) error
+	UpdateLocation(location *StockLocation, schemaName string) error
+
+	ListReorderLevels(locationID uint, schemaName string) ([]StockReorderLevel, error)
+	// SaveReorderLevel inserts the reorder level or replaces the one of the same location and drug
+	SaveReorderLevel(level *StockReorderLevel, schemaName string) error
+
+	// ListLots returns lots ordered by drug and expiry date
+	ListLots(filter *StockLotFilter, schemaName string) ([]StockLot, error)
+	GetLot(id uint, schemaName string) (*StockLot, error)
+
+	// Receive saves a goods-received note, adding each item to its lot and creating the lot
+	// when the location has not held it before. It fails with ErrInvalidInput when a lot is
+	// already held with another expiry date.
+	Receive(receipt *GoodsReceipt, schemaName string) error
+	GetReceipt(id uint, schemaName string) (*GoodsReceipt, error)
+	ListReceipts(filter *GoodsReceiptFilter, schemaName string) ([]GoodsReceipt, error)
+
+	// Adjust adds the movement's quantity to its lot, failing with ErrInvalidInput when the
+	// balance would fall below zero
+	Adjust(movement *StockMovement, schemaName string) error
+
+	// DispenseOrder takes the order's quantity from the lots of the location first-expiry-first-out
+	// and saves the status change, only while the order still has fromStatus. It fails with
+	// ErrInsufficientStock when the usable lots hold too little and with ErrPreconditionFailed when
+	// another request changed the order first.
+	DispenseOrder(order *MedicationOrder, fromStatus string, locationID uint, today time.Time, event *AuditEvent, schemaName string) ([]StockMovement, error)
+
+	// ListMovements returns ledger entries in the order they were written
+	ListMovements(filter *StockMovementFilter, schemaName string) ([]StockMovement, error)
+	// Balance returns the ledger balance of a drug at a location before a time
+	Balance(locationID uint, drugID uint, before time.Time, schemaName string) (float64, error)
+
+	LowStock(locationID *uint, today time.Time, schemaName string) ([]LowStockAlert, error)
+	// NearExpiry returns lots with stock on hand expiring before a date, earliest first
+	NearExpiry(locationID *uint, before time.Time, schemaName string) ([]NearExpiryAlert, error)
+
+	// RebuildBalances recomputes every lot balance from the ledger and returns how many changed
+	RebuildBalances(schemaName string) (int64, error)
+}
+
+// InventoryService interface - pharmacy stock locations, lots, receipts and the stock ledger
+type InventoryService interface {
+	ListLocations(includeInactive bool, schemaName string) ([]StockLocation, error)
+	// CreateLocation and UpdateLocation fail with ErrDuplicateEntry when another location has the code
+	CreateLocation(req *StockLocationRequest, schemaName string) (*StockLocation, error)
+	UpdateLocation(id uint, req *StockLocationRequest, schemaName string) (*StockLocation, error)
+	ListReorderLevels(locationID uint, schemaName string) ([]StockReorderLevel, error)
+	SetReorderLevel(locationID uint, req *StockReorderLevelRequest, schemaName string) (*StockReorderLevel, error)
+
+	// ListStock returns the lots with stock on hand
+	ListStock(req *StockListRequest, schemaName string) ([]StockLot, error)
+	Receive(req *GoodsReceiptRequest, actor *Actor, schemaName string) (*GoodsReceipt, error)
+	GetReceipt(id uint, schemaName string) (*GoodsReceipt, error)
+	ListReceipts(req *GoodsReceiptListRequest, schemaName string) ([]GoodsReceipt, error)
+	Adjust(req *StockAdjustmentRequest, actor *Actor, schemaName string) (*StockMovement, error)
+
+	StockCard(req *StockCardRequest, schemaName string) (*StockCard, error)
+	Alerts(req *StockAlertRequest, schemaName string) (*StockAlerts, error)
+	RebuildBalances(schemaName string) (*StockRebuildResult, error)
+}
@@ -0,0 +1,765 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrLabBarcodeExhausted is returned when a day has issued every specimen barcode its sequence allows
+var ErrLabBarcodeExhausted = errors.New("specimen barcode sequence exhausted")
+
+// Specimen types a lab test is run on
+const (
+	LabSpecimenBlood  = "blood"
+	LabSpecimenSerum  = "serum"
+	LabSpecimenPlasma = "plasma"
+	LabSpecimenUrine  = "urine"
+	LabSpecimenStool  = "stool"
+	LabSpecimenCSF    = "csf"
+	LabSpecimenSwab   = "swab"
+	LabSpecimenSputum = "sputum"
+	LabSpecimenOther  = "other"
+)
+
+// Result types of a lab test. Numeric results are flagged against the reference range of the
+// patient's sex and age, text results against the expected NormalText of the test.
+const (
+	LabResultTypeNumeric = "numeric"
+	LabResultTypeText    = "text"
+)
+
+// Lab order priorities
+const (
+	LabPriorityRoutine = "routine"
+	LabPriorityUrgent  = "urgent"
+	LabPriorityStat    = "stat"
+)
+
+// Lab order statuses. The status follows the specimens and results of the order: it is
+// collected once every specimen is collected, received once every specimen has reached the lab
+// and completed once every test has a result. Orders can be cancelled until a specimen has been
+// received.
+const (
+	LabOrderStatusOrdered   = "ordered"
+	LabOrderStatusCollected = "collected"
+	LabOrderStatusReceived  = "received"
+	LabOrderStatusCompleted = "completed"
+	LabOrderStatusCancelled = "cancelled"
+)
+
+// Specimen statuses. A rejected specimen, for example a haemolysed sample, is collected again
+// under the same barcode.
+const (
+	LabSpecimenStatusPending   = "pending"
+	LabSpecimenStatusCollected = "collected"
+	LabSpecimenStatusReceived  = "received"
+	LabSpecimenStatusRejected  = "rejected"
+)
+
+// labSpecimenTransitions lists the statuses each specimen status can move to
+var labSpecimenTransitions = map[string][]string{
+	LabSpecimenStatusPending:   {LabSpecimenStatusCollected},
+	LabSpecimenStatusCollected: {LabSpecimenStatusReceived, LabSpecimenStatusRejected},
+	LabSpecimenStatusReceived:  {LabSpecimenStatusRejected},
+	LabSpecimenStatusRejected:  {LabSpecimenStatusCollected},
+}
+
+// CanMoveLabSpecimen reports whether a specimen with status from can move to status to
+func CanMoveLabSpecimen(from string, to string) bool {
+	for _, next := range labSpecimenTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Lab order item statuses. A result recorded again replaces the previous one and marks the
+// item corrected.
+const (
+	LabItemStatusPending   = "pending"
+	LabItemStatusResulted  = "resulted"
+	LabItemStatusCorrected = "corrected"
+)
+
+// Abnormal flags of a lab result, using the HL7 interpretation codes
+const (
+	LabFlagNormal       = "N"
+	LabFlagLow          = "L"
+	LabFlagHigh         = "H"
+	LabFlagCriticalLow  = "LL"
+	LabFlagCriticalHigh = "HH"
+	LabFlagAbnormal     = "A"
+)
+
+// Sources of a lab result
+const (
+	LabResultSourceManual   = "manual"
+	LabResultSourceAnalyzer = "analyzer"
+)
+
+const (
+	// labBarcodePeriodFormat is the day prefix of a specimen barcode, yymmdd in Thai time
+	labBarcodePeriodFormat = "060102"
+	// labBarcodeSeqWidth is the number of digits of the daily sequence of a specimen barcode
+	labBarcodeSeqWidth = 5
+	// MaxLabResultValueLength caps the length of a reported result value
+	MaxLabResultValueLength = 100
+)
+
+// LabReferenceRange is the reference range of a numeric test for a sex and an age band. Sex is
+// M or F, or empty for both; the band runs from AgeMinYears up to but excluding AgeMaxYears,
+// without an upper end when AgeMaxYears is nil. Either limit of the range or of the critical
+// range may be left open.
+type LabReferenceRange struct {
+	Sex          string   `json:"sex,omitempty" binding:"omitempty,oneof=M F"`
+	AgeMinYears  int      `json:"age_min_years" binding:"min=0,max=150"`
+	AgeMaxYears  *int     `json:"age_max_years,omitempty" binding:"omitempty,min=1,max=150"`
+	Low          *float64 `json:"low,omitempty"`
+	High         *float64 `json:"high,omitempty"`
+	CriticalLow  *float64 `json:"critical_low,omitempty"`
+	CriticalHigh *float64 `json:"critical_high,omitempty"`
+}
+
+// Applies reports whether the range is meant for a patient of sex aged ageYears
+func (r *LabReferenceRange) Applies(sex Gender, ageYears int) bool {
+	if r.Sex != "" && r.Sex != string(sex) {
+		return false
+	}
+	if ageYears < r.AgeMinYears {
+		return false
+	}
+	return r.AgeMaxYears == nil || ageYears < *r.AgeMaxYears
+}
+
+// Validate checks that the limits are in order and the age band is not empty
+func (r *LabReferenceRange) Validate() error {
+	if r.AgeMaxYears != nil && *r.AgeMaxYears <= r.AgeMinYears {
+		return fmt.Errorf("%w: age_max_years must be greater than age_min_years", ErrInvalidInput)
+	}
+	if r.Low == nil && r.High == nil {
+		return fmt.Errorf("%w: a reference range needs low or high", ErrInvalidInput)
+	}
+	if r.Low != nil && r.High != nil && *r.Low > *r.High {
+		return fmt.Errorf("%w: low must not be above high", ErrInvalidInput)
+	}
+	if r.CriticalLow != nil && r.Low != nil && *r.CriticalLow > *r.Low {
+		return fmt.Errorf("%w: critical_low must not be above low", ErrInvalidInput)
+	}
+	if r.CriticalHigh != nil && r.High != nil && *r.CriticalHigh < *r.High {
+		return fmt.Errorf("%w: critical_high must not be below high", ErrInvalidInput)
+	}
+	return nil
+}
+
+// Flag returns the abnormal flag of value, critical limits first
+func (r *LabReferenceRange) Flag(value float64) string {
+	switch {
+	case r.CriticalLow != nil && value < *r.CriticalLow:
+		return LabFlagCriticalLow
+	case r.CriticalHigh != nil && value > *r.CriticalHigh:
+		return LabFlagCriticalHigh
+	case r.Low != nil && value < *r.Low:
+		return LabFlagLow
+	case r.High != nil && value > *r.High:
+		return LabFlagHigh
+	}
+	return LabFlagNormal
+}
+
+// Text formats the range as printed on a report, e.g. 3.5-5.0, < 200 or > 40
+func (r *LabReferenceRange) Text() string {
+	switch {
+	case r.Low != nil && r.High != nil:
+		return fmt.Sprintf("%g-%g", *r.Low, *r.High)
+	case r.High != nil:
+		return fmt.Sprintf("< %g", *r.High)
+	case r.Low != nil:
+		return fmt.Sprintf("> %g", *r.Low)
+	}
+	return ""
+}
+
+// LabReferenceRanges holds the reference ranges of a test. It is stored as JSONB.
+type LabReferenceRanges []LabReferenceRange
+
+// Scan implements the sql.Scanner interface with proper nil and type handling
+func (r *LabReferenceRanges) Scan(value interface{}) error {
+	if value == nil {
+		*r = nil
+		return nil
+	}
+
+	var raw []byte
+	switch v := value.(type) {
+	case string:
+		raw = []byte(v)
+	case []byte:
+		raw = v
+	default:
+		return fmt.Errorf("unsupported type for LabReferenceRanges: %T", value)
+	}
+	return json.Unmarshal(raw, r)
+}
+
+func (r LabReferenceRanges) Value() (driver.Value, error) {
+	if r == nil {
+		return nil, nil
+	}
+	encoded, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+	return string(encoded), nil
+}
+
+// LabTest is an entry of the lab test catalogue of a tenant. Tests that are no longer offered
+// are deactivated rather than deleted so their orders keep their test.
+type LabTest struct {
+	gorm.Model
+	Code         string `json:"code" gorm:"uniqueIndex:idx_lab_tests_code_live,where:deleted_at IS NULL;not null;size:20"`
+	Name         string `json:"name" gorm:"not null;size:255"`
+	SpecimenType string `json:"specimen_type" gorm:"not null;size:20"`
+	ResultType   string `json:"result_type" gorm:"not null;size:10"`
+	Unit         string `json:"unit" gorm:"size:20"`
+	// NormalText is the expected value of a text result, such as negative; other values are
+	// flagged abnormal. Text tests without it are not flagged.
+	NormalText      string             `json:"normal_text" gorm:"size:100"`
+	ReferenceRanges LabReferenceRanges `json:"reference_ranges" gorm:"type:jsonb"`
+	IsActive        bool               `json:"is_active" gorm:"not null"`
+}
+
+// ReferenceRange returns the first range meant for a patient of sex aged ageYears, nil when none is
+func (t *LabTest) ReferenceRange(sex Gender, ageYears int) *LabReferenceRange {
+	for i := range t.ReferenceRanges {
+		if t.ReferenceRanges[i].Applies(sex, ageYears) {
+			return &t.ReferenceRanges[i]
+		}
+	}
+	return nil
+}
+
+// LabTestRequest is the body of POST and PUT /lab/tests
+type LabTestRequest struct {
+	Code            string              `json:"code" binding:"required,max=20,alphanum"`
+	Name            string              `json:"name" binding:"required,max=255"`
+	SpecimenType    string              `json:"specimen_type" binding:"required,oneof=blood serum plasma urine stool csf swab sputum other"`
+	ResultType      string              `json:"result_type" binding:"required,oneof=numeric text"`
+	Unit            string              `json:"unit" binding:"max=20"`
+	NormalText      string              `json:"normal_text" binding:"max=100"`
+	ReferenceRanges []LabReferenceRange `json:"reference_ranges" binding:"max=20,dive"`
+	// IsActive defaults to true when omitted
+	IsActive *bool `json:"is_active"`
+}
+
+// LabTestListRequest holds the query string of GET /lab/tests
+type LabTestListRequest struct {
+	// Query matches the code or name
+	Query           string `form:"q"`
+	IncludeInactive bool   `form:"include_inactive"`
+}
+
+// LabOrder is a request for lab tests during an encounter. Its tests are grouped into one
+// specimen per specimen type, each with its own barcode.
+type LabOrder struct {
+	gorm.Model
+	EncounterID  uint   `json:"encounter_id" gorm:"not null;index"`
+	PatientID    uint   `json:"patient_id" gorm:"not null;index"`
+	Priority     string `json:"priority" gorm:"not null;size:10"`
+	ClinicalNote string `json:"clinical_note" gorm:"size:500"`
+	Status       string `json:"status" gorm:"not null;size:20"`
+
+	OrderedBy    uint       `json:"ordered_by" gorm:"not null"`
+	OrderedAt    time.Time  `json:"ordered_at" gorm:"not null"`
+	CompletedAt  *time.Time `json:"completed_at"`
+	CancelledBy  *uint      `json:"cancelled_by"`
+	CancelledAt  *time.Time `json:"cancelled_at"`
+	CancelReason string     `json:"cancel_reason" gorm:"size:255"`
+
+	Specimens []LabSpecimen  `json:"specimens" gorm:"foreignKey:OrderID"`
+	Items     []LabOrderItem `json:"items" gorm:"foreignKey:OrderID"`
+}
+
+// RefreshStatus derives the status of an order that is not cancelled from its specimens and
+// results. A rejected specimen takes the order back to ordered until it is collected again.
+func (o *LabOrder) RefreshStatus(at time.Time) {
+	if o.Status == LabOrderStatusCancelled {
+		return
+	}
+
+	resulted := len(o.Items) > 0
+	for i := range o.Items {
+		if o.Items[i].Status == LabItemStatusPending {
+			resulted = false
+			break
+		}
+	}
+	if resulted {
+		o.Status = LabOrderStatusCompleted
+		if o.CompletedAt == nil {
+			o.CompletedAt = &at
+		}
+		return
+	}
+	o.CompletedAt = nil
+
+	collected, received := true, true
+	for i := range o.Specimens {
+		switch o.Specimens[i].Status {
+		case LabSpecimenStatusReceived:
+		case LabSpecimenStatusCollected:
+			received = false
+		default:
+			collected, received = false, false
+		}
+	}
+	switch {
+	case received:
+		o.Status = LabOrderStatusReceived
+	case collected:
+		o.Status = LabOrderStatusCollected
+	default:
+		o.Status = LabOrderStatusOrdered
+	}
+}
+
+// Cancellable reports whether the order can still be cancelled: no specimen is held by the lab
+// and no test has a result
+func (o *LabOrder) Cancellable() bool {
+	if o.Status == LabOrderStatusCancelled || o.Status == LabOrderStatusCompleted {
+		return false
+	}
+	for i := range o.Specimens {
+		if o.Specimens[i].Status == LabSpecimenStatusReceived {
+			return false
+		}
+	}
+	for i := range o.Items {
+		if o.Items[i].Status != LabItemStatusPending {
+			return false
+		}
+	}
+	return true
+}
+
+// SpecimenResulted reports whether a test run on the specimen already has a result
+func (o *LabOrder) SpecimenResulted(specimenID uint) bool {
+	for i := range o.Items {
+		if o.Items[i].SpecimenID == specimenID && o.Items[i].Status != LabItemStatusPending {
+			return true
+		}
+	}
+	return false
+}
+
+// Cancel cancels the order and records who cancelled it and why
+func (o *LabOrder) Cancel(staffID uint, reason string, at time.Time) {
+	o.Status = LabOrderStatusCancelled
+	o.CancelledBy, o.CancelledAt = &staffID, &at
+	o.CancelReason = reason
+}
+
+// Specimen returns the specimen of the order with the barcode, nil when there is none
+func (o *LabOrder) Specimen(barcode string) *LabSpecimen {
+	for i := range o.Specimens {
+		if o.Specimens[i].Barcode == barcode {
+			return &o.Specimens[i]
+		}
+	}
+	return nil
+}
+
+// Item returns the item of the order with the ID, nil when there is none
+func (o *LabOrder) Item(id uint) *LabOrderItem {
+	for i := range o.Items {
+		if o.Items[i].ID == id {
+			return &o.Items[i]
+		}
+	}
+	return nil
+}
+
+// ItemByTestCode returns the item of the specimen for the test code, ignoring case
+func (o *LabOrder) ItemByTestCode(specimenID uint, testCode string) *LabOrderItem {
+	for i := range o.Items {
+		if o.Items[i].SpecimenID == specimenID && strings.EqualFold(o.Items[i].TestCode, testCode) {
+			return &o.Items[i]
+		}
+	}
+	return nil
+}
+
+// LabSpecimen is a sample taken for the tests of an order that share its specimen type. The
+// barcode is issued when the order is placed and printed on the tube label.
+type LabSpecimen struct {
+	ID           uint       `json:"id" gorm:"primarykey"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+	OrderID      uint       `json:"order_id" gorm:"not null;index"`
+	Barcode      string     `json:"barcode" gorm:"not null;uniqueIndex;size:20"`
+	SpecimenType string     `json:"specimen_type" gorm:"not null;size:20"`
+	Status       string     `json:"status" gorm:"not null;size:20"`
+	CollectedBy  *uint      `json:"collected_by"`
+	CollectedAt  *time.Time `json:"collected_at"`
+	ReceivedBy   *uint      `json:"received_by"`
+	ReceivedAt   *time.Time `json:"received_at"`
+	RejectedBy   *uint      `json:"rejected_by"`
+	RejectedAt   *time.Time `json:"rejected_at"`
+	RejectReason string     `json:"reject_reason" gorm:"size:255"`
+}
+
+// SetStatus moves the specimen to status and records who moved it and when. Collecting a
+// rejected specimen again clears its earlier receipt.
+func (s *LabSpecimen) SetStatus(status string, staffID uint, at time.Time) {
+	s.Status = status
+	switch status {
+	case LabSpecimenStatusCollected:
+		s.CollectedBy, s.CollectedAt = &staffID, &at
+		s.ReceivedBy, s.ReceivedAt = nil, nil
+	case LabSpecimenStatusReceived:
+		s.ReceivedBy, s.ReceivedAt = &staffID, &at
+	case LabSpecimenStatusRejected:
+		s.RejectedBy, s.RejectedAt = &staffID, &at
+	}
+}
+
+// LabSpecimenBarcodePeriod is the day a specimen barcode is counted in, yymmdd in Thai time
+func LabSpecimenBarcodePeriod(at time.Time) string {
+	return at.In(HNTimeZone).Format(labBarcodePeriodFormat)
+}
+
+// FormatLabSpecimenBarcode builds the barcode of the seq-th specimen of the period, the period
+// followed by a five-digit sequence, e.g. 25031500012. Barcodes are digits only so they print
+// compactly in Code 128.
+func FormatLabSpecimenBarcode(period string, seq uint64) (string, error) {
+	barcode := period + fmt.Sprintf("%0*d", labBarcodeSeqWidth, seq)
+	if len(barcode) != len(labBarcodePeriodFormat)+labBarcodeSeqWidth {
+		return "", fmt.Errorf("%w: sequence %d of %s does not fit %d digits", ErrLabBarcodeExhausted, seq, period, labBarcodeSeqWidth)
+	}
+	return barcode, nil
+}
+
+// LabOrderItem is one test of an order with its result. The test code, name, result type and
+// unit are copied from the catalogue when ordering; the reference range is copied when the
+// result is recorded so the report shows the range the flag was set against.
+type LabOrderItem struct {
+	ID           uint      `json:"id" gorm:"primarykey"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	OrderID      uint      `json:"order_id" gorm:"not null;index"`
+	SpecimenID   uint      `json:"specimen_id" gorm:"not null;index"`
+	TestID       uint      `json:"test_id" gorm:"not null"`
+	TestCode     string    `json:"test_code" gorm:"not null;size:20"`
+	TestName     string    `json:"test_name" gorm:"not null;size:255"`
+	SpecimenType string    `json:"specimen_type" gorm:"not null;size:20"`
+	ResultType   string    `json:"result_type" gorm:"not null;size:10"`
+	Unit         string    `json:"unit" gorm:"size:20"`
+	Status       string    `json:"status" gorm:"not null;size:20"`
+
+	Value          string     `json:"value" gorm:"size:100"`
+	NumericValue   *float64   `json:"numeric_value"`
+	ReferenceRange string     `json:"reference_range" gorm:"size:100"`
+	Flag           string     `json:"flag" gorm:"size:2"`
+	ResultSource   string     `json:"result_source" gorm:"size:20"`
+	Instrument     string     `json:"instrument" gorm:"size:100"`
+	ResultedBy     *uint      `json:"resulted_by"`
+	ResultedAt     *time.Time `json:"resulted_at"`
+}
+
+// SetResult records value as the result of the item, flagged against the reference range of
+// the test for a patient of sex aged ageYears. Numeric results must be numbers.
+func (i *LabOrderItem) SetResult(test *LabTest, value string, sex Gender, ageYears int, at time.Time) error {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return fmt.Errorf("%w: result of %s is empty", ErrInvalidInput, i.TestCode)
+	}
+	if len(value) > MaxLabResultValueLength {
+		return fmt.Errorf("%w: result of %s is longer than %d characters", ErrInvalidInput, i.TestCode, MaxLabResultValueLength)
+	}
+
+	var numeric *float64
+	var referenceRange, flag string
+	if test.ResultType == LabResultTypeNumeric {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("%w: result of %s must be a number, got %q", ErrInvalidInput, i.TestCode, value)
+		}
+		numeric = &parsed
+		if r := test.ReferenceRange(sex, ageYears); r != nil {
+			referenceRange = r.Text()
+			flag = r.Flag(parsed)
+		}
+	} else if test.NormalText != "" {
+		referenceRange = test.NormalText
+		flag = LabFlagNormal
+		if !strings.EqualFold(value, test.NormalText) {
+			flag = LabFlagAbnormal
+		}
+	}
+
+	if i.Status == LabItemStatusPending {
+		i.Status = LabItemStatusResulted
+	} else {
+		i.Status = LabItemStatusCorrected
+	}
+	i.Value = value
+	i.NumericValue = numeric
+	i.ReferenceRange = referenceRange
+	i.Flag = flag
+	i.ResultedAt = &at
+	return nil
+}
+
+// IsAbnormal reports whether the result is flagged outside its reference
+func (i *LabOrderItem) IsAbnormal() bool {
+	return i.Flag != "" && i.Flag != LabFlagNormal
+}
+
+// LabOrderRequest is the body of POST /encounters/:id/lab-orders
+type LabOrderRequest struct {
+	TestIDs      []uint `json:"test_ids" binding:"required,min=1,max=30,dive,required"`
+	Priority     string `json:"priority" binding:"omitempty,oneof=routine urgent stat"`
+	ClinicalNote string `json:"clinical_note" binding:"max=500"`
+}
+
+// LabOrderListRequest holds the query string of GET /patient/:id/lab-orders
+type LabOrderListRequest struct {
+	Status string `form:"status" binding:"omitempty,oneof=ordered collected received completed cancelled"`
+}
+
+// LabCancelRequest is the body of POST /lab-orders/:id/cancel
+type LabCancelRequest struct {
+	Reason string `json:"reason" binding:"required,max=255"`
+}
+
+// LabSpecimenRejectRequest is the body of POST /lab/specimens/:barcode/reject
+type LabSpecimenRejectRequest struct {
+	Reason string `json:"reason" binding:"required,max=255"`
+}
+
+// LabResultRequest is the body of POST /lab-orders/:id/results
+type LabResultRequest struct {
+	Results []LabResultEntry `json:"results" binding:"required,min=1,max=100,dive"`
+}
+
+// LabResultEntry is the result of one item of an order
+type LabResultEntry struct {
+	ItemID uint   `json:"item_id" binding:"required"`
+	Value  string `json:"value" binding:"required,max=100"`
+	// Unit is checked against the unit of the test when given
+	Unit string `json:"unit" binding:"max=20"`
+}
+
+// LabSpecimenListRequest holds the query string of GET /lab/specimens
+type LabSpecimenListRequest struct {
+	Status string `form:"status" binding:"omitempty,oneof=pending collected received rejected"`
+	// Date is the day the specimens were ordered, today when omitted
+	Date string `form:"date"`
+}
+
+// LabSpecimenFilter selects the specimens of the worklist ordered in [From, To)
+type LabSpecimenFilter struct {
+	Status string
+	From   time.Time
+	To     time.Time
+}
+
+// LabSpecimenEntry is a specimen of the worklist with the patient and priority of its order
+type LabSpecimenEntry struct {
+	LabSpecimen
+	PatientID   uint   `json:"patient_id"`
+	PatientHN   string `json:"patient_hn"`
+	FirstNameTH string `json:"first_name_th"`
+	LastNameTH  string `json:"last_name_th"`
+	Priority    string `json:"priority"`
+	// Tests lists the codes of the tests run on the specimen
+	Tests string `json:"tests"`
+}
+
+// LabSpecimenLabel is the content of the barcode label stuck on a specimen tube. ZPL renders it
+// for Zebra-compatible label printers.
+type LabSpecimenLabel struct {
+	Barcode      string    `json:"barcode"`
+	SpecimenType string    `json:"specimen_type"`
+	PatientHN    string    `json:"patient_hn"`
+	PatientName  string    `json:"patient_name"`
+	DateOfBirth  string    `json:"date_of_birth"`
+	Sex          string    `json:"sex"`
+	Priority     string    `json:"priority"`
+	Tests        []string  `json:"tests"`
+	OrderedAt    time.Time `json:"ordered_at"`
+	ZPL          string    `json:"zpl"`
+}
+
+// NewLabSpecimenLabel builds the label of a specimen of order for patient. The English name is
+// printed when recorded, since label printer fonts seldom carry Thai glyphs.
+func NewLabSpecimenLabel(specimen *LabSpecimen, order *LabOrder, patient *Patient) *LabSpecimenLabel {
+	name := strings.TrimSpace(patient.FirstNameEN + " " + patient.LastNameEN)
+	if name == "" {
+		name = strings.TrimSpace(patient.FirstNameTH + " " + patient.LastNameTH)
+	}
+	label := &LabSpecimenLabel{
+		Barcode:      specimen.Barcode,
+		SpecimenType: specimen.SpecimenType,
+		PatientHN:    patient.PatientHN,
+		PatientName:  name,
+		DateOfBirth:  patient.DateOfBirth.Format(DateFormat),
+		Sex:          string(patient.Gender),
+		Priority:     order.Priority,
+		Tests:        []string{},
+		OrderedAt:    order.OrderedAt,
+	}
+	for i := range order.Items {
+		if order.Items[i].SpecimenID == specimen.ID {
+			label.Tests = append(label.Tests, order.Items[i].TestCode)
+		}
+	}
+	label.ZPL = label.renderZPL()
+	return label
+}
+
+// renderZPL lays the label out for a 50 x 25 mm tube label at 203 dpi with a Code 128 barcode
+func (l *LabSpecimenLabel) renderZPL() string {
+	clean := strings.NewReplacer("^", " ", "~", " ")
+	priority := ""
+	if l.Priority != LabPriorityRoutine {
+		priority = strings.ToUpper(l.Priority) + " "
+	}
+
+	var b strings.Builder
+	b.WriteString("^XA^CI28\n")
+	fmt.Fprintf(&b, "^FO20,15^A0N,26,26^FD%s^FS\n", clean.Replace(l.PatientName))
+	fmt.Fprintf(&b, "^FO20,45^A0N,22,22^FDHN %s  DOB %s  %s^FS\n", clean.Replace(l.PatientHN), l.DateOfBirth, l.Sex)
+	fmt.Fprintf(&b, "^FO20,75^BY2^BCN,70,Y,N,N^FD%s^FS\n", l.Barcode)
+	fmt.Fprintf(&b, "^FO20,175^A0N,22,22^FD%s%s %s^FS\n", priority, l.SpecimenType, clean.Replace(strings.Join(l.Tests, " ")))
+	b.WriteString("^XZ\n")
+	return b.String()
+}
+
+// AnalyzerResult is one result read from an analyzer, matched to an order by the specimen
+// barcode and the test code
+type AnalyzerResult struct {
+	// Line is the position of the result in its batch, for reporting rejected rows
+	Line       int
+	Barcode    string
+	TestCode   string
+	Value      string
+	Unit       string
+	ResultedAt *time.Time
+}
+
+// AnalyzerBatch is a set of results an analyzer delivered together, such as one file
+type AnalyzerBatch struct {
+	ID         string
+	Instrument string
+	Results    []AnalyzerResult
+	// Err is set when the batch could not be read; it then has no results
+	Err error
+}
+
+// AnalyzerSource delivers results of laboratory analyzers. Fetch returns the batches waiting
+// for a tenant; each is handed back through Complete with its import report once its results
+// are saved, so a batch that failed to import is fetched again. Implementations live in
+// internal/infrastructure/analyzer.
+type AnalyzerSource interface {
+	Fetch(schemaName string) ([]AnalyzerBatch, error)
+	Complete(schemaName string, report *LabImportBatchReport) error
+}
+
+// LabImportRejection is an analyzer result that could not be recorded
+type LabImportRejection struct {
+	Line     int    `json:"line"`
+	Barcode  string `json:"barcode"`
+	TestCode string `json:"test_code"`
+	Reason   string `json:"reason"`
+}
+
+// LabImportBatchReport is the outcome of importing one analyzer batch
+type LabImportBatchReport struct {
+	BatchID    string               `json:"batch_id"`
+	Instrument string               `json:"instrument"`
+	Imported   int                  `json:"imported"`
+	Rejected   []LabImportRejection `json:"rejected"`
+	// Error is set when the batch could not be read at all
+	Error string `json:"error,omitempty"`
+}
+
+// LabImportSummary is the outcome of importing every batch an analyzer source had waiting
+type LabImportSummary struct {
+	Batches  []LabImportBatchReport `json:"batches"`
+	Imported int                    `json:"imported"`
+	Rejected int                    `json:"rejected"`
+}
+
+// LabRepository interface - the lab test catalogue and lab orders are stored per tenant schema
+type LabRepository interface {
+	// ListTests returns the tests ordered by code, only active ones unless includeInactive is set
+	ListTests(query string, includeInactive bool, schemaName string) ([]LabTest, error)
+	GetTest(id uint, schemaName string) (*LabTest, error)
+	// ListTestsByIDs returns the tests with the IDs, in no particular order
+	ListTestsByIDs(ids []uint, schemaName string) ([]LabTest, error)
+	CreateTest(test *LabTest, schemaName string) error
+	UpdateTest(test *LabTest, schemaName string) error
+
+	// GetOrder returns an order with its specimens and items
+	GetOrder(id uint, schemaName string) (*LabOrder, error)
+	// ListOrdersByEncounter returns the orders of an encounter in the order they were placed
+	ListOrdersByEncounter(encounterID uint, schemaName string) ([]LabOrder, error)
+	// ListOrdersByPatient returns the orders of a patient, newest first, narrowed to a status
+	// when status is not empty
+	ListOrdersByPatient(patientID uint, status string, schemaName string) ([]LabOrder, error)
+	// CreateOrder inserts the order with its specimens and items, issuing the specimen barcodes
+	// from the lab_specimen_counters row of the day, and appends the audit event
+	CreateOrder(order *LabOrder, event *AuditEvent, schemaName string) error
+	// SaveOrder saves the status of the order with the given specimens and items only while the
+	// order is unchanged since it was read at version (its UpdatedAt), failing with
+	// ErrPreconditionFailed when another request changed it first
+	SaveOrder(order *LabOrder, version time.Time, specimens []*LabSpecimen, items []*LabOrderItem, event *AuditEvent, schemaName string) error
+
+	GetSpecimenByBarcode(barcode string, schemaName string) (*LabSpecimen, error)
+	// ListSpecimens returns the worklist of specimens of live orders, urgent ones first
+	ListSpecimens(filter *LabSpecimenFilter, schemaName string) ([]LabSpecimenEntry, error)
+}
+
+// LabService interface - lab test catalogue, lab orders, specimens and results, audited like
+// the patient record
+type LabService interface {
+	ListTests(req *LabTestListRequest, schemaName string) ([]LabTest, error)
+	GetTest(id uint, schemaName string) (*LabTest, error)
+	// CreateTest and UpdateTest fail with ErrDuplicateEntry when another test has the code
+	CreateTest(req *LabTestRequest, schemaName string) (*LabTest, error)
+	UpdateTest(id uint, req *LabTestRequest, schemaName string) (*LabTest, error)
+
+	// Create orders active tests during an encounter that is not done, with one specimen per
+	// specimen type
+	Create(encounterID uint, req *LabOrderRequest, actor *Actor, schemaName string) (*LabOrder, error)
+	GetByID(id uint, actor *Actor, schemaName string) (*LabOrder, error)
+	ListByEncounter(encounterID uint, actor *Actor, schemaName string) ([]LabOrder, error)
+	ListByPatient(patientID uint, req *LabOrderListRequest, actor *Actor, schemaName string) ([]LabOrder, error)
+	// Cancel fails with ErrInvalidStatusTransition once a specimen of the order has been received
+	Cancel(id uint, req *LabCancelRequest, actor *Actor, schemaName string) (*LabOrder, error)
+
+	ListSpecimens(req *LabSpecimenListRequest, schemaName string) ([]LabSpecimenEntry, error)
+	// Label returns the barcode label of a specimen
+	Label(barcode string, actor *Actor, schemaName string) (*LabSpecimenLabel, error)
+	// Collect, Receive and Reject move a specimen and fail with ErrInvalidStatusTransition when
+	// it is not in a status they can move it from
+	Collect(barcode string, actor *Actor, schemaName string) (*LabOrder, error)
+	Receive(barcode string, actor *Actor, schemaName string) (*LabOrder, error)
+	Reject(barcode string, req *LabSpecimenRejectRequest, actor *Actor, schemaName string) (*LabOrder, error)
+
+	// RecordResults records results of the order typed in by lab staff; the specimen of each
+	// item must have been received
+	RecordResults(id uint, req *LabResultRequest, actor *Actor, schemaName string) (*LabOrder, error)
+	// ImportFromAnalyzer records the results waiting at the analyzer source. Results that do
+	// not match a received specimen and an ordered test are reported as rejected. It fails with
+	// ErrAnalyzerUnavailable when the source cannot be read.
+	ImportFromAnalyzer(actor *Actor, schemaName string) (*LabImportSummary, error)
+}
@@ -0,0 +1,284 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Medication order statuses. An order is verified by a pharmacist before it is dispensed and
+// can be cancelled until it has been dispensed.
+const (
+	MedicationStatusOrdered   = "ordered"
+	MedicationStatusVerified  = "verified"
+	MedicationStatusDispensed = "dispensed"
+	MedicationStatusCancelled = "cancelled"
+)
+
+// medicationStatusTransitions lists the statuses each status can move to
+var medicationStatusTransitions = map[string][]string{
+	MedicationStatusOrdered:  {MedicationStatusVerified, MedicationStatusCancelled},
+	MedicationStatusVerified: {MedicationStatusDispensed, MedicationStatusCancelled},
+}
+
+// CanMoveMedicationOrder reports whether an order with status from can move to status to
+func CanMoveMedicationOrder(from string, to string) bool {
+	for _, next := range medicationStatusTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Administration routes of a medication order
+const (
+	MedicationRouteOral       = "oral"
+	MedicationRouteSublingual = "sublingual"
+	MedicationRouteTopical    = "topical"
+	MedicationRouteInhaled    = "inhaled"
+	MedicationRouteNasal      = "nasal"
+	MedicationRouteOphthalmic = "ophthalmic"
+	MedicationRouteOtic       = "otic"
+	MedicationRouteRectal     = "rectal"
+	MedicationRouteVaginal    = "vaginal"
+	MedicationRouteIV         = "iv"
+	MedicationRouteIM         = "im"
+	MedicationRouteSC         = "sc"
+)
+
+// MedicationFrequencies maps the frequency codes of an order to the doses given per day.
+// stat (once, now) and prn (as needed) have no fixed daily count.
+var MedicationFrequencies = map[string]int{
+	"od":   1,
+	"bid":  2,
+	"tid":  3,
+	"qid":  4,
+	"q4h":  6,
+	"q6h":  4,
+	"q8h":  3,
+	"q12h": 2,
+	"hs":   1,
+	"stat": 0,
+	"prn":  0,
+}
+
+// MedicationFrequencyStat is a single dose given at once
+const MedicationFrequencyStat = "stat"
+
+// ComputeMedicationQuantity returns the number of dispensing units a course needs, rounded up.
+// It can only be computed when the dose is given in the dispensing unit of the drug and the
+// frequency has a fixed daily count or is stat.
+func ComputeMedicationQuantity(drug *Drug, dose float64, doseUnit string, frequency string, durationDays int) (float64, bool) {
+	if !strings.EqualFold(strings.TrimSpace(doseUnit), drug.DispensingUnit) {
+		return 0, false
+	}
+	if frequency == MedicationFrequencyStat {
+		return math.Ceil(dose), true
+	}
+	perDay := MedicationFrequencies[frequency]
+	if perDay == 0 {
+		return 0, false
+	}
+	return math.Ceil(dose * float64(perDay*durationDays)), true
+}
+
+// Medication alert types raised when an order is placed
+const (
+	MedicationAlertAllergy        = "allergy"
+	MedicationAlertDuplicateClass = "duplicate_class"
+	MedicationAlertInteraction    = "interaction"
+)
+
+// MedicationAlert is a warning raised against a new order. Severity is the allergy severity for
+// allergy alerts, the interaction severity for interactions and moderate for duplicate classes.
+type MedicationAlert struct {
+	Type     string `json:"type"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	// AllergyID is the recorded allergy the drug matches
+	AllergyID *uint `json:"allergy_id,omitempty"`
+	// RelatedOrderID is the active order the new one duplicates or interacts with
+	RelatedOrderID *uint `json:"related_order_id,omitempty"`
+}
+
+// MedicationAlerts holds the alerts an order was placed with. It is stored as JSONB.
+type MedicationAlerts []MedicationAlert
+
+// Scan implements the sql.Scanner interface with proper nil and type handling
+func (a *MedicationAlerts) Scan(value interface{}) error {
+	if value == nil {
+		*a = nil
+		return nil
+	}
+
+	var raw []byte
+	switch v := value.(type) {
+	case string:
+		raw = []byte(v)
+	case []byte:
+		raw = v
+	default:
+		return fmt.Errorf("unsupported type for MedicationAlerts: %T", value)
+	}
+	return json.Unmarshal(raw, a)
+}
+
+func (a MedicationAlerts) Value() (driver.Value, error) {
+	if a == nil {
+		return nil, nil
+	}
+	encoded, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+	return string(encoded), nil
+}
+
+// Messages returns the alert messages, for the audit trail
+func (a MedicationAlerts) Messages() []string {
+	messages := make([]string, len(a))
+	for i := range a {
+		messages[i] = a[i].Message
+	}
+	return messages
+}
+
+// MedicationAlertError carries the alerts of an order that was placed without acknowledging them
+type MedicationAlertError struct {
+	Alerts MedicationAlerts
+}
+
+func (e *MedicationAlertError) Error() string {
+	return fmt.Sprintf("%s: %d alerts", ErrUnacknowledgedAlerts, len(e.Alerts))
+}
+
+func (e *MedicationAlertError) Unwrap() error {
+	return ErrUnacknowledgedAlerts
+}
+
+// MedicationOrder is a prescription of one drug during an encounter. The drug name, class and
+// dispensing unit are copied from the drug master when ordering. The course runs from OrderedAt
+// to EndsAt; orders that are not cancelled and have not ended are active and are checked against
+// new orders. Alerts are the warnings the prescriber acknowledged when placing the order.
+type MedicationOrder struct {
+	gorm.Model
+	EncounterID      uint    `json:"encounter_id" gorm:"not null;index"`
+	PatientID        uint    `json:"patient_id" gorm:"not null;index"`
+	DrugID           uint    `json:"drug_id" gorm:"not null"`
+	DrugName         string  `json:"drug_name" gorm:"not null;size:310"`
+	TherapeuticClass string  `json:"therapeutic_class" gorm:"not null;size:100"`
+	Dose             float64 `json:"dose" gorm:"not null"`
+	DoseUnit         string  `json:"dose_unit" gorm:"not null;size:20"`
+	Route            string  `json:"route" gorm:"not null;size:20"`
+	Frequency        string  `json:"frequency" gorm:"not null;size:10"`
+	DurationDays     int     `json:"duration_days" gorm:"not null"`
+	Quantity         float64 `json:"quantity" gorm:"not null"`
+	DispensingUnit   string  `json:"dispensing_unit" gorm:"not null;size:20"`
+	Instruction      string  `json:"instruction" gorm:"size:500"`
+	Status           string  `json:"status" gorm:"not null;size:20"`
+
+	Alerts         MedicationAlerts `json:"alerts" gorm:"type:jsonb"`
+	OverrideReason string           `json:"override_reason" gorm:"size:500"`
+
+	OrderedBy    uint       `json:"ordered_by" gorm:"not null"`
+	OrderedAt    time.Time  `json:"ordered_at" gorm:"not null"`
+	EndsAt       time.Time  `json:"ends_at" gorm:"not null"`
+	VerifiedBy   *uint      `json:"verified_by"`
+	VerifiedAt   *time.Time `json:"verified_at"`
+	DispensedBy  *uint      `json:"dispensed_by"`
+	DispensedAt  *time.Time `json:"dispensed_at"`
+	CancelledBy  *uint      `json:"cancelled_by"`
+	CancelledAt  *time.Time `json:"cancelled_at"`
+	CancelReason string     `json:"cancel_reason" gorm:"size:255"`
+}
+
+// SetStatus moves the order to status and records who moved it and when
+func (o *MedicationOrder) SetStatus(status string, staffID uint, at time.Time) {
+	o.Status = status
+	switch status {
+	case MedicationStatusVerified:
+		o.VerifiedBy, o.VerifiedAt = &staffID, &at
+	case MedicationStatusDispensed:
+		o.DispensedBy, o.DispensedAt = &staffID, &at
+	case MedicationStatusCancelled:
+		o.CancelledBy, o.CancelledAt = &staffID, &at
+	}
+}
+
+// MedicationOrderRequest is the body of POST /encounters/:id/medications. Quantity is computed
+// from the dose, frequency and duration when omitted, if the dose is in the dispensing unit.
+type MedicationOrderRequest struct {
+	DrugID       uint     `json:"drug_id" binding:"required"`
+	Dose         float64  `json:"dose" binding:"required,gt=0"`
+	DoseUnit     string   `json:"dose_unit" binding:"required,max=20"`
+	Route        string   `json:"route" binding:"required,oneof=oral sublingual topical inhaled nasal ophthalmic otic rectal vaginal iv im sc"`
+	Frequency    string   `json:"frequency" binding:"required,oneof=od bid tid qid q4h q6h q8h q12h hs stat prn"`
+	DurationDays int      `json:"duration_days" binding:"required,min=1,max=365"`
+	Quantity     *float64 `json:"quantity" binding:"omitempty,gt=0"`
+	Instruction  string   `json:"instruction" binding:"max=500"`
+	// AcknowledgeAlerts places the order despite allergy, duplicate class and interaction alerts;
+	// OverrideReason is then required and the override is audited
+	AcknowledgeAlerts bool   `json:"acknowledge_alerts"`
+	OverrideReason    string `json:"override_reason" binding:"max=500"`
+}
+
+// MedicationOrderListRequest holds the query string of GET /patient/:id/medications
+type MedicationOrderListRequest struct {
+	ActiveOnly bool `form:"active_only"`
+}
+
+// MedicationCancelRequest is the body of POST /medication-orders/:id/cancel
+type MedicationCancelRequest struct {
+	Reason string `json:"reason" binding:"required,max=255"`
+}
+
+// MedicationDispenseRequest is the body of POST /medication-orders/:id/dispense
+type MedicationDispenseRequest struct {
+	LocationID uint `json:"location_id" binding:"required"`
+}
+
+// MedicationDispensing is a dispensed order with the stock movements of the lots picked for it
+type MedicationDispensing struct {
+	Order     *MedicationOrder `json:"order"`
+	Movements []StockMovement  `json:"movements"`
+}
+
+// MedicationOrderRepository interface - medication orders are stored per tenant schema
+type MedicationOrderRepository interface {
+	GetByID(id uint, schemaName string) (*MedicationOrder, error)
+	// ListByEncounter returns the orders of an encounter in the order they were placed
+	ListByEncounter(encounterID uint, schemaName string) ([]MedicationOrder, error)
+	// ListByPatient returns the orders of a patient and of the duplicates merged into it, newest
+	// first. activeAt narrows them to orders that are not cancelled and whose course has not
+	// ended at that time.
+	ListByPatient(patientID uint, activeAt *time.Time, schemaName string) ([]MedicationOrder, error)
+	// Create inserts the order with its audit events in one transaction
+	Create(order *MedicationOrder, events []*AuditEvent, schemaName string) error
+	// UpdateStatus saves the status change only while the order still has fromStatus,
+	// failing with ErrPreconditionFailed when another request changed it first
+	UpdateStatus(order *MedicationOrder, fromStatus string, event *AuditEvent, schemaName string) error
+}
+
+// MedicationOrderService interface - prescriptions of live patients, audited like the patient record
+type MedicationOrderService interface {
+	// Create checks the order against the patient's drug allergies, active orders of the same
+	// therapeutic class and known interactions with active orders. It fails with a
+	// MedicationAlertError when alerts are raised and not acknowledged.
+	Create(encounterID uint, req *MedicationOrderRequest, actor *Actor, schemaName string) (*MedicationOrder, error)
+	GetByID(id uint, actor *Actor, schemaName string) (*MedicationOrder, error)
+	ListByEncounter(encounterID uint, actor *Actor, schemaName string) ([]MedicationOrder, error)
+	ListByPatient(patientID uint, req *MedicationOrderListRequest, actor *Actor, schemaName string) ([]MedicationOrder, error)
+	// Verify, Dispense and Cancel fail with ErrInvalidStatusTransition when the order is not in a
+	// status they can move it from
+	Verify(id uint, actor *Actor, schemaName string) (*MedicationOrder, error)
+	// Dispense takes the order's quantity from the stock of a location first-expiry-first-out,
+	// failing with ErrInsufficientStock when the location holds too little usable stock
+	Dispense(id uint, req *MedicationDispenseRequest, actor *Actor, schemaName string) (*MedicationDispensing, error)
+	Cancel(id uint, req *MedicationCancelRequest, actor *Actor, schemaName string) (*MedicationOrder, error)
+}
@@ -2,8 +2,11 @@ package domain
 
 import (
 	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"reflect"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
@@ -76,9 +79,44 @@ func (b BloodGrp) Value() (driver.Value, error) {
 	return string(b), nil
 }
 
+// NullableString is a string column stored as NULL when blank, so unique
+// indexes only apply to real values
+type NullableString string
+
+// Scan implements the sql.Scanner interface with proper nil and type handling
+func (n *NullableString) Scan(value interface{}) error {
+	if value == nil {
+		*n = ""
+		return nil
+	}
+
+	switch v := value.(type) {
+	case string:
+		*n = NullableString(v)
+	case []byte:
+		*n = NullableString(string(v))
+	default:
+		return fmt.Errorf("unsupported type for NullableString: %T", value)
+	}
+	return nil
+}
+
+func (n NullableString) Value() (driver.Value, error) {
+	if n == "" {
+		return nil, nil
+	}
+	return string(n), nil
+}
+
+// NormalizePassportID trims a passport number and upper-cases its letters
+func NormalizePassportID(passportID string) string {
+	return strings.ToUpper(strings.TrimSpace(passportID))
+}
+
 // Patient model - tenant isolation is handled at schema level
 type Patient struct {
 	gorm.Model
+	Versioned
 	FirstNameTH  string    `json:"first_name_th" gorm:"not null,max=255"`
 	LastNameTH   string    `json:"last_name_th" gorm:"not null,max=255"`
 	MiddleNameTH string    `json:"middle_name_th" gorm:"null,max=255"`
@@ -89,18 +127,24 @@ type Patient struct {
 	NickNameTH   string    `json:"nick_name_th" gorm:"not null,max=255"`
 	NickNameEN   string    `json:"nick_name_en" gorm:"not null,max=255"`
 	PatientHN    string    `json:"patient_hn" gorm:"uniqueIndex;not null"`
-	NationalID   string    `json:"national_id" gorm:"uniqueIndex"`
-	PassportID   string    `json:"passport_id" gorm:"uniqueIndex"`
-	PhoneNumber  string    `json:"phone_number" gorm:"uniqueIndex,max=10"`
-	Email        string    `json:"email" gorm:"uniqueIndex, email"`
+	// Unique among live patients only, so a deleted record does not block re-registration
+	NationalID  NullableString `json:"national_id" gorm:"uniqueIndex:idx_patients_national_id_live,where:deleted_at IS NULL"`
+	PassportID  NullableString `json:"passport_id" gorm:"uniqueIndex:idx_patients_passport_id_live,where:deleted_at IS NULL"`
+	PhoneNumber string         `json:"phone_number" gorm:"uniqueIndex:idx_patients_phone_number_live,where:deleted_at IS NULL"`
+	Email       string         `json:"email" gorm:"uniqueIndex:idx_patients_email_live,where:deleted_at IS NULL"`
 	// Enum: M, F, OTHER
 	Gender      Gender `json:"gender" gorm:"not null,max=5,enum=M|F|OTHER"`
 	Nationality string `json:"nationality" gorm:"not null,max=100"`
 	// Enum: A, B, O, AB
 	BloodGrp BloodGrp `json:"blood_grp" gorm:"not null,max=3,enum=A|B|O|AB"`
+
+	// PrimaryContact is filled in when a single patient is fetched
+	PrimaryContact *PatientContact `json:"primary_contact,omitempty" gorm:"-"`
+	// AllergyBanner is filled in by search and by fetching a single patient
+	AllergyBanner *AllergyBanner `json:"allergy_banner,omitempty" gorm:"-"`
 }
 
-// DTO for creating a patient
+// DTO for creating a patient - at least one of national_id and passport_id is required
 type PatientCreateRequest struct {
 	FirstNameTH  string `json:"first_name_th" binding:"required,max=255"`
 	LastNameTH   string `json:"last_name_th" binding:"required,max=255"`
@@ -111,16 +155,18 @@ type PatientCreateRequest struct {
 	DateOfBirth  string `json:"date_of_birth" binding:"required"`
 	NickNameTH   string `json:"nick_name_th" binding:"required,max=50"`
 	NickNameEN   string `json:"nick_name_en" binding:"required,max=50"`
-	NationalID   string `json:"national_id" binding:"required,max=13"`
-	PassportID   string `json:"passport_id" binding:"required,max=13"`
+	NationalID   string `json:"national_id" binding:"required_without=PassportID,omitempty,thai_national_id"`
+	PassportID   string `json:"passport_id" binding:"required_without=NationalID,omitempty,passport"`
 	PhoneNumber  string `json:"phone_number" binding:"required,max=10"`
 	Email        string `json:"email" binding:"required,email"`
 	Gender       Gender `json:"gender" binding:"required,oneof=M F OTHER"`
 	Nationality  string `json:"nationality" binding:"required,max=100"`
 	BloodGrp     string `json:"blood_grp" binding:"required,oneof=A B O AB"`
+	// Force registers the patient even when possible duplicates are found; the override is audited
+	Force bool `json:"force"`
 }
 
-// DTO for full update (PUT) - all fields are required
+// DTO for full update (PUT) - all fields are required, and at least one of national_id and passport_id
 type PatientUpdateRequest struct {
 	FirstNameTH  string `json:"first_name_th" binding:"required,max=255"`
 	LastNameTH   string `json:"last_name_th" binding:"required,max=255"`
@@ -131,8 +177,8 @@ type PatientUpdateRequest struct {
 	DateOfBirth  string `json:"date_of_birth" binding:"required"`
 	NickNameTH   string `json:"nick_name_th" binding:"max=50"`
 	NickNameEN   string `json:"nick_name_en" binding:"max=50"`
-	NationalID   string `json:"national_id" binding:"max=13"`
-	PassportID   string `json:"passport_id" binding:"max=13"`
+	NationalID   string `json:"national_id" binding:"required_without=PassportID,omitempty,thai_national_id"`
+	PassportID   string `json:"passport_id" binding:"required_without=NationalID,omitempty,passport"`
 	PhoneNumber  string `json:"phone_number" binding:"max=10"`
 	Email        string `json:"email" binding:"omitempty,email"`
 	Gender       string `json:"gender" binding:"required,oneof=M F OTHER"`
@@ -151,8 +197,8 @@ type PatientPartialUpdateRequest struct {
 	DateOfBirth  *string `json:"date_of_birth,omitempty" db:"date_of_birth"`
 	NickNameTH   *string `json:"nick_name_th,omitempty" binding:"omitempty,max=50" db:"nick_name_th"`
 	NickNameEN   *string `json:"nick_name_en,omitempty" binding:"omitempty,max=50" db:"nick_name_en"`
-	NationalID   *string `json:"national_id,omitempty" binding:"omitempty,max=13" db:"national_id"`
-	PassportID   *string `json:"passport_id,omitempty" binding:"omitempty,max=13" db:"passport_id"`
+	NationalID   *string `json:"national_id,omitempty" binding:"omitempty,thai_national_id" db:"national_id"`
+	PassportID   *string `json:"passport_id,omitempty" binding:"omitempty,passport" db:"passport_id"`
 	PhoneNumber  *string `json:"phone_number,omitempty" binding:"omitempty,max=10" db:"phone_number"`
 	Email        *string `json:"email,omitempty" binding:"omitempty,email" db:"email"`
 	Gender       *string `json:"gender,omitempty" binding:"omitempty,oneof=M F OTHER" db:"gender"`
@@ -185,30 +231,261 @@ func (r *PatientPartialUpdateRequest) ToMap() (map[string]interface{}, error) {
 			continue
 		}
 
+		// Blank identifiers are cleared to NULL
+		switch dbTag {
+		case "national_id":
+			updates[dbTag] = NullableString(strings.TrimSpace(value.(string)))
+			continue
+		case "passport_id":
+			updates[dbTag] = NullableString(NormalizePassportID(value.(string)))
+			continue
+		}
+
 		updates[dbTag] = value
 	}
 
 	return updates, nil
 }
 
+// Patient search page sizes
+const (
+	DefaultPatientSearchLimit = 20
+	MaxPatientSearchLimit     = 100
+	// DefaultPatientSort lists the newest registrations first
+	DefaultPatientSort = "-created_at"
+)
+
+// Patient search modes
+const (
+	// PatientSearchContains matches the query as a substring of names and identifiers
+	PatientSearchContains = "contains"
+	// PatientSearchFuzzy ranks patients by trigram similarity of their Thai and English names
+	PatientSearchFuzzy = "fuzzy"
+	// FuzzySearchThreshold is the minimum name similarity (0-1) for a fuzzy match
+	FuzzySearchThreshold = 0.3
+	// FuzzySortKey is the cursor sort key of fuzzy search, which is always by score
+	FuzzySortKey = "-score"
+)
+
+// PatientSortColumns maps the sort keys accepted by patient search to their columns.
+// Only NOT NULL columns are listed so keyset pagination never has to compare NULLs.
+var PatientSortColumns = map[string]string{
+	"hn":            "patient_hn",
+	"first_name_th": "first_name_th",
+	"last_name_th":  "last_name_th",
+	"first_name_en": "first_name_en",
+	"last_name_en":  "last_name_en",
+	"date_of_birth": "date_of_birth",
+	"created_at":    "created_at",
+}
+
+// PatientSearchRequest holds the query string of GET /patient/search.
+// Sort is a key of PatientSortColumns, prefixed with "-" for descending order.
+type PatientSearchRequest struct {
+	Query       string `form:"query"`
+	Mode        string `form:"mode" binding:"omitempty,oneof=contains fuzzy"`
+	Limit       int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Cursor      string `form:"cursor"`
+	Sort        string `form:"sort"`
+	Gender      string `form:"gender" binding:"omitempty,oneof=M F OTHER"`
+	BloodGrp    string `form:"blood_grp" binding:"omitempty,oneof=A B O AB"`
+	Nationality string `form:"nationality" binding:"max=100"`
+	DOBFrom     string `form:"dob_from"`
+	DOBTo       string `form:"dob_to"`
+	CreatedFrom string `form:"created_from"`
+	CreatedTo   string `form:"created_to"`
+}
+
+// PatientSearchFilter is the validated search passed to the repository.
+// Date ranges are half-open: From is inclusive, To is exclusive.
+type PatientSearchFilter struct {
+	Query       string
+	Gender      string
+	BloodGrp    string
+	Nationality string
+	DOBFrom     *time.Time
+	DOBTo       *time.Time
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	// Fuzzy ranks by name similarity instead of SortColumn
+	Fuzzy      bool
+	SortColumn string
+	SortDesc   bool
+	// AfterValue and AfterID are the sort key of the last row of the previous page
+	AfterValue interface{}
+	AfterID    uint
+	Limit      int
+}
+
+// PatientCursor is the opaque position of the last patient of a search page
+type PatientCursor struct {
+	Sort  string `json:"s"`
+	Value string `json:"v"`
+	ID    uint   `json:"id"`
+}
+
+// Encode returns the cursor as a URL-safe string
+func (c *PatientCursor) Encode() string {
+	encoded, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(encoded)
+}
+
+// DecodePatientCursor parses a cursor produced by PatientCursor.Encode
+func DecodePatientCursor(cursor string) (*PatientCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid cursor", ErrInvalidInput)
+	}
+
+	var decoded PatientCursor
+	if err := json.Unmarshal(raw, &decoded); err != nil || decoded.ID == 0 {
+		return nil, fmt.Errorf("%w: invalid cursor", ErrInvalidInput)
+	}
+	return &decoded, nil
+}
+
+// PatientSearchItem is a patient in search results.
+// Score is the name similarity and is only set in fuzzy mode.
+type PatientSearchItem struct {
+	Patient
+	Score *float64 `json:"score,omitempty" gorm:"->;column:score"`
+}
+
+// PatientSearchResult is one page of patient search results
+type PatientSearchResult struct {
+	Patients   []PatientSearchItem
+	Total      int64
+	Limit      int
+	NextCursor string
+}
+
+// Duplicate detection scoring. Each matching attribute adds its weight; the name weight is
+// scaled by trigram similarity. A candidate at or above DuplicateScoreThreshold is reported.
+const (
+	DuplicateWeightIdentifier  = 100
+	DuplicateWeightDateOfBirth = 30
+	DuplicateWeightPhone       = 30
+	DuplicateWeightName        = 50
+	DuplicateScoreThreshold    = 60
+	// MaxDuplicateCandidates is the number of candidates returned to the clerk
+	MaxDuplicateCandidates = 5
+	// DuplicateCandidateScanLimit is the number of rows scored per registration
+	DuplicateCandidateScanLimit = 50
+)
+
+// DuplicateCandidate is an existing patient that may be the person being registered.
+// Reasons lists the matching attributes by column name, plus "name" for a similar name.
+type DuplicateCandidate struct {
+	Patient Patient  `json:"patient"`
+	Score   int      `json:"score"`
+	Reasons []string `json:"reasons"`
+}
+
+// ScoreDuplicateCandidate scores how likely candidate is the same person as probe.
+// candidate.Score holds the name similarity computed by the repository.
+func ScoreDuplicateCandidate(probe *Patient, candidate *PatientSearchItem) DuplicateCandidate {
+	result := DuplicateCandidate{Patient: candidate.Patient, Reasons: []string{}}
+	existing := &candidate.Patient
+
+	if probe.NationalID != "" && probe.NationalID == existing.NationalID {
+		result.Score += DuplicateWeightIdentifier
+		result.Reasons = append(result.Reasons, "national_id")
+	}
+	if probe.PassportID != "" && probe.PassportID == existing.PassportID {
+		result.Score += DuplicateWeightIdentifier
+		result.Reasons = append(result.Reasons, "passport_id")
+	}
+	if probe.DateOfBirth.Format(DateFormat) == existing.DateOfBirth.Format(DateFormat) {
+		result.Score += DuplicateWeightDateOfBirth
+		result.Reasons = append(result.Reasons, "date_of_birth")
+	}
+	if probe.PhoneNumber != "" && probe.PhoneNumber == existing.PhoneNumber {
+		result.Score += DuplicateWeightPhone
+		result.Reasons = append(result.Reasons, "phone_number")
+	}
+	if candidate.Score != nil && *candidate.Score >= FuzzySearchThreshold {
+		result.Score += int(*candidate.Score*DuplicateWeightName + 0.5)
+		result.Reasons = append(result.Reasons, "name")
+	}
+	return result
+}
+
+// DuplicatePatientError carries the candidates found when registering a possible duplicate
+type DuplicatePatientError struct {
+	Candidates []DuplicateCandidate
+}
+
+func (e *DuplicatePatientError) Error() string {
+	return fmt.Sprintf("%s: %d candidates", ErrPossibleDuplicate, len(e.Candidates))
+}
+
+func (e *DuplicatePatientError) Unwrap() error {
+	return ErrPossibleDuplicate
+}
+
 // PatientRepository interface - tenant schema provides isolation, no hospitalID needed
 type PatientRepository interface {
 	GetAll(schemaName string) ([]Patient, error)
 	GetByID(id uint, schemaName string) (*Patient, error)
-	Search(query string, schemaName string) ([]Patient, error)
+	// Search returns up to filter.Limit patients and the total number of matches ignoring the cursor
+	Search(filter *PatientSearchFilter, schemaName string) ([]Patient, int64, error)
+	// FuzzySearch ranks patients by name similarity to filter.Query, best match first
+	FuzzySearch(filter *PatientSearchFilter, schemaName string) ([]PatientSearchItem, int64, error)
+	// Search, FuzzySearch and SearchByID fill in the allergy banner of each patient.
+	// SearchByID also fills in the primary contact of the patient
 	SearchByID(id uint, schemaName string) (*Patient, error)
-	Create(patient *Patient, schemaName string) error
-	Update(patient *Patient, schemaName string) error
-	PartialUpdate(id uint, updates map[string]interface{}, schemaName string) error
-	Delete(id uint, schemaName string) error
+	// FindDuplicateCandidates returns up to limit patients sharing an identifier, date of birth or
+	// phone number with probe, or with a similar name. Score is set to the best name similarity.
+	FindDuplicateCandidates(probe *Patient, limit int, schemaName string) ([]PatientSearchItem, error)
+	// Write methods append the given audit events in the same transaction as the change.
+	// Create sets the patient ID of its events once the row is inserted. Create, Update and
+	// PartialUpdate also record the resulting demographics as a new PatientVersion.
+	// Create issues the patient's HN from a counter row in the same transaction as the insert,
+	// so a failed registration gives its number back
+	Create(patient *Patient, issuer *HNIssuer, events []*AuditEvent, schemaName string) error
+	// Update and PartialUpdate only write the version the caller read, patient.Version and version
+	// respectively, and fail with ErrPreconditionFailed once another write has incremented it
+	Update(patient *Patient, event *AuditEvent, schemaName string) error
+	PartialUpdate(id uint, version uint, updates map[string]interface{}, event *AuditEvent, schemaName string) error
+	Delete(id uint, event *AuditEvent, schemaName string) error
+	// Merge soft-deletes merge.RetiredID and stores the merge; it fails with gorm.ErrRecordNotFound
+	// unless both patients are live
+	Merge(merge *PatientMerge, events []*AuditEvent, schemaName string) error
+	// Unmerge marks the merge reversed and restores the retired patient
+	Unmerge(merge *PatientMerge, events []*AuditEvent, schemaName string) error
+	GetMerge(id uint, schemaName string) (*PatientMerge, error)
+	// ListMerges returns merges involving the patient, or all merges when patientID is nil, newest first
+	ListMerges(patientID *uint, schemaName string) ([]PatientMerge, error)
+	// ListDeleted returns soft-deleted patients, newest deletion first, optionally only those deleted
+	// before deletedBefore. Patients retired by an active merge are excluded, they come back by unmerge.
+	ListDeleted(deletedBefore *time.Time, schemaName string) ([]Patient, error)
+	GetDeleted(id uint, schemaName string) (*Patient, error)
+	// FindLiveByUniqueFields returns live patients sharing a national ID, passport, phone or email with p
+	FindLiveByUniqueFields(p *Patient, schemaName string) ([]Patient, error)
+	Restore(id uint, event *AuditEvent, schemaName string) error
+	// ListVersions returns the version history of a patient, oldest first
+	ListVersions(patientID uint, schemaName string) ([]PatientVersion, error)
+	// Purge hard-deletes the given patients that are still deleted before deletedBefore, with their
+	// contacts, addresses and versions, and returns how many were removed. Patients that took part
+	// in a merge or have clinical history are kept and returned as retained. Only the events of
+	// removed patients are appended.
+	Purge(ids []uint, deletedBefore time.Time, events []*AuditEvent, schemaName string) (int, []PurgeRetention, error)
 }
 
-// PatientService interface - tenant isolation handled at schema level
+// PatientService interface - tenant isolation handled at schema level.
+// Every call is recorded in the audit trail against the given actor.
 type PatientService interface {
-	Search(query string, schemaName string) ([]Patient, error)
-	SearchByID(id uint, schemaName string) (*Patient, error)
-	Create(req *PatientCreateRequest, schemaName string) (*Patient, error)
-	Update(id uint, req *PatientUpdateRequest, schemaName string) (*Patient, error)
-	PartialUpdate(id uint, req *PatientPartialUpdateRequest, schemaName string) (*Patient, error)
-	Delete(id uint, schemaName string) error
+	Search(req *PatientSearchRequest, actor *Actor, schemaName string) (*PatientSearchResult, error)
+	SearchByID(id uint, actor *Actor, schemaName string) (*Patient, error)
+	Create(req *PatientCreateRequest, actor *Actor, schemaName string) (*Patient, error)
+	// Update and PartialUpdate take the version the client read, from its If-Match header
+	Update(id uint, req *PatientUpdateRequest, version uint, actor *Actor, schemaName string) (*Patient, error)
+	PartialUpdate(id uint, req *PatientPartialUpdateRequest, version uint, actor *Actor, schemaName string) (*Patient, error)
+	Delete(id uint, actor *Actor, schemaName string) error
+	Merge(req *PatientMergeRequest, actor *Actor, schemaName string) (*PatientMerge, error)
+	Unmerge(mergeID uint, actor *Actor, schemaName string) (*PatientMerge, error)
+	ListMerges(patientID *uint, schemaName string) ([]PatientMerge, error)
+	// History lists the versions of a patient, each with its changes from the previous version
+	History(id uint, actor *Actor, schemaName string) ([]PatientVersion, error)
+	DiffVersions(id uint, from int, to int, actor *Actor, schemaName string) (*PatientVersionDiff, error)
 }
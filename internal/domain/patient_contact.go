@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"gorm.io/gorm"
+)
+
+// Relationships of a contact to the patient
+const (
+	RelationshipSpouse   = "spouse"
+	RelationshipParent   = "parent"
+	RelationshipChild    = "child"
+	RelationshipSibling  = "sibling"
+	RelationshipRelative = "relative"
+	RelationshipGuardian = "guardian"
+	RelationshipFriend   = "friend"
+	RelationshipOther    = "other"
+)
+
+// PatientContact is a next of kin or emergency contact of a patient.
+// A patient has at most one primary contact, enforced by a partial unique index.
+type PatientContact struct {
+	gorm.Model
+	PatientID      uint   `json:"patient_id" gorm:"not null;index"`
+	Name           string `json:"name" gorm:"not null;size:255"`
+	Relationship   string `json:"relationship" gorm:"not null;size:20"`
+	PhoneNumber    string `json:"phone_number" gorm:"not null;size:20"`
+	AltPhoneNumber string `json:"alt_phone_number" gorm:"size:20"`
+	Address        string `json:"address" gorm:"type:text"`
+	IsPrimary      bool   `json:"is_primary" gorm:"not null;default:false"`
+}
+
+// PatientContactFieldValues returns the audited fields of a contact keyed by JSON name
+func PatientContactFieldValues(c *PatientContact) map[string]interface{} {
+	return map[string]interface{}{
+		"name":             c.Name,
+		"relationship":     c.Relationship,
+		"phone_number":     c.PhoneNumber,
+		"alt_phone_number": c.AltPhoneNumber,
+		"address":          c.Address,
+		"is_primary":       c.IsPrimary,
+	}
+}
+
+// PatientContactRequest is the body of POST and PUT /patient/:id/contacts
+type PatientContactRequest struct {
+	Name           string `json:"name" binding:"required,max=255"`
+	Relationship   string `json:"relationship" binding:"required,oneof=spouse parent child sibling relative guardian friend other"`
+	PhoneNumber    string `json:"phone_number" binding:"required,max=10"`
+	AltPhoneNumber string `json:"alt_phone_number" binding:"max=10"`
+	Address        string `json:"address" binding:"max=500"`
+	// IsPrimary makes this the primary contact, replacing the current one
+	IsPrimary bool `json:"is_primary"`
+}
+
+// PatientContactRepository interface - contacts are stored per tenant schema
+type PatientContactRepository interface {
+	ListByPatient(patientID uint, schemaName string) ([]PatientContact, error)
+	GetByID(patientID uint, id uint, schemaName string) (*PatientContact, error)
+	// GetPrimary returns the primary contact of a patient, nil when there is none
+	GetPrimary(patientID uint, schemaName string) (*PatientContact, error)
+	// Write methods append the audit event in the same transaction. Saving a primary
+	// contact clears the flag on the other contacts of the patient first.
+	Create(contact *PatientContact, event *AuditEvent, schemaName string) error
+	Update(contact *PatientContact, event *AuditEvent, schemaName string) error
+	Delete(contact *PatientContact, event *AuditEvent, schemaName string) error
+}
+
+// PatientContactService interface - contacts of a live patient, audited like the patient record
+type PatientContactService interface {
+	List(patientID uint, actor *Actor, schemaName string) ([]PatientContact, error)
+	Create(patientID uint, req *PatientContactRequest, actor *Actor, schemaName string) (*PatientContact, error)
+	Update(patientID uint, id uint, req *PatientContactRequest, actor *Actor, schemaName string) (*PatientContact, error)
+	Delete(patientID uint, id uint, actor *Actor, schemaName string) error
+}
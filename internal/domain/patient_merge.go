@@ -0,0 +1,34 @@
+package domain
+
+import "time"
+
+// PatientMerge records a duplicate patient record retired into a surviving record.
+// While the merge is active the retired record is soft-deleted and its HN is an alias
+// of the survivor, so search and lookups by the retired HN or ID find the survivor.
+type PatientMerge struct {
+	ID               uint      `json:"id" gorm:"primarykey"`
+	CreatedAt        time.Time `json:"created_at"`
+	SurvivorID       uint      `json:"survivor_id" gorm:"not null"`
+	SurvivorHN       string    `json:"survivor_hn" gorm:"not null;size:50"`
+	RetiredID        uint      `json:"retired_id" gorm:"not null"`
+	RetiredHN        string    `json:"retired_hn" gorm:"not null;size:50"`
+	Reason           string    `json:"reason" gorm:"size:255"`
+	MergedBy         uint      `json:"merged_by" gorm:"not null"`
+	MergedByUsername string    `json:"merged_by_username" gorm:"size:100"`
+	// UnmergedAt is set once the merge is reversed, the alias then no longer resolves
+	UnmergedAt         *time.Time `json:"unmerged_at"`
+	UnmergedBy         *uint      `json:"unmerged_by"`
+	UnmergedByUsername string     `json:"unmerged_by_username" gorm:"size:100"`
+}
+
+// IsActive reports whether the merge has not been reversed
+func (m *PatientMerge) IsActive() bool {
+	return m.UnmergedAt == nil
+}
+
+// PatientMergeRequest retires RetiredID into SurvivorID
+type PatientMergeRequest struct {
+	SurvivorID uint   `json:"survivor_id" binding:"required"`
+	RetiredID  uint   `json:"retired_id" binding:"required"`
+	Reason     string `json:"reason" binding:"required,max=255"`
+}
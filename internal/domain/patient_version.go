@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Patient version actions, the write that produced a version
+const (
+	PatientVersionCreate        = "create"
+	PatientVersionUpdate        = "update"
+	PatientVersionPartialUpdate = "partial_update"
+	// PatientVersionBaseline is the state of a patient registered before versioning,
+	// captured just before its first update
+	PatientVersionBaseline = "baseline"
+)
+
+// PatientSnapshot holds the demographic fields of a patient keyed by column name,
+// as returned by PatientFieldValues. It is stored as JSONB.
+type PatientSnapshot map[string]interface{}
+
+// Scan implements the sql.Scanner interface with proper nil and type handling
+func (s *PatientSnapshot) Scan(value interface{}) error {
+	if value == nil {
+		*s = nil
+		return nil
+	}
+
+	var raw []byte
+	switch v := value.(type) {
+	case string:
+		raw = []byte(v)
+	case []byte:
+		raw = v
+	default:
+		return fmt.Errorf("unsupported type for PatientSnapshot: %T", value)
+	}
+	return json.Unmarshal(raw, s)
+}
+
+func (s PatientSnapshot) Value() (driver.Value, error) {
+	if s == nil {
+		return nil, nil
+	}
+	encoded, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	return string(encoded), nil
+}
+
+// PatientVersion is the full demographic record of a patient after one write.
+// Versions are numbered from 1 per patient and written in the same transaction as the change.
+type PatientVersion struct {
+	ID                uint            `json:"id" gorm:"primarykey"`
+	PatientID         uint            `json:"patient_id" gorm:"not null"`
+	Version           int             `json:"version" gorm:"not null"`
+	CreatedAt         time.Time       `json:"created_at"`
+	Action            string          `json:"action" gorm:"not null;size:20"`
+	ChangedBy         uint            `json:"changed_by"`
+	ChangedByUsername string          `json:"changed_by_username" gorm:"size:100"`
+	Snapshot          PatientSnapshot `json:"snapshot" gorm:"type:jsonb;not null"`
+	// Changes is the difference from the previous version, filled in when listing history
+	Changes map[string]FieldChange `json:"changes,omitempty" gorm:"-"`
+}
+
+// NewPatientVersion snapshots a patient, attributing the version to the actor of event
+func NewPatientVersion(patient *Patient, action string, event *AuditEvent) *PatientVersion {
+	version := &PatientVersion{
+		PatientID: patient.ID,
+		Action:    action,
+		Snapshot:  PatientSnapshot(PatientFieldValues(patient)),
+	}
+	if event != nil {
+		version.ChangedBy = event.ActorID
+		version.ChangedByUsername = event.ActorUsername
+	}
+	return version
+}
+
+// PatientVersionDiff is the field-by-field difference between two versions of a patient
+type PatientVersionDiff struct {
+	PatientID uint                   `json:"patient_id"`
+	From      int                    `json:"from"`
+	To        int                    `json:"to"`
+	Changes   map[string]FieldChange `json:"changes"`
+}
+
+// DiffPatientVersions compares the snapshots of two versions; from may be newer than to
+func DiffPatientVersions(from *PatientVersion, to *PatientVersion) *PatientVersionDiff {
+	return &PatientVersionDiff{
+		PatientID: to.PatientID,
+		From:      from.Version,
+		To:        to.Version,
+		Changes:   DiffPatientFields(from.Snapshot, to.Snapshot),
+	}
+}
+
+// PatientVersionDiffRequest holds the query string of GET /patient/:id/history/diff
+type PatientVersionDiffRequest struct {
+	From int `form:"from" binding:"required,min=1"`
+	To   int `form:"to" binding:"required,min=1"`
+}
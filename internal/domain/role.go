@@ -0,0 +1,165 @@
+package domain
+
+import (
+	"gorm.io/gorm"
+)
+
+// Permission codes checked by middleware.RequirePermission
+const (
+	PermPatientRead      = "patient:read"
+	PermPatientWrite     = "patient:write"
+	PermPatientDelete    = "patient:delete"
+	PermPatientMerge     = "patient:merge"
+	PermStaffRead        = "staff:read"
+	PermStaffManage      = "staff:manage"
+	PermRoleManage       = "role:manage"
+	PermAuditRead        = "audit:read"
+	PermTrashManage      = "trash:manage"
+	PermSettingsManage   = "settings:manage"
+	PermAllergyWrite     = "allergy:write"
+	PermCoverageWrite    = "coverage:write"
+	PermEncounterWrite   = "encounter:write"
+	PermScheduleManage   = "schedule:manage"
+	PermAppointmentWrite = "appointment:write"
+	PermVitalsWrite      = "vitals:write"
+	PermDiagnosisWrite   = "diagnosis:write"
+	PermDrugManage       = "drug:manage"
+	PermMedicationOrder  = "medication:order"
+	PermMedicationVerify = "medication:verify"
+	PermInventoryRead    = "inventory:read"
+	PermInventoryManage  = "inventory:manage"
+	PermLabManage        = "lab:manage"
+	PermLabOrder         = "lab:order"
+	PermLabCollect       = "lab:collect"
+	PermLabResult        = "lab:result"
+)
+
+// AllPermissions lists every permission known to the system with its description.
+// It is seeded into the permissions table of each tenant schema.
+var AllPermissions = []Permission{
+	{Code: PermPatientRead, Description: "View and search patient records"},
+	{Code: PermPatientWrite, Description: "Register and update patient records"},
+	{Code: PermPatientDelete, Description: "Delete patient records"},
+	{Code: PermPatientMerge, Description: "Merge duplicate patient records and reverse merges"},
+	{Code: PermStaffRead, Description: "View staff members"},
+	{Code: PermStaffManage, Description: "Create, update and delete staff members"},
+	{Code: PermRoleManage, Description: "Manage roles and assign them to staff"},
+	{Code: PermAuditRead, Description: "View and verify the patient record audit trail"},
+	{Code: PermTrashManage, Description: "List, restore and purge deleted patients and staff"},
+	{Code: PermSettingsManage, Description: "View and change tenant settings such as the HN format"},
+	{Code: PermAllergyWrite, Description: "Record and change patient allergies"},
+	{Code: PermCoverageWrite, Description: "Record patient payer coverage and check eligibility"},
+	{Code: PermEncounterWrite, Description: "Register patient visits and move them through the queue"},
+	{Code: PermScheduleManage, Description: "Publish and withdraw doctor availability"},
+	{Code: PermAppointmentWrite, Description: "Book, reschedule and cancel appointments"},
+	{Code: PermVitalsWrite, Description: "Record vital signs and triage levels"},
+	{Code: PermDiagnosisWrite, Description: "Record and remove encounter diagnoses"},
+	{Code: PermDrugManage, Description: "Maintain the drug master and known drug interactions"},
+	{Code: PermMedicationOrder, Description: "Prescribe medications and cancel medication orders"},
+	{Code: PermMedicationVerify, Description: "Verify, dispense and cancel medication orders"},
+	{Code: PermInventoryRead, Description: "View pharmacy stock, goods-received notes, stock cards and stock alerts"},
+	{Code: PermInventoryManage, Description: "Manage stock locations and reorder levels, receive goods and adjust stock"},
+	{Code: PermLabManage, Description: "Maintain the lab test catalogue and its reference ranges"},
+	{Code: PermLabOrder, Description: "Order and cancel lab tests"},
+	{Code: PermLabCollect, Description: "Collect specimens and print their barcode labels"},
+	{Code: PermLabResult, Description: "Receive and reject specimens, record results and import them from analyzers"},
+}
+
+// Built-in role codes seeded for every tenant
+const (
+	RoleAdmin        = "admin"
+	RoleDoctor       = "doctor"
+	RoleNurse        = "nurse"
+	RoleRegistration = "registration"
+	RolePharmacist   = "pharmacist"
+	RoleBilling      = "billing"
+	RoleLabTech      = "lab_technician"
+)
+
+// DefaultRole describes a built-in role and the permission codes it grants
+type DefaultRole struct {
+	Code        string
+	Name        string
+	Permissions []string
+}
+
+// DefaultRoles are seeded into each tenant schema as system roles
+var DefaultRoles = []DefaultRole{
+	{Code: RoleAdmin, Name: "Administrator", Permissions: permissionCodes(AllPermissions)},
+	{Code: RoleDoctor, Name: "Doctor", Permissions: []string{PermPatientRead, PermPatientWrite, PermStaffRead, PermAllergyWrite, PermEncounterWrite, PermScheduleManage, PermVitalsWrite, PermDiagnosisWrite, PermMedicationOrder, PermLabOrder}},
+	{Code: RoleNurse, Name: "Nurse", Permissions: []string{PermPatientRead, PermPatientWrite, PermStaffRead, PermAllergyWrite, PermEncounterWrite, PermAppointmentWrite, PermVitalsWrite, PermLabCollect}},
+	{Code: RoleRegistration, Name: "Registration Clerk", Permissions: []string{PermPatientRead, PermPatientWrite, PermCoverageWrite, PermEncounterWrite, PermAppointmentWrite}},
+	{Code: RolePharmacist, Name: "Pharmacist", Permissions: []string{PermPatientRead, PermAllergyWrite, PermDrugManage, PermMedicationVerify, PermInventoryRead, PermInventoryManage}},
+	{Code: RoleBilling, Name: "Billing", Permissions: []string{PermPatientRead, PermCoverageWrite}},
+	{Code: RoleLabTech, Name: "Medical Technologist", Permissions: []string{PermPatientRead, PermLabManage, PermLabCollect, PermLabResult}},
+}
+
+// Permission is a single grantable action, stored per tenant schema
+type Permission struct {
+	ID          uint   `json:"id" gorm:"primarykey"`
+	Code        string `json:"code" gorm:"uniqueIndex;not null;size:100"`
+	Description string `json:"description" gorm:"size:255"`
+}
+
+// Role groups permissions and is attached to staff members
+type Role struct {
+	gorm.Model
+	Code        string       `json:"code" gorm:"uniqueIndex;not null;size:50"`
+	Name        string       `json:"name" gorm:"not null;size:100"`
+	Description string       `json:"description" gorm:"size:255"`
+	IsSystem    bool         `json:"is_system" gorm:"default:false"`
+	Permissions []Permission `json:"permissions" gorm:"many2many:role_permissions;"`
+}
+
+// RoleCreateRequest represents the create role request payload
+type RoleCreateRequest struct {
+	Code        string   `json:"code" binding:"required,max=50"`
+	Name        string   `json:"name" binding:"required,max=100"`
+	Description string   `json:"description" binding:"max=255"`
+	Permissions []string `json:"permissions" binding:"required,min=1"`
+}
+
+// RoleUpdateRequest represents the update role request payload
+type RoleUpdateRequest struct {
+	Name        string   `json:"name" binding:"required,max=100"`
+	Description string   `json:"description" binding:"max=255"`
+	Permissions []string `json:"permissions" binding:"required,min=1"`
+}
+
+// StaffRolesRequest represents the payload to replace the roles of a staff member
+type StaffRolesRequest struct {
+	Roles []string `json:"roles" binding:"required"`
+}
+
+// RoleRepository interface - roles and permissions live in the tenant schema
+type RoleRepository interface {
+	GetAll(schemaName string) ([]Role, error)
+	GetByID(id uint, schemaName string) (*Role, error)
+	GetByCodes(codes []string, schemaName string) ([]Role, error)
+	GetAllPermissions(schemaName string) ([]Permission, error)
+	GetPermissionsByCodes(codes []string, schemaName string) ([]Permission, error)
+	Create(role *Role, schemaName string) error
+	Update(role *Role, schemaName string) error
+	Delete(id uint, schemaName string) error
+	ReplaceStaffRoles(staffID uint, roles []Role, schemaName string) error
+}
+
+// RoleService interface for role management
+type RoleService interface {
+	GetAll(schemaName string) ([]Role, error)
+	GetByID(id uint, schemaName string) (*Role, error)
+	GetAllPermissions(schemaName string) ([]Permission, error)
+	Create(req *RoleCreateRequest, schemaName string) (*Role, error)
+	Update(id uint, req *RoleUpdateRequest, schemaName string) (*Role, error)
+	Delete(id uint, schemaName string) error
+	AssignStaffRoles(staffID uint, req *StaffRolesRequest, schemaName string) error
+}
+
+// permissionCodes returns the codes of the given permissions
+func permissionCodes(permissions []Permission) []string {
+	codes := make([]string, 0, len(permissions))
+	for _, p := range permissions {
+		codes = append(codes, p.Code)
+	}
+	return codes
+}
@@ -1,31 +1,73 @@
 package domain
 
 import (
+	"time"
+
 	"gorm.io/gorm"
 )
 
 // Staff model - tenant isolation is handled at schema level
 type Staff struct {
 	gorm.Model
-	Username    string `json:"username" gorm:"uniqueIndex;not null,min=5,max=100"`
+	Versioned
+	// Unique fields are unique among live staff only, so deleted staff can be replaced
+	Username    string `json:"username" gorm:"uniqueIndex:idx_staffs_username_live,where:deleted_at IS NULL;not null,min=5,max=100"`
 	Password    string `json:"-" gorm:"not null"`
-	StaffCode   string `json:"staff_code" gorm:"uniqueIndex;not null"`
-	PhoneNumber string `json:"phone_number" gorm:"uniqueIndex;not null"`
-	Email       string `json:"email" gorm:"uniqueIndex;not null,email"`
+	StaffCode   string `json:"staff_code" gorm:"uniqueIndex:idx_staffs_staff_code_live,where:deleted_at IS NULL;not null"`
+	PhoneNumber string `json:"phone_number" gorm:"uniqueIndex:idx_staffs_phone_number_live,where:deleted_at IS NULL;not null"`
+	Email       string `json:"email" gorm:"uniqueIndex:idx_staffs_email_live,where:deleted_at IS NULL;not null,email"`
 	FirstName   string `json:"first_name" gorm:"not null,max=255"`
 	LastName    string `json:"last_name" gorm:"not null,max=255"`
-	IsAdmin     bool   `json:"is_admin" gorm:"default:false"`
+	Roles       []Role `json:"roles,omitempty" gorm:"many2many:staff_roles;"`
+}
+
+// RoleCodes returns the codes of the roles attached to the staff member
+func (s *Staff) RoleCodes() []string {
+	codes := make([]string, 0, len(s.Roles))
+	for _, role := range s.Roles {
+		codes = append(codes, role.Code)
+	}
+	return codes
+}
+
+// HasRole reports whether the staff member holds the role with the given code
+func (s *Staff) HasRole(code string) bool {
+	for _, role := range s.Roles {
+		if role.Code == code {
+			return true
+		}
+	}
+	return false
 }
 
+// PermissionCodes returns the distinct permissions granted by the staff member's roles.
+// Roles are the only source of privileges; they are changed through /role/staff/:id.
+func (s *Staff) PermissionCodes() []string {
+	seen := make(map[string]bool)
+	codes := make([]string, 0)
+	for _, role := range s.Roles {
+		for _, permission := range role.Permissions {
+			if !seen[permission.Code] {
+				seen[permission.Code] = true
+				codes = append(codes, permission.Code)
+			}
+		}
+	}
+	return codes
+}
+
+// StaffResponse is the staff member returned on login; IsAdmin reports whether they hold the built-in admin role
 type StaffResponse struct {
-	ID          uint   `json:"id"`
-	Username    string `json:"username"`
-	StaffCode   string `json:"staff_code"`
-	PhoneNumber string `json:"phone_number"`
-	Email       string `json:"email"`
-	FirstName   string `json:"first_name"`
-	LastName    string `json:"last_name"`
-	IsAdmin     bool   `json:"is_admin"`
+	ID          uint     `json:"id"`
+	Username    string   `json:"username"`
+	StaffCode   string   `json:"staff_code"`
+	PhoneNumber string   `json:"phone_number"`
+	Email       string   `json:"email"`
+	FirstName   string   `json:"first_name"`
+	LastName    string   `json:"last_name"`
+	IsAdmin     bool     `json:"is_admin"`
+	Roles       []string `json:"roles"`
+	Permissions []string `json:"permissions"`
 }
 
 // StaffCreateRequest represents the create request payload
@@ -38,7 +80,6 @@ type StaffCreateRequest struct {
 	Email       string `json:"email" binding:"required,email"`
 	FirstName   string `json:"first_name" binding:"required,max=255"`
 	LastName    string `json:"last_name" binding:"required,max=255"`
-	IsAdmin     bool   `json:"is_admin" default:"false"`
 }
 
 // StaffUpdateRequest represents the update request payload
@@ -48,7 +89,6 @@ type StaffUpdateRequest struct {
 	Email       string `json:"email" binding:"required,email"`
 	FirstName   string `json:"first_name" binding:"max=255"`
 	LastName    string `json:"last_name" binding:"max=255"`
-	IsAdmin     bool   `json:"is_admin"`
 }
 
 // StaffLoginRequest represents the login request payload (no hospitalID needed - tenant provides isolation)
@@ -57,10 +97,19 @@ type StaffLoginRequest struct {
 	Password string `json:"password" binding:"required,min=6"`
 }
 
+// StaffChangePasswordRequest represents the change password request payload
+type StaffChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required,min=6"`
+	NewPassword     string `json:"new_password" binding:"required,min=6"`
+}
+
 // StaffLoginResponse represents the login response payload
+// Token is a short-lived access token; RefreshToken is exchanged for a new pair at /staff/token/refresh
 type StaffLoginResponse struct {
-	Token string        `json:"token"`
-	Staff StaffResponse `json:"staff"`
+	Token        string        `json:"token"`
+	ExpiresAt    time.Time     `json:"expires_at"`
+	RefreshToken string        `json:"refresh_token"`
+	Staff        StaffResponse `json:"staff"`
 }
 
 // StaffRepository interface - tenant schema provides isolation
@@ -70,12 +119,28 @@ type StaffRepository interface {
 	GetByUsername(username string, schemaName string) (*Staff, error)
 	Create(staff *Staff, schemaName string) error
 	Update(staff *Staff, schemaName string) error
+	// UpdatePassword and Delete also revoke every session of the staff member in the same transaction
+	UpdatePassword(id uint, passwordHash string, schemaName string) error
 	Delete(id uint, schemaName string) error
+	// ListDeleted returns soft-deleted staff, newest deletion first, optionally only those deleted before deletedBefore
+	ListDeleted(deletedBefore *time.Time, schemaName string) ([]Staff, error)
+	GetDeleted(id uint, schemaName string) (*Staff, error)
+	// FindLiveByUniqueFields returns live staff sharing a username, staff code, phone or email with s
+	FindLiveByUniqueFields(s *Staff, schemaName string) ([]Staff, error)
+	Restore(id uint, schemaName string) error
+	// Purge hard-deletes the given staff still deleted before deletedBefore, with their
+	// refresh tokens, role assignments, availability templates and unbooked slots. Doctors
+	// with appointments are kept and returned with the reason; the count is of removed staff.
+	Purge(ids []uint, deletedBefore time.Time, schemaName string) (int, []PurgeRetention, error)
 }
 
 // StaffService interface - tenant isolation handled at schema level
 type StaffService interface {
 	Login(req *StaffLoginRequest, schemaName string) (*StaffLoginResponse, error)
+	RefreshToken(req *TokenRefreshRequest, schemaName string) (*StaffLoginResponse, error)
+	Logout(staffID uint, schemaName string) error
+	ChangePassword(id uint, req *StaffChangePasswordRequest, schemaName string) error
+	TokenRevocationChecker
 	GetAll(schemaName string) ([]Staff, error)
 	GetByID(id uint, schemaName string) (*Staff, error)
 	Create(req *StaffCreateRequest, schemaName string) (*Staff, error)
@@ -4,6 +4,9 @@ import (
 	"gorm.io/gorm"
 )
 
+// HospitalCodeLength is the exact length of a hospital code
+const HospitalCodeLength = 8
+
 // Tenant represents a tenant with their schema mapping
 type Tenant struct {
 	gorm.Model
@@ -18,7 +21,11 @@ type Tenant struct {
 	HospitalName string  `json:"hospital_name" gorm:"not null;size:150" binding:"required,min=1,max=150"`
 	HospitalCode string  `json:"hospital_code" gorm:"not null;size:8" binding:"required,len=8"`
 	Address      *string `json:"address" gorm:"type:text"` // Can be null
-	HNRunning    uint64  `json:"hn_running" gorm:"not null;default:0"`
+	// HNRunning is the running number of HNs issued before HN templates; it seeds the
+	// never-resetting counter in the tenant schema and is no longer incremented
+	HNRunning uint64 `json:"hn_running" gorm:"not null;default:0"`
+	// HNTemplate formats new HNs, see ParseHNTemplate
+	HNTemplate string `json:"hn_template" gorm:"not null;size:100;default:'{HOSP}-{SEQ:8}'"`
 }
 
 // TenantInfo holds the tenant context information for requests
@@ -49,8 +56,6 @@ type TenantRepository interface {
 	Update(tenant *Tenant) error
 	Delete(id uint) error
 	SchemaExists(schemaName string) (bool, error)
-	// IncrementHNRunning atomically increments HNRunning and returns the new value
-	IncrementHNRunning(tenantID uint) (uint64, error)
 }
 
 // TenantService interface for tenant business logic
@@ -61,6 +66,6 @@ type TenantService interface {
 	CreateTenantSchema(schemaName string) error
 	MigrateTenantSchema(schemaName string) error
 	SetupTenantWithAdmin(tenantCode, name, subdomain, hospitalName, hospitalCode string, address *string, adminUsername, adminPassword, adminEmail string) (*Tenant, error)
-	// GenerateHN generates a new HN in format 'hospitalCode-HNRunning'
-	GenerateHN(schemaName string) (string, error)
+	// GetHNIssuer returns what PatientRepository.Create needs to issue the tenant's next HN
+	GetHNIssuer(schemaName string) (*HNIssuer, error)
 }
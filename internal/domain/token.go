@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"time"
+)
+
+// Token revocation reasons recorded in the revocation list
+const (
+	RevokeReasonLogout         = "logout"
+	RevokeReasonPasswordChange = "password_change"
+	RevokeReasonStaffDeleted   = "staff_deleted"
+	RevokeReasonTokenReuse     = "refresh_token_reuse"
+)
+
+// RefreshToken is a rotating refresh token issued to a staff session.
+// Only the SHA-256 hash of the token is stored.
+type RefreshToken struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time `json:"created_at"`
+	StaffID   uint      `json:"staff_id" gorm:"not null;index"`
+	TokenHash string    `json:"-" gorm:"uniqueIndex;not null;size:64"`
+	// AccessTokenID is the jti of the access token issued together with this refresh token
+	AccessTokenID        string     `json:"access_token_id" gorm:"not null;size:64"`
+	AccessTokenExpiresAt time.Time  `json:"access_token_expires_at" gorm:"not null"`
+	ExpiresAt            time.Time  `json:"expires_at" gorm:"not null"`
+	RevokedAt            *time.Time `json:"revoked_at"`
+	ReplacedByID         *uint      `json:"replaced_by_id"`
+}
+
+// IsActive reports whether the refresh token can still be exchanged
+func (t *RefreshToken) IsActive(now time.Time) bool {
+	return t.RevokedAt == nil && now.Before(t.ExpiresAt)
+}
+
+// RevokedToken is an entry in the access token revocation list, keyed by jti
+type RevokedToken struct {
+	JTI       string    `json:"jti" gorm:"primaryKey;size:64"`
+	StaffID   uint      `json:"staff_id" gorm:"not null;index"`
+	ExpiresAt time.Time `json:"expires_at" gorm:"not null;index"`
+	RevokedAt time.Time `json:"revoked_at" gorm:"not null"`
+	Reason    string    `json:"reason" gorm:"size:50"`
+}
+
+// TokenRefreshRequest represents the token refresh request payload
+type TokenRefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// TokenRepository interface - refresh tokens and revocations live in the tenant schema
+type TokenRepository interface {
+	CreateRefreshToken(token *RefreshToken, schemaName string) error
+	GetRefreshTokenByHash(tokenHash string, schemaName string) (*RefreshToken, error)
+	// RotateRefreshToken revokes oldID and stores newToken in one transaction.
+	// It returns ErrInvalidToken if oldID has already been revoked.
+	RotateRefreshToken(oldID uint, newToken *RefreshToken, schemaName string) error
+	// RevokeAllForStaff revokes every refresh token of a staff member and adds
+	// the jti of every still-valid access token to the revocation list
+	RevokeAllForStaff(staffID uint, reason string, schemaName string) error
+	IsRevoked(jti string, schemaName string) (bool, error)
+}
+
+// TokenRevocationChecker is used by the auth middleware to reject revoked access tokens
+type TokenRevocationChecker interface {
+	IsTokenRevoked(jti string, schemaName string) (bool, error)
+}
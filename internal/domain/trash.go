@@ -0,0 +1,124 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RestoreConflict is a unique field of a deleted record that a live record has taken since
+type RestoreConflict struct {
+	Field string `json:"field"`
+	Value string `json:"value"`
+	// ExistingID is the live record holding the value
+	ExistingID uint `json:"existing_id"`
+}
+
+// RestoreConflictError is returned when a deleted record cannot be restored without
+// breaking a unique index; the live record has to be changed or merged first
+type RestoreConflictError struct {
+	Conflicts []RestoreConflict
+}
+
+func (e *RestoreConflictError) Error() string {
+	fields := make([]string, len(e.Conflicts))
+	for i, conflict := range e.Conflicts {
+		fields[i] = conflict.Field
+	}
+	return fmt.Sprintf("%s: %s already in use", ErrDuplicateEntry, strings.Join(fields, ", "))
+}
+
+func (e *RestoreConflictError) Unwrap() error {
+	return ErrDuplicateEntry
+}
+
+// uniqueField is a column covered by a unique index over live rows
+type uniqueField struct {
+	name  string
+	value string
+}
+
+func patientUniqueFields(p *Patient) []uniqueField {
+	return []uniqueField{
+		{"national_id", string(p.NationalID)},
+		{"passport_id", string(p.PassportID)},
+		{"phone_number", p.PhoneNumber},
+		{"email", p.Email},
+	}
+}
+
+func staffUniqueFields(s *Staff) []uniqueField {
+	return []uniqueField{
+		{"username", s.Username},
+		{"staff_code", s.StaffCode},
+		{"phone_number", s.PhoneNumber},
+		{"email", s.Email},
+	}
+}
+
+// restoreConflicts lists the non-blank fields of deleted that the live record also holds
+func restoreConflicts(deleted []uniqueField, live []uniqueField, liveID uint) []RestoreConflict {
+	conflicts := make([]RestoreConflict, 0)
+	for i, field := range deleted {
+		if field.value != "" && live[i].value == field.value {
+			conflicts = append(conflicts, RestoreConflict{Field: field.name, Value: field.value, ExistingID: liveID})
+		}
+	}
+	return conflicts
+}
+
+// PatientRestoreConflicts returns the unique fields of a deleted patient held by live patients
+func PatientRestoreConflicts(deleted *Patient, live []Patient) []RestoreConflict {
+	conflicts := make([]RestoreConflict, 0)
+	for i := range live {
+		conflicts = append(conflicts, restoreConflicts(patientUniqueFields(deleted), patientUniqueFields(&live[i]), live[i].ID)...)
+	}
+	return conflicts
+}
+
+// StaffRestoreConflicts returns the unique fields of a deleted staff member held by live staff
+func StaffRestoreConflicts(deleted *Staff, live []Staff) []RestoreConflict {
+	conflicts := make([]RestoreConflict, 0)
+	for i := range live {
+		conflicts = append(conflicts, restoreConflicts(staffUniqueFields(deleted), staffUniqueFields(&live[i]), live[i].ID)...)
+	}
+	return conflicts
+}
+
+// Reasons a record past the retention period is kept in the trash
+const (
+	// PurgeRetainedMerged marks a patient that took part in a merge, needed to undo it
+	PurgeRetainedMerged = "merged"
+	// PurgeRetainedHistory marks a patient with clinical or payer history, which falls under
+	// medical record retention rather than the trash retention period
+	PurgeRetainedHistory = "clinical_history"
+	// PurgeRetainedAppointments marks a doctor with appointments booked with them, which stay
+	// part of the patients' records
+	PurgeRetainedAppointments = "appointments"
+)
+
+// PurgeRetention is a record that was due to be purged but was kept, and why
+type PurgeRetention struct {
+	ID     uint   `json:"id"`
+	Reason string `json:"reason"`
+}
+
+// PurgeResult reports the records permanently removed by a purge
+type PurgeResult struct {
+	Purged int `json:"purged"`
+	// Retained lists the records past the retention period that were kept
+	Retained []PurgeRetention `json:"retained"`
+	// DeletedBefore is the cut-off: only records deleted before it were purged
+	DeletedBefore time.Time `json:"deleted_before"`
+}
+
+// TrashService lists, restores and purges soft-deleted patients and staff.
+// Patient restores and purges are recorded in the audit trail.
+type TrashService interface {
+	ListPatients(schemaName string) ([]Patient, error)
+	RestorePatient(id uint, actor *Actor, schemaName string) (*Patient, error)
+	PurgePatients(actor *Actor, schemaName string) (*PurgeResult, error)
+	ListStaff(schemaName string) ([]Staff, error)
+	RestoreStaff(id uint, schemaName string) (*Staff, error)
+	PurgeStaff(schemaName string) (*PurgeResult, error)
+}
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Versioned is the optimistic concurrency version of a tenant record. Every repository write
+// increments it, and a write that expects a version the row no longer has is rejected.
+// It is embedded so the version is not part of the audited field values of a record.
+type Versioned struct {
+	Version uint `json:"version" gorm:"not null;default:1"`
+}
+
+// ETag returns the version as a strong entity tag
+func (v Versioned) ETag() string {
+	return fmt.Sprintf("%q", strconv.FormatUint(uint64(v.Version), 10))
+}
+
+// ParseETag returns the version of an entity tag produced by ETag. Weak tags are accepted
+// since the version identifies the record state regardless of encoding.
+func ParseETag(tag string) (uint, error) {
+	tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return 0, fmt.Errorf("%w: malformed entity tag", ErrInvalidInput)
+	}
+
+	version, err := strconv.ParseUint(tag[1:len(tag)-1], 10, 32)
+	if err != nil || version == 0 {
+		return 0, fmt.Errorf("%w: malformed entity tag", ErrInvalidInput)
+	}
+	return uint(version), nil
+}
@@ -0,0 +1,311 @@
+package domain
+
+import (
+	"math"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Vital sign measures. Each code is also the column of the measure in vital_signs.
+const (
+	VitalMeasureSystolicBP      = "systolic_bp"
+	VitalMeasureDiastolicBP     = "diastolic_bp"
+	VitalMeasurePulse           = "pulse"
+	VitalMeasureTemperature     = "temperature"
+	VitalMeasureRespiratoryRate = "respiratory_rate"
+	VitalMeasureSpO2            = "spo2"
+	VitalMeasureHeight          = "height"
+	VitalMeasureWeight          = "weight"
+	VitalMeasureBMI             = "bmi"
+	VitalMeasurePainScore       = "pain_score"
+)
+
+// VitalMeasure describes a measure of a vital sign reading
+type VitalMeasure struct {
+	Code string `json:"code"`
+	Unit string `json:"unit"`
+}
+
+// VitalMeasures lists the measures in the order they are charted
+var VitalMeasures = []VitalMeasure{
+	{Code: VitalMeasureSystolicBP, Unit: "mmHg"},
+	{Code: VitalMeasureDiastolicBP, Unit: "mmHg"},
+	{Code: VitalMeasurePulse, Unit: "/min"},
+	{Code: VitalMeasureTemperature, Unit: "°C"},
+	{Code: VitalMeasureRespiratoryRate, Unit: "/min"},
+	{Code: VitalMeasureSpO2, Unit: "%"},
+	{Code: VitalMeasureHeight, Unit: "cm"},
+	{Code: VitalMeasureWeight, Unit: "kg"},
+	{Code: VitalMeasureBMI, Unit: "kg/m²"},
+	{Code: VitalMeasurePainScore, Unit: "0-10"},
+}
+
+// Triage levels of the five-level emergency severity index (ESI) used in Thai emergency
+// departments, from most to least urgent
+const (
+	TriageLevelResuscitation = 1
+	TriageLevelEmergent      = 2
+	TriageLevelUrgent        = 3
+	TriageLevelLessUrgent    = 4
+	TriageLevelNonUrgent     = 5
+)
+
+// Vital flags
+const (
+	VitalFlagLow  = "low"
+	VitalFlagHigh = "high"
+)
+
+const (
+	// DefaultVitalTrendLimit is the number of readings per measure a trend returns by default
+	DefaultVitalTrendLimit = 10
+	// MaxVitalTrendLimit caps the readings per measure of a trend
+	MaxVitalTrendLimit = 50
+)
+
+// VitalSign is one set of vital signs taken during an encounter. Every measure is optional,
+// BMI is computed from weight and height when the reading is recorded.
+type VitalSign struct {
+	gorm.Model
+	EncounterID uint `json:"encounter_id" gorm:"not null;index"`
+	PatientID   uint `json:"patient_id" gorm:"not null;index"`
+
+	SystolicBP      *int     `json:"systolic_bp"`
+	DiastolicBP     *int     `json:"diastolic_bp"`
+	Pulse           *int     `json:"pulse"`
+	Temperature     *float64 `json:"temperature"`
+	RespiratoryRate *int     `json:"respiratory_rate"`
+	SpO2            *int     `json:"spo2" gorm:"column:spo2"`
+	Height          *float64 `json:"height"`
+	Weight          *float64 `json:"weight"`
+	BMI             *float64 `json:"bmi" gorm:"column:bmi"`
+	PainScore       *int     `json:"pain_score"`
+	TriageLevel     *int     `json:"triage_level"`
+	Note            string   `json:"note" gorm:"size:500"`
+
+	RecordedBy uint      `json:"recorded_by" gorm:"not null"`
+	RecordedAt time.Time `json:"recorded_at" gorm:"not null"`
+
+	// Flags lists the measures outside the reference range for the patient's age at RecordedAt
+	Flags []VitalFlag `json:"flags" gorm:"-"`
+}
+
+// Values returns the measures present in the reading by code
+func (v *VitalSign) Values() map[string]float64 {
+	values := make(map[string]float64)
+	ints := map[string]*int{
+		VitalMeasureSystolicBP:      v.SystolicBP,
+		VitalMeasureDiastolicBP:     v.DiastolicBP,
+		VitalMeasurePulse:           v.Pulse,
+		VitalMeasureRespiratoryRate: v.RespiratoryRate,
+		VitalMeasureSpO2:            v.SpO2,
+		VitalMeasurePainScore:       v.PainScore,
+	}
+	for code, value := range ints {
+		if value != nil {
+			values[code] = float64(*value)
+		}
+	}
+	floats := map[string]*float64{
+		VitalMeasureTemperature: v.Temperature,
+		VitalMeasureHeight:      v.Height,
+		VitalMeasureWeight:      v.Weight,
+		VitalMeasureBMI:         v.BMI,
+	}
+	for code, value := range floats {
+		if value != nil {
+			values[code] = *value
+		}
+	}
+	return values
+}
+
+// Evaluate sets Flags from the reference ranges for a patient born on dateOfBirth
+func (v *VitalSign) Evaluate(dateOfBirth time.Time) {
+	ranges := VitalRanges(dateOfBirth, v.RecordedAt)
+	values := v.Values()
+	v.Flags = []VitalFlag{}
+	for _, measure := range VitalMeasures {
+		value, ok := values[measure.Code]
+		if !ok {
+			continue
+		}
+		r := ranges[measure.Code]
+		if flag := r.Flag(value); flag != "" {
+			v.Flags = append(v.Flags, VitalFlag{Measure: measure.Code, Value: value, Flag: flag, RangeLow: r.Low, RangeHigh: r.High})
+		}
+	}
+}
+
+// VitalFlag reports a measure outside its reference range
+type VitalFlag struct {
+	Measure   string  `json:"measure"`
+	Value     float64 `json:"value"`
+	Flag      string  `json:"flag"`
+	RangeLow  float64 `json:"range_low"`
+	RangeHigh float64 `json:"range_high"`
+}
+
+// VitalRange is an inclusive reference range; a zero range has no limits
+type VitalRange struct {
+	Low  float64
+	High float64
+}
+
+// Flag returns low or high when value is outside the range, "" otherwise
+func (r VitalRange) Flag(value float64) string {
+	if r == (VitalRange{}) {
+		return ""
+	}
+	switch {
+	case value < r.Low:
+		return VitalFlagLow
+	case value > r.High:
+		return VitalFlagHigh
+	}
+	return ""
+}
+
+// vitalAgeBand holds the age-dependent ranges up to an age in months
+type vitalAgeBand struct {
+	maxAgeMonths int
+	ranges       map[string]VitalRange
+}
+
+// vitalAgeBands follow the paediatric ranges of the PALS guideline; the last band is adults
+var vitalAgeBands = []vitalAgeBand{
+	{maxAgeMonths: 12, ranges: map[string]VitalRange{
+		VitalMeasurePulse: {100, 160}, VitalMeasureRespiratoryRate: {30, 60},
+		VitalMeasureSystolicBP: {70, 100}, VitalMeasureDiastolicBP: {35, 65},
+	}},
+	{maxAgeMonths: 36, ranges: map[string]VitalRange{
+		VitalMeasurePulse: {90, 150}, VitalMeasureRespiratoryRate: {24, 40},
+		VitalMeasureSystolicBP: {80, 110}, VitalMeasureDiastolicBP: {40, 70},
+	}},
+	{maxAgeMonths: 72, ranges: map[string]VitalRange{
+		VitalMeasurePulse: {80, 140}, VitalMeasureRespiratoryRate: {22, 34},
+		VitalMeasureSystolicBP: {80, 110}, VitalMeasureDiastolicBP: {45, 75},
+	}},
+	{maxAgeMonths: 156, ranges: map[string]VitalRange{
+		VitalMeasurePulse: {70, 120}, VitalMeasureRespiratoryRate: {18, 30},
+		VitalMeasureSystolicBP: {90, 120}, VitalMeasureDiastolicBP: {55, 80},
+	}},
+	{maxAgeMonths: 216, ranges: map[string]VitalRange{
+		VitalMeasurePulse: {60, 100}, VitalMeasureRespiratoryRate: {12, 20},
+		VitalMeasureSystolicBP: {100, 130}, VitalMeasureDiastolicBP: {60, 85},
+	}},
+	{maxAgeMonths: math.MaxInt, ranges: map[string]VitalRange{
+		VitalMeasurePulse: {60, 100}, VitalMeasureRespiratoryRate: {12, 20},
+		VitalMeasureSystolicBP: {90, 139}, VitalMeasureDiastolicBP: {60, 89},
+		// Asian BMI cut-offs used by the Thai MOPH; children need growth charts instead
+		VitalMeasureBMI: {18.5, 22.9},
+	}},
+}
+
+// VitalRanges returns the reference ranges for a patient born on dateOfBirth at time at.
+// Temperature, SpO2 and pain score do not depend on age.
+func VitalRanges(dateOfBirth, at time.Time) map[string]VitalRange {
+	ranges := map[string]VitalRange{
+		VitalMeasureTemperature: {36.0, 37.5},
+		VitalMeasureSpO2:        {95, 100},
+		VitalMeasurePainScore:   {0, 3},
+	}
+	months := AgeInMonths(dateOfBirth, at)
+	for _, band := range vitalAgeBands {
+		if months < band.maxAgeMonths {
+			for code, r := range band.ranges {
+				ranges[code] = r
+			}
+			break
+		}
+	}
+	return ranges
+}
+
+// AgeInMonths returns the completed months between dateOfBirth and at, counted on the Thai calendar day
+func AgeInMonths(dateOfBirth, at time.Time) int {
+	at = at.In(HNTimeZone)
+	months := (at.Year()-dateOfBirth.Year())*12 + int(at.Month()-dateOfBirth.Month())
+	if at.Day() < dateOfBirth.Day() {
+		months--
+	}
+	if months < 0 {
+		return 0
+	}
+	return months
+}
+
+// ComputeBMI returns weight / height² rounded to one decimal, weight in kg and height in cm
+func ComputeBMI(weight, height float64) float64 {
+	meters := height / 100
+	return math.Round(weight/(meters*meters)*10) / 10
+}
+
+// VitalSignRequest is the body of POST /encounters/:id/vitals
+type VitalSignRequest struct {
+	SystolicBP      *int     `json:"systolic_bp" binding:"omitempty,min=40,max=300"`
+	DiastolicBP     *int     `json:"diastolic_bp" binding:"omitempty,min=20,max=200"`
+	Pulse           *int     `json:"pulse" binding:"omitempty,min=20,max=300"`
+	Temperature     *float64 `json:"temperature" binding:"omitempty,min=30,max=45"`
+	RespiratoryRate *int     `json:"respiratory_rate" binding:"omitempty,min=4,max=80"`
+	SpO2            *int     `json:"spo2" binding:"omitempty,min=50,max=100"`
+	Height          *float64 `json:"height" binding:"omitempty,min=20,max=250"`
+	Weight          *float64 `json:"weight" binding:"omitempty,min=0.5,max=400"`
+	PainScore       *int     `json:"pain_score" binding:"omitempty,min=0,max=10"`
+	TriageLevel     *int     `json:"triage_level" binding:"omitempty,min=1,max=5"`
+	Note            string   `json:"note" binding:"max=500"`
+}
+
+// VitalSignListRequest holds the query of GET /patient/:id/vitals, days in YYYY-MM-DD
+type VitalSignListRequest struct {
+	From string `form:"from"`
+	To   string `form:"to"`
+}
+
+// VitalSignFilter selects the readings of a patient recorded in [From, To)
+type VitalSignFilter struct {
+	PatientID uint
+	From      *time.Time
+	To        *time.Time
+}
+
+// VitalTrendRequest holds the query of GET /patient/:id/vitals/trend
+type VitalTrendRequest struct {
+	Limit int `form:"limit" binding:"omitempty,min=1,max=50"`
+}
+
+// VitalTrendPoint is one reading of a measure
+type VitalTrendPoint struct {
+	Measure     string    `json:"-"`
+	EncounterID uint      `json:"encounter_id"`
+	RecordedAt  time.Time `json:"recorded_at"`
+	Value       float64   `json:"value"`
+	Flag        string    `json:"flag,omitempty" gorm:"-"`
+}
+
+// VitalTrend holds the latest readings of a measure, oldest first
+type VitalTrend struct {
+	Measure string            `json:"measure"`
+	Unit    string            `json:"unit"`
+	Points  []VitalTrendPoint `json:"points"`
+}
+
+// VitalSignRepository defines the interface for vital sign data access
+type VitalSignRepository interface {
+	Create(vital *VitalSign, event *AuditEvent, schemaName string) error
+	ListByEncounter(encounterID uint, schemaName string) ([]VitalSign, error)
+	ListByPatient(filter *VitalSignFilter, schemaName string) ([]VitalSign, error)
+	// LatestHeight returns the last height recorded for the patient, nil when there is none
+	LatestHeight(patientID uint, schemaName string) (*float64, error)
+	// Trend returns up to limit latest readings of each measure, ordered by measure then time
+	Trend(patientID uint, limit int, schemaName string) ([]VitalTrendPoint, error)
+}
+
+// VitalSignService defines the interface for vital sign business logic
+type VitalSignService interface {
+	Record(encounterID uint, req *VitalSignRequest, actor *Actor, schemaName string) (*VitalSign, error)
+	ListByEncounter(encounterID uint, actor *Actor, schemaName string) ([]VitalSign, error)
+	ListByPatient(patientID uint, req *VitalSignListRequest, actor *Actor, schemaName string) ([]VitalSign, error)
+	Trend(patientID uint, req *VitalTrendRequest, actor *Actor, schemaName string) ([]VitalTrend, error)
+}
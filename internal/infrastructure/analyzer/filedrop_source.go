@@ -0,0 +1,192 @@
+package analyzer
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+// FileDropProvider is the provider name of the file-drop source
+const FileDropProvider = "filedrop"
+
+// Folders of a tenant under the drop directory
+const (
+	inboxDir     = "inbox"
+	processedDir = "processed"
+	failedDir    = "failed"
+)
+
+// schemaNamePattern matches the schema names tenants are created with, so a schema name can
+// never leave the drop directory
+var schemaNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// fileDropColumns are the columns of a result file; unit and resulted_at may be left empty
+var fileDropColumns = []string{"barcode", "test_code", "value", "unit", "resulted_at"}
+
+// FileDropSource is a domain.AnalyzerSource reading CSV files dropped into a folder, standing in
+// for an instrument interface during development and testing. Each tenant has its own folder:
+//
+//	<dir>/<schema>/inbox/      result files waiting to be imported
+//	<dir>/<schema>/processed/  imported files, each with a <file>.report.json
+//	<dir>/<schema>/failed/     files that could not be read, each with a <file>.report.json
+//
+// A file has the header barcode,test_code,value,unit,resulted_at with resulted_at in RFC 3339.
+// The instrument is the part of the file name before the first underscore, e.g. cobas for
+// cobas_20250601_0930.csv. Writers should create files under another extension and rename them
+// to .csv once complete, since every .csv file in the inbox is read.
+type FileDropSource struct {
+	dir string
+}
+
+// NewFileDropSource creates a file-drop source rooted at dir
+func NewFileDropSource(dir string) *FileDropSource {
+	return &FileDropSource{dir: dir}
+}
+
+// Fetch reads the result files in the inbox of the tenant in name order. A missing inbox has no
+// batches; a file that cannot be parsed is returned as a batch with Err set.
+func (f *FileDropSource) Fetch(schemaName string) ([]domain.AnalyzerBatch, error) {
+	inbox, err := f.tenantDir(schemaName, inboxDir)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(inbox)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read analyzer inbox: %w", err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if entry.Type().IsRegular() && strings.EqualFold(filepath.Ext(entry.Name()), ".csv") {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	batches := make([]domain.AnalyzerBatch, 0, len(names))
+	for _, name := range names {
+		batch := domain.AnalyzerBatch{ID: name, Instrument: instrumentName(name)}
+		batch.Results, batch.Err = readResultFile(filepath.Join(inbox, name))
+		if batch.Err != nil {
+			batch.Results = nil
+		}
+		batches = append(batches, batch)
+	}
+	return batches, nil
+}
+
+// Complete moves the file of the batch out of the inbox, to failed when it could not be read
+// and to processed otherwise, and writes the report next to it
+func (f *FileDropSource) Complete(schemaName string, report *domain.LabImportBatchReport) error {
+	if report.BatchID == "" || filepath.Base(report.BatchID) != report.BatchID {
+		return fmt.Errorf("invalid analyzer batch %q", report.BatchID)
+	}
+	inbox, err := f.tenantDir(schemaName, inboxDir)
+	if err != nil {
+		return err
+	}
+	target := processedDir
+	if report.Error != "" {
+		target = failedDir
+	}
+	targetDir, err := f.tenantDir(schemaName, target)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(targetDir, 0o750); err != nil {
+		return fmt.Errorf("failed to create %s folder: %w", target, err)
+	}
+
+	encoded, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode import report: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(targetDir, report.BatchID+".report.json"), encoded, 0o640); err != nil {
+		return fmt.Errorf("failed to write import report: %w", err)
+	}
+	if err := os.Rename(filepath.Join(inbox, report.BatchID), filepath.Join(targetDir, report.BatchID)); err != nil {
+		return fmt.Errorf("failed to move %s to %s: %w", report.BatchID, target, err)
+	}
+	return nil
+}
+
+// tenantDir returns a folder of the tenant under the drop directory
+func (f *FileDropSource) tenantDir(schemaName string, folder string) (string, error) {
+	if !schemaNamePattern.MatchString(schemaName) {
+		return "", domain.ErrInvalidSchemaName
+	}
+	return filepath.Join(f.dir, schemaName, folder), nil
+}
+
+// instrumentName is the file name up to the first underscore or the extension
+func instrumentName(fileName string) string {
+	name := strings.TrimSuffix(fileName, filepath.Ext(fileName))
+	if i := strings.Index(name, "_"); i > 0 {
+		name = name[:i]
+	}
+	return name
+}
+
+// readResultFile parses a result file. Lines are numbered as in the file, the header being line 1.
+func readResultFile(path string) ([]domain.AnalyzerResult, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = len(fileDropColumns)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+	for i, column := range fileDropColumns {
+		if !strings.EqualFold(strings.TrimSpace(strings.TrimPrefix(header[i], "\ufeff")), column) {
+			return nil, fmt.Errorf("header must be %s", strings.Join(fileDropColumns, ","))
+		}
+	}
+
+	var results []domain.AnalyzerResult
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		result := domain.AnalyzerResult{
+			Line:     line,
+			Barcode:  strings.TrimSpace(record[0]),
+			TestCode: strings.TrimSpace(record[1]),
+			Value:    strings.TrimSpace(record[2]),
+			Unit:     strings.TrimSpace(record[3]),
+		}
+		if resultedAt := strings.TrimSpace(record[4]); resultedAt != "" {
+			at, err := time.Parse(time.RFC3339, resultedAt)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: resulted_at must be in RFC 3339 format", line)
+			}
+			result.ResultedAt = &at
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
@@ -0,0 +1,21 @@
+package analyzer
+
+import (
+	"fmt"
+
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+// NewSource creates the analyzer source named by provider. dir is the drop folder of the
+// filedrop provider.
+func NewSource(provider string, dir string) (domain.AnalyzerSource, error) {
+	switch provider {
+	case FileDropProvider:
+		if dir == "" {
+			return nil, fmt.Errorf("the %s analyzer provider needs a directory", provider)
+		}
+		return NewFileDropSource(dir), nil
+	default:
+		return nil, fmt.Errorf("unknown analyzer provider %q", provider)
+	}
+}
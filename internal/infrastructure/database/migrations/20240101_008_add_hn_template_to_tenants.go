@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+)
+
+// Migration_20240101_008_AddHNTemplateToTenants adds the per-tenant HN template
+func Migration_20240101_008_AddHNTemplateToTenants() MigrationDefinition {
+	return MigrationDefinition{
+		Version: "20240101_008",
+		Name:    "add_hn_template_to_tenants",
+		Up: func(db *gorm.DB) error {
+			// Existing tenants keep the original HOSPITALCODE-00000001 format
+			if !db.Migrator().HasColumn(&tenantTable{}, "hn_template") {
+				if err := db.Exec("ALTER TABLE tenants ADD COLUMN hn_template VARCHAR(100) NOT NULL DEFAULT '{HOSP}-{SEQ:8}'").Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(db *gorm.DB) error {
+			if db.Migrator().HasColumn(&tenantTable{}, "hn_template") {
+				if err := db.Exec("ALTER TABLE tenants DROP COLUMN hn_template").Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
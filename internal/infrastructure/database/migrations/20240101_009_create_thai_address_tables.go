@@ -0,0 +1,123 @@
+package migrations
+
+import (
+	"embed"
+	"encoding/csv"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// thaiAddressData holds the bundled province, district and subdistrict dataset.
+// The files follow the DOPA administrative codes, so a newer export can replace them.
+//
+//go:embed data/thai_provinces.csv data/thai_districts.csv data/thai_subdistricts.csv
+var thaiAddressData embed.FS
+
+// referenceSeedBatchSize bounds the rows inserted per statement when seeding a reference table
+const referenceSeedBatchSize = 500
+
+// Migration_20240101_009_CreateThaiAddressTables creates the public reference tables
+// for structured Thai addresses and loads the bundled dataset
+func Migration_20240101_009_CreateThaiAddressTables() MigrationDefinition {
+	return MigrationDefinition{
+		Version: "20240101_009",
+		Name:    "create_thai_address_tables",
+		Up: func(db *gorm.DB) error {
+			statements := []string{
+				`CREATE TABLE IF NOT EXISTS thai_provinces (
+					code VARCHAR(2) PRIMARY KEY,
+					name_th VARCHAR(100) NOT NULL,
+					name_en VARCHAR(100) NOT NULL
+				)`,
+				`CREATE TABLE IF NOT EXISTS thai_districts (
+					code VARCHAR(4) PRIMARY KEY,
+					province_code VARCHAR(2) NOT NULL REFERENCES thai_provinces(code),
+					name_th VARCHAR(100) NOT NULL,
+					name_en VARCHAR(100) NOT NULL
+				)`,
+				`CREATE TABLE IF NOT EXISTS thai_subdistricts (
+					code VARCHAR(6) PRIMARY KEY,
+					district_code VARCHAR(4) NOT NULL REFERENCES thai_districts(code),
+					name_th VARCHAR(100) NOT NULL,
+					name_en VARCHAR(100) NOT NULL,
+					postal_code VARCHAR(5) NOT NULL
+				)`,
+				"CREATE INDEX IF NOT EXISTS idx_thai_districts_province_code ON thai_districts(province_code)",
+				"CREATE INDEX IF NOT EXISTS idx_thai_subdistricts_district_code ON thai_subdistricts(district_code)",
+				"CREATE INDEX IF NOT EXISTS idx_thai_subdistricts_postal_code ON thai_subdistricts(postal_code)",
+			}
+			for _, statement := range statements {
+				if err := db.Exec(statement).Error; err != nil {
+					return err
+				}
+			}
+
+			// Parents first so the foreign keys hold
+			seeds := []struct {
+				table   string
+				file    string
+				columns []string
+			}{
+				{"thai_provinces", "data/thai_provinces.csv", []string{"code", "name_th", "name_en"}},
+				{"thai_districts", "data/thai_districts.csv", []string{"code", "province_code", "name_th", "name_en"}},
+				{"thai_subdistricts", "data/thai_subdistricts.csv", []string{"code", "district_code", "name_th", "name_en", "postal_code"}},
+			}
+			for _, seed := range seeds {
+				if err := seedReferenceTable(db, thaiAddressData, seed.table, seed.file, seed.columns); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(db *gorm.DB) error {
+			for _, table := range []string{"thai_subdistricts", "thai_districts", "thai_provinces"} {
+				if err := db.Exec("DROP TABLE IF EXISTS " + table).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// seedReferenceTable upserts the rows of a bundled CSV file keyed by code, whose header must
+// match columns. Empty cells are loaded as NULL.
+func seedReferenceTable(db *gorm.DB, data fs.FS, table string, file string, columns []string) error {
+	f, err := data.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	if len(records) == 0 || strings.Join(records[0], ",") != strings.Join(columns, ",") {
+		return fmt.Errorf("unexpected header in %s, want %s", file, strings.Join(columns, ","))
+	}
+
+	rows := make([]map[string]interface{}, 0, len(records)-1)
+	for _, record := range records[1:] {
+		row := make(map[string]interface{}, len(columns))
+		for i, column := range columns {
+			if value := strings.TrimSpace(record[i]); value != "" {
+				row[column] = value
+			} else {
+				row[column] = nil
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return db.Table(table).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "code"}},
+			DoUpdates: clause.AssignmentColumns(columns[1:]),
+		}).
+		CreateInBatches(rows, referenceSeedBatchSize).Error
+}
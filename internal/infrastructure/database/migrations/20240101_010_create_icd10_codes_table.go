@@ -0,0 +1,51 @@
+package migrations
+
+import (
+	"embed"
+
+	"gorm.io/gorm"
+)
+
+// icd10Data holds the bundled ICD-10-TM catalogue. Rows are ordered so every parent precedes
+// its children; a full export from the Thai Health Coding Center can replace the file.
+//
+//go:embed data/icd10_tm.csv
+var icd10Data embed.FS
+
+// Migration_20240101_010_CreateICD10CodesTable creates the public ICD-10 catalogue shared by
+// all tenants and loads the bundled codes. A code is billable when it has no children.
+func Migration_20240101_010_CreateICD10CodesTable() MigrationDefinition {
+	return MigrationDefinition{
+		Version: "20240101_010",
+		Name:    "create_icd10_codes_table",
+		Up: func(db *gorm.DB) error {
+			statements := []string{
+				"CREATE EXTENSION IF NOT EXISTS pg_trgm WITH SCHEMA public",
+				`CREATE TABLE IF NOT EXISTS icd10_codes (
+					code VARCHAR(8) PRIMARY KEY,
+					parent_code VARCHAR(8) REFERENCES icd10_codes(code),
+					description VARCHAR(255) NOT NULL,
+					billable BOOLEAN NOT NULL DEFAULT FALSE
+				)`,
+				"CREATE INDEX IF NOT EXISTS idx_icd10_codes_parent_code ON icd10_codes(parent_code)",
+				"CREATE INDEX IF NOT EXISTS idx_icd10_codes_code_pattern ON icd10_codes(code varchar_pattern_ops)",
+				"CREATE INDEX IF NOT EXISTS idx_icd10_codes_description_trgm ON icd10_codes USING GIN (description public.gin_trgm_ops)",
+			}
+			for _, statement := range statements {
+				if err := db.Exec(statement).Error; err != nil {
+					return err
+				}
+			}
+
+			if err := seedReferenceTable(db, icd10Data, "icd10_codes", "data/icd10_tm.csv", []string{"code", "parent_code", "description"}); err != nil {
+				return err
+			}
+			return db.Exec(`UPDATE icd10_codes c SET billable = NOT EXISTS (
+				SELECT 1 FROM icd10_codes child WHERE child.parent_code = c.code
+			)`).Error
+		},
+		Down: func(db *gorm.DB) error {
+			return db.Exec("DROP TABLE IF EXISTS icd10_codes").Error
+		},
+	}
+}
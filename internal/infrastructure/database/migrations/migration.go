@@ -22,6 +22,9 @@ func GetAllMigrations() []MigrationDefinition {
 	return []MigrationDefinition{
 		Migration_20240101_005_CreateTenantsTable(),
 		Migration_20240101_007_AddHospitalFieldsToTenants(),
+		Migration_20240101_008_AddHNTemplateToTenants(),
+		Migration_20240101_009_CreateThaiAddressTables(),
+		Migration_20240101_010_CreateICD10CodesTable(),
 	}
 }
 
@@ -33,5 +36,8 @@ func GetAllMigrationsIncludingLegacy() []MigrationDefinition {
 		Migration_20240101_003_CreatePatientsTable(),
 		Migration_20240101_005_CreateTenantsTable(),
 		Migration_20240101_007_AddHospitalFieldsToTenants(),
+		Migration_20240101_008_AddHNTemplateToTenants(),
+		Migration_20240101_009_CreateThaiAddressTables(),
+		Migration_20240101_010_CreateICD10CodesTable(),
 	}
 }
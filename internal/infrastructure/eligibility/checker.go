@@ -0,0 +1,17 @@
+package eligibility
+
+import (
+	"fmt"
+
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+// NewChecker creates the eligibility checker named by provider
+func NewChecker(provider string) (domain.EligibilityChecker, error) {
+	switch provider {
+	case FakeSource:
+		return NewFakeChecker(), nil
+	default:
+		return nil, fmt.Errorf("unknown eligibility provider %q", provider)
+	}
+}
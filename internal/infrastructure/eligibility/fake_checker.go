@@ -0,0 +1,63 @@
+package eligibility
+
+import (
+	"sync"
+	"time"
+
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+// FakeSource is the source reported by results of the fake checker
+const FakeSource = "fake"
+
+// FakeChecker is an in-process domain.EligibilityChecker for development and tests. It answers
+// without calling the NHSO: people are eligible for the scheme asked about unless a result or an
+// error has been set for their national ID.
+type FakeChecker struct {
+	mu      sync.RWMutex
+	results map[string]domain.EligibilityResult
+	errors  map[string]error
+}
+
+// NewFakeChecker creates a fake checker that finds everyone eligible
+func NewFakeChecker() *FakeChecker {
+	return &FakeChecker{
+		results: make(map[string]domain.EligibilityResult),
+		errors:  make(map[string]error),
+	}
+}
+
+// SetResult makes checks for nationalID return result, such as a person who is not eligible
+func (f *FakeChecker) SetResult(nationalID string, result domain.EligibilityResult) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.results[nationalID] = result
+}
+
+// SetError makes checks for nationalID fail with err, as when the payer service is down
+func (f *FakeChecker) SetError(nationalID string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.errors[nationalID] = err
+}
+
+func (f *FakeChecker) Check(query *domain.EligibilityQuery) (*domain.EligibilityResult, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if err, ok := f.errors[query.NationalID]; ok {
+		return nil, err
+	}
+
+	result, ok := f.results[query.NationalID]
+	if !ok {
+		result = domain.EligibilityResult{
+			Eligible: true,
+			Scheme:   query.Scheme,
+			Message:  "eligible (fake checker, no payer was contacted)",
+		}
+	}
+	result.CheckedAt = time.Now()
+	result.Source = FakeSource
+	return &result, nil
+}
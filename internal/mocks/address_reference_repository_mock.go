@@ -0,0 +1,63 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+// MockAddressReferenceRepository is a mock implementation of domain.AddressReferenceRepository
+type MockAddressReferenceRepository struct {
+	mock.Mock
+}
+
+func NewMockAddressReferenceRepository() *MockAddressReferenceRepository {
+	return &MockAddressReferenceRepository{}
+}
+
+func (m *MockAddressReferenceRepository) ListProvinces() ([]domain.Province, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Province), args.Error(1)
+}
+
+func (m *MockAddressReferenceRepository) ListDistricts(provinceCode string) ([]domain.District, error) {
+	args := m.Called(provinceCode)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.District), args.Error(1)
+}
+
+func (m *MockAddressReferenceRepository) ListSubdistricts(districtCode string) ([]domain.Subdistrict, error) {
+	args := m.Called(districtCode)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Subdistrict), args.Error(1)
+}
+
+func (m *MockAddressReferenceRepository) GetLocation(subdistrictCode string) (*domain.AddressLocation, error) {
+	args := m.Called(subdistrictCode)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.AddressLocation), args.Error(1)
+}
+
+func (m *MockAddressReferenceRepository) FindByPostalCode(postalCode string) ([]domain.AddressLocation, error) {
+	args := m.Called(postalCode)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.AddressLocation), args.Error(1)
+}
+
+func (m *MockAddressReferenceRepository) Search(query string, limit int) ([]domain.AddressLocation, error) {
+	args := m.Called(query, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.AddressLocation), args.Error(1)
+}
@@ -0,0 +1,55 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+// MockAddressService is a mock implementation of domain.AddressService
+type MockAddressService struct {
+	mock.Mock
+}
+
+func NewMockAddressService() *MockAddressService {
+	return &MockAddressService{}
+}
+
+func (m *MockAddressService) ListProvinces() ([]domain.Province, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Province), args.Error(1)
+}
+
+func (m *MockAddressService) ListDistricts(provinceCode string) ([]domain.District, error) {
+	args := m.Called(provinceCode)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.District), args.Error(1)
+}
+
+func (m *MockAddressService) ListSubdistricts(districtCode string) ([]domain.Subdistrict, error) {
+	args := m.Called(districtCode)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Subdistrict), args.Error(1)
+}
+
+func (m *MockAddressService) FindByPostalCode(postalCode string) ([]domain.AddressLocation, error) {
+	args := m.Called(postalCode)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.AddressLocation), args.Error(1)
+}
+
+func (m *MockAddressService) Autocomplete(req *domain.AddressSearchRequest) ([]domain.AddressLocation, error) {
+	args := m.Called(req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.AddressLocation), args.Error(1)
+}
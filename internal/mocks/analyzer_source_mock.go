@@ -0,0 +1,28 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+// MockAnalyzerSource is a mock implementation of domain.AnalyzerSource
+type MockAnalyzerSource struct {
+	mock.Mock
+}
+
+func NewMockAnalyzerSource() *MockAnalyzerSource {
+	return &MockAnalyzerSource{}
+}
+
+func (m *MockAnalyzerSource) Fetch(schemaName string) ([]domain.AnalyzerBatch, error) {
+	args := m.Called(schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.AnalyzerBatch), args.Error(1)
+}
+
+func (m *MockAnalyzerSource) Complete(schemaName string, report *domain.LabImportBatchReport) error {
+	args := m.Called(schemaName, report)
+	return args.Error(0)
+}
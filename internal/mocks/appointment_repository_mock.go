@@ -0,0 +1,62 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+// MockAppointmentRepository is a mock implementation of domain.AppointmentRepository
+type MockAppointmentRepository struct {
+	mock.Mock
+}
+
+func NewMockAppointmentRepository() *MockAppointmentRepository {
+	return &MockAppointmentRepository{}
+}
+
+func (m *MockAppointmentRepository) GetSlot(id uint, schemaName string) (*domain.AppointmentSlot, error) {
+	args := m.Called(id, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.AppointmentSlot), args.Error(1)
+}
+
+func (m *MockAppointmentRepository) GetByID(id uint, schemaName string) (*domain.Appointment, error) {
+	args := m.Called(id, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Appointment), args.Error(1)
+}
+
+func (m *MockAppointmentRepository) ListByPatient(patientID uint, schemaName string) ([]domain.Appointment, error) {
+	args := m.Called(patientID, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Appointment), args.Error(1)
+}
+
+func (m *MockAppointmentRepository) Create(appointment *domain.Appointment, event *domain.AuditEvent, schemaName string) error {
+	args := m.Called(appointment, event, schemaName)
+	return args.Error(0)
+}
+
+func (m *MockAppointmentRepository) Reschedule(previous *domain.Appointment, replacement *domain.Appointment, event *domain.AuditEvent, schemaName string) error {
+	args := m.Called(previous, replacement, event, schemaName)
+	return args.Error(0)
+}
+
+func (m *MockAppointmentRepository) Cancel(appointment *domain.Appointment, event *domain.AuditEvent, schemaName string) error {
+	args := m.Called(appointment, event, schemaName)
+	return args.Error(0)
+}
+
+func (m *MockAppointmentRepository) Calendar(filter *domain.AppointmentCalendarFilter, schemaName string) ([]domain.CalendarEntry, error) {
+	args := m.Called(filter, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.CalendarEntry), args.Error(1)
+}
@@ -0,0 +1,63 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+// MockAppointmentService is a mock implementation of domain.AppointmentService
+type MockAppointmentService struct {
+	mock.Mock
+}
+
+func NewMockAppointmentService() *MockAppointmentService {
+	return &MockAppointmentService{}
+}
+
+func (m *MockAppointmentService) Book(req *domain.AppointmentBookRequest, actor *domain.Actor, schemaName string) (*domain.Appointment, error) {
+	args := m.Called(req, actor, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Appointment), args.Error(1)
+}
+
+func (m *MockAppointmentService) GetByID(id uint, actor *domain.Actor, schemaName string) (*domain.Appointment, error) {
+	args := m.Called(id, actor, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Appointment), args.Error(1)
+}
+
+func (m *MockAppointmentService) ListByPatient(patientID uint, actor *domain.Actor, schemaName string) ([]domain.Appointment, error) {
+	args := m.Called(patientID, actor, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Appointment), args.Error(1)
+}
+
+func (m *MockAppointmentService) Reschedule(id uint, req *domain.AppointmentRescheduleRequest, actor *domain.Actor, schemaName string) (*domain.Appointment, error) {
+	args := m.Called(id, req, actor, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Appointment), args.Error(1)
+}
+
+func (m *MockAppointmentService) Cancel(id uint, req *domain.AppointmentCancelRequest, actor *domain.Actor, schemaName string) (*domain.Appointment, error) {
+	args := m.Called(id, req, actor, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Appointment), args.Error(1)
+}
+
+func (m *MockAppointmentService) Calendar(req *domain.AppointmentCalendarRequest, actor *domain.Actor, schemaName string) (*domain.AppointmentCalendar, error) {
+	args := m.Called(req, actor, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.AppointmentCalendar), args.Error(1)
+}
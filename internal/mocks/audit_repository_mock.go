@@ -0,0 +1,36 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+// MockAuditRepository is a mock implementation of domain.AuditRepository
+type MockAuditRepository struct {
+	mock.Mock
+}
+
+func NewMockAuditRepository() *MockAuditRepository {
+	return &MockAuditRepository{}
+}
+
+func (m *MockAuditRepository) Append(events []*domain.AuditEvent, schemaName string) error {
+	args := m.Called(events, schemaName)
+	return args.Error(0)
+}
+
+func (m *MockAuditRepository) Query(filter *domain.AuditFilter, schemaName string) ([]domain.AuditEvent, int64, error) {
+	args := m.Called(filter, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(int64), args.Error(2)
+	}
+	return args.Get(0).([]domain.AuditEvent), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockAuditRepository) GetChain(afterID uint, limit int, schemaName string) ([]domain.AuditEvent, error) {
+	args := m.Called(afterID, limit, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.AuditEvent), args.Error(1)
+}
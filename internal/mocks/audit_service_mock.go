@@ -0,0 +1,31 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+// MockAuditService is a mock implementation of domain.AuditService
+type MockAuditService struct {
+	mock.Mock
+}
+
+func NewMockAuditService() *MockAuditService {
+	return &MockAuditService{}
+}
+
+func (m *MockAuditService) Query(filter *domain.AuditFilter, schemaName string) ([]domain.AuditEvent, int64, error) {
+	args := m.Called(filter, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(int64), args.Error(2)
+	}
+	return args.Get(0).([]domain.AuditEvent), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockAuditService) Verify(schemaName string) (*domain.AuditVerifyResult, error) {
+	args := m.Called(schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.AuditVerifyResult), args.Error(1)
+}
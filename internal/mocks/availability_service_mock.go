@@ -0,0 +1,44 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+// MockAvailabilityService is a mock implementation of domain.AvailabilityService
+type MockAvailabilityService struct {
+	mock.Mock
+}
+
+func NewMockAvailabilityService() *MockAvailabilityService {
+	return &MockAvailabilityService{}
+}
+
+func (m *MockAvailabilityService) List(doctorID uint, schemaName string) ([]domain.AvailabilityTemplate, error) {
+	args := m.Called(doctorID, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.AvailabilityTemplate), args.Error(1)
+}
+
+func (m *MockAvailabilityService) Create(req *domain.AvailabilityTemplateRequest, schemaName string) (*domain.AvailabilityTemplate, error) {
+	args := m.Called(req, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.AvailabilityTemplate), args.Error(1)
+}
+
+func (m *MockAvailabilityService) Expand(id uint, req *domain.AvailabilityExpandRequest, schemaName string) (*domain.AvailabilityExpandResult, error) {
+	args := m.Called(id, req, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.AvailabilityExpandResult), args.Error(1)
+}
+
+func (m *MockAvailabilityService) Delete(id uint, schemaName string) error {
+	args := m.Called(id, schemaName)
+	return args.Error(0)
+}
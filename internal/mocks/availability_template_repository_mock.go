@@ -0,0 +1,48 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+// MockAvailabilityTemplateRepository is a mock implementation of domain.AvailabilityTemplateRepository
+type MockAvailabilityTemplateRepository struct {
+	mock.Mock
+}
+
+func NewMockAvailabilityTemplateRepository() *MockAvailabilityTemplateRepository {
+	return &MockAvailabilityTemplateRepository{}
+}
+
+func (m *MockAvailabilityTemplateRepository) List(doctorID uint, schemaName string) ([]domain.AvailabilityTemplate, error) {
+	args := m.Called(doctorID, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.AvailabilityTemplate), args.Error(1)
+}
+
+func (m *MockAvailabilityTemplateRepository) GetByID(id uint, schemaName string) (*domain.AvailabilityTemplate, error) {
+	args := m.Called(id, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.AvailabilityTemplate), args.Error(1)
+}
+
+func (m *MockAvailabilityTemplateRepository) Create(template *domain.AvailabilityTemplate, slots []domain.AppointmentSlot, schemaName string) error {
+	args := m.Called(template, slots, schemaName)
+	return args.Error(0)
+}
+
+func (m *MockAvailabilityTemplateRepository) AddSlots(slots []domain.AppointmentSlot, schemaName string) (int, error) {
+	args := m.Called(slots, schemaName)
+	return args.Get(0).(int), args.Error(1)
+}
+
+func (m *MockAvailabilityTemplateRepository) Delete(template *domain.AvailabilityTemplate, from time.Time, schemaName string) error {
+	args := m.Called(template, from, schemaName)
+	return args.Error(0)
+}
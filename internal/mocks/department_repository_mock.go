@@ -0,0 +1,41 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+// MockDepartmentRepository is a mock implementation of domain.DepartmentRepository
+type MockDepartmentRepository struct {
+	mock.Mock
+}
+
+func NewMockDepartmentRepository() *MockDepartmentRepository {
+	return &MockDepartmentRepository{}
+}
+
+func (m *MockDepartmentRepository) List(includeInactive bool, schemaName string) ([]domain.Department, error) {
+	args := m.Called(includeInactive, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Department), args.Error(1)
+}
+
+func (m *MockDepartmentRepository) GetByID(id uint, schemaName string) (*domain.Department, error) {
+	args := m.Called(id, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Department), args.Error(1)
+}
+
+func (m *MockDepartmentRepository) Create(department *domain.Department, schemaName string) error {
+	args := m.Called(department, schemaName)
+	return args.Error(0)
+}
+
+func (m *MockDepartmentRepository) Update(department *domain.Department, schemaName string) error {
+	args := m.Called(department, schemaName)
+	return args.Error(0)
+}
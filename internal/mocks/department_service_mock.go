@@ -0,0 +1,39 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+// MockDepartmentService is a mock implementation of domain.DepartmentService
+type MockDepartmentService struct {
+	mock.Mock
+}
+
+func NewMockDepartmentService() *MockDepartmentService {
+	return &MockDepartmentService{}
+}
+
+func (m *MockDepartmentService) List(includeInactive bool, schemaName string) ([]domain.Department, error) {
+	args := m.Called(includeInactive, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Department), args.Error(1)
+}
+
+func (m *MockDepartmentService) Create(req *domain.DepartmentRequest, schemaName string) (*domain.Department, error) {
+	args := m.Called(req, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Department), args.Error(1)
+}
+
+func (m *MockDepartmentService) Update(id uint, req *domain.DepartmentRequest, schemaName string) (*domain.Department, error) {
+	args := m.Called(id, req, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Department), args.Error(1)
+}
@@ -0,0 +1,49 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+// MockDiagnosisRepository is a mock implementation of domain.DiagnosisRepository
+type MockDiagnosisRepository struct {
+	mock.Mock
+}
+
+func NewMockDiagnosisRepository() *MockDiagnosisRepository {
+	return &MockDiagnosisRepository{}
+}
+
+func (m *MockDiagnosisRepository) GetByID(encounterID uint, id uint, schemaName string) (*domain.Diagnosis, error) {
+	args := m.Called(encounterID, id, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Diagnosis), args.Error(1)
+}
+
+func (m *MockDiagnosisRepository) ListByEncounter(encounterID uint, schemaName string) ([]domain.Diagnosis, error) {
+	args := m.Called(encounterID, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Diagnosis), args.Error(1)
+}
+
+func (m *MockDiagnosisRepository) ListByPatient(patientID uint, schemaName string) ([]domain.Diagnosis, error) {
+	args := m.Called(patientID, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Diagnosis), args.Error(1)
+}
+
+func (m *MockDiagnosisRepository) Create(diagnosis *domain.Diagnosis, event *domain.AuditEvent, schemaName string) error {
+	args := m.Called(diagnosis, event, schemaName)
+	return args.Error(0)
+}
+
+func (m *MockDiagnosisRepository) Delete(diagnosis *domain.Diagnosis, event *domain.AuditEvent, schemaName string) error {
+	args := m.Called(diagnosis, event, schemaName)
+	return args.Error(0)
+}
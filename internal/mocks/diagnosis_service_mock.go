@@ -0,0 +1,44 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+// MockDiagnosisService is a mock implementation of domain.DiagnosisService
+type MockDiagnosisService struct {
+	mock.Mock
+}
+
+func NewMockDiagnosisService() *MockDiagnosisService {
+	return &MockDiagnosisService{}
+}
+
+func (m *MockDiagnosisService) Create(encounterID uint, req *domain.DiagnosisRequest, actor *domain.Actor, schemaName string) (*domain.Diagnosis, error) {
+	args := m.Called(encounterID, req, actor, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Diagnosis), args.Error(1)
+}
+
+func (m *MockDiagnosisService) ListByEncounter(encounterID uint, actor *domain.Actor, schemaName string) ([]domain.Diagnosis, error) {
+	args := m.Called(encounterID, actor, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Diagnosis), args.Error(1)
+}
+
+func (m *MockDiagnosisService) ListByPatient(patientID uint, actor *domain.Actor, schemaName string) ([]domain.Diagnosis, error) {
+	args := m.Called(patientID, actor, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Diagnosis), args.Error(1)
+}
+
+func (m *MockDiagnosisService) Delete(encounterID uint, id uint, actor *domain.Actor, schemaName string) error {
+	args := m.Called(encounterID, id, actor, schemaName)
+	return args.Error(0)
+}
@@ -0,0 +1,67 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+// MockDrugRepository is a mock implementation of domain.DrugRepository
+type MockDrugRepository struct {
+	mock.Mock
+}
+
+func NewMockDrugRepository() *MockDrugRepository {
+	return &MockDrugRepository{}
+}
+
+func (m *MockDrugRepository) List(query string, includeInactive bool, schemaName string) ([]domain.Drug, error) {
+	args := m.Called(query, includeInactive, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Drug), args.Error(1)
+}
+
+func (m *MockDrugRepository) GetByID(id uint, schemaName string) (*domain.Drug, error) {
+	args := m.Called(id, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Drug), args.Error(1)
+}
+
+func (m *MockDrugRepository) Create(drug *domain.Drug, schemaName string) error {
+	args := m.Called(drug, schemaName)
+	return args.Error(0)
+}
+
+func (m *MockDrugRepository) Update(drug *domain.Drug, schemaName string) error {
+	args := m.Called(drug, schemaName)
+	return args.Error(0)
+}
+
+func (m *MockDrugRepository) ListInteractions(drugID uint, drugIDs []uint, schemaName string) ([]domain.DrugInteraction, error) {
+	args := m.Called(drugID, drugIDs, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.DrugInteraction), args.Error(1)
+}
+
+func (m *MockDrugRepository) GetInteraction(drugID uint, id uint, schemaName string) (*domain.DrugInteraction, error) {
+	args := m.Called(drugID, id, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.DrugInteraction), args.Error(1)
+}
+
+func (m *MockDrugRepository) CreateInteraction(interaction *domain.DrugInteraction, schemaName string) error {
+	args := m.Called(interaction, schemaName)
+	return args.Error(0)
+}
+
+func (m *MockDrugRepository) DeleteInteraction(interaction *domain.DrugInteraction, schemaName string) error {
+	args := m.Called(interaction, schemaName)
+	return args.Error(0)
+}
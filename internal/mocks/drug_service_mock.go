@@ -0,0 +1,68 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+// MockDrugService is a mock implementation of domain.DrugService
+type MockDrugService struct {
+	mock.Mock
+}
+
+func NewMockDrugService() *MockDrugService {
+	return &MockDrugService{}
+}
+
+func (m *MockDrugService) List(req *domain.DrugListRequest, schemaName string) ([]domain.Drug, error) {
+	args := m.Called(req, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Drug), args.Error(1)
+}
+
+func (m *MockDrugService) GetByID(id uint, schemaName string) (*domain.Drug, error) {
+	args := m.Called(id, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Drug), args.Error(1)
+}
+
+func (m *MockDrugService) Create(req *domain.DrugRequest, schemaName string) (*domain.Drug, error) {
+	args := m.Called(req, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Drug), args.Error(1)
+}
+
+func (m *MockDrugService) Update(id uint, req *domain.DrugRequest, schemaName string) (*domain.Drug, error) {
+	args := m.Called(id, req, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Drug), args.Error(1)
+}
+
+func (m *MockDrugService) ListInteractions(drugID uint, schemaName string) ([]domain.DrugInteraction, error) {
+	args := m.Called(drugID, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.DrugInteraction), args.Error(1)
+}
+
+func (m *MockDrugService) CreateInteraction(drugID uint, req *domain.DrugInteractionRequest, schemaName string) (*domain.DrugInteraction, error) {
+	args := m.Called(drugID, req, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.DrugInteraction), args.Error(1)
+}
+
+func (m *MockDrugService) DeleteInteraction(drugID uint, id uint, schemaName string) error {
+	args := m.Called(drugID, id, schemaName)
+	return args.Error(0)
+}
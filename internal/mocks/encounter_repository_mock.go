@@ -0,0 +1,49 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+// MockEncounterRepository is a mock implementation of domain.EncounterRepository
+type MockEncounterRepository struct {
+	mock.Mock
+}
+
+func NewMockEncounterRepository() *MockEncounterRepository {
+	return &MockEncounterRepository{}
+}
+
+func (m *MockEncounterRepository) Create(encounter *domain.Encounter, issuer *domain.HNIssuer, event *domain.AuditEvent, schemaName string) error {
+	args := m.Called(encounter, issuer, event, schemaName)
+	return args.Error(0)
+}
+
+func (m *MockEncounterRepository) GetByID(id uint, schemaName string) (*domain.Encounter, error) {
+	args := m.Called(id, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Encounter), args.Error(1)
+}
+
+func (m *MockEncounterRepository) ListByPatient(patientID uint, schemaName string) ([]domain.Encounter, error) {
+	args := m.Called(patientID, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Encounter), args.Error(1)
+}
+
+func (m *MockEncounterRepository) UpdateStatus(encounter *domain.Encounter, fromStatus string, event *domain.AuditEvent, schemaName string) error {
+	args := m.Called(encounter, fromStatus, event, schemaName)
+	return args.Error(0)
+}
+
+func (m *MockEncounterRepository) Queue(filter *domain.EncounterQueueFilter, schemaName string) ([]domain.EncounterQueueEntry, error) {
+	args := m.Called(filter, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.EncounterQueueEntry), args.Error(1)
+}
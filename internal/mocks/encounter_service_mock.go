@@ -0,0 +1,55 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+// MockEncounterService is a mock implementation of domain.EncounterService
+type MockEncounterService struct {
+	mock.Mock
+}
+
+func NewMockEncounterService() *MockEncounterService {
+	return &MockEncounterService{}
+}
+
+func (m *MockEncounterService) Create(req *domain.EncounterCreateRequest, actor *domain.Actor, schemaName string) (*domain.Encounter, error) {
+	args := m.Called(req, actor, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Encounter), args.Error(1)
+}
+
+func (m *MockEncounterService) GetByID(id uint, actor *domain.Actor, schemaName string) (*domain.Encounter, error) {
+	args := m.Called(id, actor, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Encounter), args.Error(1)
+}
+
+func (m *MockEncounterService) ListByPatient(patientID uint, actor *domain.Actor, schemaName string) ([]domain.Encounter, error) {
+	args := m.Called(patientID, actor, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Encounter), args.Error(1)
+}
+
+func (m *MockEncounterService) UpdateStatus(id uint, req *domain.EncounterStatusRequest, actor *domain.Actor, schemaName string) (*domain.Encounter, error) {
+	args := m.Called(id, req, actor, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Encounter), args.Error(1)
+}
+
+func (m *MockEncounterService) Queue(req *domain.EncounterQueueRequest, actor *domain.Actor, schemaName string) ([]domain.EncounterQueueEntry, error) {
+	args := m.Called(req, actor, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.EncounterQueueEntry), args.Error(1)
+}
@@ -0,0 +1,27 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+)
+
+// MockHNCounterRepository is a mock implementation of domain.HNCounterRepository
+type MockHNCounterRepository struct {
+	mock.Mock
+}
+
+func NewMockHNCounterRepository() *MockHNCounterRepository {
+	return &MockHNCounterRepository{}
+}
+
+func (m *MockHNCounterRepository) Current(period string, schemaName string) (uint64, error) {
+	args := m.Called(period, schemaName)
+	return args.Get(0).(uint64), args.Error(1)
+}
+
+func (m *MockHNCounterRepository) FindHNs(pattern string, schemaName string) ([]string, error) {
+	args := m.Called(pattern, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
@@ -0,0 +1,39 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+// MockHNService is a mock implementation of domain.HNService
+type MockHNService struct {
+	mock.Mock
+}
+
+func NewMockHNService() *MockHNService {
+	return &MockHNService{}
+}
+
+func (m *MockHNService) GetSettings(schemaName string) (*domain.HNSettings, error) {
+	args := m.Called(schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.HNSettings), args.Error(1)
+}
+
+func (m *MockHNService) Preview(req *domain.HNPreviewRequest, schemaName string) (*domain.HNPreview, error) {
+	args := m.Called(req, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.HNPreview), args.Error(1)
+}
+
+func (m *MockHNService) UpdateTemplate(req *domain.HNTemplateRequest, schemaName string) (*domain.HNSettings, error) {
+	args := m.Called(req, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.HNSettings), args.Error(1)
+}
@@ -0,0 +1,31 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+// MockICD10Repository is a mock implementation of domain.ICD10Repository
+type MockICD10Repository struct {
+	mock.Mock
+}
+
+func NewMockICD10Repository() *MockICD10Repository {
+	return &MockICD10Repository{}
+}
+
+func (m *MockICD10Repository) GetByCode(code string) (*domain.ICD10Code, error) {
+	args := m.Called(code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ICD10Code), args.Error(1)
+}
+
+func (m *MockICD10Repository) Search(filter *domain.ICD10SearchFilter) ([]domain.ICD10Code, error) {
+	args := m.Called(filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.ICD10Code), args.Error(1)
+}
@@ -0,0 +1,31 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+// MockICD10Service is a mock implementation of domain.ICD10Service
+type MockICD10Service struct {
+	mock.Mock
+}
+
+func NewMockICD10Service() *MockICD10Service {
+	return &MockICD10Service{}
+}
+
+func (m *MockICD10Service) GetByCode(code string) (*domain.ICD10Code, error) {
+	args := m.Called(code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ICD10Code), args.Error(1)
+}
+
+func (m *MockICD10Service) Search(req *domain.ICD10SearchRequest) ([]domain.ICD10Code, error) {
+	args := m.Called(req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.ICD10Code), args.Error(1)
+}
@@ -0,0 +1,35 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+// MockIdempotencyRepository is a mock implementation of domain.IdempotencyRepository
+type MockIdempotencyRepository struct {
+	mock.Mock
+}
+
+func NewMockIdempotencyRepository() *MockIdempotencyRepository {
+	return &MockIdempotencyRepository{}
+}
+
+func (m *MockIdempotencyRepository) Reserve(record *domain.IdempotencyKey, expiredBefore time.Time, schemaName string) (*domain.IdempotencyKey, error) {
+	args := m.Called(record, expiredBefore, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.IdempotencyKey), args.Error(1)
+}
+
+func (m *MockIdempotencyRepository) Complete(record *domain.IdempotencyKey, schemaName string) error {
+	args := m.Called(record, schemaName)
+	return args.Error(0)
+}
+
+func (m *MockIdempotencyRepository) Release(key string, schemaName string) error {
+	args := m.Called(key, schemaName)
+	return args.Error(0)
+}
@@ -0,0 +1,33 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+// MockIdempotencyService is a mock implementation of domain.IdempotencyService
+type MockIdempotencyService struct {
+	mock.Mock
+}
+
+func NewMockIdempotencyService() *MockIdempotencyService {
+	return &MockIdempotencyService{}
+}
+
+func (m *MockIdempotencyService) Begin(record *domain.IdempotencyKey, schemaName string) (*domain.IdempotencyKey, error) {
+	args := m.Called(record, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.IdempotencyKey), args.Error(1)
+}
+
+func (m *MockIdempotencyService) Complete(record *domain.IdempotencyKey, schemaName string) error {
+	args := m.Called(record, schemaName)
+	return args.Error(0)
+}
+
+func (m *MockIdempotencyService) Release(record *domain.IdempotencyKey, schemaName string) error {
+	args := m.Called(record, schemaName)
+	return args.Error(0)
+}
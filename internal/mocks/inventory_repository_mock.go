@@ -0,0 +1,140 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+// MockInventoryRepository is a mock implementation of domain.InventoryRepository
+type MockInventoryRepository struct {
+	mock.Mock
+}
+
+func NewMockInventoryRepository() *MockInventoryRepository {
+	return &MockInventoryRepository{}
+}
+
+func (m *MockInventoryRepository) ListLocations(includeInactive bool, schemaName string) ([]domain.StockLocation, error) {
+	args := m.Called(includeInactive, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.StockLocation), args.Error(1)
+}
+
+func (m *MockInventoryRepository) GetLocation(id uint, schemaName string) (*domain.StockLocation, error) {
+	args := m.Called(id, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.StockLocation), args.Error(1)
+}
+
+func (m *MockInventoryRepository) CreateLocation(location *domain.StockLocation, schemaName string) error {
+	args := m.Called(location, schemaName)
+	return args.Error(0)
+}
+
+func (m *MockInventoryRepository) UpdateLocation(location *domain.StockLocation, schemaName string) error {
+	args := m.Called(location, schemaName)
+	return args.Error(0)
+}
+
+func (m *MockInventoryRepository) ListReorderLevels(locationID uint, schemaName string) ([]domain.StockReorderLevel, error) {
+	args := m.Called(locationID, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.StockReorderLevel), args.Error(1)
+}
+
+func (m *MockInventoryRepository) SaveReorderLevel(level *domain.StockReorderLevel, schemaName string) error {
+	args := m.Called(level, schemaName)
+	return args.Error(0)
+}
+
+func (m *MockInventoryRepository) ListLots(filter *domain.StockLotFilter, schemaName string) ([]domain.StockLot, error) {
+	args := m.Called(filter, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.StockLot), args.Error(1)
+}
+
+func (m *MockInventoryRepository) GetLot(id uint, schemaName string) (*domain.StockLot, error) {
+	args := m.Called(id, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.StockLot), args.Error(1)
+}
+
+func (m *MockInventoryRepository) Receive(receipt *domain.GoodsReceipt, schemaName string) error {
+	args := m.Called(receipt, schemaName)
+	return args.Error(0)
+}
+
+func (m *MockInventoryRepository) GetReceipt(id uint, schemaName string) (*domain.GoodsReceipt, error) {
+	args := m.Called(id, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.GoodsReceipt), args.Error(1)
+}
+
+func (m *MockInventoryRepository) ListReceipts(filter *domain.GoodsReceiptFilter, schemaName string) ([]domain.GoodsReceipt, error) {
+	args := m.Called(filter, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.GoodsReceipt), args.Error(1)
+}
+
+func (m *MockInventoryRepository) Adjust(movement *domain.StockMovement, schemaName string) error {
+	args := m.Called(movement, schemaName)
+	return args.Error(0)
+}
+
+func (m *MockInventoryRepository) DispenseOrder(order *domain.MedicationOrder, fromStatus string, locationID uint, today time.Time, event *domain.AuditEvent, schemaName string) ([]domain.StockMovement, error) {
+	args := m.Called(order, fromStatus, locationID, today, event, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.StockMovement), args.Error(1)
+}
+
+func (m *MockInventoryRepository) ListMovements(filter *domain.StockMovementFilter, schemaName string) ([]domain.StockMovement, error) {
+	args := m.Called(filter, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.StockMovement), args.Error(1)
+}
+
+func (m *MockInventoryRepository) Balance(locationID uint, drugID uint, before time.Time, schemaName string) (float64, error) {
+	args := m.Called(locationID, drugID, before, schemaName)
+	return args.Get(0).(float64), args.Error(1)
+}
+
+func (m *MockInventoryRepository) LowStock(locationID *uint, today time.Time, schemaName string) ([]domain.LowStockAlert, error) {
+	args := m.Called(locationID, today, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.LowStockAlert), args.Error(1)
+}
+
+func (m *MockInventoryRepository) NearExpiry(locationID *uint, before time.Time, schemaName string) ([]domain.NearExpiryAlert, error) {
+	args := m.Called(locationID, before, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.NearExpiryAlert), args.Error(1)
+}
+
+func (m *MockInventoryRepository) RebuildBalances(schemaName string) (int64, error) {
+	args := m.Called(schemaName)
+	return args.Get(0).(int64), args.Error(1)
+}
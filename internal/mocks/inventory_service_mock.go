@@ -0,0 +1,119 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+// MockInventoryService is a mock implementation of domain.InventoryService
+type MockInventoryService struct {
+	mock.Mock
+}
+
+func NewMockInventoryService() *MockInventoryService {
+	return &MockInventoryService{}
+}
+
+func (m *MockInventoryService) ListLocations(includeInactive bool, schemaName string) ([]domain.StockLocation, error) {
+	args := m.Called(includeInactive, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.StockLocation), args.Error(1)
+}
+
+func (m *MockInventoryService) CreateLocation(req *domain.StockLocationRequest, schemaName string) (*domain.StockLocation, error) {
+	args := m.Called(req, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.StockLocation), args.Error(1)
+}
+
+func (m *MockInventoryService) UpdateLocation(id uint, req *domain.StockLocationRequest, schemaName string) (*domain.StockLocation, error) {
+	args := m.Called(id, req, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.StockLocation), args.Error(1)
+}
+
+func (m *MockInventoryService) ListReorderLevels(locationID uint, schemaName string) ([]domain.StockReorderLevel, error) {
+	args := m.Called(locationID, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.StockReorderLevel), args.Error(1)
+}
+
+func (m *MockInventoryService) SetReorderLevel(locationID uint, req *domain.StockReorderLevelRequest, schemaName string) (*domain.StockReorderLevel, error) {
+	args := m.Called(locationID, req, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.StockReorderLevel), args.Error(1)
+}
+
+func (m *MockInventoryService) ListStock(req *domain.StockListRequest, schemaName string) ([]domain.StockLot, error) {
+	args := m.Called(req, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.StockLot), args.Error(1)
+}
+
+func (m *MockInventoryService) Receive(req *domain.GoodsReceiptRequest, actor *domain.Actor, schemaName string) (*domain.GoodsReceipt, error) {
+	args := m.Called(req, actor, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.GoodsReceipt), args.Error(1)
+}
+
+func (m *MockInventoryService) GetReceipt(id uint, schemaName string) (*domain.GoodsReceipt, error) {
+	args := m.Called(id, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.GoodsReceipt), args.Error(1)
+}
+
+func (m *MockInventoryService) ListReceipts(req *domain.GoodsReceiptListRequest, schemaName string) ([]domain.GoodsReceipt, error) {
+	args := m.Called(req, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.GoodsReceipt), args.Error(1)
+}
+
+func (m *MockInventoryService) Adjust(req *domain.StockAdjustmentRequest, actor *domain.Actor, schemaName string) (*domain.StockMovement, error) {
+	args := m.Called(req, actor, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.StockMovement), args.Error(1)
+}
+
+func (m *MockInventoryService) StockCard(req *domain.StockCardRequest, schemaName string) (*domain.StockCard, error) {
+	args := m.Called(req, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.StockCard), args.Error(1)
+}
+
+func (m *MockInventoryService) Alerts(req *domain.StockAlertRequest, schemaName string) (*domain.StockAlerts, error) {
+	args := m.Called(req, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.StockAlerts), args.Error(1)
+}
+
+func (m *MockInventoryService) RebuildBalances(schemaName string) (*domain.StockRebuildResult, error) {
+	args := m.Called(schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.StockRebuildResult), args.Error(1)
+}
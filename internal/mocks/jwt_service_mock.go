@@ -1,6 +1,8 @@
 package mocks
 
 import (
+	"time"
+
 	"github.com/stretchr/testify/mock"
 	"github.com/wichai2002/his_v1/pkg/jwt"
 )
@@ -14,9 +16,12 @@ func NewMockJWTService() *MockJWTService {
 	return &MockJWTService{}
 }
 
-func (m *MockJWTService) GenerateToken(userID uint, username string, isAdmin bool, schemaName string) (string, error) {
-	args := m.Called(userID, username, isAdmin, schemaName)
-	return args.String(0), args.Error(1)
+func (m *MockJWTService) GenerateToken(userID uint, username string, isAdmin bool, roles []string, permissions []string, schemaName string) (*jwt.AccessToken, error) {
+	args := m.Called(userID, username, isAdmin, roles, permissions, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*jwt.AccessToken), args.Error(1)
 }
 
 func (m *MockJWTService) ValidateToken(tokenString string) (*jwt.Claims, error) {
@@ -26,3 +31,8 @@ func (m *MockJWTService) ValidateToken(tokenString string) (*jwt.Claims, error)
 	}
 	return args.Get(0).(*jwt.Claims), args.Error(1)
 }
+
+func (m *MockJWTService) GenerateRefreshToken() (string, time.Time, error) {
+	args := m.Called()
+	return args.String(0), args.Get(1).(time.Time), args.Error(2)
+}
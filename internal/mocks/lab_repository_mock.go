@@ -0,0 +1,101 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+// MockLabRepository is a mock implementation of domain.LabRepository
+type MockLabRepository struct {
+	mock.Mock
+}
+
+func NewMockLabRepository() *MockLabRepository {
+	return &MockLabRepository{}
+}
+
+func (m *MockLabRepository) ListTests(query string, includeInactive bool, schemaName string) ([]domain.LabTest, error) {
+	args := m.Called(query, includeInactive, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.LabTest), args.Error(1)
+}
+
+func (m *MockLabRepository) GetTest(id uint, schemaName string) (*domain.LabTest, error) {
+	args := m.Called(id, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.LabTest), args.Error(1)
+}
+
+func (m *MockLabRepository) ListTestsByIDs(ids []uint, schemaName string) ([]domain.LabTest, error) {
+	args := m.Called(ids, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.LabTest), args.Error(1)
+}
+
+func (m *MockLabRepository) CreateTest(test *domain.LabTest, schemaName string) error {
+	args := m.Called(test, schemaName)
+	return args.Error(0)
+}
+
+func (m *MockLabRepository) UpdateTest(test *domain.LabTest, schemaName string) error {
+	args := m.Called(test, schemaName)
+	return args.Error(0)
+}
+
+func (m *MockLabRepository) GetOrder(id uint, schemaName string) (*domain.LabOrder, error) {
+	args := m.Called(id, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.LabOrder), args.Error(1)
+}
+
+func (m *MockLabRepository) ListOrdersByEncounter(encounterID uint, schemaName string) ([]domain.LabOrder, error) {
+	args := m.Called(encounterID, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.LabOrder), args.Error(1)
+}
+
+func (m *MockLabRepository) ListOrdersByPatient(patientID uint, status string, schemaName string) ([]domain.LabOrder, error) {
+	args := m.Called(patientID, status, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.LabOrder), args.Error(1)
+}
+
+func (m *MockLabRepository) CreateOrder(order *domain.LabOrder, event *domain.AuditEvent, schemaName string) error {
+	args := m.Called(order, event, schemaName)
+	return args.Error(0)
+}
+
+func (m *MockLabRepository) SaveOrder(order *domain.LabOrder, version time.Time, specimens []*domain.LabSpecimen, items []*domain.LabOrderItem, event *domain.AuditEvent, schemaName string) error {
+	args := m.Called(order, version, specimens, items, event, schemaName)
+	return args.Error(0)
+}
+
+func (m *MockLabRepository) GetSpecimenByBarcode(barcode string, schemaName string) (*domain.LabSpecimen, error) {
+	args := m.Called(barcode, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.LabSpecimen), args.Error(1)
+}
+
+func (m *MockLabRepository) ListSpecimens(filter *domain.LabSpecimenFilter, schemaName string) ([]domain.LabSpecimenEntry, error) {
+	args := m.Called(filter, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.LabSpecimenEntry), args.Error(1)
+}
@@ -0,0 +1,143 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+// MockLabService is a mock implementation of domain.LabService
+type MockLabService struct {
+	mock.Mock
+}
+
+func NewMockLabService() *MockLabService {
+	return &MockLabService{}
+}
+
+func (m *MockLabService) ListTests(req *domain.LabTestListRequest, schemaName string) ([]domain.LabTest, error) {
+	args := m.Called(req, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.LabTest), args.Error(1)
+}
+
+func (m *MockLabService) GetTest(id uint, schemaName string) (*domain.LabTest, error) {
+	args := m.Called(id, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.LabTest), args.Error(1)
+}
+
+func (m *MockLabService) CreateTest(req *domain.LabTestRequest, schemaName string) (*domain.LabTest, error) {
+	args := m.Called(req, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.LabTest), args.Error(1)
+}
+
+func (m *MockLabService) UpdateTest(id uint, req *domain.LabTestRequest, schemaName string) (*domain.LabTest, error) {
+	args := m.Called(id, req, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.LabTest), args.Error(1)
+}
+
+func (m *MockLabService) Create(encounterID uint, req *domain.LabOrderRequest, actor *domain.Actor, schemaName string) (*domain.LabOrder, error) {
+	args := m.Called(encounterID, req, actor, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.LabOrder), args.Error(1)
+}
+
+func (m *MockLabService) GetByID(id uint, actor *domain.Actor, schemaName string) (*domain.LabOrder, error) {
+	args := m.Called(id, actor, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.LabOrder), args.Error(1)
+}
+
+func (m *MockLabService) ListByEncounter(encounterID uint, actor *domain.Actor, schemaName string) ([]domain.LabOrder, error) {
+	args := m.Called(encounterID, actor, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.LabOrder), args.Error(1)
+}
+
+func (m *MockLabService) ListByPatient(patientID uint, req *domain.LabOrderListRequest, actor *domain.Actor, schemaName string) ([]domain.LabOrder, error) {
+	args := m.Called(patientID, req, actor, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.LabOrder), args.Error(1)
+}
+
+func (m *MockLabService) Cancel(id uint, req *domain.LabCancelRequest, actor *domain.Actor, schemaName string) (*domain.LabOrder, error) {
+	args := m.Called(id, req, actor, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.LabOrder), args.Error(1)
+}
+
+func (m *MockLabService) ListSpecimens(req *domain.LabSpecimenListRequest, schemaName string) ([]domain.LabSpecimenEntry, error) {
+	args := m.Called(req, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.LabSpecimenEntry), args.Error(1)
+}
+
+func (m *MockLabService) Label(barcode string, actor *domain.Actor, schemaName string) (*domain.LabSpecimenLabel, error) {
+	args := m.Called(barcode, actor, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.LabSpecimenLabel), args.Error(1)
+}
+
+func (m *MockLabService) Collect(barcode string, actor *domain.Actor, schemaName string) (*domain.LabOrder, error) {
+	args := m.Called(barcode, actor, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.LabOrder), args.Error(1)
+}
+
+func (m *MockLabService) Receive(barcode string, actor *domain.Actor, schemaName string) (*domain.LabOrder, error) {
+	args := m.Called(barcode, actor, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.LabOrder), args.Error(1)
+}
+
+func (m *MockLabService) Reject(barcode string, req *domain.LabSpecimenRejectRequest, actor *domain.Actor, schemaName string) (*domain.LabOrder, error) {
+	args := m.Called(barcode, req, actor, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.LabOrder), args.Error(1)
+}
+
+func (m *MockLabService) RecordResults(id uint, req *domain.LabResultRequest, actor *domain.Actor, schemaName string) (*domain.LabOrder, error) {
+	args := m.Called(id, req, actor, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.LabOrder), args.Error(1)
+}
+
+func (m *MockLabService) ImportFromAnalyzer(actor *domain.Actor, schemaName string) (*domain.LabImportSummary, error) {
+	args := m.Called(actor, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.LabImportSummary), args.Error(1)
+}
@@ -0,0 +1,51 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+// MockMedicationOrderRepository is a mock implementation of domain.MedicationOrderRepository
+type MockMedicationOrderRepository struct {
+	mock.Mock
+}
+
+func NewMockMedicationOrderRepository() *MockMedicationOrderRepository {
+	return &MockMedicationOrderRepository{}
+}
+
+func (m *MockMedicationOrderRepository) GetByID(id uint, schemaName string) (*domain.MedicationOrder, error) {
+	args := m.Called(id, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.MedicationOrder), args.Error(1)
+}
+
+func (m *MockMedicationOrderRepository) ListByEncounter(encounterID uint, schemaName string) ([]domain.MedicationOrder, error) {
+	args := m.Called(encounterID, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.MedicationOrder), args.Error(1)
+}
+
+func (m *MockMedicationOrderRepository) ListByPatient(patientID uint, activeAt *time.Time, schemaName string) ([]domain.MedicationOrder, error) {
+	args := m.Called(patientID, activeAt, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.MedicationOrder), args.Error(1)
+}
+
+func (m *MockMedicationOrderRepository) Create(order *domain.MedicationOrder, events []*domain.AuditEvent, schemaName string) error {
+	args := m.Called(order, events, schemaName)
+	return args.Error(0)
+}
+
+func (m *MockMedicationOrderRepository) UpdateStatus(order *domain.MedicationOrder, fromStatus string, event *domain.AuditEvent, schemaName string) error {
+	args := m.Called(order, fromStatus, event, schemaName)
+	return args.Error(0)
+}
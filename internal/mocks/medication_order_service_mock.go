@@ -0,0 +1,71 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+// MockMedicationOrderService is a mock implementation of domain.MedicationOrderService
+type MockMedicationOrderService struct {
+	mock.Mock
+}
+
+func NewMockMedicationOrderService() *MockMedicationOrderService {
+	return &MockMedicationOrderService{}
+}
+
+func (m *MockMedicationOrderService) Create(encounterID uint, req *domain.MedicationOrderRequest, actor *domain.Actor, schemaName string) (*domain.MedicationOrder, error) {
+	args := m.Called(encounterID, req, actor, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.MedicationOrder), args.Error(1)
+}
+
+func (m *MockMedicationOrderService) GetByID(id uint, actor *domain.Actor, schemaName string) (*domain.MedicationOrder, error) {
+	args := m.Called(id, actor, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.MedicationOrder), args.Error(1)
+}
+
+func (m *MockMedicationOrderService) ListByEncounter(encounterID uint, actor *domain.Actor, schemaName string) ([]domain.MedicationOrder, error) {
+	args := m.Called(encounterID, actor, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.MedicationOrder), args.Error(1)
+}
+
+func (m *MockMedicationOrderService) ListByPatient(patientID uint, req *domain.MedicationOrderListRequest, actor *domain.Actor, schemaName string) ([]domain.MedicationOrder, error) {
+	args := m.Called(patientID, req, actor, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.MedicationOrder), args.Error(1)
+}
+
+func (m *MockMedicationOrderService) Verify(id uint, actor *domain.Actor, schemaName string) (*domain.MedicationOrder, error) {
+	args := m.Called(id, actor, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.MedicationOrder), args.Error(1)
+}
+
+func (m *MockMedicationOrderService) Dispense(id uint, req *domain.MedicationDispenseRequest, actor *domain.Actor, schemaName string) (*domain.MedicationDispensing, error) {
+	args := m.Called(id, req, actor, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.MedicationDispensing), args.Error(1)
+}
+
+func (m *MockMedicationOrderService) Cancel(id uint, req *domain.MedicationCancelRequest, actor *domain.Actor, schemaName string) (*domain.MedicationOrder, error) {
+	args := m.Called(id, req, actor, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.MedicationOrder), args.Error(1)
+}
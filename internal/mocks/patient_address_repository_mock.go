@@ -0,0 +1,41 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+// MockPatientAddressRepository is a mock implementation of domain.PatientAddressRepository
+type MockPatientAddressRepository struct {
+	mock.Mock
+}
+
+func NewMockPatientAddressRepository() *MockPatientAddressRepository {
+	return &MockPatientAddressRepository{}
+}
+
+func (m *MockPatientAddressRepository) ListByPatient(patientID uint, schemaName string) ([]domain.PatientAddress, error) {
+	args := m.Called(patientID, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.PatientAddress), args.Error(1)
+}
+
+func (m *MockPatientAddressRepository) GetByType(patientID uint, addressType string, schemaName string) (*domain.PatientAddress, error) {
+	args := m.Called(patientID, addressType, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PatientAddress), args.Error(1)
+}
+
+func (m *MockPatientAddressRepository) Save(address *domain.PatientAddress, event *domain.AuditEvent, schemaName string) error {
+	args := m.Called(address, event, schemaName)
+	return args.Error(0)
+}
+
+func (m *MockPatientAddressRepository) Delete(address *domain.PatientAddress, event *domain.AuditEvent, schemaName string) error {
+	args := m.Called(address, event, schemaName)
+	return args.Error(0)
+}
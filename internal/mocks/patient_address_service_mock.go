@@ -0,0 +1,36 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+// MockPatientAddressService is a mock implementation of domain.PatientAddressService
+type MockPatientAddressService struct {
+	mock.Mock
+}
+
+func NewMockPatientAddressService() *MockPatientAddressService {
+	return &MockPatientAddressService{}
+}
+
+func (m *MockPatientAddressService) List(patientID uint, actor *domain.Actor, schemaName string) ([]domain.PatientAddress, error) {
+	args := m.Called(patientID, actor, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.PatientAddress), args.Error(1)
+}
+
+func (m *MockPatientAddressService) Save(patientID uint, addressType string, req *domain.PatientAddressRequest, actor *domain.Actor, schemaName string) (*domain.PatientAddress, error) {
+	args := m.Called(patientID, addressType, req, actor, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PatientAddress), args.Error(1)
+}
+
+func (m *MockPatientAddressService) Delete(patientID uint, addressType string, actor *domain.Actor, schemaName string) error {
+	args := m.Called(patientID, addressType, actor, schemaName)
+	return args.Error(0)
+}
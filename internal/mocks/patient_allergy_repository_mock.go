@@ -0,0 +1,46 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+// MockPatientAllergyRepository is a mock implementation of domain.PatientAllergyRepository
+type MockPatientAllergyRepository struct {
+	mock.Mock
+}
+
+func NewMockPatientAllergyRepository() *MockPatientAllergyRepository {
+	return &MockPatientAllergyRepository{}
+}
+
+func (m *MockPatientAllergyRepository) ListByPatient(patientID uint, schemaName string) ([]domain.PatientAllergy, error) {
+	args := m.Called(patientID, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.PatientAllergy), args.Error(1)
+}
+
+func (m *MockPatientAllergyRepository) GetByID(patientID uint, id uint, schemaName string) (*domain.PatientAllergy, error) {
+	args := m.Called(patientID, id, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PatientAllergy), args.Error(1)
+}
+
+func (m *MockPatientAllergyRepository) Create(allergy *domain.PatientAllergy, event *domain.AuditEvent, schemaName string) error {
+	args := m.Called(allergy, event, schemaName)
+	return args.Error(0)
+}
+
+func (m *MockPatientAllergyRepository) Update(allergy *domain.PatientAllergy, event *domain.AuditEvent, schemaName string) error {
+	args := m.Called(allergy, event, schemaName)
+	return args.Error(0)
+}
+
+func (m *MockPatientAllergyRepository) Delete(allergy *domain.PatientAllergy, event *domain.AuditEvent, schemaName string) error {
+	args := m.Called(allergy, event, schemaName)
+	return args.Error(0)
+}
@@ -0,0 +1,44 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+// MockPatientAllergyService is a mock implementation of domain.PatientAllergyService
+type MockPatientAllergyService struct {
+	mock.Mock
+}
+
+func NewMockPatientAllergyService() *MockPatientAllergyService {
+	return &MockPatientAllergyService{}
+}
+
+func (m *MockPatientAllergyService) List(patientID uint, actor *domain.Actor, schemaName string) ([]domain.PatientAllergy, error) {
+	args := m.Called(patientID, actor, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.PatientAllergy), args.Error(1)
+}
+
+func (m *MockPatientAllergyService) Create(patientID uint, req *domain.PatientAllergyRequest, actor *domain.Actor, schemaName string) (*domain.PatientAllergy, error) {
+	args := m.Called(patientID, req, actor, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PatientAllergy), args.Error(1)
+}
+
+func (m *MockPatientAllergyService) Update(patientID uint, id uint, req *domain.PatientAllergyRequest, actor *domain.Actor, schemaName string) (*domain.PatientAllergy, error) {
+	args := m.Called(patientID, id, req, actor, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PatientAllergy), args.Error(1)
+}
+
+func (m *MockPatientAllergyService) Delete(patientID uint, id uint, actor *domain.Actor, schemaName string) error {
+	args := m.Called(patientID, id, actor, schemaName)
+	return args.Error(0)
+}
@@ -0,0 +1,54 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+// MockPatientContactRepository is a mock implementation of domain.PatientContactRepository
+type MockPatientContactRepository struct {
+	mock.Mock
+}
+
+func NewMockPatientContactRepository() *MockPatientContactRepository {
+	return &MockPatientContactRepository{}
+}
+
+func (m *MockPatientContactRepository) ListByPatient(patientID uint, schemaName string) ([]domain.PatientContact, error) {
+	args := m.Called(patientID, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.PatientContact), args.Error(1)
+}
+
+func (m *MockPatientContactRepository) GetByID(patientID uint, id uint, schemaName string) (*domain.PatientContact, error) {
+	args := m.Called(patientID, id, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PatientContact), args.Error(1)
+}
+
+func (m *MockPatientContactRepository) GetPrimary(patientID uint, schemaName string) (*domain.PatientContact, error) {
+	args := m.Called(patientID, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PatientContact), args.Error(1)
+}
+
+func (m *MockPatientContactRepository) Create(contact *domain.PatientContact, event *domain.AuditEvent, schemaName string) error {
+	args := m.Called(contact, event, schemaName)
+	return args.Error(0)
+}
+
+func (m *MockPatientContactRepository) Update(contact *domain.PatientContact, event *domain.AuditEvent, schemaName string) error {
+	args := m.Called(contact, event, schemaName)
+	return args.Error(0)
+}
+
+func (m *MockPatientContactRepository) Delete(contact *domain.PatientContact, event *domain.AuditEvent, schemaName string) error {
+	args := m.Called(contact, event, schemaName)
+	return args.Error(0)
+}
@@ -0,0 +1,44 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+// MockPatientContactService is a mock implementation of domain.PatientContactService
+type MockPatientContactService struct {
+	mock.Mock
+}
+
+func NewMockPatientContactService() *MockPatientContactService {
+	return &MockPatientContactService{}
+}
+
+func (m *MockPatientContactService) List(patientID uint, actor *domain.Actor, schemaName string) ([]domain.PatientContact, error) {
+	args := m.Called(patientID, actor, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.PatientContact), args.Error(1)
+}
+
+func (m *MockPatientContactService) Create(patientID uint, req *domain.PatientContactRequest, actor *domain.Actor, schemaName string) (*domain.PatientContact, error) {
+	args := m.Called(patientID, req, actor, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PatientContact), args.Error(1)
+}
+
+func (m *MockPatientContactService) Update(patientID uint, id uint, req *domain.PatientContactRequest, actor *domain.Actor, schemaName string) (*domain.PatientContact, error) {
+	args := m.Called(patientID, id, req, actor, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PatientContact), args.Error(1)
+}
+
+func (m *MockPatientContactService) Delete(patientID uint, id uint, actor *domain.Actor, schemaName string) error {
+	args := m.Called(patientID, id, actor, schemaName)
+	return args.Error(0)
+}
@@ -0,0 +1,56 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+// MockPatientCoverageRepository is a mock implementation of domain.PatientCoverageRepository
+type MockPatientCoverageRepository struct {
+	mock.Mock
+}
+
+func NewMockPatientCoverageRepository() *MockPatientCoverageRepository {
+	return &MockPatientCoverageRepository{}
+}
+
+func (m *MockPatientCoverageRepository) ListByPatient(patientID uint, schemaName string) ([]domain.PatientCoverage, error) {
+	args := m.Called(patientID, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.PatientCoverage), args.Error(1)
+}
+
+func (m *MockPatientCoverageRepository) ListActive(patientID uint, on time.Time, schemaName string) ([]domain.PatientCoverage, error) {
+	args := m.Called(patientID, on, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.PatientCoverage), args.Error(1)
+}
+
+func (m *MockPatientCoverageRepository) GetByID(patientID uint, id uint, schemaName string) (*domain.PatientCoverage, error) {
+	args := m.Called(patientID, id, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PatientCoverage), args.Error(1)
+}
+
+func (m *MockPatientCoverageRepository) Create(coverage *domain.PatientCoverage, event *domain.AuditEvent, schemaName string) error {
+	args := m.Called(coverage, event, schemaName)
+	return args.Error(0)
+}
+
+func (m *MockPatientCoverageRepository) Update(coverage *domain.PatientCoverage, event *domain.AuditEvent, schemaName string) error {
+	args := m.Called(coverage, event, schemaName)
+	return args.Error(0)
+}
+
+func (m *MockPatientCoverageRepository) Delete(coverage *domain.PatientCoverage, event *domain.AuditEvent, schemaName string) error {
+	args := m.Called(coverage, event, schemaName)
+	return args.Error(0)
+}
@@ -0,0 +1,60 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+// MockPatientCoverageService is a mock implementation of domain.PatientCoverageService
+type MockPatientCoverageService struct {
+	mock.Mock
+}
+
+func NewMockPatientCoverageService() *MockPatientCoverageService {
+	return &MockPatientCoverageService{}
+}
+
+func (m *MockPatientCoverageService) List(patientID uint, actor *domain.Actor, schemaName string) ([]domain.PatientCoverage, error) {
+	args := m.Called(patientID, actor, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.PatientCoverage), args.Error(1)
+}
+
+func (m *MockPatientCoverageService) Active(patientID uint, date string, actor *domain.Actor, schemaName string) ([]domain.PatientCoverage, error) {
+	args := m.Called(patientID, date, actor, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.PatientCoverage), args.Error(1)
+}
+
+func (m *MockPatientCoverageService) Create(patientID uint, req *domain.PatientCoverageRequest, actor *domain.Actor, schemaName string) (*domain.PatientCoverage, error) {
+	args := m.Called(patientID, req, actor, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PatientCoverage), args.Error(1)
+}
+
+func (m *MockPatientCoverageService) Update(patientID uint, id uint, req *domain.PatientCoverageRequest, actor *domain.Actor, schemaName string) (*domain.PatientCoverage, error) {
+	args := m.Called(patientID, id, req, actor, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PatientCoverage), args.Error(1)
+}
+
+func (m *MockPatientCoverageService) Delete(patientID uint, id uint, actor *domain.Actor, schemaName string) error {
+	args := m.Called(patientID, id, actor, schemaName)
+	return args.Error(0)
+}
+
+func (m *MockPatientCoverageService) CheckEligibility(patientID uint, id uint, date string, actor *domain.Actor, schemaName string) (*domain.EligibilityResult, error) {
+	args := m.Called(patientID, id, date, actor, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.EligibilityResult), args.Error(1)
+}
@@ -1,6 +1,8 @@
 package mocks
 
 import (
+	"time"
+
 	"github.com/stretchr/testify/mock"
 	"github.com/wichai2002/his_v1/internal/domain"
 )
@@ -30,12 +32,20 @@ func (m *MockPatientRepository) GetByID(id uint, schemaName string) (*domain.Pat
 	return args.Get(0).(*domain.Patient), args.Error(1)
 }
 
-func (m *MockPatientRepository) Search(query string, schemaName string) ([]domain.Patient, error) {
-	args := m.Called(query, schemaName)
+func (m *MockPatientRepository) Search(filter *domain.PatientSearchFilter, schemaName string) ([]domain.Patient, int64, error) {
+	args := m.Called(filter, schemaName)
 	if args.Get(0) == nil {
-		return nil, args.Error(1)
+		return nil, args.Get(1).(int64), args.Error(2)
 	}
-	return args.Get(0).([]domain.Patient), args.Error(1)
+	return args.Get(0).([]domain.Patient), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockPatientRepository) FuzzySearch(filter *domain.PatientSearchFilter, schemaName string) ([]domain.PatientSearchItem, int64, error) {
+	args := m.Called(filter, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(int64), args.Error(2)
+	}
+	return args.Get(0).([]domain.PatientSearchItem), args.Get(1).(int64), args.Error(2)
 }
 
 func (m *MockPatientRepository) SearchByID(id uint, schemaName string) (*domain.Patient, error) {
@@ -46,22 +56,101 @@ func (m *MockPatientRepository) SearchByID(id uint, schemaName string) (*domain.
 	return args.Get(0).(*domain.Patient), args.Error(1)
 }
 
-func (m *MockPatientRepository) Create(patient *domain.Patient, schemaName string) error {
-	args := m.Called(patient, schemaName)
+func (m *MockPatientRepository) FindDuplicateCandidates(probe *domain.Patient, limit int, schemaName string) ([]domain.PatientSearchItem, error) {
+	args := m.Called(probe, limit, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.PatientSearchItem), args.Error(1)
+}
+
+func (m *MockPatientRepository) Create(patient *domain.Patient, issuer *domain.HNIssuer, events []*domain.AuditEvent, schemaName string) error {
+	args := m.Called(patient, issuer, events, schemaName)
+	return args.Error(0)
+}
+
+func (m *MockPatientRepository) Update(patient *domain.Patient, event *domain.AuditEvent, schemaName string) error {
+	args := m.Called(patient, event, schemaName)
 	return args.Error(0)
 }
 
-func (m *MockPatientRepository) Update(patient *domain.Patient, schemaName string) error {
-	args := m.Called(patient, schemaName)
+func (m *MockPatientRepository) PartialUpdate(id uint, version uint, updates map[string]interface{}, event *domain.AuditEvent, schemaName string) error {
+	args := m.Called(id, version, updates, event, schemaName)
 	return args.Error(0)
 }
 
-func (m *MockPatientRepository) PartialUpdate(id uint, updates map[string]interface{}, schemaName string) error {
-	args := m.Called(id, updates, schemaName)
+func (m *MockPatientRepository) Delete(id uint, event *domain.AuditEvent, schemaName string) error {
+	args := m.Called(id, event, schemaName)
 	return args.Error(0)
 }
 
-func (m *MockPatientRepository) Delete(id uint, schemaName string) error {
+func (m *MockPatientRepository) Merge(merge *domain.PatientMerge, events []*domain.AuditEvent, schemaName string) error {
+	args := m.Called(merge, events, schemaName)
+	return args.Error(0)
+}
+
+func (m *MockPatientRepository) Unmerge(merge *domain.PatientMerge, events []*domain.AuditEvent, schemaName string) error {
+	args := m.Called(merge, events, schemaName)
+	return args.Error(0)
+}
+
+func (m *MockPatientRepository) GetMerge(id uint, schemaName string) (*domain.PatientMerge, error) {
+	args := m.Called(id, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PatientMerge), args.Error(1)
+}
+
+func (m *MockPatientRepository) ListMerges(patientID *uint, schemaName string) ([]domain.PatientMerge, error) {
+	args := m.Called(patientID, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.PatientMerge), args.Error(1)
+}
+
+func (m *MockPatientRepository) ListDeleted(deletedBefore *time.Time, schemaName string) ([]domain.Patient, error) {
+	args := m.Called(deletedBefore, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Patient), args.Error(1)
+}
+
+func (m *MockPatientRepository) GetDeleted(id uint, schemaName string) (*domain.Patient, error) {
 	args := m.Called(id, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Patient), args.Error(1)
+}
+
+func (m *MockPatientRepository) FindLiveByUniqueFields(p *domain.Patient, schemaName string) ([]domain.Patient, error) {
+	args := m.Called(p, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Patient), args.Error(1)
+}
+
+func (m *MockPatientRepository) Restore(id uint, event *domain.AuditEvent, schemaName string) error {
+	args := m.Called(id, event, schemaName)
 	return args.Error(0)
 }
+
+func (m *MockPatientRepository) Purge(ids []uint, deletedBefore time.Time, events []*domain.AuditEvent, schemaName string) (int, []domain.PurgeRetention, error) {
+	args := m.Called(ids, deletedBefore, events, schemaName)
+	if args.Get(1) == nil {
+		return args.Get(0).(int), nil, args.Error(2)
+	}
+	return args.Get(0).(int), args.Get(1).([]domain.PurgeRetention), args.Error(2)
+}
+
+func (m *MockPatientRepository) ListVersions(patientID uint, schemaName string) ([]domain.PatientVersion, error) {
+	args := m.Called(patientID, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.PatientVersion), args.Error(1)
+}
@@ -14,47 +14,87 @@ func NewMockPatientService() *MockPatientService {
 	return &MockPatientService{}
 }
 
-func (m *MockPatientService) Search(query string, schemaName string) ([]domain.Patient, error) {
-	args := m.Called(query, schemaName)
+func (m *MockPatientService) Search(req *domain.PatientSearchRequest, actor *domain.Actor, schemaName string) (*domain.PatientSearchResult, error) {
+	args := m.Called(req, actor, schemaName)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]domain.Patient), args.Error(1)
+	return args.Get(0).(*domain.PatientSearchResult), args.Error(1)
 }
 
-func (m *MockPatientService) SearchByID(id uint, schemaName string) (*domain.Patient, error) {
-	args := m.Called(id, schemaName)
+func (m *MockPatientService) SearchByID(id uint, actor *domain.Actor, schemaName string) (*domain.Patient, error) {
+	args := m.Called(id, actor, schemaName)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*domain.Patient), args.Error(1)
 }
 
-func (m *MockPatientService) Create(req *domain.PatientCreateRequest, schemaName string) (*domain.Patient, error) {
-	args := m.Called(req, schemaName)
+func (m *MockPatientService) Create(req *domain.PatientCreateRequest, actor *domain.Actor, schemaName string) (*domain.Patient, error) {
+	args := m.Called(req, actor, schemaName)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*domain.Patient), args.Error(1)
 }
 
-func (m *MockPatientService) Update(id uint, req *domain.PatientUpdateRequest, schemaName string) (*domain.Patient, error) {
-	args := m.Called(id, req, schemaName)
+func (m *MockPatientService) Update(id uint, req *domain.PatientUpdateRequest, version uint, actor *domain.Actor, schemaName string) (*domain.Patient, error) {
+	args := m.Called(id, req, version, actor, schemaName)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*domain.Patient), args.Error(1)
 }
 
-func (m *MockPatientService) PartialUpdate(id uint, req *domain.PatientPartialUpdateRequest, schemaName string) (*domain.Patient, error) {
-	args := m.Called(id, req, schemaName)
+func (m *MockPatientService) PartialUpdate(id uint, req *domain.PatientPartialUpdateRequest, version uint, actor *domain.Actor, schemaName string) (*domain.Patient, error) {
+	args := m.Called(id, req, version, actor, schemaName)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*domain.Patient), args.Error(1)
 }
 
-func (m *MockPatientService) Delete(id uint, schemaName string) error {
-	args := m.Called(id, schemaName)
+func (m *MockPatientService) Delete(id uint, actor *domain.Actor, schemaName string) error {
+	args := m.Called(id, actor, schemaName)
 	return args.Error(0)
 }
+
+func (m *MockPatientService) Merge(req *domain.PatientMergeRequest, actor *domain.Actor, schemaName string) (*domain.PatientMerge, error) {
+	args := m.Called(req, actor, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PatientMerge), args.Error(1)
+}
+
+func (m *MockPatientService) Unmerge(mergeID uint, actor *domain.Actor, schemaName string) (*domain.PatientMerge, error) {
+	args := m.Called(mergeID, actor, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PatientMerge), args.Error(1)
+}
+
+func (m *MockPatientService) ListMerges(patientID *uint, schemaName string) ([]domain.PatientMerge, error) {
+	args := m.Called(patientID, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.PatientMerge), args.Error(1)
+}
+
+func (m *MockPatientService) History(id uint, actor *domain.Actor, schemaName string) ([]domain.PatientVersion, error) {
+	args := m.Called(id, actor, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.PatientVersion), args.Error(1)
+}
+
+func (m *MockPatientService) DiffVersions(id uint, from int, to int, actor *domain.Actor, schemaName string) (*domain.PatientVersionDiff, error) {
+	args := m.Called(id, from, to, actor, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PatientVersionDiff), args.Error(1)
+}
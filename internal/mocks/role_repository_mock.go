@@ -0,0 +1,75 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+// MockRoleRepository is a mock implementation of domain.RoleRepository
+type MockRoleRepository struct {
+	mock.Mock
+}
+
+func NewMockRoleRepository() *MockRoleRepository {
+	return &MockRoleRepository{}
+}
+
+func (m *MockRoleRepository) GetAll(schemaName string) ([]domain.Role, error) {
+	args := m.Called(schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Role), args.Error(1)
+}
+
+func (m *MockRoleRepository) GetByID(id uint, schemaName string) (*domain.Role, error) {
+	args := m.Called(id, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Role), args.Error(1)
+}
+
+func (m *MockRoleRepository) GetByCodes(codes []string, schemaName string) ([]domain.Role, error) {
+	args := m.Called(codes, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Role), args.Error(1)
+}
+
+func (m *MockRoleRepository) GetAllPermissions(schemaName string) ([]domain.Permission, error) {
+	args := m.Called(schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Permission), args.Error(1)
+}
+
+func (m *MockRoleRepository) GetPermissionsByCodes(codes []string, schemaName string) ([]domain.Permission, error) {
+	args := m.Called(codes, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Permission), args.Error(1)
+}
+
+func (m *MockRoleRepository) Create(role *domain.Role, schemaName string) error {
+	args := m.Called(role, schemaName)
+	return args.Error(0)
+}
+
+func (m *MockRoleRepository) Update(role *domain.Role, schemaName string) error {
+	args := m.Called(role, schemaName)
+	return args.Error(0)
+}
+
+func (m *MockRoleRepository) Delete(id uint, schemaName string) error {
+	args := m.Called(id, schemaName)
+	return args.Error(0)
+}
+
+func (m *MockRoleRepository) ReplaceStaffRoles(staffID uint, roles []domain.Role, schemaName string) error {
+	args := m.Called(staffID, roles, schemaName)
+	return args.Error(0)
+}
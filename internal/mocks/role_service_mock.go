@@ -0,0 +1,65 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+// MockRoleService is a mock implementation of domain.RoleService
+type MockRoleService struct {
+	mock.Mock
+}
+
+func NewMockRoleService() *MockRoleService {
+	return &MockRoleService{}
+}
+
+func (m *MockRoleService) GetAll(schemaName string) ([]domain.Role, error) {
+	args := m.Called(schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Role), args.Error(1)
+}
+
+func (m *MockRoleService) GetByID(id uint, schemaName string) (*domain.Role, error) {
+	args := m.Called(id, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Role), args.Error(1)
+}
+
+func (m *MockRoleService) GetAllPermissions(schemaName string) ([]domain.Permission, error) {
+	args := m.Called(schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Permission), args.Error(1)
+}
+
+func (m *MockRoleService) Create(req *domain.RoleCreateRequest, schemaName string) (*domain.Role, error) {
+	args := m.Called(req, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Role), args.Error(1)
+}
+
+func (m *MockRoleService) Update(id uint, req *domain.RoleUpdateRequest, schemaName string) (*domain.Role, error) {
+	args := m.Called(id, req, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Role), args.Error(1)
+}
+
+func (m *MockRoleService) Delete(id uint, schemaName string) error {
+	args := m.Called(id, schemaName)
+	return args.Error(0)
+}
+
+func (m *MockRoleService) AssignStaffRoles(staffID uint, req *domain.StaffRolesRequest, schemaName string) error {
+	args := m.Called(staffID, req, schemaName)
+	return args.Error(0)
+}
@@ -1,6 +1,8 @@
 package mocks
 
 import (
+	"time"
+
 	"github.com/stretchr/testify/mock"
 	"github.com/wichai2002/his_v1/internal/domain"
 )
@@ -48,7 +50,49 @@ func (m *MockStaffRepository) Update(staff *domain.Staff, schemaName string) err
 	return args.Error(0)
 }
 
+func (m *MockStaffRepository) UpdatePassword(id uint, passwordHash string, schemaName string) error {
+	args := m.Called(id, passwordHash, schemaName)
+	return args.Error(0)
+}
+
 func (m *MockStaffRepository) Delete(id uint, schemaName string) error {
 	args := m.Called(id, schemaName)
 	return args.Error(0)
 }
+
+func (m *MockStaffRepository) ListDeleted(deletedBefore *time.Time, schemaName string) ([]domain.Staff, error) {
+	args := m.Called(deletedBefore, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Staff), args.Error(1)
+}
+
+func (m *MockStaffRepository) GetDeleted(id uint, schemaName string) (*domain.Staff, error) {
+	args := m.Called(id, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Staff), args.Error(1)
+}
+
+func (m *MockStaffRepository) FindLiveByUniqueFields(s *domain.Staff, schemaName string) ([]domain.Staff, error) {
+	args := m.Called(s, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Staff), args.Error(1)
+}
+
+func (m *MockStaffRepository) Restore(id uint, schemaName string) error {
+	args := m.Called(id, schemaName)
+	return args.Error(0)
+}
+
+func (m *MockStaffRepository) Purge(ids []uint, deletedBefore time.Time, schemaName string) (int, []domain.PurgeRetention, error) {
+	args := m.Called(ids, deletedBefore, schemaName)
+	if args.Get(1) == nil {
+		return args.Get(0).(int), nil, args.Error(2)
+	}
+	return args.Get(0).(int), args.Get(1).([]domain.PurgeRetention), args.Error(2)
+}
@@ -22,6 +22,29 @@ func (m *MockStaffService) Login(req *domain.StaffLoginRequest, schemaName strin
 	return args.Get(0).(*domain.StaffLoginResponse), args.Error(1)
 }
 
+func (m *MockStaffService) RefreshToken(req *domain.TokenRefreshRequest, schemaName string) (*domain.StaffLoginResponse, error) {
+	args := m.Called(req, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.StaffLoginResponse), args.Error(1)
+}
+
+func (m *MockStaffService) Logout(staffID uint, schemaName string) error {
+	args := m.Called(staffID, schemaName)
+	return args.Error(0)
+}
+
+func (m *MockStaffService) ChangePassword(id uint, req *domain.StaffChangePasswordRequest, schemaName string) error {
+	args := m.Called(id, req, schemaName)
+	return args.Error(0)
+}
+
+func (m *MockStaffService) IsTokenRevoked(jti string, schemaName string) (bool, error) {
+	args := m.Called(jti, schemaName)
+	return args.Bool(0), args.Error(1)
+}
+
 func (m *MockStaffService) GetAll(schemaName string) ([]domain.Staff, error) {
 	args := m.Called(schemaName)
 	if args.Get(0) == nil {
@@ -0,0 +1,51 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+// MockTenantRepository is a mock implementation of domain.TenantRepository
+type MockTenantRepository struct {
+	mock.Mock
+}
+
+func NewMockTenantRepository() *MockTenantRepository {
+	return &MockTenantRepository{}
+}
+
+func (m *MockTenantRepository) GetBySubdomain(subdomain string) (*domain.Tenant, error) {
+	args := m.Called(subdomain)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Tenant), args.Error(1)
+}
+
+func (m *MockTenantRepository) GetBySchemaName(schemaName string) (*domain.Tenant, error) {
+	args := m.Called(schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Tenant), args.Error(1)
+}
+
+func (m *MockTenantRepository) Create(tenant *domain.Tenant) error {
+	args := m.Called(tenant)
+	return args.Error(0)
+}
+
+func (m *MockTenantRepository) Update(tenant *domain.Tenant) error {
+	args := m.Called(tenant)
+	return args.Error(0)
+}
+
+func (m *MockTenantRepository) Delete(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockTenantRepository) SchemaExists(schemaName string) (bool, error) {
+	args := m.Called(schemaName)
+	return args.Bool(0), args.Error(1)
+}
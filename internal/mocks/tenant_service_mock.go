@@ -60,7 +60,10 @@ func (m *MockTenantService) SetupTenantWithAdmin(
 	return args.Get(0).(*domain.Tenant), args.Error(1)
 }
 
-func (m *MockTenantService) GenerateHN(schemaName string) (string, error) {
+func (m *MockTenantService) GetHNIssuer(schemaName string) (*domain.HNIssuer, error) {
 	args := m.Called(schemaName)
-	return args.String(0), args.Error(1)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.HNIssuer), args.Error(1)
 }
@@ -0,0 +1,43 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+// MockTokenRepository is a mock implementation of domain.TokenRepository
+type MockTokenRepository struct {
+	mock.Mock
+}
+
+func NewMockTokenRepository() *MockTokenRepository {
+	return &MockTokenRepository{}
+}
+
+func (m *MockTokenRepository) CreateRefreshToken(token *domain.RefreshToken, schemaName string) error {
+	args := m.Called(token, schemaName)
+	return args.Error(0)
+}
+
+func (m *MockTokenRepository) GetRefreshTokenByHash(tokenHash string, schemaName string) (*domain.RefreshToken, error) {
+	args := m.Called(tokenHash, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.RefreshToken), args.Error(1)
+}
+
+func (m *MockTokenRepository) RotateRefreshToken(oldID uint, newToken *domain.RefreshToken, schemaName string) error {
+	args := m.Called(oldID, newToken, schemaName)
+	return args.Error(0)
+}
+
+func (m *MockTokenRepository) RevokeAllForStaff(staffID uint, reason string, schemaName string) error {
+	args := m.Called(staffID, reason, schemaName)
+	return args.Error(0)
+}
+
+func (m *MockTokenRepository) IsRevoked(jti string, schemaName string) (bool, error) {
+	args := m.Called(jti, schemaName)
+	return args.Bool(0), args.Error(1)
+}
@@ -0,0 +1,63 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+// MockTrashService is a mock implementation of domain.TrashService
+type MockTrashService struct {
+	mock.Mock
+}
+
+func NewMockTrashService() *MockTrashService {
+	return &MockTrashService{}
+}
+
+func (m *MockTrashService) ListPatients(schemaName string) ([]domain.Patient, error) {
+	args := m.Called(schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Patient), args.Error(1)
+}
+
+func (m *MockTrashService) RestorePatient(id uint, actor *domain.Actor, schemaName string) (*domain.Patient, error) {
+	args := m.Called(id, actor, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Patient), args.Error(1)
+}
+
+func (m *MockTrashService) PurgePatients(actor *domain.Actor, schemaName string) (*domain.PurgeResult, error) {
+	args := m.Called(actor, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PurgeResult), args.Error(1)
+}
+
+func (m *MockTrashService) ListStaff(schemaName string) ([]domain.Staff, error) {
+	args := m.Called(schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Staff), args.Error(1)
+}
+
+func (m *MockTrashService) RestoreStaff(id uint, schemaName string) (*domain.Staff, error) {
+	args := m.Called(id, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Staff), args.Error(1)
+}
+
+func (m *MockTrashService) PurgeStaff(schemaName string) (*domain.PurgeResult, error) {
+	args := m.Called(schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PurgeResult), args.Error(1)
+}
@@ -0,0 +1,52 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+// MockVitalSignRepository is a mock implementation of domain.VitalSignRepository
+type MockVitalSignRepository struct {
+	mock.Mock
+}
+
+func NewMockVitalSignRepository() *MockVitalSignRepository {
+	return &MockVitalSignRepository{}
+}
+
+func (m *MockVitalSignRepository) Create(vital *domain.VitalSign, event *domain.AuditEvent, schemaName string) error {
+	args := m.Called(vital, event, schemaName)
+	return args.Error(0)
+}
+
+func (m *MockVitalSignRepository) ListByEncounter(encounterID uint, schemaName string) ([]domain.VitalSign, error) {
+	args := m.Called(encounterID, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.VitalSign), args.Error(1)
+}
+
+func (m *MockVitalSignRepository) ListByPatient(filter *domain.VitalSignFilter, schemaName string) ([]domain.VitalSign, error) {
+	args := m.Called(filter, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.VitalSign), args.Error(1)
+}
+
+func (m *MockVitalSignRepository) LatestHeight(patientID uint, schemaName string) (*float64, error) {
+	args := m.Called(patientID, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*float64), args.Error(1)
+}
+
+func (m *MockVitalSignRepository) Trend(patientID uint, limit int, schemaName string) ([]domain.VitalTrendPoint, error) {
+	args := m.Called(patientID, limit, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.VitalTrendPoint), args.Error(1)
+}
@@ -0,0 +1,47 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+// MockVitalSignService is a mock implementation of domain.VitalSignService
+type MockVitalSignService struct {
+	mock.Mock
+}
+
+func NewMockVitalSignService() *MockVitalSignService {
+	return &MockVitalSignService{}
+}
+
+func (m *MockVitalSignService) Record(encounterID uint, req *domain.VitalSignRequest, actor *domain.Actor, schemaName string) (*domain.VitalSign, error) {
+	args := m.Called(encounterID, req, actor, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.VitalSign), args.Error(1)
+}
+
+func (m *MockVitalSignService) ListByEncounter(encounterID uint, actor *domain.Actor, schemaName string) ([]domain.VitalSign, error) {
+	args := m.Called(encounterID, actor, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.VitalSign), args.Error(1)
+}
+
+func (m *MockVitalSignService) ListByPatient(patientID uint, req *domain.VitalSignListRequest, actor *domain.Actor, schemaName string) ([]domain.VitalSign, error) {
+	args := m.Called(patientID, req, actor, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.VitalSign), args.Error(1)
+}
+
+func (m *MockVitalSignService) Trend(patientID uint, req *domain.VitalTrendRequest, actor *domain.Actor, schemaName string) ([]domain.VitalTrend, error) {
+	args := m.Called(patientID, req, actor, schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.VitalTrend), args.Error(1)
+}
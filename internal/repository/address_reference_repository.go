@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"strings"
+
+	"github.com/wichai2002/his_v1/internal/domain"
+	"gorm.io/gorm"
+)
+
+// addressLocationSelect selects the columns of domain.AddressLocation from a subdistrict
+// joined with its district and province
+const addressLocationSelect = `
+	SELECT s.code AS subdistrict_code, s.name_th AS tambon_th, s.name_en AS tambon_en,
+		d.code AS district_code, d.name_th AS amphoe_th, d.name_en AS amphoe_en,
+		p.code AS province_code, p.name_th AS changwat_th, p.name_en AS changwat_en,
+		s.postal_code
+	FROM thai_subdistricts s
+	JOIN thai_districts d ON d.code = s.district_code
+	JOIN thai_provinces p ON p.code = d.province_code`
+
+// likeEscaper escapes the LIKE wildcards in user input; backslash is the default escape in PostgreSQL
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+type addressReferenceRepository struct {
+	db *gorm.DB
+}
+
+// NewAddressReferenceRepository creates a repository over the Thai address reference tables
+func NewAddressReferenceRepository(db *gorm.DB) domain.AddressReferenceRepository {
+	return &addressReferenceRepository{db: db}
+}
+
+func (r *addressReferenceRepository) ListProvinces() ([]domain.Province, error) {
+	var provinces []domain.Province
+	if err := r.db.Order("name_th").Find(&provinces).Error; err != nil {
+		return nil, err
+	}
+	return provinces, nil
+}
+
+func (r *addressReferenceRepository) ListDistricts(provinceCode string) ([]domain.District, error) {
+	var districts []domain.District
+	if err := r.db.Where("province_code = ?", provinceCode).Order("code").Find(&districts).Error; err != nil {
+		return nil, err
+	}
+	return districts, nil
+}
+
+func (r *addressReferenceRepository) ListSubdistricts(districtCode string) ([]domain.Subdistrict, error) {
+	var subdistricts []domain.Subdistrict
+	if err := r.db.Where("district_code = ?", districtCode).Order("code").Find(&subdistricts).Error; err != nil {
+		return nil, err
+	}
+	return subdistricts, nil
+}
+
+// GetLocation returns a subdistrict with its district and province, gorm.ErrRecordNotFound if unknown
+func (r *addressReferenceRepository) GetLocation(subdistrictCode string) (*domain.AddressLocation, error) {
+	var locations []domain.AddressLocation
+	if err := r.db.Raw(addressLocationSelect+" WHERE s.code = ?", subdistrictCode).Scan(&locations).Error; err != nil {
+		return nil, err
+	}
+	if len(locations) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &locations[0], nil
+}
+
+func (r *addressReferenceRepository) FindByPostalCode(postalCode string) ([]domain.AddressLocation, error) {
+	var locations []domain.AddressLocation
+	if err := r.db.Raw(addressLocationSelect+" WHERE s.postal_code = ? ORDER BY s.code", postalCode).
+		Scan(&locations).Error; err != nil {
+		return nil, err
+	}
+	return locations, nil
+}
+
+// Search matches subdistrict, district and province names anywhere in the name and postal
+// codes by prefix. Subdistricts whose own name starts with the query rank first.
+func (r *addressReferenceRepository) Search(query string, limit int) ([]domain.AddressLocation, error) {
+	escaped := likeEscaper.Replace(query)
+	contains := "%" + escaped + "%"
+	prefix := escaped + "%"
+
+	var locations []domain.AddressLocation
+	if err := r.db.Raw(addressLocationSelect+`
+		WHERE s.name_th ILIKE @contains OR s.name_en ILIKE @contains
+			OR d.name_th ILIKE @contains OR d.name_en ILIKE @contains
+			OR p.name_th ILIKE @contains OR p.name_en ILIKE @contains
+			OR s.postal_code LIKE @prefix
+		ORDER BY (s.name_th ILIKE @prefix OR s.name_en ILIKE @prefix) DESC, s.code
+		LIMIT @limit`,
+		map[string]interface{}{"contains": contains, "prefix": prefix, "limit": limit}).
+		Scan(&locations).Error; err != nil {
+		return nil, err
+	}
+	return locations, nil
+}
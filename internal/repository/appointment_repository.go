@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/infrastructure/database"
+	"gorm.io/gorm"
+)
+
+type appointmentRepository struct {
+	*TenantAwareRepository
+}
+
+// NewAppointmentRepository creates a new appointment repository
+func NewAppointmentRepository(db *gorm.DB, dbManager *database.TenantDBManager) domain.AppointmentRepository {
+	return &appointmentRepository{
+		TenantAwareRepository: NewTenantAwareRepository(db, dbManager),
+	}
+}
+
+// getDB returns the appropriate database based on schema
+func (r *appointmentRepository) getDB(schemaName string) (*gorm.DB, error) {
+	if schemaName == "" || schemaName == "public" {
+		return r.GetDB(), nil
+	}
+	return r.GetTenantDB(schemaName)
+}
+
+func (r *appointmentRepository) GetSlot(id uint, schemaName string) (*domain.AppointmentSlot, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	var slot domain.AppointmentSlot
+	if err := db.Joins("JOIN availability_templates ON availability_templates.id = appointment_slots.template_id").
+		Where("availability_templates.deleted_at IS NULL").
+		First(&slot, "appointment_slots.id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &slot, nil
+}
+
+func (r *appointmentRepository) GetByID(id uint, schemaName string) (*domain.Appointment, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	var appointment domain.Appointment
+	if err := db.First(&appointment, id).Error; err != nil {
+		return nil, err
+	}
+	return &appointment, nil
+}
+
+func (r *appointmentRepository) ListByPatient(patientID uint, schemaName string) ([]domain.Appointment, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	var appointments []domain.Appointment
+	if err := db.Where(mergedPatientCondition, patientID).Order("start_at DESC, id DESC").Find(&appointments).Error; err != nil {
+		return nil, err
+	}
+	return appointments, nil
+}
+
+func (r *appointmentRepository) Create(appointment *domain.Appointment, event *domain.AuditEvent, schemaName string) error {
+	return r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		if err := tx.Create(appointment).Error; err != nil {
+			return err
+		}
+		return appendAuditEvents(tx, event)
+	})
+}
+
+func (r *appointmentRepository) Reschedule(previous *domain.Appointment, replacement *domain.Appointment, event *domain.AuditEvent, schemaName string) error {
+	return r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		// Release the old slot first so the patient may move to another doctor at the same time
+		result := tx.Model(previous).Where("status = ?", domain.AppointmentStatusBooked).
+			Update("status", domain.AppointmentStatusRescheduled)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrPreconditionFailed
+		}
+		previous.Status = domain.AppointmentStatusRescheduled
+
+		if err := tx.Create(replacement).Error; err != nil {
+			return err
+		}
+		return appendAuditEvents(tx, event)
+	})
+}
+
+func (r *appointmentRepository) Cancel(appointment *domain.Appointment, event *domain.AuditEvent, schemaName string) error {
+	return r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		result := tx.Model(appointment).Where("status = ?", domain.AppointmentStatusBooked).
+			Select("status", "cancelled_at", "cancel_reason").
+			Updates(appointment)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrPreconditionFailed
+		}
+		return appendAuditEvents(tx, event)
+	})
+}
+
+// Calendar lists the slots of live templates and any withdrawn slot that is still booked
+func (r *appointmentRepository) Calendar(filter *domain.AppointmentCalendarFilter, schemaName string) ([]domain.CalendarEntry, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	query := db.Table("appointment_slots").
+		Select(`appointment_slots.id AS slot_id, appointment_slots.doctor_id, appointment_slots.department_id,
+			appointment_slots.start_at, appointment_slots.end_at,
+			CONCAT_WS(' ', staffs.first_name, staffs.last_name) AS doctor_name,
+			appointments.id AS appointment_id, appointments.patient_id, appointments.reason,
+			patients.patient_hn, patients.first_name_th, patients.last_name_th`).
+		Joins("JOIN availability_templates ON availability_templates.id = appointment_slots.template_id").
+		Joins("JOIN staffs ON staffs.id = appointment_slots.doctor_id").
+		Joins("LEFT JOIN appointments ON appointments.slot_id = appointment_slots.id AND appointments.status = ? AND appointments.deleted_at IS NULL", domain.AppointmentStatusBooked).
+		Joins("LEFT JOIN patients ON patients.id = appointments.patient_id").
+		Where("(availability_templates.deleted_at IS NULL OR appointments.id IS NOT NULL)").
+		Where("appointment_slots.start_at >= ? AND appointment_slots.start_at < ?", filter.From, filter.To)
+	if filter.DoctorID != 0 {
+		query = query.Where("appointment_slots.doctor_id = ?", filter.DoctorID)
+	}
+	if filter.DepartmentID != 0 {
+		query = query.Where("appointment_slots.department_id = ?", filter.DepartmentID)
+	}
+
+	var entries []domain.CalendarEntry
+	if err := query.Order("appointment_slots.start_at, appointment_slots.doctor_id").Scan(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/infrastructure/database"
+	"gorm.io/gorm"
+)
+
+// defaultAuditQueryLimit caps audit queries that do not ask for a page size
+const defaultAuditQueryLimit = 100
+
+type auditRepository struct {
+	*TenantAwareRepository
+}
+
+// NewAuditRepository creates a new audit trail repository
+func NewAuditRepository(db *gorm.DB, dbManager *database.TenantDBManager) domain.AuditRepository {
+	return &auditRepository{
+		TenantAwareRepository: NewTenantAwareRepository(db, dbManager),
+	}
+}
+
+// getDB returns the appropriate database based on schema
+func (r *auditRepository) getDB(schemaName string) (*gorm.DB, error) {
+	if schemaName == "" || schemaName == "public" {
+		return r.GetDB(), nil
+	}
+	return r.GetTenantDB(schemaName)
+}
+
+func (r *auditRepository) Append(events []*domain.AuditEvent, schemaName string) error {
+	if len(events) == 0 {
+		return nil
+	}
+	return r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		return appendAuditEvents(tx, events...)
+	})
+}
+
+func (r *auditRepository) Query(filter *domain.AuditFilter, schemaName string) ([]domain.AuditEvent, int64, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	query := db.Model(&domain.AuditEvent{})
+	if filter.PatientID != nil {
+		query = query.Where("patient_id = ?", *filter.PatientID)
+	}
+	if filter.ActorID != nil {
+		query = query.Where("actor_id = ?", *filter.ActorID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultAuditQueryLimit
+	}
+
+	var events []domain.AuditEvent
+	if err := query.Order("id DESC").Limit(limit).Offset(filter.Offset).Find(&events).Error; err != nil {
+		return nil, 0, err
+	}
+	return events, total, nil
+}
+
+func (r *auditRepository) GetChain(afterID uint, limit int, schemaName string) ([]domain.AuditEvent, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	var events []domain.AuditEvent
+	if err := db.Where("id > ?", afterID).Order("id ASC").Limit(limit).Find(&events).Error; err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// appendAuditEvents chains and inserts audit events using tx, which must already be
+// scoped to the tenant schema. Repositories call it inside their own transaction so
+// the audit record commits or rolls back together with the change it describes.
+// It must run in the same transaction after the audited rows exist, so events can refer to
+// them. The chain lock is held until commit, so keep the statements after it short.
+func appendAuditEvents(tx *gorm.DB, events ...*domain.AuditEvent) error {
+	// Serialise appends per tenant so every event links to the latest hash. The advisory
+	// lock only blocks other appends, never patient reads or writes to other tables.
+	if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(current_schema() || '.audit_events'))").Error; err != nil {
+		return fmt.Errorf("failed to lock audit trail: %w", err)
+	}
+
+	var lastHashes []string
+	if err := tx.Model(&domain.AuditEvent{}).Order("id DESC").Limit(1).Pluck("hash", &lastHashes).Error; err != nil {
+		return err
+	}
+
+	var prevHash string
+	if len(lastHashes) > 0 {
+		prevHash = lastHashes[0]
+	}
+
+	// Postgres stores microseconds, so truncate before hashing to keep the hash reproducible
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	for _, event := range events {
+		event.CreatedAt = now
+		event.PrevHash = prevHash
+		event.Hash = event.ComputeHash()
+		prevHash = event.Hash
+	}
+
+	return tx.Create(events).Error
+}
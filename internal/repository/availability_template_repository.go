@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/infrastructure/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// slotBatchSize bounds the rows of one slot insert; a 180 day expansion of a long session fits in a few batches
+const slotBatchSize = 500
+
+type availabilityTemplateRepository struct {
+	*TenantAwareRepository
+}
+
+// NewAvailabilityTemplateRepository creates a new availability template repository
+func NewAvailabilityTemplateRepository(db *gorm.DB, dbManager *database.TenantDBManager) domain.AvailabilityTemplateRepository {
+	return &availabilityTemplateRepository{
+		TenantAwareRepository: NewTenantAwareRepository(db, dbManager),
+	}
+}
+
+// getDB returns the appropriate database based on schema
+func (r *availabilityTemplateRepository) getDB(schemaName string) (*gorm.DB, error) {
+	if schemaName == "" || schemaName == "public" {
+		return r.GetDB(), nil
+	}
+	return r.GetTenantDB(schemaName)
+}
+
+func (r *availabilityTemplateRepository) List(doctorID uint, schemaName string) ([]domain.AvailabilityTemplate, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	query := db.Order("doctor_id, weekday, start_time")
+	if doctorID != 0 {
+		query = query.Where("doctor_id = ?", doctorID)
+	}
+	var templates []domain.AvailabilityTemplate
+	if err := query.Find(&templates).Error; err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+func (r *availabilityTemplateRepository) GetByID(id uint, schemaName string) (*domain.AvailabilityTemplate, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	var template domain.AvailabilityTemplate
+	if err := db.First(&template, id).Error; err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+func (r *availabilityTemplateRepository) Create(template *domain.AvailabilityTemplate, slots []domain.AppointmentSlot, schemaName string) error {
+	return r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		if err := tx.Create(template).Error; err != nil {
+			return err
+		}
+		for i := range slots {
+			slots[i].TemplateID = template.ID
+		}
+		_, err := insertSlots(tx, slots)
+		return err
+	})
+}
+
+func (r *availabilityTemplateRepository) AddSlots(slots []domain.AppointmentSlot, schemaName string) (int, error) {
+	var inserted int
+	err := r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		var err error
+		inserted, err = insertSlots(tx, slots)
+		return err
+	})
+	return inserted, err
+}
+
+func (r *availabilityTemplateRepository) Delete(template *domain.AvailabilityTemplate, from time.Time, schemaName string) error {
+	return r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			DELETE FROM appointment_slots
+			WHERE template_id = ? AND start_at >= ?
+			AND NOT EXISTS (SELECT 1 FROM appointments WHERE appointments.slot_id = appointment_slots.id)
+		`, template.ID, from).Error; err != nil {
+			return err
+		}
+		return tx.Delete(template).Error
+	})
+}
+
+// insertSlots inserts the slots, skipping any that would overlap a slot the doctor already has
+func insertSlots(tx *gorm.DB, slots []domain.AppointmentSlot) (int, error) {
+	if len(slots) == 0 {
+		return 0, nil
+	}
+	result := tx.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(&slots, slotBatchSize)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return int(result.RowsAffected), nil
+}
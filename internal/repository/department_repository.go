@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/infrastructure/database"
+	"gorm.io/gorm"
+)
+
+type departmentRepository struct {
+	*TenantAwareRepository
+}
+
+// NewDepartmentRepository creates a new department repository
+func NewDepartmentRepository(db *gorm.DB, dbManager *database.TenantDBManager) domain.DepartmentRepository {
+	return &departmentRepository{
+		TenantAwareRepository: NewTenantAwareRepository(db, dbManager),
+	}
+}
+
+// getDB returns the appropriate database based on schema
+func (r *departmentRepository) getDB(schemaName string) (*gorm.DB, error) {
+	if schemaName == "" || schemaName == "public" {
+		return r.GetDB(), nil
+	}
+	return r.GetTenantDB(schemaName)
+}
+
+func (r *departmentRepository) List(includeInactive bool, schemaName string) ([]domain.Department, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	query := db.Order("code")
+	if !includeInactive {
+		query = query.Where("is_active")
+	}
+	var departments []domain.Department
+	if err := query.Find(&departments).Error; err != nil {
+		return nil, err
+	}
+	return departments, nil
+}
+
+func (r *departmentRepository) GetByID(id uint, schemaName string) (*domain.Department, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	var department domain.Department
+	if err := db.First(&department, id).Error; err != nil {
+		return nil, err
+	}
+	return &department, nil
+}
+
+func (r *departmentRepository) Create(department *domain.Department, schemaName string) error {
+	return r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		return tx.Create(department).Error
+	})
+}
+
+func (r *departmentRepository) Update(department *domain.Department, schemaName string) error {
+	return r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		return tx.Select("*").Omit("created_at").Updates(department).Error
+	})
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/infrastructure/database"
+	"gorm.io/gorm"
+)
+
+// diagnosisTypeOrder lists the principal diagnosis first, then the others as they were recorded
+const diagnosisTypeOrder = `CASE type WHEN 'principal' THEN 0 WHEN 'comorbidity' THEN 1
+	WHEN 'complication' THEN 2 ELSE 3 END, created_at, id`
+
+type diagnosisRepository struct {
+	*TenantAwareRepository
+}
+
+// NewDiagnosisRepository creates a new diagnosis repository
+func NewDiagnosisRepository(db *gorm.DB, dbManager *database.TenantDBManager) domain.DiagnosisRepository {
+	return &diagnosisRepository{
+		TenantAwareRepository: NewTenantAwareRepository(db, dbManager),
+	}
+}
+
+// getDB returns the appropriate database based on schema
+func (r *diagnosisRepository) getDB(schemaName string) (*gorm.DB, error) {
+	if schemaName == "" || schemaName == "public" {
+		return r.GetDB(), nil
+	}
+	return r.GetTenantDB(schemaName)
+}
+
+func (r *diagnosisRepository) GetByID(encounterID uint, id uint, schemaName string) (*domain.Diagnosis, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	var diagnosis domain.Diagnosis
+	if err := db.Where("encounter_id = ? AND id = ?", encounterID, id).First(&diagnosis).Error; err != nil {
+		return nil, err
+	}
+	return &diagnosis, nil
+}
+
+func (r *diagnosisRepository) ListByEncounter(encounterID uint, schemaName string) ([]domain.Diagnosis, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	var diagnoses []domain.Diagnosis
+	if err := db.Where("encounter_id = ?", encounterID).Order(diagnosisTypeOrder).Find(&diagnoses).Error; err != nil {
+		return nil, err
+	}
+	return diagnoses, nil
+}
+
+func (r *diagnosisRepository) ListByPatient(patientID uint, schemaName string) ([]domain.Diagnosis, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	var diagnoses []domain.Diagnosis
+	if err := db.Where(mergedPatientCondition, patientID).
+		Order("encounter_id DESC, " + diagnosisTypeOrder).Find(&diagnoses).Error; err != nil {
+		return nil, err
+	}
+	return diagnoses, nil
+}
+
+func (r *diagnosisRepository) Create(diagnosis *domain.Diagnosis, event *domain.AuditEvent, schemaName string) error {
+	return r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		if err := tx.Create(diagnosis).Error; err != nil {
+			return err
+		}
+		return appendAuditEvents(tx, event)
+	})
+}
+
+func (r *diagnosisRepository) Delete(diagnosis *domain.Diagnosis, event *domain.AuditEvent, schemaName string) error {
+	return r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		if err := tx.Delete(diagnosis).Error; err != nil {
+			return err
+		}
+		return appendAuditEvents(tx, event)
+	})
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/infrastructure/database"
+	"gorm.io/gorm"
+)
+
+type drugRepository struct {
+	*TenantAwareRepository
+}
+
+// NewDrugRepository creates a new drug master repository
+func NewDrugRepository(db *gorm.DB, dbManager *database.TenantDBManager) domain.DrugRepository {
+	return &drugRepository{
+		TenantAwareRepository: NewTenantAwareRepository(db, dbManager),
+	}
+}
+
+// getDB returns the appropriate database based on schema
+func (r *drugRepository) getDB(schemaName string) (*gorm.DB, error) {
+	if schemaName == "" || schemaName == "public" {
+		return r.GetDB(), nil
+	}
+	return r.GetTenantDB(schemaName)
+}
+
+func (r *drugRepository) List(query string, includeInactive bool, schemaName string) ([]domain.Drug, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	q := db.Order("generic_name, strength, code")
+	if !includeInactive {
+		q = q.Where("is_active")
+	}
+	if query = strings.TrimSpace(query); query != "" {
+		pattern := "%" + likeEscaper.Replace(query) + "%"
+		q = q.Where("code ILIKE ? OR generic_name ILIKE ? OR trade_name ILIKE ?", pattern, pattern, pattern)
+	}
+	var drugs []domain.Drug
+	if err := q.Find(&drugs).Error; err != nil {
+		return nil, err
+	}
+	return drugs, nil
+}
+
+func (r *drugRepository) GetByID(id uint, schemaName string) (*domain.Drug, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	var drug domain.Drug
+	if err := db.First(&drug, id).Error; err != nil {
+		return nil, err
+	}
+	return &drug, nil
+}
+
+func (r *drugRepository) Create(drug *domain.Drug, schemaName string) error {
+	return r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		return tx.Create(drug).Error
+	})
+}
+
+func (r *drugRepository) Update(drug *domain.Drug, schemaName string) error {
+	return r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		return tx.Select("*").Omit("created_at").Updates(drug).Error
+	})
+}
+
+func (r *drugRepository) ListInteractions(drugID uint, drugIDs []uint, schemaName string) ([]domain.DrugInteraction, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	q := db.Order("id")
+	if len(drugIDs) > 0 {
+		q = q.Where("(drug_id = ? AND interacting_drug_id IN ?) OR (interacting_drug_id = ? AND drug_id IN ?)", drugID, drugIDs, drugID, drugIDs)
+	} else {
+		q = q.Where("drug_id = ? OR interacting_drug_id = ?", drugID, drugID)
+	}
+	var interactions []domain.DrugInteraction
+	if err := q.Find(&interactions).Error; err != nil {
+		return nil, err
+	}
+	return interactions, nil
+}
+
+func (r *drugRepository) GetInteraction(drugID uint, id uint, schemaName string) (*domain.DrugInteraction, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	var interaction domain.DrugInteraction
+	if err := db.Where("drug_id = ? OR interacting_drug_id = ?", drugID, drugID).First(&interaction, id).Error; err != nil {
+		return nil, err
+	}
+	return &interaction, nil
+}
+
+func (r *drugRepository) CreateInteraction(interaction *domain.DrugInteraction, schemaName string) error {
+	return r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		return tx.Create(interaction).Error
+	})
+}
+
+func (r *drugRepository) DeleteInteraction(interaction *domain.DrugInteraction, schemaName string) error {
+	return r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		return tx.Delete(interaction).Error
+	})
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/infrastructure/database"
+	"gorm.io/gorm"
+)
+
+type encounterRepository struct {
+	*TenantAwareRepository
+}
+
+// NewEncounterRepository creates a new encounter repository
+func NewEncounterRepository(db *gorm.DB, dbManager *database.TenantDBManager) domain.EncounterRepository {
+	return &encounterRepository{
+		TenantAwareRepository: NewTenantAwareRepository(db, dbManager),
+	}
+}
+
+// getDB returns the appropriate database based on schema
+func (r *encounterRepository) getDB(schemaName string) (*gorm.DB, error) {
+	if schemaName == "" || schemaName == "public" {
+		return r.GetDB(), nil
+	}
+	return r.GetTenantDB(schemaName)
+}
+
+// Create draws the VN from the vn_counters row of the day, locked until the visit commits,
+// the same way patient registration issues HNs
+func (r *encounterRepository) Create(encounter *domain.Encounter, issuer *domain.HNIssuer, event *domain.AuditEvent, schemaName string) error {
+	return r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		now := time.Now()
+		period := issuer.Template.Period(now)
+		seq, err := nextCounterValue(tx, "vn_counters", period, issuer.Seed(period))
+		if err != nil {
+			return err
+		}
+		vn, err := issuer.Template.Format(issuer.HospitalCode, now, seq)
+		if err != nil {
+			return err
+		}
+		encounter.VN = vn
+		encounter.SetStatus(domain.EncounterStatusRegistered, now)
+
+		if err := tx.Create(encounter).Error; err != nil {
+			return err
+		}
+		if err := event.SetChange("vn", domain.FieldChange{After: encounter.VN}); err != nil {
+			return err
+		}
+		return appendAuditEvents(tx, event)
+	})
+}
+
+func (r *encounterRepository) GetByID(id uint, schemaName string) (*domain.Encounter, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	var encounter domain.Encounter
+	if err := db.First(&encounter, id).Error; err != nil {
+		return nil, err
+	}
+	return &encounter, nil
+}
+
+func (r *encounterRepository) ListByPatient(patientID uint, schemaName string) ([]domain.Encounter, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	var encounters []domain.Encounter
+	if err := db.Where(mergedPatientCondition, patientID).Order("registered_at DESC, id DESC").Find(&encounters).Error; err != nil {
+		return nil, err
+	}
+	return encounters, nil
+}
+
+func (r *encounterRepository) UpdateStatus(encounter *domain.Encounter, fromStatus string, event *domain.AuditEvent, schemaName string) error {
+	return r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		result := tx.Model(encounter).Where("status = ?", fromStatus).
+			Select("status", "triaged_at", "consultation_started_at", "completed_at").
+			Updates(encounter)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrPreconditionFailed
+		}
+		return appendAuditEvents(tx, event)
+	})
+}
+
+func (r *encounterRepository) Queue(filter *domain.EncounterQueueFilter, schemaName string) ([]domain.EncounterQueueEntry, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	var entries []domain.EncounterQueueEntry
+	if err := db.Table("encounters").
+		Select("encounters.*, patients.patient_hn, patients.first_name_th, patients.last_name_th").
+		Joins("JOIN patients ON patients.id = encounters.patient_id").
+		Where("encounters.deleted_at IS NULL").
+		Where("encounters.department_id = ? AND encounters.status IN ?", filter.DepartmentID, filter.Statuses).
+		Where("encounters.registered_at >= ? AND encounters.registered_at < ?", filter.From, filter.To).
+		Order("encounters.registered_at, encounters.id").
+		Scan(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
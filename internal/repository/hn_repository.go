@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/infrastructure/database"
+	"gorm.io/gorm"
+)
+
+type hnCounterRepository struct {
+	*TenantAwareRepository
+}
+
+// NewHNCounterRepository creates a new HN counter repository
+func NewHNCounterRepository(db *gorm.DB, dbManager *database.TenantDBManager) domain.HNCounterRepository {
+	return &hnCounterRepository{
+		TenantAwareRepository: NewTenantAwareRepository(db, dbManager),
+	}
+}
+
+// getDB returns the appropriate database based on schema
+func (r *hnCounterRepository) getDB(schemaName string) (*gorm.DB, error) {
+	if schemaName == "" || schemaName == "public" {
+		return r.GetDB(), nil
+	}
+	return r.GetTenantDB(schemaName)
+}
+
+func (r *hnCounterRepository) Current(period string, schemaName string) (uint64, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	var counters []domain.HNCounter
+	if err := db.Where("period = ?", period).Limit(1).Find(&counters).Error; err != nil {
+		return 0, err
+	}
+	if len(counters) == 0 {
+		return 0, nil
+	}
+	return counters[0].LastValue, nil
+}
+
+// FindHNs includes deleted and merged patients, whose HNs must never be issued again
+func (r *hnCounterRepository) FindHNs(pattern string, schemaName string) ([]string, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	var hns []string
+	if err := db.Unscoped().Model(&domain.Patient{}).
+		Where("patient_hn ~ ?", pattern).
+		Order("patient_hn").
+		Pluck("patient_hn", &hns).Error; err != nil {
+		return nil, err
+	}
+	return hns, nil
+}
+
+// nextHNSequence increments the counter of period with a single upsert and returns the new
+// value; a counter that does not exist yet starts after seed. The counter row stays locked
+// until tx ends, so registrations of one tenant and period take turns only for the rest of
+// their transaction, and a rollback hands the number to the next registration.
+func nextHNSequence(tx *gorm.DB, period string, seed uint64) (uint64, error) {
+	return nextCounterValue(tx, "hn_counters", period, seed)
+}
+
+// nextCounterValue increments the counter of period in table, which must have the layout
+// of hn_counters
+func nextCounterValue(tx *gorm.DB, table string, period string, seed uint64) (uint64, error) {
+	var value uint64
+	err := tx.Raw(fmt.Sprintf(`
+		INSERT INTO %[1]s (period, last_value, updated_at) VALUES (?, ?, NOW())
+		ON CONFLICT (period) DO UPDATE SET last_value = %[1]s.last_value + 1, updated_at = NOW()
+		RETURNING last_value`, table), period, seed+1).Scan(&value).Error
+	return value, err
+}
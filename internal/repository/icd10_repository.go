@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"github.com/wichai2002/his_v1/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type icd10Repository struct {
+	db *gorm.DB
+}
+
+// NewICD10Repository creates a repository over the public ICD-10 catalogue
+func NewICD10Repository(db *gorm.DB) domain.ICD10Repository {
+	return &icd10Repository{db: db}
+}
+
+func (r *icd10Repository) GetByCode(code string) (*domain.ICD10Code, error) {
+	var icd10 domain.ICD10Code
+	if err := r.db.Where("code = ?", code).First(&icd10).Error; err != nil {
+		return nil, err
+	}
+	return &icd10, nil
+}
+
+// Search matches codes by prefix with the dot ignored, or descriptions containing every keyword.
+// Keyword matches whose description starts with the first keyword rank first.
+func (r *icd10Repository) Search(filter *domain.ICD10SearchFilter) ([]domain.ICD10Code, error) {
+	query := r.db.Model(&domain.ICD10Code{})
+	if filter.CodePrefix != "" {
+		query = query.Where("REPLACE(code, '.', '') LIKE ?", likeEscaper.Replace(filter.CodePrefix)+"%")
+	}
+	for _, keyword := range filter.Keywords {
+		query = query.Where("description ILIKE ?", "%"+likeEscaper.Replace(keyword)+"%")
+	}
+	if filter.BillableOnly {
+		query = query.Where("billable")
+	}
+	if len(filter.Keywords) > 0 {
+		query = query.Clauses(clause.OrderBy{Expression: clause.Expr{
+			SQL:  "description ILIKE ? DESC, code",
+			Vars: []interface{}{likeEscaper.Replace(filter.Keywords[0]) + "%"},
+		}})
+	} else {
+		query = query.Order("code")
+	}
+
+	var codes []domain.ICD10Code
+	if err := query.Limit(filter.Limit).Find(&codes).Error; err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
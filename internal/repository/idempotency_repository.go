@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/infrastructure/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type idempotencyRepository struct {
+	*TenantAwareRepository
+}
+
+// NewIdempotencyRepository creates a new idempotency key repository
+func NewIdempotencyRepository(db *gorm.DB, dbManager *database.TenantDBManager) domain.IdempotencyRepository {
+	return &idempotencyRepository{
+		TenantAwareRepository: NewTenantAwareRepository(db, dbManager),
+	}
+}
+
+// getDB returns the appropriate database based on schema
+func (r *idempotencyRepository) getDB(schemaName string) (*gorm.DB, error) {
+	if schemaName == "" || schemaName == "public" {
+		return r.GetDB(), nil
+	}
+	return r.GetTenantDB(schemaName)
+}
+
+// Reserve claims the key with an insert that does nothing on conflict, so of two concurrent
+// requests with the same key exactly one reserves it and the other gets the holding record.
+// Expired keys of the tenant are pruned first, which keeps the table to a day of creates.
+func (r *idempotencyRepository) Reserve(record *domain.IdempotencyKey, expiredBefore time.Time, schemaName string) (*domain.IdempotencyKey, error) {
+	var existing *domain.IdempotencyKey
+	err := r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		if err := tx.Where("created_at < ?", expiredBefore).
+			Delete(&domain.IdempotencyKey{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(record)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 1 {
+			return nil
+		}
+
+		existing = &domain.IdempotencyKey{}
+		return tx.Where("key = ?", record.Key).First(existing).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return existing, nil
+}
+
+func (r *idempotencyRepository) Complete(record *domain.IdempotencyKey, schemaName string) error {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	result := db.Model(&domain.IdempotencyKey{}).
+		Where("key = ? AND status_code = 0", record.Key).
+		Updates(map[string]interface{}{
+			"status_code":      record.StatusCode,
+			"response_body":    record.ResponseBody,
+			"response_headers": record.ResponseHeaders,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Release only removes a key still being processed, never a stored response
+func (r *idempotencyRepository) Release(key string, schemaName string) error {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return fmt.Errorf("failed to get tenant db: %w", err)
+	}
+	return db.Where("key = ? AND status_code = 0", key).Delete(&domain.IdempotencyKey{}).Error
+}
@@ -0,0 +1,419 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/infrastructure/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// drugNameColumn is the generic name with the strength, as Drug.DisplayName builds it
+const drugNameColumn = "TRIM(d.generic_name || ' ' || COALESCE(d.strength, ''))"
+
+type inventoryRepository struct {
+	*TenantAwareRepository
+}
+
+// NewInventoryRepository creates a new pharmacy inventory repository
+func NewInventoryRepository(db *gorm.DB, dbManager *database.TenantDBManager) domain.InventoryRepository {
+	return &inventoryRepository{
+		TenantAwareRepository: NewTenantAwareRepository(db, dbManager),
+	}
+}
+
+// getDB returns the appropriate database based on schema
+func (r *inventoryRepository) getDB(schemaName string) (*gorm.DB, error) {
+	if schemaName == "" || schemaName == "public" {
+		return r.GetDB(), nil
+	}
+	return r.GetTenantDB(schemaName)
+}
+
+func (r *inventoryRepository) ListLocations(includeInactive bool, schemaName string) ([]domain.StockLocation, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	query := db.Order("code")
+	if !includeInactive {
+		query = query.Where("is_active")
+	}
+	var locations []domain.StockLocation
+	if err := query.Find(&locations).Error; err != nil {
+		return nil, err
+	}
+	return locations, nil
+}
+
+func (r *inventoryRepository) GetLocation(id uint, schemaName string) (*domain.StockLocation, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	var location domain.StockLocation
+	if err := db.First(&location, id).Error; err != nil {
+		return nil, err
+	}
+	return &location, nil
+}
+
+func (r *inventoryRepository) CreateLocation(location *domain.StockLocation, schemaName string) error {
+	return r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		return tx.Create(location).Error
+	})
+}
+
+func (r *inventoryRepository) UpdateLocation(location *domain.StockLocation, schemaName string) error {
+	return r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		return tx.Select("*").Omit("created_at").Updates(location).Error
+	})
+}
+
+func (r *inventoryRepository) ListReorderLevels(locationID uint, schemaName string) ([]domain.StockReorderLevel, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	var levels []domain.StockReorderLevel
+	if err := db.Where("location_id = ?", locationID).Order("drug_id").Find(&levels).Error; err != nil {
+		return nil, err
+	}
+	return levels, nil
+}
+
+func (r *inventoryRepository) SaveReorderLevel(level *domain.StockReorderLevel, schemaName string) error {
+	return r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "location_id"}, {Name: "drug_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"reorder_level", "updated_at"}),
+		}).Create(level).Error
+	})
+}
+
+func (r *inventoryRepository) ListLots(filter *domain.StockLotFilter, schemaName string) ([]domain.StockLot, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	query := db.Order("location_id, drug_id, expiry_date, id")
+	if filter.LocationID != nil {
+		query = query.Where("location_id = ?", *filter.LocationID)
+	}
+	if filter.DrugID != nil {
+		query = query.Where("drug_id = ?", *filter.DrugID)
+	}
+	if filter.InStockOnly {
+		query = query.Where("quantity > 0")
+	}
+	var lots []domain.StockLot
+	if err := query.Find(&lots).Error; err != nil {
+		return nil, err
+	}
+	return lots, nil
+}
+
+func (r *inventoryRepository) GetLot(id uint, schemaName string) (*domain.StockLot, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	var lot domain.StockLot
+	if err := db.First(&lot, id).Error; err != nil {
+		return nil, err
+	}
+	return &lot, nil
+}
+
+func (r *inventoryRepository) Receive(receipt *domain.GoodsReceipt, schemaName string) error {
+	return r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		if err := tx.Omit("Items").Create(receipt).Error; err != nil {
+			return err
+		}
+
+		for i := range receipt.Items {
+			item := &receipt.Items[i]
+			item.GoodsReceiptID = receipt.ID
+
+			// Create the lot on its first receipt, then lock it so concurrent movements queue up
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&domain.StockLot{
+				LocationID: receipt.LocationID,
+				DrugID:     item.DrugID,
+				LotNumber:  item.LotNumber,
+				ExpiryDate: item.ExpiryDate,
+			}).Error; err != nil {
+				return err
+			}
+			var lot domain.StockLot
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("location_id = ? AND drug_id = ? AND lot_number = ?", receipt.LocationID, item.DrugID, item.LotNumber).
+				First(&lot).Error; err != nil {
+				return err
+			}
+			if lot.ExpiryDate.Format(domain.DateFormat) != item.ExpiryDate.Format(domain.DateFormat) {
+				return fmt.Errorf("%w: lot %s of drug %d is held with expiry date %s", domain.ErrInvalidInput, lot.LotNumber, lot.DrugID, lot.ExpiryDate.Format(domain.DateFormat))
+			}
+
+			item.LotID = lot.ID
+			if err := tx.Create(item).Error; err != nil {
+				return err
+			}
+			if err := moveStock(tx, &lot, &domain.StockMovement{
+				CreatedAt:      receipt.ReceivedAt,
+				Type:           domain.StockMovementReceipt,
+				Quantity:       item.Quantity,
+				GoodsReceiptID: &receipt.ID,
+				PerformedBy:    receipt.ReceivedBy,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *inventoryRepository) GetReceipt(id uint, schemaName string) (*domain.GoodsReceipt, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	var receipt domain.GoodsReceipt
+	if err := db.Preload("Items", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	}).First(&receipt, id).Error; err != nil {
+		return nil, err
+	}
+	return &receipt, nil
+}
+
+func (r *inventoryRepository) ListReceipts(filter *domain.GoodsReceiptFilter, schemaName string) ([]domain.GoodsReceipt, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	query := db.Preload("Items", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	})
+	if filter.LocationID != nil {
+		query = query.Where("location_id = ?", *filter.LocationID)
+	}
+	if filter.From != nil {
+		query = query.Where("received_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("received_at < ?", *filter.To)
+	}
+	var receipts []domain.GoodsReceipt
+	if err := query.Order("received_at DESC, id DESC").Find(&receipts).Error; err != nil {
+		return nil, err
+	}
+	return receipts, nil
+}
+
+func (r *inventoryRepository) Adjust(movement *domain.StockMovement, schemaName string) error {
+	return r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		var lot domain.StockLot
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&lot, movement.LotID).Error; err != nil {
+			return err
+		}
+		if lot.Quantity+movement.Quantity < 0 {
+			return fmt.Errorf("%w: lot %s holds %g, %g cannot be removed", domain.ErrInvalidInput, lot.LotNumber, lot.Quantity, -movement.Quantity)
+		}
+		return moveStock(tx, &lot, movement)
+	})
+}
+
+func (r *inventoryRepository) DispenseOrder(order *domain.MedicationOrder, fromStatus string, locationID uint, today time.Time, event *domain.AuditEvent, schemaName string) ([]domain.StockMovement, error) {
+	var movements []domain.StockMovement
+	err := r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		if err := saveMedicationOrderStatus(tx, order, fromStatus); err != nil {
+			return err
+		}
+
+		var lots []domain.StockLot
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("location_id = ? AND drug_id = ? AND quantity > 0 AND expiry_date >= ?", locationID, order.DrugID, today.Format(domain.DateFormat)).
+			Order("expiry_date, id").Find(&lots).Error; err != nil {
+			return err
+		}
+		picks, err := domain.PickFEFO(lots, order.Quantity, today)
+		if err != nil {
+			return err
+		}
+
+		for _, pick := range picks {
+			movement := domain.StockMovement{
+				Type:              domain.StockMovementDispense,
+				Quantity:          -pick.Quantity,
+				MedicationOrderID: &order.ID,
+			}
+			if order.DispensedAt != nil {
+				movement.CreatedAt = *order.DispensedAt
+			}
+			if order.DispensedBy != nil {
+				movement.PerformedBy = *order.DispensedBy
+			}
+			if err := moveStock(tx, pick.Lot, &movement); err != nil {
+				return err
+			}
+			movements = append(movements, movement)
+		}
+		return appendAuditEvents(tx, event)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return movements, nil
+}
+
+// moveStock applies a movement to a locked lot and appends it to the ledger
+func moveStock(tx *gorm.DB, lot *domain.StockLot, movement *domain.StockMovement) error {
+	lot.Quantity += movement.Quantity
+	if err := tx.Model(lot).Update("quantity", lot.Quantity).Error; err != nil {
+		return err
+	}
+
+	movement.LocationID = lot.LocationID
+	movement.DrugID = lot.DrugID
+	movement.LotID = lot.ID
+	movement.LotNumber = lot.LotNumber
+	movement.LotBalance = lot.Quantity
+	return tx.Create(movement).Error
+}
+
+func (r *inventoryRepository) ListMovements(filter *domain.StockMovementFilter, schemaName string) ([]domain.StockMovement, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	query := db.Where("location_id = ? AND drug_id = ?", filter.LocationID, filter.DrugID)
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+	var movements []domain.StockMovement
+	if err := query.Order("id").Find(&movements).Error; err != nil {
+		return nil, err
+	}
+	return movements, nil
+}
+
+func (r *inventoryRepository) Balance(locationID uint, drugID uint, before time.Time, schemaName string) (float64, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	var balance float64
+	if err := db.Model(&domain.StockMovement{}).
+		Where("location_id = ? AND drug_id = ? AND created_at < ?", locationID, drugID, before).
+		Select("COALESCE(SUM(quantity), 0)").Scan(&balance).Error; err != nil {
+		return 0, err
+	}
+	return balance, nil
+}
+
+// LowStock compares the usable stock of every drug with a reorder level against that level
+func (r *inventoryRepository) LowStock(locationID *uint, today time.Time, schemaName string) ([]domain.LowStockAlert, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	day := today.Format(domain.DateFormat)
+	onHand := "COALESCE(SUM(s.quantity) FILTER (WHERE s.expiry_date >= ?), 0)"
+	conditions := []string{"r.reorder_level > 0"}
+	args := []interface{}{day}
+	if locationID != nil {
+		conditions = append(conditions, "r.location_id = ?")
+		args = append(args, *locationID)
+	}
+	args = append(args, day)
+
+	query := fmt.Sprintf(`
+		SELECT r.location_id, l.code AS location_code, r.drug_id, d.code AS drug_code,
+			%s AS drug_name, d.dispensing_unit, %s AS on_hand, r.reorder_level
+		FROM stock_reorder_levels r
+		JOIN stock_locations l ON l.id = r.location_id AND l.deleted_at IS NULL AND l.is_active
+		JOIN drugs d ON d.id = r.drug_id AND d.deleted_at IS NULL AND d.is_active
+		LEFT JOIN stock_lots s ON s.location_id = r.location_id AND s.drug_id = r.drug_id
+		WHERE %s
+		GROUP BY r.location_id, l.code, r.drug_id, d.code, d.generic_name, d.strength, d.dispensing_unit, r.reorder_level
+		HAVING %s <= r.reorder_level
+		ORDER BY l.code, drug_name
+	`, drugNameColumn, onHand, strings.Join(conditions, " AND "), onHand)
+
+	var alerts []domain.LowStockAlert
+	if err := db.Raw(query, args...).Scan(&alerts).Error; err != nil {
+		return nil, err
+	}
+	return alerts, nil
+}
+
+func (r *inventoryRepository) NearExpiry(locationID *uint, before time.Time, schemaName string) ([]domain.NearExpiryAlert, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	conditions := []string{"s.quantity > 0", "s.expiry_date < ?"}
+	args := []interface{}{before.Format(domain.DateFormat)}
+	if locationID != nil {
+		conditions = append(conditions, "s.location_id = ?")
+		args = append(args, *locationID)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT s.id AS lot_id, s.location_id, l.code AS location_code, s.drug_id, d.code AS drug_code,
+			%s AS drug_name, s.lot_number, s.expiry_date, s.quantity
+		FROM stock_lots s
+		JOIN stock_locations l ON l.id = s.location_id
+		JOIN drugs d ON d.id = s.drug_id
+		WHERE %s
+		ORDER BY s.expiry_date, l.code, s.id
+	`, drugNameColumn, strings.Join(conditions, " AND "))
+
+	var alerts []domain.NearExpiryAlert
+	if err := db.Raw(query, args...).Scan(&alerts).Error; err != nil {
+		return nil, err
+	}
+	return alerts, nil
+}
+
+// RebuildBalances locks the lots against concurrent movements while it replaces each balance
+// with the sum of the lot's ledger entries
+func (r *inventoryRepository) RebuildBalances(schemaName string) (int64, error) {
+	var corrected int64
+	err := r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		if err := tx.Exec("LOCK TABLE stock_lots IN EXCLUSIVE MODE").Error; err != nil {
+			return err
+		}
+		ledgerBalance := "COALESCE((SELECT SUM(m.quantity) FROM stock_movements m WHERE m.lot_id = s.id), 0)"
+		result := tx.Exec(fmt.Sprintf(
+			"UPDATE stock_lots s SET quantity = %s, updated_at = NOW() WHERE s.quantity <> %s",
+			ledgerBalance, ledgerBalance,
+		))
+		if result.Error != nil {
+			return result.Error
+		}
+		corrected = result.RowsAffected
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return corrected, nil
+}
@@ -0,0 +1,256 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/infrastructure/database"
+	"gorm.io/gorm"
+)
+
+type labRepository struct {
+	*TenantAwareRepository
+}
+
+// NewLabRepository creates a new lab repository
+func NewLabRepository(db *gorm.DB, dbManager *database.TenantDBManager) domain.LabRepository {
+	return &labRepository{
+		TenantAwareRepository: NewTenantAwareRepository(db, dbManager),
+	}
+}
+
+// getDB returns the appropriate database based on schema
+func (r *labRepository) getDB(schemaName string) (*gorm.DB, error) {
+	if schemaName == "" || schemaName == "public" {
+		return r.GetDB(), nil
+	}
+	return r.GetTenantDB(schemaName)
+}
+
+func (r *labRepository) ListTests(query string, includeInactive bool, schemaName string) ([]domain.LabTest, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	q := db.Order("code")
+	if !includeInactive {
+		q = q.Where("is_active")
+	}
+	if query = strings.TrimSpace(query); query != "" {
+		pattern := "%" + likeEscaper.Replace(query) + "%"
+		q = q.Where("code ILIKE ? OR name ILIKE ?", pattern, pattern)
+	}
+	var tests []domain.LabTest
+	if err := q.Find(&tests).Error; err != nil {
+		return nil, err
+	}
+	return tests, nil
+}
+
+func (r *labRepository) GetTest(id uint, schemaName string) (*domain.LabTest, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	var test domain.LabTest
+	if err := db.First(&test, id).Error; err != nil {
+		return nil, err
+	}
+	return &test, nil
+}
+
+func (r *labRepository) ListTestsByIDs(ids []uint, schemaName string) ([]domain.LabTest, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	var tests []domain.LabTest
+	if err := db.Where("id IN ?", ids).Find(&tests).Error; err != nil {
+		return nil, err
+	}
+	return tests, nil
+}
+
+func (r *labRepository) CreateTest(test *domain.LabTest, schemaName string) error {
+	return r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		return tx.Create(test).Error
+	})
+}
+
+func (r *labRepository) UpdateTest(test *domain.LabTest, schemaName string) error {
+	return r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		return tx.Select("*").Omit("created_at").Updates(test).Error
+	})
+}
+
+func (r *labRepository) GetOrder(id uint, schemaName string) (*domain.LabOrder, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	var order domain.LabOrder
+	if err := preloadLabOrder(db).First(&order, id).Error; err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (r *labRepository) ListOrdersByEncounter(encounterID uint, schemaName string) ([]domain.LabOrder, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	var orders []domain.LabOrder
+	if err := preloadLabOrder(db).Where("encounter_id = ?", encounterID).Order("ordered_at, id").Find(&orders).Error; err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+func (r *labRepository) ListOrdersByPatient(patientID uint, status string, schemaName string) ([]domain.LabOrder, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	query := preloadLabOrder(db).Where(mergedPatientCondition, patientID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	var orders []domain.LabOrder
+	if err := query.Order("ordered_at DESC, id DESC").Find(&orders).Error; err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// preloadLabOrder loads the specimens and items of orders in a stable order
+func preloadLabOrder(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Specimens", func(tx *gorm.DB) *gorm.DB { return tx.Order("lab_specimens.id") }).
+		Preload("Items", func(tx *gorm.DB) *gorm.DB { return tx.Order("lab_order_items.id") })
+}
+
+func (r *labRepository) CreateOrder(order *domain.LabOrder, event *domain.AuditEvent, schemaName string) error {
+	return r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		if err := tx.Omit("Specimens", "Items").Create(order).Error; err != nil {
+			return err
+		}
+
+		// Items are linked to the specimen of their specimen type once it has an ID
+		specimenIDs := make(map[string]uint, len(order.Specimens))
+		period := domain.LabSpecimenBarcodePeriod(order.OrderedAt)
+		for i := range order.Specimens {
+			specimen := &order.Specimens[i]
+			seq, err := nextCounterValue(tx, "lab_specimen_counters", period, 0)
+			if err != nil {
+				return err
+			}
+			barcode, err := domain.FormatLabSpecimenBarcode(period, seq)
+			if err != nil {
+				return err
+			}
+			specimen.OrderID = order.ID
+			specimen.Barcode = barcode
+			if err := tx.Create(specimen).Error; err != nil {
+				return err
+			}
+			specimenIDs[specimen.SpecimenType] = specimen.ID
+		}
+		for i := range order.Items {
+			order.Items[i].OrderID = order.ID
+			order.Items[i].SpecimenID = specimenIDs[order.Items[i].SpecimenType]
+		}
+		if err := tx.Create(&order.Items).Error; err != nil {
+			return err
+		}
+
+		barcodes := make([]string, 0, len(order.Specimens))
+		for i := range order.Specimens {
+			barcodes = append(barcodes, order.Specimens[i].Barcode)
+		}
+		if err := event.SetChange("order_id", domain.FieldChange{After: order.ID}); err != nil {
+			return err
+		}
+		if err := event.SetChange("barcodes", domain.FieldChange{After: barcodes}); err != nil {
+			return err
+		}
+		return appendAuditEvents(tx, event)
+	})
+}
+
+func (r *labRepository) SaveOrder(order *domain.LabOrder, version time.Time, specimens []*domain.LabSpecimen, items []*domain.LabOrderItem, event *domain.AuditEvent, schemaName string) error {
+	return r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		// Every change to an order moves its updated_at, so two requests changing the same order
+		// cannot both pass this check
+		result := tx.Model(order).Where("updated_at = ?", version).
+			Select("status", "completed_at", "cancelled_by", "cancelled_at", "cancel_reason", "updated_at").
+			Updates(order)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrPreconditionFailed
+		}
+
+		for _, specimen := range specimens {
+			if err := tx.Select("*").Omit("created_at").Updates(specimen).Error; err != nil {
+				return err
+			}
+		}
+		for _, item := range items {
+			if err := tx.Select("*").Omit("created_at").Updates(item).Error; err != nil {
+				return err
+			}
+		}
+		return appendAuditEvents(tx, event)
+	})
+}
+
+func (r *labRepository) GetSpecimenByBarcode(barcode string, schemaName string) (*domain.LabSpecimen, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	var specimen domain.LabSpecimen
+	if err := db.Where("barcode = ?", barcode).First(&specimen).Error; err != nil {
+		return nil, err
+	}
+	return &specimen, nil
+}
+
+func (r *labRepository) ListSpecimens(filter *domain.LabSpecimenFilter, schemaName string) ([]domain.LabSpecimenEntry, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	query := db.Table("lab_specimens").
+		Select(`lab_specimens.*, lab_orders.patient_id, patients.patient_hn, patients.first_name_th, patients.last_name_th, lab_orders.priority,
+			(SELECT string_agg(lab_order_items.test_code, ' ' ORDER BY lab_order_items.test_code) FROM lab_order_items
+			WHERE lab_order_items.specimen_id = lab_specimens.id) AS tests`).
+		Joins("JOIN lab_orders ON lab_orders.id = lab_specimens.order_id").
+		Joins("JOIN patients ON patients.id = lab_orders.patient_id").
+		Where("lab_orders.deleted_at IS NULL AND lab_orders.status <> ?", domain.LabOrderStatusCancelled).
+		Where("lab_orders.ordered_at >= ? AND lab_orders.ordered_at < ?", filter.From, filter.To)
+	if filter.Status != "" {
+		query = query.Where("lab_specimens.status = ?", filter.Status)
+	}
+
+	var entries []domain.LabSpecimenEntry
+	if err := query.
+		Order(fmt.Sprintf("CASE lab_orders.priority WHEN '%s' THEN 0 WHEN '%s' THEN 1 ELSE 2 END", domain.LabPriorityStat, domain.LabPriorityUrgent)).
+		Order("lab_orders.ordered_at, lab_specimens.id").
+		Scan(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/infrastructure/database"
+	"gorm.io/gorm"
+)
+
+type medicationOrderRepository struct {
+	*TenantAwareRepository
+}
+
+// NewMedicationOrderRepository creates a new medication order repository
+func NewMedicationOrderRepository(db *gorm.DB, dbManager *database.TenantDBManager) domain.MedicationOrderRepository {
+	return &medicationOrderRepository{
+		TenantAwareRepository: NewTenantAwareRepository(db, dbManager),
+	}
+}
+
+// getDB returns the appropriate database based on schema
+func (r *medicationOrderRepository) getDB(schemaName string) (*gorm.DB, error) {
+	if schemaName == "" || schemaName == "public" {
+		return r.GetDB(), nil
+	}
+	return r.GetTenantDB(schemaName)
+}
+
+func (r *medicationOrderRepository) GetByID(id uint, schemaName string) (*domain.MedicationOrder, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	var order domain.MedicationOrder
+	if err := db.First(&order, id).Error; err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (r *medicationOrderRepository) ListByEncounter(encounterID uint, schemaName string) ([]domain.MedicationOrder, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	var orders []domain.MedicationOrder
+	if err := db.Where("encounter_id = ?", encounterID).Order("ordered_at, id").Find(&orders).Error; err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+func (r *medicationOrderRepository) ListByPatient(patientID uint, activeAt *time.Time, schemaName string) ([]domain.MedicationOrder, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	query := db.Where(mergedPatientCondition, patientID)
+	if activeAt != nil {
+		query = query.Where("status <> ? AND ends_at > ?", domain.MedicationStatusCancelled, *activeAt)
+	}
+	var orders []domain.MedicationOrder
+	if err := query.Order("ordered_at DESC, id DESC").Find(&orders).Error; err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+func (r *medicationOrderRepository) Create(order *domain.MedicationOrder, events []*domain.AuditEvent, schemaName string) error {
+	return r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		if err := tx.Create(order).Error; err != nil {
+			return err
+		}
+		return appendAuditEvents(tx, events...)
+	})
+}
+
+func (r *medicationOrderRepository) UpdateStatus(order *domain.MedicationOrder, fromStatus string, event *domain.AuditEvent, schemaName string) error {
+	return r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		if err := saveMedicationOrderStatus(tx, order, fromStatus); err != nil {
+			return err
+		}
+		return appendAuditEvents(tx, event)
+	})
+}
+
+// saveMedicationOrderStatus saves the status fields of an order while it still has fromStatus
+func saveMedicationOrderStatus(tx *gorm.DB, order *domain.MedicationOrder, fromStatus string) error {
+	result := tx.Model(order).Where("status = ?", fromStatus).
+		Select("status", "verified_by", "verified_at", "dispensed_by", "dispensed_at", "cancelled_by", "cancelled_at", "cancel_reason").
+		Updates(order)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrPreconditionFailed
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/infrastructure/database"
+	"gorm.io/gorm"
+)
+
+type patientAddressRepository struct {
+	*TenantAwareRepository
+}
+
+// NewPatientAddressRepository creates a new patient address repository
+func NewPatientAddressRepository(db *gorm.DB, dbManager *database.TenantDBManager) domain.PatientAddressRepository {
+	return &patientAddressRepository{
+		TenantAwareRepository: NewTenantAwareRepository(db, dbManager),
+	}
+}
+
+// getDB returns the appropriate database based on schema
+func (r *patientAddressRepository) getDB(schemaName string) (*gorm.DB, error) {
+	if schemaName == "" || schemaName == "public" {
+		return r.GetDB(), nil
+	}
+	return r.GetTenantDB(schemaName)
+}
+
+// ListByPatient returns the addresses of a patient in registered, current, work order
+func (r *patientAddressRepository) ListByPatient(patientID uint, schemaName string) ([]domain.PatientAddress, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	var addresses []domain.PatientAddress
+	if err := db.Where("patient_id = ?", patientID).
+		Order("array_position(ARRAY['registered','current','work']::varchar[], address_type), id").
+		Find(&addresses).Error; err != nil {
+		return nil, err
+	}
+	return addresses, nil
+}
+
+func (r *patientAddressRepository) GetByType(patientID uint, addressType string, schemaName string) (*domain.PatientAddress, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	var addresses []domain.PatientAddress
+	if err := db.Where("patient_id = ? AND address_type = ?", patientID, addressType).
+		Limit(1).
+		Find(&addresses).Error; err != nil {
+		return nil, err
+	}
+	if len(addresses) == 0 {
+		return nil, nil
+	}
+	return &addresses[0], nil
+}
+
+func (r *patientAddressRepository) Save(address *domain.PatientAddress, event *domain.AuditEvent, schemaName string) error {
+	return r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		if address.ID == 0 {
+			if err := tx.Create(address).Error; err != nil {
+				return err
+			}
+		} else if err := tx.Select("*").Omit("created_at").Updates(address).Error; err != nil {
+			return err
+		}
+		return appendAuditEvents(tx, event)
+	})
+}
+
+func (r *patientAddressRepository) Delete(address *domain.PatientAddress, event *domain.AuditEvent, schemaName string) error {
+	return r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		if err := tx.Delete(address).Error; err != nil {
+			return err
+		}
+		return appendAuditEvents(tx, event)
+	})
+}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/infrastructure/database"
+	"gorm.io/gorm"
+)
+
+// allergySeverityOrder sorts allergies most severe first
+const allergySeverityOrder = "array_position(ARRAY['life_threatening','severe','moderate','mild']::varchar[], severity), id"
+
+type patientAllergyRepository struct {
+	*TenantAwareRepository
+}
+
+// NewPatientAllergyRepository creates a new patient allergy repository
+func NewPatientAllergyRepository(db *gorm.DB, dbManager *database.TenantDBManager) domain.PatientAllergyRepository {
+	return &patientAllergyRepository{
+		TenantAwareRepository: NewTenantAwareRepository(db, dbManager),
+	}
+}
+
+// getDB returns the appropriate database based on schema
+func (r *patientAllergyRepository) getDB(schemaName string) (*gorm.DB, error) {
+	if schemaName == "" || schemaName == "public" {
+		return r.GetDB(), nil
+	}
+	return r.GetTenantDB(schemaName)
+}
+
+func (r *patientAllergyRepository) ListByPatient(patientID uint, schemaName string) ([]domain.PatientAllergy, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	var allergies []domain.PatientAllergy
+	if err := db.Where(mergedPatientCondition, patientID).Order(allergySeverityOrder).Find(&allergies).Error; err != nil {
+		return nil, err
+	}
+	return allergies, nil
+}
+
+func (r *patientAllergyRepository) GetByID(patientID uint, id uint, schemaName string) (*domain.PatientAllergy, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	var allergy domain.PatientAllergy
+	if err := db.Where(mergedPatientCondition+" AND id = ?", patientID, id).First(&allergy).Error; err != nil {
+		return nil, err
+	}
+	return &allergy, nil
+}
+
+func (r *patientAllergyRepository) Create(allergy *domain.PatientAllergy, event *domain.AuditEvent, schemaName string) error {
+	return r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		if err := tx.Create(allergy).Error; err != nil {
+			return err
+		}
+		return appendAuditEvents(tx, event)
+	})
+}
+
+func (r *patientAllergyRepository) Update(allergy *domain.PatientAllergy, event *domain.AuditEvent, schemaName string) error {
+	return r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		if err := tx.Select("*").Omit("created_at").Updates(allergy).Error; err != nil {
+			return err
+		}
+		return appendAuditEvents(tx, event)
+	})
+}
+
+func (r *patientAllergyRepository) Delete(allergy *domain.PatientAllergy, event *domain.AuditEvent, schemaName string) error {
+	return r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		if err := tx.Delete(allergy).Error; err != nil {
+			return err
+		}
+		return appendAuditEvents(tx, event)
+	})
+}
+
+// fillAllergyBanners sets the allergy banner of each patient for the page, including the
+// allergies of duplicates merged into them
+func fillAllergyBanners(db *gorm.DB, patients []*domain.Patient) error {
+	if len(patients) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, len(patients))
+	for i, patient := range patients {
+		ids[i] = patient.ID
+	}
+
+	// Map each patient of the page and every record merged into it back to the patient
+	var members []struct {
+		OwnerID   uint
+		PatientID uint
+	}
+	if err := db.Raw(`WITH RECURSIVE merged AS (
+			SELECT id AS owner_id, id AS patient_id FROM patients WHERE id IN ?
+			UNION
+			SELECT merged.owner_id, m.retired_id FROM patient_merges m JOIN merged ON m.survivor_id = merged.patient_id
+			WHERE m.unmerged_at IS NULL
+		) SELECT owner_id, patient_id FROM merged`, ids).Scan(&members).Error; err != nil {
+		return fmt.Errorf("failed to resolve merged patients: %w", err)
+	}
+	owners := make(map[uint][]uint, len(members))
+	memberIDs := make([]uint, 0, len(members))
+	for _, member := range members {
+		owners[member.PatientID] = append(owners[member.PatientID], member.OwnerID)
+		memberIDs = append(memberIDs, member.PatientID)
+	}
+
+	var allergies []domain.PatientAllergy
+	if err := db.Where("patient_id IN ?", memberIDs).Order(allergySeverityOrder).Find(&allergies).Error; err != nil {
+		return fmt.Errorf("failed to load allergies: %w", err)
+	}
+
+	byPatient := make(map[uint][]domain.PatientAllergy)
+	for _, allergy := range allergies {
+		for _, owner := range owners[allergy.PatientID] {
+			byPatient[owner] = append(byPatient[owner], allergy)
+		}
+	}
+	for _, patient := range patients {
+		patient.AllergyBanner = domain.BuildAllergyBanner(byPatient[patient.ID])
+	}
+	return nil
+}
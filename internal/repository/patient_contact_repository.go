@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/infrastructure/database"
+	"gorm.io/gorm"
+)
+
+type patientContactRepository struct {
+	*TenantAwareRepository
+}
+
+// NewPatientContactRepository creates a new patient contact repository
+func NewPatientContactRepository(db *gorm.DB, dbManager *database.TenantDBManager) domain.PatientContactRepository {
+	return &patientContactRepository{
+		TenantAwareRepository: NewTenantAwareRepository(db, dbManager),
+	}
+}
+
+// getDB returns the appropriate database based on schema
+func (r *patientContactRepository) getDB(schemaName string) (*gorm.DB, error) {
+	if schemaName == "" || schemaName == "public" {
+		return r.GetDB(), nil
+	}
+	return r.GetTenantDB(schemaName)
+}
+
+// ListByPatient returns the contacts of a patient, primary contact first
+func (r *patientContactRepository) ListByPatient(patientID uint, schemaName string) ([]domain.PatientContact, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	var contacts []domain.PatientContact
+	if err := db.Where("patient_id = ?", patientID).
+		Order("is_primary DESC, id").
+		Find(&contacts).Error; err != nil {
+		return nil, err
+	}
+	return contacts, nil
+}
+
+func (r *patientContactRepository) GetByID(patientID uint, id uint, schemaName string) (*domain.PatientContact, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	var contact domain.PatientContact
+	if err := db.Where("patient_id = ? AND id = ?", patientID, id).First(&contact).Error; err != nil {
+		return nil, err
+	}
+	return &contact, nil
+}
+
+func (r *patientContactRepository) GetPrimary(patientID uint, schemaName string) (*domain.PatientContact, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+	return findPrimaryContact(db, patientID)
+}
+
+func (r *patientContactRepository) Create(contact *domain.PatientContact, event *domain.AuditEvent, schemaName string) error {
+	return r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		if contact.IsPrimary {
+			if err := clearPrimaryContact(tx, contact.PatientID, 0); err != nil {
+				return err
+			}
+		}
+		if err := tx.Create(contact).Error; err != nil {
+			return err
+		}
+		return appendAuditEvents(tx, event)
+	})
+}
+
+func (r *patientContactRepository) Update(contact *domain.PatientContact, event *domain.AuditEvent, schemaName string) error {
+	return r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		if contact.IsPrimary {
+			if err := clearPrimaryContact(tx, contact.PatientID, contact.ID); err != nil {
+				return err
+			}
+		}
+		if err := tx.Select("*").Omit("created_at").Updates(contact).Error; err != nil {
+			return err
+		}
+		return appendAuditEvents(tx, event)
+	})
+}
+
+func (r *patientContactRepository) Delete(contact *domain.PatientContact, event *domain.AuditEvent, schemaName string) error {
+	return r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		if err := tx.Delete(contact).Error; err != nil {
+			return err
+		}
+		return appendAuditEvents(tx, event)
+	})
+}
+
+// findPrimaryContact returns the primary contact of a patient, nil when there is none
+func findPrimaryContact(db *gorm.DB, patientID uint) (*domain.PatientContact, error) {
+	var contacts []domain.PatientContact
+	if err := db.Where("patient_id = ? AND is_primary", patientID).Limit(1).Find(&contacts).Error; err != nil {
+		return nil, err
+	}
+	if len(contacts) == 0 {
+		return nil, nil
+	}
+	return &contacts[0], nil
+}
+
+// clearPrimaryContact removes the primary flag from the contacts of a patient other than exceptID
+func clearPrimaryContact(tx *gorm.DB, patientID uint, exceptID uint) error {
+	return tx.Model(&domain.PatientContact{}).
+		Where("patient_id = ? AND is_primary AND id <> ?", patientID, exceptID).
+		Update("is_primary", false).Error
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/infrastructure/database"
+	"gorm.io/gorm"
+)
+
+// coverageSchemeOrder sorts coverages in domain.CoverageSchemes order, newest first within a scheme
+const coverageSchemeOrder = "array_position(ARRAY['csmbs','sss','uc','private','self_pay']::varchar[], scheme), valid_from DESC, id"
+
+type patientCoverageRepository struct {
+	*TenantAwareRepository
+}
+
+// NewPatientCoverageRepository creates a new patient coverage repository
+func NewPatientCoverageRepository(db *gorm.DB, dbManager *database.TenantDBManager) domain.PatientCoverageRepository {
+	return &patientCoverageRepository{
+		TenantAwareRepository: NewTenantAwareRepository(db, dbManager),
+	}
+}
+
+// getDB returns the appropriate database based on schema
+func (r *patientCoverageRepository) getDB(schemaName string) (*gorm.DB, error) {
+	if schemaName == "" || schemaName == "public" {
+		return r.GetDB(), nil
+	}
+	return r.GetTenantDB(schemaName)
+}
+
+func (r *patientCoverageRepository) ListByPatient(patientID uint, schemaName string) ([]domain.PatientCoverage, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	var coverages []domain.PatientCoverage
+	if err := db.Where(mergedPatientCondition, patientID).Order(coverageSchemeOrder).Find(&coverages).Error; err != nil {
+		return nil, err
+	}
+	return coverages, nil
+}
+
+func (r *patientCoverageRepository) ListActive(patientID uint, on time.Time, schemaName string) ([]domain.PatientCoverage, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	// Compare calendar days so the time zone of on does not shift the boundaries
+	day := on.Format(domain.DateFormat)
+	var coverages []domain.PatientCoverage
+	if err := db.Where(mergedPatientCondition+" AND valid_from <= ? AND (valid_to IS NULL OR valid_to >= ?)", patientID, day, day).
+		Order(coverageSchemeOrder).Find(&coverages).Error; err != nil {
+		return nil, err
+	}
+	return coverages, nil
+}
+
+func (r *patientCoverageRepository) GetByID(patientID uint, id uint, schemaName string) (*domain.PatientCoverage, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	var coverage domain.PatientCoverage
+	if err := db.Where(mergedPatientCondition+" AND id = ?", patientID, id).First(&coverage).Error; err != nil {
+		return nil, err
+	}
+	return &coverage, nil
+}
+
+func (r *patientCoverageRepository) Create(coverage *domain.PatientCoverage, event *domain.AuditEvent, schemaName string) error {
+	return r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		if err := tx.Create(coverage).Error; err != nil {
+			return err
+		}
+		return appendAuditEvents(tx, event)
+	})
+}
+
+func (r *patientCoverageRepository) Update(coverage *domain.PatientCoverage, event *domain.AuditEvent, schemaName string) error {
+	return r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		if err := tx.Select("*").Omit("created_at").Updates(coverage).Error; err != nil {
+			return err
+		}
+		return appendAuditEvents(tx, event)
+	})
+}
+
+func (r *patientCoverageRepository) Delete(coverage *domain.PatientCoverage, event *domain.AuditEvent, schemaName string) error {
+	return r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		if err := tx.Delete(coverage).Error; err != nil {
+			return err
+		}
+		return appendAuditEvents(tx, event)
+	})
+}
@@ -2,10 +2,13 @@ package repository
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/wichai2002/his_v1/internal/domain"
 	"github.com/wichai2002/his_v1/internal/infrastructure/database"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type patientRepository struct {
@@ -60,100 +63,645 @@ func (r *patientRepository) SearchByID(id uint, schemaName string) (*domain.Pati
 		return nil, fmt.Errorf("failed to get tenant db: %w", err)
 	}
 
+	// The ID of a retired patient resolves to the record it was merged into
 	var patient domain.Patient
 	if err := db.Where("id = ?", id).
 		Or("national_id = ?", id).
 		Or("passport_id = ?", id).
+		Or("id IN ("+mergedSurvivorsSQL("retired_id = ?")+")", id).
 		First(&patient).Error; err != nil {
 		return nil, err
 	}
+
+	primary, err := findPrimaryContact(db, patient.ID)
+	if err != nil {
+		return nil, err
+	}
+	patient.PrimaryContact = primary
+
+	if err := fillAllergyBanners(db, []*domain.Patient{&patient}); err != nil {
+		return nil, err
+	}
 	return &patient, nil
 }
 
-// Search patient by query: first name, last name, middle name, patient HN, national ID, passport ID, phone number
-func (r *patientRepository) Search(query string, schemaName string) ([]domain.Patient, error) {
+// mergedSurvivorsSQL selects the surviving patients of the active merges matching condition.
+// A survivor may itself have been merged away later, so the chain is followed to its end;
+// the intermediate records are soft-deleted and drop out of the outer query.
+func mergedSurvivorsSQL(condition string) string {
+	return `WITH RECURSIVE chain AS (
+		SELECT survivor_id FROM patient_merges WHERE unmerged_at IS NULL AND ` + condition + `
+		UNION
+		SELECT m.survivor_id FROM patient_merges m JOIN chain ON m.retired_id = chain.survivor_id
+		WHERE m.unmerged_at IS NULL
+	) SELECT survivor_id FROM chain`
+}
+
+// mergedPatientsSQL selects a patient and the patients retired into it by active merges,
+// following merges of merges. It takes the patient ID as its only parameter.
+const mergedPatientsSQL = `WITH RECURSIVE merged AS (
+		SELECT CAST(? AS INTEGER) AS patient_id
+		UNION
+		SELECT m.retired_id FROM patient_merges m JOIN merged ON m.survivor_id = merged.patient_id
+		WHERE m.unmerged_at IS NULL
+	) SELECT patient_id FROM merged`
+
+// mergedPatientCondition matches the rows of a patient and of the duplicates merged into it.
+// A merge leaves the history of the retired record in place, so reads of clinical history go
+// through it: allergies, visits and orders of the duplicate show on the surviving record, and
+// drop off again when the merge is reversed.
+const mergedPatientCondition = "patient_id IN (" + mergedPatientsSQL + ")"
+
+// Search patients matching the filter, ordered by the sort column then ID.
+// The query matches first name, last name, patient HN, national ID, passport ID and phone number,
+// and the HNs of merged patients find the record they were merged into.
+func (r *patientRepository) Search(filter *domain.PatientSearchFilter, schemaName string) ([]domain.Patient, int64, error) {
 	db, err := r.getDB(schemaName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+		return nil, 0, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	query := db.Model(&domain.Patient{})
+
+	if filter.Query != "" {
+		searchQuery := "%" + filter.Query + "%"
+		query = query.Where(db.Where("first_name_th ILIKE ?", searchQuery).
+			Or("last_name_th ILIKE ?", searchQuery).
+			Or("first_name_en ILIKE ?", searchQuery).
+			Or("last_name_en ILIKE ?", searchQuery).
+			Or("patient_hn ILIKE ?", searchQuery).
+			Or("id IN ("+mergedSurvivorsSQL("retired_hn ILIKE ?")+")", searchQuery).
+			Or("national_id ILIKE ?", searchQuery).
+			Or("passport_id ILIKE ?", searchQuery).
+			Or("phone_number ILIKE ?", searchQuery))
+	}
+	query = applyPatientFilters(query, filter)
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// SortColumn comes from domain.PatientSortColumns, never from user input directly
+	direction, operator := "ASC", ">"
+	if filter.SortDesc {
+		direction, operator = "DESC", "<"
+	}
+
+	// Keyset pagination: continue after the last row of the previous page
+	if filter.AfterValue != nil {
+		query = query.Where(fmt.Sprintf("(%s, id) %s (?, ?)", filter.SortColumn, operator), filter.AfterValue, filter.AfterID)
 	}
 
 	var patients []domain.Patient
+	if err := query.Order(fmt.Sprintf("%s %s, id %s", filter.SortColumn, direction, direction)).
+		Limit(filter.Limit).
+		Find(&patients).Error; err != nil {
+		return nil, 0, err
+	}
+
+	page := make([]*domain.Patient, len(patients))
+	for i := range patients {
+		page[i] = &patients[i]
+	}
+	if err := fillAllergyBanners(db, page); err != nil {
+		return nil, 0, err
+	}
+
+	return patients, total, nil
+}
+
+// fuzzyNameExpressions are the name expressions compared by fuzzy search.
+// Each has a trigram GIN index created with the tenant schema.
+var fuzzyNameExpressions = []string{
+	"first_name_th",
+	"last_name_th",
+	"first_name_en",
+	"last_name_en",
+	"(first_name_th || ' ' || last_name_th)",
+	"(first_name_en || ' ' || last_name_en)",
+}
+
+// FuzzySearch ranks patients by the best trigram similarity between the query and their names
+func (r *patientRepository) FuzzySearch(filter *domain.PatientSearchFilter, schemaName string) ([]domain.PatientSearchItem, int64, error) {
+	var items []domain.PatientSearchItem
+	var total int64
+
+	err := r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		// The % operator matches above this threshold and can be answered from the trigram indexes
+		if err := tx.Exec(fmt.Sprintf("SET LOCAL pg_trgm.similarity_threshold = %g", domain.FuzzySearchThreshold)).Error; err != nil {
+			return fmt.Errorf("failed to set similarity threshold: %w", err)
+		}
+
+		matches := make([]string, len(fuzzyNameExpressions))
+		scores := make([]string, len(fuzzyNameExpressions))
+		args := make([]interface{}, len(fuzzyNameExpressions))
+		for i, expression := range fuzzyNameExpressions {
+			matches[i] = expression + " % ?"
+			scores[i] = "similarity(" + expression + ", ?)"
+			args[i] = filter.Query
+		}
+		scoreSQL := "GREATEST(" + strings.Join(scores, ", ") + ")"
+
+		query := applyPatientFilters(tx.Model(&domain.Patient{}).Where(strings.Join(matches, " OR "), args...), filter)
 
-	if query == "" {
-		// Return all patients if no query
-		if err := db.Find(&patients).Error; err != nil {
-			return nil, err
+		if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+			return err
 		}
-		return patients, nil
+
+		// Keyset pagination on (score, id), both descending
+		if filter.AfterValue != nil {
+			cursorArgs := append(append([]interface{}{}, args...), filter.AfterValue, filter.AfterID)
+			query = query.Where("("+scoreSQL+", id) < (?, ?)", cursorArgs...)
+		}
+
+		if err := query.Select("patients.*, "+scoreSQL+" AS score", args...).
+			Order("score DESC, id DESC").
+			Limit(filter.Limit).
+			Find(&items).Error; err != nil {
+			return err
+		}
+
+		page := make([]*domain.Patient, len(items))
+		for i := range items {
+			page[i] = &items[i].Patient
+		}
+		return fillAllergyBanners(tx, page)
+	})
+	if err != nil {
+		return nil, 0, err
 	}
 
-	searchQuery := "%" + query + "%"
-	if err := db.Where("first_name_th ILIKE ?", searchQuery).
-		Or("last_name_th ILIKE ?", searchQuery).
-		Or("first_name_en ILIKE ?", searchQuery).
-		Or("last_name_en ILIKE ?", searchQuery).
-		Or("patient_hn ILIKE ?", searchQuery).
-		Or("national_id ILIKE ?", searchQuery).
-		Or("passport_id ILIKE ?", searchQuery).
-		Or("phone_number ILIKE ?", searchQuery).
-		Find(&patients).Error; err != nil {
+	return items, total, nil
+}
+
+// applyPatientFilters adds the structured search filters to a patient query
+func applyPatientFilters(query *gorm.DB, filter *domain.PatientSearchFilter) *gorm.DB {
+	if filter.Gender != "" {
+		query = query.Where("gender = ?", filter.Gender)
+	}
+	if filter.BloodGrp != "" {
+		query = query.Where("blood_grp = ?", filter.BloodGrp)
+	}
+	if filter.Nationality != "" {
+		// Exact match ignoring case; ILIKE would treat % and _ in the value as wildcards
+		query = query.Where("LOWER(nationality) = LOWER(?)", filter.Nationality)
+	}
+	if filter.DOBFrom != nil {
+		query = query.Where("date_of_birth >= ?", *filter.DOBFrom)
+	}
+	if filter.DOBTo != nil {
+		query = query.Where("date_of_birth < ?", *filter.DOBTo)
+	}
+	if filter.CreatedFrom != nil {
+		query = query.Where("created_at >= ?", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		query = query.Where("created_at < ?", *filter.CreatedTo)
+	}
+	return query
+}
+
+// FindDuplicateCandidates looks up patients that may be the same person as probe.
+// Exact identifier matches sort first, then phone and date of birth matches, then name similarity.
+func (r *patientRepository) FindDuplicateCandidates(probe *domain.Patient, limit int, schemaName string) ([]domain.PatientSearchItem, error) {
+	var items []domain.PatientSearchItem
+
+	err := r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		if err := tx.Exec(fmt.Sprintf("SET LOCAL pg_trgm.similarity_threshold = %g", domain.FuzzySearchThreshold)).Error; err != nil {
+			return fmt.Errorf("failed to set similarity threshold: %w", err)
+		}
+
+		nameTH := probe.FirstNameTH + " " + probe.LastNameTH
+		nameEN := probe.FirstNameEN + " " + probe.LastNameEN
+		scoreSQL := "GREATEST(similarity(first_name_th || ' ' || last_name_th, ?), similarity(first_name_en || ' ' || last_name_en, ?))"
+
+		// NULL never equals anything, so blank identifiers of the probe match no rows
+		identifierMatch := "(national_id = ? OR passport_id = ?)"
+		contactMatch := "(phone_number = ? OR date_of_birth = ?)"
+		nameMatch := "((first_name_th || ' ' || last_name_th) % ? OR (first_name_en || ' ' || last_name_en) % ?)"
+
+		// Match flags are selected so the ordering can refer to them by alias
+		selectSQL := "patients.*, " + scoreSQL + " AS score, " +
+			"COALESCE(" + identifierMatch + ", FALSE) AS identifier_match, " +
+			"COALESCE(phone_number = ?, FALSE) AS phone_match"
+
+		return tx.Model(&domain.Patient{}).
+			Where(identifierMatch+" OR "+contactMatch+" OR "+nameMatch,
+				probe.NationalID, probe.PassportID, probe.PhoneNumber, probe.DateOfBirth, nameTH, nameEN).
+			Select(selectSQL, nameTH, nameEN, probe.NationalID, probe.PassportID, probe.PhoneNumber).
+			Order("identifier_match DESC, phone_match DESC, score DESC, id ASC").
+			Limit(limit).
+			Find(&items).Error
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	return patients, nil
+	return items, nil
+}
+
+// Create inserts the patient and its audit events in one transaction
+func (r *patientRepository) Create(patient *domain.Patient, issuer *domain.HNIssuer, events []*domain.AuditEvent, schemaName string) error {
+	return r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		now := time.Now()
+		period := issuer.Template.Period(now)
+		seq, err := nextHNSequence(tx, period, issuer.Seed(period))
+		if err != nil {
+			return err
+		}
+		hn, err := issuer.Template.Format(issuer.HospitalCode, now, seq)
+		if err != nil {
+			return err
+		}
+		patient.PatientHN = hn
+
+		if err := tx.Create(patient).Error; err != nil {
+			return err
+		}
+		for _, event := range events {
+			event.PatientID = &patient.ID
+		}
+		if err := appendAuditEvents(tx, events...); err != nil {
+			return err
+		}
+
+		var creator *domain.AuditEvent
+		if len(events) > 0 {
+			creator = events[0]
+		}
+		version := domain.NewPatientVersion(patient, domain.PatientVersionCreate, creator)
+		version.Version = 1
+		return tx.Create(version).Error
+	})
+}
+
+func (r *patientRepository) Update(patient *domain.Patient, event *domain.AuditEvent, schemaName string) error {
+	return r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		if err := ensureBaselineVersion(tx, patient.ID); err != nil {
+			return err
+		}
+		// Only the version that was read is overwritten, so a concurrent update is not lost
+		expected := patient.Version
+		patient.Version++
+		result := tx.Model(patient).Where("version = ?", expected).Select("*").Omit("created_at").Updates(patient)
+		if result.Error != nil {
+			patient.Version = expected
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			patient.Version = expected
+			return domain.ErrPreconditionFailed
+		}
+		if err := appendAuditEvents(tx, event); err != nil {
+			return err
+		}
+		return appendPatientVersion(tx, patient.ID, domain.PatientVersionUpdate, event)
+	})
+}
+
+func (r *patientRepository) PartialUpdate(id uint, version uint, updates map[string]interface{}, event *domain.AuditEvent, schemaName string) error {
+	return r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		// Fails with gorm.ErrRecordNotFound when the patient does not exist
+		if err := ensureBaselineVersion(tx, id); err != nil {
+			return err
+		}
+
+		columns := make(map[string]interface{}, len(updates)+1)
+		for column, value := range updates {
+			columns[column] = value
+		}
+		columns["version"] = gorm.Expr("version + 1")
+
+		result := tx.Model(&domain.Patient{}).Where("id = ? AND version = ?", id, version).Updates(columns)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		// The row exists, so no match means another write has moved the version on
+		if result.RowsAffected == 0 {
+			return domain.ErrPreconditionFailed
+		}
+
+		if err := appendAuditEvents(tx, event); err != nil {
+			return err
+		}
+		return appendPatientVersion(tx, id, domain.PatientVersionPartialUpdate, event)
+	})
+}
+
+// ensureBaselineVersion locks the patient row for the rest of the transaction, serialising
+// version numbers, and snapshots patients registered before versioning as their first version
+func ensureBaselineVersion(tx *gorm.DB, patientID uint) error {
+	var patient domain.Patient
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&patient, patientID).Error; err != nil {
+		return err
+	}
+
+	var count int64
+	if err := tx.Model(&domain.PatientVersion{}).Where("patient_id = ?", patientID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	baseline := domain.NewPatientVersion(&patient, domain.PatientVersionBaseline, nil)
+	baseline.Version = 1
+	baseline.CreatedAt = patient.CreatedAt
+	return tx.Create(baseline).Error
+}
+
+// appendPatientVersion snapshots the patient row as written by the current transaction
+func appendPatientVersion(tx *gorm.DB, patientID uint, action string, event *domain.AuditEvent) error {
+	var patient domain.Patient
+	if err := tx.First(&patient, patientID).Error; err != nil {
+		return err
+	}
+
+	version := domain.NewPatientVersion(&patient, action, event)
+	if err := tx.Model(&domain.PatientVersion{}).Where("patient_id = ?", patientID).
+		Select("COALESCE(MAX(version), 0) + 1").Scan(&version.Version).Error; err != nil {
+		return err
+	}
+	return tx.Create(version).Error
 }
 
-func (r *patientRepository) Create(patient *domain.Patient, schemaName string) error {
+func (r *patientRepository) ListVersions(patientID uint, schemaName string) ([]domain.PatientVersion, error) {
 	db, err := r.getDB(schemaName)
 	if err != nil {
-		return fmt.Errorf("failed to get tenant db: %w", err)
+		return nil, err
+	}
+
+	var versions []domain.PatientVersion
+	if err := db.Where("patient_id = ?", patientID).Order("version ASC").Find(&versions).Error; err != nil {
+		return nil, err
 	}
-	return db.Create(patient).Error
+	return versions, nil
+}
+
+func (r *patientRepository) Delete(id uint, event *domain.AuditEvent, schemaName string) error {
+	return r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		result := tx.Delete(&domain.Patient{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		// Check if any rows were affected (record exists)
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return appendAuditEvents(tx, event)
+	})
+}
+
+// Merge retires a patient into the survivor. Both rows are locked so neither can be deleted
+// or merged elsewhere concurrently.
+func (r *patientRepository) Merge(merge *domain.PatientMerge, events []*domain.AuditEvent, schemaName string) error {
+	return r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		var patients []domain.Patient
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ?", []uint{merge.SurvivorID, merge.RetiredID}).
+			Find(&patients).Error; err != nil {
+			return err
+		}
+		if len(patients) != 2 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if err := tx.Create(merge).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&domain.Patient{}, merge.RetiredID).Error; err != nil {
+			return err
+		}
+
+		return appendAuditEvents(tx, events...)
+	})
+}
+
+// Unmerge reverses an active merge and restores the retired patient
+func (r *patientRepository) Unmerge(merge *domain.PatientMerge, events []*domain.AuditEvent, schemaName string) error {
+	return r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		result := tx.Model(&domain.PatientMerge{}).
+			Where("id = ? AND unmerged_at IS NULL", merge.ID).
+			Updates(map[string]interface{}{
+				"unmerged_at":          merge.UnmergedAt,
+				"unmerged_by":          merge.UnmergedBy,
+				"unmerged_by_username": merge.UnmergedByUsername,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		// Already reversed by a concurrent request
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if err := tx.Unscoped().Model(&domain.Patient{}).
+			Where("id = ?", merge.RetiredID).
+			Updates(map[string]interface{}{"deleted_at": nil, "version": gorm.Expr("version + 1")}).Error; err != nil {
+			return err
+		}
+
+		return appendAuditEvents(tx, events...)
+	})
 }
 
-func (r *patientRepository) Update(patient *domain.Patient, schemaName string) error {
+func (r *patientRepository) GetMerge(id uint, schemaName string) (*domain.PatientMerge, error) {
 	db, err := r.getDB(schemaName)
 	if err != nil {
-		return fmt.Errorf("failed to get tenant db: %w", err)
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
 	}
-	return db.Save(patient).Error
+
+	var merge domain.PatientMerge
+	if err := db.First(&merge, id).Error; err != nil {
+		return nil, err
+	}
+	return &merge, nil
 }
 
-func (r *patientRepository) PartialUpdate(id uint, updates map[string]interface{}, schemaName string) error {
+func (r *patientRepository) ListMerges(patientID *uint, schemaName string) ([]domain.PatientMerge, error) {
 	db, err := r.getDB(schemaName)
 	if err != nil {
-		return fmt.Errorf("failed to get tenant db: %w", err)
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	query := db.Model(&domain.PatientMerge{})
+	if patientID != nil {
+		query = query.Where("survivor_id = ? OR retired_id = ?", *patientID, *patientID)
 	}
 
-	// Check if record exists and update in one operation
-	result := db.Model(&domain.Patient{}).Where("id = ?", id).Updates(updates)
-	if result.Error != nil {
-		return result.Error
+	var merges []domain.PatientMerge
+	if err := query.Order("id DESC").Find(&merges).Error; err != nil {
+		return nil, err
 	}
+	return merges, nil
+}
+
+// notRetiredByMergeSQL excludes patients retired by an active merge, which are restored by unmerge
+const notRetiredByMergeSQL = "NOT EXISTS (SELECT 1 FROM patient_merges m WHERE m.retired_id = patients.id AND m.unmerged_at IS NULL)"
 
-	// Check if any rows were affected (record exists)
-	if result.RowsAffected == 0 {
-		return gorm.ErrRecordNotFound
+func (r *patientRepository) ListDeleted(deletedBefore *time.Time, schemaName string) ([]domain.Patient, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
 	}
 
-	return nil
+	query := db.Unscoped().Where("deleted_at IS NOT NULL").Where(notRetiredByMergeSQL)
+	if deletedBefore != nil {
+		query = query.Where("deleted_at < ?", *deletedBefore)
+	}
+
+	var patients []domain.Patient
+	if err := query.Order("deleted_at DESC, id DESC").Find(&patients).Error; err != nil {
+		return nil, err
+	}
+	return patients, nil
 }
 
-func (r *patientRepository) Delete(id uint, schemaName string) error {
+func (r *patientRepository) GetDeleted(id uint, schemaName string) (*domain.Patient, error) {
 	db, err := r.getDB(schemaName)
 	if err != nil {
-		return fmt.Errorf("failed to get tenant db: %w", err)
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	var patient domain.Patient
+	if err := db.Unscoped().Where("deleted_at IS NOT NULL").Where(notRetiredByMergeSQL).First(&patient, id).Error; err != nil {
+		return nil, err
 	}
+	return &patient, nil
+}
 
-	result := db.Delete(&domain.Patient{}, id)
-	if result.Error != nil {
-		return result.Error
+func (r *patientRepository) FindLiveByUniqueFields(p *domain.Patient, schemaName string) ([]domain.Patient, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
 	}
 
-	// Check if any rows were affected (record exists)
-	if result.RowsAffected == 0 {
-		return gorm.ErrRecordNotFound
+	// NULL and blank values never conflict
+	var patients []domain.Patient
+	if err := db.Where("id <> ?", p.ID).
+		Where(db.Where("national_id = ?", p.NationalID).
+			Or("passport_id = ?", p.PassportID).
+			Or("phone_number <> '' AND phone_number = ?", p.PhoneNumber).
+			Or("email <> '' AND email = ?", p.Email)).
+		Find(&patients).Error; err != nil {
+		return nil, err
 	}
+	return patients, nil
+}
 
-	return nil
+// Restore clears the deletion of a patient; the live unique indexes reject values taken since
+func (r *patientRepository) Restore(id uint, event *domain.AuditEvent, schemaName string) error {
+	return r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		result := tx.Unscoped().Model(&domain.Patient{}).
+			Where("id = ? AND deleted_at IS NOT NULL", id).
+			Where(notRetiredByMergeSQL).
+			Updates(map[string]interface{}{"deleted_at": nil, "version": gorm.Expr("version + 1")})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return appendAuditEvents(tx, event)
+	})
+}
+
+// patientDetailTables hold details of the patient record itself and are removed with it
+var patientDetailTables = []string{"patient_versions", "patient_contacts", "patient_addresses"}
+
+// patientHistoryTables hold the clinical and payer history of a patient. It falls under medical
+// record retention, so patients with rows in any of them, deleted or not, are never purged.
+var patientHistoryTables = []string{
+	"patient_allergies", "patient_coverages", "encounters", "appointments",
+	"vital_signs", "diagnoses", "medication_orders", "lab_orders",
+}
+
+// Purge removes patients and the details of their record for good. Patients involved in a merge
+// or with clinical history are kept, as are those restored since they were listed.
+func (r *patientRepository) Purge(ids []uint, deletedBefore time.Time, events []*domain.AuditEvent, schemaName string) (int, []domain.PurgeRetention, error) {
+	if len(ids) == 0 {
+		return 0, nil, nil
+	}
+
+	var purged []uint
+	var retained []domain.PurgeRetention
+	err := r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		// Lock the rows still eligible so a concurrent restore or new visit cannot slip in between
+		var eligible []uint
+		if err := tx.Unscoped().Model(&domain.Patient{}).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ? AND deleted_at IS NOT NULL AND deleted_at < ?", ids, deletedBefore).
+			Order("id").
+			Pluck("id", &eligible).Error; err != nil {
+			return err
+		}
+		if len(eligible) == 0 {
+			return nil
+		}
+
+		reasons := make(map[uint]string, len(eligible))
+		var merged []uint
+		if err := tx.Raw(`
+			SELECT retired_id FROM patient_merges WHERE retired_id IN ?
+			UNION SELECT survivor_id FROM patient_merges WHERE survivor_id IN ?
+		`, eligible, eligible).Scan(&merged).Error; err != nil {
+			return err
+		}
+		for _, id := range merged {
+			reasons[id] = domain.PurgeRetainedMerged
+		}
+		for _, table := range patientHistoryTables {
+			var withHistory []uint
+			if err := tx.Table(table).Distinct("patient_id").Where("patient_id IN ?", eligible).
+				Pluck("patient_id", &withHistory).Error; err != nil {
+				return err
+			}
+			for _, id := range withHistory {
+				if _, ok := reasons[id]; !ok {
+					reasons[id] = domain.PurgeRetainedHistory
+				}
+			}
+		}
+
+		for _, id := range eligible {
+			if reason, ok := reasons[id]; ok {
+				retained = append(retained, domain.PurgeRetention{ID: id, Reason: reason})
+			} else {
+				purged = append(purged, id)
+			}
+		}
+		if len(purged) == 0 {
+			return nil
+		}
+
+		for _, table := range patientDetailTables {
+			if err := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE patient_id IN ?", table), purged).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Unscoped().Delete(&domain.Patient{}, purged).Error; err != nil {
+			return err
+		}
+
+		kept := make(map[uint]bool, len(purged))
+		for _, id := range purged {
+			kept[id] = true
+		}
+		purgedEvents := make([]*domain.AuditEvent, 0, len(purged))
+		for _, event := range events {
+			if event.PatientID != nil && kept[*event.PatientID] {
+				purgedEvents = append(purgedEvents, event)
+			}
+		}
+		return appendAuditEvents(tx, purgedEvents...)
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	return len(purged), retained, nil
 }
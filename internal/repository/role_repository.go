@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/infrastructure/database"
+	"gorm.io/gorm"
+)
+
+type roleRepository struct {
+	*TenantAwareRepository
+}
+
+// NewRoleRepository creates a new role repository
+func NewRoleRepository(db *gorm.DB, dbManager *database.TenantDBManager) domain.RoleRepository {
+	return &roleRepository{
+		TenantAwareRepository: NewTenantAwareRepository(db, dbManager),
+	}
+}
+
+// getDB returns the appropriate database based on schema
+func (r *roleRepository) getDB(schemaName string) (*gorm.DB, error) {
+	if schemaName == "" || schemaName == "public" {
+		return r.GetDB(), nil
+	}
+	return r.GetTenantDB(schemaName)
+}
+
+func (r *roleRepository) GetAll(schemaName string) ([]domain.Role, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	var roles []domain.Role
+	if err := db.Preload("Permissions").Order("id ASC").Find(&roles).Error; err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+func (r *roleRepository) GetByID(id uint, schemaName string) (*domain.Role, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	var role domain.Role
+	if err := db.Preload("Permissions").First(&role, id).Error; err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (r *roleRepository) GetByCodes(codes []string, schemaName string) ([]domain.Role, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	var roles []domain.Role
+	if err := db.Where("code IN ?", codes).Find(&roles).Error; err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+func (r *roleRepository) GetAllPermissions(schemaName string) ([]domain.Permission, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	var permissions []domain.Permission
+	if err := db.Order("code ASC").Find(&permissions).Error; err != nil {
+		return nil, err
+	}
+	return permissions, nil
+}
+
+func (r *roleRepository) GetPermissionsByCodes(codes []string, schemaName string) ([]domain.Permission, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	var permissions []domain.Permission
+	if err := db.Where("code IN ?", codes).Find(&permissions).Error; err != nil {
+		return nil, err
+	}
+	return permissions, nil
+}
+
+func (r *roleRepository) Create(role *domain.Role, schemaName string) error {
+	return r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		// Permissions already exist, only the join rows are written
+		return tx.Omit("Permissions.*").Create(role).Error
+	})
+}
+
+// Update saves role attributes and replaces its permission set in one transaction
+func (r *roleRepository) Update(role *domain.Role, schemaName string) error {
+	return r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		if err := tx.Omit("Permissions").Save(role).Error; err != nil {
+			return err
+		}
+		return tx.Model(role).Omit("Permissions.*").Association("Permissions").Replace(role.Permissions)
+	})
+}
+
+// Delete hard-deletes the role together with its permission grants and staff assignments,
+// so the code of a deleted custom role can be reused
+func (r *roleRepository) Delete(id uint, schemaName string) error {
+	return r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM role_permissions WHERE role_id = ?", id).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM staff_roles WHERE role_id = ?", id).Error; err != nil {
+			return err
+		}
+
+		result := tx.Unscoped().Delete(&domain.Role{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+// ReplaceStaffRoles replaces all roles attached to a staff member
+func (r *roleRepository) ReplaceStaffRoles(staffID uint, roles []domain.Role, schemaName string) error {
+	return r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		staff := &domain.Staff{}
+		if err := tx.First(staff, staffID).Error; err != nil {
+			return err
+		}
+		return tx.Model(staff).Omit("Roles.*").Association("Roles").Replace(roles)
+	})
+}
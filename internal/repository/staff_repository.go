@@ -2,10 +2,12 @@ package repository
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/wichai2002/his_v1/internal/domain"
 	"github.com/wichai2002/his_v1/internal/infrastructure/database"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type staffRepository struct {
@@ -34,7 +36,7 @@ func (r *staffRepository) GetAll(schemaName string) ([]domain.Staff, error) {
 	}
 
 	var staffs []domain.Staff
-	if err := db.Find(&staffs).Error; err != nil {
+	if err := db.Preload("Roles.Permissions").Find(&staffs).Error; err != nil {
 		return nil, err
 	}
 	return staffs, nil
@@ -47,7 +49,7 @@ func (r *staffRepository) GetByID(id uint, schemaName string) (*domain.Staff, er
 	}
 
 	var staff domain.Staff
-	if err := db.First(&staff, id).Error; err != nil {
+	if err := db.Preload("Roles.Permissions").First(&staff, id).Error; err != nil {
 		return nil, err
 	}
 	return &staff, nil
@@ -61,7 +63,7 @@ func (r *staffRepository) GetByUsername(username string, schemaName string) (*do
 	}
 
 	var staff domain.Staff
-	if err := db.Where("username = ?", username).First(&staff).Error; err != nil {
+	if err := db.Preload("Roles.Permissions").Where("username = ?", username).First(&staff).Error; err != nil {
 		return nil, err
 	}
 	return &staff, nil
@@ -80,13 +82,178 @@ func (r *staffRepository) Update(staff *domain.Staff, schemaName string) error {
 	if err != nil {
 		return fmt.Errorf("failed to get tenant db: %w", err)
 	}
-	return db.Save(staff).Error
+	// Only the version that was read is overwritten, so a concurrent update is not lost.
+	// Omit associations so saving staff never rewrites role assignments.
+	expected := staff.Version
+	staff.Version++
+	result := db.Model(staff).Where("version = ?", expected).Select("*").Omit("Roles", "created_at").Updates(staff)
+	if result.Error != nil {
+		staff.Version = expected
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		staff.Version = expected
+		return domain.ErrPreconditionFailed
+	}
+	return nil
+}
+
+// UpdatePassword stores a new password hash and revokes every session of the staff member
+// in one transaction, so a changed password never leaves old tokens usable
+func (r *staffRepository) UpdatePassword(id uint, passwordHash string, schemaName string) error {
+	return r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		result := tx.Model(&domain.Staff{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"password": passwordHash,
+			"version":  gorm.Expr("version + 1"),
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return revokeAllForStaff(tx, id, domain.RevokeReasonPasswordChange)
+	})
 }
 
+// Delete soft-deletes the staff member and revokes all their sessions in one transaction
 func (r *staffRepository) Delete(id uint, schemaName string) error {
+	return r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		if err := tx.Delete(&domain.Staff{}, id).Error; err != nil {
+			return err
+		}
+		return revokeAllForStaff(tx, id, domain.RevokeReasonStaffDeleted)
+	})
+}
+
+func (r *staffRepository) ListDeleted(deletedBefore *time.Time, schemaName string) ([]domain.Staff, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	query := db.Unscoped().Where("deleted_at IS NOT NULL")
+	if deletedBefore != nil {
+		query = query.Where("deleted_at < ?", *deletedBefore)
+	}
+
+	var staffs []domain.Staff
+	if err := query.Order("deleted_at DESC, id DESC").Find(&staffs).Error; err != nil {
+		return nil, err
+	}
+	return staffs, nil
+}
+
+func (r *staffRepository) GetDeleted(id uint, schemaName string) (*domain.Staff, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	var staff domain.Staff
+	if err := db.Unscoped().Where("deleted_at IS NOT NULL").First(&staff, id).Error; err != nil {
+		return nil, err
+	}
+	return &staff, nil
+}
+
+func (r *staffRepository) FindLiveByUniqueFields(s *domain.Staff, schemaName string) ([]domain.Staff, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	var staffs []domain.Staff
+	if err := db.Where("id <> ?", s.ID).
+		Where(db.Where("username = ?", s.Username).
+			Or("staff_code = ?", s.StaffCode).
+			Or("phone_number = ?", s.PhoneNumber).
+			Or("email = ?", s.Email)).
+		Find(&staffs).Error; err != nil {
+		return nil, err
+	}
+	return staffs, nil
+}
+
+// Restore clears the deletion of a staff member; their revoked sessions stay revoked
+func (r *staffRepository) Restore(id uint, schemaName string) error {
 	db, err := r.getDB(schemaName)
 	if err != nil {
 		return fmt.Errorf("failed to get tenant db: %w", err)
 	}
-	return db.Delete(&domain.Staff{}, id).Error
+
+	result := db.Unscoped().Model(&domain.Staff{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Updates(map[string]interface{}{"deleted_at": nil, "version": gorm.Expr("version + 1")})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Purge removes staff for good together with the rows referencing them. Doctors with
+// appointments are kept: the appointments belong to the patients' records.
+func (r *staffRepository) Purge(ids []uint, deletedBefore time.Time, schemaName string) (int, []domain.PurgeRetention, error) {
+	if len(ids) == 0 {
+		return 0, nil, nil
+	}
+
+	var purged []uint
+	var retained []domain.PurgeRetention
+	err := r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		var eligible []uint
+		if err := tx.Unscoped().Model(&domain.Staff{}).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ? AND deleted_at IS NOT NULL AND deleted_at < ?", ids, deletedBefore).
+			Order("id").
+			Pluck("id", &eligible).Error; err != nil {
+			return err
+		}
+		if len(eligible) == 0 {
+			return nil
+		}
+
+		// Appointments carry the doctor directly, including cancelled and rescheduled ones
+		var withAppointments []uint
+		if err := tx.Table("appointments").Distinct("doctor_id").Where("doctor_id IN ?", eligible).
+			Pluck("doctor_id", &withAppointments).Error; err != nil {
+			return err
+		}
+		booked := make(map[uint]bool, len(withAppointments))
+		for _, id := range withAppointments {
+			booked[id] = true
+		}
+		for _, id := range eligible {
+			if booked[id] {
+				retained = append(retained, domain.PurgeRetention{ID: id, Reason: domain.PurgeRetainedAppointments})
+			} else {
+				purged = append(purged, id)
+			}
+		}
+		if len(purged) == 0 {
+			return nil
+		}
+
+		if err := tx.Where("staff_id IN ?", purged).Delete(&domain.RefreshToken{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM staff_roles WHERE staff_id IN ?", purged).Error; err != nil {
+			return err
+		}
+		// None of these slots is booked, so the doctor's published availability can go
+		if err := tx.Exec("DELETE FROM appointment_slots WHERE doctor_id IN ?", purged).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM availability_templates WHERE doctor_id IN ?", purged).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&domain.Staff{}, purged).Error
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	return len(purged), retained, nil
 }
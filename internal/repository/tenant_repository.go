@@ -3,7 +3,6 @@ package repository
 import (
 	"github.com/wichai2002/his_v1/internal/domain"
 	"gorm.io/gorm"
-	"gorm.io/gorm/clause"
 )
 
 type tenantRepository struct {
@@ -56,29 +55,3 @@ func (r *tenantRepository) SchemaExists(schemaName string) (bool, error) {
 	}
 	return count > 0, nil
 }
-
-// IncrementHNRunning atomically increments HNRunning and returns the new value
-func (r *tenantRepository) IncrementHNRunning(tenantID uint) (uint64, error) {
-	var tenant domain.Tenant
-
-	// Use transaction with row-level locking for atomic increment
-	err := r.db.Transaction(func(tx *gorm.DB) error {
-		// Lock the row for update
-		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
-			First(&tenant, tenantID).Error; err != nil {
-			return err
-		}
-
-		// Increment the HN running number
-		tenant.HNRunning++
-
-		// Save the updated value
-		return tx.Save(&tenant).Error
-	})
-
-	if err != nil {
-		return 0, err
-	}
-
-	return tenant.HNRunning, nil
-}
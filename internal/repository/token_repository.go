@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/infrastructure/database"
+	"gorm.io/gorm"
+)
+
+type tokenRepository struct {
+	*TenantAwareRepository
+}
+
+// NewTokenRepository creates a new refresh token / revocation list repository
+func NewTokenRepository(db *gorm.DB, dbManager *database.TenantDBManager) domain.TokenRepository {
+	return &tokenRepository{
+		TenantAwareRepository: NewTenantAwareRepository(db, dbManager),
+	}
+}
+
+// getDB returns the appropriate database based on schema
+func (r *tokenRepository) getDB(schemaName string) (*gorm.DB, error) {
+	if schemaName == "" || schemaName == "public" {
+		return r.GetDB(), nil
+	}
+	return r.GetTenantDB(schemaName)
+}
+
+func (r *tokenRepository) CreateRefreshToken(token *domain.RefreshToken, schemaName string) error {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return fmt.Errorf("failed to get tenant db: %w", err)
+	}
+	return db.Create(token).Error
+}
+
+func (r *tokenRepository) GetRefreshTokenByHash(tokenHash string, schemaName string) (*domain.RefreshToken, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	var token domain.RefreshToken
+	if err := db.Where("token_hash = ?", tokenHash).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// RotateRefreshToken stores the new token and revokes the old one in a single transaction.
+// The conditional update guarantees a refresh token can only be exchanged once,
+// even when two refresh requests race each other.
+func (r *tokenRepository) RotateRefreshToken(oldID uint, newToken *domain.RefreshToken, schemaName string) error {
+	return r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		if err := tx.Create(newToken).Error; err != nil {
+			return err
+		}
+
+		result := tx.Model(&domain.RefreshToken{}).
+			Where("id = ? AND revoked_at IS NULL", oldID).
+			Updates(map[string]interface{}{
+				"revoked_at":     time.Now(),
+				"replaced_by_id": newToken.ID,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrInvalidToken
+		}
+		return nil
+	})
+}
+
+// RevokeAllForStaff revokes all refresh tokens of a staff member and blacklists
+// every access token issued alongside them that has not expired yet
+func (r *tokenRepository) RevokeAllForStaff(staffID uint, reason string, schemaName string) error {
+	return r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		return revokeAllForStaff(tx, staffID, reason)
+	})
+}
+
+// revokeAllForStaff revokes the tokens of a staff member using tx, which must already be
+// scoped to the tenant schema, so callers can revoke in the same transaction as their change
+func revokeAllForStaff(tx *gorm.DB, staffID uint, reason string) error {
+	now := time.Now()
+
+	if err := tx.Exec(`
+		INSERT INTO revoked_tokens (jti, staff_id, expires_at, revoked_at, reason)
+		SELECT access_token_id, staff_id, access_token_expires_at, ?, ?
+		FROM refresh_tokens
+		WHERE staff_id = ? AND access_token_expires_at > ?
+		ON CONFLICT (jti) DO NOTHING
+	`, now, reason, staffID, now).Error; err != nil {
+		return fmt.Errorf("failed to revoke access tokens: %w", err)
+	}
+
+	if err := tx.Model(&domain.RefreshToken{}).
+		Where("staff_id = ? AND revoked_at IS NULL", staffID).
+		Update("revoked_at", now).Error; err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+
+	return nil
+}
+
+func (r *tokenRepository) IsRevoked(jti string, schemaName string) (bool, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return false, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	var count int64
+	if err := db.Model(&domain.RevokedToken{}).Where("jti = ?", jti).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/infrastructure/database"
+	"gorm.io/gorm"
+)
+
+type vitalSignRepository struct {
+	*TenantAwareRepository
+}
+
+// NewVitalSignRepository creates a new vital sign repository
+func NewVitalSignRepository(db *gorm.DB, dbManager *database.TenantDBManager) domain.VitalSignRepository {
+	return &vitalSignRepository{
+		TenantAwareRepository: NewTenantAwareRepository(db, dbManager),
+	}
+}
+
+// getDB returns the appropriate database based on schema
+func (r *vitalSignRepository) getDB(schemaName string) (*gorm.DB, error) {
+	if schemaName == "" || schemaName == "public" {
+		return r.GetDB(), nil
+	}
+	return r.GetTenantDB(schemaName)
+}
+
+func (r *vitalSignRepository) Create(vital *domain.VitalSign, event *domain.AuditEvent, schemaName string) error {
+	return r.ExecuteInSchema(schemaName, func(tx *gorm.DB) error {
+		if err := tx.Create(vital).Error; err != nil {
+			return err
+		}
+		return appendAuditEvents(tx, event)
+	})
+}
+
+func (r *vitalSignRepository) ListByEncounter(encounterID uint, schemaName string) ([]domain.VitalSign, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	var vitals []domain.VitalSign
+	if err := db.Where("encounter_id = ?", encounterID).Order("recorded_at, id").Find(&vitals).Error; err != nil {
+		return nil, err
+	}
+	return vitals, nil
+}
+
+func (r *vitalSignRepository) ListByPatient(filter *domain.VitalSignFilter, schemaName string) ([]domain.VitalSign, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	query := db.Where(mergedPatientCondition, filter.PatientID)
+	if filter.From != nil {
+		query = query.Where("recorded_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("recorded_at < ?", *filter.To)
+	}
+
+	var vitals []domain.VitalSign
+	if err := query.Order("recorded_at, id").Find(&vitals).Error; err != nil {
+		return nil, err
+	}
+	return vitals, nil
+}
+
+func (r *vitalSignRepository) LatestHeight(patientID uint, schemaName string) (*float64, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	var heights []float64
+	if err := db.Model(&domain.VitalSign{}).
+		Where(mergedPatientCondition+" AND height IS NOT NULL", patientID).
+		Order("recorded_at DESC, id DESC").Limit(1).
+		Pluck("height", &heights).Error; err != nil {
+		return nil, err
+	}
+	if len(heights) == 0 {
+		return nil, nil
+	}
+	return &heights[0], nil
+}
+
+// Trend unpivots the measure columns into rows and keeps the latest limit rows of each measure
+func (r *vitalSignRepository) Trend(patientID uint, limit int, schemaName string) ([]domain.VitalTrendPoint, error) {
+	db, err := r.getDB(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant db: %w", err)
+	}
+
+	measures := make([]string, len(domain.VitalMeasures))
+	for i, measure := range domain.VitalMeasures {
+		// Measure codes are the column names, so they are safe to inline
+		measures[i] = fmt.Sprintf("('%s', v.%s::float8)", measure.Code, measure.Code)
+	}
+	query := fmt.Sprintf(`
+		SELECT measure, encounter_id, recorded_at, value FROM (
+			SELECT m.measure, v.encounter_id, v.recorded_at, m.value,
+				ROW_NUMBER() OVER (PARTITION BY m.measure ORDER BY v.recorded_at DESC, v.id DESC) AS rn
+			FROM vital_signs v
+			CROSS JOIN LATERAL (VALUES %s) AS m(measure, value)
+			WHERE v.patient_id IN (%s) AND v.deleted_at IS NULL AND m.value IS NOT NULL
+		) latest
+		WHERE rn <= ?
+		ORDER BY measure, recorded_at
+	`, strings.Join(measures, ", "), mergedPatientsSQL)
+
+	var points []domain.VitalTrendPoint
+	if err := db.Raw(query, patientID, limit).Scan(&points).Error; err != nil {
+		return nil, err
+	}
+	return points, nil
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+type addressService struct {
+	referenceRepo domain.AddressReferenceRepository
+}
+
+// NewAddressService creates the service for the Thai address reference dataset
+func NewAddressService(referenceRepo domain.AddressReferenceRepository) domain.AddressService {
+	return &addressService{
+		referenceRepo: referenceRepo,
+	}
+}
+
+func (s *addressService) ListProvinces() ([]domain.Province, error) {
+	provinces, err := s.referenceRepo.ListProvinces()
+	return provinces, wrapError(err)
+}
+
+func (s *addressService) ListDistricts(provinceCode string) ([]domain.District, error) {
+	districts, err := s.referenceRepo.ListDistricts(provinceCode)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	if len(districts) == 0 {
+		return nil, domain.ErrNotFound
+	}
+	return districts, nil
+}
+
+func (s *addressService) ListSubdistricts(districtCode string) ([]domain.Subdistrict, error) {
+	subdistricts, err := s.referenceRepo.ListSubdistricts(districtCode)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	if len(subdistricts) == 0 {
+		return nil, domain.ErrNotFound
+	}
+	return subdistricts, nil
+}
+
+func (s *addressService) FindByPostalCode(postalCode string) ([]domain.AddressLocation, error) {
+	locations, err := s.referenceRepo.FindByPostalCode(postalCode)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	if len(locations) == 0 {
+		return nil, domain.ErrNotFound
+	}
+	return locations, nil
+}
+
+// Autocomplete suggests subdistricts for a partial tambon, amphoe or changwat name or postal code
+func (s *addressService) Autocomplete(req *domain.AddressSearchRequest) ([]domain.AddressLocation, error) {
+	query := strings.TrimSpace(req.Query)
+	if utf8.RuneCountInString(query) < domain.MinAddressSearchLength {
+		return nil, fmt.Errorf("%w: query must be at least %d characters", domain.ErrInvalidInput, domain.MinAddressSearchLength)
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = domain.DefaultAddressSearchLimit
+	}
+	if limit > domain.MaxAddressSearchLimit {
+		return nil, fmt.Errorf("%w: limit must not exceed %d", domain.ErrInvalidInput, domain.MaxAddressSearchLimit)
+	}
+
+	locations, err := s.referenceRepo.Search(query, limit)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return locations, nil
+}
@@ -0,0 +1,257 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+type appointmentService struct {
+	appointmentRepo domain.AppointmentRepository
+	patientRepo     domain.PatientRepository
+	auditRepo       domain.AuditRepository
+}
+
+// NewAppointmentService creates the service for booking appointments into doctor slots
+func NewAppointmentService(appointmentRepo domain.AppointmentRepository, patientRepo domain.PatientRepository, auditRepo domain.AuditRepository) domain.AppointmentService {
+	return &appointmentService{
+		appointmentRepo: appointmentRepo,
+		patientRepo:     patientRepo,
+		auditRepo:       auditRepo,
+	}
+}
+
+func (s *appointmentService) Book(req *domain.AppointmentBookRequest, actor *domain.Actor, schemaName string) (*domain.Appointment, error) {
+	if _, err := s.patientRepo.GetByID(req.PatientID, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+	slot, err := s.bookableSlot(req.SlotID, schemaName)
+	if err != nil {
+		return nil, err
+	}
+
+	appointment := newAppointment(req.PatientID, slot, strings.TrimSpace(req.Reason), actor)
+	changes := map[string]domain.FieldChange{
+		"slot_id":  {After: slot.ID},
+		"start_at": {After: formatAppointmentTime(slot.StartAt)},
+		"reason":   {After: appointment.Reason},
+	}
+	event, err := domain.NewAuditEvent(actor, domain.AuditActionPatientAppointmentBook, &appointment.PatientID, changes)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.appointmentRepo.Create(appointment, event, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+	return appointment, nil
+}
+
+func (s *appointmentService) GetByID(id uint, actor *domain.Actor, schemaName string) (*domain.Appointment, error) {
+	appointment, err := s.appointmentRepo.GetByID(id, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	if err := s.recordView(actor, []uint{appointment.PatientID}, schemaName); err != nil {
+		return nil, err
+	}
+	return appointment, nil
+}
+
+func (s *appointmentService) ListByPatient(patientID uint, actor *domain.Actor, schemaName string) ([]domain.Appointment, error) {
+	if _, err := s.patientRepo.GetByID(patientID, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+
+	appointments, err := s.appointmentRepo.ListByPatient(patientID, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	if err := s.recordView(actor, []uint{patientID}, schemaName); err != nil {
+		return nil, err
+	}
+	return appointments, nil
+}
+
+func (s *appointmentService) Reschedule(id uint, req *domain.AppointmentRescheduleRequest, actor *domain.Actor, schemaName string) (*domain.Appointment, error) {
+	previous, err := s.bookedAppointment(id, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	if req.SlotID == previous.SlotID {
+		return nil, fmt.Errorf("%w: the appointment is already in slot %d", domain.ErrInvalidInput, req.SlotID)
+	}
+	slot, err := s.bookableSlot(req.SlotID, schemaName)
+	if err != nil {
+		return nil, err
+	}
+
+	replacement := newAppointment(previous.PatientID, slot, previous.Reason, actor)
+	replacement.RescheduledFromID = &previous.ID
+	changes := map[string]domain.FieldChange{
+		"appointment_id": {Before: previous.ID},
+		"slot_id":        {Before: previous.SlotID, After: slot.ID},
+		"start_at":       {Before: formatAppointmentTime(previous.StartAt), After: formatAppointmentTime(slot.StartAt)},
+	}
+	event, err := domain.NewAuditEvent(actor, domain.AuditActionPatientAppointmentReschedule, &previous.PatientID, changes)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.appointmentRepo.Reschedule(previous, replacement, event, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+	return replacement, nil
+}
+
+func (s *appointmentService) Cancel(id uint, req *domain.AppointmentCancelRequest, actor *domain.Actor, schemaName string) (*domain.Appointment, error) {
+	appointment, err := s.bookedAppointment(id, schemaName)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	appointment.Status = domain.AppointmentStatusCancelled
+	appointment.CancelledAt = &now
+	appointment.CancelReason = strings.TrimSpace(req.Reason)
+
+	changes := map[string]domain.FieldChange{
+		"appointment_id": {Before: appointment.ID},
+		"status":         {Before: domain.AppointmentStatusBooked, After: appointment.Status},
+		"start_at":       {Before: formatAppointmentTime(appointment.StartAt)},
+		"cancel_reason":  {After: appointment.CancelReason},
+	}
+	event, err := domain.NewAuditEvent(actor, domain.AuditActionPatientAppointmentCancel, &appointment.PatientID, changes)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.appointmentRepo.Cancel(appointment, event, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+	return appointment, nil
+}
+
+// Calendar returns the slots of a day, or of the Monday to Sunday week containing the day
+func (s *appointmentService) Calendar(req *domain.AppointmentCalendarRequest, actor *domain.Actor, schemaName string) (*domain.AppointmentCalendar, error) {
+	if req.DoctorID == 0 && req.DepartmentID == 0 {
+		return nil, fmt.Errorf("%w: doctor_id or department_id is required", domain.ErrInvalidInput)
+	}
+	day, err := parseCalendarDay(req.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	view, days := domain.CalendarViewDay, 1
+	if req.View == domain.CalendarViewWeek {
+		view, days = domain.CalendarViewWeek, 7
+		day = day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	}
+	from := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, domain.HNTimeZone)
+	filter := &domain.AppointmentCalendarFilter{
+		DoctorID:     req.DoctorID,
+		DepartmentID: req.DepartmentID,
+		From:         from,
+		To:           from.AddDate(0, 0, days),
+	}
+
+	entries, err := s.appointmentRepo.Calendar(filter, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	seen := make(map[uint]bool)
+	var patientIDs []uint
+	for i := range entries {
+		entries[i].Available = entries[i].AppointmentID == nil
+		if patientID := entries[i].PatientID; patientID != nil && !seen[*patientID] {
+			seen[*patientID] = true
+			patientIDs = append(patientIDs, *patientID)
+		}
+	}
+	if err := s.recordView(actor, patientIDs, schemaName); err != nil {
+		return nil, err
+	}
+
+	return &domain.AppointmentCalendar{
+		View:    view,
+		From:    day.Format(domain.DateFormat),
+		To:      day.AddDate(0, 0, days-1).Format(domain.DateFormat),
+		Entries: entries,
+	}, nil
+}
+
+// bookableSlot returns a slot of a live template that has not started yet
+func (s *appointmentService) bookableSlot(slotID uint, schemaName string) (*domain.AppointmentSlot, error) {
+	slot, err := s.appointmentRepo.GetSlot(slotID, schemaName)
+	if err != nil {
+		err = wrapError(err)
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, fmt.Errorf("%w: slot %d does not exist", domain.ErrInvalidInput, slotID)
+		}
+		return nil, err
+	}
+	if !slot.StartAt.After(time.Now()) {
+		return nil, fmt.Errorf("%w: slot %d has already started", domain.ErrInvalidInput, slotID)
+	}
+	return slot, nil
+}
+
+// bookedAppointment returns an appointment that can still be rescheduled or cancelled
+func (s *appointmentService) bookedAppointment(id uint, schemaName string) (*domain.Appointment, error) {
+	appointment, err := s.appointmentRepo.GetByID(id, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	if appointment.Status != domain.AppointmentStatusBooked {
+		return nil, fmt.Errorf("%w: appointment %d is %s", domain.ErrInvalidStatusTransition, appointment.ID, appointment.Status)
+	}
+	return appointment, nil
+}
+
+// recordView appends one view event per patient shown
+func (s *appointmentService) recordView(actor *domain.Actor, patientIDs []uint, schemaName string) error {
+	if len(patientIDs) == 0 {
+		return nil
+	}
+	events := make([]*domain.AuditEvent, 0, len(patientIDs))
+	for i := range patientIDs {
+		event, err := domain.NewAuditEvent(actor, domain.AuditActionPatientAppointmentView, &patientIDs[i], nil)
+		if err != nil {
+			return err
+		}
+		events = append(events, event)
+	}
+
+	if err := s.auditRepo.Append(events, schemaName); err != nil {
+		return fmt.Errorf("failed to record audit event: %w", err)
+	}
+	return nil
+}
+
+func newAppointment(patientID uint, slot *domain.AppointmentSlot, reason string, actor *domain.Actor) *domain.Appointment {
+	appointment := &domain.Appointment{
+		PatientID:    patientID,
+		SlotID:       slot.ID,
+		DoctorID:     slot.DoctorID,
+		DepartmentID: slot.DepartmentID,
+		StartAt:      slot.StartAt,
+		EndAt:        slot.EndAt,
+		Status:       domain.AppointmentStatusBooked,
+		Reason:       reason,
+	}
+	if actor != nil {
+		appointment.BookedBy = actor.StaffID
+	}
+	return appointment
+}
+
+// formatAppointmentTime renders a slot time for the audit trail in Thai time
+func formatAppointmentTime(at time.Time) string {
+	return at.In(domain.HNTimeZone).Format(time.RFC3339)
+}
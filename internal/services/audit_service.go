@@ -0,0 +1,65 @@
+package services
+
+import (
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+// auditVerifyBatchSize is the number of events loaded per round trip while verifying the chain
+const auditVerifyBatchSize = 1000
+
+// maxAuditQueryLimit caps the page size of audit queries
+const maxAuditQueryLimit = 500
+
+type auditService struct {
+	auditRepo domain.AuditRepository
+}
+
+// NewAuditService creates a new audit trail service
+func NewAuditService(auditRepo domain.AuditRepository) domain.AuditService {
+	return &auditService{
+		auditRepo: auditRepo,
+	}
+}
+
+func (s *auditService) Query(filter *domain.AuditFilter, schemaName string) ([]domain.AuditEvent, int64, error) {
+	if filter.Limit < 0 || filter.Limit > maxAuditQueryLimit || filter.Offset < 0 {
+		return nil, 0, domain.ErrInvalidInput
+	}
+
+	events, total, err := s.auditRepo.Query(filter, schemaName)
+	if err != nil {
+		return nil, 0, wrapError(err)
+	}
+	return events, total, nil
+}
+
+// Verify recomputes every hash in ID order and checks each event links to its predecessor
+func (s *auditService) Verify(schemaName string) (*domain.AuditVerifyResult, error) {
+	result := &domain.AuditVerifyResult{Valid: true}
+
+	var lastID uint
+	var prevHash string
+	for {
+		events, err := s.auditRepo.GetChain(lastID, auditVerifyBatchSize, schemaName)
+		if err != nil {
+			return nil, wrapError(err)
+		}
+
+		for i := range events {
+			event := &events[i]
+			if event.PrevHash != prevHash || event.ComputeHash() != event.Hash {
+				brokenAt := event.ID
+				result.Valid = false
+				result.BrokenAtID = &brokenAt
+				return result, nil
+			}
+			result.CheckedEvents++
+			prevHash = event.Hash
+			lastID = event.ID
+		}
+
+		if len(events) < auditVerifyBatchSize {
+			return result, nil
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+type availabilityService struct {
+	templateRepo   domain.AvailabilityTemplateRepository
+	staffRepo      domain.StaffRepository
+	departmentRepo domain.DepartmentRepository
+}
+
+// NewAvailabilityService creates the service for the availability doctors publish
+func NewAvailabilityService(templateRepo domain.AvailabilityTemplateRepository, staffRepo domain.StaffRepository, departmentRepo domain.DepartmentRepository) domain.AvailabilityService {
+	return &availabilityService{
+		templateRepo:   templateRepo,
+		staffRepo:      staffRepo,
+		departmentRepo: departmentRepo,
+	}
+}
+
+func (s *availabilityService) List(doctorID uint, schemaName string) ([]domain.AvailabilityTemplate, error) {
+	templates, err := s.templateRepo.List(doctorID, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return templates, nil
+}
+
+func (s *availabilityService) Create(req *domain.AvailabilityTemplateRequest, schemaName string) (*domain.AvailabilityTemplate, error) {
+	template, err := newAvailabilityTemplate(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkDoctor(req.DoctorID, schemaName); err != nil {
+		return nil, err
+	}
+	if err := s.checkDepartment(req.DepartmentID, schemaName); err != nil {
+		return nil, err
+	}
+	if err := s.checkOverlap(template, schemaName); err != nil {
+		return nil, err
+	}
+
+	today, _ := parseCalendarDay("")
+	slots, err := template.Slots(today, today.AddDate(0, 0, domain.DefaultSlotHorizonDays))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.templateRepo.Create(template, futureSlots(slots, time.Now()), schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+	return template, nil
+}
+
+func (s *availabilityService) Expand(id uint, req *domain.AvailabilityExpandRequest, schemaName string) (*domain.AvailabilityExpandResult, error) {
+	template, err := s.templateRepo.GetByID(id, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	until, err := parseCalendarDay(req.Until)
+	if err != nil {
+		return nil, err
+	}
+	today, _ := parseCalendarDay("")
+	if until.After(today.AddDate(0, 0, domain.MaxSlotHorizonDays)) {
+		return nil, fmt.Errorf("%w: until must be within %d days", domain.ErrInvalidInput, domain.MaxSlotHorizonDays)
+	}
+
+	slots, err := template.Slots(today, until)
+	if err != nil {
+		return nil, err
+	}
+	created, err := s.templateRepo.AddSlots(futureSlots(slots, time.Now()), schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return &domain.AvailabilityExpandResult{
+		TemplateID:   template.ID,
+		Until:        until.Format(domain.DateFormat),
+		SlotsCreated: created,
+	}, nil
+}
+
+func (s *availabilityService) Delete(id uint, schemaName string) error {
+	template, err := s.templateRepo.GetByID(id, schemaName)
+	if err != nil {
+		return wrapError(err)
+	}
+	if err := s.templateRepo.Delete(template, time.Now(), schemaName); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+// checkDoctor rejects templates for staff who do not hold the doctor role
+func (s *availabilityService) checkDoctor(doctorID uint, schemaName string) error {
+	staff, err := s.staffRepo.GetByID(doctorID, schemaName)
+	if err != nil {
+		err = wrapError(err)
+		if errors.Is(err, domain.ErrNotFound) {
+			return fmt.Errorf("%w: staff %d does not exist", domain.ErrInvalidInput, doctorID)
+		}
+		return err
+	}
+	if !staff.HasRole(domain.RoleDoctor) {
+		return fmt.Errorf("%w: staff %s is not a doctor", domain.ErrInvalidInput, staff.StaffCode)
+	}
+	return nil
+}
+
+func (s *availabilityService) checkDepartment(departmentID uint, schemaName string) error {
+	department, err := s.departmentRepo.GetByID(departmentID, schemaName)
+	if err != nil {
+		err = wrapError(err)
+		if errors.Is(err, domain.ErrNotFound) {
+			return fmt.Errorf("%w: department %d does not exist", domain.ErrInvalidInput, departmentID)
+		}
+		return err
+	}
+	if !department.IsActive {
+		return fmt.Errorf("%w: department %s is not active", domain.ErrInvalidInput, department.Code)
+	}
+	return nil
+}
+
+// checkOverlap rejects a template whose sessions overlap another template of the doctor on a
+// day both are valid, since the slots of one would hide those of the other
+func (s *availabilityService) checkOverlap(template *domain.AvailabilityTemplate, schemaName string) error {
+	existing, err := s.templateRepo.List(template.DoctorID, schemaName)
+	if err != nil {
+		return wrapError(err)
+	}
+	for _, other := range existing {
+		if other.Weekday != template.Weekday {
+			continue
+		}
+		if template.StartTime >= other.EndTime || other.StartTime >= template.EndTime {
+			continue
+		}
+		if other.ValidTo != nil && other.ValidTo.Before(template.ValidFrom) {
+			continue
+		}
+		if template.ValidTo != nil && template.ValidTo.Before(other.ValidFrom) {
+			continue
+		}
+		return fmt.Errorf("%w: availability overlaps template %d", domain.ErrDuplicateEntry, other.ID)
+	}
+	return nil
+}
+
+// newAvailabilityTemplate validates the request; times are normalised to HH:MM so they compare as strings
+func newAvailabilityTemplate(req *domain.AvailabilityTemplateRequest) (*domain.AvailabilityTemplate, error) {
+	start, err := time.Parse(domain.ClockFormat, req.StartTime)
+	if err != nil {
+		return nil, fmt.Errorf("%w: start_time must be in HH:MM format", domain.ErrInvalidInput)
+	}
+	end, err := time.Parse(domain.ClockFormat, req.EndTime)
+	if err != nil {
+		return nil, fmt.Errorf("%w: end_time must be in HH:MM format", domain.ErrInvalidInput)
+	}
+	if end.Sub(start) < time.Duration(req.SlotMinutes)*time.Minute {
+		return nil, fmt.Errorf("%w: end_time must leave room for at least one slot after start_time", domain.ErrInvalidInput)
+	}
+
+	validFrom, err := parseCalendarDay(req.ValidFrom)
+	if err != nil {
+		return nil, err
+	}
+	var validTo *time.Time
+	if req.ValidTo != "" {
+		to, err := parseCalendarDay(req.ValidTo)
+		if err != nil {
+			return nil, err
+		}
+		if to.Before(validFrom) {
+			return nil, fmt.Errorf("%w: valid_to must not be before valid_from", domain.ErrInvalidInput)
+		}
+		validTo = &to
+	}
+
+	return &domain.AvailabilityTemplate{
+		DoctorID:     req.DoctorID,
+		DepartmentID: req.DepartmentID,
+		Weekday:      *req.Weekday,
+		StartTime:    start.Format(domain.ClockFormat),
+		EndTime:      end.Format(domain.ClockFormat),
+		SlotMinutes:  req.SlotMinutes,
+		ValidFrom:    validFrom,
+		ValidTo:      validTo,
+	}, nil
+}
+
+// futureSlots drops the slots that start before now
+func futureSlots(slots []domain.AppointmentSlot, now time.Time) []domain.AppointmentSlot {
+	future := make([]domain.AppointmentSlot, 0, len(slots))
+	for _, slot := range slots {
+		if slot.StartAt.After(now) {
+			future = append(future, slot)
+		}
+	}
+	return future
+}
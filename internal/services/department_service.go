@@ -0,0 +1,57 @@
+package services
+
+import (
+	"strings"
+
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+type departmentService struct {
+	departmentRepo domain.DepartmentRepository
+}
+
+// NewDepartmentService creates the service managing the departments of a tenant
+func NewDepartmentService(departmentRepo domain.DepartmentRepository) domain.DepartmentService {
+	return &departmentService{
+		departmentRepo: departmentRepo,
+	}
+}
+
+func (s *departmentService) List(includeInactive bool, schemaName string) ([]domain.Department, error) {
+	departments, err := s.departmentRepo.List(includeInactive, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return departments, nil
+}
+
+func (s *departmentService) Create(req *domain.DepartmentRequest, schemaName string) (*domain.Department, error) {
+	department := &domain.Department{}
+	applyDepartmentRequest(department, req)
+
+	if err := s.departmentRepo.Create(department, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+	return department, nil
+}
+
+func (s *departmentService) Update(id uint, req *domain.DepartmentRequest, schemaName string) (*domain.Department, error) {
+	department, err := s.departmentRepo.GetByID(id, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	applyDepartmentRequest(department, req)
+
+	if err := s.departmentRepo.Update(department, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+	return department, nil
+}
+
+// applyDepartmentRequest copies the request onto the department; codes are stored upper case
+func applyDepartmentRequest(department *domain.Department, req *domain.DepartmentRequest) {
+	department.Code = strings.ToUpper(req.Code)
+	department.NameTH = strings.TrimSpace(req.NameTH)
+	department.NameEN = strings.TrimSpace(req.NameEN)
+	department.IsActive = req.IsActive == nil || *req.IsActive
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+type diagnosisService struct {
+	diagnosisRepo domain.DiagnosisRepository
+	icd10Repo     domain.ICD10Repository
+	encounterRepo domain.EncounterRepository
+	patientRepo   domain.PatientRepository
+	auditRepo     domain.AuditRepository
+}
+
+// NewDiagnosisService creates the service for the ICD-10 diagnoses of encounters
+func NewDiagnosisService(diagnosisRepo domain.DiagnosisRepository, icd10Repo domain.ICD10Repository, encounterRepo domain.EncounterRepository, patientRepo domain.PatientRepository, auditRepo domain.AuditRepository) domain.DiagnosisService {
+	return &diagnosisService{
+		diagnosisRepo: diagnosisRepo,
+		icd10Repo:     icd10Repo,
+		encounterRepo: encounterRepo,
+		patientRepo:   patientRepo,
+		auditRepo:     auditRepo,
+	}
+}
+
+// Create records a diagnosis. The principal diagnosis must be a billable code, external cause
+// codes (V01-Y98) are only recorded as external causes, and a code is recorded once per encounter.
+func (s *diagnosisService) Create(encounterID uint, req *domain.DiagnosisRequest, actor *domain.Actor, schemaName string) (*domain.Diagnosis, error) {
+	encounter, err := s.encounterRepo.GetByID(encounterID, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	code := domain.NormalizeICD10Code(req.Code)
+	icd10, err := s.icd10Repo.GetByCode(code)
+	if err != nil {
+		err = wrapError(err)
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, fmt.Errorf("%w: ICD-10 code %s does not exist", domain.ErrInvalidInput, code)
+		}
+		return nil, err
+	}
+	if err := checkDiagnosisCode(icd10, req.Type); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.diagnosisRepo.ListByEncounter(encounter.ID, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	for _, other := range existing {
+		if other.Code == icd10.Code {
+			return nil, fmt.Errorf("%w: %s is already recorded for encounter %s", domain.ErrDuplicateEntry, icd10.Code, encounter.VN)
+		}
+		if req.Type == domain.DiagnosisTypePrincipal && other.Type == domain.DiagnosisTypePrincipal {
+			return nil, fmt.Errorf("%w: encounter %s already has principal diagnosis %s", domain.ErrDuplicateEntry, encounter.VN, other.Code)
+		}
+	}
+
+	diagnosis := &domain.Diagnosis{
+		EncounterID: encounter.ID,
+		PatientID:   encounter.PatientID,
+		Code:        icd10.Code,
+		Description: icd10.Description,
+		Type:        req.Type,
+		Note:        strings.TrimSpace(req.Note),
+	}
+	if actor != nil {
+		diagnosis.DiagnosedBy = actor.StaffID
+	}
+
+	changes := map[string]domain.FieldChange{
+		"vn":   {After: encounter.VN},
+		"code": {After: diagnosis.Code},
+		"type": {After: diagnosis.Type},
+	}
+	event, err := domain.NewAuditEvent(actor, domain.AuditActionPatientDiagnosisCreate, &diagnosis.PatientID, changes)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.diagnosisRepo.Create(diagnosis, event, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+	return diagnosis, nil
+}
+
+func (s *diagnosisService) ListByEncounter(encounterID uint, actor *domain.Actor, schemaName string) ([]domain.Diagnosis, error) {
+	encounter, err := s.encounterRepo.GetByID(encounterID, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	diagnoses, err := s.diagnosisRepo.ListByEncounter(encounter.ID, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	if err := s.recordView(actor, encounter.PatientID, schemaName); err != nil {
+		return nil, err
+	}
+	return diagnoses, nil
+}
+
+func (s *diagnosisService) ListByPatient(patientID uint, actor *domain.Actor, schemaName string) ([]domain.Diagnosis, error) {
+	if _, err := s.patientRepo.GetByID(patientID, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+
+	diagnoses, err := s.diagnosisRepo.ListByPatient(patientID, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	if err := s.recordView(actor, patientID, schemaName); err != nil {
+		return nil, err
+	}
+	return diagnoses, nil
+}
+
+func (s *diagnosisService) Delete(encounterID uint, id uint, actor *domain.Actor, schemaName string) error {
+	encounter, err := s.encounterRepo.GetByID(encounterID, schemaName)
+	if err != nil {
+		return wrapError(err)
+	}
+	diagnosis, err := s.diagnosisRepo.GetByID(encounter.ID, id, schemaName)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	changes := map[string]domain.FieldChange{
+		"vn":   {Before: encounter.VN},
+		"code": {Before: diagnosis.Code},
+		"type": {Before: diagnosis.Type},
+	}
+	event, err := domain.NewAuditEvent(actor, domain.AuditActionPatientDiagnosisDelete, &diagnosis.PatientID, changes)
+	if err != nil {
+		return err
+	}
+
+	if err := s.diagnosisRepo.Delete(diagnosis, event, schemaName); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+// recordView appends a view event for the patient whose diagnoses were shown
+func (s *diagnosisService) recordView(actor *domain.Actor, patientID uint, schemaName string) error {
+	event, err := domain.NewAuditEvent(actor, domain.AuditActionPatientDiagnosisView, &patientID, nil)
+	if err != nil {
+		return err
+	}
+	if err := s.auditRepo.Append([]*domain.AuditEvent{event}, schemaName); err != nil {
+		return fmt.Errorf("failed to record audit event: %w", err)
+	}
+	return nil
+}
+
+// checkDiagnosisCode applies the ICD-10 coding rules that depend on the diagnosis type
+func checkDiagnosisCode(icd10 *domain.ICD10Code, diagnosisType string) error {
+	externalCause := domain.IsExternalCauseCode(icd10.Code)
+	switch {
+	case diagnosisType == domain.DiagnosisTypeExternalCause && !externalCause:
+		return fmt.Errorf("%w: external cause diagnoses need a code from V01-Y98, got %s", domain.ErrInvalidInput, icd10.Code)
+	case diagnosisType != domain.DiagnosisTypeExternalCause && externalCause:
+		return fmt.Errorf("%w: %s is an external cause code and can only be recorded as external_cause", domain.ErrInvalidInput, icd10.Code)
+	case diagnosisType == domain.DiagnosisTypePrincipal && !icd10.Billable:
+		return fmt.Errorf("%w: %s is not a billable code, use one of its subcodes as the principal diagnosis", domain.ErrInvalidInput, icd10.Code)
+	}
+	return nil
+}
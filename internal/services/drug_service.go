@@ -0,0 +1,127 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+type drugService struct {
+	drugRepo domain.DrugRepository
+}
+
+// NewDrugService creates the service managing the drug master of a tenant
+func NewDrugService(drugRepo domain.DrugRepository) domain.DrugService {
+	return &drugService{
+		drugRepo: drugRepo,
+	}
+}
+
+func (s *drugService) List(req *domain.DrugListRequest, schemaName string) ([]domain.Drug, error) {
+	drugs, err := s.drugRepo.List(req.Query, req.IncludeInactive, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return drugs, nil
+}
+
+func (s *drugService) GetByID(id uint, schemaName string) (*domain.Drug, error) {
+	drug, err := s.drugRepo.GetByID(id, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return drug, nil
+}
+
+func (s *drugService) Create(req *domain.DrugRequest, schemaName string) (*domain.Drug, error) {
+	drug := &domain.Drug{}
+	applyDrugRequest(drug, req)
+
+	if err := s.drugRepo.Create(drug, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+	return drug, nil
+}
+
+func (s *drugService) Update(id uint, req *domain.DrugRequest, schemaName string) (*domain.Drug, error) {
+	drug, err := s.drugRepo.GetByID(id, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	applyDrugRequest(drug, req)
+
+	if err := s.drugRepo.Update(drug, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+	return drug, nil
+}
+
+func (s *drugService) ListInteractions(drugID uint, schemaName string) ([]domain.DrugInteraction, error) {
+	if _, err := s.drugRepo.GetByID(drugID, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+
+	interactions, err := s.drugRepo.ListInteractions(drugID, nil, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return interactions, nil
+}
+
+// CreateInteraction records an interaction between two drugs, storing the lower drug ID first
+func (s *drugService) CreateInteraction(drugID uint, req *domain.DrugInteractionRequest, schemaName string) (*domain.DrugInteraction, error) {
+	if req.InteractingDrugID == drugID {
+		return nil, fmt.Errorf("%w: a drug cannot interact with itself", domain.ErrInvalidInput)
+	}
+	if _, err := s.drugRepo.GetByID(drugID, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+	if _, err := s.drugRepo.GetByID(req.InteractingDrugID, schemaName); err != nil {
+		err = wrapError(err)
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, fmt.Errorf("%w: drug %d does not exist", domain.ErrInvalidInput, req.InteractingDrugID)
+		}
+		return nil, err
+	}
+
+	interaction := &domain.DrugInteraction{
+		DrugID:            drugID,
+		InteractingDrugID: req.InteractingDrugID,
+		Severity:          req.Severity,
+		Description:       strings.TrimSpace(req.Description),
+	}
+	if interaction.DrugID > interaction.InteractingDrugID {
+		interaction.DrugID, interaction.InteractingDrugID = interaction.InteractingDrugID, interaction.DrugID
+	}
+
+	if err := s.drugRepo.CreateInteraction(interaction, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+	return interaction, nil
+}
+
+func (s *drugService) DeleteInteraction(drugID uint, id uint, schemaName string) error {
+	interaction, err := s.drugRepo.GetInteraction(drugID, id, schemaName)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	if err := s.drugRepo.DeleteInteraction(interaction, schemaName); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+// applyDrugRequest copies the request onto the drug; codes are stored upper case and classes lower case
+func applyDrugRequest(drug *domain.Drug, req *domain.DrugRequest) {
+	drug.Code = strings.ToUpper(req.Code)
+	drug.GenericName = strings.TrimSpace(req.GenericName)
+	drug.TradeName = strings.TrimSpace(req.TradeName)
+	drug.Strength = strings.TrimSpace(req.Strength)
+	drug.DosageForm = strings.TrimSpace(req.DosageForm)
+	drug.DispensingUnit = strings.TrimSpace(req.DispensingUnit)
+	drug.TherapeuticClass = strings.ToLower(strings.TrimSpace(req.TherapeuticClass))
+	drug.IsActive = req.IsActive == nil || *req.IsActive
+}
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+type encounterService struct {
+	encounterRepo  domain.EncounterRepository
+	patientRepo    domain.PatientRepository
+	departmentRepo domain.DepartmentRepository
+	auditRepo      domain.AuditRepository
+}
+
+// NewEncounterService creates the service for patient visits and department queues
+func NewEncounterService(encounterRepo domain.EncounterRepository, patientRepo domain.PatientRepository, departmentRepo domain.DepartmentRepository, auditRepo domain.AuditRepository) domain.EncounterService {
+	return &encounterService{
+		encounterRepo:  encounterRepo,
+		patientRepo:    patientRepo,
+		departmentRepo: departmentRepo,
+		auditRepo:      auditRepo,
+	}
+}
+
+func (s *encounterService) Create(req *domain.EncounterCreateRequest, actor *domain.Actor, schemaName string) (*domain.Encounter, error) {
+	if _, err := s.patientRepo.GetByID(req.PatientID, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+	department, err := s.departmentRepo.GetByID(req.DepartmentID, schemaName)
+	if err != nil {
+		err = wrapError(err)
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, fmt.Errorf("%w: department %d does not exist", domain.ErrInvalidInput, req.DepartmentID)
+		}
+		return nil, err
+	}
+	if !department.IsActive {
+		return nil, fmt.Errorf("%w: department %s is not active", domain.ErrInvalidInput, department.Code)
+	}
+
+	encounter := &domain.Encounter{
+		PatientID:      req.PatientID,
+		DepartmentID:   department.ID,
+		ChiefComplaint: strings.TrimSpace(req.ChiefComplaint),
+	}
+	if actor != nil {
+		encounter.RegisteredBy = actor.StaffID
+	}
+
+	changes := map[string]domain.FieldChange{
+		"department_id":   {After: department.ID},
+		"chief_complaint": {After: encounter.ChiefComplaint},
+	}
+	event, err := domain.NewAuditEvent(actor, domain.AuditActionPatientEncounterCreate, &encounter.PatientID, changes)
+	if err != nil {
+		return nil, err
+	}
+
+	// The repository issues the VN in the transaction that inserts the encounter
+	if err := s.encounterRepo.Create(encounter, domain.NewVNIssuer(), event, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+	return encounter, nil
+}
+
+func (s *encounterService) GetByID(id uint, actor *domain.Actor, schemaName string) (*domain.Encounter, error) {
+	encounter, err := s.encounterRepo.GetByID(id, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	if err := s.recordView(actor, []uint{encounter.PatientID}, schemaName); err != nil {
+		return nil, err
+	}
+	return encounter, nil
+}
+
+func (s *encounterService) ListByPatient(patientID uint, actor *domain.Actor, schemaName string) ([]domain.Encounter, error) {
+	if _, err := s.patientRepo.GetByID(patientID, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+
+	encounters, err := s.encounterRepo.ListByPatient(patientID, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	if err := s.recordView(actor, []uint{patientID}, schemaName); err != nil {
+		return nil, err
+	}
+	return encounters, nil
+}
+
+func (s *encounterService) UpdateStatus(id uint, req *domain.EncounterStatusRequest, actor *domain.Actor, schemaName string) (*domain.Encounter, error) {
+	encounter, err := s.encounterRepo.GetByID(id, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	from := encounter.Status
+	if next := domain.NextEncounterStatus(from); req.Status != next {
+		return nil, fmt.Errorf("%w: encounter %s is %s and cannot move to %s", domain.ErrInvalidStatusTransition, encounter.VN, from, req.Status)
+	}
+	encounter.SetStatus(req.Status, time.Now())
+
+	changes := map[string]domain.FieldChange{
+		"vn":     {After: encounter.VN},
+		"status": {Before: from, After: encounter.Status},
+	}
+	event, err := domain.NewAuditEvent(actor, domain.AuditActionPatientEncounterStatus, &encounter.PatientID, changes)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.encounterRepo.UpdateStatus(encounter, from, event, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+	return encounter, nil
+}
+
+// Queue lists the encounters registered today that have not finished, first come first
+func (s *encounterService) Queue(req *domain.EncounterQueueRequest, actor *domain.Actor, schemaName string) ([]domain.EncounterQueueEntry, error) {
+	now := time.Now().In(domain.HNTimeZone)
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, domain.HNTimeZone)
+	filter := &domain.EncounterQueueFilter{
+		DepartmentID: req.DepartmentID,
+		Statuses:     []string{domain.EncounterStatusRegistered, domain.EncounterStatusTriaged, domain.EncounterStatusInConsultation},
+		From:         from,
+		To:           from.AddDate(0, 0, 1),
+	}
+	if req.Status != "" {
+		filter.Statuses = []string{req.Status}
+	}
+
+	entries, err := s.encounterRepo.Queue(filter, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	patientIDs := make([]uint, len(entries))
+	for i := range entries {
+		entries[i].Position = i + 1
+		patientIDs[i] = entries[i].PatientID
+	}
+	if err := s.recordView(actor, patientIDs, schemaName); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// recordView appends one view event per patient shown
+func (s *encounterService) recordView(actor *domain.Actor, patientIDs []uint, schemaName string) error {
+	if len(patientIDs) == 0 {
+		return nil
+	}
+	events := make([]*domain.AuditEvent, 0, len(patientIDs))
+	for i := range patientIDs {
+		event, err := domain.NewAuditEvent(actor, domain.AuditActionPatientEncounterView, &patientIDs[i], nil)
+		if err != nil {
+			return err
+		}
+		events = append(events, event)
+	}
+
+	if err := s.auditRepo.Append(events, schemaName); err != nil {
+		return fmt.Errorf("failed to record audit event: %w", err)
+	}
+	return nil
+}
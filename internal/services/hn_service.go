@@ -0,0 +1,192 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+const (
+	// defaultHNPreviewCount is how many HNs a preview lists when the request does not say
+	defaultHNPreviewCount = 5
+	// maxReportedHNCollisions bounds the existing HNs listed when a template is rejected
+	maxReportedHNCollisions = 20
+)
+
+type hnService struct {
+	tenantRepo    domain.TenantRepository
+	hnCounterRepo domain.HNCounterRepository
+}
+
+// NewHNService creates the service managing the HN template of a tenant
+func NewHNService(tenantRepo domain.TenantRepository, hnCounterRepo domain.HNCounterRepository) domain.HNService {
+	return &hnService{
+		tenantRepo:    tenantRepo,
+		hnCounterRepo: hnCounterRepo,
+	}
+}
+
+func (s *hnService) GetSettings(schemaName string) (*domain.HNSettings, error) {
+	tenant, err := s.tenantRepo.GetBySchemaName(schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	template, err := domain.ParseHNTemplate(tenant.HNTemplate)
+	if err != nil {
+		return nil, err
+	}
+	return s.settings(tenant, template, schemaName)
+}
+
+// Preview lists the next HNs of a template, and the existing HNs it would reissue, without
+// allocating a number or saving the template
+func (s *hnService) Preview(req *domain.HNPreviewRequest, schemaName string) (*domain.HNPreview, error) {
+	tenant, err := s.tenantRepo.GetBySchemaName(schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	source := strings.TrimSpace(req.Template)
+	if source == "" {
+		source = tenant.HNTemplate
+	}
+	template, err := domain.ParseHNTemplate(source)
+	if err != nil {
+		return nil, err
+	}
+
+	count := req.Count
+	if count == 0 {
+		count = defaultHNPreviewCount
+	}
+
+	now := time.Now()
+	period := template.Period(now)
+	last, err := s.lastSequence(tenant, period, schemaName)
+	if err != nil {
+		return nil, err
+	}
+
+	next := make([]string, 0, count)
+	for i := 1; i <= count; i++ {
+		hn, err := template.Format(tenant.HospitalCode, now, last+uint64(i))
+		if err != nil {
+			// The period runs out of numbers; the preview shows what is left
+			break
+		}
+		next = append(next, hn)
+	}
+
+	collisions, err := s.findCollisions(tenant, template, now, last, schemaName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.HNPreview{
+		Template:   template.String(),
+		Reset:      template.Reset(),
+		Period:     period,
+		Next:       next,
+		Collisions: collisions,
+	}, nil
+}
+
+// UpdateTemplate saves a template once no existing HN could be issued by it again.
+// Counters are kept per period, so a template with the same reset period continues numbering.
+func (s *hnService) UpdateTemplate(req *domain.HNTemplateRequest, schemaName string) (*domain.HNSettings, error) {
+	tenant, err := s.tenantRepo.GetBySchemaName(schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	template, err := domain.ParseHNTemplate(strings.TrimSpace(req.Template))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	last, err := s.lastSequence(tenant, template.Period(now), schemaName)
+	if err != nil {
+		return nil, err
+	}
+	collisions, err := s.findCollisions(tenant, template, now, last, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	if len(collisions) > 0 {
+		return nil, &domain.HNCollisionError{Collisions: collisions}
+	}
+
+	tenant.HNTemplate = template.String()
+	if err := s.tenantRepo.Update(tenant); err != nil {
+		return nil, wrapError(err)
+	}
+	return s.settings(tenant, template, schemaName)
+}
+
+func (s *hnService) settings(tenant *domain.Tenant, template *domain.HNTemplate, schemaName string) (*domain.HNSettings, error) {
+	now := time.Now()
+	period := template.Period(now)
+	last, err := s.lastSequence(tenant, period, schemaName)
+	if err != nil {
+		return nil, err
+	}
+
+	// An exhausted period has no next HN; registration fails until the period resets
+	next, err := template.Format(tenant.HospitalCode, now, last+1)
+	if err != nil && !errors.Is(err, domain.ErrHNSequenceExhausted) {
+		return nil, err
+	}
+
+	return &domain.HNSettings{
+		Template:     template.String(),
+		Reset:        template.Reset(),
+		Period:       period,
+		LastSequence: last,
+		NextHN:       next,
+	}, nil
+}
+
+// lastSequence returns the last running number issued in period, counting HNRunning
+// for tenants whose never-resetting counter has not been used yet
+func (s *hnService) lastSequence(tenant *domain.Tenant, period string, schemaName string) (uint64, error) {
+	last, err := s.hnCounterRepo.Current(period, schemaName)
+	if err != nil {
+		return 0, wrapError(err)
+	}
+	issuer := domain.HNIssuer{LegacyRunning: tenant.HNRunning}
+	if seed := issuer.Seed(period); seed > last {
+		return seed, nil
+	}
+	return last, nil
+}
+
+// findCollisions returns existing HNs the template would issue again: HNs of a later period,
+// or of the current period with a running number the counter has not reached yet.
+// HNs of earlier periods are safe, their periods never come back.
+func (s *hnService) findCollisions(tenant *domain.Tenant, template *domain.HNTemplate, now time.Time, last uint64, schemaName string) ([]string, error) {
+	hns, err := s.hnCounterRepo.FindHNs(template.Pattern(tenant.HospitalCode), schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	current := template.Period(now)
+	collisions := make([]string, 0)
+	for _, hn := range hns {
+		period, seq, ok := template.Parse(hn, tenant.HospitalCode, now)
+		if !ok {
+			continue
+		}
+		// Periods of one template have the same layout, so they order as strings
+		if period > current || (period == current && seq > last) {
+			collisions = append(collisions, hn)
+			if len(collisions) == maxReportedHNCollisions {
+				break
+			}
+		}
+	}
+	return collisions, nil
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+// icd10CodeQuery recognises a query that is the start of a code, such as J1, J18 or j18.9
+var icd10CodeQuery = regexp.MustCompile(`^[A-Za-z][0-9][0-9A-Za-z.]*$`)
+
+type icd10Service struct {
+	icd10Repo domain.ICD10Repository
+}
+
+// NewICD10Service creates the service for the ICD-10 catalogue
+func NewICD10Service(icd10Repo domain.ICD10Repository) domain.ICD10Service {
+	return &icd10Service{
+		icd10Repo: icd10Repo,
+	}
+}
+
+func (s *icd10Service) GetByCode(code string) (*domain.ICD10Code, error) {
+	icd10, err := s.icd10Repo.GetByCode(domain.NormalizeICD10Code(code))
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return icd10, nil
+}
+
+// Search looks codes up by prefix when the query looks like a code and by description keywords otherwise
+func (s *icd10Service) Search(req *domain.ICD10SearchRequest) ([]domain.ICD10Code, error) {
+	query := strings.TrimSpace(req.Query)
+	if utf8.RuneCountInString(query) < domain.MinICD10SearchLength {
+		return nil, fmt.Errorf("%w: query must be at least %d characters", domain.ErrInvalidInput, domain.MinICD10SearchLength)
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = domain.DefaultICD10SearchLimit
+	}
+	if limit > domain.MaxICD10SearchLimit {
+		return nil, fmt.Errorf("%w: limit must not exceed %d", domain.ErrInvalidInput, domain.MaxICD10SearchLimit)
+	}
+
+	filter := &domain.ICD10SearchFilter{BillableOnly: req.BillableOnly, Limit: limit}
+	if icd10CodeQuery.MatchString(query) {
+		filter.CodePrefix = strings.ToUpper(strings.ReplaceAll(query, ".", ""))
+	} else {
+		filter.Keywords = strings.Fields(query)
+	}
+
+	codes, err := s.icd10Repo.Search(filter)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return codes, nil
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+type idempotencyService struct {
+	idempotencyRepo domain.IdempotencyRepository
+}
+
+// NewIdempotencyService creates the service behind Idempotency-Key handling of create endpoints
+func NewIdempotencyService(idempotencyRepo domain.IdempotencyRepository) domain.IdempotencyService {
+	return &idempotencyService{
+		idempotencyRepo: idempotencyRepo,
+	}
+}
+
+func (s *idempotencyService) Begin(record *domain.IdempotencyKey, schemaName string) (*domain.IdempotencyKey, error) {
+	record.Key = strings.TrimSpace(record.Key)
+	if record.Key == "" || len(record.Key) > domain.MaxIdempotencyKeyLength {
+		return nil, fmt.Errorf("%w: idempotency key must be 1-%d characters", domain.ErrInvalidInput, domain.MaxIdempotencyKeyLength)
+	}
+
+	record.CreatedAt = time.Now()
+	existing, err := s.idempotencyRepo.Reserve(record, record.CreatedAt.Add(-domain.IdempotencyKeyTTL), schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	if existing == nil {
+		return nil, nil
+	}
+
+	// A key only replays for the staff member and request it was first used with
+	if existing.StaffID != record.StaffID || existing.RequestHash != record.RequestHash {
+		return nil, domain.ErrIdempotencyKeyReused
+	}
+	if !existing.IsCompleted() {
+		return nil, domain.ErrIdempotencyKeyInUse
+	}
+	return existing, nil
+}
+
+func (s *idempotencyService) Complete(record *domain.IdempotencyKey, schemaName string) error {
+	return wrapError(s.idempotencyRepo.Complete(record, schemaName))
+}
+
+func (s *idempotencyService) Release(record *domain.IdempotencyKey, schemaName string) error {
+	return wrapError(s.idempotencyRepo.Release(record.Key, schemaName))
+}
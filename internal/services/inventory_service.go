@@ -0,0 +1,355 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+type inventoryService struct {
+	inventoryRepo domain.InventoryRepository
+	drugRepo      domain.DrugRepository
+}
+
+// NewInventoryService creates the service for pharmacy stock locations, lots and the stock ledger
+func NewInventoryService(inventoryRepo domain.InventoryRepository, drugRepo domain.DrugRepository) domain.InventoryService {
+	return &inventoryService{
+		inventoryRepo: inventoryRepo,
+		drugRepo:      drugRepo,
+	}
+}
+
+func (s *inventoryService) ListLocations(includeInactive bool, schemaName string) ([]domain.StockLocation, error) {
+	locations, err := s.inventoryRepo.ListLocations(includeInactive, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return locations, nil
+}
+
+func (s *inventoryService) CreateLocation(req *domain.StockLocationRequest, schemaName string) (*domain.StockLocation, error) {
+	location := &domain.StockLocation{}
+	applyStockLocationRequest(location, req)
+
+	if err := s.inventoryRepo.CreateLocation(location, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+	return location, nil
+}
+
+func (s *inventoryService) UpdateLocation(id uint, req *domain.StockLocationRequest, schemaName string) (*domain.StockLocation, error) {
+	location, err := s.inventoryRepo.GetLocation(id, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	applyStockLocationRequest(location, req)
+
+	if err := s.inventoryRepo.UpdateLocation(location, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+	return location, nil
+}
+
+func (s *inventoryService) ListReorderLevels(locationID uint, schemaName string) ([]domain.StockReorderLevel, error) {
+	if _, err := s.inventoryRepo.GetLocation(locationID, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+
+	levels, err := s.inventoryRepo.ListReorderLevels(locationID, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return levels, nil
+}
+
+func (s *inventoryService) SetReorderLevel(locationID uint, req *domain.StockReorderLevelRequest, schemaName string) (*domain.StockReorderLevel, error) {
+	if _, err := s.inventoryRepo.GetLocation(locationID, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+	if _, err := s.getDrug(req.DrugID, schemaName); err != nil {
+		return nil, err
+	}
+
+	level := &domain.StockReorderLevel{
+		LocationID:   locationID,
+		DrugID:       req.DrugID,
+		ReorderLevel: *req.ReorderLevel,
+	}
+	if err := s.inventoryRepo.SaveReorderLevel(level, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+	return level, nil
+}
+
+func (s *inventoryService) ListStock(req *domain.StockListRequest, schemaName string) ([]domain.StockLot, error) {
+	filter := &domain.StockLotFilter{InStockOnly: true}
+	if req.LocationID != 0 {
+		filter.LocationID = &req.LocationID
+	}
+	if req.DrugID != 0 {
+		filter.DrugID = &req.DrugID
+	}
+
+	lots, err := s.inventoryRepo.ListLots(filter, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return lots, nil
+}
+
+// Receive books a goods-received note into an active location. Lots must not have expired and
+// each lot may appear once per note.
+func (s *inventoryService) Receive(req *domain.GoodsReceiptRequest, actor *domain.Actor, schemaName string) (*domain.GoodsReceipt, error) {
+	location, err := getActiveStockLocation(s.inventoryRepo, req.LocationID, schemaName)
+	if err != nil {
+		return nil, err
+	}
+
+	today, _ := parseCalendarDay("")
+	receipt := &domain.GoodsReceipt{
+		LocationID:      location.ID,
+		Supplier:        strings.TrimSpace(req.Supplier),
+		ReferenceNumber: strings.TrimSpace(req.ReferenceNumber),
+		Note:            strings.TrimSpace(req.Note),
+		ReceivedAt:      time.Now(),
+		Items:           make([]domain.GoodsReceiptItem, 0, len(req.Items)),
+	}
+	if actor != nil {
+		receipt.ReceivedBy = actor.StaffID
+	}
+
+	seen := make(map[string]bool, len(req.Items))
+	for i, itemReq := range req.Items {
+		lotNumber := strings.TrimSpace(itemReq.LotNumber)
+		if lotNumber == "" {
+			return nil, fmt.Errorf("%w: items[%d].lot_number is required", domain.ErrInvalidInput, i)
+		}
+		key := fmt.Sprintf("%d/%s", itemReq.DrugID, lotNumber)
+		if seen[key] {
+			return nil, fmt.Errorf("%w: lot %s of drug %d appears more than once", domain.ErrInvalidInput, lotNumber, itemReq.DrugID)
+		}
+		seen[key] = true
+
+		expiry, err := time.Parse(domain.DateFormat, itemReq.ExpiryDate)
+		if err != nil {
+			return nil, fmt.Errorf("%w: items[%d].expiry_date must be in YYYY-MM-DD format", domain.ErrInvalidInput, i)
+		}
+		if expiry.Before(today) {
+			return nil, fmt.Errorf("%w: lot %s expired on %s", domain.ErrInvalidInput, lotNumber, itemReq.ExpiryDate)
+		}
+		if _, err := s.getDrug(itemReq.DrugID, schemaName); err != nil {
+			return nil, err
+		}
+
+		receipt.Items = append(receipt.Items, domain.GoodsReceiptItem{
+			DrugID:     itemReq.DrugID,
+			LotNumber:  lotNumber,
+			ExpiryDate: expiry,
+			Quantity:   itemReq.Quantity,
+			UnitCost:   itemReq.UnitCost,
+		})
+	}
+
+	if err := s.inventoryRepo.Receive(receipt, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+	return receipt, nil
+}
+
+func (s *inventoryService) GetReceipt(id uint, schemaName string) (*domain.GoodsReceipt, error) {
+	receipt, err := s.inventoryRepo.GetReceipt(id, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return receipt, nil
+}
+
+func (s *inventoryService) ListReceipts(req *domain.GoodsReceiptListRequest, schemaName string) ([]domain.GoodsReceipt, error) {
+	filter := &domain.GoodsReceiptFilter{}
+	if req.LocationID != 0 {
+		filter.LocationID = &req.LocationID
+	}
+	var err error
+	if filter.From, filter.To, err = parseDayRange(req.From, req.To); err != nil {
+		return nil, err
+	}
+
+	receipts, err := s.inventoryRepo.ListReceipts(filter, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return receipts, nil
+}
+
+// Adjust corrects the balance of a lot after a stock count or writes off damaged or expired stock
+func (s *inventoryService) Adjust(req *domain.StockAdjustmentRequest, actor *domain.Actor, schemaName string) (*domain.StockMovement, error) {
+	if _, err := s.inventoryRepo.GetLot(req.LotID, schemaName); err != nil {
+		err = wrapError(err)
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, fmt.Errorf("%w: stock lot %d does not exist", domain.ErrInvalidInput, req.LotID)
+		}
+		return nil, err
+	}
+
+	movement := &domain.StockMovement{
+		LotID:    req.LotID,
+		Type:     domain.StockMovementAdjustment,
+		Quantity: req.Quantity,
+		Reason:   strings.TrimSpace(req.Reason),
+	}
+	if actor != nil {
+		movement.PerformedBy = actor.StaffID
+	}
+	if err := s.inventoryRepo.Adjust(movement, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+	return movement, nil
+}
+
+// StockCard lists the ledger of a drug at a location with a running balance. Without from the
+// card starts at the first movement.
+func (s *inventoryService) StockCard(req *domain.StockCardRequest, schemaName string) (*domain.StockCard, error) {
+	if _, err := s.inventoryRepo.GetLocation(req.LocationID, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+	if _, err := s.getDrug(req.DrugID, schemaName); err != nil {
+		return nil, err
+	}
+
+	filter := &domain.StockMovementFilter{LocationID: req.LocationID, DrugID: req.DrugID}
+	var err error
+	if filter.From, filter.To, err = parseDayRange(req.From, req.To); err != nil {
+		return nil, err
+	}
+
+	var opening float64
+	if filter.From != nil {
+		if opening, err = s.inventoryRepo.Balance(req.LocationID, req.DrugID, *filter.From, schemaName); err != nil {
+			return nil, wrapError(err)
+		}
+	}
+	movements, err := s.inventoryRepo.ListMovements(filter, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return domain.NewStockCard(req.LocationID, req.DrugID, opening, movements), nil
+}
+
+// Alerts returns the drugs at or below their reorder level and the lots that have expired or
+// expire within the window
+func (s *inventoryService) Alerts(req *domain.StockAlertRequest, schemaName string) (*domain.StockAlerts, error) {
+	var locationID *uint
+	if req.LocationID != 0 {
+		locationID = &req.LocationID
+	}
+	withinDays := req.WithinDays
+	if withinDays <= 0 {
+		withinDays = domain.DefaultNearExpiryDays
+	}
+	if withinDays > domain.MaxNearExpiryDays {
+		withinDays = domain.MaxNearExpiryDays
+	}
+
+	today, _ := parseCalendarDay("")
+	lowStock, err := s.inventoryRepo.LowStock(locationID, today, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	// A lot expiring on the last day of the window is still reported
+	nearExpiry, err := s.inventoryRepo.NearExpiry(locationID, today.AddDate(0, 0, withinDays+1), schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	for i := range nearExpiry {
+		expiry := nearExpiry[i].ExpiryDate
+		expiryDay := time.Date(expiry.Year(), expiry.Month(), expiry.Day(), 0, 0, 0, 0, time.UTC)
+		nearExpiry[i].DaysToExpiry = int(expiryDay.Sub(today).Hours() / 24)
+		nearExpiry[i].Expired = expiryDay.Before(today)
+	}
+
+	alerts := &domain.StockAlerts{
+		LowStock:   lowStock,
+		NearExpiry: nearExpiry,
+	}
+	if alerts.LowStock == nil {
+		alerts.LowStock = []domain.LowStockAlert{}
+	}
+	if alerts.NearExpiry == nil {
+		alerts.NearExpiry = []domain.NearExpiryAlert{}
+	}
+	return alerts, nil
+}
+
+func (s *inventoryService) RebuildBalances(schemaName string) (*domain.StockRebuildResult, error) {
+	corrected, err := s.inventoryRepo.RebuildBalances(schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return &domain.StockRebuildResult{CorrectedLots: corrected}, nil
+}
+
+// getDrug loads a drug named in a request, reporting a missing one as invalid input
+func (s *inventoryService) getDrug(id uint, schemaName string) (*domain.Drug, error) {
+	drug, err := s.drugRepo.GetByID(id, schemaName)
+	if err != nil {
+		err = wrapError(err)
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, fmt.Errorf("%w: drug %d does not exist", domain.ErrInvalidInput, id)
+		}
+		return nil, err
+	}
+	return drug, nil
+}
+
+// getActiveStockLocation loads a stock location named in a request, reporting a missing or
+// inactive one as invalid input
+func getActiveStockLocation(inventoryRepo domain.InventoryRepository, id uint, schemaName string) (*domain.StockLocation, error) {
+	location, err := inventoryRepo.GetLocation(id, schemaName)
+	if err != nil {
+		err = wrapError(err)
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, fmt.Errorf("%w: stock location %d does not exist", domain.ErrInvalidInput, id)
+		}
+		return nil, err
+	}
+	if !location.IsActive {
+		return nil, fmt.Errorf("%w: stock location %s is not active", domain.ErrInvalidInput, location.Code)
+	}
+	return location, nil
+}
+
+// applyStockLocationRequest copies the request onto the location; codes are stored upper case
+func applyStockLocationRequest(location *domain.StockLocation, req *domain.StockLocationRequest) {
+	location.Code = strings.ToUpper(req.Code)
+	location.Name = strings.TrimSpace(req.Name)
+	location.IsActive = req.IsActive == nil || *req.IsActive
+}
+
+// parseDayRange turns inclusive from and to dates into the start of from and the start of the
+// day after to in Thai time; either may be empty
+func parseDayRange(fromDate string, toDate string) (*time.Time, *time.Time, error) {
+	var from, to *time.Time
+	if fromDate != "" {
+		day, err := parseCalendarDay(fromDate)
+		if err != nil {
+			return nil, nil, err
+		}
+		start := thaiMidnight(day)
+		from = &start
+	}
+	if toDate != "" {
+		day, err := parseCalendarDay(toDate)
+		if err != nil {
+			return nil, nil, err
+		}
+		end := thaiMidnight(day).AddDate(0, 0, 1)
+		to = &end
+	}
+	if from != nil && to != nil && !from.Before(*to) {
+		return nil, nil, fmt.Errorf("%w: from must not be after to", domain.ErrInvalidInput)
+	}
+	return from, to, nil
+}
@@ -0,0 +1,622 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+type labService struct {
+	labRepo        domain.LabRepository
+	encounterRepo  domain.EncounterRepository
+	patientRepo    domain.PatientRepository
+	auditRepo      domain.AuditRepository
+	analyzerSource domain.AnalyzerSource
+
+	// importMu serializes analyzer imports so two requests do not read the same batches
+	importMu sync.Mutex
+}
+
+// NewLabService creates the service for the lab test catalogue, lab orders and their results
+func NewLabService(labRepo domain.LabRepository, encounterRepo domain.EncounterRepository, patientRepo domain.PatientRepository, auditRepo domain.AuditRepository, analyzerSource domain.AnalyzerSource) domain.LabService {
+	return &labService{
+		labRepo:        labRepo,
+		encounterRepo:  encounterRepo,
+		patientRepo:    patientRepo,
+		auditRepo:      auditRepo,
+		analyzerSource: analyzerSource,
+	}
+}
+
+func (s *labService) ListTests(req *domain.LabTestListRequest, schemaName string) ([]domain.LabTest, error) {
+	tests, err := s.labRepo.ListTests(req.Query, req.IncludeInactive, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return tests, nil
+}
+
+func (s *labService) GetTest(id uint, schemaName string) (*domain.LabTest, error) {
+	test, err := s.labRepo.GetTest(id, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return test, nil
+}
+
+func (s *labService) CreateTest(req *domain.LabTestRequest, schemaName string) (*domain.LabTest, error) {
+	test := &domain.LabTest{}
+	if err := applyLabTestRequest(test, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.labRepo.CreateTest(test, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+	return test, nil
+}
+
+func (s *labService) UpdateTest(id uint, req *domain.LabTestRequest, schemaName string) (*domain.LabTest, error) {
+	test, err := s.labRepo.GetTest(id, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	if err := applyLabTestRequest(test, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.labRepo.UpdateTest(test, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+	return test, nil
+}
+
+// applyLabTestRequest copies a catalogue entry from the request. Reference ranges belong to
+// numeric tests and an expected text to text tests.
+func applyLabTestRequest(test *domain.LabTest, req *domain.LabTestRequest) error {
+	normalText := strings.TrimSpace(req.NormalText)
+	switch req.ResultType {
+	case domain.LabResultTypeNumeric:
+		if normalText != "" {
+			return fmt.Errorf("%w: normal_text is only used by text tests", domain.ErrInvalidInput)
+		}
+		for i := range req.ReferenceRanges {
+			if err := req.ReferenceRanges[i].Validate(); err != nil {
+				return fmt.Errorf("reference range %d: %w", i+1, err)
+			}
+		}
+	case domain.LabResultTypeText:
+		if len(req.ReferenceRanges) > 0 {
+			return fmt.Errorf("%w: reference_ranges are only used by numeric tests", domain.ErrInvalidInput)
+		}
+	}
+
+	test.Code = strings.ToUpper(req.Code)
+	test.Name = strings.TrimSpace(req.Name)
+	test.SpecimenType = req.SpecimenType
+	test.ResultType = req.ResultType
+	test.Unit = strings.TrimSpace(req.Unit)
+	test.NormalText = normalText
+	test.ReferenceRanges = domain.LabReferenceRanges(req.ReferenceRanges)
+	test.IsActive = req.IsActive == nil || *req.IsActive
+	return nil
+}
+
+func (s *labService) Create(encounterID uint, req *domain.LabOrderRequest, actor *domain.Actor, schemaName string) (*domain.LabOrder, error) {
+	encounter, err := s.encounterRepo.GetByID(encounterID, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	if encounter.Status == domain.EncounterStatusDone {
+		return nil, fmt.Errorf("%w: encounter %s is done", domain.ErrInvalidInput, encounter.VN)
+	}
+
+	seen := make(map[uint]bool, len(req.TestIDs))
+	for _, id := range req.TestIDs {
+		if seen[id] {
+			return nil, fmt.Errorf("%w: test %d is listed twice", domain.ErrInvalidInput, id)
+		}
+		seen[id] = true
+	}
+	tests, err := s.labRepo.ListTestsByIDs(req.TestIDs, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	testsByID := make(map[uint]*domain.LabTest, len(tests))
+	for i := range tests {
+		testsByID[tests[i].ID] = &tests[i]
+	}
+
+	priority := req.Priority
+	if priority == "" {
+		priority = domain.LabPriorityRoutine
+	}
+	now := time.Now()
+	order := &domain.LabOrder{
+		EncounterID:  encounter.ID,
+		PatientID:    encounter.PatientID,
+		Priority:     priority,
+		ClinicalNote: strings.TrimSpace(req.ClinicalNote),
+		Status:       domain.LabOrderStatusOrdered,
+		OrderedAt:    now,
+	}
+	if actor != nil {
+		order.OrderedBy = actor.StaffID
+	}
+
+	// Tests sharing a specimen type are run on one specimen, in the order they were requested
+	codes := make([]string, 0, len(req.TestIDs))
+	specimenTypes := make(map[string]bool)
+	for _, id := range req.TestIDs {
+		test, ok := testsByID[id]
+		if !ok {
+			return nil, fmt.Errorf("%w: test %d does not exist", domain.ErrInvalidInput, id)
+		}
+		if !test.IsActive {
+			return nil, fmt.Errorf("%w: test %s is not active", domain.ErrInvalidInput, test.Code)
+		}
+		if !specimenTypes[test.SpecimenType] {
+			specimenTypes[test.SpecimenType] = true
+			order.Specimens = append(order.Specimens, domain.LabSpecimen{
+				SpecimenType: test.SpecimenType,
+				Status:       domain.LabSpecimenStatusPending,
+			})
+		}
+		order.Items = append(order.Items, domain.LabOrderItem{
+			TestID:       test.ID,
+			TestCode:     test.Code,
+			TestName:     test.Name,
+			SpecimenType: test.SpecimenType,
+			ResultType:   test.ResultType,
+			Unit:         test.Unit,
+			Status:       domain.LabItemStatusPending,
+		})
+		codes = append(codes, test.Code)
+	}
+
+	event, err := domain.NewAuditEvent(actor, domain.AuditActionPatientLabOrder, &order.PatientID, map[string]domain.FieldChange{
+		"vn":       {After: encounter.VN},
+		"tests":    {After: codes},
+		"priority": {After: order.Priority},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.labRepo.CreateOrder(order, event, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+	return order, nil
+}
+
+func (s *labService) GetByID(id uint, actor *domain.Actor, schemaName string) (*domain.LabOrder, error) {
+	order, err := s.labRepo.GetOrder(id, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	if err := s.recordView(actor, order.PatientID, schemaName); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+func (s *labService) ListByEncounter(encounterID uint, actor *domain.Actor, schemaName string) ([]domain.LabOrder, error) {
+	encounter, err := s.encounterRepo.GetByID(encounterID, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	orders, err := s.labRepo.ListOrdersByEncounter(encounter.ID, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	if err := s.recordView(actor, encounter.PatientID, schemaName); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// ListByPatient returns the orders of a patient with their results, newest first
+func (s *labService) ListByPatient(patientID uint, req *domain.LabOrderListRequest, actor *domain.Actor, schemaName string) ([]domain.LabOrder, error) {
+	if _, err := s.patientRepo.GetByID(patientID, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+
+	orders, err := s.labRepo.ListOrdersByPatient(patientID, req.Status, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	if err := s.recordView(actor, patientID, schemaName); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+func (s *labService) Cancel(id uint, req *domain.LabCancelRequest, actor *domain.Actor, schemaName string) (*domain.LabOrder, error) {
+	order, err := s.labRepo.GetOrder(id, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	if !order.Cancellable() {
+		return nil, fmt.Errorf("%w: lab order %d is %s and its specimens are at the lab", domain.ErrInvalidStatusTransition, order.ID, order.Status)
+	}
+
+	var staffID uint
+	if actor != nil {
+		staffID = actor.StaffID
+	}
+	version, from := order.UpdatedAt, order.Status
+	order.Cancel(staffID, strings.TrimSpace(req.Reason), time.Now())
+
+	event, err := domain.NewAuditEvent(actor, domain.AuditActionPatientLabCancel, &order.PatientID, map[string]domain.FieldChange{
+		"order_id":      {After: order.ID},
+		"status":        {Before: from, After: order.Status},
+		"cancel_reason": {After: order.CancelReason},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.labRepo.SaveOrder(order, version, nil, nil, event, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+	return order, nil
+}
+
+// ListSpecimens returns the specimens of orders placed on the requested day, today by default
+func (s *labService) ListSpecimens(req *domain.LabSpecimenListRequest, schemaName string) ([]domain.LabSpecimenEntry, error) {
+	day, err := parseCalendarDay(req.Date)
+	if err != nil {
+		return nil, err
+	}
+	from := thaiMidnight(day)
+
+	entries, err := s.labRepo.ListSpecimens(&domain.LabSpecimenFilter{
+		Status: req.Status,
+		From:   from,
+		To:     from.AddDate(0, 0, 1),
+	}, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return entries, nil
+}
+
+func (s *labService) Label(barcode string, actor *domain.Actor, schemaName string) (*domain.LabSpecimenLabel, error) {
+	specimen, order, err := s.getSpecimen(barcode, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status == domain.LabOrderStatusCancelled {
+		return nil, fmt.Errorf("%w: lab order %d is cancelled", domain.ErrInvalidStatusTransition, order.ID)
+	}
+	patient, err := s.patientRepo.GetByID(order.PatientID, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	if err := s.recordView(actor, order.PatientID, schemaName); err != nil {
+		return nil, err
+	}
+	return domain.NewLabSpecimenLabel(specimen, order, patient), nil
+}
+
+func (s *labService) Collect(barcode string, actor *domain.Actor, schemaName string) (*domain.LabOrder, error) {
+	return s.moveSpecimen(barcode, domain.LabSpecimenStatusCollected, "", actor, schemaName)
+}
+
+func (s *labService) Receive(barcode string, actor *domain.Actor, schemaName string) (*domain.LabOrder, error) {
+	return s.moveSpecimen(barcode, domain.LabSpecimenStatusReceived, "", actor, schemaName)
+}
+
+func (s *labService) Reject(barcode string, req *domain.LabSpecimenRejectRequest, actor *domain.Actor, schemaName string) (*domain.LabOrder, error) {
+	return s.moveSpecimen(barcode, domain.LabSpecimenStatusRejected, strings.TrimSpace(req.Reason), actor, schemaName)
+}
+
+// moveSpecimen moves a specimen one step and refreshes the status of its order. A specimen is
+// not rejected once a test run on it has a result; the result is corrected instead.
+func (s *labService) moveSpecimen(barcode string, status string, reason string, actor *domain.Actor, schemaName string) (*domain.LabOrder, error) {
+	specimen, order, err := s.getSpecimen(barcode, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status == domain.LabOrderStatusCancelled || order.Status == domain.LabOrderStatusCompleted {
+		return nil, fmt.Errorf("%w: lab order %d is %s", domain.ErrInvalidStatusTransition, order.ID, order.Status)
+	}
+	from := specimen.Status
+	if !domain.CanMoveLabSpecimen(from, status) {
+		return nil, fmt.Errorf("%w: specimen %s is %s and cannot move to %s", domain.ErrInvalidStatusTransition, specimen.Barcode, from, status)
+	}
+	if status == domain.LabSpecimenStatusRejected && order.SpecimenResulted(specimen.ID) {
+		return nil, fmt.Errorf("%w: specimen %s already has results", domain.ErrInvalidStatusTransition, specimen.Barcode)
+	}
+
+	var staffID uint
+	if actor != nil {
+		staffID = actor.StaffID
+	}
+	version := order.UpdatedAt
+	now := time.Now()
+	specimen.SetStatus(status, staffID, now)
+	changes := map[string]domain.FieldChange{
+		"order_id": {After: order.ID},
+		"barcode":  {After: specimen.Barcode},
+		"status":   {Before: from, After: specimen.Status},
+	}
+	if status == domain.LabSpecimenStatusRejected {
+		specimen.RejectReason = reason
+		changes["reject_reason"] = domain.FieldChange{After: reason}
+	}
+	order.RefreshStatus(now)
+
+	event, err := domain.NewAuditEvent(actor, domain.AuditActionPatientLabSpecimen, &order.PatientID, changes)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.labRepo.SaveOrder(order, version, []*domain.LabSpecimen{specimen}, nil, event, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+	return order, nil
+}
+
+// getSpecimen returns the specimen with the barcode as held by its order
+func (s *labService) getSpecimen(barcode string, schemaName string) (*domain.LabSpecimen, *domain.LabOrder, error) {
+	found, err := s.labRepo.GetSpecimenByBarcode(barcode, schemaName)
+	if err != nil {
+		return nil, nil, wrapError(err)
+	}
+	order, err := s.labRepo.GetOrder(found.OrderID, schemaName)
+	if err != nil {
+		return nil, nil, wrapError(err)
+	}
+	specimen := order.Specimen(found.Barcode)
+	if specimen == nil {
+		return nil, nil, domain.ErrNotFound
+	}
+	return specimen, order, nil
+}
+
+func (s *labService) RecordResults(id uint, req *domain.LabResultRequest, actor *domain.Actor, schemaName string) (*domain.LabOrder, error) {
+	order, err := s.labRepo.GetOrder(id, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	recorder, err := s.newResultRecorder(order, actor, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	items := make([]*domain.LabOrderItem, 0, len(req.Results))
+	for _, entry := range req.Results {
+		item := order.Item(entry.ItemID)
+		if item == nil {
+			return nil, fmt.Errorf("%w: item %d is not part of lab order %d", domain.ErrInvalidInput, entry.ItemID, order.ID)
+		}
+		for _, recorded := range items {
+			if recorded.ID == item.ID {
+				return nil, fmt.Errorf("%w: item %d is listed twice", domain.ErrInvalidInput, item.ID)
+			}
+		}
+		changed, err := recorder.record(item, entry.Value, entry.Unit, domain.LabResultSourceManual, "", now)
+		if err != nil {
+			return nil, err
+		}
+		if changed {
+			items = append(items, item)
+		}
+	}
+	if len(items) == 0 {
+		return order, nil
+	}
+
+	if err := recorder.save(items, now); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// ImportFromAnalyzer records the results of every batch waiting at the analyzer source. Each
+// result is saved on its own, so a batch that fails part way is fetched again and its saved
+// results, having the same value, are skipped. Results that cannot be recorded are rejected
+// with the reason in the batch report.
+func (s *labService) ImportFromAnalyzer(actor *domain.Actor, schemaName string) (*domain.LabImportSummary, error) {
+	s.importMu.Lock()
+	defer s.importMu.Unlock()
+
+	batches, err := s.analyzerSource.Fetch(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrAnalyzerUnavailable, err)
+	}
+
+	summary := &domain.LabImportSummary{Batches: []domain.LabImportBatchReport{}}
+	for _, batch := range batches {
+		report := domain.LabImportBatchReport{
+			BatchID:    batch.ID,
+			Instrument: batch.Instrument,
+			Rejected:   []domain.LabImportRejection{},
+		}
+		if batch.Err != nil {
+			report.Error = batch.Err.Error()
+		}
+		for _, result := range batch.Results {
+			err := s.importResult(&result, batch.Instrument, actor, schemaName)
+			if err == nil {
+				report.Imported++
+				continue
+			}
+			if !isLabImportRejection(err) {
+				return nil, err
+			}
+			report.Rejected = append(report.Rejected, domain.LabImportRejection{
+				Line:     result.Line,
+				Barcode:  result.Barcode,
+				TestCode: result.TestCode,
+				Reason:   err.Error(),
+			})
+		}
+
+		if err := s.analyzerSource.Complete(schemaName, &report); err != nil {
+			return nil, fmt.Errorf("%w: %v", domain.ErrAnalyzerUnavailable, err)
+		}
+		summary.Batches = append(summary.Batches, report)
+		summary.Imported += report.Imported
+		summary.Rejected += len(report.Rejected)
+	}
+	return summary, nil
+}
+
+// isLabImportRejection reports whether err concerns the analyzer result itself rather than
+// the database, so the result is rejected and the import goes on
+func isLabImportRejection(err error) bool {
+	return errors.Is(err, domain.ErrInvalidInput) || errors.Is(err, domain.ErrNotFound) ||
+		errors.Is(err, domain.ErrInvalidStatusTransition) || errors.Is(err, domain.ErrPreconditionFailed)
+}
+
+// importResult records one analyzer result against the specimen with its barcode
+func (s *labService) importResult(result *domain.AnalyzerResult, instrument string, actor *domain.Actor, schemaName string) error {
+	if result.Barcode == "" || result.TestCode == "" {
+		return fmt.Errorf("%w: barcode and test code are required", domain.ErrInvalidInput)
+	}
+	specimen, order, err := s.getSpecimen(result.Barcode, schemaName)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return fmt.Errorf("%w: specimen %s does not exist", domain.ErrNotFound, result.Barcode)
+		}
+		return err
+	}
+	item := order.ItemByTestCode(specimen.ID, result.TestCode)
+	if item == nil {
+		return fmt.Errorf("%w: test %s was not ordered on specimen %s", domain.ErrInvalidInput, result.TestCode, specimen.Barcode)
+	}
+
+	recorder, err := s.newResultRecorder(order, actor, schemaName)
+	if err != nil {
+		return err
+	}
+	at := time.Now()
+	if result.ResultedAt != nil {
+		at = *result.ResultedAt
+	}
+	changed, err := recorder.record(item, result.Value, result.Unit, domain.LabResultSourceAnalyzer, instrument, at)
+	if err != nil || !changed {
+		return err
+	}
+	return recorder.save([]*domain.LabOrderItem{item}, at)
+}
+
+// labResultRecorder records results on the items of one order for its patient
+type labResultRecorder struct {
+	service    *labService
+	order      *domain.LabOrder
+	version    time.Time
+	patient    *domain.Patient
+	tests      map[uint]*domain.LabTest
+	changes    map[string]domain.FieldChange
+	actor      *domain.Actor
+	schemaName string
+}
+
+func (s *labService) newResultRecorder(order *domain.LabOrder, actor *domain.Actor, schemaName string) (*labResultRecorder, error) {
+	if order.Status == domain.LabOrderStatusCancelled {
+		return nil, fmt.Errorf("%w: lab order %d is cancelled", domain.ErrInvalidStatusTransition, order.ID)
+	}
+	patient, err := s.patientRepo.GetByID(order.PatientID, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return &labResultRecorder{
+		service:    s,
+		order:      order,
+		version:    order.UpdatedAt,
+		patient:    patient,
+		tests:      make(map[uint]*domain.LabTest),
+		changes:    map[string]domain.FieldChange{"order_id": {After: order.ID}},
+		actor:      actor,
+		schemaName: schemaName,
+	}, nil
+}
+
+// record sets the result of item, reporting false when it already holds the same value. The
+// specimen must have been received and the unit, when given, must be the unit of the test.
+func (r *labResultRecorder) record(item *domain.LabOrderItem, value string, unit string, source string, instrument string, at time.Time) (bool, error) {
+	specimen := r.specimen(item.SpecimenID)
+	if specimen == nil || specimen.Status != domain.LabSpecimenStatusReceived {
+		return false, fmt.Errorf("%w: the specimen of %s has not been received", domain.ErrInvalidStatusTransition, item.TestCode)
+	}
+	if unit = strings.TrimSpace(unit); unit != "" && !strings.EqualFold(unit, item.Unit) {
+		return false, fmt.Errorf("%w: result of %s is in %s, expected %s", domain.ErrInvalidInput, item.TestCode, unit, item.Unit)
+	}
+	if item.Status != domain.LabItemStatusPending && item.Value == strings.TrimSpace(value) {
+		return false, nil
+	}
+
+	test, ok := r.tests[item.TestID]
+	if !ok {
+		var err error
+		if test, err = r.service.labRepo.GetTest(item.TestID, r.schemaName); err != nil {
+			return false, wrapError(err)
+		}
+		r.tests[item.TestID] = test
+	}
+
+	before := item.Value
+	ageYears := domain.AgeInMonths(r.patient.DateOfBirth, at) / 12
+	if err := item.SetResult(test, value, r.patient.Gender, ageYears, at); err != nil {
+		return false, err
+	}
+	var staffID uint
+	if r.actor != nil {
+		staffID = r.actor.StaffID
+	}
+	item.ResultSource = source
+	item.Instrument = instrument
+	item.ResultedBy = &staffID
+
+	change := domain.FieldChange{After: strings.TrimSpace(item.Value + " " + item.Flag)}
+	if item.Status == domain.LabItemStatusCorrected {
+		change.Before = before
+	}
+	r.changes[item.TestCode] = change
+	return true, nil
+}
+
+// save refreshes the status of the order and saves the recorded items with one audit event
+func (r *labResultRecorder) save(items []*domain.LabOrderItem, at time.Time) error {
+	r.order.RefreshStatus(at)
+	event, err := domain.NewAuditEvent(r.actor, domain.AuditActionPatientLabResult, &r.order.PatientID, r.changes)
+	if err != nil {
+		return err
+	}
+	if err := r.service.labRepo.SaveOrder(r.order, r.version, nil, items, event, r.schemaName); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+func (r *labResultRecorder) specimen(id uint) *domain.LabSpecimen {
+	for i := range r.order.Specimens {
+		if r.order.Specimens[i].ID == id {
+			return &r.order.Specimens[i]
+		}
+	}
+	return nil
+}
+
+// recordView appends a view event for the patient whose lab orders were shown
+func (s *labService) recordView(actor *domain.Actor, patientID uint, schemaName string) error {
+	event, err := domain.NewAuditEvent(actor, domain.AuditActionPatientLabView, &patientID, nil)
+	if err != nil {
+		return err
+	}
+	if err := s.auditRepo.Append([]*domain.AuditEvent{event}, schemaName); err != nil {
+		return fmt.Errorf("failed to record audit event: %w", err)
+	}
+	return nil
+}
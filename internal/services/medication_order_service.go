@@ -0,0 +1,346 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+type medicationOrderService struct {
+	orderRepo     domain.MedicationOrderRepository
+	drugRepo      domain.DrugRepository
+	encounterRepo domain.EncounterRepository
+	patientRepo   domain.PatientRepository
+	allergyRepo   domain.PatientAllergyRepository
+	inventoryRepo domain.InventoryRepository
+	auditRepo     domain.AuditRepository
+}
+
+// NewMedicationOrderService creates the service for prescriptions placed during encounters
+func NewMedicationOrderService(orderRepo domain.MedicationOrderRepository, drugRepo domain.DrugRepository, encounterRepo domain.EncounterRepository, patientRepo domain.PatientRepository, allergyRepo domain.PatientAllergyRepository, inventoryRepo domain.InventoryRepository, auditRepo domain.AuditRepository) domain.MedicationOrderService {
+	return &medicationOrderService{
+		orderRepo:     orderRepo,
+		drugRepo:      drugRepo,
+		encounterRepo: encounterRepo,
+		patientRepo:   patientRepo,
+		allergyRepo:   allergyRepo,
+		inventoryRepo: inventoryRepo,
+		auditRepo:     auditRepo,
+	}
+}
+
+func (s *medicationOrderService) Create(encounterID uint, req *domain.MedicationOrderRequest, actor *domain.Actor, schemaName string) (*domain.MedicationOrder, error) {
+	encounter, err := s.encounterRepo.GetByID(encounterID, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	if encounter.Status == domain.EncounterStatusDone {
+		return nil, fmt.Errorf("%w: encounter %s is done", domain.ErrInvalidInput, encounter.VN)
+	}
+
+	drug, err := s.drugRepo.GetByID(req.DrugID, schemaName)
+	if err != nil {
+		err = wrapError(err)
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, fmt.Errorf("%w: drug %d does not exist", domain.ErrInvalidInput, req.DrugID)
+		}
+		return nil, err
+	}
+	if !drug.IsActive {
+		return nil, fmt.Errorf("%w: drug %s is not active", domain.ErrInvalidInput, drug.Code)
+	}
+
+	var quantity float64
+	if req.Quantity != nil {
+		quantity = *req.Quantity
+	} else {
+		var ok bool
+		if quantity, ok = domain.ComputeMedicationQuantity(drug, req.Dose, req.DoseUnit, req.Frequency, req.DurationDays); !ok {
+			return nil, fmt.Errorf("%w: quantity is required when the dose is not in %s or the frequency is %s", domain.ErrInvalidInput, drug.DispensingUnit, req.Frequency)
+		}
+	}
+
+	now := time.Now()
+	alerts, err := s.checkOrder(drug, encounter.PatientID, now, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	overrideReason := strings.TrimSpace(req.OverrideReason)
+	if len(alerts) > 0 {
+		if !req.AcknowledgeAlerts {
+			return nil, &domain.MedicationAlertError{Alerts: alerts}
+		}
+		if overrideReason == "" {
+			return nil, fmt.Errorf("%w: override_reason is required to acknowledge alerts", domain.ErrInvalidInput)
+		}
+	} else {
+		overrideReason = ""
+	}
+
+	order := &domain.MedicationOrder{
+		EncounterID:      encounter.ID,
+		PatientID:        encounter.PatientID,
+		DrugID:           drug.ID,
+		DrugName:         drug.DisplayName(),
+		TherapeuticClass: drug.TherapeuticClass,
+		Dose:             req.Dose,
+		DoseUnit:         strings.TrimSpace(req.DoseUnit),
+		Route:            req.Route,
+		Frequency:        req.Frequency,
+		DurationDays:     req.DurationDays,
+		Quantity:         quantity,
+		DispensingUnit:   drug.DispensingUnit,
+		Instruction:      strings.TrimSpace(req.Instruction),
+		Status:           domain.MedicationStatusOrdered,
+		Alerts:           alerts,
+		OverrideReason:   overrideReason,
+		OrderedAt:        now,
+		EndsAt:           now.AddDate(0, 0, req.DurationDays),
+	}
+	if actor != nil {
+		order.OrderedBy = actor.StaffID
+	}
+
+	changes := map[string]domain.FieldChange{
+		"vn":        {After: encounter.VN},
+		"drug_code": {After: drug.Code},
+		"dose":      {After: fmt.Sprintf("%g %s", order.Dose, order.DoseUnit)},
+		"frequency": {After: order.Frequency},
+		"quantity":  {After: order.Quantity},
+	}
+	event, err := domain.NewAuditEvent(actor, domain.AuditActionPatientMedicationOrder, &order.PatientID, changes)
+	if err != nil {
+		return nil, err
+	}
+	events := []*domain.AuditEvent{event}
+
+	if len(alerts) > 0 {
+		override, err := domain.NewAuditEvent(actor, domain.AuditActionPatientMedicationAlertOverride, &order.PatientID,
+			map[string]domain.FieldChange{
+				"drug_code":       {After: drug.Code},
+				"alerts":          {After: alerts.Messages()},
+				"override_reason": {After: overrideReason},
+			})
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, override)
+	}
+
+	if err := s.orderRepo.Create(order, events, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+	return order, nil
+}
+
+func (s *medicationOrderService) GetByID(id uint, actor *domain.Actor, schemaName string) (*domain.MedicationOrder, error) {
+	order, err := s.orderRepo.GetByID(id, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	if err := s.recordView(actor, order.PatientID, schemaName); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+func (s *medicationOrderService) ListByEncounter(encounterID uint, actor *domain.Actor, schemaName string) ([]domain.MedicationOrder, error) {
+	encounter, err := s.encounterRepo.GetByID(encounterID, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	orders, err := s.orderRepo.ListByEncounter(encounter.ID, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	if err := s.recordView(actor, encounter.PatientID, schemaName); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// ListByPatient returns the orders of a patient, newest first, only those still running with active_only
+func (s *medicationOrderService) ListByPatient(patientID uint, req *domain.MedicationOrderListRequest, actor *domain.Actor, schemaName string) ([]domain.MedicationOrder, error) {
+	if _, err := s.patientRepo.GetByID(patientID, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+
+	var activeAt *time.Time
+	if req.ActiveOnly {
+		now := time.Now()
+		activeAt = &now
+	}
+	orders, err := s.orderRepo.ListByPatient(patientID, activeAt, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	if err := s.recordView(actor, patientID, schemaName); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+func (s *medicationOrderService) Verify(id uint, actor *domain.Actor, schemaName string) (*domain.MedicationOrder, error) {
+	order, from, event, err := s.prepareMove(id, domain.MedicationStatusVerified, nil, actor, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.orderRepo.UpdateStatus(order, from, event, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+	return order, nil
+}
+
+// Dispense takes the order's quantity from the lots of the location that expire first, in the
+// same transaction as the status change
+func (s *medicationOrderService) Dispense(id uint, req *domain.MedicationDispenseRequest, actor *domain.Actor, schemaName string) (*domain.MedicationDispensing, error) {
+	location, err := getActiveStockLocation(s.inventoryRepo, req.LocationID, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	order, from, event, err := s.prepareMove(id, domain.MedicationStatusDispensed, func(order *domain.MedicationOrder, changes map[string]domain.FieldChange) {
+		changes["location"] = domain.FieldChange{After: location.Code}
+	}, actor, schemaName)
+	if err != nil {
+		return nil, err
+	}
+
+	today, _ := parseCalendarDay("")
+	movements, err := s.inventoryRepo.DispenseOrder(order, from, location.ID, today, event, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return &domain.MedicationDispensing{Order: order, Movements: movements}, nil
+}
+
+func (s *medicationOrderService) Cancel(id uint, req *domain.MedicationCancelRequest, actor *domain.Actor, schemaName string) (*domain.MedicationOrder, error) {
+	order, from, event, err := s.prepareMove(id, domain.MedicationStatusCancelled, func(order *domain.MedicationOrder, changes map[string]domain.FieldChange) {
+		order.CancelReason = strings.TrimSpace(req.Reason)
+		changes["cancel_reason"] = domain.FieldChange{After: order.CancelReason}
+	}, actor, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.orderRepo.UpdateStatus(order, from, event, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+	return order, nil
+}
+
+// prepareMove moves an order one step through its lifecycle in memory and builds the audit event
+// of the change, returning the status it moved from. apply sets fields of the new status and adds
+// them to the audited changes.
+func (s *medicationOrderService) prepareMove(id uint, status string, apply func(order *domain.MedicationOrder, changes map[string]domain.FieldChange), actor *domain.Actor, schemaName string) (*domain.MedicationOrder, string, *domain.AuditEvent, error) {
+	order, err := s.orderRepo.GetByID(id, schemaName)
+	if err != nil {
+		return nil, "", nil, wrapError(err)
+	}
+
+	from := order.Status
+	if !domain.CanMoveMedicationOrder(from, status) {
+		return nil, "", nil, fmt.Errorf("%w: medication order %d is %s and cannot move to %s", domain.ErrInvalidStatusTransition, order.ID, from, status)
+	}
+	var staffID uint
+	if actor != nil {
+		staffID = actor.StaffID
+	}
+	order.SetStatus(status, staffID, time.Now())
+
+	changes := map[string]domain.FieldChange{
+		"order_id": {After: order.ID},
+		"drug":     {After: order.DrugName},
+		"status":   {Before: from, After: order.Status},
+	}
+	if apply != nil {
+		apply(order, changes)
+	}
+	event, err := domain.NewAuditEvent(actor, domain.AuditActionPatientMedicationStatus, &order.PatientID, changes)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	return order, from, event, nil
+}
+
+// checkOrder raises the alerts of a new order: drug allergies of the patient, active orders of
+// the same therapeutic class and known interactions with the drugs of active orders
+func (s *medicationOrderService) checkOrder(drug *domain.Drug, patientID uint, at time.Time, schemaName string) (domain.MedicationAlerts, error) {
+	var alerts domain.MedicationAlerts
+
+	allergies, err := s.allergyRepo.ListByPatient(patientID, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	for i := range allergies {
+		allergy := &allergies[i]
+		if allergy.AllergenType != domain.AllergenTypeDrug || !drug.MatchesAllergen(allergy.Allergen) {
+			continue
+		}
+		alerts = append(alerts, domain.MedicationAlert{
+			Type:      domain.MedicationAlertAllergy,
+			Severity:  allergy.Severity,
+			Message:   fmt.Sprintf("patient has a %s %s allergy to %s", allergy.Certainty, strings.ReplaceAll(allergy.Severity, "_", "-"), allergy.Allergen),
+			AllergyID: &allergy.ID,
+		})
+	}
+
+	active, err := s.orderRepo.ListByPatient(patientID, &at, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	if len(active) == 0 {
+		return alerts, nil
+	}
+
+	ordersByDrug := make(map[uint][]*domain.MedicationOrder)
+	drugIDs := make([]uint, 0, len(active))
+	for i := range active {
+		order := &active[i]
+		if order.TherapeuticClass == drug.TherapeuticClass {
+			alerts = append(alerts, domain.MedicationAlert{
+				Type:           domain.MedicationAlertDuplicateClass,
+				Severity:       domain.InteractionSeverityModerate,
+				Message:        fmt.Sprintf("%s is in the same therapeutic class (%s) as active order %d, %s", drug.DisplayName(), drug.TherapeuticClass, order.ID, order.DrugName),
+				RelatedOrderID: &order.ID,
+			})
+		}
+		if _, seen := ordersByDrug[order.DrugID]; !seen {
+			drugIDs = append(drugIDs, order.DrugID)
+		}
+		ordersByDrug[order.DrugID] = append(ordersByDrug[order.DrugID], order)
+	}
+
+	interactions, err := s.drugRepo.ListInteractions(drug.ID, drugIDs, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	for _, interaction := range interactions {
+		for _, order := range ordersByDrug[interaction.OtherDrugID(drug.ID)] {
+			alerts = append(alerts, domain.MedicationAlert{
+				Type:           domain.MedicationAlertInteraction,
+				Severity:       interaction.Severity,
+				Message:        fmt.Sprintf("%s interacts with active order %d, %s: %s", drug.DisplayName(), order.ID, order.DrugName, interaction.Description),
+				RelatedOrderID: &order.ID,
+			})
+		}
+	}
+	return alerts, nil
+}
+
+// recordView appends a view event for the patient whose orders were shown
+func (s *medicationOrderService) recordView(actor *domain.Actor, patientID uint, schemaName string) error {
+	event, err := domain.NewAuditEvent(actor, domain.AuditActionPatientMedicationView, &patientID, nil)
+	if err != nil {
+		return err
+	}
+	if err := s.auditRepo.Append([]*domain.AuditEvent{event}, schemaName); err != nil {
+		return fmt.Errorf("failed to record audit event: %w", err)
+	}
+	return nil
+}
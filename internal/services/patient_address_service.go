@@ -0,0 +1,127 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+type patientAddressService struct {
+	addressRepo   domain.PatientAddressRepository
+	referenceRepo domain.AddressReferenceRepository
+	patientRepo   domain.PatientRepository
+	auditRepo     domain.AuditRepository
+}
+
+// NewPatientAddressService creates the service for the typed addresses of patients
+func NewPatientAddressService(addressRepo domain.PatientAddressRepository, referenceRepo domain.AddressReferenceRepository, patientRepo domain.PatientRepository, auditRepo domain.AuditRepository) domain.PatientAddressService {
+	return &patientAddressService{
+		addressRepo:   addressRepo,
+		referenceRepo: referenceRepo,
+		patientRepo:   patientRepo,
+		auditRepo:     auditRepo,
+	}
+}
+
+func (s *patientAddressService) List(patientID uint, actor *domain.Actor, schemaName string) ([]domain.PatientAddress, error) {
+	if _, err := s.patientRepo.GetByID(patientID, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+
+	addresses, err := s.addressRepo.ListByPatient(patientID, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	event, err := domain.NewAuditEvent(actor, domain.AuditActionPatientAddressView, &patientID, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.auditRepo.Append([]*domain.AuditEvent{event}, schemaName); err != nil {
+		return nil, fmt.Errorf("failed to record audit event: %w", err)
+	}
+	return addresses, nil
+}
+
+// Save creates the address of a type or replaces the current one, validating the subdistrict
+// and postal code against the reference dataset
+func (s *patientAddressService) Save(patientID uint, addressType string, req *domain.PatientAddressRequest, actor *domain.Actor, schemaName string) (*domain.PatientAddress, error) {
+	if !domain.IsValidAddressType(addressType) {
+		return nil, fmt.Errorf("%w: address type must be one of %s", domain.ErrInvalidInput, strings.Join(domain.AddressTypes, ", "))
+	}
+
+	location, err := s.referenceRepo.GetLocation(req.SubdistrictCode)
+	if err != nil {
+		err = wrapError(err)
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, fmt.Errorf("%w: unknown subdistrict %s", domain.ErrInvalidInput, req.SubdistrictCode)
+		}
+		return nil, err
+	}
+	if location.PostalCode != req.PostalCode {
+		return nil, fmt.Errorf("%w: postal code %s does not match subdistrict %s, expected %s",
+			domain.ErrInvalidInput, req.PostalCode, location.TambonTH, location.PostalCode)
+	}
+
+	if _, err := s.patientRepo.GetByID(patientID, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+
+	address, err := s.addressRepo.GetByType(patientID, addressType, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	before := map[string]interface{}{}
+	if address == nil {
+		address = &domain.PatientAddress{PatientID: patientID, AddressType: addressType}
+	} else {
+		before = domain.PatientAddressFieldValues(address)
+	}
+
+	address.HouseNo = strings.TrimSpace(req.HouseNo)
+	address.Moo = strings.TrimSpace(req.Moo)
+	address.Soi = strings.TrimSpace(req.Soi)
+	address.Road = strings.TrimSpace(req.Road)
+	address.SubdistrictCode = location.SubdistrictCode
+	address.Tambon = location.TambonTH
+	address.Amphoe = location.AmphoeTH
+	address.Changwat = location.ChangwatTH
+	address.PostalCode = location.PostalCode
+
+	changes := domain.DiffPatientFields(before, domain.PatientAddressFieldValues(address))
+	changes["address_type"] = domain.FieldChange{Before: addressType, After: addressType}
+	event, err := domain.NewAuditEvent(actor, domain.AuditActionPatientAddressSave, &patientID, changes)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.addressRepo.Save(address, event, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+	return address, nil
+}
+
+func (s *patientAddressService) Delete(patientID uint, addressType string, actor *domain.Actor, schemaName string) error {
+	address, err := s.addressRepo.GetByType(patientID, addressType, schemaName)
+	if err != nil {
+		return wrapError(err)
+	}
+	if address == nil {
+		return domain.ErrNotFound
+	}
+
+	changes := map[string]domain.FieldChange{
+		"address_type": {Before: addressType},
+	}
+	for name, value := range domain.PatientAddressFieldValues(address) {
+		changes[name] = domain.FieldChange{Before: value}
+	}
+	event, err := domain.NewAuditEvent(actor, domain.AuditActionPatientAddressDelete, &patientID, changes)
+	if err != nil {
+		return err
+	}
+
+	return wrapError(s.addressRepo.Delete(address, event, schemaName))
+}
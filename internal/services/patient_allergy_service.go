@@ -0,0 +1,125 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+type patientAllergyService struct {
+	allergyRepo domain.PatientAllergyRepository
+	patientRepo domain.PatientRepository
+	auditRepo   domain.AuditRepository
+}
+
+// NewPatientAllergyService creates the service for the allergy and adverse drug reaction registry
+func NewPatientAllergyService(allergyRepo domain.PatientAllergyRepository, patientRepo domain.PatientRepository, auditRepo domain.AuditRepository) domain.PatientAllergyService {
+	return &patientAllergyService{
+		allergyRepo: allergyRepo,
+		patientRepo: patientRepo,
+		auditRepo:   auditRepo,
+	}
+}
+
+func (s *patientAllergyService) List(patientID uint, actor *domain.Actor, schemaName string) ([]domain.PatientAllergy, error) {
+	if _, err := s.patientRepo.GetByID(patientID, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+
+	allergies, err := s.allergyRepo.ListByPatient(patientID, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	event, err := domain.NewAuditEvent(actor, domain.AuditActionPatientAllergyView, &patientID, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.auditRepo.Append([]*domain.AuditEvent{event}, schemaName); err != nil {
+		return nil, fmt.Errorf("failed to record audit event: %w", err)
+	}
+	return allergies, nil
+}
+
+func (s *patientAllergyService) Create(patientID uint, req *domain.PatientAllergyRequest, actor *domain.Actor, schemaName string) (*domain.PatientAllergy, error) {
+	if _, err := s.patientRepo.GetByID(patientID, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+
+	allergy := &domain.PatientAllergy{PatientID: patientID}
+	if actor != nil {
+		allergy.RecordedBy = actor.StaffID
+		allergy.RecordedByUsername = actor.Username
+	}
+	if err := applyPatientAllergyRequest(allergy, req); err != nil {
+		return nil, err
+	}
+
+	changes := domain.DiffPatientFields(map[string]interface{}{}, domain.PatientAllergyFieldValues(allergy))
+	event, err := domain.NewAuditEvent(actor, domain.AuditActionPatientAllergyCreate, &patientID, changes)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.allergyRepo.Create(allergy, event, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+	return allergy, nil
+}
+
+func (s *patientAllergyService) Update(patientID uint, id uint, req *domain.PatientAllergyRequest, actor *domain.Actor, schemaName string) (*domain.PatientAllergy, error) {
+	allergy, err := s.allergyRepo.GetByID(patientID, id, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	before := domain.PatientAllergyFieldValues(allergy)
+	if err := applyPatientAllergyRequest(allergy, req); err != nil {
+		return nil, err
+	}
+	changes := domain.DiffPatientFields(before, domain.PatientAllergyFieldValues(allergy))
+
+	event, err := domain.NewAuditEvent(actor, domain.AuditActionPatientAllergyUpdate, &patientID, changes)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.allergyRepo.Update(allergy, event, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+	return allergy, nil
+}
+
+// Delete removes an allergy entered in error; the audit event keeps what it said
+func (s *patientAllergyService) Delete(patientID uint, id uint, actor *domain.Actor, schemaName string) error {
+	allergy, err := s.allergyRepo.GetByID(patientID, id, schemaName)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	changes := make(map[string]domain.FieldChange)
+	for name, value := range domain.PatientAllergyFieldValues(allergy) {
+		changes[name] = domain.FieldChange{Before: value}
+	}
+	event, err := domain.NewAuditEvent(actor, domain.AuditActionPatientAllergyDelete, &patientID, changes)
+	if err != nil {
+		return err
+	}
+
+	return wrapError(s.allergyRepo.Delete(allergy, event, schemaName))
+}
+
+func applyPatientAllergyRequest(allergy *domain.PatientAllergy, req *domain.PatientAllergyRequest) error {
+	allergen := strings.TrimSpace(req.Allergen)
+	if allergen == "" {
+		return fmt.Errorf("%w: allergen is required", domain.ErrInvalidInput)
+	}
+	allergy.AllergenType = req.AllergenType
+	allergy.Allergen = allergen
+	allergy.Reaction = strings.TrimSpace(req.Reaction)
+	allergy.Severity = req.Severity
+	allergy.Certainty = req.Certainty
+	allergy.Note = strings.TrimSpace(req.Note)
+	return nil
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+type patientContactService struct {
+	contactRepo domain.PatientContactRepository
+	patientRepo domain.PatientRepository
+	auditRepo   domain.AuditRepository
+}
+
+// NewPatientContactService creates the service for next of kin and emergency contacts
+func NewPatientContactService(contactRepo domain.PatientContactRepository, patientRepo domain.PatientRepository, auditRepo domain.AuditRepository) domain.PatientContactService {
+	return &patientContactService{
+		contactRepo: contactRepo,
+		patientRepo: patientRepo,
+		auditRepo:   auditRepo,
+	}
+}
+
+func (s *patientContactService) List(patientID uint, actor *domain.Actor, schemaName string) ([]domain.PatientContact, error) {
+	if _, err := s.patientRepo.GetByID(patientID, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+
+	contacts, err := s.contactRepo.ListByPatient(patientID, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	// Contacts are part of the patient record, so reading them is audited like a view
+	event, err := domain.NewAuditEvent(actor, domain.AuditActionPatientContactView, &patientID, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.auditRepo.Append([]*domain.AuditEvent{event}, schemaName); err != nil {
+		return nil, fmt.Errorf("failed to record audit event: %w", err)
+	}
+	return contacts, nil
+}
+
+// Create adds a contact; the first contact of a patient becomes the primary contact
+func (s *patientContactService) Create(patientID uint, req *domain.PatientContactRequest, actor *domain.Actor, schemaName string) (*domain.PatientContact, error) {
+	if _, err := s.patientRepo.GetByID(patientID, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+
+	contact := &domain.PatientContact{PatientID: patientID}
+	applyPatientContactRequest(contact, req)
+
+	if !contact.IsPrimary {
+		primary, err := s.contactRepo.GetPrimary(patientID, schemaName)
+		if err != nil {
+			return nil, wrapError(err)
+		}
+		contact.IsPrimary = primary == nil
+	}
+
+	changes := domain.DiffPatientFields(map[string]interface{}{}, domain.PatientContactFieldValues(contact))
+	event, err := domain.NewAuditEvent(actor, domain.AuditActionPatientContactCreate, &patientID, changes)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.contactRepo.Create(contact, event, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+	return contact, nil
+}
+
+func (s *patientContactService) Update(patientID uint, id uint, req *domain.PatientContactRequest, actor *domain.Actor, schemaName string) (*domain.PatientContact, error) {
+	contact, err := s.contactRepo.GetByID(patientID, id, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	before := domain.PatientContactFieldValues(contact)
+	applyPatientContactRequest(contact, req)
+	changes := domain.DiffPatientFields(before, domain.PatientContactFieldValues(contact))
+
+	event, err := domain.NewAuditEvent(actor, domain.AuditActionPatientContactUpdate, &patientID, changes)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.contactRepo.Update(contact, event, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+	return contact, nil
+}
+
+// Delete removes a contact; deleting the primary contact leaves the patient without one
+func (s *patientContactService) Delete(patientID uint, id uint, actor *domain.Actor, schemaName string) error {
+	contact, err := s.contactRepo.GetByID(patientID, id, schemaName)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	changes := make(map[string]domain.FieldChange)
+	for name, value := range domain.PatientContactFieldValues(contact) {
+		changes[name] = domain.FieldChange{Before: value}
+	}
+	event, err := domain.NewAuditEvent(actor, domain.AuditActionPatientContactDelete, &patientID, changes)
+	if err != nil {
+		return err
+	}
+
+	return wrapError(s.contactRepo.Delete(contact, event, schemaName))
+}
+
+func applyPatientContactRequest(contact *domain.PatientContact, req *domain.PatientContactRequest) {
+	contact.Name = strings.TrimSpace(req.Name)
+	contact.Relationship = req.Relationship
+	contact.PhoneNumber = strings.TrimSpace(req.PhoneNumber)
+	contact.AltPhoneNumber = strings.TrimSpace(req.AltPhoneNumber)
+	contact.Address = strings.TrimSpace(req.Address)
+	contact.IsPrimary = req.IsPrimary
+}
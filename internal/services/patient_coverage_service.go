@@ -0,0 +1,281 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+type patientCoverageService struct {
+	coverageRepo       domain.PatientCoverageRepository
+	patientRepo        domain.PatientRepository
+	auditRepo          domain.AuditRepository
+	eligibilityChecker domain.EligibilityChecker
+}
+
+// NewPatientCoverageService creates the service for patient payer coverage. Eligibility checks
+// go through eligibilityChecker, so the NHSO service can be replaced by a fake in development.
+func NewPatientCoverageService(coverageRepo domain.PatientCoverageRepository, patientRepo domain.PatientRepository, auditRepo domain.AuditRepository, eligibilityChecker domain.EligibilityChecker) domain.PatientCoverageService {
+	return &patientCoverageService{
+		coverageRepo:       coverageRepo,
+		patientRepo:        patientRepo,
+		auditRepo:          auditRepo,
+		eligibilityChecker: eligibilityChecker,
+	}
+}
+
+func (s *patientCoverageService) List(patientID uint, actor *domain.Actor, schemaName string) ([]domain.PatientCoverage, error) {
+	if _, err := s.patientRepo.GetByID(patientID, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+
+	coverages, err := s.coverageRepo.ListByPatient(patientID, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	if err := s.recordView(patientID, nil, actor, schemaName); err != nil {
+		return nil, err
+	}
+	return coverages, nil
+}
+
+func (s *patientCoverageService) Active(patientID uint, date string, actor *domain.Actor, schemaName string) ([]domain.PatientCoverage, error) {
+	on, err := parseCalendarDay(date)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.patientRepo.GetByID(patientID, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+
+	coverages, err := s.coverageRepo.ListActive(patientID, on, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	changes := map[string]domain.FieldChange{"active_on": {After: on.Format(domain.DateFormat)}}
+	if err := s.recordView(patientID, changes, actor, schemaName); err != nil {
+		return nil, err
+	}
+	return coverages, nil
+}
+
+func (s *patientCoverageService) Create(patientID uint, req *domain.PatientCoverageRequest, actor *domain.Actor, schemaName string) (*domain.PatientCoverage, error) {
+	if _, err := s.patientRepo.GetByID(patientID, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+
+	coverage := &domain.PatientCoverage{PatientID: patientID}
+	if err := applyPatientCoverageRequest(coverage, req); err != nil {
+		return nil, err
+	}
+	if err := s.checkOverlap(coverage, schemaName); err != nil {
+		return nil, err
+	}
+
+	changes := domain.DiffPatientFields(map[string]interface{}{}, domain.PatientCoverageFieldValues(coverage))
+	event, err := domain.NewAuditEvent(actor, domain.AuditActionPatientCoverageCreate, &patientID, changes)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.coverageRepo.Create(coverage, event, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+	return coverage, nil
+}
+
+// Update replaces a coverage. Any change clears the last eligibility check, which was made
+// for the coverage as it was.
+func (s *patientCoverageService) Update(patientID uint, id uint, req *domain.PatientCoverageRequest, actor *domain.Actor, schemaName string) (*domain.PatientCoverage, error) {
+	coverage, err := s.coverageRepo.GetByID(patientID, id, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	before := domain.PatientCoverageFieldValues(coverage)
+	if err := applyPatientCoverageRequest(coverage, req); err != nil {
+		return nil, err
+	}
+	if err := s.checkOverlap(coverage, schemaName); err != nil {
+		return nil, err
+	}
+	after := domain.PatientCoverageFieldValues(coverage)
+	if len(domain.DiffPatientFields(before, after)) > 0 {
+		coverage.EligibilityStatus = ""
+		coverage.EligibilityMessage = ""
+		coverage.EligibilityCheckedAt = nil
+		after = domain.PatientCoverageFieldValues(coverage)
+	}
+	changes := domain.DiffPatientFields(before, after)
+
+	event, err := domain.NewAuditEvent(actor, domain.AuditActionPatientCoverageUpdate, &patientID, changes)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.coverageRepo.Update(coverage, event, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+	return coverage, nil
+}
+
+// Delete removes a coverage entered in error; the audit event keeps what it said
+func (s *patientCoverageService) Delete(patientID uint, id uint, actor *domain.Actor, schemaName string) error {
+	coverage, err := s.coverageRepo.GetByID(patientID, id, schemaName)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	changes := make(map[string]domain.FieldChange)
+	for name, value := range domain.PatientCoverageFieldValues(coverage) {
+		changes[name] = domain.FieldChange{Before: value}
+	}
+	event, err := domain.NewAuditEvent(actor, domain.AuditActionPatientCoverageDelete, &patientID, changes)
+	if err != nil {
+		return err
+	}
+
+	return wrapError(s.coverageRepo.Delete(coverage, event, schemaName))
+}
+
+func (s *patientCoverageService) CheckEligibility(patientID uint, id uint, date string, actor *domain.Actor, schemaName string) (*domain.EligibilityResult, error) {
+	on, err := parseCalendarDay(date)
+	if err != nil {
+		return nil, err
+	}
+
+	patient, err := s.patientRepo.GetByID(patientID, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	coverage, err := s.coverageRepo.GetByID(patientID, id, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	if coverage.Scheme == domain.CoverageSchemeSelfPay {
+		return nil, fmt.Errorf("%w: self-pay coverage has no payer to check", domain.ErrInvalidInput)
+	}
+	// Payers identify people by national ID; patients registered by passport cannot be checked
+	if patient.NationalID == "" {
+		return nil, fmt.Errorf("%w: eligibility check requires the patient's national ID", domain.ErrInvalidInput)
+	}
+
+	result, err := s.eligibilityChecker.Check(&domain.EligibilityQuery{
+		NationalID: string(patient.NationalID),
+		Scheme:     coverage.Scheme,
+		On:         on,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrEligibilityUnavailable, err)
+	}
+
+	before := domain.PatientCoverageFieldValues(coverage)
+	coverage.EligibilityStatus = domain.EligibilityStatusNotEligible
+	if result.Eligible {
+		coverage.EligibilityStatus = domain.EligibilityStatusEligible
+	}
+	coverage.EligibilityMessage = result.Message
+	checkedAt := result.CheckedAt
+	coverage.EligibilityCheckedAt = &checkedAt
+
+	changes := domain.DiffPatientFields(before, domain.PatientCoverageFieldValues(coverage))
+	changes["checked_on"] = domain.FieldChange{After: on.Format(domain.DateFormat)}
+	changes["source"] = domain.FieldChange{After: result.Source}
+	event, err := domain.NewAuditEvent(actor, domain.AuditActionPatientCoverageEligibility, &patientID, changes)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.coverageRepo.Update(coverage, event, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+	return result, nil
+}
+
+func (s *patientCoverageService) recordView(patientID uint, changes map[string]domain.FieldChange, actor *domain.Actor, schemaName string) error {
+	event, err := domain.NewAuditEvent(actor, domain.AuditActionPatientCoverageView, &patientID, changes)
+	if err != nil {
+		return err
+	}
+	if err := s.auditRepo.Append([]*domain.AuditEvent{event}, schemaName); err != nil {
+		return fmt.Errorf("failed to record audit event: %w", err)
+	}
+	return nil
+}
+
+// checkOverlap rejects a coverage whose period overlaps another coverage of the same scheme
+func (s *patientCoverageService) checkOverlap(coverage *domain.PatientCoverage, schemaName string) error {
+	existing, err := s.coverageRepo.ListByPatient(coverage.PatientID, schemaName)
+	if err != nil {
+		return wrapError(err)
+	}
+	for _, other := range existing {
+		if other.ID == coverage.ID || other.Scheme != coverage.Scheme {
+			continue
+		}
+		startsBeforeOtherEnds := other.ValidTo == nil || !coverage.ValidFrom.After(*other.ValidTo)
+		endsAfterOtherStarts := coverage.ValidTo == nil || !coverage.ValidTo.Before(other.ValidFrom)
+		if startsBeforeOtherEnds && endsAfterOtherStarts {
+			return fmt.Errorf("%w: patient already has %s coverage from %s", domain.ErrDuplicateEntry, coverage.Scheme, other.ValidFrom.Format(domain.DateFormat))
+		}
+	}
+	return nil
+}
+
+func applyPatientCoverageRequest(coverage *domain.PatientCoverage, req *domain.PatientCoverageRequest) error {
+	validFrom, err := time.Parse(domain.DateFormat, req.ValidFrom)
+	if err != nil {
+		return fmt.Errorf("%w: valid_from must be in YYYY-MM-DD format", domain.ErrInvalidInput)
+	}
+	var validTo *time.Time
+	if req.ValidTo != "" {
+		parsed, err := time.Parse(domain.DateFormat, req.ValidTo)
+		if err != nil {
+			return fmt.Errorf("%w: valid_to must be in YYYY-MM-DD format", domain.ErrInvalidInput)
+		}
+		if parsed.Before(validFrom) {
+			return fmt.Errorf("%w: valid_to must not be before valid_from", domain.ErrInvalidInput)
+		}
+		validTo = &parsed
+	}
+
+	mainHospitalCode := strings.TrimSpace(req.MainHospitalCode)
+	policyNumber := strings.TrimSpace(req.PolicyNumber)
+	switch req.Scheme {
+	case domain.CoverageSchemeUC, domain.CoverageSchemeSSS:
+		if mainHospitalCode == "" {
+			return fmt.Errorf("%w: main_hospital_code is required for %s coverage", domain.ErrInvalidInput, req.Scheme)
+		}
+	case domain.CoverageSchemePrivate:
+		if policyNumber == "" {
+			return fmt.Errorf("%w: policy_number is required for private insurance", domain.ErrInvalidInput)
+		}
+	}
+
+	coverage.Scheme = req.Scheme
+	coverage.MainHospitalCode = mainHospitalCode
+	coverage.MainHospitalName = strings.TrimSpace(req.MainHospitalName)
+	coverage.PolicyNumber = policyNumber
+	coverage.ValidFrom = validFrom
+	coverage.ValidTo = validTo
+	return nil
+}
+
+// parseCalendarDay parses a YYYY-MM-DD date, defaulting to today in Thai time
+func parseCalendarDay(date string) (time.Time, error) {
+	if date == "" {
+		now := time.Now().In(domain.HNTimeZone)
+		return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC), nil
+	}
+	on, err := time.Parse(domain.DateFormat, date)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: date must be in YYYY-MM-DD format", domain.ErrInvalidInput)
+	}
+	return on, nil
+}
@@ -2,6 +2,8 @@ package services
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -11,12 +13,14 @@ import (
 
 type patientService struct {
 	patientRepo   domain.PatientRepository
+	auditRepo     domain.AuditRepository
 	tenantService domain.TenantService
 }
 
-func NewPatientService(patientRepo domain.PatientRepository, tenantService domain.TenantService) domain.PatientService {
+func NewPatientService(patientRepo domain.PatientRepository, auditRepo domain.AuditRepository, tenantService domain.TenantService) domain.PatientService {
 	return &patientService{
 		patientRepo:   patientRepo,
+		auditRepo:     auditRepo,
 		tenantService: tenantService,
 	}
 }
@@ -59,44 +63,269 @@ func wrapError(err error) error {
 		return domain.ErrNotFound
 	}
 
-	// Check for duplicate key errors (PostgreSQL)
+	// Check for duplicate key and exclusion constraint errors (PostgreSQL)
 	errStr := err.Error()
-	if strings.Contains(errStr, "duplicate key") || strings.Contains(errStr, "unique constraint") {
+	if strings.Contains(errStr, "duplicate key") || strings.Contains(errStr, "unique constraint") ||
+		strings.Contains(errStr, "exclusion constraint") {
 		return fmt.Errorf("%w: %s", domain.ErrDuplicateEntry, errStr)
 	}
 
 	return err
 }
 
-func (s *patientService) Search(query string, schemaName string) ([]domain.Patient, error) {
-	patients, err := s.patientRepo.Search(query, schemaName)
+// requireIdentifier checks a patient keeps a national ID or a passport number,
+// since blank identifiers are stored as NULL and no longer identify anyone
+func requireIdentifier(nationalID domain.NullableString, passportID domain.NullableString) error {
+	if nationalID == "" && passportID == "" {
+		return fmt.Errorf("%w: national_id or passport_id is required", domain.ErrInvalidInput)
+	}
+	return nil
+}
+
+// recordAccess appends read events for the given patients.
+// Reads fail closed: if the access cannot be audited the records are not returned.
+func (s *patientService) recordAccess(actor *domain.Actor, action string, patients []domain.Patient, schemaName string) error {
+	events := make([]*domain.AuditEvent, 0, len(patients))
+	for i := range patients {
+		event, err := domain.NewAuditEvent(actor, action, &patients[i].ID, nil)
+		if err != nil {
+			return err
+		}
+		events = append(events, event)
+	}
+
+	if err := s.auditRepo.Append(events, schemaName); err != nil {
+		return fmt.Errorf("failed to record audit event: %w", err)
+	}
+	return nil
+}
+
+// Search returns one page of patients matching the request
+func (s *patientService) Search(req *domain.PatientSearchRequest, actor *domain.Actor, schemaName string) (*domain.PatientSearchResult, error) {
+	filter, err := buildPatientSearchFilter(req)
+	if err != nil {
+		return nil, err
+	}
+	limit := filter.Limit
+
+	// Fetch one extra row to find out whether there is a next page
+	filter.Limit = limit + 1
+
+	var items []domain.PatientSearchItem
+	var total int64
+	if filter.Fuzzy {
+		items, total, err = s.patientRepo.FuzzySearch(filter, schemaName)
+	} else {
+		var patients []domain.Patient
+		patients, total, err = s.patientRepo.Search(filter, schemaName)
+		items = make([]domain.PatientSearchItem, len(patients))
+		for i := range patients {
+			items[i].Patient = patients[i]
+		}
+	}
 	if err != nil {
 		return nil, wrapError(err)
 	}
-	return patients, nil
+
+	result := &domain.PatientSearchResult{Total: total, Limit: limit}
+	if len(items) > limit {
+		items = items[:limit]
+		last := &items[limit-1]
+		cursor := &domain.PatientCursor{
+			Sort:  sortKey(filter),
+			Value: searchItemSortValue(last, filter),
+			ID:    last.ID,
+		}
+		result.NextCursor = cursor.Encode()
+	}
+	result.Patients = items
+
+	patients := make([]domain.Patient, len(items))
+	for i := range items {
+		patients[i] = items[i].Patient
+	}
+	if err := s.recordAccess(actor, domain.AuditActionPatientSearch, patients, schemaName); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// buildPatientSearchFilter validates the search request and converts it to a repository filter
+func buildPatientSearchFilter(req *domain.PatientSearchRequest) (*domain.PatientSearchFilter, error) {
+	filter := &domain.PatientSearchFilter{
+		Query:       strings.TrimSpace(req.Query),
+		Gender:      req.Gender,
+		BloodGrp:    req.BloodGrp,
+		Nationality: strings.TrimSpace(req.Nationality),
+		Limit:       req.Limit,
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = domain.DefaultPatientSearchLimit
+	}
+	if filter.Limit > domain.MaxPatientSearchLimit {
+		return nil, fmt.Errorf("%w: limit must not exceed %d", domain.ErrInvalidInput, domain.MaxPatientSearchLimit)
+	}
+
+	if req.Mode == domain.PatientSearchFuzzy {
+		// Fuzzy results are always ranked by score, best match first
+		if filter.Query == "" {
+			return nil, fmt.Errorf("%w: fuzzy search requires a query", domain.ErrInvalidInput)
+		}
+		if req.Sort != "" {
+			return nil, fmt.Errorf("%w: fuzzy search is always sorted by score", domain.ErrInvalidInput)
+		}
+		filter.Fuzzy = true
+		filter.SortDesc = true
+	} else {
+		sort := req.Sort
+		if sort == "" {
+			sort = domain.DefaultPatientSort
+		}
+		column, ok := domain.PatientSortColumns[strings.TrimPrefix(sort, "-")]
+		if !ok {
+			return nil, fmt.Errorf("%w: unsupported sort %q", domain.ErrInvalidInput, sort)
+		}
+		filter.SortColumn = column
+		filter.SortDesc = strings.HasPrefix(sort, "-")
+	}
+
+	var err error
+	if filter.DOBFrom, filter.DOBTo, err = parseDateRange(req.DOBFrom, req.DOBTo); err != nil {
+		return nil, err
+	}
+	if filter.CreatedFrom, filter.CreatedTo, err = parseDateRange(req.CreatedFrom, req.CreatedTo); err != nil {
+		return nil, err
+	}
+
+	if req.Cursor != "" {
+		cursor, err := domain.DecodePatientCursor(req.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		// A cursor is only meaningful for the sort order it was created with
+		if cursor.Sort != sortKey(filter) {
+			return nil, fmt.Errorf("%w: cursor does not match sort", domain.ErrInvalidInput)
+		}
+		if filter.AfterValue, err = parseSortValue(cursor.Value, filter); err != nil {
+			return nil, err
+		}
+		filter.AfterID = cursor.ID
+	}
+
+	return filter, nil
+}
+
+// parseDateRange parses an inclusive YYYY-MM-DD range into a half-open time range
+func parseDateRange(from string, to string) (*time.Time, *time.Time, error) {
+	var fromTime, toTime *time.Time
+	if from != "" {
+		parsed, err := time.Parse(domain.DateFormat, from)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: expected format YYYY-MM-DD", domain.ErrInvalidDateFormat)
+		}
+		fromTime = &parsed
+	}
+	if to != "" {
+		parsed, err := time.Parse(domain.DateFormat, to)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: expected format YYYY-MM-DD", domain.ErrInvalidDateFormat)
+		}
+		parsed = parsed.AddDate(0, 0, 1)
+		toTime = &parsed
+	}
+	if fromTime != nil && toTime != nil && !fromTime.Before(*toTime) {
+		return nil, nil, fmt.Errorf("%w: date range start is after its end", domain.ErrInvalidInput)
+	}
+	return fromTime, toTime, nil
+}
+
+// sortKey returns the normalised sort of the filter, used to bind cursors to their sort order
+func sortKey(filter *domain.PatientSearchFilter) string {
+	if filter.Fuzzy {
+		return domain.FuzzySortKey
+	}
+	if filter.SortDesc {
+		return "-" + filter.SortColumn
+	}
+	return filter.SortColumn
+}
+
+// searchItemSortValue returns the value of the sort key of a search result for use in a cursor
+func searchItemSortValue(item *domain.PatientSearchItem, filter *domain.PatientSearchFilter) string {
+	if filter.Fuzzy {
+		if item.Score == nil {
+			return "0"
+		}
+		return strconv.FormatFloat(*item.Score, 'g', -1, 64)
+	}
+
+	p := &item.Patient
+	switch filter.SortColumn {
+	case "created_at":
+		return p.CreatedAt.UTC().Format(time.RFC3339Nano)
+	case "date_of_birth":
+		return p.DateOfBirth.UTC().Format(time.RFC3339Nano)
+	case "patient_hn":
+		return p.PatientHN
+	case "first_name_th":
+		return p.FirstNameTH
+	case "last_name_th":
+		return p.LastNameTH
+	case "first_name_en":
+		return p.FirstNameEN
+	case "last_name_en":
+		return p.LastNameEN
+	default:
+		return ""
+	}
+}
+
+// parseSortValue converts a cursor value back to the type of the sort key
+func parseSortValue(value string, filter *domain.PatientSearchFilter) (interface{}, error) {
+	if filter.Fuzzy {
+		score, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid cursor", domain.ErrInvalidInput)
+		}
+		return score, nil
+	}
+
+	column := filter.SortColumn
+	if column == "created_at" || column == "date_of_birth" {
+		parsed, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid cursor", domain.ErrInvalidInput)
+		}
+		return parsed, nil
+	}
+	return value, nil
 }
 
-func (s *patientService) SearchByID(id uint, schemaName string) (*domain.Patient, error) {
+func (s *patientService) SearchByID(id uint, actor *domain.Actor, schemaName string) (*domain.Patient, error) {
 	patient, err := s.patientRepo.SearchByID(id, schemaName)
 	if err != nil {
 		return nil, wrapError(err)
 	}
+
+	if err := s.recordAccess(actor, domain.AuditActionPatientView, []domain.Patient{*patient}, schemaName); err != nil {
+		return nil, err
+	}
 	return patient, nil
 }
 
 // Create a new patient
-func (s *patientService) Create(req *domain.PatientCreateRequest, schemaName string) (*domain.Patient, error) {
+func (s *patientService) Create(req *domain.PatientCreateRequest, actor *domain.Actor, schemaName string) (*domain.Patient, error) {
 	// Validate and parse date of birth
 	dob, err := parseDateOfBirth(req.DateOfBirth)
 	if err != nil {
 		return nil, err
 	}
 
-	// Generate HN using tenant's hospital code and running number
-	// Format: hospitalCode-HNRunning (e.g., "HOSP0001-00000001")
-	hn, err := s.tenantService.GenerateHN(schemaName)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate HN: %w", err)
+	nationalID := domain.NullableString(strings.TrimSpace(req.NationalID))
+	passportID := domain.NullableString(domain.NormalizePassportID(req.PassportID))
+	if err := requireIdentifier(nationalID, passportID); err != nil {
+		return nil, err
 	}
 
 	patient := &domain.Patient{
@@ -109,9 +338,8 @@ func (s *patientService) Create(req *domain.PatientCreateRequest, schemaName str
 		DateOfBirth:  dob,
 		NickNameTH:   strings.TrimSpace(req.NickNameTH),
 		NickNameEN:   strings.TrimSpace(req.NickNameEN),
-		PatientHN:    hn,
-		NationalID:   strings.TrimSpace(req.NationalID),
-		PassportID:   strings.TrimSpace(req.PassportID),
+		NationalID:   nationalID,
+		PassportID:   passportID,
 		PhoneNumber:  strings.TrimSpace(req.PhoneNumber),
 		Email:        strings.TrimSpace(req.Email),
 		Gender:       req.Gender,
@@ -119,19 +347,89 @@ func (s *patientService) Create(req *domain.PatientCreateRequest, schemaName str
 		BloodGrp:     domain.BloodGrp(req.BloodGrp),
 	}
 
-	if err := s.patientRepo.Create(patient, schemaName); err != nil {
+	candidates, err := s.findDuplicateCandidates(patient, schemaName)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(candidates) > 0 && !req.Force {
+		// Returning candidates discloses their records, so the lookup is audited like a search
+		matched := make([]domain.Patient, len(candidates))
+		for i := range candidates {
+			matched[i] = candidates[i].Patient
+		}
+		if err := s.recordAccess(actor, domain.AuditActionPatientDuplicateCheck, matched, schemaName); err != nil {
+			return nil, err
+		}
+		return nil, &domain.DuplicatePatientError{Candidates: candidates}
+	}
+
+	// The repository issues the HN from the tenant's template when it inserts the patient
+	issuer, err := s.tenantService.GetHNIssuer(schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load HN template: %w", err)
+	}
+
+	// The repository fills in the patient ID once the row is inserted
+	event, err := domain.NewAuditEvent(actor, domain.AuditActionPatientCreate, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	events := []*domain.AuditEvent{event}
+
+	if len(candidates) > 0 {
+		hns := make([]string, len(candidates))
+		for i := range candidates {
+			hns[i] = candidates[i].Patient.PatientHN
+		}
+		override, err := domain.NewAuditEvent(actor, domain.AuditActionPatientDuplicateOverride, nil,
+			map[string]domain.FieldChange{"duplicate_candidates": {After: hns}})
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, override)
+	}
+
+	if err := s.patientRepo.Create(patient, issuer, events, schemaName); err != nil {
 		return nil, wrapError(err)
 	}
 
 	return patient, nil
 }
 
+// findDuplicateCandidates returns the existing patients scoring at or above the duplicate threshold, best first
+func (s *patientService) findDuplicateCandidates(probe *domain.Patient, schemaName string) ([]domain.DuplicateCandidate, error) {
+	items, err := s.patientRepo.FindDuplicateCandidates(probe, domain.DuplicateCandidateScanLimit, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	candidates := make([]domain.DuplicateCandidate, 0, len(items))
+	for i := range items {
+		candidate := domain.ScoreDuplicateCandidate(probe, &items[i])
+		if candidate.Score >= domain.DuplicateScoreThreshold {
+			candidates = append(candidates, candidate)
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+	if len(candidates) > domain.MaxDuplicateCandidates {
+		candidates = candidates[:domain.MaxDuplicateCandidates]
+	}
+	return candidates, nil
+}
+
 // Update performs a full update (PUT) - replaces all fields
-func (s *patientService) Update(id uint, req *domain.PatientUpdateRequest, schemaName string) (*domain.Patient, error) {
+func (s *patientService) Update(id uint, req *domain.PatientUpdateRequest, version uint, actor *domain.Actor, schemaName string) (*domain.Patient, error) {
 	patient, err := s.patientRepo.GetByID(id, schemaName)
 	if err != nil {
 		return nil, wrapError(err)
 	}
+	if patient.Version != version {
+		return nil, domain.ErrPreconditionFailed
+	}
 
 	// Validate and parse date of birth
 	dob, err := parseDateOfBirth(req.DateOfBirth)
@@ -139,6 +437,8 @@ func (s *patientService) Update(id uint, req *domain.PatientUpdateRequest, schem
 		return nil, err
 	}
 
+	before := domain.PatientFieldValues(patient)
+
 	// Replace all fields (full update)
 	patient.FirstNameTH = strings.TrimSpace(req.FirstNameTH)
 	patient.LastNameTH = strings.TrimSpace(req.LastNameTH)
@@ -149,15 +449,24 @@ func (s *patientService) Update(id uint, req *domain.PatientUpdateRequest, schem
 	patient.DateOfBirth = dob
 	patient.NickNameTH = strings.TrimSpace(req.NickNameTH)
 	patient.NickNameEN = strings.TrimSpace(req.NickNameEN)
-	patient.NationalID = strings.TrimSpace(req.NationalID)
-	patient.PassportID = strings.TrimSpace(req.PassportID)
+	patient.NationalID = domain.NullableString(strings.TrimSpace(req.NationalID))
+	patient.PassportID = domain.NullableString(domain.NormalizePassportID(req.PassportID))
 	patient.PhoneNumber = strings.TrimSpace(req.PhoneNumber)
 	patient.Email = strings.TrimSpace(req.Email)
 	patient.Gender = domain.Gender(req.Gender)
 	patient.Nationality = strings.TrimSpace(req.Nationality)
 	patient.BloodGrp = domain.BloodGrp(req.BloodGrp)
+	if err := requireIdentifier(patient.NationalID, patient.PassportID); err != nil {
+		return nil, err
+	}
+
+	changes := domain.DiffPatientFields(before, domain.PatientFieldValues(patient))
+	event, err := domain.NewAuditEvent(actor, domain.AuditActionPatientUpdate, &patient.ID, changes)
+	if err != nil {
+		return nil, err
+	}
 
-	if err := s.patientRepo.Update(patient, schemaName); err != nil {
+	if err := s.patientRepo.Update(patient, event, schemaName); err != nil {
 		return nil, wrapError(err)
 	}
 
@@ -165,29 +474,59 @@ func (s *patientService) Update(id uint, req *domain.PatientUpdateRequest, schem
 }
 
 // PartialUpdate performs a partial update (PATCH) - only updates provided fields
-func (s *patientService) PartialUpdate(id uint, req *domain.PatientPartialUpdateRequest, schemaName string) (*domain.Patient, error) {
+func (s *patientService) PartialUpdate(id uint, req *domain.PatientPartialUpdateRequest, version uint, actor *domain.Actor, schemaName string) (*domain.Patient, error) {
 	// Convert request to update map (validates date if present)
 	updates, err := req.ToMap()
 	if err != nil {
 		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidDateFormat, err)
 	}
 
-	// Skip database call if there's nothing to update
+	// Load the current record so the audit event can carry before values
+	patient, err := s.patientRepo.GetByID(id, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	if patient.Version != version {
+		return nil, domain.ErrPreconditionFailed
+	}
+
+	// Skip the write if there's nothing to update, the request is then only a read
 	if len(updates) == 0 {
-		patient, err := s.patientRepo.GetByID(id, schemaName)
-		if err != nil {
-			return nil, wrapError(err)
+		if err := s.recordAccess(actor, domain.AuditActionPatientView, []domain.Patient{*patient}, schemaName); err != nil {
+			return nil, err
 		}
 		return patient, nil
 	}
 
+	// Clearing one identifier is only allowed while the other is kept. Patches that leave both
+	// alone are not checked, so legacy records without an identifier stay editable.
+	nationalID, nationalTouched := updates["national_id"]
+	passportID, passportTouched := updates["passport_id"]
+	if nationalTouched || passportTouched {
+		if !nationalTouched {
+			nationalID = patient.NationalID
+		}
+		if !passportTouched {
+			passportID = patient.PassportID
+		}
+		if err := requireIdentifier(nationalID.(domain.NullableString), passportID.(domain.NullableString)); err != nil {
+			return nil, err
+		}
+	}
+
+	changes := domain.DiffPatientFields(domain.PatientFieldValues(patient), updates)
+	event, err := domain.NewAuditEvent(actor, domain.AuditActionPatientPartialUpdate, &patient.ID, changes)
+	if err != nil {
+		return nil, err
+	}
+
 	// Perform the partial update - repository will handle record existence check
-	if err := s.patientRepo.PartialUpdate(id, updates, schemaName); err != nil {
+	if err := s.patientRepo.PartialUpdate(id, version, updates, event, schemaName); err != nil {
 		return nil, wrapError(err)
 	}
 
 	// Fetch the updated patient
-	patient, err := s.patientRepo.GetByID(id, schemaName)
+	patient, err = s.patientRepo.GetByID(id, schemaName)
 	if err != nil {
 		return nil, wrapError(err)
 	}
@@ -195,15 +534,167 @@ func (s *patientService) PartialUpdate(id uint, req *domain.PatientPartialUpdate
 	return patient, nil
 }
 
-func (s *patientService) Delete(id uint, schemaName string) error {
+func (s *patientService) Delete(id uint, actor *domain.Actor, schemaName string) error {
 	// First check if patient exists
 	_, err := s.patientRepo.GetByID(id, schemaName)
 	if err != nil {
 		return wrapError(err)
 	}
 
-	if err := s.patientRepo.Delete(id, schemaName); err != nil {
+	event, err := domain.NewAuditEvent(actor, domain.AuditActionPatientDelete, &id, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := s.patientRepo.Delete(id, event, schemaName); err != nil {
 		return wrapError(err)
 	}
 	return nil
 }
+
+// Merge retires a duplicate patient into the surviving record. The retired HN stays
+// searchable as an alias of the survivor until the merge is reversed.
+func (s *patientService) Merge(req *domain.PatientMergeRequest, actor *domain.Actor, schemaName string) (*domain.PatientMerge, error) {
+	if req.SurvivorID == req.RetiredID {
+		return nil, fmt.Errorf("%w: a patient cannot be merged into itself", domain.ErrInvalidInput)
+	}
+
+	survivor, err := s.patientRepo.GetByID(req.SurvivorID, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	retired, err := s.patientRepo.GetByID(req.RetiredID, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	merge := &domain.PatientMerge{
+		SurvivorID: survivor.ID,
+		SurvivorHN: survivor.PatientHN,
+		RetiredID:  retired.ID,
+		RetiredHN:  retired.PatientHN,
+		Reason:     strings.TrimSpace(req.Reason),
+	}
+	if actor != nil {
+		merge.MergedBy = actor.StaffID
+		merge.MergedByUsername = actor.Username
+	}
+
+	events, err := mergeAuditEvents(actor, domain.AuditActionPatientMerge, merge)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.patientRepo.Merge(merge, events, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+	return merge, nil
+}
+
+// Unmerge reverses an active merge and restores the retired patient under its own HN
+func (s *patientService) Unmerge(mergeID uint, actor *domain.Actor, schemaName string) (*domain.PatientMerge, error) {
+	merge, err := s.patientRepo.GetMerge(mergeID, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	if !merge.IsActive() {
+		return nil, fmt.Errorf("%w: merge has already been reversed", domain.ErrInvalidInput)
+	}
+
+	now := time.Now()
+	merge.UnmergedAt = &now
+	if actor != nil {
+		merge.UnmergedBy = &actor.StaffID
+		merge.UnmergedByUsername = actor.Username
+	}
+
+	events, err := mergeAuditEvents(actor, domain.AuditActionPatientUnmerge, merge)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.patientRepo.Unmerge(merge, events, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+	return merge, nil
+}
+
+func (s *patientService) ListMerges(patientID *uint, schemaName string) ([]domain.PatientMerge, error) {
+	merges, err := s.patientRepo.ListMerges(patientID, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return merges, nil
+}
+
+// History returns the version history of a patient, oldest first, with the changes each version made
+func (s *patientService) History(id uint, actor *domain.Actor, schemaName string) ([]domain.PatientVersion, error) {
+	patient, err := s.patientRepo.GetByID(id, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	versions, err := s.patientRepo.ListVersions(id, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	for i := 1; i < len(versions); i++ {
+		versions[i].Changes = domain.DiffPatientFields(versions[i-1].Snapshot, versions[i].Snapshot)
+	}
+
+	// Earlier versions disclose the record just like the current one
+	if err := s.recordAccess(actor, domain.AuditActionPatientHistory, []domain.Patient{*patient}, schemaName); err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+// DiffVersions compares two versions of a patient field by field
+func (s *patientService) DiffVersions(id uint, from int, to int, actor *domain.Actor, schemaName string) (*domain.PatientVersionDiff, error) {
+	patient, err := s.patientRepo.GetByID(id, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	versions, err := s.patientRepo.ListVersions(id, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	var fromVersion, toVersion *domain.PatientVersion
+	for i := range versions {
+		if versions[i].Version == from {
+			fromVersion = &versions[i]
+		}
+		if versions[i].Version == to {
+			toVersion = &versions[i]
+		}
+	}
+	if fromVersion == nil || toVersion == nil {
+		return nil, domain.ErrNotFound
+	}
+
+	if err := s.recordAccess(actor, domain.AuditActionPatientHistory, []domain.Patient{*patient}, schemaName); err != nil {
+		return nil, err
+	}
+	return domain.DiffPatientVersions(fromVersion, toVersion), nil
+}
+
+// mergeAuditEvents records a merge or unmerge against both the survivor and the retired patient
+func mergeAuditEvents(actor *domain.Actor, action string, merge *domain.PatientMerge) ([]*domain.AuditEvent, error) {
+	changes := map[string]domain.FieldChange{
+		"survivor_hn": {After: merge.SurvivorHN},
+		"retired_hn":  {After: merge.RetiredHN},
+	}
+
+	events := make([]*domain.AuditEvent, 0, 2)
+	for _, patientID := range []uint{merge.SurvivorID, merge.RetiredID} {
+		id := patientID
+		event, err := domain.NewAuditEvent(actor, action, &id, changes)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
@@ -0,0 +1,181 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+type roleService struct {
+	roleRepo domain.RoleRepository
+}
+
+// NewRoleService creates a new role service
+func NewRoleService(roleRepo domain.RoleRepository) domain.RoleService {
+	return &roleService{
+		roleRepo: roleRepo,
+	}
+}
+
+func (s *roleService) GetAll(schemaName string) ([]domain.Role, error) {
+	roles, err := s.roleRepo.GetAll(schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return roles, nil
+}
+
+func (s *roleService) GetByID(id uint, schemaName string) (*domain.Role, error) {
+	role, err := s.roleRepo.GetByID(id, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return role, nil
+}
+
+func (s *roleService) GetAllPermissions(schemaName string) ([]domain.Permission, error) {
+	permissions, err := s.roleRepo.GetAllPermissions(schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return permissions, nil
+}
+
+// Create creates a custom (non-system) role
+func (s *roleService) Create(req *domain.RoleCreateRequest, schemaName string) (*domain.Role, error) {
+	permissions, err := s.resolvePermissions(req.Permissions, schemaName)
+	if err != nil {
+		return nil, err
+	}
+
+	role := &domain.Role{
+		Code:        strings.ToLower(strings.TrimSpace(req.Code)),
+		Name:        strings.TrimSpace(req.Name),
+		Description: strings.TrimSpace(req.Description),
+		Permissions: permissions,
+	}
+
+	if err := s.roleRepo.Create(role, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+	return role, nil
+}
+
+// Update replaces the name, description and permission set of a role.
+// The permissions of built-in system roles are fixed so the admin role can never lose role:manage.
+func (s *roleService) Update(id uint, req *domain.RoleUpdateRequest, schemaName string) (*domain.Role, error) {
+	role, err := s.roleRepo.GetByID(id, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	permissions, err := s.resolvePermissions(req.Permissions, schemaName)
+	if err != nil {
+		return nil, err
+	}
+
+	if role.IsSystem && !sameCodes(permissionCodes(role.Permissions), permissionCodes(permissions)) {
+		return nil, fmt.Errorf("%w: permissions of system role %s cannot be changed", domain.ErrInvalidInput, role.Code)
+	}
+
+	role.Name = strings.TrimSpace(req.Name)
+	role.Description = strings.TrimSpace(req.Description)
+	role.Permissions = permissions
+
+	if err := s.roleRepo.Update(role, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+	return role, nil
+}
+
+// Delete deletes a custom role; built-in system roles cannot be deleted
+func (s *roleService) Delete(id uint, schemaName string) error {
+	role, err := s.roleRepo.GetByID(id, schemaName)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	if role.IsSystem {
+		return fmt.Errorf("%w: system role %s cannot be deleted", domain.ErrInvalidInput, role.Code)
+	}
+
+	if err := s.roleRepo.Delete(id, schemaName); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+// AssignStaffRoles replaces the roles of a staff member.
+// New permissions take effect when the staff member next logs in or refreshes their token.
+func (s *roleService) AssignStaffRoles(staffID uint, req *domain.StaffRolesRequest, schemaName string) error {
+	roles := []domain.Role{}
+	if len(req.Roles) > 0 {
+		found, err := s.roleRepo.GetByCodes(req.Roles, schemaName)
+		if err != nil {
+			return wrapError(err)
+		}
+		if missing := missingCodes(req.Roles, roleCodes(found)); len(missing) > 0 {
+			return fmt.Errorf("%w: unknown roles: %s", domain.ErrInvalidInput, strings.Join(missing, ", "))
+		}
+		roles = found
+	}
+
+	if err := s.roleRepo.ReplaceStaffRoles(staffID, roles, schemaName); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+// resolvePermissions loads the permissions for the given codes and rejects unknown codes
+func (s *roleService) resolvePermissions(codes []string, schemaName string) ([]domain.Permission, error) {
+	permissions, err := s.roleRepo.GetPermissionsByCodes(codes, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	if missing := missingCodes(codes, permissionCodes(permissions)); len(missing) > 0 {
+		return nil, fmt.Errorf("%w: unknown permissions: %s", domain.ErrInvalidInput, strings.Join(missing, ", "))
+	}
+
+	return permissions, nil
+}
+
+// roleCodes returns the codes of the given roles
+func roleCodes(roles []domain.Role) []string {
+	codes := make([]string, 0, len(roles))
+	for _, role := range roles {
+		codes = append(codes, role.Code)
+	}
+	return codes
+}
+
+// permissionCodes returns the codes of the given permissions
+func permissionCodes(permissions []domain.Permission) []string {
+	codes := make([]string, 0, len(permissions))
+	for _, p := range permissions {
+		codes = append(codes, p.Code)
+	}
+	return codes
+}
+
+// sameCodes reports whether a and b contain the same set of codes
+func sameCodes(a []string, b []string) bool {
+	return len(missingCodes(a, b)) == 0 && len(missingCodes(b, a)) == 0
+}
+
+// missingCodes returns the requested codes that are not in found
+func missingCodes(requested []string, found []string) []string {
+	foundSet := make(map[string]bool, len(found))
+	for _, code := range found {
+		foundSet[code] = true
+	}
+
+	var missing []string
+	for _, code := range requested {
+		if !foundSet[code] {
+			missing = append(missing, code)
+		}
+	}
+	return missing
+}
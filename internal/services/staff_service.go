@@ -1,7 +1,6 @@
 package services
 
 import (
-	"errors"
 	"fmt"
 	"time"
 
@@ -12,40 +11,109 @@ import (
 
 type staffService struct {
 	staffRepo  domain.StaffRepository
+	tokenRepo  domain.TokenRepository
 	jwtService jwt.JWTService
 }
 
 func NewStaffService(
 	staffRepo domain.StaffRepository,
+	tokenRepo domain.TokenRepository,
 	jwtService jwt.JWTService,
 ) domain.StaffService {
 	return &staffService{
 		staffRepo:  staffRepo,
+		tokenRepo:  tokenRepo,
 		jwtService: jwtService,
 	}
 }
 
-// Login authenticates a staff member and returns a JWT token
+// Login authenticates a staff member and returns an access/refresh token pair
 func (s *staffService) Login(req *domain.StaffLoginRequest, schemaName string) (*domain.StaffLoginResponse, error) {
 	// Tenant schema provides isolation, just search by username
 	staff, err := s.staffRepo.GetByUsername(req.Username, schemaName)
 	if err != nil {
-		return nil, errors.New("invalid credentials")
+		return nil, domain.ErrInvalidCredentials
 	}
 
 	// Verify password
 	if err := bcrypt.CompareHashAndPassword([]byte(staff.Password), []byte(req.Password)); err != nil {
-		return nil, errors.New("invalid credentials")
+		return nil, domain.ErrInvalidCredentials
 	}
 
+	return s.issueTokens(staff, schemaName, func(token *domain.RefreshToken) error {
+		return s.tokenRepo.CreateRefreshToken(token, schemaName)
+	})
+}
+
+// RefreshToken exchanges a refresh token for a new access/refresh token pair.
+// The presented refresh token is revoked (rotation). Presenting a refresh token that
+// was already rotated is treated as token theft and revokes every session of the staff member.
+func (s *staffService) RefreshToken(req *domain.TokenRefreshRequest, schemaName string) (*domain.StaffLoginResponse, error) {
+	current, err := s.tokenRepo.GetRefreshTokenByHash(jwt.HashToken(req.RefreshToken), schemaName)
+	if err != nil {
+		return nil, domain.ErrInvalidToken
+	}
+
+	if current.RevokedAt != nil {
+		if err := s.tokenRepo.RevokeAllForStaff(current.StaffID, domain.RevokeReasonTokenReuse, schemaName); err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrInvalidToken
+	}
+
+	if !current.IsActive(time.Now()) {
+		return nil, domain.ErrInvalidToken
+	}
+
+	// Staff may have been deleted since the token was issued
+	staff, err := s.staffRepo.GetByID(current.StaffID, schemaName)
+	if err != nil {
+		return nil, domain.ErrInvalidToken
+	}
+
+	return s.issueTokens(staff, schemaName, func(token *domain.RefreshToken) error {
+		return s.tokenRepo.RotateRefreshToken(current.ID, token, schemaName)
+	})
+}
+
+// issueTokens generates a new access/refresh token pair and persists the refresh token with store
+func (s *staffService) issueTokens(
+	staff *domain.Staff,
+	schemaName string,
+	store func(token *domain.RefreshToken) error,
+) (*domain.StaffLoginResponse, error) {
 	// Generate JWT token with schema information
-	token, err := s.jwtService.GenerateToken(staff.ID, staff.Username, staff.IsAdmin, schemaName)
+	accessToken, err := s.jwtService.GenerateToken(
+		staff.ID,
+		staff.Username,
+		staff.HasRole(domain.RoleAdmin),
+		staff.RoleCodes(),
+		staff.PermissionCodes(),
+		schemaName,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, refreshExpiresAt, err := s.jwtService.GenerateRefreshToken()
 	if err != nil {
 		return nil, err
 	}
 
+	if err := store(&domain.RefreshToken{
+		StaffID:              staff.ID,
+		TokenHash:            jwt.HashToken(refreshToken),
+		AccessTokenID:        accessToken.ID,
+		AccessTokenExpiresAt: accessToken.ExpiresAt,
+		ExpiresAt:            refreshExpiresAt,
+	}); err != nil {
+		return nil, err
+	}
+
 	return &domain.StaffLoginResponse{
-		Token: token,
+		Token:        accessToken.Token,
+		ExpiresAt:    accessToken.ExpiresAt,
+		RefreshToken: refreshToken,
 		Staff: domain.StaffResponse{
 			ID:          staff.ID,
 			Username:    staff.Username,
@@ -54,11 +122,42 @@ func (s *staffService) Login(req *domain.StaffLoginRequest, schemaName string) (
 			Email:       staff.Email,
 			FirstName:   staff.FirstName,
 			LastName:    staff.LastName,
-			IsAdmin:     staff.IsAdmin,
+			IsAdmin:     staff.HasRole(domain.RoleAdmin),
+			Roles:       staff.RoleCodes(),
+			Permissions: staff.PermissionCodes(),
 		},
 	}, nil
 }
 
+// Logout revokes all outstanding access and refresh tokens of the staff member
+func (s *staffService) Logout(staffID uint, schemaName string) error {
+	return s.tokenRepo.RevokeAllForStaff(staffID, domain.RevokeReasonLogout, schemaName)
+}
+
+// ChangePassword verifies the current password, stores the new one and revokes all sessions
+func (s *staffService) ChangePassword(id uint, req *domain.StaffChangePasswordRequest, schemaName string) error {
+	staff, err := s.staffRepo.GetByID(id, schemaName)
+	if err != nil {
+		return err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(staff.Password), []byte(req.CurrentPassword)); err != nil {
+		return domain.ErrInvalidCredentials
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	return s.staffRepo.UpdatePassword(staff.ID, string(hashedPassword), schemaName)
+}
+
+// IsTokenRevoked reports whether the access token with the given jti has been revoked
+func (s *staffService) IsTokenRevoked(jti string, schemaName string) (bool, error) {
+	return s.tokenRepo.IsRevoked(jti, schemaName)
+}
+
 func (s *staffService) GetAll(schemaName string) ([]domain.Staff, error) {
 	return s.staffRepo.GetAll(schemaName)
 }
@@ -86,7 +185,6 @@ func (s *staffService) Create(req *domain.StaffCreateRequest, schemaName string)
 		Email:       req.Email,
 		FirstName:   req.FirstName,
 		LastName:    req.LastName,
-		IsAdmin:     req.IsAdmin,
 	}
 
 	if err := s.staffRepo.Create(staff, schemaName); err != nil {
@@ -108,7 +206,6 @@ func (s *staffService) Update(id uint, req *domain.StaffUpdateRequest, schemaNam
 	staff.Email = req.Email
 	staff.FirstName = req.FirstName
 	staff.LastName = req.LastName
-	staff.IsAdmin = req.IsAdmin
 
 	if err := s.staffRepo.Update(staff, schemaName); err != nil {
 		return nil, err
@@ -117,6 +214,7 @@ func (s *staffService) Update(id uint, req *domain.StaffUpdateRequest, schemaNam
 	return staff, nil
 }
 
+// Delete soft-deletes the staff member; the repository revokes their sessions in the same transaction
 func (s *staffService) Delete(id uint, schemaName string) error {
 	return s.staffRepo.Delete(id, schemaName)
 }
@@ -77,6 +77,7 @@ func (s *tenantService) CreateTenant(req *domain.TenantCreateRequest) (*domain.T
 		HospitalCode: req.HospitalCode,
 		Address:      req.Address,
 		HNRunning:    0,
+		HNTemplate:   domain.DefaultHNTemplate,
 	}
 
 	if err := s.tenantRepo.Create(tenant); err != nil {
@@ -107,10 +108,84 @@ func (s *tenantService) MigrateTenantSchema(schemaName string) error {
 		// Create tables in tenant schema
 		// Note: We create tenant-specific tables (Staff, Patient)
 		// Tenant table remains in public schema
-		if err := tx.AutoMigrate(&domain.Staff{}, &domain.Patient{}); err != nil {
+		if err := tx.AutoMigrate(
+			&domain.Permission{},
+			&domain.Role{},
+			&domain.Staff{},
+			&domain.Patient{},
+			&domain.RefreshToken{},
+			&domain.RevokedToken{},
+			&domain.AuditEvent{},
+		); err != nil {
 			return fmt.Errorf("failed to migrate tenant schema: %w", err)
 		}
-		return nil
+		if err := protectAuditTable(tx, schemaName); err != nil {
+			return err
+		}
+		if err := createPatientSearchIndexes(tx, schemaName); err != nil {
+			return err
+		}
+		if err := nullBlankPatientIdentifiers(tx, schemaName); err != nil {
+			return err
+		}
+		if err := createLiveUniqueIndexes(tx, schemaName); err != nil {
+			return err
+		}
+		if err := createPatientMergeTables(tx, schemaName); err != nil {
+			return err
+		}
+		if err := createPatientVersionTables(tx, schemaName); err != nil {
+			return err
+		}
+		if err := createIdempotencyTables(tx, schemaName); err != nil {
+			return err
+		}
+		if err := createHNCounterTables(tx, schemaName); err != nil {
+			return err
+		}
+		if err := createPatientContactTables(tx, schemaName); err != nil {
+			return err
+		}
+		if err := createPatientAddressTables(tx, schemaName); err != nil {
+			return err
+		}
+		if err := createPatientAllergyTables(tx, schemaName); err != nil {
+			return err
+		}
+		if err := createPatientCoverageTables(tx, schemaName); err != nil {
+			return err
+		}
+		if err := createDepartmentTables(tx, schemaName); err != nil {
+			return err
+		}
+		if err := createEncounterTables(tx, schemaName); err != nil {
+			return err
+		}
+		if err := createAppointmentTables(tx, schemaName); err != nil {
+			return err
+		}
+		if err := createVitalSignTables(tx, schemaName); err != nil {
+			return err
+		}
+		if err := createDiagnosisTables(tx, schemaName); err != nil {
+			return err
+		}
+		if err := createMedicationTables(tx, schemaName); err != nil {
+			return err
+		}
+		if err := createInventoryTables(tx, schemaName); err != nil {
+			return err
+		}
+		if err := createLabTables(tx, schemaName); err != nil {
+			return err
+		}
+		if err := purgeDeletedRoles(tx, schemaName); err != nil {
+			return err
+		}
+		if err := seedRolesAndPermissions(tx, schemaName); err != nil {
+			return err
+		}
+		return backfillAdminRoles(tx, schemaName)
 	})
 }
 
@@ -158,6 +233,7 @@ func (s *tenantService) SetupTenantWithAdmin(
 		HospitalCode: hospitalCode,
 		Address:      address,
 		HNRunning:    0,
+		HNTemplate:   domain.DefaultHNTemplate,
 	}
 
 	if err := tx.Create(tenant).Error; err != nil {
@@ -210,14 +286,14 @@ func (s *tenantService) migrateTenantSchemaWithDB(tx *gorm.DB, schemaName string
 			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
 			updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
 			deleted_at TIMESTAMP WITH TIME ZONE,
-			username VARCHAR(100) UNIQUE NOT NULL,
+			username VARCHAR(100) NOT NULL,
 			password VARCHAR(255) NOT NULL,
-			staff_code VARCHAR(50) UNIQUE NOT NULL,
-			phone_number VARCHAR(20) UNIQUE NOT NULL,
-			email VARCHAR(255) UNIQUE NOT NULL,
+			staff_code VARCHAR(50) NOT NULL,
+			phone_number VARCHAR(20) NOT NULL,
+			email VARCHAR(255) NOT NULL,
 			first_name VARCHAR(255) NOT NULL,
 			last_name VARCHAR(255) NOT NULL,
-			is_admin BOOLEAN DEFAULT FALSE
+			version INTEGER NOT NULL DEFAULT 1
 		)
 	`, schemaName)
 	if err := tx.Exec(staffTable).Error; err != nil {
@@ -247,13 +323,14 @@ func (s *tenantService) migrateTenantSchemaWithDB(tx *gorm.DB, schemaName string
 			nick_name_th VARCHAR(50),
 			nick_name_en VARCHAR(50),
 			patient_hn VARCHAR(50) UNIQUE NOT NULL,
-			national_id VARCHAR(20) UNIQUE,
-			passport_id VARCHAR(50) UNIQUE,
-			phone_number VARCHAR(20) UNIQUE,
-			email VARCHAR(255) UNIQUE,
+			national_id VARCHAR(20),
+			passport_id VARCHAR(50),
+			phone_number VARCHAR(20),
+			email VARCHAR(255),
 			gender VARCHAR(10) NOT NULL,
 			nationality VARCHAR(100) NOT NULL,
-			blood_grp VARCHAR(5) NOT NULL
+			blood_grp VARCHAR(5) NOT NULL,
+			version INTEGER NOT NULL DEFAULT 1
 		)
 	`, schemaName)
 	if err := tx.Exec(patientTable).Error; err != nil {
@@ -266,6 +343,114 @@ func (s *tenantService) migrateTenantSchemaWithDB(tx *gorm.DB, schemaName string
 		return fmt.Errorf("failed to create patients index: %w", err)
 	}
 
+	// Create sort, range and trigram indexes used by patient search
+	if err := createPatientSearchIndexes(tx, schemaName); err != nil {
+		return err
+	}
+
+	// Unique identifiers of patients and staff only apply to live rows
+	if err := createLiveUniqueIndexes(tx, schemaName); err != nil {
+		return err
+	}
+
+	// Create the merge history behind HN aliases of merged patients
+	if err := createPatientMergeTables(tx, schemaName); err != nil {
+		return err
+	}
+
+	// Create the demographic version history of patients
+	if err := createPatientVersionTables(tx, schemaName); err != nil {
+		return err
+	}
+
+	// Create the stored responses of create requests sent with an Idempotency-Key
+	if err := createIdempotencyTables(tx, schemaName); err != nil {
+		return err
+	}
+
+	// Create the HN running numbers, one counter per reset period
+	if err := createHNCounterTables(tx, schemaName); err != nil {
+		return err
+	}
+
+	// Create next of kin and emergency contacts of patients
+	if err := createPatientContactTables(tx, schemaName); err != nil {
+		return err
+	}
+
+	// Create structured Thai addresses of patients
+	if err := createPatientAddressTables(tx, schemaName); err != nil {
+		return err
+	}
+
+	// Create the allergy and adverse drug reaction registry
+	if err := createPatientAllergyTables(tx, schemaName); err != nil {
+		return err
+	}
+
+	// Create the payer coverage table
+	if err := createPatientCoverageTables(tx, schemaName); err != nil {
+		return err
+	}
+
+	// Create the departments and their seed list
+	if err := createDepartmentTables(tx, schemaName); err != nil {
+		return err
+	}
+
+	// Create patient visits and the daily VN counters
+	if err := createEncounterTables(tx, schemaName); err != nil {
+		return err
+	}
+
+	// Create doctor availability, appointment slots and appointments
+	if err := createAppointmentTables(tx, schemaName); err != nil {
+		return err
+	}
+
+	// Create vital signs taken during visits
+	if err := createVitalSignTables(tx, schemaName); err != nil {
+		return err
+	}
+
+	// Create the ICD-10 diagnoses of visits
+	if err := createDiagnosisTables(tx, schemaName); err != nil {
+		return err
+	}
+
+	// Create the drug master, drug interactions and medication orders
+	if err := createMedicationTables(tx, schemaName); err != nil {
+		return err
+	}
+
+	// Create pharmacy stock locations, lots, goods-received notes and the append-only stock ledger
+	if err := createInventoryTables(tx, schemaName); err != nil {
+		return err
+	}
+
+	// Create the lab test catalogue, lab orders, specimens and results
+	if err := createLabTables(tx, schemaName); err != nil {
+		return err
+	}
+
+	// Create refresh token and revocation list tables
+	if err := createTokenTables(tx, schemaName); err != nil {
+		return err
+	}
+
+	// Create role-based access control tables and seed built-in roles
+	if err := createRoleTables(tx, schemaName); err != nil {
+		return err
+	}
+	if err := seedRolesAndPermissions(tx, schemaName); err != nil {
+		return err
+	}
+
+	// Create the append-only patient record audit trail
+	if err := createAuditTables(tx, schemaName); err != nil {
+		return err
+	}
+
 	// Reset search path
 	if err := tx.Exec("SET search_path TO public").Error; err != nil {
 		return err
@@ -274,6 +459,1242 @@ func (s *tenantService) migrateTenantSchemaWithDB(tx *gorm.DB, schemaName string
 	return nil
 }
 
+// createTokenTables creates the refresh token and access token revocation tables
+func createTokenTables(tx *gorm.DB, schemaName string) error {
+	refreshTokenTable := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.refresh_tokens (
+			id SERIAL PRIMARY KEY,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			staff_id INTEGER NOT NULL REFERENCES %s.staffs(id),
+			token_hash VARCHAR(64) UNIQUE NOT NULL,
+			access_token_id VARCHAR(64) NOT NULL,
+			access_token_expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			revoked_at TIMESTAMP WITH TIME ZONE,
+			replaced_by_id INTEGER REFERENCES %s.refresh_tokens(id)
+		)
+	`, schemaName, schemaName, schemaName)
+	if err := tx.Exec(refreshTokenTable).Error; err != nil {
+		return fmt.Errorf("failed to create refresh_tokens table: %w", err)
+	}
+
+	refreshTokenIndex := fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_refresh_tokens_staff_id ON %s.refresh_tokens(staff_id)", schemaName, schemaName)
+	if err := tx.Exec(refreshTokenIndex).Error; err != nil {
+		return fmt.Errorf("failed to create refresh_tokens index: %w", err)
+	}
+
+	revokedTokenTable := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.revoked_tokens (
+			jti VARCHAR(64) PRIMARY KEY,
+			staff_id INTEGER NOT NULL,
+			expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			revoked_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			reason VARCHAR(50)
+		)
+	`, schemaName)
+	if err := tx.Exec(revokedTokenTable).Error; err != nil {
+		return fmt.Errorf("failed to create revoked_tokens table: %w", err)
+	}
+
+	revokedTokenIndex := fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_revoked_tokens_expires_at ON %s.revoked_tokens(expires_at)", schemaName, schemaName)
+	if err := tx.Exec(revokedTokenIndex).Error; err != nil {
+		return fmt.Errorf("failed to create revoked_tokens index: %w", err)
+	}
+
+	return nil
+}
+
+// createPatientSearchIndexes creates the indexes behind patient search: B-tree indexes for
+// the default sort and range filters, and pg_trgm GIN indexes on the Thai and English names
+// that serve both ILIKE substring search and similarity-ranked fuzzy search
+func createPatientSearchIndexes(tx *gorm.DB, schemaName string) error {
+	// Extensions are database wide; install into public so every tenant search_path sees it
+	if err := tx.Exec("CREATE EXTENSION IF NOT EXISTS pg_trgm WITH SCHEMA public").Error; err != nil {
+		return fmt.Errorf("failed to create pg_trgm extension: %w", err)
+	}
+
+	indexes := []string{
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_patients_created_at ON %s.patients(created_at, id)", schemaName, schemaName),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_patients_date_of_birth ON %s.patients(date_of_birth, id)", schemaName, schemaName),
+	}
+
+	// Must match fuzzyNameExpressions in the patient repository for the planner to use them
+	trigramIndexes := []struct {
+		name       string
+		expression string
+	}{
+		{"first_name_th", "first_name_th"},
+		{"last_name_th", "last_name_th"},
+		{"first_name_en", "first_name_en"},
+		{"last_name_en", "last_name_en"},
+		{"full_name_th", "(first_name_th || ' ' || last_name_th)"},
+		{"full_name_en", "(first_name_en || ' ' || last_name_en)"},
+	}
+	for _, index := range trigramIndexes {
+		indexes = append(indexes, fmt.Sprintf(
+			"CREATE INDEX IF NOT EXISTS idx_%s_patients_%s_trgm ON %s.patients USING GIN (%s public.gin_trgm_ops)",
+			schemaName, index.name, schemaName, index.expression,
+		))
+	}
+
+	for _, index := range indexes {
+		if err := tx.Exec(index).Error; err != nil {
+			return fmt.Errorf("failed to create patients search index: %w", err)
+		}
+	}
+	return nil
+}
+
+// nullBlankPatientIdentifiers converts blank national and passport IDs saved before they
+// were stored as NULL, so the unique indexes only apply to real values
+func nullBlankPatientIdentifiers(tx *gorm.DB, schemaName string) error {
+	for _, column := range []string{"national_id", "passport_id"} {
+		query := fmt.Sprintf("UPDATE %s.patients SET %s = NULL WHERE TRIM(%s) = ''", schemaName, column, column)
+		if err := tx.Exec(query).Error; err != nil {
+			return fmt.Errorf("failed to clear blank patient %s: %w", column, err)
+		}
+	}
+	return nil
+}
+
+// liveUniqueColumns are the columns of each table that must be unique among rows that are not soft-deleted
+var liveUniqueColumns = map[string][]string{
+	"patients": {"national_id", "passport_id", "phone_number", "email"},
+	"staffs":   {"username", "staff_code", "phone_number", "email"},
+}
+
+// createLiveUniqueIndexes replaces the plain unique constraints on patient and staff identifiers
+// with partial unique indexes over live rows. A deleted record then no longer blocks its values,
+// and restoring it checks for records that have taken them since.
+func createLiveUniqueIndexes(tx *gorm.DB, schemaName string) error {
+	for _, table := range []string{"patients", "staffs"} {
+		for _, column := range liveUniqueColumns[table] {
+			statements := []string{
+				// Created inline by the tenant DDL and by GORM's uniqueIndex tag respectively
+				fmt.Sprintf("ALTER TABLE %s.%s DROP CONSTRAINT IF EXISTS %s_%s_key", schemaName, table, table, column),
+				fmt.Sprintf("DROP INDEX IF EXISTS %s.idx_%s_%s", schemaName, table, column),
+				fmt.Sprintf("CREATE UNIQUE INDEX IF NOT EXISTS idx_%s_%s_live ON %s.%s(%s) WHERE deleted_at IS NULL",
+					table, column, schemaName, table, column),
+			}
+			for _, statement := range statements {
+				if err := tx.Exec(statement).Error; err != nil {
+					return fmt.Errorf("failed to create live unique index on %s.%s: %w", table, column, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// createPatientMergeTables creates the patient_merges table. The partial unique index allows
+// a patient to be retired by only one active merge, so its HN aliases a single survivor.
+func createPatientMergeTables(tx *gorm.DB, schemaName string) error {
+	mergeTable := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.patient_merges (
+			id SERIAL PRIMARY KEY,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			survivor_id INTEGER NOT NULL REFERENCES %s.patients(id),
+			survivor_hn VARCHAR(50) NOT NULL,
+			retired_id INTEGER NOT NULL REFERENCES %s.patients(id),
+			retired_hn VARCHAR(50) NOT NULL,
+			reason VARCHAR(255),
+			merged_by INTEGER NOT NULL,
+			merged_by_username VARCHAR(100),
+			unmerged_at TIMESTAMP WITH TIME ZONE,
+			unmerged_by INTEGER,
+			unmerged_by_username VARCHAR(100)
+		)
+	`, schemaName, schemaName, schemaName)
+	if err := tx.Exec(mergeTable).Error; err != nil {
+		return fmt.Errorf("failed to create patient_merges table: %w", err)
+	}
+
+	mergeIndexes := []string{
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_patient_merges_survivor_id ON %s.patient_merges(survivor_id)", schemaName, schemaName),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_patient_merges_retired_hn ON %s.patient_merges(retired_hn)", schemaName, schemaName),
+		fmt.Sprintf("CREATE UNIQUE INDEX IF NOT EXISTS idx_%s_patient_merges_active_retired_id ON %s.patient_merges(retired_id) WHERE unmerged_at IS NULL", schemaName, schemaName),
+	}
+	for _, index := range mergeIndexes {
+		if err := tx.Exec(index).Error; err != nil {
+			return fmt.Errorf("failed to create patient_merges index: %w", err)
+		}
+	}
+	return nil
+}
+
+// createPatientVersionTables creates the patient_versions table. The unique index on
+// (patient_id, version) rejects two writers numbering the same version.
+func createPatientVersionTables(tx *gorm.DB, schemaName string) error {
+	versionTable := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.patient_versions (
+			id SERIAL PRIMARY KEY,
+			patient_id INTEGER NOT NULL REFERENCES %s.patients(id),
+			version INTEGER NOT NULL,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			action VARCHAR(20) NOT NULL,
+			changed_by INTEGER,
+			changed_by_username VARCHAR(100),
+			snapshot JSONB NOT NULL
+		)
+	`, schemaName, schemaName)
+	if err := tx.Exec(versionTable).Error; err != nil {
+		return fmt.Errorf("failed to create patient_versions table: %w", err)
+	}
+
+	versionIndex := fmt.Sprintf("CREATE UNIQUE INDEX IF NOT EXISTS idx_%s_patient_versions_patient_version ON %s.patient_versions(patient_id, version)", schemaName, schemaName)
+	if err := tx.Exec(versionIndex).Error; err != nil {
+		return fmt.Errorf("failed to create patient_versions index: %w", err)
+	}
+	return nil
+}
+
+// createIdempotencyTables creates the idempotency_keys table; the primary key on the client's
+// key is what lets only one of several concurrent retries through
+func createIdempotencyTables(tx *gorm.DB, schemaName string) error {
+	keyTable := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.idempotency_keys (
+			key VARCHAR(255) PRIMARY KEY,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			staff_id INTEGER NOT NULL,
+			request_hash VARCHAR(64) NOT NULL,
+			status_code INTEGER NOT NULL DEFAULT 0,
+			response_body TEXT,
+			response_headers TEXT
+		)
+	`, schemaName)
+	if err := tx.Exec(keyTable).Error; err != nil {
+		return fmt.Errorf("failed to create idempotency_keys table: %w", err)
+	}
+
+	keyIndex := fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_idempotency_keys_created_at ON %s.idempotency_keys(created_at)", schemaName, schemaName)
+	if err := tx.Exec(keyIndex).Error; err != nil {
+		return fmt.Errorf("failed to create idempotency_keys index: %w", err)
+	}
+	return nil
+}
+
+// createHNCounterTables creates the hn_counters table, keyed by the period a counter covers
+func createHNCounterTables(tx *gorm.DB, schemaName string) error {
+	counterTable := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.hn_counters (
+			period VARCHAR(20) PRIMARY KEY,
+			last_value BIGINT NOT NULL DEFAULT 0,
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+		)
+	`, schemaName)
+	if err := tx.Exec(counterTable).Error; err != nil {
+		return fmt.Errorf("failed to create hn_counters table: %w", err)
+	}
+	return nil
+}
+
+// createPatientContactTables creates the patient_contacts table. The partial unique index
+// keeps a single live primary contact per patient.
+func createPatientContactTables(tx *gorm.DB, schemaName string) error {
+	contactTable := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.patient_contacts (
+			id SERIAL PRIMARY KEY,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			deleted_at TIMESTAMP WITH TIME ZONE,
+			patient_id INTEGER NOT NULL REFERENCES %s.patients(id),
+			name VARCHAR(255) NOT NULL,
+			relationship VARCHAR(20) NOT NULL,
+			phone_number VARCHAR(20) NOT NULL,
+			alt_phone_number VARCHAR(20),
+			address TEXT,
+			is_primary BOOLEAN NOT NULL DEFAULT FALSE
+		)
+	`, schemaName, schemaName)
+	if err := tx.Exec(contactTable).Error; err != nil {
+		return fmt.Errorf("failed to create patient_contacts table: %w", err)
+	}
+
+	contactIndexes := []string{
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_patient_contacts_patient_id ON %s.patient_contacts(patient_id)", schemaName, schemaName),
+		fmt.Sprintf("CREATE UNIQUE INDEX IF NOT EXISTS idx_%s_patient_contacts_primary ON %s.patient_contacts(patient_id) WHERE is_primary AND deleted_at IS NULL", schemaName, schemaName),
+	}
+	for _, index := range contactIndexes {
+		if err := tx.Exec(index).Error; err != nil {
+			return fmt.Errorf("failed to create patient_contacts index: %w", err)
+		}
+	}
+	return nil
+}
+
+// createPatientAddressTables creates the patient_addresses table. A patient has one live
+// address per type; subdistrict_code refers to the public thai_subdistricts reference table.
+func createPatientAddressTables(tx *gorm.DB, schemaName string) error {
+	addressTable := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.patient_addresses (
+			id SERIAL PRIMARY KEY,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			deleted_at TIMESTAMP WITH TIME ZONE,
+			patient_id INTEGER NOT NULL REFERENCES %s.patients(id),
+			address_type VARCHAR(20) NOT NULL CHECK (address_type IN ('registered', 'current', 'work')),
+			house_no VARCHAR(20) NOT NULL,
+			moo VARCHAR(10),
+			soi VARCHAR(100),
+			road VARCHAR(100),
+			subdistrict_code VARCHAR(6) NOT NULL REFERENCES public.thai_subdistricts(code),
+			tambon VARCHAR(100) NOT NULL,
+			amphoe VARCHAR(100) NOT NULL,
+			changwat VARCHAR(100) NOT NULL,
+			postal_code VARCHAR(5) NOT NULL
+		)
+	`, schemaName, schemaName)
+	if err := tx.Exec(addressTable).Error; err != nil {
+		return fmt.Errorf("failed to create patient_addresses table: %w", err)
+	}
+
+	addressIndexes := []string{
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_patient_addresses_deleted_at ON %s.patient_addresses(deleted_at)", schemaName, schemaName),
+		fmt.Sprintf("CREATE UNIQUE INDEX IF NOT EXISTS idx_%s_patient_addresses_type ON %s.patient_addresses(patient_id, address_type) WHERE deleted_at IS NULL", schemaName, schemaName),
+	}
+	for _, index := range addressIndexes {
+		if err := tx.Exec(index).Error; err != nil {
+			return fmt.Errorf("failed to create patient_addresses index: %w", err)
+		}
+	}
+	return nil
+}
+
+// createPatientAllergyTables creates the patient_allergies table. An allergen is recorded once
+// per live patient entry, compared ignoring case.
+func createPatientAllergyTables(tx *gorm.DB, schemaName string) error {
+	allergyTable := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.patient_allergies (
+			id SERIAL PRIMARY KEY,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			deleted_at TIMESTAMP WITH TIME ZONE,
+			patient_id INTEGER NOT NULL REFERENCES %s.patients(id),
+			allergen_type VARCHAR(20) NOT NULL CHECK (allergen_type IN ('drug', 'food', 'environmental')),
+			allergen VARCHAR(255) NOT NULL,
+			reaction VARCHAR(500),
+			severity VARCHAR(20) NOT NULL CHECK (severity IN ('mild', 'moderate', 'severe', 'life_threatening')),
+			certainty VARCHAR(20) NOT NULL CHECK (certainty IN ('suspected', 'probable', 'confirmed')),
+			note TEXT,
+			recorded_by INTEGER NOT NULL,
+			recorded_by_username VARCHAR(100)
+		)
+	`, schemaName, schemaName)
+	if err := tx.Exec(allergyTable).Error; err != nil {
+		return fmt.Errorf("failed to create patient_allergies table: %w", err)
+	}
+
+	allergyIndexes := []string{
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_patient_allergies_deleted_at ON %s.patient_allergies(deleted_at)", schemaName, schemaName),
+		fmt.Sprintf("CREATE UNIQUE INDEX IF NOT EXISTS idx_%s_patient_allergies_allergen ON %s.patient_allergies(patient_id, LOWER(allergen)) WHERE deleted_at IS NULL", schemaName, schemaName),
+	}
+	for _, index := range allergyIndexes {
+		if err := tx.Exec(index).Error; err != nil {
+			return fmt.Errorf("failed to create patient_allergies index: %w", err)
+		}
+	}
+	return nil
+}
+
+// createPatientCoverageTables creates the patient_coverages table. Overlapping periods of the
+// same scheme are rejected by the service, since a range exclusion constraint needs btree_gist.
+func createPatientCoverageTables(tx *gorm.DB, schemaName string) error {
+	coverageTable := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.patient_coverages (
+			id SERIAL PRIMARY KEY,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			deleted_at TIMESTAMP WITH TIME ZONE,
+			patient_id INTEGER NOT NULL REFERENCES %s.patients(id),
+			scheme VARCHAR(20) NOT NULL CHECK (scheme IN ('uc', 'sss', 'csmbs', 'private', 'self_pay')),
+			main_hospital_code VARCHAR(5),
+			main_hospital_name VARCHAR(255),
+			policy_number VARCHAR(50),
+			valid_from DATE NOT NULL,
+			valid_to DATE,
+			eligibility_status VARCHAR(20) CHECK (eligibility_status IN ('', 'eligible', 'not_eligible')),
+			eligibility_message VARCHAR(500),
+			eligibility_checked_at TIMESTAMP WITH TIME ZONE,
+			CHECK (valid_to IS NULL OR valid_to >= valid_from)
+		)
+	`, schemaName, schemaName)
+	if err := tx.Exec(coverageTable).Error; err != nil {
+		return fmt.Errorf("failed to create patient_coverages table: %w", err)
+	}
+
+	coverageIndexes := []string{
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_patient_coverages_deleted_at ON %s.patient_coverages(deleted_at)", schemaName, schemaName),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_patient_coverages_patient_id ON %s.patient_coverages(patient_id, valid_from)", schemaName, schemaName),
+	}
+	for _, index := range coverageIndexes {
+		if err := tx.Exec(index).Error; err != nil {
+			return fmt.Errorf("failed to create patient_coverages index: %w", err)
+		}
+	}
+	return nil
+}
+
+// createDepartmentTables creates the departments table and seeds the usual outpatient
+// departments into a tenant that has none yet
+func createDepartmentTables(tx *gorm.DB, schemaName string) error {
+	departmentTable := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.departments (
+			id SERIAL PRIMARY KEY,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			deleted_at TIMESTAMP WITH TIME ZONE,
+			code VARCHAR(20) NOT NULL,
+			name_th VARCHAR(255) NOT NULL,
+			name_en VARCHAR(255) NOT NULL,
+			is_active BOOLEAN NOT NULL DEFAULT TRUE
+		)
+	`, schemaName)
+	if err := tx.Exec(departmentTable).Error; err != nil {
+		return fmt.Errorf("failed to create departments table: %w", err)
+	}
+
+	departmentIndex := fmt.Sprintf("CREATE UNIQUE INDEX IF NOT EXISTS idx_%s_departments_code_live ON %s.departments(code) WHERE deleted_at IS NULL", schemaName, schemaName)
+	if err := tx.Exec(departmentIndex).Error; err != nil {
+		return fmt.Errorf("failed to create departments index: %w", err)
+	}
+
+	seedDepartments := fmt.Sprintf(`
+		INSERT INTO %s.departments (code, name_th, name_en)
+		SELECT v.code, v.name_th, v.name_en FROM (VALUES
+			('OPD', 'ตรวจโรคทั่วไป', 'General Outpatient'),
+			('ER', 'อุบัติเหตุและฉุกเฉิน', 'Emergency'),
+			('MED', 'อายุรกรรม', 'Internal Medicine'),
+			('SURG', 'ศัลยกรรม', 'Surgery'),
+			('PED', 'กุมารเวชกรรม', 'Pediatrics'),
+			('OBG', 'สูติ-นรีเวชกรรม', 'Obstetrics and Gynecology'),
+			('DENT', 'ทันตกรรม', 'Dental')
+		) AS v(code, name_th, name_en)
+		WHERE NOT EXISTS (SELECT 1 FROM %s.departments)
+	`, schemaName, schemaName)
+	if err := tx.Exec(seedDepartments).Error; err != nil {
+		return fmt.Errorf("failed to seed departments: %w", err)
+	}
+	return nil
+}
+
+// createEncounterTables creates the encounters table and the vn_counters table the daily visit
+// numbers are drawn from. Rows are never removed, so every VN stays unique.
+func createEncounterTables(tx *gorm.DB, schemaName string) error {
+	counterTable := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.vn_counters (
+			period VARCHAR(20) PRIMARY KEY,
+			last_value BIGINT NOT NULL DEFAULT 0,
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+		)
+	`, schemaName)
+	if err := tx.Exec(counterTable).Error; err != nil {
+		return fmt.Errorf("failed to create vn_counters table: %w", err)
+	}
+
+	encounterTable := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.encounters (
+			id SERIAL PRIMARY KEY,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			deleted_at TIMESTAMP WITH TIME ZONE,
+			vn VARCHAR(50) NOT NULL UNIQUE,
+			patient_id INTEGER NOT NULL REFERENCES %s.patients(id),
+			department_id INTEGER NOT NULL REFERENCES %s.departments(id),
+			status VARCHAR(20) NOT NULL CHECK (status IN ('registered', 'triaged', 'in_consultation', 'done')),
+			chief_complaint VARCHAR(500),
+			registered_by INTEGER NOT NULL,
+			registered_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			triaged_at TIMESTAMP WITH TIME ZONE,
+			consultation_started_at TIMESTAMP WITH TIME ZONE,
+			completed_at TIMESTAMP WITH TIME ZONE
+		)
+	`, schemaName, schemaName, schemaName)
+	if err := tx.Exec(encounterTable).Error; err != nil {
+		return fmt.Errorf("failed to create encounters table: %w", err)
+	}
+
+	encounterIndexes := []string{
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_encounters_deleted_at ON %s.encounters(deleted_at)", schemaName, schemaName),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_encounters_patient_id ON %s.encounters(patient_id, registered_at)", schemaName, schemaName),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_encounters_queue ON %s.encounters(department_id, registered_at)", schemaName, schemaName),
+	}
+	for _, index := range encounterIndexes {
+		if err := tx.Exec(index).Error; err != nil {
+			return fmt.Errorf("failed to create encounters index: %w", err)
+		}
+	}
+	return nil
+}
+
+// createAppointmentTables creates the availability templates, the slots expanded from them and
+// the appointments booked into the slots. Double booking is rejected by the database: a slot
+// has one booked appointment, and exclusion constraints keep a doctor's slots and a patient's
+// booked appointments from overlapping in time.
+func createAppointmentTables(tx *gorm.DB, schemaName string) error {
+	// The exclusion constraints compare the integer IDs with gist, which needs btree_gist
+	if err := tx.Exec("CREATE EXTENSION IF NOT EXISTS btree_gist WITH SCHEMA public").Error; err != nil {
+		return fmt.Errorf("failed to create btree_gist extension: %w", err)
+	}
+
+	templateTable := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.availability_templates (
+			id SERIAL PRIMARY KEY,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			deleted_at TIMESTAMP WITH TIME ZONE,
+			doctor_id INTEGER NOT NULL REFERENCES %s.staffs(id),
+			department_id INTEGER NOT NULL REFERENCES %s.departments(id),
+			weekday SMALLINT NOT NULL CHECK (weekday BETWEEN 0 AND 6),
+			start_time VARCHAR(5) NOT NULL,
+			end_time VARCHAR(5) NOT NULL,
+			slot_minutes INTEGER NOT NULL CHECK (slot_minutes BETWEEN 5 AND 240),
+			valid_from DATE NOT NULL,
+			valid_to DATE,
+			CHECK (end_time > start_time),
+			CHECK (valid_to IS NULL OR valid_to >= valid_from)
+		)
+	`, schemaName, schemaName, schemaName)
+	if err := tx.Exec(templateTable).Error; err != nil {
+		return fmt.Errorf("failed to create availability_templates table: %w", err)
+	}
+
+	slotTable := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.appointment_slots (
+			id SERIAL PRIMARY KEY,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			template_id INTEGER NOT NULL REFERENCES %s.availability_templates(id),
+			doctor_id INTEGER NOT NULL REFERENCES %s.staffs(id),
+			department_id INTEGER NOT NULL REFERENCES %s.departments(id),
+			start_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			end_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			CHECK (end_at > start_at)
+		)
+	`, schemaName, schemaName, schemaName, schemaName)
+	if err := tx.Exec(slotTable).Error; err != nil {
+		return fmt.Errorf("failed to create appointment_slots table: %w", err)
+	}
+
+	appointmentTable := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.appointments (
+			id SERIAL PRIMARY KEY,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			deleted_at TIMESTAMP WITH TIME ZONE,
+			patient_id INTEGER NOT NULL REFERENCES %s.patients(id),
+			slot_id INTEGER NOT NULL REFERENCES %s.appointment_slots(id),
+			doctor_id INTEGER NOT NULL REFERENCES %s.staffs(id),
+			department_id INTEGER NOT NULL REFERENCES %s.departments(id),
+			start_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			end_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			status VARCHAR(20) NOT NULL CHECK (status IN ('booked', 'cancelled', 'rescheduled')),
+			reason VARCHAR(500),
+			booked_by INTEGER NOT NULL,
+			cancelled_at TIMESTAMP WITH TIME ZONE,
+			cancel_reason VARCHAR(500),
+			rescheduled_from_id INTEGER REFERENCES %s.appointments(id)
+		)
+	`, schemaName, schemaName, schemaName, schemaName, schemaName, schemaName)
+	if err := tx.Exec(appointmentTable).Error; err != nil {
+		return fmt.Errorf("failed to create appointments table: %w", err)
+	}
+
+	appointmentIndexes := []string{
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_availability_templates_doctor_id ON %s.availability_templates(doctor_id)", schemaName, schemaName),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_availability_templates_deleted_at ON %s.availability_templates(deleted_at)", schemaName, schemaName),
+		fmt.Sprintf("CREATE UNIQUE INDEX IF NOT EXISTS idx_%s_appointment_slots_doctor_start ON %s.appointment_slots(doctor_id, start_at)", schemaName, schemaName),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_appointment_slots_template_id ON %s.appointment_slots(template_id)", schemaName, schemaName),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_appointment_slots_department_start ON %s.appointment_slots(department_id, start_at)", schemaName, schemaName),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_appointments_deleted_at ON %s.appointments(deleted_at)", schemaName, schemaName),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_appointments_patient_id ON %s.appointments(patient_id, start_at)", schemaName, schemaName),
+		fmt.Sprintf("CREATE UNIQUE INDEX IF NOT EXISTS idx_%s_appointments_slot_booked ON %s.appointments(slot_id) WHERE status = 'booked' AND deleted_at IS NULL", schemaName, schemaName),
+		fmt.Sprintf("CREATE UNIQUE INDEX IF NOT EXISTS idx_%s_appointments_patient_booked ON %s.appointments(patient_id, start_at) WHERE status = 'booked' AND deleted_at IS NULL", schemaName, schemaName),
+	}
+	for _, index := range appointmentIndexes {
+		if err := tx.Exec(index).Error; err != nil {
+			return fmt.Errorf("failed to create appointments index: %w", err)
+		}
+	}
+
+	// ADD CONSTRAINT has no IF NOT EXISTS, so existing tenants are checked in pg_constraint
+	appointmentExclusions := []struct {
+		table      string
+		name       string
+		definition string
+	}{
+		{
+			table:      "appointment_slots",
+			name:       fmt.Sprintf("excl_%s_appointment_slots_doctor_period", schemaName),
+			definition: "EXCLUDE USING gist (doctor_id WITH =, tstzrange(start_at, end_at) WITH &&)",
+		},
+		{
+			table: "appointments",
+			name:  fmt.Sprintf("excl_%s_appointments_patient_booked_period", schemaName),
+			definition: "EXCLUDE USING gist (patient_id WITH =, tstzrange(start_at, end_at) WITH &&) " +
+				"WHERE (status = 'booked' AND deleted_at IS NULL)",
+		},
+	}
+	for _, exclusion := range appointmentExclusions {
+		constraint := fmt.Sprintf(`
+			DO $$
+			BEGIN
+				IF NOT EXISTS (
+					SELECT 1 FROM pg_constraint
+					WHERE conname = '%s' AND conrelid = '%s.%s'::regclass
+				) THEN
+					ALTER TABLE %s.%s ADD CONSTRAINT %s %s;
+				END IF;
+			END
+			$$
+		`, exclusion.name, schemaName, exclusion.table, schemaName, exclusion.table, exclusion.name, exclusion.definition)
+		if err := tx.Exec(constraint).Error; err != nil {
+			return fmt.Errorf("failed to create %s exclusion constraint: %w", exclusion.table, err)
+		}
+	}
+	return nil
+}
+
+// createVitalSignTables creates the vital_signs table. The CHECKs mirror the plausible ranges
+// accepted by the API, so a reading outside them cannot be charted by mistake.
+func createVitalSignTables(tx *gorm.DB, schemaName string) error {
+	vitalTable := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.vital_signs (
+			id SERIAL PRIMARY KEY,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			deleted_at TIMESTAMP WITH TIME ZONE,
+			encounter_id INTEGER NOT NULL REFERENCES %s.encounters(id),
+			patient_id INTEGER NOT NULL REFERENCES %s.patients(id),
+			systolic_bp SMALLINT CHECK (systolic_bp BETWEEN 40 AND 300),
+			diastolic_bp SMALLINT CHECK (diastolic_bp BETWEEN 20 AND 200),
+			pulse SMALLINT CHECK (pulse BETWEEN 20 AND 300),
+			temperature NUMERIC(4,1) CHECK (temperature BETWEEN 30 AND 45),
+			respiratory_rate SMALLINT CHECK (respiratory_rate BETWEEN 4 AND 80),
+			spo2 SMALLINT CHECK (spo2 BETWEEN 50 AND 100),
+			height NUMERIC(5,1) CHECK (height BETWEEN 20 AND 250),
+			weight NUMERIC(5,1) CHECK (weight BETWEEN 0.5 AND 400),
+			bmi NUMERIC(4,1),
+			pain_score SMALLINT CHECK (pain_score BETWEEN 0 AND 10),
+			triage_level SMALLINT CHECK (triage_level BETWEEN 1 AND 5),
+			note VARCHAR(500),
+			recorded_by INTEGER NOT NULL,
+			recorded_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			CHECK ((systolic_bp IS NULL) = (diastolic_bp IS NULL))
+		)
+	`, schemaName, schemaName, schemaName)
+	if err := tx.Exec(vitalTable).Error; err != nil {
+		return fmt.Errorf("failed to create vital_signs table: %w", err)
+	}
+
+	vitalIndexes := []string{
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_vital_signs_deleted_at ON %s.vital_signs(deleted_at)", schemaName, schemaName),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_vital_signs_encounter_id ON %s.vital_signs(encounter_id)", schemaName, schemaName),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_vital_signs_patient_id ON %s.vital_signs(patient_id, recorded_at)", schemaName, schemaName),
+	}
+	for _, index := range vitalIndexes {
+		if err := tx.Exec(index).Error; err != nil {
+			return fmt.Errorf("failed to create vital_signs index: %w", err)
+		}
+	}
+	return nil
+}
+
+// createDiagnosisTables creates the diagnoses table. code refers to the public icd10_codes
+// catalogue; an encounter has one live principal diagnosis and records each code once.
+func createDiagnosisTables(tx *gorm.DB, schemaName string) error {
+	diagnosisTable := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.diagnoses (
+			id SERIAL PRIMARY KEY,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			deleted_at TIMESTAMP WITH TIME ZONE,
+			encounter_id INTEGER NOT NULL REFERENCES %s.encounters(id),
+			patient_id INTEGER NOT NULL REFERENCES %s.patients(id),
+			code VARCHAR(8) NOT NULL REFERENCES public.icd10_codes(code),
+			description VARCHAR(255) NOT NULL,
+			type VARCHAR(20) NOT NULL CHECK (type IN ('principal', 'comorbidity', 'complication', 'external_cause')),
+			note VARCHAR(500),
+			diagnosed_by INTEGER NOT NULL
+		)
+	`, schemaName, schemaName, schemaName)
+	if err := tx.Exec(diagnosisTable).Error; err != nil {
+		return fmt.Errorf("failed to create diagnoses table: %w", err)
+	}
+
+	diagnosisIndexes := []string{
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_diagnoses_deleted_at ON %s.diagnoses(deleted_at)", schemaName, schemaName),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_diagnoses_patient_id ON %s.diagnoses(patient_id)", schemaName, schemaName),
+		fmt.Sprintf("CREATE UNIQUE INDEX IF NOT EXISTS idx_%s_diagnoses_encounter_code ON %s.diagnoses(encounter_id, code) WHERE deleted_at IS NULL", schemaName, schemaName),
+		fmt.Sprintf("CREATE UNIQUE INDEX IF NOT EXISTS idx_%s_diagnoses_principal ON %s.diagnoses(encounter_id) WHERE type = 'principal' AND deleted_at IS NULL", schemaName, schemaName),
+	}
+	for _, index := range diagnosisIndexes {
+		if err := tx.Exec(index).Error; err != nil {
+			return fmt.Errorf("failed to create diagnoses index: %w", err)
+		}
+	}
+	return nil
+}
+
+// createMedicationTables creates the drug master, the interactions between its drugs and the
+// medication orders of encounters. An interaction pair is stored once, lower drug ID first.
+func createMedicationTables(tx *gorm.DB, schemaName string) error {
+	drugTable := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.drugs (
+			id SERIAL PRIMARY KEY,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			deleted_at TIMESTAMP WITH TIME ZONE,
+			code VARCHAR(20) NOT NULL,
+			generic_name VARCHAR(255) NOT NULL,
+			trade_name VARCHAR(255),
+			strength VARCHAR(50),
+			dosage_form VARCHAR(50) NOT NULL,
+			dispensing_unit VARCHAR(20) NOT NULL,
+			therapeutic_class VARCHAR(100) NOT NULL,
+			is_active BOOLEAN NOT NULL DEFAULT TRUE
+		)
+	`, schemaName)
+	if err := tx.Exec(drugTable).Error; err != nil {
+		return fmt.Errorf("failed to create drugs table: %w", err)
+	}
+
+	interactionTable := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.drug_interactions (
+			id SERIAL PRIMARY KEY,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			deleted_at TIMESTAMP WITH TIME ZONE,
+			drug_id INTEGER NOT NULL REFERENCES %s.drugs(id),
+			interacting_drug_id INTEGER NOT NULL REFERENCES %s.drugs(id),
+			severity VARCHAR(20) NOT NULL CHECK (severity IN ('minor', 'moderate', 'major', 'contraindicated')),
+			description VARCHAR(500) NOT NULL,
+			CHECK (drug_id < interacting_drug_id)
+		)
+	`, schemaName, schemaName, schemaName)
+	if err := tx.Exec(interactionTable).Error; err != nil {
+		return fmt.Errorf("failed to create drug_interactions table: %w", err)
+	}
+
+	orderTable := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.medication_orders (
+			id SERIAL PRIMARY KEY,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			deleted_at TIMESTAMP WITH TIME ZONE,
+			encounter_id INTEGER NOT NULL REFERENCES %s.encounters(id),
+			patient_id INTEGER NOT NULL REFERENCES %s.patients(id),
+			drug_id INTEGER NOT NULL REFERENCES %s.drugs(id),
+			drug_name VARCHAR(310) NOT NULL,
+			therapeutic_class VARCHAR(100) NOT NULL,
+			dose NUMERIC(10,3) NOT NULL CHECK (dose > 0),
+			dose_unit VARCHAR(20) NOT NULL,
+			route VARCHAR(20) NOT NULL CHECK (route IN ('oral', 'sublingual', 'topical', 'inhaled', 'nasal', 'ophthalmic', 'otic', 'rectal', 'vaginal', 'iv', 'im', 'sc')),
+			frequency VARCHAR(10) NOT NULL CHECK (frequency IN ('od', 'bid', 'tid', 'qid', 'q4h', 'q6h', 'q8h', 'q12h', 'hs', 'stat', 'prn')),
+			duration_days INTEGER NOT NULL CHECK (duration_days BETWEEN 1 AND 365),
+			quantity NUMERIC(10,2) NOT NULL CHECK (quantity > 0),
+			dispensing_unit VARCHAR(20) NOT NULL,
+			instruction VARCHAR(500),
+			status VARCHAR(20) NOT NULL CHECK (status IN ('ordered', 'verified', 'dispensed', 'cancelled')),
+			alerts JSONB,
+			override_reason VARCHAR(500),
+			ordered_by INTEGER NOT NULL,
+			ordered_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			ends_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			verified_by INTEGER,
+			verified_at TIMESTAMP WITH TIME ZONE,
+			dispensed_by INTEGER,
+			dispensed_at TIMESTAMP WITH TIME ZONE,
+			cancelled_by INTEGER,
+			cancelled_at TIMESTAMP WITH TIME ZONE,
+			cancel_reason VARCHAR(255)
+		)
+	`, schemaName, schemaName, schemaName, schemaName)
+	if err := tx.Exec(orderTable).Error; err != nil {
+		return fmt.Errorf("failed to create medication_orders table: %w", err)
+	}
+
+	medicationIndexes := []string{
+		fmt.Sprintf("CREATE UNIQUE INDEX IF NOT EXISTS idx_%s_drugs_code_live ON %s.drugs(code) WHERE deleted_at IS NULL", schemaName, schemaName),
+		fmt.Sprintf("CREATE UNIQUE INDEX IF NOT EXISTS idx_%s_drug_interactions_pair ON %s.drug_interactions(drug_id, interacting_drug_id) WHERE deleted_at IS NULL", schemaName, schemaName),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_drug_interactions_interacting ON %s.drug_interactions(interacting_drug_id)", schemaName, schemaName),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_medication_orders_deleted_at ON %s.medication_orders(deleted_at)", schemaName, schemaName),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_medication_orders_encounter_id ON %s.medication_orders(encounter_id)", schemaName, schemaName),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_medication_orders_patient_ends_at ON %s.medication_orders(patient_id, ends_at)", schemaName, schemaName),
+	}
+	for _, index := range medicationIndexes {
+		if err := tx.Exec(index).Error; err != nil {
+			return fmt.Errorf("failed to create medication index: %w", err)
+		}
+	}
+	return nil
+}
+
+// createInventoryTables creates the pharmacy stock tables. Lot balances are kept in step with
+// the stock_movements ledger, so they can be rebuilt from it.
+func createInventoryTables(tx *gorm.DB, schemaName string) error {
+	locationTable := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.stock_locations (
+			id SERIAL PRIMARY KEY,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			deleted_at TIMESTAMP WITH TIME ZONE,
+			code VARCHAR(20) NOT NULL,
+			name VARCHAR(255) NOT NULL,
+			is_active BOOLEAN NOT NULL DEFAULT TRUE
+		)
+	`, schemaName)
+	if err := tx.Exec(locationTable).Error; err != nil {
+		return fmt.Errorf("failed to create stock_locations table: %w", err)
+	}
+
+	reorderLevelTable := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.stock_reorder_levels (
+			id SERIAL PRIMARY KEY,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			location_id INTEGER NOT NULL REFERENCES %s.stock_locations(id),
+			drug_id INTEGER NOT NULL REFERENCES %s.drugs(id),
+			reorder_level NUMERIC(12,2) NOT NULL CHECK (reorder_level >= 0),
+			UNIQUE (location_id, drug_id)
+		)
+	`, schemaName, schemaName, schemaName)
+	if err := tx.Exec(reorderLevelTable).Error; err != nil {
+		return fmt.Errorf("failed to create stock_reorder_levels table: %w", err)
+	}
+
+	lotTable := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.stock_lots (
+			id SERIAL PRIMARY KEY,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			location_id INTEGER NOT NULL REFERENCES %s.stock_locations(id),
+			drug_id INTEGER NOT NULL REFERENCES %s.drugs(id),
+			lot_number VARCHAR(50) NOT NULL,
+			expiry_date DATE NOT NULL,
+			quantity NUMERIC(12,2) NOT NULL DEFAULT 0 CHECK (quantity >= 0),
+			UNIQUE (location_id, drug_id, lot_number)
+		)
+	`, schemaName, schemaName, schemaName)
+	if err := tx.Exec(lotTable).Error; err != nil {
+		return fmt.Errorf("failed to create stock_lots table: %w", err)
+	}
+
+	receiptTable := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.goods_receipts (
+			id SERIAL PRIMARY KEY,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			location_id INTEGER NOT NULL REFERENCES %s.stock_locations(id),
+			supplier VARCHAR(255) NOT NULL,
+			reference_number VARCHAR(50) NOT NULL,
+			note VARCHAR(500),
+			received_by INTEGER NOT NULL,
+			received_at TIMESTAMP WITH TIME ZONE NOT NULL
+		)
+	`, schemaName, schemaName)
+	if err := tx.Exec(receiptTable).Error; err != nil {
+		return fmt.Errorf("failed to create goods_receipts table: %w", err)
+	}
+
+	receiptItemTable := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.goods_receipt_items (
+			id SERIAL PRIMARY KEY,
+			goods_receipt_id INTEGER NOT NULL REFERENCES %s.goods_receipts(id),
+			drug_id INTEGER NOT NULL REFERENCES %s.drugs(id),
+			lot_id INTEGER NOT NULL REFERENCES %s.stock_lots(id),
+			lot_number VARCHAR(50) NOT NULL,
+			expiry_date DATE NOT NULL,
+			quantity NUMERIC(12,2) NOT NULL CHECK (quantity > 0),
+			unit_cost NUMERIC(12,2) CHECK (unit_cost >= 0)
+		)
+	`, schemaName, schemaName, schemaName, schemaName)
+	if err := tx.Exec(receiptItemTable).Error; err != nil {
+		return fmt.Errorf("failed to create goods_receipt_items table: %w", err)
+	}
+
+	movementTable := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.stock_movements (
+			id BIGSERIAL PRIMARY KEY,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			location_id INTEGER NOT NULL REFERENCES %s.stock_locations(id),
+			drug_id INTEGER NOT NULL REFERENCES %s.drugs(id),
+			lot_id INTEGER NOT NULL REFERENCES %s.stock_lots(id),
+			lot_number VARCHAR(50) NOT NULL,
+			type VARCHAR(20) NOT NULL CHECK (type IN ('receipt', 'dispense', 'adjustment')),
+			quantity NUMERIC(12,2) NOT NULL CHECK (quantity <> 0),
+			lot_balance NUMERIC(12,2) NOT NULL CHECK (lot_balance >= 0),
+			goods_receipt_id INTEGER REFERENCES %s.goods_receipts(id),
+			medication_order_id INTEGER REFERENCES %s.medication_orders(id),
+			reason VARCHAR(255),
+			performed_by INTEGER NOT NULL
+		)
+	`, schemaName, schemaName, schemaName, schemaName, schemaName, schemaName)
+	if err := tx.Exec(movementTable).Error; err != nil {
+		return fmt.Errorf("failed to create stock_movements table: %w", err)
+	}
+
+	inventoryIndexes := []string{
+		fmt.Sprintf("CREATE UNIQUE INDEX IF NOT EXISTS idx_%s_stock_locations_code_live ON %s.stock_locations(code) WHERE deleted_at IS NULL", schemaName, schemaName),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_stock_locations_deleted_at ON %s.stock_locations(deleted_at)", schemaName, schemaName),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_stock_lots_drug_expiry ON %s.stock_lots(drug_id, expiry_date)", schemaName, schemaName),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_goods_receipts_received_at ON %s.goods_receipts(received_at)", schemaName, schemaName),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_goods_receipt_items_receipt ON %s.goods_receipt_items(goods_receipt_id)", schemaName, schemaName),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_stock_movements_location_drug ON %s.stock_movements(location_id, drug_id, created_at)", schemaName, schemaName),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_stock_movements_lot ON %s.stock_movements(lot_id)", schemaName, schemaName),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_stock_movements_order ON %s.stock_movements(medication_order_id) WHERE medication_order_id IS NOT NULL", schemaName, schemaName),
+	}
+	for _, index := range inventoryIndexes {
+		if err := tx.Exec(index).Error; err != nil {
+			return fmt.Errorf("failed to create inventory index: %w", err)
+		}
+	}
+
+	return protectStockLedger(tx, schemaName)
+}
+
+// createLabTables creates the lab test catalogue, lab orders with their specimens and tests, and
+// the daily counter specimen barcodes are drawn from
+func createLabTables(tx *gorm.DB, schemaName string) error {
+	counterTable := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.lab_specimen_counters (
+			period VARCHAR(20) PRIMARY KEY,
+			last_value BIGINT NOT NULL DEFAULT 0,
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+		)
+	`, schemaName)
+	if err := tx.Exec(counterTable).Error; err != nil {
+		return fmt.Errorf("failed to create lab_specimen_counters table: %w", err)
+	}
+
+	testTable := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.lab_tests (
+			id SERIAL PRIMARY KEY,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			deleted_at TIMESTAMP WITH TIME ZONE,
+			code VARCHAR(20) NOT NULL,
+			name VARCHAR(255) NOT NULL,
+			specimen_type VARCHAR(20) NOT NULL CHECK (specimen_type IN ('blood', 'serum', 'plasma', 'urine', 'stool', 'csf', 'swab', 'sputum', 'other')),
+			result_type VARCHAR(10) NOT NULL CHECK (result_type IN ('numeric', 'text')),
+			unit VARCHAR(20),
+			normal_text VARCHAR(100),
+			reference_ranges JSONB,
+			is_active BOOLEAN NOT NULL DEFAULT TRUE
+		)
+	`, schemaName)
+	if err := tx.Exec(testTable).Error; err != nil {
+		return fmt.Errorf("failed to create lab_tests table: %w", err)
+	}
+
+	orderTable := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.lab_orders (
+			id SERIAL PRIMARY KEY,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			deleted_at TIMESTAMP WITH TIME ZONE,
+			encounter_id INTEGER NOT NULL REFERENCES %s.encounters(id),
+			patient_id INTEGER NOT NULL REFERENCES %s.patients(id),
+			priority VARCHAR(10) NOT NULL CHECK (priority IN ('routine', 'urgent', 'stat')),
+			clinical_note VARCHAR(500),
+			status VARCHAR(20) NOT NULL CHECK (status IN ('ordered', 'collected', 'received', 'completed', 'cancelled')),
+			ordered_by INTEGER NOT NULL,
+			ordered_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			completed_at TIMESTAMP WITH TIME ZONE,
+			cancelled_by INTEGER,
+			cancelled_at TIMESTAMP WITH TIME ZONE,
+			cancel_reason VARCHAR(255)
+		)
+	`, schemaName, schemaName, schemaName)
+	if err := tx.Exec(orderTable).Error; err != nil {
+		return fmt.Errorf("failed to create lab_orders table: %w", err)
+	}
+
+	specimenTable := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.lab_specimens (
+			id SERIAL PRIMARY KEY,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			order_id INTEGER NOT NULL REFERENCES %s.lab_orders(id),
+			barcode VARCHAR(20) NOT NULL UNIQUE,
+			specimen_type VARCHAR(20) NOT NULL,
+			status VARCHAR(20) NOT NULL CHECK (status IN ('pending', 'collected', 'received', 'rejected')),
+			collected_by INTEGER,
+			collected_at TIMESTAMP WITH TIME ZONE,
+			received_by INTEGER,
+			received_at TIMESTAMP WITH TIME ZONE,
+			rejected_by INTEGER,
+			rejected_at TIMESTAMP WITH TIME ZONE,
+			reject_reason VARCHAR(255)
+		)
+	`, schemaName, schemaName)
+	if err := tx.Exec(specimenTable).Error; err != nil {
+		return fmt.Errorf("failed to create lab_specimens table: %w", err)
+	}
+
+	itemTable := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.lab_order_items (
+			id SERIAL PRIMARY KEY,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			order_id INTEGER NOT NULL REFERENCES %s.lab_orders(id),
+			specimen_id INTEGER NOT NULL REFERENCES %s.lab_specimens(id),
+			test_id INTEGER NOT NULL REFERENCES %s.lab_tests(id),
+			test_code VARCHAR(20) NOT NULL,
+			test_name VARCHAR(255) NOT NULL,
+			specimen_type VARCHAR(20) NOT NULL,
+			result_type VARCHAR(10) NOT NULL,
+			unit VARCHAR(20),
+			status VARCHAR(20) NOT NULL CHECK (status IN ('pending', 'resulted', 'corrected')),
+			value VARCHAR(100),
+			numeric_value DOUBLE PRECISION,
+			reference_range VARCHAR(100),
+			flag VARCHAR(2) CHECK (flag IN ('', 'N', 'L', 'H', 'LL', 'HH', 'A')),
+			result_source VARCHAR(20),
+			instrument VARCHAR(100),
+			resulted_by INTEGER,
+			resulted_at TIMESTAMP WITH TIME ZONE
+		)
+	`, schemaName, schemaName, schemaName, schemaName)
+	if err := tx.Exec(itemTable).Error; err != nil {
+		return fmt.Errorf("failed to create lab_order_items table: %w", err)
+	}
+
+	labIndexes := []string{
+		fmt.Sprintf("CREATE UNIQUE INDEX IF NOT EXISTS idx_%s_lab_tests_code_live ON %s.lab_tests(code) WHERE deleted_at IS NULL", schemaName, schemaName),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_lab_orders_deleted_at ON %s.lab_orders(deleted_at)", schemaName, schemaName),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_lab_orders_encounter_id ON %s.lab_orders(encounter_id)", schemaName, schemaName),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_lab_orders_patient_ordered_at ON %s.lab_orders(patient_id, ordered_at)", schemaName, schemaName),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_lab_orders_ordered_at ON %s.lab_orders(ordered_at)", schemaName, schemaName),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_lab_specimens_order_id ON %s.lab_specimens(order_id)", schemaName, schemaName),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_lab_order_items_order_id ON %s.lab_order_items(order_id)", schemaName, schemaName),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_lab_order_items_specimen_id ON %s.lab_order_items(specimen_id)", schemaName, schemaName),
+	}
+	for _, index := range labIndexes {
+		if err := tx.Exec(index).Error; err != nil {
+			return fmt.Errorf("failed to create lab index: %w", err)
+		}
+	}
+	return nil
+}
+
+// protectStockLedger installs triggers that reject UPDATE, DELETE and TRUNCATE on stock_movements
+func protectStockLedger(tx *gorm.DB, schemaName string) error {
+	statements := []string{
+		fmt.Sprintf(`
+			CREATE OR REPLACE FUNCTION %s.stock_movements_append_only() RETURNS trigger AS $$
+			BEGIN
+				RAISE EXCEPTION 'stock_movements is append-only';
+			END;
+			$$ LANGUAGE plpgsql
+		`, schemaName),
+		fmt.Sprintf("DROP TRIGGER IF EXISTS stock_movements_no_modify ON %s.stock_movements", schemaName),
+		fmt.Sprintf(`
+			CREATE TRIGGER stock_movements_no_modify BEFORE UPDATE OR DELETE ON %s.stock_movements
+			FOR EACH ROW EXECUTE FUNCTION %s.stock_movements_append_only()
+		`, schemaName, schemaName),
+		fmt.Sprintf("DROP TRIGGER IF EXISTS stock_movements_no_truncate ON %s.stock_movements", schemaName),
+		fmt.Sprintf(`
+			CREATE TRIGGER stock_movements_no_truncate BEFORE TRUNCATE ON %s.stock_movements
+			FOR EACH STATEMENT EXECUTE FUNCTION %s.stock_movements_append_only()
+		`, schemaName, schemaName),
+	}
+	for _, statement := range statements {
+		if err := tx.Exec(statement).Error; err != nil {
+			return fmt.Errorf("failed to protect stock_movements table: %w", err)
+		}
+	}
+	return nil
+}
+
+// createAuditTables creates the hash-chained audit_events table.
+// patient_id has no foreign key so the trail outlives purged patient records.
+func createAuditTables(tx *gorm.DB, schemaName string) error {
+	auditTable := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.audit_events (
+			id BIGSERIAL PRIMARY KEY,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			actor_id INTEGER NOT NULL,
+			actor_username VARCHAR(100),
+			action VARCHAR(50) NOT NULL,
+			patient_id INTEGER,
+			changes TEXT,
+			client_ip VARCHAR(45),
+			request_id VARCHAR(64),
+			prev_hash VARCHAR(64) NOT NULL,
+			hash VARCHAR(64) UNIQUE NOT NULL
+		)
+	`, schemaName)
+	if err := tx.Exec(auditTable).Error; err != nil {
+		return fmt.Errorf("failed to create audit_events table: %w", err)
+	}
+
+	auditIndexes := []string{
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_audit_events_patient_id ON %s.audit_events(patient_id)", schemaName, schemaName),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_audit_events_actor_id ON %s.audit_events(actor_id)", schemaName, schemaName),
+	}
+	for _, index := range auditIndexes {
+		if err := tx.Exec(index).Error; err != nil {
+			return fmt.Errorf("failed to create audit_events index: %w", err)
+		}
+	}
+
+	return protectAuditTable(tx, schemaName)
+}
+
+// protectAuditTable installs triggers that reject UPDATE, DELETE and TRUNCATE on audit_events
+func protectAuditTable(tx *gorm.DB, schemaName string) error {
+	statements := []string{
+		fmt.Sprintf(`
+			CREATE OR REPLACE FUNCTION %s.audit_events_append_only() RETURNS trigger AS $$
+			BEGIN
+				RAISE EXCEPTION 'audit_events is append-only';
+			END;
+			$$ LANGUAGE plpgsql
+		`, schemaName),
+		fmt.Sprintf("DROP TRIGGER IF EXISTS audit_events_no_modify ON %s.audit_events", schemaName),
+		fmt.Sprintf(`
+			CREATE TRIGGER audit_events_no_modify BEFORE UPDATE OR DELETE ON %s.audit_events
+			FOR EACH ROW EXECUTE FUNCTION %s.audit_events_append_only()
+		`, schemaName, schemaName),
+		fmt.Sprintf("DROP TRIGGER IF EXISTS audit_events_no_truncate ON %s.audit_events", schemaName),
+		fmt.Sprintf(`
+			CREATE TRIGGER audit_events_no_truncate BEFORE TRUNCATE ON %s.audit_events
+			FOR EACH STATEMENT EXECUTE FUNCTION %s.audit_events_append_only()
+		`, schemaName, schemaName),
+	}
+	for _, statement := range statements {
+		if err := tx.Exec(statement).Error; err != nil {
+			return fmt.Errorf("failed to protect audit_events table: %w", err)
+		}
+	}
+	return nil
+}
+
+// createRoleTables creates the roles, permissions and their join tables
+func createRoleTables(tx *gorm.DB, schemaName string) error {
+	permissionTable := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.permissions (
+			id SERIAL PRIMARY KEY,
+			code VARCHAR(100) UNIQUE NOT NULL,
+			description VARCHAR(255)
+		)
+	`, schemaName)
+	if err := tx.Exec(permissionTable).Error; err != nil {
+		return fmt.Errorf("failed to create permissions table: %w", err)
+	}
+
+	roleTable := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.roles (
+			id SERIAL PRIMARY KEY,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			deleted_at TIMESTAMP WITH TIME ZONE,
+			code VARCHAR(50) UNIQUE NOT NULL,
+			name VARCHAR(100) NOT NULL,
+			description VARCHAR(255),
+			is_system BOOLEAN DEFAULT FALSE
+		)
+	`, schemaName)
+	if err := tx.Exec(roleTable).Error; err != nil {
+		return fmt.Errorf("failed to create roles table: %w", err)
+	}
+
+	rolePermissionTable := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.role_permissions (
+			role_id INTEGER NOT NULL REFERENCES %s.roles(id),
+			permission_id INTEGER NOT NULL REFERENCES %s.permissions(id),
+			PRIMARY KEY (role_id, permission_id)
+		)
+	`, schemaName, schemaName, schemaName)
+	if err := tx.Exec(rolePermissionTable).Error; err != nil {
+		return fmt.Errorf("failed to create role_permissions table: %w", err)
+	}
+
+	staffRoleTable := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.staff_roles (
+			staff_id INTEGER NOT NULL REFERENCES %s.staffs(id),
+			role_id INTEGER NOT NULL REFERENCES %s.roles(id),
+			PRIMARY KEY (staff_id, role_id)
+		)
+	`, schemaName, schemaName, schemaName)
+	if err := tx.Exec(staffRoleTable).Error; err != nil {
+		return fmt.Errorf("failed to create staff_roles table: %w", err)
+	}
+
+	return nil
+}
+
+// purgeDeletedRoles removes custom roles soft-deleted before roles were hard-deleted,
+// which would otherwise keep their codes taken by the unique index
+func purgeDeletedRoles(tx *gorm.DB, schemaName string) error {
+	query := fmt.Sprintf("DELETE FROM %s.roles WHERE deleted_at IS NOT NULL AND is_system = FALSE", schemaName)
+	if err := tx.Exec(query).Error; err != nil {
+		return fmt.Errorf("failed to purge deleted roles: %w", err)
+	}
+	return nil
+}
+
+// seedRolesAndPermissions inserts every known permission and the built-in system roles.
+// It is idempotent so it can run on every tenant migration and pick up new permissions.
+func seedRolesAndPermissions(tx *gorm.DB, schemaName string) error {
+	for _, permission := range domain.AllPermissions {
+		insertPermission := fmt.Sprintf(`
+			INSERT INTO %s.permissions (code, description) VALUES ($1, $2)
+			ON CONFLICT (code) DO UPDATE SET description = EXCLUDED.description
+		`, schemaName)
+		if err := tx.Exec(insertPermission, permission.Code, permission.Description).Error; err != nil {
+			return fmt.Errorf("failed to seed permission %s: %w", permission.Code, err)
+		}
+	}
+
+	for _, role := range domain.DefaultRoles {
+		insertRole := fmt.Sprintf(`
+			INSERT INTO %s.roles (code, name, is_system) VALUES ($1, $2, TRUE)
+			ON CONFLICT (code) DO NOTHING
+		`, schemaName)
+		if err := tx.Exec(insertRole, role.Code, role.Name).Error; err != nil {
+			return fmt.Errorf("failed to seed role %s: %w", role.Code, err)
+		}
+
+		grantPermissions := fmt.Sprintf(`
+			INSERT INTO %s.role_permissions (role_id, permission_id)
+			SELECT r.id, p.id FROM %s.roles r, %s.permissions p
+			WHERE r.code = ? AND p.code IN ?
+			ON CONFLICT DO NOTHING
+		`, schemaName, schemaName, schemaName)
+		if err := tx.Exec(grantPermissions, role.Code, role.Permissions).Error; err != nil {
+			return fmt.Errorf("failed to seed permissions for role %s: %w", role.Code, err)
+		}
+	}
+
+	return nil
+}
+
+// backfillAdminRoles grants the admin role to staff flagged is_admin before roles replaced the
+// flag, so upgraded tenants keep someone able to manage roles. Schemas created since have no
+// is_admin column and are left alone; the grant is idempotent so it can run on every migration.
+func backfillAdminRoles(tx *gorm.DB, schemaName string) error {
+	var columns int64
+	if err := tx.Raw(`
+		SELECT COUNT(*) FROM information_schema.columns
+		WHERE table_schema = ? AND table_name = 'staffs' AND column_name = 'is_admin'
+	`, schemaName).Scan(&columns).Error; err != nil {
+		return fmt.Errorf("failed to check staffs.is_admin: %w", err)
+	}
+	if columns == 0 {
+		return nil
+	}
+
+	grantAdmin := fmt.Sprintf(`
+		INSERT INTO %s.staff_roles (staff_id, role_id)
+		SELECT s.id, r.id FROM %s.staffs s JOIN %s.roles r ON r.code = ?
+		WHERE s.is_admin = TRUE
+		ON CONFLICT DO NOTHING
+	`, schemaName, schemaName, schemaName)
+	if err := tx.Exec(grantAdmin, domain.RoleAdmin).Error; err != nil {
+		return fmt.Errorf("failed to grant admin role to is_admin staff: %w", err)
+	}
+	return nil
+}
+
 // createAdminInSchema creates an admin user in the specified tenant schema
 func (s *tenantService) createAdminInSchema(
 	tx *gorm.DB,
@@ -296,11 +1717,13 @@ func (s *tenantService) createAdminInSchema(
 	insertSQL := fmt.Sprintf(`
 		INSERT INTO %s.staffs (
 			username, password, staff_code, phone_number, email,
-			first_name, last_name, is_admin
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			first_name, last_name
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
 	`, schemaName)
 
-	return tx.Exec(
+	var adminID uint
+	if err := tx.Raw(
 		insertSQL,
 		adminUsername,
 		string(hashedPassword),
@@ -309,8 +1732,17 @@ func (s *tenantService) createAdminInSchema(
 		adminEmail,
 		"Admin",
 		tenant.Name,
-		true,
-	).Error
+	).Scan(&adminID).Error; err != nil {
+		return err
+	}
+
+	// Attach the built-in admin role, the only source of the admin's privileges
+	assignRoleSQL := fmt.Sprintf(`
+		INSERT INTO %s.staff_roles (staff_id, role_id)
+		SELECT $1, id FROM %s.roles WHERE code = $2
+	`, schemaName, schemaName)
+
+	return tx.Exec(assignRoleSQL, adminID, domain.RoleAdmin).Error
 }
 
 // generateSchemaName generates a valid schema name from tenant code
@@ -342,23 +1774,12 @@ func sanitizeSchemaName(name string) string {
 	return name
 }
 
-// GenerateHN generates a new HN in format 'hospitalCode-HNRunning'
-// Example: "HOSP0001-00000001"
-func (s *tenantService) GenerateHN(schemaName string) (string, error) {
-	// Get tenant by schema name
+// GetHNIssuer loads the HN template and hospital code of the tenant. The HN itself is issued
+// by the patient repository, in the transaction that inserts the patient.
+func (s *tenantService) GetHNIssuer(schemaName string) (*domain.HNIssuer, error) {
 	tenant, err := s.tenantRepo.GetBySchemaName(schemaName)
 	if err != nil {
-		return "", fmt.Errorf("failed to get tenant: %w", err)
+		return nil, fmt.Errorf("failed to get tenant: %w", err)
 	}
-
-	// Atomically increment HN running number
-	newHN, err := s.tenantRepo.IncrementHNRunning(tenant.ID)
-	if err != nil {
-		return "", fmt.Errorf("failed to increment HN running: %w", err)
-	}
-
-	// Format: hospitalCode-HNRunning (8 digits padded)
-	hn := fmt.Sprintf("%s-%08d", tenant.HospitalCode, newHN)
-
-	return hn, nil
+	return domain.NewHNIssuer(tenant)
 }
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"time"
+
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+type trashService struct {
+	patientRepo domain.PatientRepository
+	staffRepo   domain.StaffRepository
+	retention   time.Duration
+}
+
+// NewTrashService creates the trash service; records are purged once deleted for longer than retention
+func NewTrashService(patientRepo domain.PatientRepository, staffRepo domain.StaffRepository, retention time.Duration) domain.TrashService {
+	return &trashService{
+		patientRepo: patientRepo,
+		staffRepo:   staffRepo,
+		retention:   retention,
+	}
+}
+
+func (s *trashService) ListPatients(schemaName string) ([]domain.Patient, error) {
+	patients, err := s.patientRepo.ListDeleted(nil, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return patients, nil
+}
+
+// RestorePatient brings a deleted patient back, unless a live patient has taken one of its identifiers
+func (s *trashService) RestorePatient(id uint, actor *domain.Actor, schemaName string) (*domain.Patient, error) {
+	patient, err := s.patientRepo.GetDeleted(id, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	live, err := s.patientRepo.FindLiveByUniqueFields(patient, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	if conflicts := domain.PatientRestoreConflicts(patient, live); len(conflicts) > 0 {
+		return nil, &domain.RestoreConflictError{Conflicts: conflicts}
+	}
+
+	event, err := domain.NewAuditEvent(actor, domain.AuditActionPatientRestore, &patient.ID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// The live unique indexes still reject a value taken concurrently
+	if err := s.patientRepo.Restore(id, event, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+
+	patient.DeletedAt.Valid = false
+	return patient, nil
+}
+
+// PurgePatients permanently removes patients deleted longer ago than the retention period
+func (s *trashService) PurgePatients(actor *domain.Actor, schemaName string) (*domain.PurgeResult, error) {
+	before := time.Now().Add(-s.retention)
+
+	expired, err := s.patientRepo.ListDeleted(&before, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	ids := make([]uint, 0, len(expired))
+	events := make([]*domain.AuditEvent, 0, len(expired))
+	for i := range expired {
+		patient := &expired[i]
+		event, err := domain.NewAuditEvent(actor, domain.AuditActionPatientPurge, &patient.ID,
+			map[string]domain.FieldChange{"patient_hn": {Before: patient.PatientHN}})
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, patient.ID)
+		events = append(events, event)
+	}
+
+	purged, retained, err := s.patientRepo.Purge(ids, before, events, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	if retained == nil {
+		retained = []domain.PurgeRetention{}
+	}
+	return &domain.PurgeResult{Purged: purged, Retained: retained, DeletedBefore: before}, nil
+}
+
+func (s *trashService) ListStaff(schemaName string) ([]domain.Staff, error) {
+	staffs, err := s.staffRepo.ListDeleted(nil, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return staffs, nil
+}
+
+// RestoreStaff brings a deleted staff member back, unless live staff have taken one of their unique fields
+func (s *trashService) RestoreStaff(id uint, schemaName string) (*domain.Staff, error) {
+	staff, err := s.staffRepo.GetDeleted(id, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	live, err := s.staffRepo.FindLiveByUniqueFields(staff, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	if conflicts := domain.StaffRestoreConflicts(staff, live); len(conflicts) > 0 {
+		return nil, &domain.RestoreConflictError{Conflicts: conflicts}
+	}
+
+	if err := s.staffRepo.Restore(id, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+
+	staff.DeletedAt.Valid = false
+	return staff, nil
+}
+
+// PurgeStaff permanently removes staff deleted longer ago than the retention period
+func (s *trashService) PurgeStaff(schemaName string) (*domain.PurgeResult, error) {
+	before := time.Now().Add(-s.retention)
+
+	expired, err := s.staffRepo.ListDeleted(&before, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	ids := make([]uint, len(expired))
+	for i := range expired {
+		ids[i] = expired[i].ID
+	}
+
+	purged, retained, err := s.staffRepo.Purge(ids, before, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	if retained == nil {
+		retained = []domain.PurgeRetention{}
+	}
+	return &domain.PurgeResult{Purged: purged, Retained: retained, DeletedBefore: before}, nil
+}
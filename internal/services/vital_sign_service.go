@@ -0,0 +1,236 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+type vitalSignService struct {
+	vitalRepo     domain.VitalSignRepository
+	encounterRepo domain.EncounterRepository
+	patientRepo   domain.PatientRepository
+	auditRepo     domain.AuditRepository
+}
+
+// NewVitalSignService creates the service for vital signs and triage levels taken during encounters
+func NewVitalSignService(vitalRepo domain.VitalSignRepository, encounterRepo domain.EncounterRepository, patientRepo domain.PatientRepository, auditRepo domain.AuditRepository) domain.VitalSignService {
+	return &vitalSignService{
+		vitalRepo:     vitalRepo,
+		encounterRepo: encounterRepo,
+		patientRepo:   patientRepo,
+		auditRepo:     auditRepo,
+	}
+}
+
+func (s *vitalSignService) Record(encounterID uint, req *domain.VitalSignRequest, actor *domain.Actor, schemaName string) (*domain.VitalSign, error) {
+	if err := validateVitalSignRequest(req); err != nil {
+		return nil, err
+	}
+	encounter, err := s.encounterRepo.GetByID(encounterID, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	if encounter.Status == domain.EncounterStatusDone {
+		return nil, fmt.Errorf("%w: encounter %s is done", domain.ErrInvalidInput, encounter.VN)
+	}
+	patient, err := s.patientRepo.GetByID(encounter.PatientID, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	vital := &domain.VitalSign{
+		EncounterID:     encounter.ID,
+		PatientID:       encounter.PatientID,
+		SystolicBP:      req.SystolicBP,
+		DiastolicBP:     req.DiastolicBP,
+		Pulse:           req.Pulse,
+		Temperature:     req.Temperature,
+		RespiratoryRate: req.RespiratoryRate,
+		SpO2:            req.SpO2,
+		Height:          req.Height,
+		Weight:          req.Weight,
+		PainScore:       req.PainScore,
+		TriageLevel:     req.TriageLevel,
+		Note:            strings.TrimSpace(req.Note),
+		RecordedAt:      time.Now(),
+	}
+	if actor != nil {
+		vital.RecordedBy = actor.StaffID
+	}
+
+	// Height is rarely re-measured, so BMI falls back to the last height on record
+	if vital.Weight != nil {
+		height := vital.Height
+		if height == nil {
+			if height, err = s.vitalRepo.LatestHeight(vital.PatientID, schemaName); err != nil {
+				return nil, wrapError(err)
+			}
+		}
+		if height != nil {
+			bmi := domain.ComputeBMI(*vital.Weight, *height)
+			vital.BMI = &bmi
+		}
+	}
+	vital.Evaluate(patient.DateOfBirth)
+
+	changes := map[string]domain.FieldChange{
+		"vn": {After: encounter.VN},
+	}
+	for code, value := range vital.Values() {
+		changes[code] = domain.FieldChange{After: value}
+	}
+	if vital.TriageLevel != nil {
+		changes["triage_level"] = domain.FieldChange{After: *vital.TriageLevel}
+	}
+	event, err := domain.NewAuditEvent(actor, domain.AuditActionPatientVitalsRecord, &vital.PatientID, changes)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.vitalRepo.Create(vital, event, schemaName); err != nil {
+		return nil, wrapError(err)
+	}
+	return vital, nil
+}
+
+func (s *vitalSignService) ListByEncounter(encounterID uint, actor *domain.Actor, schemaName string) ([]domain.VitalSign, error) {
+	encounter, err := s.encounterRepo.GetByID(encounterID, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	patient, err := s.patientRepo.GetByID(encounter.PatientID, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	vitals, err := s.vitalRepo.ListByEncounter(encounter.ID, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	for i := range vitals {
+		vitals[i].Evaluate(patient.DateOfBirth)
+	}
+
+	if err := s.recordView(actor, patient.ID, schemaName); err != nil {
+		return nil, err
+	}
+	return vitals, nil
+}
+
+// ListByPatient returns the time series of a patient's readings, oldest first
+func (s *vitalSignService) ListByPatient(patientID uint, req *domain.VitalSignListRequest, actor *domain.Actor, schemaName string) ([]domain.VitalSign, error) {
+	patient, err := s.patientRepo.GetByID(patientID, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	filter := &domain.VitalSignFilter{PatientID: patientID}
+	if req.From != "" {
+		from, err := parseCalendarDay(req.From)
+		if err != nil {
+			return nil, err
+		}
+		from = thaiMidnight(from)
+		filter.From = &from
+	}
+	if req.To != "" {
+		to, err := parseCalendarDay(req.To)
+		if err != nil {
+			return nil, err
+		}
+		// to is inclusive, so the range ends at the start of the next day
+		to = thaiMidnight(to).AddDate(0, 0, 1)
+		filter.To = &to
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, fmt.Errorf("%w: from must not be after to", domain.ErrInvalidInput)
+	}
+
+	vitals, err := s.vitalRepo.ListByPatient(filter, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	for i := range vitals {
+		vitals[i].Evaluate(patient.DateOfBirth)
+	}
+
+	if err := s.recordView(actor, patientID, schemaName); err != nil {
+		return nil, err
+	}
+	return vitals, nil
+}
+
+// Trend returns the latest readings of every measure the patient has, oldest first per measure
+func (s *vitalSignService) Trend(patientID uint, req *domain.VitalTrendRequest, actor *domain.Actor, schemaName string) ([]domain.VitalTrend, error) {
+	patient, err := s.patientRepo.GetByID(patientID, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = domain.DefaultVitalTrendLimit
+	}
+	if limit > domain.MaxVitalTrendLimit {
+		limit = domain.MaxVitalTrendLimit
+	}
+
+	points, err := s.vitalRepo.Trend(patientID, limit, schemaName)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	byMeasure := make(map[string][]domain.VitalTrendPoint)
+	for _, point := range points {
+		ranges := domain.VitalRanges(patient.DateOfBirth, point.RecordedAt)
+		point.Flag = ranges[point.Measure].Flag(point.Value)
+		byMeasure[point.Measure] = append(byMeasure[point.Measure], point)
+	}
+	trends := make([]domain.VitalTrend, 0, len(byMeasure))
+	for _, measure := range domain.VitalMeasures {
+		if measurePoints, ok := byMeasure[measure.Code]; ok {
+			trends = append(trends, domain.VitalTrend{Measure: measure.Code, Unit: measure.Unit, Points: measurePoints})
+		}
+	}
+
+	if err := s.recordView(actor, patientID, schemaName); err != nil {
+		return nil, err
+	}
+	return trends, nil
+}
+
+// recordView appends a view event for the patient whose readings were shown
+func (s *vitalSignService) recordView(actor *domain.Actor, patientID uint, schemaName string) error {
+	event, err := domain.NewAuditEvent(actor, domain.AuditActionPatientVitalsView, &patientID, nil)
+	if err != nil {
+		return err
+	}
+	if err := s.auditRepo.Append([]*domain.AuditEvent{event}, schemaName); err != nil {
+		return fmt.Errorf("failed to record audit event: %w", err)
+	}
+	return nil
+}
+
+// validateVitalSignRequest requires at least one measure and a complete, plausible blood pressure
+func validateVitalSignRequest(req *domain.VitalSignRequest) error {
+	if req.SystolicBP == nil && req.DiastolicBP == nil && req.Pulse == nil && req.Temperature == nil &&
+		req.RespiratoryRate == nil && req.SpO2 == nil && req.Height == nil && req.Weight == nil &&
+		req.PainScore == nil && req.TriageLevel == nil {
+		return fmt.Errorf("%w: at least one measure or a triage level is required", domain.ErrInvalidInput)
+	}
+	if (req.SystolicBP == nil) != (req.DiastolicBP == nil) {
+		return fmt.Errorf("%w: systolic_bp and diastolic_bp must be recorded together", domain.ErrInvalidInput)
+	}
+	if req.SystolicBP != nil && *req.SystolicBP <= *req.DiastolicBP {
+		return fmt.Errorf("%w: systolic_bp must be higher than diastolic_bp", domain.ErrInvalidInput)
+	}
+	return nil
+}
+
+// thaiMidnight returns the start of a calendar day from parseCalendarDay in Thai time
+func thaiMidnight(day time.Time) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, domain.HNTimeZone)
+}
@@ -1,6 +1,9 @@
 package jwt
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"time"
 
@@ -8,45 +11,78 @@ import (
 )
 
 type JWTService interface {
-	GenerateToken(userID uint, username string, isAdmin bool, schemaName string) (string, error)
+	GenerateToken(userID uint, username string, isAdmin bool, roles []string, permissions []string, schemaName string) (*AccessToken, error)
 	ValidateToken(token string) (*Claims, error)
+	// GenerateRefreshToken returns a new opaque refresh token and its expiry
+	GenerateRefreshToken() (string, time.Time, error)
 }
 
 type Claims struct {
-	UserID     uint   `json:"user_id"`
-	Username   string `json:"username"`
-	IsAdmin    bool   `json:"is_admin"`
-	TenantID   uint   `json:"tenant_id"`
-	SchemaName string `json:"schema_name"` // Tenant schema for multi-tenancy
+	UserID      uint     `json:"user_id"`
+	Username    string   `json:"username"`
+	IsAdmin     bool     `json:"is_admin"`
+	Roles       []string `json:"roles"`
+	Permissions []string `json:"permissions"` // Snapshot of role permissions at issue time
+	TenantID    uint     `json:"tenant_id"`
+	SchemaName  string   `json:"schema_name"` // Tenant schema for multi-tenancy
 	jwt.RegisteredClaims
 }
 
+// AccessToken is a signed access token together with its jti and expiry
+type AccessToken struct {
+	Token     string
+	ID        string
+	ExpiresAt time.Time
+}
+
 type jwtService struct {
-	secretKey string
-	expiresIn time.Duration
+	secretKey        string
+	expiresIn        time.Duration
+	refreshExpiresIn time.Duration
 }
 
-func NewJWTService(secretKey string, expiresIn time.Duration) JWTService {
+func NewJWTService(secretKey string, expiresIn time.Duration, refreshExpiresIn time.Duration) JWTService {
 	return &jwtService{
-		secretKey: secretKey,
-		expiresIn: expiresIn,
+		secretKey:        secretKey,
+		expiresIn:        expiresIn,
+		refreshExpiresIn: refreshExpiresIn,
 	}
 }
 
-func (s *jwtService) GenerateToken(userID uint, username string, isAdmin bool, schemaName string) (string, error) {
+func (s *jwtService) GenerateToken(userID uint, username string, isAdmin bool, roles []string, permissions []string, schemaName string) (*AccessToken, error) {
+	tokenID, err := randomHex(16)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(s.expiresIn)
+
 	claims := &Claims{
-		UserID:     userID,
-		Username:   username,
-		IsAdmin:    isAdmin,
-		SchemaName: schemaName,
+		UserID:      userID,
+		Username:    username,
+		IsAdmin:     isAdmin,
+		Roles:       roles,
+		Permissions: permissions,
+		SchemaName:  schemaName,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(s.expiresIn)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ID:        tokenID,
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(s.secretKey))
+	signed, err := token.SignedString([]byte(s.secretKey))
+	if err != nil {
+		return nil, err
+	}
+
+	return &AccessToken{
+		Token:     signed,
+		ID:        tokenID,
+		ExpiresAt: expiresAt,
+	}, nil
 }
 
 func (s *jwtService) ValidateToken(tokenString string) (*Claims, error) {
@@ -67,3 +103,27 @@ func (s *jwtService) ValidateToken(tokenString string) (*Claims, error) {
 
 	return nil, errors.New("invalid token")
 }
+
+func (s *jwtService) GenerateRefreshToken() (string, time.Time, error) {
+	token, err := randomHex(32)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return token, time.Now().Add(s.refreshExpiresIn), nil
+}
+
+// HashToken returns the SHA-256 hex digest of a token.
+// Refresh tokens are stored hashed so a database leak does not expose usable tokens.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// randomHex returns n random bytes encoded as a hex string
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -8,9 +8,19 @@ type Response struct {
 	Success bool        `json:"success"`
 	Message string      `json:"message,omitempty"`
 	Data    interface{} `json:"data,omitempty"`
+	Meta    *PageMeta   `json:"meta,omitempty"`
 	Error   string      `json:"error,omitempty"`
 }
 
+// PageMeta describes the page returned by a paginated endpoint
+type PageMeta struct {
+	Total      int64  `json:"total"`
+	Limit      int    `json:"limit"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	// Next is the URL of the next page, empty on the last page
+	Next string `json:"next,omitempty"`
+}
+
 func SuccessResponse(c *gin.Context, statusCode int, message string, data interface{}) {
 	c.JSON(statusCode, Response{
 		Success: true,
@@ -19,9 +29,27 @@ func SuccessResponse(c *gin.Context, statusCode int, message string, data interf
 	})
 }
 
+func PaginatedResponse(c *gin.Context, statusCode int, message string, data interface{}, meta *PageMeta) {
+	c.JSON(statusCode, Response{
+		Success: true,
+		Message: message,
+		Data:    data,
+		Meta:    meta,
+	})
+}
+
 func ErrorResponse(c *gin.Context, statusCode int, message string) {
 	c.JSON(statusCode, Response{
 		Success: false,
 		Error:   message,
 	})
 }
+
+// ErrorResponseWithData reports an error along with data the client needs to act on it
+func ErrorResponseWithData(c *gin.Context, statusCode int, message string, data interface{}) {
+	c.JSON(statusCode, Response{
+		Success: false,
+		Error:   message,
+		Data:    data,
+	})
+}
@@ -0,0 +1,71 @@
+package validation
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// Binding tags registered with gin's validator
+const (
+	TagThaiNationalID = "thai_national_id"
+	TagPassport       = "passport"
+)
+
+// passportRegex follows the ICAO 9303 document number: 6 to 9 letters or digits
+var passportRegex = regexp.MustCompile(`^[A-Za-z0-9]{6,9}$`)
+
+// digitRegex is used to reject passport numbers made only of letters
+var digitRegex = regexp.MustCompile(`[0-9]`)
+
+// IsThaiNationalID checks a 13-digit Thai citizen ID and its mod-11 check digit.
+// The first 12 digits are weighted 13 down to 2; the check digit is (11 - sum mod 11) mod 10.
+func IsThaiNationalID(id string) bool {
+	if len(id) != 13 {
+		return false
+	}
+
+	sum := 0
+	for i := 0; i < 13; i++ {
+		if id[i] < '0' || id[i] > '9' {
+			return false
+		}
+		if i < 12 {
+			sum += int(id[i]-'0') * (13 - i)
+		}
+	}
+
+	check := (11 - sum%11) % 10
+	return int(id[12]-'0') == check
+}
+
+// IsPassportNumber checks a passport number is 6 to 9 letters or digits with at least one digit
+func IsPassportNumber(passport string) bool {
+	return passportRegex.MatchString(passport) && digitRegex.MatchString(passport)
+}
+
+// RegisterValidators adds the custom validation tags to v.
+// Blank values pass so that PATCH can clear an identifier; use required or
+// required_without to demand a value.
+func RegisterValidators(v *validator.Validate) error {
+	if err := v.RegisterValidation(TagThaiNationalID, func(fl validator.FieldLevel) bool {
+		id := strings.TrimSpace(fl.Field().String())
+		return id == "" || IsThaiNationalID(id)
+	}); err != nil {
+		return err
+	}
+	return v.RegisterValidation(TagPassport, func(fl validator.FieldLevel) bool {
+		passport := strings.TrimSpace(fl.Field().String())
+		return passport == "" || IsPassportNumber(passport)
+	})
+}
+
+// RegisterGinValidators adds the custom validation tags to gin's default validator
+func RegisterGinValidators() error {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		return RegisterValidators(v)
+	}
+	return nil
+}
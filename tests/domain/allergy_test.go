@@ -0,0 +1,61 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+func TestBuildAllergyBanner(t *testing.T) {
+	tests := []struct {
+		name            string
+		allergies       []domain.PatientAllergy
+		expectedCount   int
+		expectedHighest string
+		expectedDrug    bool
+		expectedSummary string
+	}{
+		{
+			name:            "nothing recorded",
+			allergies:       nil,
+			expectedCount:   0,
+			expectedSummary: "",
+		},
+		{
+			name: "most severe first, drug first among equals",
+			allergies: []domain.PatientAllergy{
+				{AllergenType: domain.AllergenTypeFood, Allergen: "Shrimp", Severity: domain.AllergySeveritySevere},
+				{AllergenType: domain.AllergenTypeEnvironmental, Allergen: "Dust mite", Severity: domain.AllergySeverityMild},
+				{AllergenType: domain.AllergenTypeDrug, Allergen: "Penicillin", Severity: domain.AllergySeveritySevere},
+			},
+			expectedCount:   3,
+			expectedHighest: domain.AllergySeveritySevere,
+			expectedDrug:    true,
+			expectedSummary: "Penicillin (severe), Shrimp (severe), Dust mite (mild)",
+		},
+		{
+			name: "summary names at most three allergens",
+			allergies: []domain.PatientAllergy{
+				{AllergenType: domain.AllergenTypeFood, Allergen: "Peanut", Severity: domain.AllergySeverityLifeThreatening},
+				{AllergenType: domain.AllergenTypeFood, Allergen: "Egg", Severity: domain.AllergySeverityMild},
+				{AllergenType: domain.AllergenTypeFood, Allergen: "Milk", Severity: domain.AllergySeverityModerate},
+				{AllergenType: domain.AllergenTypeFood, Allergen: "Wheat", Severity: domain.AllergySeverityMild},
+			},
+			expectedCount:   4,
+			expectedHighest: domain.AllergySeverityLifeThreatening,
+			expectedSummary: "Peanut (life-threatening), Milk (moderate), Egg (mild) +1 more",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			banner := domain.BuildAllergyBanner(tt.allergies)
+
+			assert.Equal(t, tt.expectedCount, banner.Count)
+			assert.Equal(t, tt.expectedHighest, banner.HighestSeverity)
+			assert.Equal(t, tt.expectedDrug, banner.HasDrugAllergy)
+			assert.Equal(t, tt.expectedSummary, banner.Summary)
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+func day(value string) time.Time {
+	parsed, _ := time.Parse(domain.DateFormat, value)
+	return parsed
+}
+
+func TestAvailabilityTemplate_Slots(t *testing.T) {
+	// Mondays 09:00-10:00 with 20 minute slots; 2026-03-16 and 2026-03-23 are Mondays
+	template := &domain.AvailabilityTemplate{
+		DoctorID:     3,
+		DepartmentID: 2,
+		Weekday:      int(time.Monday),
+		StartTime:    "09:00",
+		EndTime:      "10:00",
+		SlotMinutes:  20,
+		ValidFrom:    day("2026-03-01"),
+	}
+	template.ID = 7
+
+	slots, err := template.Slots(day("2026-03-15"), day("2026-03-23"))
+
+	assert.NoError(t, err)
+	assert.Len(t, slots, 6)
+	assert.Equal(t, time.Date(2026, 3, 16, 9, 0, 0, 0, domain.HNTimeZone), slots[0].StartAt)
+	assert.Equal(t, time.Date(2026, 3, 16, 9, 20, 0, 0, domain.HNTimeZone), slots[0].EndAt)
+	assert.Equal(t, time.Date(2026, 3, 16, 9, 40, 0, 0, domain.HNTimeZone), slots[2].StartAt)
+	assert.Equal(t, time.Date(2026, 3, 23, 9, 0, 0, 0, domain.HNTimeZone), slots[3].StartAt)
+	assert.Equal(t, uint(7), slots[0].TemplateID)
+	assert.Equal(t, uint(3), slots[0].DoctorID)
+	assert.Equal(t, uint(2), slots[0].DepartmentID)
+}
+
+func TestAvailabilityTemplate_Slots_LastSlotMustFit(t *testing.T) {
+	template := &domain.AvailabilityTemplate{
+		Weekday:     int(time.Monday),
+		StartTime:   "13:00",
+		EndTime:     "14:00",
+		SlotMinutes: 25,
+		ValidFrom:   day("2026-03-01"),
+	}
+
+	slots, err := template.Slots(day("2026-03-16"), day("2026-03-16"))
+
+	assert.NoError(t, err)
+	assert.Len(t, slots, 2)
+	assert.Equal(t, time.Date(2026, 3, 16, 13, 50, 0, 0, domain.HNTimeZone), slots[1].EndAt)
+}
+
+func TestAvailabilityTemplate_Slots_Validity(t *testing.T) {
+	validTo := day("2026-03-20")
+	template := &domain.AvailabilityTemplate{
+		Weekday:     int(time.Monday),
+		StartTime:   "09:00",
+		EndTime:     "09:30",
+		SlotMinutes: 30,
+		ValidFrom:   day("2026-03-10"),
+		ValidTo:     &validTo,
+	}
+
+	slots, err := template.Slots(day("2026-03-01"), day("2026-03-31"))
+
+	assert.NoError(t, err)
+	assert.Len(t, slots, 1)
+	assert.Equal(t, 16, slots[0].StartAt.Day())
+}
+
+func TestAvailabilityTemplate_Slots_InvalidTime(t *testing.T) {
+	template := &domain.AvailabilityTemplate{StartTime: "9am", EndTime: "12:00", SlotMinutes: 15}
+
+	_, err := template.Slots(day("2026-03-16"), day("2026-03-16"))
+
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+}
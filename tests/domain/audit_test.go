@@ -0,0 +1,141 @@
+package domain_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+func TestNewAuditEvent(t *testing.T) {
+	patientID := uint(7)
+	actor := &domain.Actor{StaffID: 3, Username: "nurse001", ClientIP: "10.0.0.1", RequestID: "req-1"}
+	changes := map[string]domain.FieldChange{
+		"phone_number": {Before: "0811111111", After: "0822222222"},
+	}
+
+	event, err := domain.NewAuditEvent(actor, domain.AuditActionPatientUpdate, &patientID, changes)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(3), event.ActorID)
+	assert.Equal(t, "nurse001", event.ActorUsername)
+	assert.Equal(t, "10.0.0.1", event.ClientIP)
+	assert.Equal(t, "req-1", event.RequestID)
+	assert.Equal(t, &patientID, event.PatientID)
+
+	var decoded map[string]domain.FieldChange
+	assert.NoError(t, json.Unmarshal([]byte(event.Changes), &decoded))
+	assert.Equal(t, "0822222222", decoded["phone_number"].After)
+}
+
+func TestNewAuditEvent_NilActorAndNoChanges(t *testing.T) {
+	event, err := domain.NewAuditEvent(nil, domain.AuditActionPatientView, nil, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(0), event.ActorID)
+	assert.Empty(t, event.Changes)
+	assert.Nil(t, event.PatientID)
+}
+
+func TestAuditEvent_ComputeHash(t *testing.T) {
+	patientID := uint(1)
+	event := domain.AuditEvent{
+		CreatedAt: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+		ActorID:   1,
+		Action:    domain.AuditActionPatientView,
+		PatientID: &patientID,
+		PrevHash:  "",
+	}
+
+	hash := event.ComputeHash()
+	assert.Len(t, hash, 64)
+	assert.Equal(t, hash, event.ComputeHash(), "hash must be deterministic")
+
+	tampered := event
+	tampered.ActorID = 2
+	assert.NotEqual(t, hash, tampered.ComputeHash())
+
+	relinked := event
+	relinked.PrevHash = "abc"
+	assert.NotEqual(t, hash, relinked.ComputeHash())
+}
+
+func TestAuditEvent_ComputeHash_FieldBoundaries(t *testing.T) {
+	event := domain.AuditEvent{
+		CreatedAt:     time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+		ActorID:       1,
+		ActorUsername: "nurse|001",
+		Action:        domain.AuditActionPatientView,
+	}
+
+	// Moving a separator from one free-text field to the next must change the hash
+	shifted := event
+	shifted.ActorUsername = "nurse"
+	shifted.Action = "001|" + domain.AuditActionPatientView
+	assert.NotEqual(t, event.ComputeHash(), shifted.ComputeHash())
+
+	// A missing patient is not the same as patient 0
+	zero := uint(0)
+	withZero := event
+	withZero.PatientID = &zero
+	assert.NotEqual(t, event.ComputeHash(), withZero.ComputeHash())
+}
+
+func TestDiffPatientFields(t *testing.T) {
+	before := domain.PatientFieldValues(&domain.Patient{
+		FirstNameEN: "John",
+		PhoneNumber: "0811111111",
+		DateOfBirth: time.Date(1990, 1, 15, 0, 0, 0, 0, time.UTC),
+		Gender:      domain.Male,
+	})
+
+	changes := domain.DiffPatientFields(before, map[string]interface{}{
+		"first_name_en": "John",
+		"phone_number":  "0822222222",
+		"date_of_birth": time.Date(1991, 1, 15, 0, 0, 0, 0, time.UTC),
+		"gender":        "M",
+	})
+
+	assert.Len(t, changes, 2)
+	assert.Equal(t, domain.FieldChange{Before: "0811111111", After: "0822222222"}, changes["phone_number"])
+	assert.Equal(t, domain.FieldChange{Before: "1990-01-15", After: "1991-01-15"}, changes["date_of_birth"])
+}
+
+func TestPatientFieldValues_SkipsNonColumnFields(t *testing.T) {
+	values := domain.PatientFieldValues(&domain.Patient{
+		FirstNameEN:    "John",
+		PrimaryContact: &domain.PatientContact{Name: "Jane"},
+	})
+
+	assert.Equal(t, "John", values["first_name_en"])
+	assert.NotContains(t, values, "primary_contact")
+	assert.NotContains(t, values, "PrimaryContact")
+}
+
+func TestAuditEvent_SetChange(t *testing.T) {
+	patientID := uint(7)
+	event, err := domain.NewAuditEvent(nil, domain.AuditActionPatientEncounterCreate, &patientID, map[string]domain.FieldChange{
+		"department_id": {After: 2},
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, event.SetChange("vn", domain.FieldChange{After: "69031500001"}))
+
+	var decoded map[string]domain.FieldChange
+	assert.NoError(t, json.Unmarshal([]byte(event.Changes), &decoded))
+	assert.Equal(t, "69031500001", decoded["vn"].After)
+	assert.Equal(t, float64(2), decoded["department_id"].After)
+}
+
+func TestAuditEvent_SetChange_NoChanges(t *testing.T) {
+	event, err := domain.NewAuditEvent(nil, domain.AuditActionPatientEncounterCreate, nil, nil)
+	assert.NoError(t, err)
+
+	assert.NoError(t, event.SetChange("vn", domain.FieldChange{After: "69031500001"}))
+
+	var decoded map[string]domain.FieldChange
+	assert.NoError(t, json.Unmarshal([]byte(event.Changes), &decoded))
+	assert.Len(t, decoded, 1)
+}
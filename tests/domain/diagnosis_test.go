@@ -0,0 +1,36 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+func TestNormalizeICD10Code(t *testing.T) {
+	tests := []struct {
+		code     string
+		expected string
+	}{
+		{code: "J18.9", expected: "J18.9"},
+		{code: "j189", expected: "J18.9"},
+		{code: " e11.65 ", expected: "E11.65"},
+		{code: "I10", expected: "I10"},
+		{code: "i10.", expected: "I10"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			assert.Equal(t, tt.expected, domain.NormalizeICD10Code(tt.code))
+		})
+	}
+}
+
+func TestIsExternalCauseCode(t *testing.T) {
+	assert.True(t, domain.IsExternalCauseCode("V43.5"))
+	assert.True(t, domain.IsExternalCauseCode("W19"))
+	assert.True(t, domain.IsExternalCauseCode("Y83.9"))
+	assert.False(t, domain.IsExternalCauseCode("S72.0"))
+	assert.False(t, domain.IsExternalCauseCode("Z00.0"))
+	assert.False(t, domain.IsExternalCauseCode(""))
+}
@@ -0,0 +1,57 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+func TestNextEncounterStatus(t *testing.T) {
+	tests := []struct {
+		status   string
+		expected string
+	}{
+		{domain.EncounterStatusRegistered, domain.EncounterStatusTriaged},
+		{domain.EncounterStatusTriaged, domain.EncounterStatusInConsultation},
+		{domain.EncounterStatusInConsultation, domain.EncounterStatusDone},
+		{domain.EncounterStatusDone, ""},
+		{"cancelled", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			assert.Equal(t, tt.expected, domain.NextEncounterStatus(tt.status))
+		})
+	}
+}
+
+func TestEncounter_SetStatus(t *testing.T) {
+	registered := time.Date(2026, 3, 15, 8, 0, 0, 0, domain.HNTimeZone)
+	triaged := registered.Add(10 * time.Minute)
+	done := registered.Add(time.Hour)
+
+	encounter := &domain.Encounter{}
+	encounter.SetStatus(domain.EncounterStatusRegistered, registered)
+	encounter.SetStatus(domain.EncounterStatusTriaged, triaged)
+
+	assert.Equal(t, domain.EncounterStatusTriaged, encounter.Status)
+	assert.Equal(t, registered, encounter.RegisteredAt)
+	assert.Equal(t, triaged, *encounter.TriagedAt)
+	assert.Nil(t, encounter.ConsultationStartedAt)
+
+	encounter.SetStatus(domain.EncounterStatusDone, done)
+	assert.Equal(t, done, *encounter.CompletedAt)
+}
+
+func TestNewVNIssuer(t *testing.T) {
+	issuer := domain.NewVNIssuer()
+	issued := time.Date(2026, 3, 15, 8, 0, 0, 0, domain.HNTimeZone)
+
+	vn, err := issuer.Template.Format("", issued, 12)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "69031500012", vn)
+	assert.Equal(t, "2569-03-15", issuer.Template.Period(issued))
+}
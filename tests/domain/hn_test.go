@@ -0,0 +1,152 @@
+package domain_test
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+// 15 March 2024 is 15 March 2567 in the Buddhist era
+var hnIssuedAt = time.Date(2024, time.March, 15, 10, 0, 0, 0, domain.HNTimeZone)
+
+func TestParseHNTemplate(t *testing.T) {
+	tests := []struct {
+		name      string
+		template  string
+		wantReset string
+		wantErr   bool
+	}{
+		{name: "default template", template: domain.DefaultHNTemplate, wantReset: domain.HNResetNever},
+		{name: "yearly with check digit", template: "{BE_YY}-{SEQ:6}{CHECK}", wantReset: domain.HNResetYearly},
+		{name: "monthly", template: "{CE_YYYY}{MM}/{SEQ:4}", wantReset: domain.HNResetMonthly},
+		{name: "no sequence", template: "{HOSP}-{BE_YY}", wantErr: true},
+		{name: "two sequences", template: "{SEQ:4}-{SEQ:4}", wantErr: true},
+		{name: "sequence too wide", template: "{SEQ:13}", wantErr: true},
+		{name: "daily", template: "{BE_YY}{MM}{DD}{SEQ:5}", wantReset: domain.HNResetDaily},
+		{name: "unknown token", template: "{WW}-{SEQ:6}", wantErr: true},
+		{name: "day without month", template: "{BE_YY}{DD}-{SEQ:6}", wantErr: true},
+		{name: "unclosed token", template: "{SEQ:6", wantErr: true},
+		{name: "check digit before sequence", template: "{CHECK}{SEQ:6}", wantErr: true},
+		{name: "token after check digit", template: "{SEQ:6}{CHECK}{BE_YY}", wantErr: true},
+		{name: "month without year", template: "{MM}-{SEQ:6}", wantErr: true},
+		{name: "two years", template: "{BE_YY}{CE_YY}-{SEQ:6}", wantErr: true},
+		{name: "space in text", template: "HN {SEQ:6}", wantErr: true},
+		{name: "too long for the HN column", template: "{HOSP}{HOSP}{HOSP}{HOSP}{HOSP}{SEQ:12}", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template, err := domain.ParseHNTemplate(tt.template)
+			if tt.wantErr {
+				assert.True(t, errors.Is(err, domain.ErrInvalidInput))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantReset, template.Reset())
+		})
+	}
+}
+
+func TestHNTemplate_Format(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		seq      uint64
+		want     string
+	}{
+		{name: "default template keeps the original format", template: domain.DefaultHNTemplate, seq: 1, want: "HOSP0001-00000001"},
+		{name: "Buddhist-era year prefix", template: "{BE_YY}-{SEQ:6}", seq: 123, want: "67-000123"},
+		{name: "check digit over the digits before it", template: "{BE_YY}-{SEQ:6}{CHECK}", seq: 123, want: "67-0001239"},
+		{name: "year and month", template: "{HOSP}/{CE_YYYY}{MM}-{SEQ:3}", seq: 7, want: "HOSP0001/202403-007"},
+		{name: "year, month and day", template: "{BE_YY}{MM}{DD}{SEQ:5}", seq: 12, want: "67031500012"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template, err := domain.ParseHNTemplate(tt.template)
+			require.NoError(t, err)
+
+			hn, err := template.Format("HOSP0001", hnIssuedAt, tt.seq)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, hn)
+			assert.Regexp(t, regexp.MustCompile(template.Pattern("HOSP0001")), hn)
+		})
+	}
+}
+
+func TestHNTemplate_FormatExhausted(t *testing.T) {
+	template, err := domain.ParseHNTemplate("{BE_YY}-{SEQ:2}")
+	require.NoError(t, err)
+
+	_, err = template.Format("HOSP0001", hnIssuedAt, 100)
+
+	assert.True(t, errors.Is(err, domain.ErrHNSequenceExhausted))
+}
+
+func TestHNTemplate_Period(t *testing.T) {
+	yearly, _ := domain.ParseHNTemplate("{BE_YY}-{SEQ:6}")
+	monthly, _ := domain.ParseHNTemplate("{BE_YY}{MM}-{SEQ:4}")
+	daily, _ := domain.ParseHNTemplate("{BE_YY}{MM}{DD}{SEQ:5}")
+	never, _ := domain.ParseHNTemplate(domain.DefaultHNTemplate)
+
+	assert.Equal(t, "2567", yearly.Period(hnIssuedAt))
+	assert.Equal(t, "2567-03", monthly.Period(hnIssuedAt))
+	assert.Equal(t, "2567-03-15", daily.Period(hnIssuedAt))
+	assert.Equal(t, "", never.Period(hnIssuedAt))
+
+	// 31 December 2023 18:00 UTC is already New Year in Thailand
+	newYear := time.Date(2023, time.December, 31, 18, 0, 0, 0, time.UTC)
+	assert.Equal(t, "2567", yearly.Period(newYear))
+}
+
+func TestHNTemplate_Parse(t *testing.T) {
+	template, err := domain.ParseHNTemplate("{BE_YY}-{SEQ:6}{CHECK}")
+	require.NoError(t, err)
+
+	period, seq, ok := template.Parse("66-0000423", "HOSP0001", hnIssuedAt)
+	assert.True(t, ok)
+	assert.Equal(t, "2566", period)
+	assert.Equal(t, uint64(42), seq)
+
+	// Wrong check digit: the template cannot have issued it
+	_, _, ok = template.Parse("66-0000421", "HOSP0001", hnIssuedAt)
+	assert.False(t, ok)
+
+	_, _, ok = template.Parse("HOSP0001-00000001", "HOSP0001", hnIssuedAt)
+	assert.False(t, ok)
+}
+
+func TestHNTemplate_Parse_Daily(t *testing.T) {
+	template, err := domain.ParseHNTemplate("{BE_YY}{MM}{DD}{SEQ:5}")
+	require.NoError(t, err)
+
+	period, seq, ok := template.Parse("67022900003", "HOSP0001", hnIssuedAt)
+	assert.True(t, ok)
+	assert.Equal(t, "2567-02-29", period)
+	assert.Equal(t, uint64(3), seq)
+
+	// 2568 (2025) is not a leap year
+	_, _, ok = template.Parse("68022900003", "HOSP0001", hnIssuedAt)
+	assert.False(t, ok)
+}
+
+func TestHNTemplate_Parse_MonthlyOnLastDayOfMonth(t *testing.T) {
+	template, err := domain.ParseHNTemplate("{BE_YY}{MM}-{SEQ:4}")
+	require.NoError(t, err)
+
+	// Parsing on the 31st must not roll February over into March
+	period, _, ok := template.Parse("6702-0001", "HOSP0001", time.Date(2024, time.March, 31, 10, 0, 0, 0, domain.HNTimeZone))
+	assert.True(t, ok)
+	assert.Equal(t, "2567-02", period)
+}
+
+func TestLuhnCheckDigit(t *testing.T) {
+	assert.Equal(t, byte('3'), domain.LuhnCheckDigit("7992739871"))
+	assert.Equal(t, byte('9'), domain.LuhnCheckDigit("67-000123"))
+}
@@ -0,0 +1,87 @@
+package domain_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+func stockLot(id uint, lotNumber string, expiry string, quantity float64) domain.StockLot {
+	expiryDate, _ := time.Parse(domain.DateFormat, expiry)
+	return domain.StockLot{ID: id, DrugID: 5, LotNumber: lotNumber, ExpiryDate: expiryDate, Quantity: quantity}
+}
+
+func TestPickFEFO(t *testing.T) {
+	today, _ := time.Parse(domain.DateFormat, "2025-06-01")
+	lots := []domain.StockLot{
+		stockLot(1, "LATE", "2026-12-31", 100),
+		stockLot(2, "EXPIRED", "2025-05-31", 50),
+		stockLot(3, "SOON", "2025-08-31", 10),
+		stockLot(4, "EMPTY", "2025-07-31", 0),
+		stockLot(5, "TODAY", "2025-06-01", 4),
+	}
+
+	tests := []struct {
+		name     string
+		quantity float64
+		expected map[string]float64
+		order    []string
+	}{
+		{name: "first lot covers it", quantity: 3, expected: map[string]float64{"TODAY": 3}, order: []string{"TODAY"}},
+		{name: "spans lots by expiry", quantity: 20, expected: map[string]float64{"TODAY": 4, "SOON": 10, "LATE": 6}, order: []string{"TODAY", "SOON", "LATE"}},
+		{name: "takes everything usable", quantity: 114, expected: map[string]float64{"TODAY": 4, "SOON": 10, "LATE": 100}, order: []string{"TODAY", "SOON", "LATE"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			picks, err := domain.PickFEFO(lots, tt.quantity, today)
+
+			assert.NoError(t, err)
+			var order []string
+			for _, pick := range picks {
+				order = append(order, pick.Lot.LotNumber)
+				assert.Equal(t, tt.expected[pick.Lot.LotNumber], pick.Quantity)
+			}
+			assert.Equal(t, tt.order, order)
+		})
+	}
+}
+
+func TestPickFEFO_InsufficientStock(t *testing.T) {
+	today, _ := time.Parse(domain.DateFormat, "2025-06-01")
+	lots := []domain.StockLot{
+		stockLot(1, "EXPIRED", "2025-01-31", 500),
+		stockLot(2, "SOON", "2025-08-31", 10),
+	}
+
+	picks, err := domain.PickFEFO(lots, 15, today)
+
+	assert.True(t, errors.Is(err, domain.ErrInsufficientStock))
+	assert.EqualError(t, err, "insufficient stock: 10 available, 15 needed")
+	assert.Nil(t, picks)
+}
+
+func TestNewStockCard(t *testing.T) {
+	movements := []domain.StockMovement{
+		{Type: domain.StockMovementReceipt, Quantity: 100},
+		{Type: domain.StockMovementDispense, Quantity: -15},
+		{Type: domain.StockMovementDispense, Quantity: -10},
+		{Type: domain.StockMovementAdjustment, Quantity: -2},
+	}
+
+	card := domain.NewStockCard(1, 5, 20, movements)
+
+	assert.Equal(t, float64(20), card.OpeningBalance)
+	assert.Equal(t, float64(100), card.Received)
+	assert.Equal(t, float64(25), card.Dispensed)
+	assert.Equal(t, float64(-2), card.Adjusted)
+	assert.Equal(t, float64(93), card.ClosingBalance)
+	if assert.Len(t, card.Entries, 4) {
+		assert.Equal(t, float64(120), card.Entries[0].Balance)
+		assert.Equal(t, float64(95), card.Entries[2].Balance)
+		assert.Equal(t, float64(93), card.Entries[3].Balance)
+	}
+}
@@ -0,0 +1,232 @@
+package domain_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+// hemoglobin has separate adult ranges for men and women and a range for children
+func hemoglobin() *domain.LabTest {
+	return &domain.LabTest{
+		Code:       "HB",
+		Name:       "Hemoglobin",
+		ResultType: domain.LabResultTypeNumeric,
+		Unit:       "g/dL",
+		ReferenceRanges: domain.LabReferenceRanges{
+			{AgeMinYears: 0, AgeMaxYears: intPtr(15), Low: floatPtr(11.5), High: floatPtr(15.5), CriticalLow: floatPtr(7)},
+			{Sex: "M", AgeMinYears: 15, Low: floatPtr(13), High: floatPtr(17), CriticalLow: floatPtr(7), CriticalHigh: floatPtr(20)},
+			{Sex: "F", AgeMinYears: 15, Low: floatPtr(12), High: floatPtr(15), CriticalLow: floatPtr(7), CriticalHigh: floatPtr(20)},
+		},
+	}
+}
+
+func TestLabTest_ReferenceRange(t *testing.T) {
+	test := hemoglobin()
+
+	tests := []struct {
+		name     string
+		sex      domain.Gender
+		ageYears int
+		expected string
+	}{
+		{name: "child", sex: domain.Male, ageYears: 8, expected: "11.5-15.5"},
+		{name: "adult man", sex: domain.Male, ageYears: 15, expected: "13-17"},
+		{name: "adult woman", sex: domain.Female, ageYears: 40, expected: "12-15"},
+		{name: "adult of other sex", sex: domain.Other, ageYears: 40, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := test.ReferenceRange(tt.sex, tt.ageYears)
+			if tt.expected == "" {
+				assert.Nil(t, r)
+				return
+			}
+			if assert.NotNil(t, r) {
+				assert.Equal(t, tt.expected, r.Text())
+			}
+		})
+	}
+}
+
+func TestLabReferenceRange_Flag(t *testing.T) {
+	r := domain.LabReferenceRange{Low: floatPtr(13), High: floatPtr(17), CriticalLow: floatPtr(7), CriticalHigh: floatPtr(20)}
+
+	tests := []struct {
+		value    float64
+		expected string
+	}{
+		{value: 6.9, expected: domain.LabFlagCriticalLow},
+		{value: 7, expected: domain.LabFlagLow},
+		{value: 13, expected: domain.LabFlagNormal},
+		{value: 17, expected: domain.LabFlagNormal},
+		{value: 17.1, expected: domain.LabFlagHigh},
+		{value: 20.5, expected: domain.LabFlagCriticalHigh},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, r.Flag(tt.value), "value %g", tt.value)
+	}
+
+	upperOnly := domain.LabReferenceRange{High: floatPtr(200)}
+	assert.Equal(t, "< 200", upperOnly.Text())
+	assert.Equal(t, domain.LabFlagNormal, upperOnly.Flag(0))
+}
+
+func TestLabReferenceRange_Validate(t *testing.T) {
+	tests := []struct {
+		name  string
+		r     domain.LabReferenceRange
+		valid bool
+	}{
+		{name: "valid", r: domain.LabReferenceRange{Low: floatPtr(3.5), High: floatPtr(5), CriticalLow: floatPtr(2.5), CriticalHigh: floatPtr(6.5)}, valid: true},
+		{name: "no limits", r: domain.LabReferenceRange{CriticalHigh: floatPtr(6.5)}},
+		{name: "low above high", r: domain.LabReferenceRange{Low: floatPtr(5), High: floatPtr(3.5)}},
+		{name: "critical inside range", r: domain.LabReferenceRange{Low: floatPtr(3.5), High: floatPtr(5), CriticalHigh: floatPtr(4)}},
+		{name: "empty age band", r: domain.LabReferenceRange{AgeMinYears: 18, AgeMaxYears: intPtr(18), High: floatPtr(5)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.r.Validate()
+			if tt.valid {
+				assert.NoError(t, err)
+				return
+			}
+			assert.True(t, errors.Is(err, domain.ErrInvalidInput))
+		})
+	}
+}
+
+func TestLabOrderItem_SetResult(t *testing.T) {
+	at := time.Date(2025, 6, 1, 3, 0, 0, 0, time.UTC)
+
+	t.Run("numeric flagged for the patient", func(t *testing.T) {
+		item := domain.LabOrderItem{TestCode: "HB", Status: domain.LabItemStatusPending}
+
+		err := item.SetResult(hemoglobin(), " 12.5 ", domain.Male, 40, at)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.LabItemStatusResulted, item.Status)
+		assert.Equal(t, "12.5", item.Value)
+		assert.Equal(t, 12.5, *item.NumericValue)
+		assert.Equal(t, "13-17", item.ReferenceRange)
+		assert.Equal(t, domain.LabFlagLow, item.Flag)
+		assert.True(t, item.IsAbnormal())
+
+		err = item.SetResult(hemoglobin(), "13.4", domain.Male, 40, at)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.LabItemStatusCorrected, item.Status)
+		assert.Equal(t, domain.LabFlagNormal, item.Flag)
+	})
+
+	t.Run("numeric result that is not a number", func(t *testing.T) {
+		item := domain.LabOrderItem{TestCode: "HB", Status: domain.LabItemStatusPending}
+
+		err := item.SetResult(hemoglobin(), "hemolysed", domain.Male, 40, at)
+
+		assert.True(t, errors.Is(err, domain.ErrInvalidInput))
+		assert.Equal(t, domain.LabItemStatusPending, item.Status)
+	})
+
+	t.Run("text against the expected text", func(t *testing.T) {
+		test := &domain.LabTest{Code: "UPREG", ResultType: domain.LabResultTypeText, NormalText: "negative"}
+		item := domain.LabOrderItem{TestCode: "UPREG", Status: domain.LabItemStatusPending}
+
+		assert.NoError(t, item.SetResult(test, "Negative", domain.Female, 25, at))
+		assert.Equal(t, domain.LabFlagNormal, item.Flag)
+		assert.NoError(t, item.SetResult(test, "positive", domain.Female, 25, at))
+		assert.Equal(t, domain.LabFlagAbnormal, item.Flag)
+		assert.Nil(t, item.NumericValue)
+	})
+}
+
+func TestLabOrder_RefreshStatus(t *testing.T) {
+	at := time.Date(2025, 6, 1, 3, 0, 0, 0, time.UTC)
+	order := func(specimens []string, items []string) *domain.LabOrder {
+		o := &domain.LabOrder{Status: domain.LabOrderStatusOrdered}
+		for _, status := range specimens {
+			o.Specimens = append(o.Specimens, domain.LabSpecimen{Status: status})
+		}
+		for _, status := range items {
+			o.Items = append(o.Items, domain.LabOrderItem{Status: status})
+		}
+		return o
+	}
+
+	tests := []struct {
+		name      string
+		specimens []string
+		items     []string
+		expected  string
+	}{
+		{name: "nothing collected", specimens: []string{"pending", "collected"}, items: []string{"pending", "pending"}, expected: domain.LabOrderStatusOrdered},
+		{name: "all collected", specimens: []string{"collected", "received"}, items: []string{"pending", "pending"}, expected: domain.LabOrderStatusCollected},
+		{name: "all received", specimens: []string{"received", "received"}, items: []string{"resulted", "pending"}, expected: domain.LabOrderStatusReceived},
+		{name: "rejected specimen", specimens: []string{"received", "rejected"}, items: []string{"pending", "pending"}, expected: domain.LabOrderStatusOrdered},
+		{name: "all resulted", specimens: []string{"received", "received"}, items: []string{"resulted", "corrected"}, expected: domain.LabOrderStatusCompleted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := order(tt.specimens, tt.items)
+
+			o.RefreshStatus(at)
+
+			assert.Equal(t, tt.expected, o.Status)
+			assert.Equal(t, tt.expected == domain.LabOrderStatusCompleted, o.CompletedAt != nil)
+		})
+	}
+
+	cancelled := order([]string{"received"}, []string{"resulted"})
+	cancelled.Status = domain.LabOrderStatusCancelled
+	cancelled.RefreshStatus(at)
+	assert.Equal(t, domain.LabOrderStatusCancelled, cancelled.Status)
+}
+
+func TestCanMoveLabSpecimen(t *testing.T) {
+	assert.True(t, domain.CanMoveLabSpecimen(domain.LabSpecimenStatusPending, domain.LabSpecimenStatusCollected))
+	assert.True(t, domain.CanMoveLabSpecimen(domain.LabSpecimenStatusRejected, domain.LabSpecimenStatusCollected))
+	assert.False(t, domain.CanMoveLabSpecimen(domain.LabSpecimenStatusPending, domain.LabSpecimenStatusReceived))
+	assert.False(t, domain.CanMoveLabSpecimen(domain.LabSpecimenStatusReceived, domain.LabSpecimenStatusCollected))
+}
+
+func TestFormatLabSpecimenBarcode(t *testing.T) {
+	// 20:00 UTC on 31 May is already 1 June in Thailand
+	period := domain.LabSpecimenBarcodePeriod(time.Date(2025, 5, 31, 20, 0, 0, 0, time.UTC))
+	assert.Equal(t, "250601", period)
+
+	barcode, err := domain.FormatLabSpecimenBarcode(period, 12)
+	assert.NoError(t, err)
+	assert.Equal(t, "25060100012", barcode)
+
+	_, err = domain.FormatLabSpecimenBarcode(period, 100000)
+	assert.True(t, errors.Is(err, domain.ErrLabBarcodeExhausted))
+}
+
+func TestNewLabSpecimenLabel(t *testing.T) {
+	dob, _ := time.Parse(domain.DateFormat, "1980-02-29")
+	patient := &domain.Patient{PatientHN: "HN000123", FirstNameTH: "สมชาย", LastNameTH: "ใจดี", FirstNameEN: "Somchai^", LastNameEN: "Jaidee", DateOfBirth: dob, Gender: domain.Male}
+	order := &domain.LabOrder{Priority: domain.LabPriorityStat, Items: []domain.LabOrderItem{
+		{SpecimenID: 1, TestCode: "CBC"},
+		{SpecimenID: 2, TestCode: "UA"},
+		{SpecimenID: 1, TestCode: "HB"},
+	}}
+	specimen := &domain.LabSpecimen{ID: 1, Barcode: "25060100012", SpecimenType: domain.LabSpecimenBlood}
+
+	label := domain.NewLabSpecimenLabel(specimen, order, patient)
+
+	assert.Equal(t, "Somchai^ Jaidee", label.PatientName)
+	assert.Equal(t, []string{"CBC", "HB"}, label.Tests)
+	assert.Equal(t, "1980-02-29", label.DateOfBirth)
+	assert.True(t, strings.HasPrefix(label.ZPL, "^XA"))
+	assert.Contains(t, label.ZPL, "^BCN,70,Y,N,N^FD25060100012^FS")
+	assert.Contains(t, label.ZPL, "^FDSomchai  Jaidee^FS")
+	assert.Contains(t, label.ZPL, "STAT blood CBC HB")
+}
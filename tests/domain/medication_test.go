@@ -0,0 +1,70 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+func TestCanMoveMedicationOrder(t *testing.T) {
+	tests := []struct {
+		from     string
+		to       string
+		expected bool
+	}{
+		{from: domain.MedicationStatusOrdered, to: domain.MedicationStatusVerified, expected: true},
+		{from: domain.MedicationStatusOrdered, to: domain.MedicationStatusCancelled, expected: true},
+		{from: domain.MedicationStatusOrdered, to: domain.MedicationStatusDispensed, expected: false},
+		{from: domain.MedicationStatusVerified, to: domain.MedicationStatusDispensed, expected: true},
+		{from: domain.MedicationStatusVerified, to: domain.MedicationStatusCancelled, expected: true},
+		{from: domain.MedicationStatusDispensed, to: domain.MedicationStatusCancelled, expected: false},
+		{from: domain.MedicationStatusCancelled, to: domain.MedicationStatusVerified, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from+" to "+tt.to, func(t *testing.T) {
+			assert.Equal(t, tt.expected, domain.CanMoveMedicationOrder(tt.from, tt.to))
+		})
+	}
+}
+
+func TestComputeMedicationQuantity(t *testing.T) {
+	drug := &domain.Drug{GenericName: "Paracetamol", DispensingUnit: "tab"}
+
+	tests := []struct {
+		name      string
+		dose      float64
+		doseUnit  string
+		frequency string
+		days      int
+		expected  float64
+		ok        bool
+	}{
+		{name: "tid for five days", dose: 1, doseUnit: "tab", frequency: "tid", days: 5, expected: 15, ok: true},
+		{name: "half tablets round up", dose: 0.5, doseUnit: "TAB", frequency: "bid", days: 3, expected: 3, ok: true},
+		{name: "stat is a single dose", dose: 2, doseUnit: "tab", frequency: "stat", days: 1, expected: 2, ok: true},
+		{name: "prn has no daily count", dose: 1, doseUnit: "tab", frequency: "prn", days: 5, ok: false},
+		{name: "dose in another unit", dose: 500, doseUnit: "mg", frequency: "tid", days: 5, ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quantity, ok := domain.ComputeMedicationQuantity(drug, tt.dose, tt.doseUnit, tt.frequency, tt.days)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, quantity)
+		})
+	}
+}
+
+func TestDrug_MatchesAllergen(t *testing.T) {
+	drug := &domain.Drug{GenericName: "Amoxicillin", TradeName: "Amoxil", TherapeuticClass: "penicillin"}
+
+	assert.True(t, drug.MatchesAllergen("amoxicillin"))
+	assert.True(t, drug.MatchesAllergen("Amoxil"))
+	assert.True(t, drug.MatchesAllergen("Penicillins"))
+	assert.True(t, drug.MatchesAllergen(" PENICILLIN "))
+	assert.False(t, drug.MatchesAllergen("Sulfonamide"))
+	assert.False(t, drug.MatchesAllergen("amo"))
+	assert.False(t, drug.MatchesAllergen(""))
+}
@@ -92,6 +92,27 @@ func TestBloodGrp_Scan(t *testing.T) {
 	}
 }
 
+func TestNullableString_Value(t *testing.T) {
+	value, err := domain.NullableString("").Value()
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+
+	value, err = domain.NullableString("1234567890121").Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "1234567890121", value)
+}
+
+func TestNullableString_Scan(t *testing.T) {
+	var n domain.NullableString
+	assert.NoError(t, n.Scan(nil))
+	assert.Equal(t, domain.NullableString(""), n)
+
+	assert.NoError(t, n.Scan([]byte("AB123456")))
+	assert.Equal(t, domain.NullableString("AB123456"), n)
+
+	assert.Error(t, n.Scan(42))
+}
+
 func TestPatientPartialUpdateRequest_ToMap(t *testing.T) {
 	firstName := "John"
 	lastName := "Doe"
@@ -178,6 +199,24 @@ func TestPatientPartialUpdateRequest_ToMap_DateParsing(t *testing.T) {
 	assert.Equal(t, 20, dob.Day())
 }
 
+func TestPatientPartialUpdateRequest_ToMap_Identifiers(t *testing.T) {
+	blank := " "
+	passportID := " ab123456 "
+	request := &domain.PatientPartialUpdateRequest{
+		NationalID: &blank,
+		PassportID: &passportID,
+	}
+
+	result, err := request.ToMap()
+	assert.NoError(t, err)
+
+	// Blank identifiers are written as NULL
+	nationalID, err := result["national_id"].(domain.NullableString).Value()
+	assert.NoError(t, err)
+	assert.Nil(t, nationalID)
+	assert.Equal(t, domain.NullableString("AB123456"), result["passport_id"])
+}
+
 func TestPatientPartialUpdateRequest_ToMap_AllFields(t *testing.T) {
 	firstName := "John"
 	lastName := "Doe"
@@ -188,7 +227,7 @@ func TestPatientPartialUpdateRequest_ToMap_AllFields(t *testing.T) {
 	dateOfBirth := "1990-01-15"
 	nickNameTH := "จอห์นนี่"
 	nickNameEN := "Johnny"
-	nationalID := "1234567890123"
+	nationalID := "1234567890121"
 	passportID := "AB123456"
 	phoneNumber := "0812345678"
 	email := "john@example.com"
@@ -233,7 +272,7 @@ func TestPatient_Struct(t *testing.T) {
 		LastNameEN:   "Jaidee",
 		DateOfBirth:  time.Date(1990, 1, 15, 0, 0, 0, 0, time.UTC),
 		PatientHN:    "HOSP0001-00000001",
-		NationalID:   "1234567890123",
+		NationalID:   "1234567890121",
 		PhoneNumber:  "0812345678",
 		Email:        "somchai@example.com",
 		Gender:       domain.Male,
@@ -247,3 +286,133 @@ func TestPatient_Struct(t *testing.T) {
 	assert.Equal(t, domain.A, patient.BloodGrp)
 	assert.Equal(t, "HOSP0001-00000001", patient.PatientHN)
 }
+
+func TestPatientCursor_EncodeDecode(t *testing.T) {
+	cursor := &domain.PatientCursor{Sort: "-created_at", Value: "2024-01-01T10:00:00.123456Z", ID: 42}
+
+	decoded, err := domain.DecodePatientCursor(cursor.Encode())
+
+	assert.NoError(t, err)
+	assert.Equal(t, cursor, decoded)
+}
+
+func TestDecodePatientCursor_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{name: "not base64", cursor: "%%%"},
+		{name: "not json", cursor: "bm90IGpzb24"},
+		{name: "missing id", cursor: (&domain.PatientCursor{Sort: "hn", Value: "HN1"}).Encode()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := domain.DecodePatientCursor(tt.cursor)
+			assert.ErrorIs(t, err, domain.ErrInvalidInput)
+		})
+	}
+}
+
+func TestScoreDuplicateCandidate(t *testing.T) {
+	dob := time.Date(1990, 1, 15, 0, 0, 0, 0, time.UTC)
+	probe := &domain.Patient{NationalID: "1234567890121", PhoneNumber: "0812345678", DateOfBirth: dob}
+	similarity := 0.8
+
+	tests := []struct {
+		name          string
+		candidate     domain.PatientSearchItem
+		expectedScore int
+		reasons       []string
+	}{
+		{
+			name:          "same national ID",
+			candidate:     domain.PatientSearchItem{Patient: domain.Patient{NationalID: "1234567890121"}},
+			expectedScore: domain.DuplicateWeightIdentifier,
+			reasons:       []string{"national_id"},
+		},
+		{
+			name:          "same date of birth and similar name",
+			candidate:     domain.PatientSearchItem{Patient: domain.Patient{DateOfBirth: dob}, Score: &similarity},
+			expectedScore: domain.DuplicateWeightDateOfBirth + 40,
+			reasons:       []string{"date_of_birth", "name"},
+		},
+		{
+			name:          "same date of birth only stays below the threshold",
+			candidate:     domain.PatientSearchItem{Patient: domain.Patient{DateOfBirth: dob, PhoneNumber: "0899999999"}},
+			expectedScore: domain.DuplicateWeightDateOfBirth,
+			reasons:       []string{"date_of_birth"},
+		},
+		{
+			name:          "blank identifiers never match",
+			candidate:     domain.PatientSearchItem{Patient: domain.Patient{}},
+			expectedScore: 0,
+			reasons:       []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := domain.ScoreDuplicateCandidate(probe, &tt.candidate)
+			assert.Equal(t, tt.expectedScore, result.Score)
+			assert.Equal(t, tt.reasons, result.Reasons)
+		})
+	}
+
+	assert.Less(t, domain.DuplicateWeightDateOfBirth, domain.DuplicateScoreThreshold)
+}
+
+func TestPatientRestoreConflicts(t *testing.T) {
+	deleted := &domain.Patient{NationalID: "1234567890121", PassportID: "", PhoneNumber: "0812345678", Email: ""}
+	other := domain.Patient{NationalID: "1234567890121", PhoneNumber: "0899999999"}
+	other.ID = 7
+	blank := domain.Patient{PhoneNumber: "0812345678"}
+	blank.ID = 8
+
+	conflicts := domain.PatientRestoreConflicts(deleted, []domain.Patient{other, blank})
+
+	// Blank passport and email never conflict, even with other blank values
+	assert.Equal(t, []domain.RestoreConflict{
+		{Field: "national_id", Value: "1234567890121", ExistingID: 7},
+		{Field: "phone_number", Value: "0812345678", ExistingID: 8},
+	}, conflicts)
+}
+
+func TestPatientSnapshot_RoundTrip(t *testing.T) {
+	patient := &domain.Patient{
+		FirstNameEN: "Somchai",
+		DateOfBirth: time.Date(1990, 1, 15, 0, 0, 0, 0, time.UTC),
+		NationalID:  "1234567890121",
+		BloodGrp:    domain.A,
+	}
+	version := domain.NewPatientVersion(patient, domain.PatientVersionUpdate, &domain.AuditEvent{ActorID: 3, ActorUsername: "clerk"})
+
+	stored, err := version.Snapshot.Value()
+	assert.NoError(t, err)
+
+	var loaded domain.PatientSnapshot
+	assert.NoError(t, loaded.Scan([]byte(stored.(string))))
+
+	// Values read back compare equal, so loaded versions diff cleanly against each other
+	assert.Equal(t, "1990-01-15", loaded["date_of_birth"])
+	assert.Empty(t, domain.DiffPatientFields(version.Snapshot, loaded))
+	assert.Equal(t, uint(3), version.ChangedBy)
+}
+
+func TestParseETag(t *testing.T) {
+	patient := domain.Patient{}
+	patient.Version = 12
+
+	version, err := domain.ParseETag(patient.ETag())
+	assert.NoError(t, err)
+	assert.Equal(t, uint(12), version)
+
+	version, err = domain.ParseETag(`W/"12"`)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(12), version)
+
+	for _, malformed := range []string{`12`, `"twelve"`, `"0"`, `"`, `*`} {
+		_, err := domain.ParseETag(malformed)
+		assert.ErrorIs(t, err, domain.ErrInvalidInput, malformed)
+	}
+}
@@ -16,13 +16,13 @@ func TestStaff_Struct(t *testing.T) {
 		Email:       "admin@test.com",
 		FirstName:   "Admin",
 		LastName:    "User",
-		IsAdmin:     true,
+		Roles:       []domain.Role{{Code: domain.RoleAdmin}},
 	}
 
 	assert.Equal(t, "admin", staff.Username)
 	assert.Equal(t, "STF001", staff.StaffCode)
 	assert.Equal(t, "admin@test.com", staff.Email)
-	assert.True(t, staff.IsAdmin)
+	assert.True(t, staff.HasRole(domain.RoleAdmin))
 }
 
 func TestStaffResponse_Struct(t *testing.T) {
@@ -58,7 +58,6 @@ func TestStaffCreateRequest_Validation(t *testing.T) {
 				Email:       "newuser@test.com",
 				FirstName:   "New",
 				LastName:    "User",
-				IsAdmin:     false,
 			},
 			isValid: true,
 		},
@@ -71,7 +70,6 @@ func TestStaffCreateRequest_Validation(t *testing.T) {
 				Email:       "admin@test.com",
 				FirstName:   "Admin",
 				LastName:    "User",
-				IsAdmin:     true,
 			},
 			isValid: true,
 		},
@@ -94,7 +92,6 @@ func TestStaffUpdateRequest_Struct(t *testing.T) {
 		Email:       "updated@test.com",
 		FirstName:   "Updated",
 		LastName:    "Name",
-		IsAdmin:     true,
 	}
 
 	assert.Equal(t, "STF002", request.StaffCode)
@@ -102,7 +99,6 @@ func TestStaffUpdateRequest_Struct(t *testing.T) {
 	assert.Equal(t, "updated@test.com", request.Email)
 	assert.Equal(t, "Updated", request.FirstName)
 	assert.Equal(t, "Name", request.LastName)
-	assert.True(t, request.IsAdmin)
 }
 
 func TestStaffLoginRequest_Struct(t *testing.T) {
@@ -149,7 +145,7 @@ func TestStaff_PasswordNotExposed(t *testing.T) {
 }
 
 func TestStaff_DefaultIsAdmin(t *testing.T) {
-	// Test that a new staff without explicit IsAdmin is false
+	// Test that a new staff without roles is not an admin
 	staff := domain.Staff{
 		Username:    "regularuser",
 		Password:    "password",
@@ -160,8 +156,9 @@ func TestStaff_DefaultIsAdmin(t *testing.T) {
 		LastName:    "User",
 	}
 
-	// IsAdmin defaults to false (zero value for bool)
-	assert.False(t, staff.IsAdmin)
+	// Admin rights only come from the admin role
+	assert.False(t, staff.HasRole(domain.RoleAdmin))
+	assert.Empty(t, staff.PermissionCodes())
 }
 
 func TestStaffCreateRequest_WithOptionalStaffCode(t *testing.T) {
@@ -179,3 +176,50 @@ func TestStaffCreateRequest_WithOptionalStaffCode(t *testing.T) {
 	assert.Empty(t, request.StaffCode)
 	assert.NotEmpty(t, request.Username)
 }
+
+func TestStaff_PermissionCodes(t *testing.T) {
+	doctor := domain.Role{
+		Code: domain.RoleDoctor,
+		Permissions: []domain.Permission{
+			{Code: domain.PermPatientRead},
+			{Code: domain.PermPatientWrite},
+		},
+	}
+	registration := domain.Role{
+		Code: domain.RoleRegistration,
+		Permissions: []domain.Permission{
+			{Code: domain.PermPatientRead},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		staff    domain.Staff
+		expected []string
+	}{
+		{
+			name:     "no roles grants nothing",
+			staff:    domain.Staff{},
+			expected: []string{},
+		},
+		{
+			name:     "permissions from multiple roles are deduplicated",
+			staff:    domain.Staff{Roles: []domain.Role{doctor, registration}},
+			expected: []string{domain.PermPatientRead, domain.PermPatientWrite},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ElementsMatch(t, tt.expected, tt.staff.PermissionCodes())
+		})
+	}
+}
+
+func TestStaff_RoleCodes(t *testing.T) {
+	staff := domain.Staff{
+		Roles: []domain.Role{{Code: domain.RoleNurse}, {Code: domain.RoleBilling}},
+	}
+
+	assert.Equal(t, []string{domain.RoleNurse, domain.RoleBilling}, staff.RoleCodes())
+}
@@ -0,0 +1,54 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wichai2002/his_v1/pkg/validation"
+)
+
+func TestIsThaiNationalID(t *testing.T) {
+	tests := []struct {
+		name     string
+		id       string
+		expected bool
+	}{
+		{"Valid ID", "1234567890121", true},
+		{"Valid ID with check digit 0", "1101700207030", true},
+		{"Wrong check digit", "1234567890123", false},
+		{"Too short", "123456789012", false},
+		{"Too long", "12345678901210", false},
+		{"Contains letters", "12345678901A1", false},
+		{"Contains dashes", "1-2345-67890-12-1", false},
+		{"Empty", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, validation.IsThaiNationalID(tt.id))
+		})
+	}
+}
+
+func TestIsPassportNumber(t *testing.T) {
+	tests := []struct {
+		name     string
+		passport string
+		expected bool
+	}{
+		{"Thai passport", "AA1234567", true},
+		{"Lower case letters", "ab123456", true},
+		{"Digits only", "123456789", true},
+		{"Letters only", "ABCDEFGH", false},
+		{"Too short", "A1234", false},
+		{"Too long", "AB12345678", false},
+		{"Contains space", "AB 123456", false},
+		{"Empty", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, validation.IsPassportNumber(tt.passport))
+		})
+	}
+}
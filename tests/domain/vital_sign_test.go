@@ -0,0 +1,97 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+func intPtr(v int) *int           { return &v }
+func floatPtr(v float64) *float64 { return &v }
+
+func TestComputeBMI(t *testing.T) {
+	assert.Equal(t, 22.9, domain.ComputeBMI(70, 175))
+	assert.Equal(t, 15.6, domain.ComputeBMI(10, 80))
+}
+
+func TestAgeInMonths(t *testing.T) {
+	born := day("2024-03-20")
+
+	tests := []struct {
+		name     string
+		at       time.Time
+		expected int
+	}{
+		{name: "day before first month", at: time.Date(2024, 4, 19, 12, 0, 0, 0, domain.HNTimeZone), expected: 0},
+		{name: "first month", at: time.Date(2024, 4, 20, 0, 30, 0, 0, domain.HNTimeZone), expected: 1},
+		{name: "second birthday", at: time.Date(2026, 3, 20, 9, 0, 0, 0, domain.HNTimeZone), expected: 24},
+		{name: "counted on the Thai day", at: time.Date(2026, 3, 19, 18, 0, 0, 0, time.UTC), expected: 24},
+		{name: "before birth", at: time.Date(2024, 1, 1, 0, 0, 0, 0, domain.HNTimeZone), expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, domain.AgeInMonths(born, tt.at))
+		})
+	}
+}
+
+func TestVitalRanges_AgeBands(t *testing.T) {
+	at := time.Date(2026, 3, 15, 9, 0, 0, 0, domain.HNTimeZone)
+
+	infant := domain.VitalRanges(day("2025-12-01"), at)
+	assert.Equal(t, domain.VitalRange{Low: 100, High: 160}, infant[domain.VitalMeasurePulse])
+	assert.Equal(t, domain.VitalRange{}, infant[domain.VitalMeasureBMI])
+
+	schoolAge := domain.VitalRanges(day("2018-01-01"), at)
+	assert.Equal(t, domain.VitalRange{Low: 18, High: 30}, schoolAge[domain.VitalMeasureRespiratoryRate])
+
+	adult := domain.VitalRanges(day("1980-06-01"), at)
+	assert.Equal(t, domain.VitalRange{Low: 60, High: 100}, adult[domain.VitalMeasurePulse])
+	assert.Equal(t, domain.VitalRange{Low: 18.5, High: 22.9}, adult[domain.VitalMeasureBMI])
+	assert.Equal(t, domain.VitalRange{Low: 95, High: 100}, adult[domain.VitalMeasureSpO2])
+}
+
+func TestVitalSign_Evaluate(t *testing.T) {
+	vital := &domain.VitalSign{
+		SystolicBP:  intPtr(150),
+		DiastolicBP: intPtr(85),
+		Pulse:       intPtr(120),
+		Temperature: floatPtr(37.0),
+		SpO2:        intPtr(92),
+		Height:      floatPtr(170),
+		BMI:         floatPtr(21.0),
+		RecordedAt:  time.Date(2026, 3, 15, 9, 0, 0, 0, domain.HNTimeZone),
+	}
+
+	// A pulse of 120 is normal for a five year old but high for an adult
+	vital.Evaluate(day("2021-01-10"))
+	measures := flaggedMeasures(vital.Flags)
+	assert.NotContains(t, measures, domain.VitalMeasurePulse)
+	assert.Contains(t, measures, domain.VitalMeasureSystolicBP)
+
+	vital.Evaluate(day("1980-06-01"))
+	assert.Equal(t, []string{domain.VitalMeasureSystolicBP, domain.VitalMeasurePulse, domain.VitalMeasureSpO2}, flaggedMeasures(vital.Flags))
+	assert.Equal(t, domain.VitalFlagHigh, vital.Flags[0].Flag)
+	assert.Equal(t, 139.0, vital.Flags[0].RangeHigh)
+	assert.Equal(t, domain.VitalFlagLow, vital.Flags[2].Flag)
+}
+
+func TestVitalSign_Evaluate_NoFlags(t *testing.T) {
+	vital := &domain.VitalSign{Weight: floatPtr(60), RecordedAt: time.Now()}
+
+	vital.Evaluate(day("1980-06-01"))
+
+	assert.NotNil(t, vital.Flags)
+	assert.Empty(t, vital.Flags)
+}
+
+func flaggedMeasures(flags []domain.VitalFlag) []string {
+	measures := make([]string, len(flags))
+	for i, flag := range flags {
+		measures[i] = flag.Measure
+	}
+	return measures
+}
@@ -0,0 +1,175 @@
+package handler_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/delivery/http/handler"
+	"github.com/wichai2002/his_v1/internal/delivery/http/middleware"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/mocks"
+)
+
+// setupAddressRouter creates a test router for the reference lookups and patient addresses
+func setupAddressRouter(mockAddressService *mocks.MockAddressService, mockPatientAddressService *mocks.MockPatientAddressService, permissions []string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.TenantSchemaKey, testSchemaName)
+		c.Set("permissions", permissions)
+		c.Next()
+	})
+
+	addressHandler := handler.NewAddressHandler(mockAddressService)
+	patientAddressHandler := handler.NewPatientAddressHandler(mockPatientAddressService)
+
+	address := router.Group("/address")
+	{
+		address.GET("/provinces/:code/districts", addressHandler.ListDistricts)
+		address.GET("/postal-codes/:postal_code", addressHandler.FindByPostalCode)
+		address.GET("/autocomplete", addressHandler.Autocomplete)
+	}
+
+	patientAddresses := router.Group("/patient/:id/addresses")
+	{
+		patientAddresses.GET("", middleware.RequirePermission(domain.PermPatientRead), patientAddressHandler.List)
+		patientAddresses.PUT("/:type", middleware.RequirePermission(domain.PermPatientWrite), patientAddressHandler.Save)
+		patientAddresses.DELETE("/:type", middleware.RequirePermission(domain.PermPatientWrite), patientAddressHandler.Delete)
+	}
+
+	return router
+}
+
+func TestAddressHandler_Autocomplete(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		setup          func(m *mocks.MockAddressService)
+		expectedStatus int
+	}{
+		{
+			name: "suggestions",
+			url:  "/address/autocomplete?q=Lumphini&limit=5",
+			setup: func(m *mocks.MockAddressService) {
+				m.On("Autocomplete", &domain.AddressSearchRequest{Query: "Lumphini", Limit: 5}).
+					Return([]domain.AddressLocation{{SubdistrictCode: "100704", PostalCode: "10330"}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing query",
+			url:            "/address/autocomplete",
+			setup:          func(m *mocks.MockAddressService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "query too short",
+			url:  "/address/autocomplete?q=%E0%B8%A5%20",
+			setup: func(m *mocks.MockAddressService) {
+				m.On("Autocomplete", mock.Anything).Return(nil, domain.ErrInvalidInput)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockAddressService()
+			tt.setup(mockService)
+			router := setupAddressRouter(mockService, mocks.NewMockPatientAddressService(), nil)
+
+			req, _ := http.NewRequest("GET", tt.url, nil)
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tt.expectedStatus, resp.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAddressHandler_FindByPostalCode_NotFound(t *testing.T) {
+	mockService := mocks.NewMockAddressService()
+	mockService.On("FindByPostalCode", "99999").Return(nil, domain.ErrNotFound)
+	router := setupAddressRouter(mockService, mocks.NewMockPatientAddressService(), nil)
+
+	req, _ := http.NewRequest("GET", "/address/postal-codes/99999", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+	assert.Contains(t, resp.Body.String(), "postal code not found")
+}
+
+func TestPatientAddressHandler_Save(t *testing.T) {
+	validBody := `{"house_no":"99/1","road":"Witthayu","subdistrict_code":"100704","postal_code":"10330"}`
+
+	tests := []struct {
+		name           string
+		path           string
+		body           string
+		permissions    []string
+		setup          func(m *mocks.MockPatientAddressService)
+		expectedStatus int
+	}{
+		{
+			name:        "saved",
+			path:        "/patient/1/addresses/registered",
+			body:        validBody,
+			permissions: []string{domain.PermPatientWrite},
+			setup: func(m *mocks.MockPatientAddressService) {
+				m.On("Save", uint(1), domain.AddressTypeRegistered, mock.AnythingOfType("*domain.PatientAddressRequest"), mock.AnythingOfType("*domain.Actor"), testSchemaName).
+					Return(&domain.PatientAddress{PatientID: 1, AddressType: domain.AddressTypeRegistered, Tambon: "ลุมพินี"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:        "postal code mismatch",
+			path:        "/patient/1/addresses/current",
+			body:        `{"house_no":"99/1","subdistrict_code":"100704","postal_code":"10110"}`,
+			permissions: []string{domain.PermPatientWrite},
+			setup: func(m *mocks.MockPatientAddressService) {
+				m.On("Save", uint(1), domain.AddressTypeCurrent, mock.Anything, mock.Anything, testSchemaName).Return(nil, domain.ErrInvalidInput)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "malformed postal code",
+			path:           "/patient/1/addresses/work",
+			body:           `{"house_no":"99/1","subdistrict_code":"100704","postal_code":"1033"}`,
+			permissions:    []string{domain.PermPatientWrite},
+			setup:          func(m *mocks.MockPatientAddressService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "read permission only",
+			path:           "/patient/1/addresses/registered",
+			body:           validBody,
+			permissions:    []string{domain.PermPatientRead},
+			setup:          func(m *mocks.MockPatientAddressService) {},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockPatientAddressService()
+			tt.setup(mockService)
+			router := setupAddressRouter(mocks.NewMockAddressService(), mockService, tt.permissions)
+
+			req, _ := http.NewRequest("PUT", tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tt.expectedStatus, resp.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,226 @@
+package handler_test
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/delivery/http/handler"
+	"github.com/wichai2002/his_v1/internal/delivery/http/middleware"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/mocks"
+)
+
+// setupAppointmentRouter creates a test router with tenant context and the given permissions
+func setupAppointmentRouter(mockService *mocks.MockAppointmentService, permissions []string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.TenantSchemaKey, testSchemaName)
+		c.Set("permissions", permissions)
+		c.Next()
+	})
+
+	appointmentHandler := handler.NewAppointmentHandler(mockService)
+
+	appointments := router.Group("/appointments")
+	{
+		appointments.POST("", middleware.RequirePermission(domain.PermAppointmentWrite), appointmentHandler.Book)
+		appointments.GET("/calendar", middleware.RequirePermission(domain.PermPatientRead), appointmentHandler.Calendar)
+		appointments.GET("/:id", middleware.RequirePermission(domain.PermPatientRead), appointmentHandler.GetByID)
+		appointments.POST("/:id/reschedule", middleware.RequirePermission(domain.PermAppointmentWrite), appointmentHandler.Reschedule)
+		appointments.POST("/:id/cancel", middleware.RequirePermission(domain.PermAppointmentWrite), appointmentHandler.Cancel)
+	}
+	router.GET("/patient/:id/appointments", middleware.RequirePermission(domain.PermPatientRead), appointmentHandler.ListByPatient)
+
+	return router
+}
+
+func TestAppointmentHandler_Book(t *testing.T) {
+	validBody := `{"patient_id":1,"slot_id":11,"reason":"follow-up"}`
+
+	tests := []struct {
+		name           string
+		body           string
+		permissions    []string
+		setup          func(m *mocks.MockAppointmentService)
+		expectedStatus int
+	}{
+		{
+			name:        "booked",
+			body:        validBody,
+			permissions: []string{domain.PermAppointmentWrite},
+			setup: func(m *mocks.MockAppointmentService) {
+				m.On("Book", mock.AnythingOfType("*domain.AppointmentBookRequest"), mock.AnythingOfType("*domain.Actor"), testSchemaName).
+					Return(&domain.Appointment{PatientID: 1, SlotID: 11, Status: domain.AppointmentStatusBooked}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "missing slot",
+			body:           `{"patient_id":1}`,
+			permissions:    []string{domain.PermAppointmentWrite},
+			setup:          func(m *mocks.MockAppointmentService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "slot already booked",
+			body:        validBody,
+			permissions: []string{domain.PermAppointmentWrite},
+			setup: func(m *mocks.MockAppointmentService) {
+				m.On("Book", mock.Anything, mock.Anything, testSchemaName).Return(nil, domain.ErrDuplicateEntry)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:        "slot has started",
+			body:        validBody,
+			permissions: []string{domain.PermAppointmentWrite},
+			setup: func(m *mocks.MockAppointmentService) {
+				m.On("Book", mock.Anything, mock.Anything, testSchemaName).
+					Return(nil, fmt.Errorf("%w: slot 11 has already started", domain.ErrInvalidInput))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "read only staff cannot book",
+			body:           validBody,
+			permissions:    []string{domain.PermPatientRead},
+			setup:          func(m *mocks.MockAppointmentService) {},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockAppointmentService()
+			tt.setup(mockService)
+			router := setupAppointmentRouter(mockService, tt.permissions)
+
+			req, _ := http.NewRequest("POST", "/appointments", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tt.expectedStatus, resp.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAppointmentHandler_Reschedule(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+	}{
+		{name: "rescheduled", expectedStatus: http.StatusCreated},
+		{name: "not booked", err: fmt.Errorf("%w: appointment 20 is cancelled", domain.ErrInvalidStatusTransition), expectedStatus: http.StatusConflict},
+		{name: "changed concurrently", err: domain.ErrPreconditionFailed, expectedStatus: http.StatusConflict},
+		{name: "not found", err: domain.ErrNotFound, expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockAppointmentService()
+			if tt.err != nil {
+				mockService.On("Reschedule", uint(20), mock.AnythingOfType("*domain.AppointmentRescheduleRequest"), mock.Anything, testSchemaName).Return(nil, tt.err)
+			} else {
+				mockService.On("Reschedule", uint(20), mock.AnythingOfType("*domain.AppointmentRescheduleRequest"), mock.Anything, testSchemaName).
+					Return(&domain.Appointment{SlotID: 12, Status: domain.AppointmentStatusBooked}, nil)
+			}
+			router := setupAppointmentRouter(mockService, []string{domain.PermAppointmentWrite})
+
+			req, _ := http.NewRequest("POST", "/appointments/20/reschedule", bytes.NewBufferString(`{"slot_id":12}`))
+			req.Header.Set("Content-Type", "application/json")
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tt.expectedStatus, resp.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAppointmentHandler_Cancel_RequiresReason(t *testing.T) {
+	mockService := mocks.NewMockAppointmentService()
+	router := setupAppointmentRouter(mockService, []string{domain.PermAppointmentWrite})
+
+	req, _ := http.NewRequest("POST", "/appointments/20/cancel", bytes.NewBufferString(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	mockService.AssertNotCalled(t, "Cancel", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAppointmentHandler_Calendar(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		setup          func(m *mocks.MockAppointmentService)
+		expectedStatus int
+	}{
+		{
+			name:  "week of a doctor",
+			query: "?doctor_id=3&date=2026-03-18&view=week",
+			setup: func(m *mocks.MockAppointmentService) {
+				m.On("Calendar", mock.MatchedBy(func(r *domain.AppointmentCalendarRequest) bool {
+					return r.DoctorID == 3 && r.Date == "2026-03-18" && r.View == domain.CalendarViewWeek
+				}), mock.Anything, testSchemaName).Return(&domain.AppointmentCalendar{View: domain.CalendarViewWeek}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "unknown view",
+			query:          "?doctor_id=3&view=month",
+			setup:          func(m *mocks.MockAppointmentService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "no doctor or department",
+			query: "",
+			setup: func(m *mocks.MockAppointmentService) {
+				m.On("Calendar", mock.Anything, mock.Anything, testSchemaName).
+					Return(nil, fmt.Errorf("%w: doctor_id or department_id is required", domain.ErrInvalidInput))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockAppointmentService()
+			tt.setup(mockService)
+			router := setupAppointmentRouter(mockService, []string{domain.PermPatientRead})
+
+			req, _ := http.NewRequest("GET", "/appointments/calendar"+tt.query, nil)
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tt.expectedStatus, resp.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAppointmentHandler_ListByPatient(t *testing.T) {
+	mockService := mocks.NewMockAppointmentService()
+	mockService.On("ListByPatient", uint(1), mock.Anything, testSchemaName).
+		Return([]domain.Appointment{{PatientID: 1, Status: domain.AppointmentStatusBooked}}, nil)
+	router := setupAppointmentRouter(mockService, []string{domain.PermPatientRead})
+
+	req, _ := http.NewRequest("GET", "/patient/1/appointments", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,132 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/delivery/http/handler"
+	"github.com/wichai2002/his_v1/internal/delivery/http/middleware"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/mocks"
+)
+
+// setupAuditRouter creates a test router with tenant context and the given permissions
+func setupAuditRouter(mockService *mocks.MockAuditService, permissions []string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.TenantSchemaKey, testSchemaName)
+		c.Set("permissions", permissions)
+		c.Next()
+	})
+
+	auditHandler := handler.NewAuditHandler(mockService)
+
+	audit := router.Group("/audit")
+	audit.Use(middleware.RequirePermission(domain.PermAuditRead))
+	{
+		audit.GET("/events", auditHandler.Query)
+		audit.GET("/verify", auditHandler.Verify)
+	}
+
+	return router
+}
+
+func TestAuditHandler_Query_ByPatient(t *testing.T) {
+	mockService := mocks.NewMockAuditService()
+	router := setupAuditRouter(mockService, []string{domain.PermAuditRead})
+
+	mockService.On("Query", mock.MatchedBy(func(filter *domain.AuditFilter) bool {
+		return filter.PatientID != nil && *filter.PatientID == 5 && filter.ActorID == nil && filter.Limit == 20
+	}), testSchemaName).Return([]domain.AuditEvent{{ID: 1, Action: domain.AuditActionPatientView}}, int64(1), nil)
+
+	req, _ := http.NewRequest("GET", "/audit/events?patient_id=5&limit=20", nil)
+	resp := httptest.NewRecorder()
+
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestAuditHandler_Query_ByStaffAndDateRange(t *testing.T) {
+	mockService := mocks.NewMockAuditService()
+	router := setupAuditRouter(mockService, []string{domain.PermAuditRead})
+
+	mockService.On("Query", mock.MatchedBy(func(filter *domain.AuditFilter) bool {
+		// The to date is inclusive, so it is moved to the start of the next day
+		return filter.ActorID != nil && *filter.ActorID == 3 &&
+			filter.From.Format(domain.DateFormat) == "2024-01-01" &&
+			filter.To.Format(domain.DateFormat) == "2024-02-01"
+	}), testSchemaName).Return([]domain.AuditEvent{}, int64(0), nil)
+
+	req, _ := http.NewRequest("GET", "/audit/events?staff_id=3&from=2024-01-01&to=2024-01-31", nil)
+	resp := httptest.NewRecorder()
+
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestAuditHandler_Query_InvalidParams(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+	}{
+		{name: "invalid patient_id", url: "/audit/events?patient_id=abc"},
+		{name: "invalid staff_id", url: "/audit/events?staff_id=-1"},
+		{name: "invalid date", url: "/audit/events?from=01-01-2024"},
+		{name: "invalid limit", url: "/audit/events?limit=ten"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockAuditService()
+			router := setupAuditRouter(mockService, []string{domain.PermAuditRead})
+
+			req, _ := http.NewRequest("GET", tt.url, nil)
+			resp := httptest.NewRecorder()
+
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, http.StatusBadRequest, resp.Code)
+			mockService.AssertNotCalled(t, "Query", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestAuditHandler_Verify(t *testing.T) {
+	mockService := mocks.NewMockAuditService()
+	router := setupAuditRouter(mockService, []string{domain.PermAuditRead})
+
+	brokenAt := uint(42)
+	mockService.On("Verify", testSchemaName).Return(&domain.AuditVerifyResult{Valid: false, CheckedEvents: 41, BrokenAtID: &brokenAt}, nil)
+
+	req, _ := http.NewRequest("GET", "/audit/verify", nil)
+	resp := httptest.NewRecorder()
+
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"broken_at_id":42`)
+	mockService.AssertExpectations(t)
+}
+
+func TestAuditHandler_MissingPermission(t *testing.T) {
+	mockService := mocks.NewMockAuditService()
+	router := setupAuditRouter(mockService, []string{domain.PermPatientRead})
+
+	req, _ := http.NewRequest("GET", "/audit/events?patient_id=1", nil)
+	resp := httptest.NewRecorder()
+
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+	mockService.AssertNotCalled(t, "Query", mock.Anything, mock.Anything)
+}
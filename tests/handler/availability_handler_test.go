@@ -0,0 +1,137 @@
+package handler_test
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/delivery/http/handler"
+	"github.com/wichai2002/his_v1/internal/delivery/http/middleware"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/mocks"
+)
+
+// setupAvailabilityRouter creates a test router with tenant context and the given permissions
+func setupAvailabilityRouter(mockService *mocks.MockAvailabilityService, permissions []string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.TenantSchemaKey, testSchemaName)
+		c.Set("permissions", permissions)
+		c.Next()
+	})
+
+	availabilityHandler := handler.NewAvailabilityHandler(mockService)
+
+	availability := router.Group("/availability")
+	{
+		availability.GET("", availabilityHandler.List)
+		availability.POST("", middleware.RequirePermission(domain.PermScheduleManage), availabilityHandler.Create)
+		availability.POST("/:id/expand", middleware.RequirePermission(domain.PermScheduleManage), availabilityHandler.Expand)
+		availability.DELETE("/:id", middleware.RequirePermission(domain.PermScheduleManage), availabilityHandler.Delete)
+	}
+
+	return router
+}
+
+func TestAvailabilityHandler_Create(t *testing.T) {
+	validBody := `{"doctor_id":3,"department_id":2,"weekday":1,"start_time":"09:00","end_time":"12:00","slot_minutes":15,"valid_from":"2026-03-16"}`
+
+	tests := []struct {
+		name           string
+		body           string
+		permissions    []string
+		setup          func(m *mocks.MockAvailabilityService)
+		expectedStatus int
+	}{
+		{
+			name:        "created",
+			body:        validBody,
+			permissions: []string{domain.PermScheduleManage},
+			setup: func(m *mocks.MockAvailabilityService) {
+				m.On("Create", mock.MatchedBy(func(r *domain.AvailabilityTemplateRequest) bool {
+					return r.Weekday != nil && *r.Weekday == 1 && r.SlotMinutes == 15
+				}), testSchemaName).Return(&domain.AvailabilityTemplate{DoctorID: 3, Weekday: 1}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "sunday is weekday zero",
+			body:           `{"doctor_id":3,"department_id":2,"start_time":"09:00","end_time":"12:00","slot_minutes":15,"valid_from":"2026-03-16"}`,
+			permissions:    []string{domain.PermScheduleManage},
+			setup:          func(m *mocks.MockAvailabilityService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "malformed time",
+			body:           `{"doctor_id":3,"department_id":2,"weekday":1,"start_time":"9am","end_time":"12:00","slot_minutes":15,"valid_from":"2026-03-16"}`,
+			permissions:    []string{domain.PermScheduleManage},
+			setup:          func(m *mocks.MockAvailabilityService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "overlapping template",
+			body:        validBody,
+			permissions: []string{domain.PermScheduleManage},
+			setup: func(m *mocks.MockAvailabilityService) {
+				m.On("Create", mock.Anything, testSchemaName).
+					Return(nil, fmt.Errorf("%w: availability overlaps template 4", domain.ErrDuplicateEntry))
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "nurses cannot publish availability",
+			body:           validBody,
+			permissions:    []string{domain.PermPatientRead, domain.PermAppointmentWrite},
+			setup:          func(m *mocks.MockAvailabilityService) {},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockAvailabilityService()
+			tt.setup(mockService)
+			router := setupAvailabilityRouter(mockService, tt.permissions)
+
+			req, _ := http.NewRequest("POST", "/availability", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tt.expectedStatus, resp.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAvailabilityHandler_List_InvalidDoctorID(t *testing.T) {
+	mockService := mocks.NewMockAvailabilityService()
+	router := setupAvailabilityRouter(mockService, nil)
+
+	req, _ := http.NewRequest("GET", "/availability?doctor_id=abc", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	mockService.AssertNotCalled(t, "List", mock.Anything, mock.Anything)
+}
+
+func TestAvailabilityHandler_Delete_NotFound(t *testing.T) {
+	mockService := mocks.NewMockAvailabilityService()
+	mockService.On("Delete", uint(7), testSchemaName).Return(domain.ErrNotFound)
+	router := setupAvailabilityRouter(mockService, []string{domain.PermScheduleManage})
+
+	req, _ := http.NewRequest("DELETE", "/availability/7", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,146 @@
+package handler_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/delivery/http/handler"
+	"github.com/wichai2002/his_v1/internal/delivery/http/middleware"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/mocks"
+)
+
+// setupDepartmentRouter creates a test router with tenant context and the given permissions
+func setupDepartmentRouter(mockService *mocks.MockDepartmentService, permissions []string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.TenantSchemaKey, testSchemaName)
+		c.Set("permissions", permissions)
+		c.Next()
+	})
+
+	departmentHandler := handler.NewDepartmentHandler(mockService)
+
+	departments := router.Group("/departments")
+	{
+		departments.GET("", departmentHandler.List)
+		departments.POST("", middleware.RequirePermission(domain.PermSettingsManage), departmentHandler.Create)
+		departments.PUT("/:id", middleware.RequirePermission(domain.PermSettingsManage), departmentHandler.Update)
+	}
+
+	return router
+}
+
+func TestDepartmentHandler_List(t *testing.T) {
+	tests := []struct {
+		name            string
+		query           string
+		includeInactive bool
+		expectedStatus  int
+	}{
+		{name: "active only", query: "", expectedStatus: http.StatusOK},
+		{name: "include inactive", query: "?include_inactive=true", includeInactive: true, expectedStatus: http.StatusOK},
+		{name: "invalid flag", query: "?include_inactive=maybe", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockDepartmentService()
+			if tt.expectedStatus == http.StatusOK {
+				mockService.On("List", tt.includeInactive, testSchemaName).Return([]domain.Department{{Code: "OPD", IsActive: true}}, nil)
+			}
+			router := setupDepartmentRouter(mockService, []string{})
+
+			req, _ := http.NewRequest("GET", "/departments"+tt.query, nil)
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tt.expectedStatus, resp.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDepartmentHandler_Create(t *testing.T) {
+	validBody := `{"code":"ENT","name_th":"หู คอ จมูก","name_en":"Otolaryngology"}`
+
+	tests := []struct {
+		name           string
+		body           string
+		permissions    []string
+		setup          func(m *mocks.MockDepartmentService)
+		expectedStatus int
+	}{
+		{
+			name:        "created",
+			body:        validBody,
+			permissions: []string{domain.PermSettingsManage},
+			setup: func(m *mocks.MockDepartmentService) {
+				m.On("Create", mock.AnythingOfType("*domain.DepartmentRequest"), testSchemaName).
+					Return(&domain.Department{Code: "ENT", IsActive: true}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "code with spaces",
+			body:           `{"code":"E N T","name_th":"หู คอ จมูก","name_en":"Otolaryngology"}`,
+			permissions:    []string{domain.PermSettingsManage},
+			setup:          func(m *mocks.MockDepartmentService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "duplicate code",
+			body:        validBody,
+			permissions: []string{domain.PermSettingsManage},
+			setup: func(m *mocks.MockDepartmentService) {
+				m.On("Create", mock.Anything, testSchemaName).Return(nil, domain.ErrDuplicateEntry)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "requires settings:manage",
+			body:           validBody,
+			permissions:    []string{domain.PermEncounterWrite},
+			setup:          func(m *mocks.MockDepartmentService) {},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockDepartmentService()
+			tt.setup(mockService)
+			router := setupDepartmentRouter(mockService, tt.permissions)
+
+			req, _ := http.NewRequest("POST", "/departments", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tt.expectedStatus, resp.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDepartmentHandler_Update_NotFound(t *testing.T) {
+	mockService := mocks.NewMockDepartmentService()
+	mockService.On("Update", uint(99), mock.AnythingOfType("*domain.DepartmentRequest"), testSchemaName).Return(nil, domain.ErrNotFound)
+	router := setupDepartmentRouter(mockService, []string{domain.PermSettingsManage})
+
+	body := `{"code":"DENT","name_th":"ทันตกรรม","name_en":"Dental","is_active":false}`
+	req, _ := http.NewRequest("PUT", "/departments/99", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,246 @@
+package handler_test
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/delivery/http/handler"
+	"github.com/wichai2002/his_v1/internal/delivery/http/middleware"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/mocks"
+)
+
+// setupDiagnosisRouter creates a test router with tenant context and the given permissions
+func setupDiagnosisRouter(mockService *mocks.MockDiagnosisService, mockICD10 *mocks.MockICD10Service, permissions []string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.TenantSchemaKey, testSchemaName)
+		c.Set("permissions", permissions)
+		c.Next()
+	})
+
+	diagnosisHandler := handler.NewDiagnosisHandler(mockService)
+	icd10Handler := handler.NewICD10Handler(mockICD10)
+
+	router.GET("/icd10", icd10Handler.Search)
+	router.GET("/icd10/:code", icd10Handler.GetByCode)
+	router.POST("/encounters/:id/diagnoses", middleware.RequirePermission(domain.PermDiagnosisWrite), diagnosisHandler.Create)
+	router.GET("/encounters/:id/diagnoses", middleware.RequirePermission(domain.PermPatientRead), diagnosisHandler.ListByEncounter)
+	router.DELETE("/encounters/:id/diagnoses/:diagnosis_id", middleware.RequirePermission(domain.PermDiagnosisWrite), diagnosisHandler.Delete)
+	router.GET("/patient/:id/diagnoses", middleware.RequirePermission(domain.PermPatientRead), diagnosisHandler.ListByPatient)
+
+	return router
+}
+
+func TestDiagnosisHandler_Create(t *testing.T) {
+	validBody := `{"code":"J18.9","type":"principal"}`
+
+	tests := []struct {
+		name           string
+		body           string
+		permissions    []string
+		setup          func(m *mocks.MockDiagnosisService)
+		expectedStatus int
+	}{
+		{
+			name:        "created",
+			body:        validBody,
+			permissions: []string{domain.PermDiagnosisWrite},
+			setup: func(m *mocks.MockDiagnosisService) {
+				m.On("Create", uint(12), mock.MatchedBy(func(r *domain.DiagnosisRequest) bool {
+					return r.Code == "J18.9" && r.Type == domain.DiagnosisTypePrincipal
+				}), mock.AnythingOfType("*domain.Actor"), testSchemaName).Return(&domain.Diagnosis{Code: "J18.9"}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "unknown type",
+			body:           `{"code":"J18.9","type":"secondary"}`,
+			permissions:    []string{domain.PermDiagnosisWrite},
+			setup:          func(m *mocks.MockDiagnosisService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "non-billable principal",
+			body:        `{"code":"J18","type":"principal"}`,
+			permissions: []string{domain.PermDiagnosisWrite},
+			setup: func(m *mocks.MockDiagnosisService) {
+				m.On("Create", uint(12), mock.Anything, mock.Anything, testSchemaName).
+					Return(nil, fmt.Errorf("%w: J18 is not a billable code", domain.ErrInvalidInput))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "second principal",
+			body:        validBody,
+			permissions: []string{domain.PermDiagnosisWrite},
+			setup: func(m *mocks.MockDiagnosisService) {
+				m.On("Create", uint(12), mock.Anything, mock.Anything, testSchemaName).
+					Return(nil, fmt.Errorf("%w: encounter 69031500001 already has principal diagnosis A09.9", domain.ErrDuplicateEntry))
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:        "encounter not found",
+			body:        validBody,
+			permissions: []string{domain.PermDiagnosisWrite},
+			setup: func(m *mocks.MockDiagnosisService) {
+				m.On("Create", uint(12), mock.Anything, mock.Anything, testSchemaName).Return(nil, domain.ErrNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "nurse cannot diagnose",
+			body:           validBody,
+			permissions:    []string{domain.PermPatientRead, domain.PermVitalsWrite},
+			setup:          func(m *mocks.MockDiagnosisService) {},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockDiagnosisService()
+			tt.setup(mockService)
+			router := setupDiagnosisRouter(mockService, mocks.NewMockICD10Service(), tt.permissions)
+
+			req, _ := http.NewRequest("POST", "/encounters/12/diagnoses", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tt.expectedStatus, resp.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDiagnosisHandler_Delete(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		setup          func(m *mocks.MockDiagnosisService)
+		expectedStatus int
+	}{
+		{
+			name: "deleted",
+			path: "/encounters/12/diagnoses/3",
+			setup: func(m *mocks.MockDiagnosisService) {
+				m.On("Delete", uint(12), uint(3), mock.Anything, testSchemaName).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid diagnosis id",
+			path:           "/encounters/12/diagnoses/abc",
+			setup:          func(m *mocks.MockDiagnosisService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "diagnosis not found",
+			path: "/encounters/12/diagnoses/3",
+			setup: func(m *mocks.MockDiagnosisService) {
+				m.On("Delete", uint(12), uint(3), mock.Anything, testSchemaName).Return(domain.ErrNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockDiagnosisService()
+			tt.setup(mockService)
+			router := setupDiagnosisRouter(mockService, mocks.NewMockICD10Service(), []string{domain.PermDiagnosisWrite})
+
+			req, _ := http.NewRequest("DELETE", tt.path, nil)
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tt.expectedStatus, resp.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDiagnosisHandler_ListByPatient(t *testing.T) {
+	mockService := mocks.NewMockDiagnosisService()
+	mockService.On("ListByPatient", uint(1), mock.Anything, testSchemaName).Return([]domain.Diagnosis{{Code: "J18.9"}}, nil)
+	router := setupDiagnosisRouter(mockService, mocks.NewMockICD10Service(), []string{domain.PermPatientRead})
+
+	req, _ := http.NewRequest("GET", "/patient/1/diagnoses", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestICD10Handler_Search(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		setup          func(m *mocks.MockICD10Service)
+		expectedStatus int
+	}{
+		{
+			name:  "keyword search",
+			query: "?q=pneumonia&billable_only=true",
+			setup: func(m *mocks.MockICD10Service) {
+				m.On("Search", mock.MatchedBy(func(r *domain.ICD10SearchRequest) bool {
+					return r.Query == "pneumonia" && r.BillableOnly
+				})).Return([]domain.ICD10Code{{Code: "J18.9"}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing query",
+			query:          "",
+			setup:          func(m *mocks.MockICD10Service) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "query too short",
+			query: "?q=J",
+			setup: func(m *mocks.MockICD10Service) {
+				m.On("Search", mock.Anything).Return(nil, fmt.Errorf("%w: query must be at least 2 characters", domain.ErrInvalidInput))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockICD10 := mocks.NewMockICD10Service()
+			tt.setup(mockICD10)
+			router := setupDiagnosisRouter(mocks.NewMockDiagnosisService(), mockICD10, nil)
+
+			req, _ := http.NewRequest("GET", "/icd10"+tt.query, nil)
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tt.expectedStatus, resp.Code)
+			mockICD10.AssertExpectations(t)
+		})
+	}
+}
+
+func TestICD10Handler_GetByCode_NotFound(t *testing.T) {
+	mockICD10 := mocks.NewMockICD10Service()
+	mockICD10.On("GetByCode", "J99.9").Return(nil, domain.ErrNotFound)
+	router := setupDiagnosisRouter(mocks.NewMockDiagnosisService(), mockICD10, nil)
+
+	req, _ := http.NewRequest("GET", "/icd10/J99.9", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+	mockICD10.AssertExpectations(t)
+}
@@ -0,0 +1,212 @@
+package handler_test
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/delivery/http/handler"
+	"github.com/wichai2002/his_v1/internal/delivery/http/middleware"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/mocks"
+)
+
+// setupEncounterRouter creates a test router with tenant context and the given permissions
+func setupEncounterRouter(mockService *mocks.MockEncounterService, permissions []string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.TenantSchemaKey, testSchemaName)
+		c.Set("permissions", permissions)
+		c.Next()
+	})
+
+	encounterHandler := handler.NewEncounterHandler(mockService)
+
+	encounters := router.Group("/encounters")
+	{
+		encounters.POST("", middleware.RequirePermission(domain.PermEncounterWrite), encounterHandler.Create)
+		encounters.GET("/queue", middleware.RequirePermission(domain.PermPatientRead), encounterHandler.Queue)
+		encounters.GET("/:id", middleware.RequirePermission(domain.PermPatientRead), encounterHandler.GetByID)
+		encounters.POST("/:id/status", middleware.RequirePermission(domain.PermEncounterWrite), encounterHandler.UpdateStatus)
+	}
+	router.GET("/patient/:id/encounters", middleware.RequirePermission(domain.PermPatientRead), encounterHandler.ListByPatient)
+
+	return router
+}
+
+func TestEncounterHandler_Create(t *testing.T) {
+	validBody := `{"patient_id":1,"department_id":2,"chief_complaint":"fever"}`
+
+	tests := []struct {
+		name           string
+		body           string
+		permissions    []string
+		setup          func(m *mocks.MockEncounterService)
+		expectedStatus int
+	}{
+		{
+			name:        "registered",
+			body:        validBody,
+			permissions: []string{domain.PermEncounterWrite},
+			setup: func(m *mocks.MockEncounterService) {
+				m.On("Create", mock.AnythingOfType("*domain.EncounterCreateRequest"), mock.AnythingOfType("*domain.Actor"), testSchemaName).
+					Return(&domain.Encounter{VN: "69031500001", PatientID: 1, DepartmentID: 2, Status: domain.EncounterStatusRegistered}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "missing department",
+			body:           `{"patient_id":1}`,
+			permissions:    []string{domain.PermEncounterWrite},
+			setup:          func(m *mocks.MockEncounterService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "patient not found",
+			body:        validBody,
+			permissions: []string{domain.PermEncounterWrite},
+			setup: func(m *mocks.MockEncounterService) {
+				m.On("Create", mock.Anything, mock.Anything, testSchemaName).Return(nil, domain.ErrNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:        "inactive department",
+			body:        validBody,
+			permissions: []string{domain.PermEncounterWrite},
+			setup: func(m *mocks.MockEncounterService) {
+				m.On("Create", mock.Anything, mock.Anything, testSchemaName).
+					Return(nil, fmt.Errorf("%w: department DENT is not active", domain.ErrInvalidInput))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "billing cannot register visits",
+			body:           validBody,
+			permissions:    []string{domain.PermPatientRead, domain.PermCoverageWrite},
+			setup:          func(m *mocks.MockEncounterService) {},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockEncounterService()
+			tt.setup(mockService)
+			router := setupEncounterRouter(mockService, tt.permissions)
+
+			req, _ := http.NewRequest("POST", "/encounters", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tt.expectedStatus, resp.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestEncounterHandler_UpdateStatus(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		err            error
+		callsService   bool
+		expectedStatus int
+	}{
+		{name: "advanced", body: `{"status":"triaged"}`, callsService: true, expectedStatus: http.StatusOK},
+		{name: "unknown status", body: `{"status":"registered"}`, expectedStatus: http.StatusBadRequest},
+		{
+			name:           "skips a step",
+			body:           `{"status":"done"}`,
+			err:            fmt.Errorf("%w: encounter 69031500001 is registered and cannot move to done", domain.ErrInvalidStatusTransition),
+			callsService:   true,
+			expectedStatus: http.StatusConflict,
+		},
+		{name: "changed concurrently", body: `{"status":"triaged"}`, err: domain.ErrPreconditionFailed, callsService: true, expectedStatus: http.StatusConflict},
+		{name: "encounter not found", body: `{"status":"triaged"}`, err: domain.ErrNotFound, callsService: true, expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockEncounterService()
+			if tt.callsService {
+				var encounter *domain.Encounter
+				if tt.err == nil {
+					encounter = &domain.Encounter{VN: "69031500001", Status: domain.EncounterStatusTriaged}
+				}
+				mockService.On("UpdateStatus", uint(9), mock.AnythingOfType("*domain.EncounterStatusRequest"), mock.AnythingOfType("*domain.Actor"), testSchemaName).
+					Return(encounter, tt.err)
+			}
+			router := setupEncounterRouter(mockService, []string{domain.PermEncounterWrite})
+
+			req, _ := http.NewRequest("POST", "/encounters/9/status", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tt.expectedStatus, resp.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestEncounterHandler_Queue(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		callsService   bool
+		expectedStatus int
+	}{
+		{name: "department queue", query: "?department_id=2", callsService: true, expectedStatus: http.StatusOK},
+		{name: "triaged only", query: "?department_id=2&status=triaged", callsService: true, expectedStatus: http.StatusOK},
+		{name: "missing department", query: "", expectedStatus: http.StatusBadRequest},
+		{name: "done is not waiting", query: "?department_id=2&status=done", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockEncounterService()
+			if tt.callsService {
+				mockService.On("Queue", mock.MatchedBy(func(req *domain.EncounterQueueRequest) bool {
+					return req.DepartmentID == 2
+				}), mock.AnythingOfType("*domain.Actor"), testSchemaName).Return([]domain.EncounterQueueEntry{
+					{Position: 1, Encounter: domain.Encounter{VN: "69031500001"}, PatientHN: "HN0001"},
+				}, nil)
+			}
+			router := setupEncounterRouter(mockService, []string{domain.PermPatientRead})
+
+			req, _ := http.NewRequest("GET", "/encounters/queue"+tt.query, nil)
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tt.expectedStatus, resp.Code)
+			if tt.callsService {
+				assert.Contains(t, resp.Body.String(), `"position":1`)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestEncounterHandler_ListByPatient(t *testing.T) {
+	mockService := mocks.NewMockEncounterService()
+	mockService.On("ListByPatient", uint(1), mock.AnythingOfType("*domain.Actor"), testSchemaName).
+		Return([]domain.Encounter{{VN: "69031500001", PatientID: 1}}, nil)
+	router := setupEncounterRouter(mockService, []string{domain.PermPatientRead})
+
+	req, _ := http.NewRequest("GET", "/patient/1/encounters", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"vn":"69031500001"`)
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,169 @@
+package handler_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/delivery/http/middleware"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/mocks"
+)
+
+// setupIdempotentRouter creates a test router whose create handler counts its calls
+func setupIdempotentRouter(mockService *mocks.MockIdempotencyService, status int, calls *int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.TenantSchemaKey, testSchemaName)
+		c.Set("user_id", uint(1))
+		c.Next()
+	})
+
+	router.POST("/patients", middleware.IdempotencyMiddleware(mockService), func(c *gin.Context) {
+		*calls++
+		c.Header("ETag", `"1"`)
+		c.JSON(status, gin.H{"success": status < 300, "hn": "HOSP0001-00000001"})
+	})
+
+	return router
+}
+
+func postWithKey(router *gin.Engine, key string, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("POST", "/patients", bytes.NewBufferString(body))
+	if key != "" {
+		req.Header.Set(middleware.IdempotencyKeyHeader, key)
+	}
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+func TestIdempotencyMiddleware_StoresFirstResponse(t *testing.T) {
+	mockService := mocks.NewMockIdempotencyService()
+	calls := 0
+	router := setupIdempotentRouter(mockService, http.StatusCreated, &calls)
+
+	mockService.On("Begin", mock.MatchedBy(func(record *domain.IdempotencyKey) bool {
+		return record.Key == "key-1" && record.StaffID == 1 && len(record.RequestHash) == 64
+	}), testSchemaName).Return(nil, nil)
+	mockService.On("Complete", mock.MatchedBy(func(record *domain.IdempotencyKey) bool {
+		return record.StatusCode == http.StatusCreated &&
+			record.ResponseBody == `{"hn":"HOSP0001-00000001","success":true}` &&
+			record.ResponseHeaders == `{"Content-Type":"application/json; charset=utf-8","ETag":"\"1\""}`
+	}), testSchemaName).Return(nil)
+
+	resp := postWithKey(router, "key-1", `{"first_name_en":"Somchai"}`)
+
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	assert.Equal(t, 1, calls)
+	mockService.AssertExpectations(t)
+}
+
+func TestIdempotencyMiddleware_ReplaysStoredResponse(t *testing.T) {
+	mockService := mocks.NewMockIdempotencyService()
+	calls := 0
+	router := setupIdempotentRouter(mockService, http.StatusCreated, &calls)
+
+	stored := &domain.IdempotencyKey{
+		Key:             "key-1",
+		StatusCode:      http.StatusCreated,
+		ResponseBody:    `{"hn":"HOSP0001-00000001","success":true}`,
+		ResponseHeaders: `{"ETag":"\"1\""}`,
+	}
+	mockService.On("Begin", mock.AnythingOfType("*domain.IdempotencyKey"), testSchemaName).Return(stored, nil)
+
+	resp := postWithKey(router, "key-1", `{"first_name_en":"Somchai"}`)
+
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	assert.Equal(t, stored.ResponseBody, resp.Body.String())
+	assert.Equal(t, `"1"`, resp.Header().Get("ETag"))
+	assert.Equal(t, "true", resp.Header().Get(middleware.IdempotentReplayedHeader))
+	assert.Equal(t, 0, calls)
+	mockService.AssertNotCalled(t, "Complete", mock.Anything, mock.Anything)
+}
+
+func TestIdempotencyMiddleware_Errors(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+	}{
+		{"key reused with a different payload", domain.ErrIdempotencyKeyReused, http.StatusUnprocessableEntity},
+		{"first request in progress", domain.ErrIdempotencyKeyInUse, http.StatusConflict},
+		{"invalid key", domain.ErrInvalidInput, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockIdempotencyService()
+			calls := 0
+			router := setupIdempotentRouter(mockService, http.StatusCreated, &calls)
+			mockService.On("Begin", mock.AnythingOfType("*domain.IdempotencyKey"), testSchemaName).Return(nil, tt.err)
+
+			resp := postWithKey(router, "key-1", `{}`)
+
+			assert.Equal(t, tt.expectedStatus, resp.Code)
+			assert.Equal(t, 0, calls)
+		})
+	}
+}
+
+func TestIdempotencyMiddleware_FailedRequestReleasesKey(t *testing.T) {
+	mockService := mocks.NewMockIdempotencyService()
+	calls := 0
+	router := setupIdempotentRouter(mockService, http.StatusBadRequest, &calls)
+
+	mockService.On("Begin", mock.AnythingOfType("*domain.IdempotencyKey"), testSchemaName).Return(nil, nil)
+	mockService.On("Release", mock.MatchedBy(func(record *domain.IdempotencyKey) bool {
+		return record.Key == "key-1"
+	}), testSchemaName).Return(nil)
+
+	resp := postWithKey(router, "key-1", `{}`)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	mockService.AssertExpectations(t)
+	mockService.AssertNotCalled(t, "Complete", mock.Anything, mock.Anything)
+}
+
+func TestIdempotencyMiddleware_PanicReleasesKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := mocks.NewMockIdempotencyService()
+	router := gin.New()
+	router.Use(gin.Recovery(), func(c *gin.Context) {
+		c.Set(middleware.TenantSchemaKey, testSchemaName)
+		c.Set("user_id", uint(1))
+		c.Next()
+	})
+	router.POST("/patients", middleware.IdempotencyMiddleware(mockService), func(c *gin.Context) {
+		panic("handler failed")
+	})
+
+	mockService.On("Begin", mock.AnythingOfType("*domain.IdempotencyKey"), testSchemaName).Return(nil, nil)
+	mockService.On("Release", mock.MatchedBy(func(record *domain.IdempotencyKey) bool {
+		return record.Key == "key-1"
+	}), testSchemaName).Return(nil)
+
+	resp := postWithKey(router, "key-1", `{}`)
+
+	assert.Equal(t, http.StatusInternalServerError, resp.Code)
+	mockService.AssertExpectations(t)
+	mockService.AssertNotCalled(t, "Complete", mock.Anything, mock.Anything)
+}
+
+func TestIdempotencyMiddleware_WithoutKey(t *testing.T) {
+	mockService := mocks.NewMockIdempotencyService()
+	calls := 0
+	router := setupIdempotentRouter(mockService, http.StatusCreated, &calls)
+
+	resp := postWithKey(router, "", `{}`)
+
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	assert.Equal(t, 1, calls)
+	mockService.AssertNotCalled(t, "Begin", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,213 @@
+package handler_test
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/delivery/http/handler"
+	"github.com/wichai2002/his_v1/internal/delivery/http/middleware"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/mocks"
+)
+
+// setupInventoryRouter creates a test router with tenant context and the given permissions
+func setupInventoryRouter(mockService *mocks.MockInventoryService, permissions []string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.TenantSchemaKey, testSchemaName)
+		c.Set("permissions", permissions)
+		c.Next()
+	})
+
+	inventoryHandler := handler.NewInventoryHandler(mockService)
+
+	router.GET("/pharmacy/stock", middleware.RequirePermission(domain.PermInventoryRead), inventoryHandler.ListStock)
+	router.GET("/pharmacy/stock-card", middleware.RequirePermission(domain.PermInventoryRead), inventoryHandler.StockCard)
+	router.GET("/pharmacy/alerts", middleware.RequirePermission(domain.PermInventoryRead), inventoryHandler.Alerts)
+	router.POST("/pharmacy/receipts", middleware.RequirePermission(domain.PermInventoryManage), inventoryHandler.Receive)
+	router.POST("/pharmacy/adjustments", middleware.RequirePermission(domain.PermInventoryManage), inventoryHandler.Adjust)
+
+	return router
+}
+
+func TestInventoryHandler_Receive(t *testing.T) {
+	validBody := `{"location_id":1,"supplier":"Siam Pharma","reference_number":"INV-2025-0113","items":[{"drug_id":5,"lot_number":"N2401","expiry_date":"2030-12-31","quantity":100}]}`
+
+	tests := []struct {
+		name           string
+		body           string
+		permissions    []string
+		setup          func(m *mocks.MockInventoryService)
+		expectedStatus int
+	}{
+		{
+			name:        "received",
+			body:        validBody,
+			permissions: []string{domain.PermInventoryManage},
+			setup: func(m *mocks.MockInventoryService) {
+				m.On("Receive", mock.MatchedBy(func(r *domain.GoodsReceiptRequest) bool {
+					return r.LocationID == 1 && len(r.Items) == 1 && r.Items[0].Quantity == 100
+				}), mock.AnythingOfType("*domain.Actor"), testSchemaName).Return(&domain.GoodsReceipt{LocationID: 1}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "no items",
+			body:           `{"location_id":1,"supplier":"Siam Pharma","reference_number":"INV-2025-0113","items":[]}`,
+			permissions:    []string{domain.PermInventoryManage},
+			setup:          func(m *mocks.MockInventoryService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "zero quantity",
+			body:           `{"location_id":1,"supplier":"Siam Pharma","reference_number":"INV-2025-0113","items":[{"drug_id":5,"lot_number":"N2401","expiry_date":"2030-12-31","quantity":0}]}`,
+			permissions:    []string{domain.PermInventoryManage},
+			setup:          func(m *mocks.MockInventoryService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "expired lot",
+			body:        validBody,
+			permissions: []string{domain.PermInventoryManage},
+			setup: func(m *mocks.MockInventoryService) {
+				m.On("Receive", mock.Anything, mock.Anything, testSchemaName).
+					Return(nil, fmt.Errorf("%w: lot N2401 expired on 2030-12-31", domain.ErrInvalidInput))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "read only",
+			body:           validBody,
+			permissions:    []string{domain.PermInventoryRead},
+			setup:          func(m *mocks.MockInventoryService) {},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockInventoryService()
+			tt.setup(mockService)
+			router := setupInventoryRouter(mockService, tt.permissions)
+
+			req, _ := http.NewRequest("POST", "/pharmacy/receipts", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tt.expectedStatus, resp.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestInventoryHandler_Adjust(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		serviceErr     error
+		expectedStatus int
+	}{
+		{name: "adjusted", body: `{"lot_id":7,"quantity":-2,"reason":"broken bottles"}`, expectedStatus: http.StatusCreated},
+		{name: "reason required", body: `{"lot_id":7,"quantity":-2}`, expectedStatus: http.StatusBadRequest},
+		{
+			name:           "below zero",
+			body:           `{"lot_id":7,"quantity":-20,"reason":"stock count"}`,
+			serviceErr:     fmt.Errorf("%w: lot N2401 has 12 on hand", domain.ErrInvalidInput),
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockInventoryService()
+			if tt.expectedStatus == http.StatusCreated {
+				mockService.On("Adjust", mock.MatchedBy(func(r *domain.StockAdjustmentRequest) bool {
+					return r.LotID == 7 && r.Quantity == -2
+				}), mock.Anything, testSchemaName).Return(&domain.StockMovement{LotID: 7, Quantity: -2}, nil)
+			}
+			if tt.serviceErr != nil {
+				mockService.On("Adjust", mock.Anything, mock.Anything, testSchemaName).Return(nil, tt.serviceErr)
+			}
+			router := setupInventoryRouter(mockService, []string{domain.PermInventoryManage})
+
+			req, _ := http.NewRequest("POST", "/pharmacy/adjustments", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tt.expectedStatus, resp.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestInventoryHandler_StockCard(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		permissions    []string
+		expectedStatus int
+	}{
+		{name: "card", query: "?location_id=1&drug_id=5&from=2025-06-01", permissions: []string{domain.PermInventoryRead}, expectedStatus: http.StatusOK},
+		{name: "drug required", query: "?location_id=1", permissions: []string{domain.PermInventoryRead}, expectedStatus: http.StatusBadRequest},
+		{name: "no inventory permission", query: "?location_id=1&drug_id=5", permissions: []string{domain.PermPatientRead}, expectedStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockInventoryService()
+			if tt.expectedStatus == http.StatusOK {
+				mockService.On("StockCard", mock.MatchedBy(func(r *domain.StockCardRequest) bool {
+					return r.LocationID == 1 && r.DrugID == 5 && r.From == "2025-06-01"
+				}), testSchemaName).Return(domain.NewStockCard(1, 5, 40, nil), nil)
+			}
+			router := setupInventoryRouter(mockService, tt.permissions)
+
+			req, _ := http.NewRequest("GET", "/pharmacy/stock-card"+tt.query, nil)
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tt.expectedStatus, resp.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestInventoryHandler_Alerts(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+	}{
+		{name: "default window", query: "", expectedStatus: http.StatusOK},
+		{name: "custom window", query: "?within_days=30", expectedStatus: http.StatusOK},
+		{name: "window too long", query: "?within_days=1000", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockInventoryService()
+			if tt.expectedStatus == http.StatusOK {
+				mockService.On("Alerts", mock.AnythingOfType("*domain.StockAlertRequest"), testSchemaName).
+					Return(&domain.StockAlerts{LowStock: []domain.LowStockAlert{}, NearExpiry: []domain.NearExpiryAlert{}}, nil)
+			}
+			router := setupInventoryRouter(mockService, []string{domain.PermInventoryRead})
+
+			req, _ := http.NewRequest("GET", "/pharmacy/alerts"+tt.query, nil)
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tt.expectedStatus, resp.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
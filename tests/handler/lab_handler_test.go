@@ -0,0 +1,295 @@
+package handler_test
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/delivery/http/handler"
+	"github.com/wichai2002/his_v1/internal/delivery/http/middleware"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/mocks"
+)
+
+// setupLabRouter creates a test router with tenant context and the given permissions
+func setupLabRouter(mockService *mocks.MockLabService, permissions []string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.TenantSchemaKey, testSchemaName)
+		c.Set("permissions", permissions)
+		c.Next()
+	})
+
+	labHandler := handler.NewLabHandler(mockService)
+
+	router.POST("/lab/tests", middleware.RequirePermission(domain.PermLabManage), labHandler.CreateTest)
+	router.GET("/lab/specimens/:barcode/label", middleware.RequirePermission(domain.PermLabCollect), labHandler.GetLabel)
+	router.POST("/lab/specimens/:barcode/receive", middleware.RequirePermission(domain.PermLabResult), labHandler.Receive)
+	router.POST("/lab/analyzer/import", middleware.RequirePermission(domain.PermLabResult), labHandler.ImportResults)
+	router.POST("/encounters/:id/lab-orders", middleware.RequirePermission(domain.PermLabOrder), labHandler.Create)
+	router.GET("/patient/:id/lab-orders", middleware.RequirePermission(domain.PermPatientRead), labHandler.ListByPatient)
+	router.POST("/lab-orders/:id/results", middleware.RequirePermission(domain.PermLabResult), labHandler.RecordResults)
+
+	return router
+}
+
+func TestLabHandler_CreateTest(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		serviceErr     error
+		expectedStatus int
+	}{
+		{
+			name:           "created",
+			body:           `{"code":"GLU","name":"Glucose, fasting","specimen_type":"plasma","result_type":"numeric","unit":"mg/dL","reference_ranges":[{"age_min_years":0,"low":70,"high":99,"critical_low":40,"critical_high":400}]}`,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "unknown specimen type",
+			body:           `{"code":"GLU","name":"Glucose","specimen_type":"saliva","result_type":"numeric"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "bad range sex",
+			body:           `{"code":"HB","name":"Hemoglobin","specimen_type":"blood","result_type":"numeric","reference_ranges":[{"sex":"X","age_min_years":15,"low":13}]}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "duplicate code",
+			body:           `{"code":"GLU","name":"Glucose","specimen_type":"plasma","result_type":"numeric"}`,
+			serviceErr:     fmt.Errorf("%w: idx_lab_tests_code_live", domain.ErrDuplicateEntry),
+			expectedStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockLabService()
+			if tt.expectedStatus == http.StatusCreated {
+				mockService.On("CreateTest", mock.MatchedBy(func(r *domain.LabTestRequest) bool {
+					return r.Code == "GLU" && len(r.ReferenceRanges) == 1 && *r.ReferenceRanges[0].CriticalHigh == 400
+				}), testSchemaName).Return(&domain.LabTest{Code: "GLU"}, nil)
+			}
+			if tt.serviceErr != nil {
+				mockService.On("CreateTest", mock.Anything, testSchemaName).Return(nil, tt.serviceErr)
+			}
+			router := setupLabRouter(mockService, []string{domain.PermLabManage})
+
+			req, _ := http.NewRequest("POST", "/lab/tests", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tt.expectedStatus, resp.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLabHandler_Create(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		body           string
+		permissions    []string
+		expectedStatus int
+	}{
+		{name: "ordered", path: "/encounters/12/lab-orders", body: `{"test_ids":[3,4],"priority":"stat"}`, permissions: []string{domain.PermLabOrder}, expectedStatus: http.StatusCreated},
+		{name: "no tests", path: "/encounters/12/lab-orders", body: `{"test_ids":[]}`, permissions: []string{domain.PermLabOrder}, expectedStatus: http.StatusBadRequest},
+		{name: "unknown priority", path: "/encounters/12/lab-orders", body: `{"test_ids":[3],"priority":"asap"}`, permissions: []string{domain.PermLabOrder}, expectedStatus: http.StatusBadRequest},
+		{name: "invalid id", path: "/encounters/abc/lab-orders", body: `{"test_ids":[3]}`, permissions: []string{domain.PermLabOrder}, expectedStatus: http.StatusBadRequest},
+		{name: "nurse cannot order", path: "/encounters/12/lab-orders", body: `{"test_ids":[3]}`, permissions: []string{domain.PermPatientRead, domain.PermLabCollect}, expectedStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockLabService()
+			if tt.expectedStatus == http.StatusCreated {
+				mockService.On("Create", uint(12), mock.MatchedBy(func(r *domain.LabOrderRequest) bool {
+					return len(r.TestIDs) == 2 && r.Priority == domain.LabPriorityStat
+				}), mock.AnythingOfType("*domain.Actor"), testSchemaName).Return(&domain.LabOrder{EncounterID: 12}, nil)
+			}
+			router := setupLabRouter(mockService, tt.permissions)
+
+			req, _ := http.NewRequest("POST", tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tt.expectedStatus, resp.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLabHandler_SpecimenActions(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		setup          func(m *mocks.MockLabService)
+		expectedStatus int
+	}{
+		{
+			name:   "label",
+			method: "GET",
+			path:   "/lab/specimens/25060100001/label",
+			setup: func(m *mocks.MockLabService) {
+				m.On("Label", "25060100001", mock.Anything, testSchemaName).Return(&domain.LabSpecimenLabel{Barcode: "25060100001", ZPL: "^XA^XZ"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "unknown barcode",
+			method: "GET",
+			path:   "/lab/specimens/25060199999/label",
+			setup: func(m *mocks.MockLabService) {
+				m.On("Label", "25060199999", mock.Anything, testSchemaName).Return(nil, domain.ErrNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:   "receive before collection",
+			method: "POST",
+			path:   "/lab/specimens/25060100001/receive",
+			setup: func(m *mocks.MockLabService) {
+				m.On("Receive", "25060100001", mock.Anything, testSchemaName).
+					Return(nil, fmt.Errorf("%w: specimen 25060100001 is pending and cannot move to received", domain.ErrInvalidStatusTransition))
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:   "changed concurrently",
+			method: "POST",
+			path:   "/lab/specimens/25060100001/receive",
+			setup: func(m *mocks.MockLabService) {
+				m.On("Receive", "25060100001", mock.Anything, testSchemaName).Return(nil, domain.ErrPreconditionFailed)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockLabService()
+			tt.setup(mockService)
+			router := setupLabRouter(mockService, []string{domain.PermLabCollect, domain.PermLabResult})
+
+			req, _ := http.NewRequest(tt.method, tt.path, nil)
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tt.expectedStatus, resp.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLabHandler_RecordResults(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		serviceErr     error
+		expectedStatus int
+	}{
+		{name: "recorded", body: `{"results":[{"item_id":101,"value":"11.2","unit":"g/dL"}]}`, expectedStatus: http.StatusOK},
+		{name: "empty value", body: `{"results":[{"item_id":101,"value":""}]}`, expectedStatus: http.StatusBadRequest},
+		{
+			name:           "not a number",
+			body:           `{"results":[{"item_id":101,"value":"clotted"}]}`,
+			serviceErr:     fmt.Errorf("%w: result of HB must be a number", domain.ErrInvalidInput),
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockLabService()
+			if tt.expectedStatus == http.StatusOK {
+				mockService.On("RecordResults", uint(30), mock.MatchedBy(func(r *domain.LabResultRequest) bool {
+					return len(r.Results) == 1 && r.Results[0].ItemID == 101 && r.Results[0].Unit == "g/dL"
+				}), mock.Anything, testSchemaName).Return(&domain.LabOrder{Status: domain.LabOrderStatusReceived}, nil)
+			}
+			if tt.serviceErr != nil {
+				mockService.On("RecordResults", uint(30), mock.Anything, mock.Anything, testSchemaName).Return(nil, tt.serviceErr)
+			}
+			router := setupLabRouter(mockService, []string{domain.PermLabResult})
+
+			req, _ := http.NewRequest("POST", "/lab-orders/30/results", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tt.expectedStatus, resp.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLabHandler_ListByPatient(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+	}{
+		{name: "all orders", query: "", expectedStatus: http.StatusOK},
+		{name: "completed only", query: "?status=completed", expectedStatus: http.StatusOK},
+		{name: "unknown status", query: "?status=done", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockLabService()
+			if tt.expectedStatus == http.StatusOK {
+				mockService.On("ListByPatient", uint(1), mock.AnythingOfType("*domain.LabOrderListRequest"), mock.Anything, testSchemaName).
+					Return([]domain.LabOrder{}, nil)
+			}
+			router := setupLabRouter(mockService, []string{domain.PermPatientRead})
+
+			req, _ := http.NewRequest("GET", "/patient/1/lab-orders"+tt.query, nil)
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tt.expectedStatus, resp.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLabHandler_ImportResults(t *testing.T) {
+	tests := []struct {
+		name           string
+		serviceErr     error
+		expectedStatus int
+	}{
+		{name: "imported", expectedStatus: http.StatusOK},
+		{name: "analyzer unavailable", serviceErr: fmt.Errorf("%w: permission denied", domain.ErrAnalyzerUnavailable), expectedStatus: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockLabService()
+			if tt.serviceErr != nil {
+				mockService.On("ImportFromAnalyzer", mock.Anything, testSchemaName).Return(nil, tt.serviceErr)
+			} else {
+				mockService.On("ImportFromAnalyzer", mock.Anything, testSchemaName).Return(&domain.LabImportSummary{Batches: []domain.LabImportBatchReport{}}, nil)
+			}
+			router := setupLabRouter(mockService, []string{domain.PermLabResult})
+
+			req, _ := http.NewRequest("POST", "/lab/analyzer/import", nil)
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tt.expectedStatus, resp.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
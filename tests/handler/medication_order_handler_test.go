@@ -0,0 +1,282 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/delivery/http/handler"
+	"github.com/wichai2002/his_v1/internal/delivery/http/middleware"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/mocks"
+	"github.com/wichai2002/his_v1/pkg/utils"
+)
+
+// setupMedicationOrderRouter creates a test router with tenant context and the given permissions
+func setupMedicationOrderRouter(mockService *mocks.MockMedicationOrderService, permissions []string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.TenantSchemaKey, testSchemaName)
+		c.Set("permissions", permissions)
+		c.Next()
+	})
+
+	orderHandler := handler.NewMedicationOrderHandler(mockService)
+
+	router.POST("/encounters/:id/medications", middleware.RequirePermission(domain.PermMedicationOrder), orderHandler.Create)
+	router.GET("/patient/:id/medications", middleware.RequirePermission(domain.PermPatientRead), orderHandler.ListByPatient)
+	router.POST("/medication-orders/:id/verify", middleware.RequirePermission(domain.PermMedicationVerify), orderHandler.Verify)
+	router.POST("/medication-orders/:id/dispense", middleware.RequirePermission(domain.PermMedicationVerify), orderHandler.Dispense)
+	router.POST("/medication-orders/:id/cancel", middleware.RequireAnyPermission(domain.PermMedicationOrder, domain.PermMedicationVerify), orderHandler.Cancel)
+
+	return router
+}
+
+func TestMedicationOrderHandler_Create(t *testing.T) {
+	validBody := `{"drug_id":4,"dose":1,"dose_unit":"tab","route":"oral","frequency":"tid","duration_days":5}`
+
+	tests := []struct {
+		name           string
+		body           string
+		permissions    []string
+		setup          func(m *mocks.MockMedicationOrderService)
+		expectedStatus int
+	}{
+		{
+			name:        "created",
+			body:        validBody,
+			permissions: []string{domain.PermMedicationOrder},
+			setup: func(m *mocks.MockMedicationOrderService) {
+				m.On("Create", uint(12), mock.MatchedBy(func(r *domain.MedicationOrderRequest) bool {
+					return r.DrugID == 4 && r.Frequency == "tid" && r.Quantity == nil
+				}), mock.AnythingOfType("*domain.Actor"), testSchemaName).Return(&domain.MedicationOrder{DrugID: 4}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "unknown frequency",
+			body:           `{"drug_id":4,"dose":1,"dose_unit":"tab","route":"oral","frequency":"daily","duration_days":5}`,
+			permissions:    []string{domain.PermMedicationOrder},
+			setup:          func(m *mocks.MockMedicationOrderService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "inactive drug",
+			body:        validBody,
+			permissions: []string{domain.PermMedicationOrder},
+			setup: func(m *mocks.MockMedicationOrderService) {
+				m.On("Create", uint(12), mock.Anything, mock.Anything, testSchemaName).
+					Return(nil, fmt.Errorf("%w: drug IBU400 is not active", domain.ErrInvalidInput))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "pharmacist cannot prescribe",
+			body:           validBody,
+			permissions:    []string{domain.PermPatientRead, domain.PermMedicationVerify},
+			setup:          func(m *mocks.MockMedicationOrderService) {},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockMedicationOrderService()
+			tt.setup(mockService)
+			router := setupMedicationOrderRouter(mockService, tt.permissions)
+
+			req, _ := http.NewRequest("POST", "/encounters/12/medications", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tt.expectedStatus, resp.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMedicationOrderHandler_Create_Alerts(t *testing.T) {
+	mockService := mocks.NewMockMedicationOrderService()
+	mockService.On("Create", uint(12), mock.Anything, mock.Anything, testSchemaName).Return(nil, &domain.MedicationAlertError{
+		Alerts: domain.MedicationAlerts{{Type: domain.MedicationAlertAllergy, Severity: domain.AllergySeveritySevere, Message: "recorded allergy to NSAIDs"}},
+	})
+	router := setupMedicationOrderRouter(mockService, []string{domain.PermMedicationOrder})
+
+	body := `{"drug_id":4,"dose":1,"dose_unit":"tab","route":"oral","frequency":"tid","duration_days":5}`
+	req, _ := http.NewRequest("POST", "/encounters/12/medications", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusConflict, resp.Code)
+
+	var response utils.Response
+	err := json.Unmarshal(resp.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.False(t, response.Success)
+	alerts, ok := response.Data.([]interface{})
+	assert.True(t, ok)
+	assert.Len(t, alerts, 1)
+	assert.Equal(t, domain.MedicationAlertAllergy, alerts[0].(map[string]interface{})["type"])
+}
+
+func TestMedicationOrderHandler_Verify(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		setup          func(m *mocks.MockMedicationOrderService)
+		expectedStatus int
+	}{
+		{
+			name: "verified",
+			path: "/medication-orders/20/verify",
+			setup: func(m *mocks.MockMedicationOrderService) {
+				m.On("Verify", uint(20), mock.Anything, testSchemaName).Return(&domain.MedicationOrder{Status: domain.MedicationStatusVerified}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid id",
+			path:           "/medication-orders/abc/verify",
+			setup:          func(m *mocks.MockMedicationOrderService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "already cancelled",
+			path: "/medication-orders/20/verify",
+			setup: func(m *mocks.MockMedicationOrderService) {
+				m.On("Verify", uint(20), mock.Anything, testSchemaName).
+					Return(nil, fmt.Errorf("%w: medication order 20 is cancelled and cannot move to verified", domain.ErrInvalidStatusTransition))
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: "changed by another request",
+			path: "/medication-orders/20/verify",
+			setup: func(m *mocks.MockMedicationOrderService) {
+				m.On("Verify", uint(20), mock.Anything, testSchemaName).Return(nil, domain.ErrPreconditionFailed)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockMedicationOrderService()
+			tt.setup(mockService)
+			router := setupMedicationOrderRouter(mockService, []string{domain.PermMedicationVerify})
+
+			req, _ := http.NewRequest("POST", tt.path, nil)
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tt.expectedStatus, resp.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMedicationOrderHandler_Dispense(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		permissions    []string
+		serviceErr     error
+		expectedStatus int
+	}{
+		{name: "dispensed", body: `{"location_id":1}`, permissions: []string{domain.PermMedicationVerify}, expectedStatus: http.StatusOK},
+		{name: "location required", body: `{}`, permissions: []string{domain.PermMedicationVerify}, expectedStatus: http.StatusBadRequest},
+		{
+			name:           "insufficient stock",
+			body:           `{"location_id":1}`,
+			permissions:    []string{domain.PermMedicationVerify},
+			serviceErr:     fmt.Errorf("%w: 4 available, 15 needed", domain.ErrInsufficientStock),
+			expectedStatus: http.StatusConflict,
+		},
+		{name: "prescriber cannot dispense", body: `{"location_id":1}`, permissions: []string{domain.PermMedicationOrder}, expectedStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockMedicationOrderService()
+			if tt.expectedStatus == http.StatusOK {
+				mockService.On("Dispense", uint(20), mock.MatchedBy(func(r *domain.MedicationDispenseRequest) bool {
+					return r.LocationID == 1
+				}), mock.Anything, testSchemaName).Return(&domain.MedicationDispensing{
+					Order:     &domain.MedicationOrder{Status: domain.MedicationStatusDispensed},
+					Movements: []domain.StockMovement{{LotNumber: "N2401", Quantity: -15}},
+				}, nil)
+			}
+			if tt.serviceErr != nil {
+				mockService.On("Dispense", uint(20), mock.Anything, mock.Anything, testSchemaName).Return(nil, tt.serviceErr)
+			}
+			router := setupMedicationOrderRouter(mockService, tt.permissions)
+
+			req, _ := http.NewRequest("POST", "/medication-orders/20/dispense", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tt.expectedStatus, resp.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMedicationOrderHandler_Cancel(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		permissions    []string
+		expectedStatus int
+	}{
+		{name: "prescriber", body: `{"reason":"wrong drug"}`, permissions: []string{domain.PermMedicationOrder}, expectedStatus: http.StatusOK},
+		{name: "pharmacist", body: `{"reason":"wrong drug"}`, permissions: []string{domain.PermMedicationVerify}, expectedStatus: http.StatusOK},
+		{name: "reason required", body: `{}`, permissions: []string{domain.PermMedicationOrder}, expectedStatus: http.StatusBadRequest},
+		{name: "nurse cannot cancel", body: `{"reason":"wrong drug"}`, permissions: []string{domain.PermPatientRead}, expectedStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockMedicationOrderService()
+			if tt.expectedStatus == http.StatusOK {
+				mockService.On("Cancel", uint(20), mock.MatchedBy(func(r *domain.MedicationCancelRequest) bool {
+					return r.Reason == "wrong drug"
+				}), mock.Anything, testSchemaName).Return(&domain.MedicationOrder{Status: domain.MedicationStatusCancelled}, nil)
+			}
+			router := setupMedicationOrderRouter(mockService, tt.permissions)
+
+			req, _ := http.NewRequest("POST", "/medication-orders/20/cancel", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tt.expectedStatus, resp.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMedicationOrderHandler_ListByPatient_ActiveOnly(t *testing.T) {
+	mockService := mocks.NewMockMedicationOrderService()
+	mockService.On("ListByPatient", uint(1), mock.MatchedBy(func(r *domain.MedicationOrderListRequest) bool {
+		return r.ActiveOnly
+	}), mock.Anything, testSchemaName).Return([]domain.MedicationOrder{{DrugID: 4}}, nil)
+	router := setupMedicationOrderRouter(mockService, []string{domain.PermPatientRead})
+
+	req, _ := http.NewRequest("GET", "/patient/1/medications?active_only=true", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	mockService.AssertExpectations(t)
+}
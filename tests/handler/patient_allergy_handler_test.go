@@ -0,0 +1,115 @@
+package handler_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/delivery/http/handler"
+	"github.com/wichai2002/his_v1/internal/delivery/http/middleware"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/mocks"
+)
+
+// setupPatientAllergyRouter creates a test router with tenant context and the given permissions
+func setupPatientAllergyRouter(mockService *mocks.MockPatientAllergyService, permissions []string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.TenantSchemaKey, testSchemaName)
+		c.Set("permissions", permissions)
+		c.Next()
+	})
+
+	allergyHandler := handler.NewPatientAllergyHandler(mockService)
+
+	allergies := router.Group("/patient/:id/allergies")
+	{
+		allergies.GET("", middleware.RequirePermission(domain.PermPatientRead), allergyHandler.List)
+		allergies.POST("", middleware.RequirePermission(domain.PermAllergyWrite), allergyHandler.Create)
+		allergies.PUT("/:allergy_id", middleware.RequirePermission(domain.PermAllergyWrite), allergyHandler.Update)
+		allergies.DELETE("/:allergy_id", middleware.RequirePermission(domain.PermAllergyWrite), allergyHandler.Delete)
+	}
+
+	return router
+}
+
+func TestPatientAllergyHandler_Create(t *testing.T) {
+	validBody := `{"allergen_type":"drug","allergen":"Penicillin","reaction":"Anaphylaxis","severity":"life_threatening","certainty":"confirmed"}`
+
+	tests := []struct {
+		name           string
+		body           string
+		permissions    []string
+		setup          func(m *mocks.MockPatientAllergyService)
+		expectedStatus int
+	}{
+		{
+			name:        "recorded",
+			body:        validBody,
+			permissions: []string{domain.PermAllergyWrite},
+			setup: func(m *mocks.MockPatientAllergyService) {
+				m.On("Create", uint(1), mock.AnythingOfType("*domain.PatientAllergyRequest"), mock.AnythingOfType("*domain.Actor"), testSchemaName).
+					Return(&domain.PatientAllergy{PatientID: 1, Allergen: "Penicillin"}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "unknown severity",
+			body:           `{"allergen_type":"drug","allergen":"Penicillin","severity":"fatal","certainty":"confirmed"}`,
+			permissions:    []string{domain.PermAllergyWrite},
+			setup:          func(m *mocks.MockPatientAllergyService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "allergen already recorded",
+			body:        validBody,
+			permissions: []string{domain.PermAllergyWrite},
+			setup: func(m *mocks.MockPatientAllergyService) {
+				m.On("Create", uint(1), mock.Anything, mock.Anything, testSchemaName).Return(nil, domain.ErrDuplicateEntry)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "registration clerk cannot record allergies",
+			body:           validBody,
+			permissions:    []string{domain.PermPatientRead, domain.PermPatientWrite},
+			setup:          func(m *mocks.MockPatientAllergyService) {},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockPatientAllergyService()
+			tt.setup(mockService)
+			router := setupPatientAllergyRouter(mockService, tt.permissions)
+
+			req, _ := http.NewRequest("POST", "/patient/1/allergies", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tt.expectedStatus, resp.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPatientAllergyHandler_Delete_NotFound(t *testing.T) {
+	mockService := mocks.NewMockPatientAllergyService()
+	mockService.On("Delete", uint(1), uint(9), mock.AnythingOfType("*domain.Actor"), testSchemaName).Return(domain.ErrNotFound)
+	router := setupPatientAllergyRouter(mockService, []string{domain.PermAllergyWrite})
+
+	req, _ := http.NewRequest("DELETE", "/patient/1/allergies/9", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+	assert.Contains(t, resp.Body.String(), "allergy not found")
+}
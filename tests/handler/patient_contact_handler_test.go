@@ -0,0 +1,175 @@
+package handler_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/delivery/http/handler"
+	"github.com/wichai2002/his_v1/internal/delivery/http/middleware"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/mocks"
+)
+
+// setupPatientContactRouter creates a test router with tenant context and the given permissions
+func setupPatientContactRouter(mockService *mocks.MockPatientContactService, permissions []string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.TenantSchemaKey, testSchemaName)
+		c.Set("permissions", permissions)
+		c.Next()
+	})
+
+	contactHandler := handler.NewPatientContactHandler(mockService)
+
+	contacts := router.Group("/patient/:id/contacts")
+	{
+		contacts.GET("", middleware.RequirePermission(domain.PermPatientRead), contactHandler.List)
+		contacts.POST("", middleware.RequirePermission(domain.PermPatientWrite), contactHandler.Create)
+		contacts.PUT("/:contact_id", middleware.RequirePermission(domain.PermPatientWrite), contactHandler.Update)
+		contacts.DELETE("/:contact_id", middleware.RequirePermission(domain.PermPatientWrite), contactHandler.Delete)
+	}
+
+	return router
+}
+
+func TestPatientContactHandler_Create(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		body           string
+		permissions    []string
+		setup          func(m *mocks.MockPatientContactService)
+		expectedStatus int
+	}{
+		{
+			name:        "created",
+			path:        "/patient/1/contacts",
+			body:        `{"name":"Somsri Jaidee","relationship":"spouse","phone_number":"0812345678"}`,
+			permissions: []string{domain.PermPatientWrite},
+			setup: func(m *mocks.MockPatientContactService) {
+				m.On("Create", uint(1), mock.AnythingOfType("*domain.PatientContactRequest"), mock.AnythingOfType("*domain.Actor"), testSchemaName).
+					Return(&domain.PatientContact{PatientID: 1, Name: "Somsri Jaidee", IsPrimary: true}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "unknown relationship",
+			path:           "/patient/1/contacts",
+			body:           `{"name":"Somsri Jaidee","relationship":"neighbour","phone_number":"0812345678"}`,
+			permissions:    []string{domain.PermPatientWrite},
+			setup:          func(m *mocks.MockPatientContactService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid patient id",
+			path:           "/patient/abc/contacts",
+			body:           `{"name":"Somsri Jaidee","relationship":"spouse","phone_number":"0812345678"}`,
+			permissions:    []string{domain.PermPatientWrite},
+			setup:          func(m *mocks.MockPatientContactService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "patient not found",
+			path:        "/patient/99/contacts",
+			body:        `{"name":"Somsri Jaidee","relationship":"spouse","phone_number":"0812345678"}`,
+			permissions: []string{domain.PermPatientWrite},
+			setup: func(m *mocks.MockPatientContactService) {
+				m.On("Create", uint(99), mock.Anything, mock.Anything, testSchemaName).Return(nil, domain.ErrNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "read permission only",
+			path:           "/patient/1/contacts",
+			body:           `{"name":"Somsri Jaidee","relationship":"spouse","phone_number":"0812345678"}`,
+			permissions:    []string{domain.PermPatientRead},
+			setup:          func(m *mocks.MockPatientContactService) {},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockPatientContactService()
+			tt.setup(mockService)
+			router := setupPatientContactRouter(mockService, tt.permissions)
+
+			req, _ := http.NewRequest("POST", tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tt.expectedStatus, resp.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPatientContactHandler_List(t *testing.T) {
+	mockService := mocks.NewMockPatientContactService()
+	mockService.On("List", uint(1), mock.AnythingOfType("*domain.Actor"), testSchemaName).
+		Return([]domain.PatientContact{{PatientID: 1, Name: "Somsri Jaidee", IsPrimary: true}}, nil)
+	router := setupPatientContactRouter(mockService, []string{domain.PermPatientRead})
+
+	req, _ := http.NewRequest("GET", "/patient/1/contacts", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), "Somsri Jaidee")
+	mockService.AssertExpectations(t)
+}
+
+func TestPatientContactHandler_Delete(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		setup          func(m *mocks.MockPatientContactService)
+		expectedStatus int
+	}{
+		{
+			name: "deleted",
+			path: "/patient/1/contacts/5",
+			setup: func(m *mocks.MockPatientContactService) {
+				m.On("Delete", uint(1), uint(5), mock.AnythingOfType("*domain.Actor"), testSchemaName).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "contact of another patient",
+			path: "/patient/2/contacts/5",
+			setup: func(m *mocks.MockPatientContactService) {
+				m.On("Delete", uint(2), uint(5), mock.Anything, testSchemaName).Return(domain.ErrNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "invalid contact id",
+			path:           "/patient/1/contacts/abc",
+			setup:          func(m *mocks.MockPatientContactService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockPatientContactService()
+			tt.setup(mockService)
+			router := setupPatientContactRouter(mockService, []string{domain.PermPatientWrite})
+
+			req, _ := http.NewRequest("DELETE", tt.path, nil)
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tt.expectedStatus, resp.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package handler_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/delivery/http/handler"
+	"github.com/wichai2002/his_v1/internal/delivery/http/middleware"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/mocks"
+)
+
+// setupPatientCoverageRouter creates a test router with tenant context and the given permissions
+func setupPatientCoverageRouter(mockService *mocks.MockPatientCoverageService, permissions []string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.TenantSchemaKey, testSchemaName)
+		c.Set("permissions", permissions)
+		c.Next()
+	})
+
+	coverageHandler := handler.NewPatientCoverageHandler(mockService)
+
+	coverages := router.Group("/patient/:id/coverages")
+	{
+		coverages.GET("", middleware.RequirePermission(domain.PermPatientRead), coverageHandler.List)
+		coverages.GET("/active", middleware.RequirePermission(domain.PermPatientRead), coverageHandler.Active)
+		coverages.POST("", middleware.RequirePermission(domain.PermCoverageWrite), coverageHandler.Create)
+		coverages.PUT("/:coverage_id", middleware.RequirePermission(domain.PermCoverageWrite), coverageHandler.Update)
+		coverages.DELETE("/:coverage_id", middleware.RequirePermission(domain.PermCoverageWrite), coverageHandler.Delete)
+		coverages.POST("/:coverage_id/eligibility", middleware.RequirePermission(domain.PermCoverageWrite), coverageHandler.CheckEligibility)
+	}
+
+	return router
+}
+
+func TestPatientCoverageHandler_Create(t *testing.T) {
+	validBody := `{"scheme":"uc","main_hospital_code":"10669","main_hospital_name":"Test Hospital","valid_from":"2026-01-01"}`
+
+	tests := []struct {
+		name           string
+		body           string
+		permissions    []string
+		setup          func(m *mocks.MockPatientCoverageService)
+		expectedStatus int
+	}{
+		{
+			name:        "created",
+			body:        validBody,
+			permissions: []string{domain.PermCoverageWrite},
+			setup: func(m *mocks.MockPatientCoverageService) {
+				m.On("Create", uint(1), mock.AnythingOfType("*domain.PatientCoverageRequest"), mock.AnythingOfType("*domain.Actor"), testSchemaName).
+					Return(&domain.PatientCoverage{PatientID: 1, Scheme: domain.CoverageSchemeUC}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "unknown scheme",
+			body:           `{"scheme":"gold_card","valid_from":"2026-01-01"}`,
+			permissions:    []string{domain.PermCoverageWrite},
+			setup:          func(m *mocks.MockPatientCoverageService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "overlapping coverage",
+			body:        validBody,
+			permissions: []string{domain.PermCoverageWrite},
+			setup: func(m *mocks.MockPatientCoverageService) {
+				m.On("Create", uint(1), mock.Anything, mock.Anything, testSchemaName).Return(nil, domain.ErrDuplicateEntry)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "nurse cannot record coverage",
+			body:           validBody,
+			permissions:    []string{domain.PermPatientRead, domain.PermPatientWrite, domain.PermAllergyWrite},
+			setup:          func(m *mocks.MockPatientCoverageService) {},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockPatientCoverageService()
+			tt.setup(mockService)
+			router := setupPatientCoverageRouter(mockService, tt.permissions)
+
+			req, _ := http.NewRequest("POST", "/patient/1/coverages", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tt.expectedStatus, resp.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPatientCoverageHandler_Active_PassesDate(t *testing.T) {
+	mockService := mocks.NewMockPatientCoverageService()
+	mockService.On("Active", uint(1), "2026-03-15", mock.AnythingOfType("*domain.Actor"), testSchemaName).
+		Return([]domain.PatientCoverage{{PatientID: 1, Scheme: domain.CoverageSchemeSSS}}, nil)
+	router := setupPatientCoverageRouter(mockService, []string{domain.PermPatientRead})
+
+	req, _ := http.NewRequest("GET", "/patient/1/coverages/active?date=2026-03-15", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"scheme":"sss"`)
+	mockService.AssertExpectations(t)
+}
+
+func TestPatientCoverageHandler_CheckEligibility(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+	}{
+		{name: "checked", expectedStatus: http.StatusOK},
+		{name: "coverage not found", err: domain.ErrNotFound, expectedStatus: http.StatusNotFound},
+		{name: "payer service down", err: domain.ErrEligibilityUnavailable, expectedStatus: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockPatientCoverageService()
+			var result *domain.EligibilityResult
+			if tt.err == nil {
+				result = &domain.EligibilityResult{Eligible: true, Scheme: domain.CoverageSchemeUC, Source: "fake"}
+			}
+			mockService.On("CheckEligibility", uint(1), uint(3), "", mock.AnythingOfType("*domain.Actor"), testSchemaName).Return(result, tt.err)
+			router := setupPatientCoverageRouter(mockService, []string{domain.PermCoverageWrite})
+
+			req, _ := http.NewRequest("POST", "/patient/1/coverages/3/eligibility", nil)
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tt.expectedStatus, resp.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -3,8 +3,10 @@ package handler_test
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -16,6 +18,7 @@ import (
 	"github.com/wichai2002/his_v1/internal/domain"
 	"github.com/wichai2002/his_v1/internal/mocks"
 	"github.com/wichai2002/his_v1/pkg/utils"
+	"github.com/wichai2002/his_v1/pkg/validation"
 )
 
 const testSchemaName = "tenant_test"
@@ -23,6 +26,9 @@ const testSchemaName = "tenant_test"
 // setupPatientRouter creates a test router with tenant middleware
 func setupPatientRouter(mockService *mocks.MockPatientService) *gin.Engine {
 	gin.SetMode(gin.TestMode)
+	if err := validation.RegisterGinValidators(); err != nil {
+		panic(err)
+	}
 	router := gin.New()
 
 	// Add tenant schema to context for testing
@@ -41,11 +47,26 @@ func setupPatientRouter(mockService *mocks.MockPatientService) *gin.Engine {
 		patients.PUT("/:id", patientHandler.Update)
 		patients.PATCH("/:id", patientHandler.PartialUpdate)
 		patients.DELETE("/:id", patientHandler.Delete)
+		patients.GET("/merges", patientHandler.ListMerges)
+		patients.POST("/merge", patientHandler.Merge)
+		patients.POST("/unmerge/:id", patientHandler.Unmerge)
+		patients.GET("/:id", patientHandler.GetByID)
+		patients.GET("/:id/history", patientHandler.History)
+		patients.GET("/:id/history/diff", patientHandler.DiffVersions)
 	}
 
 	return router
 }
 
+// searchItems wraps patients as search results without a score
+func searchItems(patients ...domain.Patient) []domain.PatientSearchItem {
+	items := make([]domain.PatientSearchItem, len(patients))
+	for i := range patients {
+		items[i].Patient = patients[i]
+	}
+	return items
+}
+
 // ==================== SEARCH TESTS ====================
 
 func TestPatientHandler_Search_Success(t *testing.T) {
@@ -71,7 +92,8 @@ func TestPatientHandler_Search_Success(t *testing.T) {
 		},
 	}
 
-	mockService.On("Search", "John", testSchemaName).Return(expectedPatients, nil)
+	mockService.On("Search", &domain.PatientSearchRequest{Query: "John"}, mock.AnythingOfType("*domain.Actor"), testSchemaName).
+		Return(&domain.PatientSearchResult{Patients: searchItems(expectedPatients...), Total: 2, Limit: domain.DefaultPatientSearchLimit}, nil)
 
 	req, _ := http.NewRequest("GET", "/patients/search?query=John", nil)
 	resp := httptest.NewRecorder()
@@ -97,7 +119,8 @@ func TestPatientHandler_Search_EmptyQuery(t *testing.T) {
 		{FirstNameEN: "John", LastNameEN: "Doe", PatientHN: "HOSP0001-00000001"},
 	}
 
-	mockService.On("Search", "", testSchemaName).Return(expectedPatients, nil)
+	mockService.On("Search", &domain.PatientSearchRequest{}, mock.AnythingOfType("*domain.Actor"), testSchemaName).
+		Return(&domain.PatientSearchResult{Patients: searchItems(expectedPatients...), Total: 1, Limit: domain.DefaultPatientSearchLimit}, nil)
 
 	req, _ := http.NewRequest("GET", "/patients/search", nil)
 	resp := httptest.NewRecorder()
@@ -113,7 +136,7 @@ func TestPatientHandler_Search_NotFound(t *testing.T) {
 	router := setupPatientRouter(mockService)
 
 	// Use domain.ErrNotFound for proper error handling
-	mockService.On("Search", "NonExistent", testSchemaName).Return(nil, domain.ErrNotFound)
+	mockService.On("Search", &domain.PatientSearchRequest{Query: "NonExistent"}, mock.AnythingOfType("*domain.Actor"), testSchemaName).Return(nil, domain.ErrNotFound)
 
 	req, _ := http.NewRequest("GET", "/patients/search?query=NonExistent", nil)
 	resp := httptest.NewRecorder()
@@ -131,6 +154,135 @@ func TestPatientHandler_Search_NotFound(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestPatientHandler_Search_Paginated(t *testing.T) {
+	mockService := mocks.NewMockPatientService()
+	router := setupPatientRouter(mockService)
+
+	expectedRequest := &domain.PatientSearchRequest{
+		Query:    "som",
+		Limit:    2,
+		Sort:     "-date_of_birth",
+		Gender:   "M",
+		BloodGrp: "O",
+		DOBFrom:  "1980-01-01",
+	}
+	mockService.On("Search", expectedRequest, mock.AnythingOfType("*domain.Actor"), testSchemaName).
+		Return(&domain.PatientSearchResult{
+			Patients:   searchItems(domain.Patient{FirstNameEN: "Somchai"}, domain.Patient{FirstNameEN: "Somsak"}),
+			Total:      5,
+			Limit:      2,
+			NextCursor: "abc",
+		}, nil)
+
+	req, _ := http.NewRequest("GET", "http://bangkok.his.com/patients/search?query=som&limit=2&sort=-date_of_birth&gender=M&blood_grp=O&dob_from=1980-01-01", nil)
+	req.Header.Set("X-Forwarded-Proto", "https")
+	resp := httptest.NewRecorder()
+
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	var response utils.Response
+	err := json.Unmarshal(resp.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), response.Meta.Total)
+	assert.Equal(t, 2, response.Meta.Limit)
+	assert.Equal(t, "abc", response.Meta.NextCursor)
+	// The next link is absolute so clients can follow it as-is
+	assert.True(t, strings.HasPrefix(response.Meta.Next, "https://bangkok.his.com/patients/search?"), response.Meta.Next)
+	assert.Contains(t, response.Meta.Next, "cursor=abc")
+	assert.Contains(t, response.Meta.Next, "gender=M")
+
+	mockService.AssertExpectations(t)
+}
+
+func TestPatientHandler_Search_LastPageHasNoNextLink(t *testing.T) {
+	mockService := mocks.NewMockPatientService()
+	router := setupPatientRouter(mockService)
+
+	mockService.On("Search", mock.AnythingOfType("*domain.PatientSearchRequest"), mock.AnythingOfType("*domain.Actor"), testSchemaName).
+		Return(&domain.PatientSearchResult{Patients: []domain.PatientSearchItem{}, Total: 0, Limit: 20}, nil)
+
+	req, _ := http.NewRequest("GET", "/patients/search?query=zzz", nil)
+	resp := httptest.NewRecorder()
+
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	var response utils.Response
+	err := json.Unmarshal(resp.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Empty(t, response.Meta.Next)
+	assert.Equal(t, []interface{}{}, response.Data)
+}
+
+func TestPatientHandler_Search_InvalidParams(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+	}{
+		{name: "limit too large", url: "/patients/search?limit=1000"},
+		{name: "limit not a number", url: "/patients/search?limit=abc"},
+		{name: "invalid gender", url: "/patients/search?gender=X"},
+		{name: "invalid blood group", url: "/patients/search?blood_grp=C"},
+		{name: "invalid mode", url: "/patients/search?mode=regex"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockPatientService()
+			router := setupPatientRouter(mockService)
+
+			req, _ := http.NewRequest("GET", tt.url, nil)
+			resp := httptest.NewRecorder()
+
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, http.StatusBadRequest, resp.Code)
+			mockService.AssertNotCalled(t, "Search", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestPatientHandler_Search_InvalidSort(t *testing.T) {
+	mockService := mocks.NewMockPatientService()
+	router := setupPatientRouter(mockService)
+
+	mockService.On("Search", mock.AnythingOfType("*domain.PatientSearchRequest"), mock.AnythingOfType("*domain.Actor"), testSchemaName).
+		Return(nil, fmt.Errorf("%w: unsupported sort \"email\"", domain.ErrInvalidInput))
+
+	req, _ := http.NewRequest("GET", "/patients/search?sort=email", nil)
+	resp := httptest.NewRecorder()
+
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+func TestPatientHandler_Search_FuzzyReturnsScore(t *testing.T) {
+	mockService := mocks.NewMockPatientService()
+	router := setupPatientRouter(mockService)
+
+	score := 0.58
+	mockService.On("Search", &domain.PatientSearchRequest{Query: "Somchay", Mode: domain.PatientSearchFuzzy}, mock.AnythingOfType("*domain.Actor"), testSchemaName).
+		Return(&domain.PatientSearchResult{
+			Patients: []domain.PatientSearchItem{{Patient: domain.Patient{FirstNameEN: "Somchai"}, Score: &score}},
+			Total:    1,
+			Limit:    domain.DefaultPatientSearchLimit,
+		}, nil)
+
+	req, _ := http.NewRequest("GET", "/patients/search?query=Somchay&mode=fuzzy", nil)
+	resp := httptest.NewRecorder()
+
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"score":0.58`)
+	assert.Contains(t, resp.Body.String(), `"first_name_en":"Somchai"`)
+	mockService.AssertExpectations(t)
+}
+
 // ==================== CREATE TESTS ====================
 
 func TestPatientHandler_Create_Success(t *testing.T) {
@@ -145,7 +297,7 @@ func TestPatientHandler_Create_Success(t *testing.T) {
 		DateOfBirth: "1990-01-15",
 		NickNameTH:  "ชาย",
 		NickNameEN:  "Chai",
-		NationalID:  "1234567890123",
+		NationalID:  "1234567890121",
 		PassportID:  "AB1234567",
 		PhoneNumber: "0812345678",
 		Email:       "somchai@example.com",
@@ -160,7 +312,7 @@ func TestPatientHandler_Create_Success(t *testing.T) {
 		FirstNameEN: "Somchai",
 		LastNameEN:  "Jaidee",
 		DateOfBirth: time.Date(1990, 1, 15, 0, 0, 0, 0, time.UTC),
-		NationalID:  "1234567890123",
+		NationalID:  "1234567890121",
 		PhoneNumber: "0812345678",
 		Email:       "somchai@example.com",
 		Gender:      domain.Male,
@@ -169,7 +321,7 @@ func TestPatientHandler_Create_Success(t *testing.T) {
 		PatientHN:   "HOSP0001-00000001",
 	}
 
-	mockService.On("Create", mock.AnythingOfType("*domain.PatientCreateRequest"), testSchemaName).Return(expectedPatient, nil)
+	mockService.On("Create", mock.AnythingOfType("*domain.PatientCreateRequest"), mock.AnythingOfType("*domain.Actor"), testSchemaName).Return(expectedPatient, nil)
 
 	body, _ := json.Marshal(createRequest)
 	req, _ := http.NewRequest("POST", "/patients", bytes.NewBuffer(body))
@@ -244,7 +396,7 @@ func TestPatientHandler_Create_InvalidEmail(t *testing.T) {
 		"date_of_birth": "1990-01-15",
 		"nick_name_th":  "ชาย",
 		"nick_name_en":  "Chai",
-		"national_id":   "1234567890123",
+		"national_id":   "1234567890121",
 		"passport_id":   "AB1234567",
 		"phone_number":  "0812345678",
 		"email":         "invalid-email", // Invalid email
@@ -263,6 +415,89 @@ func TestPatientHandler_Create_InvalidEmail(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, resp.Code)
 }
 
+func TestPatientHandler_Create_InvalidIdentifiers(t *testing.T) {
+	tests := []struct {
+		name       string
+		nationalID string
+		passportID string
+	}{
+		{"Wrong national ID check digit", "1234567890123", ""},
+		{"National ID with letters", "12345678901A1", ""},
+		{"Invalid passport number", "", "AB-12"},
+		{"No identifier", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockPatientService()
+			router := setupPatientRouter(mockService)
+
+			createRequest := map[string]interface{}{
+				"first_name_th": "สมชาย",
+				"last_name_th":  "ใจดี",
+				"first_name_en": "Somchai",
+				"last_name_en":  "Jaidee",
+				"date_of_birth": "1990-01-15",
+				"nick_name_th":  "ชาย",
+				"nick_name_en":  "Chai",
+				"national_id":   tt.nationalID,
+				"passport_id":   tt.passportID,
+				"phone_number":  "0812345678",
+				"email":         "somchai@example.com",
+				"gender":        "M",
+				"nationality":   "Thai",
+				"blood_grp":     "A",
+			}
+
+			body, _ := json.Marshal(createRequest)
+			req, _ := http.NewRequest("POST", "/patients", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			resp := httptest.NewRecorder()
+
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, http.StatusBadRequest, resp.Code)
+			mockService.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestPatientHandler_Create_PassportOnly(t *testing.T) {
+	mockService := mocks.NewMockPatientService()
+	router := setupPatientRouter(mockService)
+
+	createRequest := map[string]interface{}{
+		"first_name_th": "จอห์น",
+		"last_name_th":  "สมิธ",
+		"first_name_en": "John",
+		"last_name_en":  "Smith",
+		"date_of_birth": "1985-03-02",
+		"nick_name_th":  "จอห์น",
+		"nick_name_en":  "John",
+		"passport_id":   "X1234567",
+		"phone_number":  "0898765432",
+		"email":         "john@example.com",
+		"gender":        "M",
+		"nationality":   "British",
+		"blood_grp":     "O",
+	}
+
+	expectedPatient := &domain.Patient{PatientHN: "HOSP0001-00000002", PassportID: "X1234567"}
+	mockService.On("Create", mock.MatchedBy(func(req *domain.PatientCreateRequest) bool {
+		return req.NationalID == "" && req.PassportID == "X1234567"
+	}), mock.Anything, testSchemaName).Return(expectedPatient, nil)
+
+	body, _ := json.Marshal(createRequest)
+	req, _ := http.NewRequest("POST", "/patients", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	mockService.AssertExpectations(t)
+}
+
 func TestPatientHandler_Create_InvalidGender(t *testing.T) {
 	mockService := mocks.NewMockPatientService()
 	router := setupPatientRouter(mockService)
@@ -275,7 +510,7 @@ func TestPatientHandler_Create_InvalidGender(t *testing.T) {
 		"date_of_birth": "1990-01-15",
 		"nick_name_th":  "ชาย",
 		"nick_name_en":  "Chai",
-		"national_id":   "1234567890123",
+		"national_id":   "1234567890121",
 		"passport_id":   "AB1234567",
 		"phone_number":  "0812345678",
 		"email":         "test@example.com",
@@ -306,7 +541,7 @@ func TestPatientHandler_Create_InvalidBloodGroup(t *testing.T) {
 		"date_of_birth": "1990-01-15",
 		"nick_name_th":  "ชาย",
 		"nick_name_en":  "Chai",
-		"national_id":   "1234567890123",
+		"national_id":   "1234567890121",
 		"passport_id":   "AB1234567",
 		"phone_number":  "0812345678",
 		"email":         "test@example.com",
@@ -337,7 +572,7 @@ func TestPatientHandler_Create_ServiceError(t *testing.T) {
 		DateOfBirth: "1990-01-15",
 		NickNameTH:  "ชาย",
 		NickNameEN:  "Chai",
-		NationalID:  "1234567890123",
+		NationalID:  "1234567890121",
 		PassportID:  "AB1234567",
 		PhoneNumber: "0812345678",
 		Email:       "somchai@example.com",
@@ -347,7 +582,7 @@ func TestPatientHandler_Create_ServiceError(t *testing.T) {
 	}
 
 	// Use domain.ErrDuplicateEntry for proper error handling
-	mockService.On("Create", mock.AnythingOfType("*domain.PatientCreateRequest"), testSchemaName).Return(nil, domain.ErrDuplicateEntry)
+	mockService.On("Create", mock.AnythingOfType("*domain.PatientCreateRequest"), mock.AnythingOfType("*domain.Actor"), testSchemaName).Return(nil, domain.ErrDuplicateEntry)
 
 	body, _ := json.Marshal(createRequest)
 	req, _ := http.NewRequest("POST", "/patients", bytes.NewBuffer(body))
@@ -367,6 +602,40 @@ func TestPatientHandler_Create_ServiceError(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestPatientHandler_Create_HNSequenceExhausted(t *testing.T) {
+	mockService := mocks.NewMockPatientService()
+	router := setupPatientRouter(mockService)
+
+	createRequest := domain.PatientCreateRequest{
+		FirstNameTH: "สมชาย",
+		LastNameTH:  "ใจดี",
+		FirstNameEN: "Somchai",
+		LastNameEN:  "Jaidee",
+		DateOfBirth: "1990-01-15",
+		NickNameTH:  "ชาย",
+		NickNameEN:  "Chai",
+		NationalID:  "1234567890121",
+		PhoneNumber: "0812345678",
+		Email:       "somchai@example.com",
+		Gender:      domain.Male,
+		Nationality: "Thai",
+		BloodGrp:    "A",
+	}
+
+	mockService.On("Create", mock.AnythingOfType("*domain.PatientCreateRequest"), mock.AnythingOfType("*domain.Actor"), testSchemaName).
+		Return(nil, fmt.Errorf("%w: HN sequence 1000000 does not fit {SEQ:6}", domain.ErrHNSequenceExhausted))
+
+	body, _ := json.Marshal(createRequest)
+	req, _ := http.NewRequest("POST", "/patients", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusConflict, resp.Code)
+	assert.Contains(t, resp.Body.String(), "HN sequence exhausted")
+}
+
 // ==================== UPDATE TESTS ====================
 
 func TestPatientHandler_Update_Success(t *testing.T) {
@@ -379,6 +648,7 @@ func TestPatientHandler_Update_Success(t *testing.T) {
 		FirstNameEN: "Jane",
 		LastNameEN:  "Jaidee",
 		DateOfBirth: "1990-01-15",
+		NationalID:  "1234567890121",
 		Gender:      "F",
 		Nationality: "Thai",
 		BloodGrp:    "B",
@@ -396,10 +666,11 @@ func TestPatientHandler_Update_Success(t *testing.T) {
 		PatientHN:   "HOSP0001-00000001",
 	}
 
-	mockService.On("Update", uint(1), mock.AnythingOfType("*domain.PatientUpdateRequest"), testSchemaName).Return(expectedPatient, nil)
+	mockService.On("Update", uint(1), mock.AnythingOfType("*domain.PatientUpdateRequest"), uint(1), mock.AnythingOfType("*domain.Actor"), testSchemaName).Return(expectedPatient, nil)
 
 	body, _ := json.Marshal(updateRequest)
 	req, _ := http.NewRequest("PUT", "/patients/1", bytes.NewBuffer(body))
+	req.Header.Set("If-Match", `"1"`)
 	req.Header.Set("Content-Type", "application/json")
 	resp := httptest.NewRecorder()
 
@@ -424,6 +695,7 @@ func TestPatientHandler_Update_InvalidID(t *testing.T) {
 		FirstNameEN: "Jane",
 		LastNameEN:  "Doe",
 		DateOfBirth: "1990-01-15",
+		NationalID:  "1234567890121",
 		Gender:      "F",
 		Nationality: "Thai",
 		BloodGrp:    "B",
@@ -431,6 +703,7 @@ func TestPatientHandler_Update_InvalidID(t *testing.T) {
 
 	body, _ := json.Marshal(updateRequest)
 	req, _ := http.NewRequest("PUT", "/patients/invalid", bytes.NewBuffer(body))
+	req.Header.Set("If-Match", `"1"`)
 	req.Header.Set("Content-Type", "application/json")
 	resp := httptest.NewRecorder()
 
@@ -455,16 +728,18 @@ func TestPatientHandler_Update_NotFound(t *testing.T) {
 		FirstNameEN: "Jane",
 		LastNameEN:  "Jaidee",
 		DateOfBirth: "1990-01-15",
+		NationalID:  "1234567890121",
 		Gender:      "F",
 		Nationality: "Thai",
 		BloodGrp:    "B",
 	}
 
 	// Use domain.ErrNotFound for proper error handling
-	mockService.On("Update", uint(999), mock.AnythingOfType("*domain.PatientUpdateRequest"), testSchemaName).Return(nil, domain.ErrNotFound)
+	mockService.On("Update", uint(999), mock.AnythingOfType("*domain.PatientUpdateRequest"), uint(1), mock.AnythingOfType("*domain.Actor"), testSchemaName).Return(nil, domain.ErrNotFound)
 
 	body, _ := json.Marshal(updateRequest)
 	req, _ := http.NewRequest("PUT", "/patients/999", bytes.NewBuffer(body))
+	req.Header.Set("If-Match", `"1"`)
 	req.Header.Set("Content-Type", "application/json")
 	resp := httptest.NewRecorder()
 
@@ -486,6 +761,7 @@ func TestPatientHandler_Update_InvalidJSON(t *testing.T) {
 	router := setupPatientRouter(mockService)
 
 	req, _ := http.NewRequest("PUT", "/patients/1", bytes.NewBuffer([]byte("invalid json")))
+	req.Header.Set("If-Match", `"1"`)
 	req.Header.Set("Content-Type", "application/json")
 	resp := httptest.NewRecorder()
 
@@ -506,12 +782,41 @@ func TestPatientHandler_Update_MissingRequiredFields(t *testing.T) {
 
 	body, _ := json.Marshal(updateRequest)
 	req, _ := http.NewRequest("PUT", "/patients/1", bytes.NewBuffer(body))
+	req.Header.Set("If-Match", `"1"`)
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+func TestPatientHandler_Update_NoIdentifier(t *testing.T) {
+	mockService := mocks.NewMockPatientService()
+	router := setupPatientRouter(mockService)
+
+	// PUT replaces every field, so it must keep a national ID or passport like create
+	updateRequest := domain.PatientUpdateRequest{
+		FirstNameTH: "สมหญิง",
+		LastNameTH:  "ใจดี",
+		FirstNameEN: "Jane",
+		LastNameEN:  "Jaidee",
+		DateOfBirth: "1990-01-15",
+		Gender:      "F",
+		Nationality: "Thai",
+		BloodGrp:    "B",
+	}
+
+	body, _ := json.Marshal(updateRequest)
+	req, _ := http.NewRequest("PUT", "/patients/1", bytes.NewBuffer(body))
+	req.Header.Set("If-Match", `"1"`)
 	req.Header.Set("Content-Type", "application/json")
 	resp := httptest.NewRecorder()
 
 	router.ServeHTTP(resp, req)
 
 	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	mockService.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 }
 
 // ==================== PARTIAL UPDATE TESTS ====================
@@ -531,10 +836,11 @@ func TestPatientHandler_PartialUpdate_Success(t *testing.T) {
 		PatientHN:   "HOSP0001-00000001",
 	}
 
-	mockService.On("PartialUpdate", uint(1), mock.AnythingOfType("*domain.PatientPartialUpdateRequest"), testSchemaName).Return(expectedPatient, nil)
+	mockService.On("PartialUpdate", uint(1), mock.AnythingOfType("*domain.PatientPartialUpdateRequest"), uint(1), mock.AnythingOfType("*domain.Actor"), testSchemaName).Return(expectedPatient, nil)
 
 	body, _ := json.Marshal(partialUpdateRequest)
 	req, _ := http.NewRequest("PATCH", "/patients/1", bytes.NewBuffer(body))
+	req.Header.Set("If-Match", `"1"`)
 	req.Header.Set("Content-Type", "application/json")
 	resp := httptest.NewRecorder()
 
@@ -571,10 +877,11 @@ func TestPatientHandler_PartialUpdate_MultipleFields(t *testing.T) {
 		PatientHN:   "HOSP0001-00000001",
 	}
 
-	mockService.On("PartialUpdate", uint(1), mock.AnythingOfType("*domain.PatientPartialUpdateRequest"), testSchemaName).Return(expectedPatient, nil)
+	mockService.On("PartialUpdate", uint(1), mock.AnythingOfType("*domain.PatientPartialUpdateRequest"), uint(1), mock.AnythingOfType("*domain.Actor"), testSchemaName).Return(expectedPatient, nil)
 
 	body, _ := json.Marshal(partialUpdateRequest)
 	req, _ := http.NewRequest("PATCH", "/patients/1", bytes.NewBuffer(body))
+	req.Header.Set("If-Match", `"1"`)
 	req.Header.Set("Content-Type", "application/json")
 	resp := httptest.NewRecorder()
 
@@ -595,6 +902,7 @@ func TestPatientHandler_PartialUpdate_InvalidID(t *testing.T) {
 
 	body, _ := json.Marshal(partialUpdateRequest)
 	req, _ := http.NewRequest("PATCH", "/patients/invalid", bytes.NewBuffer(body))
+	req.Header.Set("If-Match", `"1"`)
 	req.Header.Set("Content-Type", "application/json")
 	resp := httptest.NewRecorder()
 
@@ -619,10 +927,11 @@ func TestPatientHandler_PartialUpdate_NotFound(t *testing.T) {
 	}
 
 	// Use domain.ErrNotFound for proper error handling
-	mockService.On("PartialUpdate", uint(999), mock.AnythingOfType("*domain.PatientPartialUpdateRequest"), testSchemaName).Return(nil, domain.ErrNotFound)
+	mockService.On("PartialUpdate", uint(999), mock.AnythingOfType("*domain.PatientPartialUpdateRequest"), uint(1), mock.AnythingOfType("*domain.Actor"), testSchemaName).Return(nil, domain.ErrNotFound)
 
 	body, _ := json.Marshal(partialUpdateRequest)
 	req, _ := http.NewRequest("PATCH", "/patients/999", bytes.NewBuffer(body))
+	req.Header.Set("If-Match", `"1"`)
 	req.Header.Set("Content-Type", "application/json")
 	resp := httptest.NewRecorder()
 
@@ -644,6 +953,7 @@ func TestPatientHandler_PartialUpdate_InvalidJSON(t *testing.T) {
 	router := setupPatientRouter(mockService)
 
 	req, _ := http.NewRequest("PATCH", "/patients/1", bytes.NewBuffer([]byte("invalid json")))
+	req.Header.Set("If-Match", `"1"`)
 	req.Header.Set("Content-Type", "application/json")
 	resp := httptest.NewRecorder()
 
@@ -663,6 +973,7 @@ func TestPatientHandler_PartialUpdate_InvalidEmail(t *testing.T) {
 
 	body, _ := json.Marshal(partialUpdateRequest)
 	req, _ := http.NewRequest("PATCH", "/patients/1", bytes.NewBuffer(body))
+	req.Header.Set("If-Match", `"1"`)
 	req.Header.Set("Content-Type", "application/json")
 	resp := httptest.NewRecorder()
 
@@ -671,6 +982,51 @@ func TestPatientHandler_PartialUpdate_InvalidEmail(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, resp.Code)
 }
 
+func TestPatientHandler_PartialUpdate_InvalidNationalID(t *testing.T) {
+	mockService := mocks.NewMockPatientService()
+	router := setupPatientRouter(mockService)
+
+	invalidID := "1234567890123"
+	partialUpdateRequest := domain.PatientPartialUpdateRequest{
+		NationalID: &invalidID,
+	}
+
+	body, _ := json.Marshal(partialUpdateRequest)
+	req, _ := http.NewRequest("PATCH", "/patients/1", bytes.NewBuffer(body))
+	req.Header.Set("If-Match", `"1"`)
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+func TestPatientHandler_PartialUpdate_ClearPassport(t *testing.T) {
+	mockService := mocks.NewMockPatientService()
+	router := setupPatientRouter(mockService)
+
+	blank := ""
+	partialUpdateRequest := domain.PatientPartialUpdateRequest{
+		PassportID: &blank,
+	}
+
+	mockService.On("PartialUpdate", uint(1), mock.MatchedBy(func(req *domain.PatientPartialUpdateRequest) bool {
+		return req.PassportID != nil && *req.PassportID == ""
+	}), uint(1), mock.Anything, testSchemaName).Return(&domain.Patient{NationalID: "1234567890121"}, nil)
+
+	body, _ := json.Marshal(partialUpdateRequest)
+	req, _ := http.NewRequest("PATCH", "/patients/1", bytes.NewBuffer(body))
+	req.Header.Set("If-Match", `"1"`)
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	mockService.AssertExpectations(t)
+}
+
 func TestPatientHandler_PartialUpdate_InvalidGender(t *testing.T) {
 	mockService := mocks.NewMockPatientService()
 	router := setupPatientRouter(mockService)
@@ -682,6 +1038,7 @@ func TestPatientHandler_PartialUpdate_InvalidGender(t *testing.T) {
 
 	body, _ := json.Marshal(partialUpdateRequest)
 	req, _ := http.NewRequest("PATCH", "/patients/1", bytes.NewBuffer(body))
+	req.Header.Set("If-Match", `"1"`)
 	req.Header.Set("Content-Type", "application/json")
 	resp := httptest.NewRecorder()
 
@@ -696,7 +1053,7 @@ func TestPatientHandler_Delete_Success(t *testing.T) {
 	mockService := mocks.NewMockPatientService()
 	router := setupPatientRouter(mockService)
 
-	mockService.On("Delete", uint(1), testSchemaName).Return(nil)
+	mockService.On("Delete", uint(1), mock.AnythingOfType("*domain.Actor"), testSchemaName).Return(nil)
 
 	req, _ := http.NewRequest("DELETE", "/patients/1", nil)
 	resp := httptest.NewRecorder()
@@ -737,7 +1094,7 @@ func TestPatientHandler_Delete_NotFound(t *testing.T) {
 	router := setupPatientRouter(mockService)
 
 	// Use domain.ErrNotFound for proper error handling
-	mockService.On("Delete", uint(999), testSchemaName).Return(domain.ErrNotFound)
+	mockService.On("Delete", uint(999), mock.AnythingOfType("*domain.Actor"), testSchemaName).Return(domain.ErrNotFound)
 
 	req, _ := http.NewRequest("DELETE", "/patients/999", nil)
 	resp := httptest.NewRecorder()
@@ -760,7 +1117,7 @@ func TestPatientHandler_Delete_ZeroID(t *testing.T) {
 	router := setupPatientRouter(mockService)
 
 	// Use domain.ErrNotFound as ID 0 won't match any record
-	mockService.On("Delete", uint(0), testSchemaName).Return(domain.ErrNotFound)
+	mockService.On("Delete", uint(0), mock.AnythingOfType("*domain.Actor"), testSchemaName).Return(domain.ErrNotFound)
 
 	req, _ := http.NewRequest("DELETE", "/patients/0", nil)
 	resp := httptest.NewRecorder()
@@ -791,7 +1148,7 @@ func TestPatientHandler_Create_MaxLengthFields(t *testing.T) {
 		"date_of_birth": "1990-01-15",
 		"nick_name_th":  "ชาย",
 		"nick_name_en":  "Chai",
-		"national_id":   "1234567890123",
+		"national_id":   "1234567890121",
 		"passport_id":   "AB1234567",
 		"phone_number":  "0812345678",
 		"email":         "test@example.com",
@@ -828,6 +1185,7 @@ func TestPatientHandler_Update_EmptyBody(t *testing.T) {
 	router := setupPatientRouter(mockService)
 
 	req, _ := http.NewRequest("PUT", "/patients/1", bytes.NewBuffer([]byte("{}")))
+	req.Header.Set("If-Match", `"1"`)
 	req.Header.Set("Content-Type", "application/json")
 	resp := httptest.NewRecorder()
 
@@ -848,9 +1206,10 @@ func TestPatientHandler_PartialUpdate_EmptyBody(t *testing.T) {
 	}
 
 	// Empty body is valid for PATCH - no fields to update
-	mockService.On("PartialUpdate", uint(1), mock.AnythingOfType("*domain.PatientPartialUpdateRequest"), testSchemaName).Return(expectedPatient, nil)
+	mockService.On("PartialUpdate", uint(1), mock.AnythingOfType("*domain.PatientPartialUpdateRequest"), uint(1), mock.AnythingOfType("*domain.Actor"), testSchemaName).Return(expectedPatient, nil)
 
 	req, _ := http.NewRequest("PATCH", "/patients/1", bytes.NewBuffer([]byte("{}")))
+	req.Header.Set("If-Match", `"1"`)
 	req.Header.Set("Content-Type", "application/json")
 	resp := httptest.NewRecorder()
 
@@ -859,3 +1218,302 @@ func TestPatientHandler_PartialUpdate_EmptyBody(t *testing.T) {
 	assert.Equal(t, http.StatusOK, resp.Code)
 	mockService.AssertExpectations(t)
 }
+
+func TestPatientHandler_Create_PossibleDuplicate(t *testing.T) {
+	mockService := mocks.NewMockPatientService()
+	router := setupPatientRouter(mockService)
+
+	createRequest := domain.PatientCreateRequest{
+		FirstNameTH: "จอห์น",
+		LastNameTH:  "โด",
+		FirstNameEN: "John",
+		LastNameEN:  "Doe",
+		DateOfBirth: "1990-01-15",
+		NickNameTH:  "จอห์น",
+		NickNameEN:  "John",
+		PassportID:  "AB1234567",
+		PhoneNumber: "0812345678",
+		Email:       "john@example.com",
+		Gender:      domain.Male,
+		Nationality: "Thai",
+		BloodGrp:    "A",
+	}
+
+	dupErr := &domain.DuplicatePatientError{Candidates: []domain.DuplicateCandidate{
+		{Patient: domain.Patient{PatientHN: "HOSP0001-00000001"}, Score: 130, Reasons: []string{"passport_id", "date_of_birth"}},
+	}}
+	mockService.On("Create", mock.AnythingOfType("*domain.PatientCreateRequest"), mock.AnythingOfType("*domain.Actor"), testSchemaName).Return(nil, dupErr)
+
+	body, _ := json.Marshal(createRequest)
+	req, _ := http.NewRequest("POST", "/patients", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusConflict, resp.Code)
+
+	var response struct {
+		Success bool                        `json:"success"`
+		Error   string                      `json:"error"`
+		Data    []domain.DuplicateCandidate `json:"data"`
+	}
+	err := json.Unmarshal(resp.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.False(t, response.Success)
+	assert.Contains(t, response.Error, "force=true")
+	assert.Len(t, response.Data, 1)
+	assert.Equal(t, "HOSP0001-00000001", response.Data[0].Patient.PatientHN)
+	assert.Equal(t, 130, response.Data[0].Score)
+}
+
+func TestPatientHandler_Merge(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		serviceError   error
+		expectedStatus int
+	}{
+		{
+			name:           "successful merge",
+			body:           `{"survivor_id": 1, "retired_id": 2, "reason": "registered twice"}`,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "missing reason",
+			body:           `{"survivor_id": 1, "retired_id": 2}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "merge into itself",
+			body:           `{"survivor_id": 1, "retired_id": 1, "reason": "registered twice"}`,
+			serviceError:   fmt.Errorf("%w: a patient cannot be merged into itself", domain.ErrInvalidInput),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "patient not found",
+			body:           `{"survivor_id": 1, "retired_id": 99, "reason": "registered twice"}`,
+			serviceError:   domain.ErrNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockPatientService()
+			router := setupPatientRouter(mockService)
+
+			if tt.expectedStatus != http.StatusBadRequest || tt.serviceError != nil {
+				merge := &domain.PatientMerge{ID: 5, SurvivorID: 1, RetiredID: 2, RetiredHN: "HOSP0001-00000002"}
+				if tt.serviceError != nil {
+					merge = nil
+				}
+				mockService.On("Merge", mock.AnythingOfType("*domain.PatientMergeRequest"), mock.AnythingOfType("*domain.Actor"), testSchemaName).Return(merge, tt.serviceError)
+			}
+
+			req, _ := http.NewRequest("POST", "/patients/merge", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp := httptest.NewRecorder()
+
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tt.expectedStatus, resp.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPatientHandler_Unmerge(t *testing.T) {
+	mockService := mocks.NewMockPatientService()
+	router := setupPatientRouter(mockService)
+
+	now := time.Now()
+	merge := &domain.PatientMerge{ID: 5, SurvivorID: 1, RetiredID: 2, UnmergedAt: &now}
+	mockService.On("Unmerge", uint(5), mock.AnythingOfType("*domain.Actor"), testSchemaName).Return(merge, nil)
+	mockService.On("Unmerge", uint(6), mock.AnythingOfType("*domain.Actor"), testSchemaName).Return(nil, domain.ErrNotFound)
+
+	req, _ := http.NewRequest("POST", "/patients/unmerge/5", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	req, _ = http.NewRequest("POST", "/patients/unmerge/6", nil)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+	assert.Contains(t, resp.Body.String(), "merge not found")
+
+	mockService.AssertExpectations(t)
+}
+
+func TestPatientHandler_History(t *testing.T) {
+	mockService := mocks.NewMockPatientService()
+	router := setupPatientRouter(mockService)
+
+	versions := []domain.PatientVersion{{PatientID: 1, Version: 1}, {PatientID: 1, Version: 2}}
+	mockService.On("History", uint(1), mock.AnythingOfType("*domain.Actor"), testSchemaName).Return(versions, nil)
+
+	req, _ := http.NewRequest("GET", "/patients/1/history", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"version":2`)
+	mockService.AssertExpectations(t)
+}
+
+func TestPatientHandler_DiffVersions(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		setup          func(m *mocks.MockPatientService)
+		expectedStatus int
+	}{
+		{
+			name: "diff",
+			url:  "/patients/1/history/diff?from=1&to=2",
+			setup: func(m *mocks.MockPatientService) {
+				m.On("DiffVersions", uint(1), 1, 2, mock.AnythingOfType("*domain.Actor"), testSchemaName).Return(&domain.PatientVersionDiff{
+					PatientID: 1, From: 1, To: 2,
+					Changes: map[string]domain.FieldChange{"blood_grp": {Before: "A", After: "B"}},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing versions",
+			url:            "/patients/1/history/diff?from=1",
+			setup:          func(m *mocks.MockPatientService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "unknown version",
+			url:  "/patients/1/history/diff?from=1&to=9",
+			setup: func(m *mocks.MockPatientService) {
+				m.On("DiffVersions", uint(1), 1, 9, mock.AnythingOfType("*domain.Actor"), testSchemaName).Return(nil, domain.ErrNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockPatientService()
+			tt.setup(mockService)
+			router := setupPatientRouter(mockService)
+
+			req, _ := http.NewRequest("GET", tt.url, nil)
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tt.expectedStatus, resp.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPatientHandler_GetByID_SetsETag(t *testing.T) {
+	mockService := mocks.NewMockPatientService()
+	router := setupPatientRouter(mockService)
+
+	patient := &domain.Patient{PatientHN: "HOSP0001-00000001"}
+	patient.ID = 1
+	patient.Version = 7
+	mockService.On("SearchByID", uint(1), mock.AnythingOfType("*domain.Actor"), testSchemaName).Return(patient, nil)
+
+	req, _ := http.NewRequest("GET", "/patients/1", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, `"7"`, resp.Header().Get("ETag"))
+}
+
+func TestPatientHandler_Update_Preconditions(t *testing.T) {
+	body, _ := json.Marshal(map[string]string{"first_name_en": "Jon"})
+
+	tests := []struct {
+		name           string
+		ifMatch        string
+		setup          func(m *mocks.MockPatientService)
+		expectedStatus int
+	}{
+		{
+			name:           "missing If-Match",
+			ifMatch:        "",
+			setup:          func(m *mocks.MockPatientService) {},
+			expectedStatus: http.StatusPreconditionRequired,
+		},
+		{
+			name:           "malformed If-Match",
+			ifMatch:        "seven",
+			setup:          func(m *mocks.MockPatientService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:    "stale version",
+			ifMatch: `"6"`,
+			setup: func(m *mocks.MockPatientService) {
+				m.On("PartialUpdate", uint(1), mock.AnythingOfType("*domain.PatientPartialUpdateRequest"), uint(6), mock.AnythingOfType("*domain.Actor"), testSchemaName).Return(nil, domain.ErrPreconditionFailed)
+			},
+			expectedStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name:    "current version, weak tag",
+			ifMatch: `W/"7"`,
+			setup: func(m *mocks.MockPatientService) {
+				updated := &domain.Patient{FirstNameEN: "Jon"}
+				updated.Version = 8
+				m.On("PartialUpdate", uint(1), mock.AnythingOfType("*domain.PatientPartialUpdateRequest"), uint(7), mock.AnythingOfType("*domain.Actor"), testSchemaName).Return(updated, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockPatientService()
+			tt.setup(mockService)
+			router := setupPatientRouter(mockService)
+
+			req, _ := http.NewRequest("PATCH", "/patients/1", bytes.NewBuffer(body))
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			req.Header.Set("Content-Type", "application/json")
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tt.expectedStatus, resp.Code)
+			if tt.expectedStatus == http.StatusOK {
+				assert.Equal(t, `"8"`, resp.Header().Get("ETag"))
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPatientHandler_Search_IncludesAllergyBanner(t *testing.T) {
+	mockService := mocks.NewMockPatientService()
+	router := setupPatientRouter(mockService)
+
+	patient := domain.Patient{
+		FirstNameEN: "John",
+		PatientHN:   "HOSP0001-00000001",
+		AllergyBanner: &domain.AllergyBanner{
+			Count:           1,
+			HighestSeverity: domain.AllergySeveritySevere,
+			HasDrugAllergy:  true,
+			Summary:         "Penicillin (severe)",
+		},
+	}
+	mockService.On("Search", &domain.PatientSearchRequest{Query: "John"}, mock.AnythingOfType("*domain.Actor"), testSchemaName).
+		Return(&domain.PatientSearchResult{Patients: searchItems(patient), Total: 1, Limit: domain.DefaultPatientSearchLimit}, nil)
+
+	req, _ := http.NewRequest("GET", "/patients/search?query=John", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"allergy_banner":{"count":1,"highest_severity":"severe","has_drug_allergy":true,"summary":"Penicillin (severe)"}`)
+}
@@ -0,0 +1,127 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/delivery/http/handler"
+	"github.com/wichai2002/his_v1/internal/delivery/http/middleware"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/mocks"
+	"github.com/wichai2002/his_v1/pkg/utils"
+)
+
+// setupRoleRouter creates a test router with tenant context and the given permissions
+func setupRoleRouter(mockService *mocks.MockRoleService, permissions []string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.TenantSchemaKey, testSchemaName)
+		c.Set("permissions", permissions)
+		c.Next()
+	})
+
+	roleHandler := handler.NewRoleHandler(mockService)
+
+	roles := router.Group("/role")
+	roles.Use(middleware.RequirePermission(domain.PermRoleManage))
+	{
+		roles.GET("", roleHandler.GetAll)
+		roles.POST("/create", roleHandler.Create)
+		roles.DELETE("/delete/:id", roleHandler.Delete)
+		roles.PUT("/staff/:id", roleHandler.AssignStaffRoles)
+	}
+
+	return router
+}
+
+func TestRoleHandler_GetAll_Success(t *testing.T) {
+	mockService := mocks.NewMockRoleService()
+	router := setupRoleRouter(mockService, []string{domain.PermRoleManage})
+
+	mockService.On("GetAll", testSchemaName).Return([]domain.Role{{Code: domain.RoleDoctor}}, nil)
+
+	req, _ := http.NewRequest("GET", "/role", nil)
+	resp := httptest.NewRecorder()
+
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestRoleHandler_MissingPermission(t *testing.T) {
+	mockService := mocks.NewMockRoleService()
+	router := setupRoleRouter(mockService, []string{domain.PermPatientRead})
+
+	req, _ := http.NewRequest("GET", "/role", nil)
+	resp := httptest.NewRecorder()
+
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+
+	var response utils.Response
+	err := json.Unmarshal(resp.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "permission required: role:manage", response.Error)
+
+	mockService.AssertNotCalled(t, "GetAll", mock.Anything)
+}
+
+func TestRoleHandler_Create_UnknownPermission(t *testing.T) {
+	mockService := mocks.NewMockRoleService()
+	router := setupRoleRouter(mockService, []string{domain.PermRoleManage})
+
+	mockService.On("Create", mock.AnythingOfType("*domain.RoleCreateRequest"), testSchemaName).
+		Return(nil, domain.ErrInvalidInput)
+
+	body, _ := json.Marshal(domain.RoleCreateRequest{Code: "lab", Name: "Lab", Permissions: []string{"lab:fly"}})
+	req, _ := http.NewRequest("POST", "/role/create", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestRoleHandler_Delete_NotFound(t *testing.T) {
+	mockService := mocks.NewMockRoleService()
+	router := setupRoleRouter(mockService, []string{domain.PermRoleManage})
+
+	mockService.On("Delete", uint(99), testSchemaName).Return(domain.ErrNotFound)
+
+	req, _ := http.NewRequest("DELETE", "/role/delete/99", nil)
+	resp := httptest.NewRecorder()
+
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestRoleHandler_AssignStaffRoles_Success(t *testing.T) {
+	mockService := mocks.NewMockRoleService()
+	router := setupRoleRouter(mockService, []string{domain.PermRoleManage})
+
+	mockService.On("AssignStaffRoles", uint(3), &domain.StaffRolesRequest{Roles: []string{domain.RoleNurse}}, testSchemaName).Return(nil)
+
+	body, _ := json.Marshal(domain.StaffRolesRequest{Roles: []string{domain.RoleNurse}})
+	req, _ := http.NewRequest("PUT", "/role/staff/3", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,139 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/delivery/http/handler"
+	"github.com/wichai2002/his_v1/internal/delivery/http/middleware"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/mocks"
+)
+
+// setupSettingsRouter creates a test router with tenant context and the given permissions
+func setupSettingsRouter(mockService *mocks.MockHNService, permissions []string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.TenantSchemaKey, testSchemaName)
+		c.Set("permissions", permissions)
+		c.Next()
+	})
+
+	settingsHandler := handler.NewSettingsHandler(mockService)
+
+	settings := router.Group("/settings")
+	settings.Use(middleware.RequirePermission(domain.PermSettingsManage))
+	{
+		settings.GET("/hn-template", settingsHandler.GetHNTemplate)
+		settings.PUT("/hn-template", settingsHandler.UpdateHNTemplate)
+		settings.POST("/hn-template/preview", settingsHandler.PreviewHNTemplate)
+	}
+
+	return router
+}
+
+func TestSettingsHandler_RequiresPermission(t *testing.T) {
+	mockService := mocks.NewMockHNService()
+	router := setupSettingsRouter(mockService, []string{domain.PermPatientWrite})
+
+	req, _ := http.NewRequest("GET", "/settings/hn-template", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+	mockService.AssertNotCalled(t, "GetSettings", mock.Anything)
+}
+
+func TestSettingsHandler_UpdateHNTemplate(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		setup          func(m *mocks.MockHNService)
+		expectedStatus int
+	}{
+		{
+			name: "updated",
+			body: `{"template":"{BE_YY}-{SEQ:6}{CHECK}"}`,
+			setup: func(m *mocks.MockHNService) {
+				m.On("UpdateTemplate", &domain.HNTemplateRequest{Template: "{BE_YY}-{SEQ:6}{CHECK}"}, testSchemaName).
+					Return(&domain.HNSettings{Template: "{BE_YY}-{SEQ:6}{CHECK}", NextHN: "67-0000016"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "invalid template",
+			body: `{"template":"{BE_YY}"}`,
+			setup: func(m *mocks.MockHNService) {
+				m.On("UpdateTemplate", mock.Anything, testSchemaName).Return(nil, domain.ErrInvalidInput)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing template",
+			body:           `{}`,
+			setup:          func(m *mocks.MockHNService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockHNService()
+			tt.setup(mockService)
+			router := setupSettingsRouter(mockService, []string{domain.PermSettingsManage})
+
+			req, _ := http.NewRequest("PUT", "/settings/hn-template", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tt.expectedStatus, resp.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSettingsHandler_UpdateHNTemplate_Collision(t *testing.T) {
+	mockService := mocks.NewMockHNService()
+	router := setupSettingsRouter(mockService, []string{domain.PermSettingsManage})
+
+	mockService.On("UpdateTemplate", mock.Anything, testSchemaName).
+		Return(nil, &domain.HNCollisionError{Collisions: []string{"67-000011"}})
+
+	req, _ := http.NewRequest("PUT", "/settings/hn-template", bytes.NewBufferString(`{"template":"{BE_YY}-{SEQ:6}"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusConflict, resp.Code)
+
+	var response struct {
+		Data []string `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &response))
+	assert.Equal(t, []string{"67-000011"}, response.Data)
+}
+
+func TestSettingsHandler_PreviewHNTemplate(t *testing.T) {
+	mockService := mocks.NewMockHNService()
+	router := setupSettingsRouter(mockService, []string{domain.PermSettingsManage})
+
+	mockService.On("Preview", &domain.HNPreviewRequest{Template: "{BE_YY}-{SEQ:6}", Count: 2}, testSchemaName).
+		Return(&domain.HNPreview{Template: "{BE_YY}-{SEQ:6}", Next: []string{"67-000001", "67-000002"}, Collisions: []string{}}, nil)
+
+	req, _ := http.NewRequest("POST", "/settings/hn-template/preview", bytes.NewBufferString(`{"template":"{BE_YY}-{SEQ:6}","count":2}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"next":["67-000001","67-000002"]`)
+}
@@ -35,7 +35,9 @@ func setupStaffRouter(mockService *mocks.MockStaffService) *gin.Engine {
 	staff := router.Group("/staff")
 	{
 		staff.POST("/login", staffHandler.Login)
+		staff.POST("/token/refresh", staffHandler.RefreshToken)
 		staff.POST("/logout", staffHandler.Logout)
+		staff.PUT("/password", staffHandler.ChangePassword)
 		staff.GET("", staffHandler.GetAll)
 		staff.GET("/:id", staffHandler.GetByID)
 		staff.POST("/create", staffHandler.Create)
@@ -236,6 +238,9 @@ func TestStaffHandler_Logout_Success(t *testing.T) {
 	mockService := mocks.NewMockStaffService()
 	router := setupStaffRouter(mockService)
 
+	// No user_id in context in this test router, so the zero ID is revoked
+	mockService.On("Logout", uint(0), testSchemaName).Return(nil)
+
 	req, _ := http.NewRequest("POST", "/staff/logout", nil)
 	resp := httptest.NewRecorder()
 
@@ -248,6 +253,89 @@ func TestStaffHandler_Logout_Success(t *testing.T) {
 	assert.NoError(t, err)
 	assert.True(t, response.Success)
 	assert.Equal(t, "logout successful", response.Message)
+
+	mockService.AssertExpectations(t)
+}
+
+// ==================== TOKEN REFRESH TESTS ====================
+
+func TestStaffHandler_RefreshToken_Success(t *testing.T) {
+	mockService := mocks.NewMockStaffService()
+	router := setupStaffRouter(mockService)
+
+	expectedResponse := &domain.StaffLoginResponse{
+		Token:        "new-access-token",
+		RefreshToken: "new-refresh-token",
+		Staff:        domain.StaffResponse{ID: 1, Username: "admin"},
+	}
+
+	mockService.On("RefreshToken", &domain.TokenRefreshRequest{RefreshToken: "old-refresh-token"}, testSchemaName).Return(expectedResponse, nil)
+
+	body, _ := json.Marshal(map[string]string{"refresh_token": "old-refresh-token"})
+	req, _ := http.NewRequest("POST", "/staff/token/refresh", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	var response utils.Response
+	err := json.Unmarshal(resp.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.True(t, response.Success)
+	assert.Equal(t, "token refreshed", response.Message)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestStaffHandler_RefreshToken_Invalid(t *testing.T) {
+	mockService := mocks.NewMockStaffService()
+	router := setupStaffRouter(mockService)
+
+	mockService.On("RefreshToken", mock.AnythingOfType("*domain.TokenRefreshRequest"), testSchemaName).Return(nil, domain.ErrInvalidToken)
+
+	body, _ := json.Marshal(map[string]string{"refresh_token": "reused-token"})
+	req, _ := http.NewRequest("POST", "/staff/token/refresh", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestStaffHandler_RefreshToken_MissingToken(t *testing.T) {
+	mockService := mocks.NewMockStaffService()
+	router := setupStaffRouter(mockService)
+
+	req, _ := http.NewRequest("POST", "/staff/token/refresh", bytes.NewBuffer([]byte("{}")))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+// ==================== CHANGE PASSWORD TESTS ====================
+
+func TestStaffHandler_ChangePassword_WrongCurrentPassword(t *testing.T) {
+	mockService := mocks.NewMockStaffService()
+	router := setupStaffRouter(mockService)
+
+	mockService.On("ChangePassword", uint(0), mock.AnythingOfType("*domain.StaffChangePasswordRequest"), testSchemaName).Return(domain.ErrInvalidCredentials)
+
+	body, _ := json.Marshal(domain.StaffChangePasswordRequest{CurrentPassword: "wrongpass", NewPassword: "newpassword"})
+	req, _ := http.NewRequest("PUT", "/staff/password", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+	mockService.AssertExpectations(t)
 }
 
 // ==================== GET ALL TESTS ====================
@@ -257,8 +345,8 @@ func TestStaffHandler_GetAll_Success(t *testing.T) {
 	router := setupStaffRouter(mockService)
 
 	expectedStaffs := []domain.Staff{
-		{Username: "admin", StaffCode: "STF001", Email: "admin@test.com", FirstName: "Admin", LastName: "User", IsAdmin: true},
-		{Username: "user1", StaffCode: "STF002", Email: "user1@test.com", FirstName: "User", LastName: "One", IsAdmin: false},
+		{Username: "admin", StaffCode: "STF001", Email: "admin@test.com", FirstName: "Admin", LastName: "User"},
+		{Username: "user1", StaffCode: "STF002", Email: "user1@test.com", FirstName: "User", LastName: "One"},
 	}
 
 	mockService.On("GetAll", testSchemaName).Return(expectedStaffs, nil)
@@ -334,7 +422,6 @@ func TestStaffHandler_GetByID_Success(t *testing.T) {
 		Email:     "admin@test.com",
 		FirstName: "Admin",
 		LastName:  "User",
-		IsAdmin:   true,
 	}
 
 	mockService.On("GetByID", uint(1), testSchemaName).Return(expectedStaff, nil)
@@ -408,7 +495,6 @@ func TestStaffHandler_Create_Success(t *testing.T) {
 		Email:       "newuser@test.com",
 		FirstName:   "New",
 		LastName:    "User",
-		IsAdmin:     false,
 	}
 
 	expectedStaff := &domain.Staff{
@@ -418,7 +504,6 @@ func TestStaffHandler_Create_Success(t *testing.T) {
 		Email:       "newuser@test.com",
 		FirstName:   "New",
 		LastName:    "User",
-		IsAdmin:     false,
 	}
 
 	mockService.On("Create", mock.AnythingOfType("*domain.StaffCreateRequest"), testSchemaName).Return(expectedStaff, nil)
@@ -564,7 +649,6 @@ func TestStaffHandler_Update_Success(t *testing.T) {
 		Email:       "updated@test.com",
 		FirstName:   "Updated",
 		LastName:    "Name",
-		IsAdmin:     true,
 	}
 
 	expectedStaff := &domain.Staff{
@@ -574,7 +658,6 @@ func TestStaffHandler_Update_Success(t *testing.T) {
 		Email:       "updated@test.com",
 		FirstName:   "Updated",
 		LastName:    "Name",
-		IsAdmin:     true,
 	}
 
 	mockService.On("Update", uint(1), mock.AnythingOfType("*domain.StaffUpdateRequest"), testSchemaName).Return(expectedStaff, nil)
@@ -829,18 +912,19 @@ func TestStaffHandler_Login_EmptyBody(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, resp.Code)
 }
 
-func TestStaffHandler_Create_AdminUser(t *testing.T) {
+func TestStaffHandler_Create_IgnoresIsAdmin(t *testing.T) {
 	mockService := mocks.NewMockStaffService()
 	router := setupStaffRouter(mockService)
 
-	createRequest := domain.StaffCreateRequest{
-		Username:    "newadmin",
-		Password:    "password123",
-		PhoneNumber: "0812345678",
-		Email:       "newadmin@test.com",
-		FirstName:   "New",
-		LastName:    "Admin",
-		IsAdmin:     true,
+	// Privileges are only granted through role assignment, so is_admin in the body has no effect
+	createRequest := map[string]interface{}{
+		"username":     "newadmin",
+		"password":     "password123",
+		"phone_number": "0812345678",
+		"email":        "newadmin@test.com",
+		"first_name":   "New",
+		"last_name":    "Admin",
+		"is_admin":     true,
 	}
 
 	expectedStaff := &domain.Staff{
@@ -850,7 +934,6 @@ func TestStaffHandler_Create_AdminUser(t *testing.T) {
 		Email:       "newadmin@test.com",
 		FirstName:   "New",
 		LastName:    "Admin",
-		IsAdmin:     true,
 	}
 
 	mockService.On("Create", mock.AnythingOfType("*domain.StaffCreateRequest"), testSchemaName).Return(expectedStaff, nil)
@@ -863,6 +946,7 @@ func TestStaffHandler_Create_AdminUser(t *testing.T) {
 	router.ServeHTTP(resp, req)
 
 	assert.Equal(t, http.StatusCreated, resp.Code)
+	assert.NotContains(t, resp.Body.String(), "is_admin")
 	mockService.AssertExpectations(t)
 }
 
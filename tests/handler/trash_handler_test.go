@@ -0,0 +1,142 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/delivery/http/handler"
+	"github.com/wichai2002/his_v1/internal/delivery/http/middleware"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/mocks"
+)
+
+// setupTrashRouter creates a test router with tenant context and the given permissions
+func setupTrashRouter(mockService *mocks.MockTrashService, permissions []string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.TenantSchemaKey, testSchemaName)
+		c.Set("permissions", permissions)
+		c.Next()
+	})
+
+	trashHandler := handler.NewTrashHandler(mockService)
+
+	trash := router.Group("/trash")
+	trash.Use(middleware.RequirePermission(domain.PermTrashManage))
+	{
+		trash.GET("/patients", trashHandler.ListPatients)
+		trash.POST("/patients/restore/:id", trashHandler.RestorePatient)
+		trash.DELETE("/patients/purge", trashHandler.PurgePatients)
+		trash.GET("/staff", trashHandler.ListStaff)
+		trash.POST("/staff/restore/:id", trashHandler.RestoreStaff)
+		trash.DELETE("/staff/purge", trashHandler.PurgeStaff)
+	}
+
+	return router
+}
+
+func TestTrashHandler_RequiresPermission(t *testing.T) {
+	mockService := mocks.NewMockTrashService()
+	router := setupTrashRouter(mockService, []string{domain.PermPatientDelete})
+
+	req, _ := http.NewRequest("GET", "/trash/patients", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+	mockService.AssertNotCalled(t, "ListPatients", mock.Anything)
+}
+
+func TestTrashHandler_RestorePatient(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		setup          func(m *mocks.MockTrashService)
+		expectedStatus int
+	}{
+		{
+			name: "restored",
+			url:  "/trash/patients/restore/1",
+			setup: func(m *mocks.MockTrashService) {
+				m.On("RestorePatient", uint(1), mock.AnythingOfType("*domain.Actor"), testSchemaName).Return(&domain.Patient{PatientHN: "HOSP0001-00000001"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "not in the trash",
+			url:  "/trash/patients/restore/2",
+			setup: func(m *mocks.MockTrashService) {
+				m.On("RestorePatient", uint(2), mock.AnythingOfType("*domain.Actor"), testSchemaName).Return(nil, domain.ErrNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "invalid id",
+			url:            "/trash/patients/restore/abc",
+			setup:          func(m *mocks.MockTrashService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockTrashService()
+			tt.setup(mockService)
+			router := setupTrashRouter(mockService, []string{domain.PermTrashManage})
+
+			req, _ := http.NewRequest("POST", tt.url, nil)
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tt.expectedStatus, resp.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTrashHandler_RestorePatient_Conflict(t *testing.T) {
+	mockService := mocks.NewMockTrashService()
+	router := setupTrashRouter(mockService, []string{domain.PermTrashManage})
+
+	conflictErr := &domain.RestoreConflictError{Conflicts: []domain.RestoreConflict{
+		{Field: "national_id", Value: "1234567890121", ExistingID: 9},
+	}}
+	mockService.On("RestorePatient", uint(1), mock.AnythingOfType("*domain.Actor"), testSchemaName).Return(nil, conflictErr)
+
+	req, _ := http.NewRequest("POST", "/trash/patients/restore/1", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusConflict, resp.Code)
+
+	var response struct {
+		Error string                   `json:"error"`
+		Data  []domain.RestoreConflict `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &response))
+	assert.Equal(t, "deleted patient conflicts with a live record", response.Error)
+	assert.Equal(t, uint(9), response.Data[0].ExistingID)
+}
+
+func TestTrashHandler_PurgeStaff(t *testing.T) {
+	mockService := mocks.NewMockTrashService()
+	router := setupTrashRouter(mockService, []string{domain.PermTrashManage})
+
+	mockService.On("PurgeStaff", testSchemaName).Return(&domain.PurgeResult{Purged: 2, DeletedBefore: time.Now()}, nil)
+
+	req, _ := http.NewRequest("DELETE", "/trash/staff/purge", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"purged":2`)
+	mockService.AssertExpectations(t)
+}
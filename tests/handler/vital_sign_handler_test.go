@@ -0,0 +1,196 @@
+package handler_test
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/delivery/http/handler"
+	"github.com/wichai2002/his_v1/internal/delivery/http/middleware"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/mocks"
+)
+
+// setupVitalSignRouter creates a test router with tenant context and the given permissions
+func setupVitalSignRouter(mockService *mocks.MockVitalSignService, permissions []string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.TenantSchemaKey, testSchemaName)
+		c.Set("permissions", permissions)
+		c.Next()
+	})
+
+	vitalHandler := handler.NewVitalSignHandler(mockService)
+
+	router.POST("/encounters/:id/vitals", middleware.RequirePermission(domain.PermVitalsWrite), vitalHandler.Record)
+	router.GET("/encounters/:id/vitals", middleware.RequirePermission(domain.PermPatientRead), vitalHandler.ListByEncounter)
+	router.GET("/patient/:id/vitals", middleware.RequirePermission(domain.PermPatientRead), vitalHandler.ListByPatient)
+	router.GET("/patient/:id/vitals/trend", middleware.RequirePermission(domain.PermPatientRead), vitalHandler.Trend)
+
+	return router
+}
+
+func TestVitalSignHandler_Record(t *testing.T) {
+	validBody := `{"systolic_bp":120,"diastolic_bp":80,"pulse":88,"temperature":37.2,"spo2":98,"triage_level":3}`
+
+	tests := []struct {
+		name           string
+		path           string
+		body           string
+		permissions    []string
+		setup          func(m *mocks.MockVitalSignService)
+		expectedStatus int
+	}{
+		{
+			name:        "recorded",
+			path:        "/encounters/12/vitals",
+			body:        validBody,
+			permissions: []string{domain.PermVitalsWrite},
+			setup: func(m *mocks.MockVitalSignService) {
+				m.On("Record", uint(12), mock.MatchedBy(func(r *domain.VitalSignRequest) bool {
+					return *r.SystolicBP == 120 && *r.Temperature == 37.2 && *r.TriageLevel == 3
+				}), mock.AnythingOfType("*domain.Actor"), testSchemaName).Return(&domain.VitalSign{EncounterID: 12}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "triage level out of range",
+			path:           "/encounters/12/vitals",
+			body:           `{"pulse":88,"triage_level":6}`,
+			permissions:    []string{domain.PermVitalsWrite},
+			setup:          func(m *mocks.MockVitalSignService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "implausible temperature",
+			path:           "/encounters/12/vitals",
+			body:           `{"temperature":98.6}`,
+			permissions:    []string{domain.PermVitalsWrite},
+			setup:          func(m *mocks.MockVitalSignService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid id",
+			path:           "/encounters/abc/vitals",
+			body:           validBody,
+			permissions:    []string{domain.PermVitalsWrite},
+			setup:          func(m *mocks.MockVitalSignService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "encounter not found",
+			path:        "/encounters/12/vitals",
+			body:        validBody,
+			permissions: []string{domain.PermVitalsWrite},
+			setup: func(m *mocks.MockVitalSignService) {
+				m.On("Record", uint(12), mock.Anything, mock.Anything, testSchemaName).Return(nil, domain.ErrNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:        "encounter done",
+			path:        "/encounters/12/vitals",
+			body:        validBody,
+			permissions: []string{domain.PermVitalsWrite},
+			setup: func(m *mocks.MockVitalSignService) {
+				m.On("Record", uint(12), mock.Anything, mock.Anything, testSchemaName).
+					Return(nil, fmt.Errorf("%w: encounter 69031500001 is done", domain.ErrInvalidInput))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "registration cannot record vitals",
+			path:           "/encounters/12/vitals",
+			body:           validBody,
+			permissions:    []string{domain.PermPatientRead, domain.PermEncounterWrite},
+			setup:          func(m *mocks.MockVitalSignService) {},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockVitalSignService()
+			tt.setup(mockService)
+			router := setupVitalSignRouter(mockService, tt.permissions)
+
+			req, _ := http.NewRequest("POST", tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tt.expectedStatus, resp.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestVitalSignHandler_Trend(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		setup          func(m *mocks.MockVitalSignService)
+		expectedStatus int
+	}{
+		{
+			name:  "last five readings",
+			query: "?limit=5",
+			setup: func(m *mocks.MockVitalSignService) {
+				m.On("Trend", uint(1), mock.MatchedBy(func(r *domain.VitalTrendRequest) bool { return r.Limit == 5 }), mock.Anything, testSchemaName).
+					Return([]domain.VitalTrend{{Measure: domain.VitalMeasurePulse, Unit: "/min"}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "limit above maximum",
+			query:          "?limit=500",
+			setup:          func(m *mocks.MockVitalSignService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "patient not found",
+			query: "",
+			setup: func(m *mocks.MockVitalSignService) {
+				m.On("Trend", uint(1), mock.Anything, mock.Anything, testSchemaName).Return(nil, domain.ErrNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockVitalSignService()
+			tt.setup(mockService)
+			router := setupVitalSignRouter(mockService, []string{domain.PermPatientRead})
+
+			req, _ := http.NewRequest("GET", "/patient/1/vitals/trend"+tt.query, nil)
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tt.expectedStatus, resp.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestVitalSignHandler_ListByPatient(t *testing.T) {
+	mockService := mocks.NewMockVitalSignService()
+	mockService.On("ListByPatient", uint(1), mock.MatchedBy(func(r *domain.VitalSignListRequest) bool {
+		return r.From == "2026-03-01" && r.To == ""
+	}), mock.Anything, testSchemaName).Return([]domain.VitalSign{{PatientID: 1}}, nil)
+	router := setupVitalSignRouter(mockService, []string{domain.PermPatientRead})
+
+	req, _ := http.NewRequest("GET", "/patient/1/vitals?from=2026-03-01", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	mockService.AssertExpectations(t)
+}
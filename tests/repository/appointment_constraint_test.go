@@ -0,0 +1,70 @@
+package repository_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wichai2002/his_v1/internal/repository"
+)
+
+// Overlapping slots of a doctor and overlapping booked appointments of a patient are rejected
+// by the exclusion constraints, even when they start at different times
+func TestAppointmentTables_RejectOverlaps(t *testing.T) {
+	db := openTestDB(t)
+	schemaName, dbManager, tenantService := newTestSchema(t, db)
+	patientRepo := repository.NewPatientRepository(db, dbManager)
+
+	// Migrating again shows the constraints are only added once
+	require.NoError(t, tenantService.MigrateTenantSchema(schemaName))
+
+	insertDoctor := fmt.Sprintf(`
+		INSERT INTO %s.staffs (username, password, staff_code, phone_number, email, first_name, last_name)
+		VALUES (?, 'x', ?, ?, ?, 'Overlap', 'Doctor')
+	`, schemaName)
+	firstDoctor := insertID(t, db, insertDoctor, "doctor01", "D001", "0820000001", "d1@example.com")
+	secondDoctor := insertID(t, db, insertDoctor, "doctor02", "D002", "0820000002", "d2@example.com")
+	departmentID := insertID(t, db, fmt.Sprintf(
+		"INSERT INTO %s.departments (code, name_th, name_en) VALUES ('OPD', 'ผู้ป่วยนอก', 'Outpatient')", schemaName))
+
+	insertTemplate := fmt.Sprintf(`
+		INSERT INTO %s.availability_templates (doctor_id, department_id, weekday, start_time, end_time, slot_minutes, valid_from)
+		VALUES (?, ?, 1, '09:00', '12:00', 30, CURRENT_DATE)
+	`, schemaName)
+	insertSlot := fmt.Sprintf(`
+		INSERT INTO %s.appointment_slots (template_id, doctor_id, department_id, start_at, end_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, schemaName)
+	start := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+
+	firstTemplate := insertID(t, db, insertTemplate, firstDoctor, departmentID)
+	secondTemplate := insertID(t, db, insertTemplate, secondDoctor, departmentID)
+	firstSlot := insertID(t, db, insertSlot, firstTemplate, firstDoctor, departmentID, start, start.Add(30*time.Minute))
+	secondSlot := insertID(t, db, insertSlot, secondTemplate, secondDoctor, departmentID, start.Add(15*time.Minute), start.Add(45*time.Minute))
+
+	err := db.Exec(insertSlot, firstTemplate, firstDoctor, departmentID, start.Add(15*time.Minute), start.Add(45*time.Minute)).Error
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "exclusion constraint")
+	}
+	// Back to back slots touch but do not overlap
+	assert.NoError(t, db.Exec(insertSlot, firstTemplate, firstDoctor, departmentID, start.Add(30*time.Minute), start.Add(time.Hour)).Error)
+
+	patient := createTestPatient(t, patientRepo, schemaName, "1100000000066")
+	insertAppointment := fmt.Sprintf(`
+		INSERT INTO %s.appointments (patient_id, slot_id, doctor_id, department_id, start_at, end_at, status, booked_by)
+		VALUES (?, ?, ?, ?, ?, ?, ?, 1)
+	`, schemaName)
+	require.NoError(t, db.Exec(insertAppointment, patient.ID, firstSlot, firstDoctor, departmentID,
+		start, start.Add(30*time.Minute), "booked").Error)
+
+	err = db.Exec(insertAppointment, patient.ID, secondSlot, secondDoctor, departmentID,
+		start.Add(15*time.Minute), start.Add(45*time.Minute), "booked").Error
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "exclusion constraint")
+	}
+	// A cancelled appointment no longer holds the patient's time
+	assert.NoError(t, db.Exec(insertAppointment, patient.ID, secondSlot, secondDoctor, departmentID,
+		start.Add(15*time.Minute), start.Add(45*time.Minute), "cancelled").Error)
+}
@@ -0,0 +1,59 @@
+package repository_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wichai2002/his_v1/config"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/infrastructure/database"
+	"github.com/wichai2002/his_v1/internal/repository"
+	"github.com/wichai2002/his_v1/internal/services"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openTestDB connects to the PostgreSQL database configured by the DB_* environment
+// variables and skips the test or benchmark when it is not reachable
+func openTestDB(tb testing.TB) *gorm.DB {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		tb.Skipf("no database config: %v", err)
+	}
+	dsn := fmt.Sprintf(
+		"host=%s user=%s password=%s dbname=%s port=%s sslmode=%s",
+		cfg.Database.Host, cfg.Database.User, cfg.Database.Password, cfg.Database.DBName, cfg.Database.Port, cfg.Database.SSLMode,
+	)
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		tb.Skipf("database not reachable: %v", err)
+	}
+	return db
+}
+
+// newTestSchema creates and migrates a throwaway tenant schema, dropped when the test ends
+func newTestSchema(tb testing.TB, db *gorm.DB) (string, *database.TenantDBManager, domain.TenantService) {
+	dbManager := database.NewTenantDBManager(db)
+	tenantService := services.NewTenantService(repository.NewTenantRepository(db), dbManager, db)
+
+	schemaName := fmt.Sprintf("test_%d", time.Now().UnixNano())
+	if err := tenantService.CreateTenantSchema(schemaName); err != nil {
+		tb.Fatalf("create schema: %v", err)
+	}
+	tb.Cleanup(func() { _ = dbManager.DropSchema(schemaName, true) })
+	if err := tenantService.MigrateTenantSchema(schemaName); err != nil {
+		tb.Fatalf("migrate schema: %v", err)
+	}
+	return schemaName, dbManager, tenantService
+}
+
+// insertID runs an INSERT ... RETURNING id and returns the new ID
+func insertID(t *testing.T, db *gorm.DB, query string, args ...interface{}) uint {
+	var id uint
+	require.NoError(t, db.Raw(query+" RETURNING id", args...).Scan(&id).Error)
+	require.NotZero(t, id)
+	return id
+}
@@ -0,0 +1,86 @@
+package repository_test
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/infrastructure/database"
+	"github.com/wichai2002/his_v1/internal/repository"
+	"github.com/wichai2002/his_v1/internal/services"
+)
+
+// BenchmarkPatientCreate_Concurrent registers patients from parallel goroutines in a
+// throwaway tenant schema and reports registrations per second. Each HN comes from the
+// hn_counters row of the current period, locked only until the registration commits.
+// Afterwards the counter must equal the number of patients: no number was skipped.
+//
+//	go test -run '^$' -bench PatientCreate ./tests/repository/
+func BenchmarkPatientCreate_Concurrent(b *testing.B) {
+	db := openTestDB(b)
+	dbManager := database.NewTenantDBManager(db)
+	tenantService := services.NewTenantService(repository.NewTenantRepository(db), dbManager, db)
+	patientRepo := repository.NewPatientRepository(db, dbManager)
+
+	templates := []string{domain.DefaultHNTemplate, "{BE_YY}-{SEQ:6}{CHECK}"}
+	for _, source := range templates {
+		for _, parallelism := range []int{1, 4, 16} {
+			b.Run(fmt.Sprintf("template=%s/parallelism=%d", source, parallelism), func(b *testing.B) {
+				schemaName := fmt.Sprintf("bench_hn_%d", time.Now().UnixNano())
+				if err := tenantService.CreateTenantSchema(schemaName); err != nil {
+					b.Fatalf("create schema: %v", err)
+				}
+				defer func() { _ = dbManager.DropSchema(schemaName, true) }()
+				if err := tenantService.MigrateTenantSchema(schemaName); err != nil {
+					b.Fatalf("migrate schema: %v", err)
+				}
+
+				issuer, err := domain.NewHNIssuer(&domain.Tenant{HospitalCode: "BNCH0001", HNTemplate: source})
+				if err != nil {
+					b.Fatalf("parse template: %v", err)
+				}
+				actor := &domain.Actor{StaffID: 1, Username: "bench"}
+
+				var failures atomic.Int64
+				b.SetParallelism(parallelism)
+				b.ResetTimer()
+				b.RunParallel(func(pb *testing.PB) {
+					for pb.Next() {
+						patient := &domain.Patient{
+							FirstNameEN: "Bench",
+							LastNameEN:  "Patient",
+							FirstNameTH: "ทดสอบ",
+							LastNameTH:  "ผู้ป่วย",
+							DateOfBirth: time.Date(1990, time.January, 15, 0, 0, 0, 0, time.UTC),
+							Gender:      domain.Male,
+							Nationality: "Thai",
+							BloodGrp:    domain.BloodGrp("A"),
+						}
+						event, err := domain.NewAuditEvent(actor, domain.AuditActionPatientCreate, nil, nil)
+						if err == nil {
+							err = patientRepo.Create(patient, issuer, []*domain.AuditEvent{event}, schemaName)
+						}
+						if err != nil {
+							failures.Add(1)
+						}
+					}
+				})
+				b.StopTimer()
+				b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "registrations/s")
+
+				if failures.Load() > 0 {
+					b.Fatalf("%d registrations failed", failures.Load())
+				}
+				var counter domain.HNCounter
+				if err := db.Table(schemaName + ".hn_counters").First(&counter).Error; err != nil {
+					b.Fatalf("read counter: %v", err)
+				}
+				if counter.LastValue != uint64(b.N) {
+					b.Fatalf("counter at %d after %d registrations", counter.LastValue, b.N)
+				}
+			})
+		}
+	}
+}
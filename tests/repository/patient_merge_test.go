@@ -0,0 +1,73 @@
+package repository_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/repository"
+)
+
+func TestPatientRepository_MergeChainHistory(t *testing.T) {
+	db := openTestDB(t)
+	schemaName, dbManager, _ := newTestSchema(t, db)
+	patientRepo := repository.NewPatientRepository(db, dbManager)
+	allergyRepo := repository.NewPatientAllergyRepository(db, dbManager)
+
+	survivor := createTestPatient(t, patientRepo, schemaName, "1100000000033")
+	retired := createTestPatient(t, patientRepo, schemaName, "1100000000044")
+
+	require.NoError(t, db.Exec(fmt.Sprintf(`
+		INSERT INTO %s.patient_allergies (patient_id, allergen_type, allergen, severity, certainty, recorded_by)
+		VALUES (?, 'drug', 'Penicillin', 'severe', 'confirmed', 1)
+	`, schemaName), retired.ID).Error)
+
+	mergeEvents := func(action string) []*domain.AuditEvent {
+		events := make([]*domain.AuditEvent, 0, 2)
+		for _, id := range []uint{survivor.ID, retired.ID} {
+			event, err := domain.NewAuditEvent(testRepoActor, action, &id, nil)
+			require.NoError(t, err)
+			events = append(events, event)
+		}
+		return events
+	}
+
+	merge := &domain.PatientMerge{
+		SurvivorID:       survivor.ID,
+		SurvivorHN:       survivor.PatientHN,
+		RetiredID:        retired.ID,
+		RetiredHN:        retired.PatientHN,
+		Reason:           "duplicate registration",
+		MergedBy:         testRepoActor.StaffID,
+		MergedByUsername: testRepoActor.Username,
+	}
+	require.NoError(t, patientRepo.Merge(merge, mergeEvents(domain.AuditActionPatientMerge), schemaName))
+
+	allergies, err := allergyRepo.ListByPatient(survivor.ID, schemaName)
+	require.NoError(t, err)
+	require.Len(t, allergies, 1)
+	assert.Equal(t, "Penicillin", allergies[0].Allergen)
+
+	patient, err := patientRepo.SearchByID(survivor.ID, schemaName)
+	require.NoError(t, err)
+	require.NotNil(t, patient.AllergyBanner)
+	assert.Equal(t, 1, patient.AllergyBanner.Count)
+	assert.True(t, patient.AllergyBanner.HasDrugAllergy)
+
+	unmergedAt := time.Now()
+	merge.UnmergedAt = &unmergedAt
+	merge.UnmergedBy = &testRepoActor.StaffID
+	merge.UnmergedByUsername = testRepoActor.Username
+	require.NoError(t, patientRepo.Unmerge(merge, mergeEvents(domain.AuditActionPatientUnmerge), schemaName))
+
+	allergies, err = allergyRepo.ListByPatient(survivor.ID, schemaName)
+	require.NoError(t, err)
+	assert.Empty(t, allergies)
+
+	allergies, err = allergyRepo.ListByPatient(retired.ID, schemaName)
+	require.NoError(t, err)
+	assert.Len(t, allergies, 1)
+}
@@ -0,0 +1,92 @@
+package repository_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/repository"
+	"gorm.io/gorm"
+)
+
+var testRepoActor = &domain.Actor{StaffID: 1, Username: "tester"}
+
+// createTestPatient registers a patient with the given national ID
+func createTestPatient(t *testing.T, patientRepo domain.PatientRepository, schemaName string, nationalID string) *domain.Patient {
+	issuer, err := domain.NewHNIssuer(&domain.Tenant{HospitalCode: "TEST0001", HNTemplate: domain.DefaultHNTemplate})
+	require.NoError(t, err)
+	event, err := domain.NewAuditEvent(testRepoActor, domain.AuditActionPatientCreate, nil, nil)
+	require.NoError(t, err)
+
+	patient := &domain.Patient{
+		FirstNameEN: "Purge",
+		LastNameEN:  "Patient",
+		FirstNameTH: "ทดสอบ",
+		LastNameTH:  "ผู้ป่วย",
+		DateOfBirth: time.Date(1990, time.January, 15, 0, 0, 0, 0, time.UTC),
+		Gender:      domain.Male,
+		Nationality: "Thai",
+		BloodGrp:    domain.BloodGrp("A"),
+		NationalID:  domain.NullableString(nationalID),
+		PhoneNumber: "08" + nationalID[5:],
+	}
+	require.NoError(t, patientRepo.Create(patient, issuer, []*domain.AuditEvent{event}, schemaName))
+	return patient
+}
+
+// createDeletedPatient registers a patient and moves it to the trash a year ago
+func createDeletedPatient(t *testing.T, db *gorm.DB, patientRepo domain.PatientRepository, schemaName string, nationalID string) uint {
+	patient := createTestPatient(t, patientRepo, schemaName, nationalID)
+	require.NoError(t, db.Exec(fmt.Sprintf("UPDATE %s.patients SET deleted_at = NOW() - INTERVAL '1 year' WHERE id = ?", schemaName), patient.ID).Error)
+	return patient.ID
+}
+
+func TestPatientRepository_Purge(t *testing.T) {
+	db := openTestDB(t)
+	schemaName, dbManager, _ := newTestSchema(t, db)
+	patientRepo := repository.NewPatientRepository(db, dbManager)
+
+	withContact := createDeletedPatient(t, db, patientRepo, schemaName, "1100000000011")
+	withAllergy := createDeletedPatient(t, db, patientRepo, schemaName, "1100000000022")
+
+	require.NoError(t, db.Exec(fmt.Sprintf(`
+		INSERT INTO %s.patient_contacts (patient_id, name, relationship, phone_number, is_primary)
+		VALUES (?, 'Somsri', 'spouse', '0899999999', TRUE)
+	`, schemaName), withContact).Error)
+	require.NoError(t, db.Exec(fmt.Sprintf(`
+		INSERT INTO %s.patient_allergies (patient_id, allergen_type, allergen, severity, certainty, recorded_by)
+		VALUES (?, 'drug', 'Penicillin', 'severe', 'confirmed', 1)
+	`, schemaName), withAllergy).Error)
+
+	ids := []uint{withContact, withAllergy}
+	events := make([]*domain.AuditEvent, 0, len(ids))
+	for i := range ids {
+		event, err := domain.NewAuditEvent(testRepoActor, domain.AuditActionPatientPurge, &ids[i], nil)
+		require.NoError(t, err)
+		events = append(events, event)
+	}
+
+	purged, retained, err := patientRepo.Purge(ids, time.Now(), events, schemaName)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, purged)
+	assert.Equal(t, []domain.PurgeRetention{{ID: withAllergy, Reason: domain.PurgeRetainedHistory}}, retained)
+
+	var remaining struct {
+		Patients int64
+		Contacts int64
+		Purges   int64
+	}
+	require.NoError(t, db.Raw(fmt.Sprintf(`
+		SELECT
+			(SELECT COUNT(*) FROM %[1]s.patients WHERE id = ?) AS patients,
+			(SELECT COUNT(*) FROM %[1]s.patient_contacts WHERE patient_id = ?) AS contacts,
+			(SELECT COUNT(*) FROM %[1]s.audit_events WHERE action = ?) AS purges
+	`, schemaName), withContact, withContact, domain.AuditActionPatientPurge).Scan(&remaining).Error)
+	assert.Zero(t, remaining.Patients)
+	assert.Zero(t, remaining.Contacts)
+	assert.Equal(t, int64(1), remaining.Purges)
+}
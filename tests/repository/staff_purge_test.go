@@ -0,0 +1,71 @@
+package repository_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/repository"
+)
+
+// A doctor who published availability can be purged, taking the templates and unbooked slots
+// along; a doctor with appointments stays in the trash
+func TestStaffRepository_Purge(t *testing.T) {
+	db := openTestDB(t)
+	schemaName, dbManager, _ := newTestSchema(t, db)
+	staffRepo := repository.NewStaffRepository(db, dbManager)
+	patientRepo := repository.NewPatientRepository(db, dbManager)
+
+	insertDoctor := fmt.Sprintf(`
+		INSERT INTO %s.staffs (username, password, staff_code, phone_number, email, first_name, last_name, deleted_at)
+		VALUES (?, 'x', ?, ?, ?, 'Purge', 'Doctor', NOW() - INTERVAL '1 year')
+	`, schemaName)
+	withSlots := insertID(t, db, insertDoctor, "doctor01", "D001", "0820000001", "d1@example.com")
+	withAppointment := insertID(t, db, insertDoctor, "doctor02", "D002", "0820000002", "d2@example.com")
+
+	departmentID := insertID(t, db, fmt.Sprintf(
+		"INSERT INTO %s.departments (code, name_th, name_en) VALUES ('OPD', 'ผู้ป่วยนอก', 'Outpatient')", schemaName))
+
+	slotStart := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+	slots := make(map[uint]uint, 2)
+	for _, doctorID := range []uint{withSlots, withAppointment} {
+		templateID := insertID(t, db, fmt.Sprintf(`
+			INSERT INTO %s.availability_templates (doctor_id, department_id, weekday, start_time, end_time, slot_minutes, valid_from)
+			VALUES (?, ?, 1, '09:00', '12:00', 15, CURRENT_DATE)
+		`, schemaName), doctorID, departmentID)
+		slots[doctorID] = insertID(t, db, fmt.Sprintf(`
+			INSERT INTO %s.appointment_slots (template_id, doctor_id, department_id, start_at, end_at)
+			VALUES (?, ?, ?, ?, ?)
+		`, schemaName), templateID, doctorID, departmentID, slotStart, slotStart.Add(15*time.Minute))
+	}
+
+	patient := createTestPatient(t, patientRepo, schemaName, "1100000000055")
+	require.NoError(t, db.Exec(fmt.Sprintf(`
+		INSERT INTO %s.appointments (patient_id, slot_id, doctor_id, department_id, start_at, end_at, status, booked_by)
+		VALUES (?, ?, ?, ?, ?, ?, 'booked', 1)
+	`, schemaName), patient.ID, slots[withAppointment], withAppointment, departmentID, slotStart, slotStart.Add(15*time.Minute)).Error)
+
+	purged, retained, err := staffRepo.Purge([]uint{withSlots, withAppointment}, time.Now(), schemaName)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, purged)
+	assert.Equal(t, []domain.PurgeRetention{{ID: withAppointment, Reason: domain.PurgeRetainedAppointments}}, retained)
+
+	var remaining struct {
+		Staff     int64
+		Templates int64
+		Slots     int64
+	}
+	require.NoError(t, db.Raw(fmt.Sprintf(`
+		SELECT
+			(SELECT COUNT(*) FROM %[1]s.staffs WHERE id = ?) AS staff,
+			(SELECT COUNT(*) FROM %[1]s.availability_templates WHERE doctor_id = ?) AS templates,
+			(SELECT COUNT(*) FROM %[1]s.appointment_slots WHERE doctor_id = ?) AS slots
+	`, schemaName), withSlots, withSlots, withSlots).Scan(&remaining).Error)
+	assert.Zero(t, remaining.Staff)
+	assert.Zero(t, remaining.Templates)
+	assert.Zero(t, remaining.Slots)
+}
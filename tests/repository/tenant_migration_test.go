@@ -0,0 +1,44 @@
+package repository_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wichai2002/his_v1/internal/domain"
+)
+
+// Tenants created before roles flagged admins with staffs.is_admin; migrating them must grant
+// those staff the admin role, or nobody is left holding role:manage
+func TestMigrateTenantSchema_BackfillsAdminRole(t *testing.T) {
+	db := openTestDB(t)
+	schemaName, _, tenantService := newTestSchema(t, db)
+
+	require.NoError(t, db.Exec(fmt.Sprintf("ALTER TABLE %s.staffs ADD COLUMN is_admin BOOLEAN DEFAULT FALSE", schemaName)).Error)
+	insertStaff := fmt.Sprintf(`
+		INSERT INTO %s.staffs (username, password, staff_code, phone_number, email, first_name, last_name, is_admin)
+		VALUES (?, 'x', ?, ?, ?, 'Legacy', 'Staff', ?)
+	`, schemaName)
+	require.NoError(t, db.Exec(insertStaff, "legacyadmin", "S001", "0810000001", "admin@example.com", true).Error)
+	require.NoError(t, db.Exec(insertStaff, "legacynurse", "S002", "0810000002", "nurse@example.com", false).Error)
+
+	// Migrating twice shows the backfill is idempotent
+	require.NoError(t, tenantService.MigrateTenantSchema(schemaName))
+	require.NoError(t, tenantService.MigrateTenantSchema(schemaName))
+
+	var grants []struct {
+		Username string
+		Code     string
+	}
+	require.NoError(t, db.Raw(fmt.Sprintf(`
+		SELECT s.username, r.code FROM %s.staff_roles sr
+		JOIN %s.staffs s ON s.id = sr.staff_id
+		JOIN %s.roles r ON r.id = sr.role_id
+	`, schemaName, schemaName, schemaName)).Scan(&grants).Error)
+
+	if assert.Len(t, grants, 1) {
+		assert.Equal(t, "legacyadmin", grants[0].Username)
+		assert.Equal(t, domain.RoleAdmin, grants[0].Code)
+	}
+}
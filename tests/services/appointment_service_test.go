@@ -0,0 +1,217 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/mocks"
+	"github.com/wichai2002/his_v1/internal/services"
+	"gorm.io/gorm"
+)
+
+type appointmentMocks struct {
+	appointmentRepo *mocks.MockAppointmentRepository
+	patientRepo     *mocks.MockPatientRepository
+	auditRepo       *mocks.MockAuditRepository
+}
+
+func newAppointmentService() (domain.AppointmentService, *appointmentMocks) {
+	m := &appointmentMocks{
+		appointmentRepo: mocks.NewMockAppointmentRepository(),
+		patientRepo:     mocks.NewMockPatientRepository(),
+		auditRepo:       mocks.NewMockAuditRepository(),
+	}
+	return services.NewAppointmentService(m.appointmentRepo, m.patientRepo, m.auditRepo), m
+}
+
+func futureSlot(id uint) *domain.AppointmentSlot {
+	start := time.Now().Add(48 * time.Hour).Truncate(time.Hour)
+	return &domain.AppointmentSlot{ID: id, TemplateID: 7, DoctorID: 3, DepartmentID: 2, StartAt: start, EndAt: start.Add(15 * time.Minute)}
+}
+
+func TestAppointmentService_Book(t *testing.T) {
+	service, m := newAppointmentService()
+
+	slot := futureSlot(11)
+	m.patientRepo.On("GetByID", uint(1), "tenant_test").Return(&domain.Patient{}, nil)
+	m.appointmentRepo.On("GetSlot", uint(11), "tenant_test").Return(slot, nil)
+	m.appointmentRepo.On("Create", mock.MatchedBy(func(a *domain.Appointment) bool {
+		return a.PatientID == 1 && a.SlotID == 11 && a.DoctorID == 3 && a.DepartmentID == 2 &&
+			a.StartAt.Equal(slot.StartAt) && a.Status == domain.AppointmentStatusBooked && a.BookedBy == testActor.StaffID
+	}), mock.MatchedBy(func(e *domain.AuditEvent) bool {
+		return e.Action == domain.AuditActionPatientAppointmentBook && *e.PatientID == 1
+	}), "tenant_test").Return(nil)
+
+	appointment, err := service.Book(&domain.AppointmentBookRequest{PatientID: 1, SlotID: 11, Reason: " follow-up "}, testActor, "tenant_test")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "follow-up", appointment.Reason)
+	m.appointmentRepo.AssertExpectations(t)
+}
+
+func TestAppointmentService_Book_Errors(t *testing.T) {
+	started := futureSlot(11)
+	started.StartAt = time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name          string
+		slot          *domain.AppointmentSlot
+		slotErr       error
+		createErr     error
+		expectedError error
+	}{
+		{name: "slot does not exist", slotErr: gorm.ErrRecordNotFound, expectedError: domain.ErrInvalidInput},
+		{name: "slot has started", slot: started, expectedError: domain.ErrInvalidInput},
+		{
+			name:          "slot already booked",
+			slot:          futureSlot(11),
+			createErr:     errors.New("duplicate key value violates unique constraint"),
+			expectedError: domain.ErrDuplicateEntry,
+		},
+		{
+			name:          "patient booked at an overlapping time",
+			slot:          futureSlot(11),
+			createErr:     errors.New("conflicting key value violates exclusion constraint"),
+			expectedError: domain.ErrDuplicateEntry,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, m := newAppointmentService()
+
+			m.patientRepo.On("GetByID", uint(1), "tenant_test").Return(&domain.Patient{}, nil)
+			if tt.slotErr != nil {
+				m.appointmentRepo.On("GetSlot", uint(11), "tenant_test").Return(nil, tt.slotErr)
+			} else {
+				m.appointmentRepo.On("GetSlot", uint(11), "tenant_test").Return(tt.slot, nil)
+			}
+			m.appointmentRepo.On("Create", mock.Anything, mock.Anything, "tenant_test").Return(tt.createErr)
+
+			_, err := service.Book(&domain.AppointmentBookRequest{PatientID: 1, SlotID: 11}, testActor, "tenant_test")
+
+			assert.ErrorIs(t, err, tt.expectedError)
+		})
+	}
+}
+
+func TestAppointmentService_Reschedule(t *testing.T) {
+	service, m := newAppointmentService()
+
+	previous := &domain.Appointment{Model: gorm.Model{ID: 20}, PatientID: 1, SlotID: 11, Status: domain.AppointmentStatusBooked, Reason: "follow-up"}
+	m.appointmentRepo.On("GetByID", uint(20), "tenant_test").Return(previous, nil)
+	m.appointmentRepo.On("GetSlot", uint(12), "tenant_test").Return(futureSlot(12), nil)
+	m.appointmentRepo.On("Reschedule", previous, mock.MatchedBy(func(a *domain.Appointment) bool {
+		return a.SlotID == 12 && a.PatientID == 1 && a.Reason == "follow-up" &&
+			a.RescheduledFromID != nil && *a.RescheduledFromID == 20 && a.Status == domain.AppointmentStatusBooked
+	}), mock.MatchedBy(func(e *domain.AuditEvent) bool {
+		return e.Action == domain.AuditActionPatientAppointmentReschedule
+	}), "tenant_test").Return(nil)
+
+	appointment, err := service.Reschedule(20, &domain.AppointmentRescheduleRequest{SlotID: 12}, testActor, "tenant_test")
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(12), appointment.SlotID)
+	m.appointmentRepo.AssertExpectations(t)
+}
+
+func TestAppointmentService_Reschedule_Errors(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        string
+		slotID        uint
+		expectedError error
+	}{
+		{name: "cancelled appointment", status: domain.AppointmentStatusCancelled, slotID: 12, expectedError: domain.ErrInvalidStatusTransition},
+		{name: "already rescheduled", status: domain.AppointmentStatusRescheduled, slotID: 12, expectedError: domain.ErrInvalidStatusTransition},
+		{name: "same slot", status: domain.AppointmentStatusBooked, slotID: 11, expectedError: domain.ErrInvalidInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, m := newAppointmentService()
+
+			m.appointmentRepo.On("GetByID", uint(20), "tenant_test").
+				Return(&domain.Appointment{Model: gorm.Model{ID: 20}, SlotID: 11, Status: tt.status}, nil)
+
+			_, err := service.Reschedule(20, &domain.AppointmentRescheduleRequest{SlotID: tt.slotID}, testActor, "tenant_test")
+
+			assert.ErrorIs(t, err, tt.expectedError)
+			m.appointmentRepo.AssertNotCalled(t, "Reschedule", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestAppointmentService_Cancel(t *testing.T) {
+	service, m := newAppointmentService()
+
+	m.appointmentRepo.On("GetByID", uint(20), "tenant_test").
+		Return(&domain.Appointment{Model: gorm.Model{ID: 20}, PatientID: 1, Status: domain.AppointmentStatusBooked}, nil)
+	m.appointmentRepo.On("Cancel", mock.MatchedBy(func(a *domain.Appointment) bool {
+		return a.Status == domain.AppointmentStatusCancelled && a.CancelledAt != nil && a.CancelReason == "patient request"
+	}), mock.MatchedBy(func(e *domain.AuditEvent) bool {
+		return e.Action == domain.AuditActionPatientAppointmentCancel && *e.PatientID == 1
+	}), "tenant_test").Return(nil)
+
+	appointment, err := service.Cancel(20, &domain.AppointmentCancelRequest{Reason: "patient request "}, testActor, "tenant_test")
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.AppointmentStatusCancelled, appointment.Status)
+	m.appointmentRepo.AssertExpectations(t)
+}
+
+func TestAppointmentService_Calendar_Week(t *testing.T) {
+	service, m := newAppointmentService()
+
+	appointmentID, patientID := uint(20), uint(1)
+	m.appointmentRepo.On("Calendar", mock.MatchedBy(func(f *domain.AppointmentCalendarFilter) bool {
+		// 2026-03-18 is a Wednesday; the week runs Monday 16 to Sunday 22
+		return f.DoctorID == 3 && f.From.Equal(time.Date(2026, 3, 16, 0, 0, 0, 0, domain.HNTimeZone)) &&
+			f.To.Equal(time.Date(2026, 3, 23, 0, 0, 0, 0, domain.HNTimeZone))
+	}), "tenant_test").Return([]domain.CalendarEntry{
+		{SlotID: 11, AppointmentID: &appointmentID, PatientID: &patientID},
+		{SlotID: 12},
+		{SlotID: 13, AppointmentID: &appointmentID, PatientID: &patientID},
+	}, nil)
+	m.auditRepo.On("Append", mock.MatchedBy(func(events []*domain.AuditEvent) bool {
+		return len(events) == 1 && events[0].Action == domain.AuditActionPatientAppointmentView
+	}), "tenant_test").Return(nil)
+
+	calendar, err := service.Calendar(&domain.AppointmentCalendarRequest{DoctorID: 3, Date: "2026-03-18", View: "week"}, testActor, "tenant_test")
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.CalendarViewWeek, calendar.View)
+	assert.Equal(t, "2026-03-16", calendar.From)
+	assert.Equal(t, "2026-03-22", calendar.To)
+	assert.False(t, calendar.Entries[0].Available)
+	assert.True(t, calendar.Entries[1].Available)
+	m.auditRepo.AssertExpectations(t)
+}
+
+func TestAppointmentService_Calendar_Day(t *testing.T) {
+	service, m := newAppointmentService()
+
+	m.appointmentRepo.On("Calendar", mock.MatchedBy(func(f *domain.AppointmentCalendarFilter) bool {
+		return f.DepartmentID == 2 && f.To.Sub(f.From) == 24*time.Hour
+	}), "tenant_test").Return([]domain.CalendarEntry{{SlotID: 12}}, nil)
+
+	calendar, err := service.Calendar(&domain.AppointmentCalendarRequest{DepartmentID: 2, Date: "2026-03-22"}, testActor, "tenant_test")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "2026-03-22", calendar.From)
+	assert.Equal(t, "2026-03-22", calendar.To)
+	m.auditRepo.AssertNotCalled(t, "Append", mock.Anything, mock.Anything)
+}
+
+func TestAppointmentService_Calendar_RequiresDoctorOrDepartment(t *testing.T) {
+	service, m := newAppointmentService()
+
+	_, err := service.Calendar(&domain.AppointmentCalendarRequest{Date: "2026-03-18"}, testActor, "tenant_test")
+
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	m.appointmentRepo.AssertNotCalled(t, "Calendar", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,155 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/mocks"
+	"github.com/wichai2002/his_v1/internal/services"
+)
+
+// buildAuditChain returns n correctly chained audit events
+func buildAuditChain(n int) []domain.AuditEvent {
+	events := make([]domain.AuditEvent, n)
+	prevHash := ""
+	for i := range events {
+		patientID := uint(i + 1)
+		events[i] = domain.AuditEvent{
+			ID:        uint(i + 1),
+			CreatedAt: time.Date(2024, 1, 1, 10, i, 0, 0, time.UTC),
+			ActorID:   1,
+			Action:    domain.AuditActionPatientView,
+			PatientID: &patientID,
+			PrevHash:  prevHash,
+		}
+		events[i].Hash = events[i].ComputeHash()
+		prevHash = events[i].Hash
+	}
+	return events
+}
+
+func TestAuditService_Verify(t *testing.T) {
+	tampered := buildAuditChain(3)
+	tampered[1].ActorID = 99
+
+	deleted := buildAuditChain(3)
+	deleted = append(deleted[:1], deleted[2])
+
+	tests := []struct {
+		name          string
+		chain         []domain.AuditEvent
+		expectValid   bool
+		expectBroken  uint
+		expectChecked int64
+	}{
+		{
+			name:          "intact chain",
+			chain:         buildAuditChain(3),
+			expectValid:   true,
+			expectChecked: 3,
+		},
+		{
+			name:          "empty trail is valid",
+			chain:         []domain.AuditEvent{},
+			expectValid:   true,
+			expectChecked: 0,
+		},
+		{
+			name:          "modified event is detected",
+			chain:         tampered,
+			expectValid:   false,
+			expectBroken:  2,
+			expectChecked: 1,
+		},
+		{
+			name:          "deleted event is detected",
+			chain:         deleted,
+			expectValid:   false,
+			expectBroken:  3,
+			expectChecked: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockAuditRepository()
+			mockRepo.On("GetChain", uint(0), 1000, "tenant_test").Return(tt.chain, nil)
+
+			service := services.NewAuditService(mockRepo)
+			result, err := service.Verify("tenant_test")
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectValid, result.Valid)
+			assert.Equal(t, tt.expectChecked, result.CheckedEvents)
+			if tt.expectValid {
+				assert.Nil(t, result.BrokenAtID)
+			} else {
+				assert.Equal(t, tt.expectBroken, *result.BrokenAtID)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuditService_Verify_RepositoryError(t *testing.T) {
+	mockRepo := mocks.NewMockAuditRepository()
+	mockRepo.On("GetChain", uint(0), 1000, "tenant_test").Return(nil, errors.New("database error"))
+
+	service := services.NewAuditService(mockRepo)
+	result, err := service.Verify("tenant_test")
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+}
+
+func TestAuditService_Query(t *testing.T) {
+	patientID := uint(1)
+
+	tests := []struct {
+		name        string
+		filter      *domain.AuditFilter
+		callRepo    bool
+		expectError error
+	}{
+		{
+			name:     "query by patient",
+			filter:   &domain.AuditFilter{PatientID: &patientID, Limit: 50},
+			callRepo: true,
+		},
+		{
+			name:        "limit above maximum is rejected",
+			filter:      &domain.AuditFilter{Limit: 1000},
+			expectError: domain.ErrInvalidInput,
+		},
+		{
+			name:        "negative offset is rejected",
+			filter:      &domain.AuditFilter{Offset: -1},
+			expectError: domain.ErrInvalidInput,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockAuditRepository()
+			if tt.callRepo {
+				mockRepo.On("Query", tt.filter, "tenant_test").Return(buildAuditChain(2), int64(2), nil)
+			}
+
+			service := services.NewAuditService(mockRepo)
+			events, total, err := service.Query(tt.filter, "tenant_test")
+
+			if tt.expectError != nil {
+				assert.ErrorIs(t, err, tt.expectError)
+				assert.Nil(t, events)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, events, 2)
+				assert.Equal(t, int64(2), total)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/mocks"
+	"github.com/wichai2002/his_v1/internal/services"
+	"gorm.io/gorm"
+)
+
+type availabilityMocks struct {
+	templateRepo   *mocks.MockAvailabilityTemplateRepository
+	staffRepo      *mocks.MockStaffRepository
+	departmentRepo *mocks.MockDepartmentRepository
+}
+
+func newAvailabilityService() (domain.AvailabilityService, *availabilityMocks) {
+	m := &availabilityMocks{
+		templateRepo:   mocks.NewMockAvailabilityTemplateRepository(),
+		staffRepo:      mocks.NewMockStaffRepository(),
+		departmentRepo: mocks.NewMockDepartmentRepository(),
+	}
+	return services.NewAvailabilityService(m.templateRepo, m.staffRepo, m.departmentRepo), m
+}
+
+func newTestAvailabilityRequest() *domain.AvailabilityTemplateRequest {
+	weekday := int(time.Now().In(domain.HNTimeZone).AddDate(0, 0, 1).Weekday())
+	return &domain.AvailabilityTemplateRequest{
+		DoctorID:     3,
+		DepartmentID: 2,
+		Weekday:      &weekday,
+		StartTime:    "09:00",
+		EndTime:      "10:00",
+		SlotMinutes:  15,
+		ValidFrom:    time.Now().In(domain.HNTimeZone).Format(domain.DateFormat),
+	}
+}
+
+func testDoctor() *domain.Staff {
+	return &domain.Staff{Model: gorm.Model{ID: 3}, StaffCode: "D001", Roles: []domain.Role{{Code: domain.RoleDoctor}}}
+}
+
+func TestAvailabilityService_Create(t *testing.T) {
+	service, m := newAvailabilityService()
+
+	m.staffRepo.On("GetByID", uint(3), "tenant_test").Return(testDoctor(), nil)
+	m.departmentRepo.On("GetByID", uint(2), "tenant_test").Return(&domain.Department{Model: gorm.Model{ID: 2}, IsActive: true}, nil)
+	m.templateRepo.On("List", uint(3), "tenant_test").Return([]domain.AvailabilityTemplate{}, nil)
+	m.templateRepo.On("Create", mock.MatchedBy(func(t *domain.AvailabilityTemplate) bool {
+		return t.DoctorID == 3 && t.StartTime == "09:00" && t.SlotMinutes == 15
+	}), mock.MatchedBy(func(slots []domain.AppointmentSlot) bool {
+		// Four weekly sessions of four slots fall within the horizon
+		horizon := time.Now().AddDate(0, 0, domain.DefaultSlotHorizonDays+1)
+		for _, slot := range slots {
+			if !slot.StartAt.After(time.Now()) || slot.StartAt.After(horizon) {
+				return false
+			}
+		}
+		return len(slots) >= 16
+	}), "tenant_test").Return(nil)
+
+	template, err := service.Create(newTestAvailabilityRequest(), "tenant_test")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "10:00", template.EndTime)
+	m.templateRepo.AssertExpectations(t)
+}
+
+func TestAvailabilityService_Create_Errors(t *testing.T) {
+	tests := []struct {
+		name          string
+		modify        func(req *domain.AvailabilityTemplateRequest)
+		staff         *domain.Staff
+		staffErr      error
+		department    *domain.Department
+		existing      []domain.AvailabilityTemplate
+		expectedError error
+	}{
+		{
+			name:          "session shorter than a slot",
+			modify:        func(req *domain.AvailabilityTemplateRequest) { req.EndTime = "09:10" },
+			expectedError: domain.ErrInvalidInput,
+		},
+		{
+			name:          "valid_to before valid_from",
+			modify:        func(req *domain.AvailabilityTemplateRequest) { req.ValidTo = "2020-01-01" },
+			expectedError: domain.ErrInvalidInput,
+		},
+		{
+			name:          "staff does not exist",
+			modify:        func(req *domain.AvailabilityTemplateRequest) {},
+			staffErr:      gorm.ErrRecordNotFound,
+			expectedError: domain.ErrInvalidInput,
+		},
+		{
+			name:          "staff is not a doctor",
+			modify:        func(req *domain.AvailabilityTemplateRequest) {},
+			staff:         &domain.Staff{Model: gorm.Model{ID: 3}, Roles: []domain.Role{{Code: domain.RoleNurse}}},
+			expectedError: domain.ErrInvalidInput,
+		},
+		{
+			name:          "inactive department",
+			modify:        func(req *domain.AvailabilityTemplateRequest) {},
+			staff:         testDoctor(),
+			department:    &domain.Department{Model: gorm.Model{ID: 2}, Code: "DENT"},
+			expectedError: domain.ErrInvalidInput,
+		},
+		{
+			name:       "overlaps another session of the doctor",
+			modify:     func(req *domain.AvailabilityTemplateRequest) {},
+			staff:      testDoctor(),
+			department: &domain.Department{Model: gorm.Model{ID: 2}, IsActive: true},
+			existing: []domain.AvailabilityTemplate{{
+				Model: gorm.Model{ID: 5}, DoctorID: 3, Weekday: int(time.Now().In(domain.HNTimeZone).AddDate(0, 0, 1).Weekday()),
+				StartTime: "09:45", EndTime: "12:00", ValidFrom: testDate("2020-01-01"),
+			}},
+			expectedError: domain.ErrDuplicateEntry,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, m := newAvailabilityService()
+
+			if tt.staffErr != nil {
+				m.staffRepo.On("GetByID", uint(3), "tenant_test").Return(nil, tt.staffErr)
+			} else {
+				m.staffRepo.On("GetByID", uint(3), "tenant_test").Return(tt.staff, nil)
+			}
+			m.departmentRepo.On("GetByID", uint(2), "tenant_test").Return(tt.department, nil)
+			m.templateRepo.On("List", uint(3), "tenant_test").Return(tt.existing, nil)
+
+			req := newTestAvailabilityRequest()
+			tt.modify(req)
+			_, err := service.Create(req, "tenant_test")
+
+			assert.ErrorIs(t, err, tt.expectedError)
+			m.templateRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+
+	t.Run("back-to-back sessions do not overlap", func(t *testing.T) {
+		service, m := newAvailabilityService()
+
+		req := newTestAvailabilityRequest()
+		m.staffRepo.On("GetByID", uint(3), "tenant_test").Return(testDoctor(), nil)
+		m.departmentRepo.On("GetByID", uint(2), "tenant_test").Return(&domain.Department{Model: gorm.Model{ID: 2}, IsActive: true}, nil)
+		m.templateRepo.On("List", uint(3), "tenant_test").Return([]domain.AvailabilityTemplate{{
+			Model: gorm.Model{ID: 5}, DoctorID: 3, Weekday: *req.Weekday, StartTime: "10:00", EndTime: "12:00", ValidFrom: testDate("2020-01-01"),
+		}}, nil)
+		m.templateRepo.On("Create", mock.Anything, mock.Anything, "tenant_test").Return(nil)
+
+		_, err := service.Create(req, "tenant_test")
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestAvailabilityService_Expand(t *testing.T) {
+	service, m := newAvailabilityService()
+
+	template := &domain.AvailabilityTemplate{
+		Model: gorm.Model{ID: 7}, DoctorID: 3, Weekday: int(time.Monday),
+		StartTime: "09:00", EndTime: "10:00", SlotMinutes: 30, ValidFrom: testDate("2020-01-01"),
+	}
+	m.templateRepo.On("GetByID", uint(7), "tenant_test").Return(template, nil)
+	m.templateRepo.On("AddSlots", mock.MatchedBy(func(slots []domain.AppointmentSlot) bool {
+		return len(slots) > 0 && slots[0].TemplateID == 7
+	}), "tenant_test").Return(8, nil)
+
+	until := time.Now().In(domain.HNTimeZone).AddDate(0, 0, 60).Format(domain.DateFormat)
+	result, err := service.Expand(7, &domain.AvailabilityExpandRequest{Until: until}, "tenant_test")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 8, result.SlotsCreated)
+	assert.Equal(t, until, result.Until)
+}
+
+func TestAvailabilityService_Expand_BeyondHorizon(t *testing.T) {
+	service, m := newAvailabilityService()
+
+	m.templateRepo.On("GetByID", uint(7), "tenant_test").Return(&domain.AvailabilityTemplate{Model: gorm.Model{ID: 7}}, nil)
+
+	until := time.Now().In(domain.HNTimeZone).AddDate(0, 0, domain.MaxSlotHorizonDays+1).Format(domain.DateFormat)
+	_, err := service.Expand(7, &domain.AvailabilityExpandRequest{Until: until}, "tenant_test")
+
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	m.templateRepo.AssertNotCalled(t, "AddSlots", mock.Anything, mock.Anything)
+}
+
+func TestAvailabilityService_Delete(t *testing.T) {
+	service, m := newAvailabilityService()
+
+	template := &domain.AvailabilityTemplate{Model: gorm.Model{ID: 7}}
+	m.templateRepo.On("GetByID", uint(7), "tenant_test").Return(template, nil)
+	m.templateRepo.On("Delete", template, mock.AnythingOfType("time.Time"), "tenant_test").Return(nil)
+
+	err := service.Delete(7, "tenant_test")
+
+	assert.NoError(t, err)
+	m.templateRepo.AssertExpectations(t)
+}
+
+func TestAvailabilityService_Delete_NotFound(t *testing.T) {
+	service, m := newAvailabilityService()
+
+	m.templateRepo.On("GetByID", uint(7), "tenant_test").Return(nil, gorm.ErrRecordNotFound)
+
+	err := service.Delete(7, "tenant_test")
+
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
@@ -0,0 +1,63 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/mocks"
+	"github.com/wichai2002/his_v1/internal/services"
+	"gorm.io/gorm"
+)
+
+func TestDepartmentService_Create(t *testing.T) {
+	mockRepo := mocks.NewMockDepartmentRepository()
+	mockRepo.On("Create", mock.MatchedBy(func(d *domain.Department) bool {
+		return d.Code == "ENT" && d.NameEN == "Otolaryngology" && d.IsActive
+	}), "tenant_test").Return(nil)
+
+	service := services.NewDepartmentService(mockRepo)
+	department, err := service.Create(&domain.DepartmentRequest{Code: "ent", NameTH: "หู คอ จมูก", NameEN: " Otolaryngology "}, "tenant_test")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "ENT", department.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestDepartmentService_Create_DuplicateCode(t *testing.T) {
+	mockRepo := mocks.NewMockDepartmentRepository()
+	mockRepo.On("Create", mock.Anything, "tenant_test").Return(errors.New("duplicate key value violates unique constraint"))
+
+	service := services.NewDepartmentService(mockRepo)
+	_, err := service.Create(&domain.DepartmentRequest{Code: "OPD", NameTH: "ตรวจโรคทั่วไป", NameEN: "General Outpatient"}, "tenant_test")
+
+	assert.ErrorIs(t, err, domain.ErrDuplicateEntry)
+}
+
+func TestDepartmentService_Update_Deactivate(t *testing.T) {
+	inactive := false
+	mockRepo := mocks.NewMockDepartmentRepository()
+	mockRepo.On("GetByID", uint(3), "tenant_test").Return(&domain.Department{Model: gorm.Model{ID: 3}, Code: "DENT", IsActive: true}, nil)
+	mockRepo.On("Update", mock.MatchedBy(func(d *domain.Department) bool {
+		return d.ID == 3 && !d.IsActive
+	}), "tenant_test").Return(nil)
+
+	service := services.NewDepartmentService(mockRepo)
+	department, err := service.Update(3, &domain.DepartmentRequest{Code: "DENT", NameTH: "ทันตกรรม", NameEN: "Dental", IsActive: &inactive}, "tenant_test")
+
+	assert.NoError(t, err)
+	assert.False(t, department.IsActive)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestDepartmentService_Update_NotFound(t *testing.T) {
+	mockRepo := mocks.NewMockDepartmentRepository()
+	mockRepo.On("GetByID", uint(3), "tenant_test").Return(nil, gorm.ErrRecordNotFound)
+
+	service := services.NewDepartmentService(mockRepo)
+	_, err := service.Update(3, &domain.DepartmentRequest{Code: "DENT", NameTH: "ทันตกรรม", NameEN: "Dental"}, "tenant_test")
+
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
@@ -0,0 +1,174 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/mocks"
+	"github.com/wichai2002/his_v1/internal/services"
+	"gorm.io/gorm"
+)
+
+type diagnosisMocks struct {
+	diagnosisRepo *mocks.MockDiagnosisRepository
+	icd10Repo     *mocks.MockICD10Repository
+	encounterRepo *mocks.MockEncounterRepository
+	patientRepo   *mocks.MockPatientRepository
+	auditRepo     *mocks.MockAuditRepository
+}
+
+func newDiagnosisService() (domain.DiagnosisService, *diagnosisMocks) {
+	m := &diagnosisMocks{
+		diagnosisRepo: mocks.NewMockDiagnosisRepository(),
+		icd10Repo:     mocks.NewMockICD10Repository(),
+		encounterRepo: mocks.NewMockEncounterRepository(),
+		patientRepo:   mocks.NewMockPatientRepository(),
+		auditRepo:     mocks.NewMockAuditRepository(),
+	}
+	return services.NewDiagnosisService(m.diagnosisRepo, m.icd10Repo, m.encounterRepo, m.patientRepo, m.auditRepo), m
+}
+
+func TestDiagnosisService_Create(t *testing.T) {
+	service, m := newDiagnosisService()
+
+	m.encounterRepo.On("GetByID", uint(12), "tenant_test").Return(openEncounter(), nil)
+	m.icd10Repo.On("GetByCode", "J18.9").Return(&domain.ICD10Code{Code: "J18.9", Description: "Pneumonia, unspecified", Billable: true}, nil)
+	m.diagnosisRepo.On("ListByEncounter", uint(12), "tenant_test").Return([]domain.Diagnosis{{Code: "I10", Type: domain.DiagnosisTypeComorbidity}}, nil)
+	m.diagnosisRepo.On("Create", mock.MatchedBy(func(d *domain.Diagnosis) bool {
+		return d.EncounterID == 12 && d.PatientID == 1 && d.Code == "J18.9" &&
+			d.Description == "Pneumonia, unspecified" && d.DiagnosedBy == testActor.StaffID
+	}), mock.MatchedBy(func(e *domain.AuditEvent) bool {
+		return e.Action == domain.AuditActionPatientDiagnosisCreate && *e.PatientID == 1
+	}), "tenant_test").Return(nil)
+
+	diagnosis, err := service.Create(12, &domain.DiagnosisRequest{Code: "j189", Type: domain.DiagnosisTypePrincipal}, testActor, "tenant_test")
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.DiagnosisTypePrincipal, diagnosis.Type)
+	m.diagnosisRepo.AssertExpectations(t)
+}
+
+func TestDiagnosisService_Create_CodingRules(t *testing.T) {
+	tests := []struct {
+		name     string
+		icd10    *domain.ICD10Code
+		diagType string
+	}{
+		{
+			name:     "principal must be billable",
+			icd10:    &domain.ICD10Code{Code: "J18", Description: "Pneumonia, organism unspecified"},
+			diagType: domain.DiagnosisTypePrincipal,
+		},
+		{
+			name:     "external cause needs a V01-Y98 code",
+			icd10:    &domain.ICD10Code{Code: "S72.0", Description: "Fracture of neck of femur", Billable: true},
+			diagType: domain.DiagnosisTypeExternalCause,
+		},
+		{
+			name:     "external cause code as principal",
+			icd10:    &domain.ICD10Code{Code: "W19", Description: "Unspecified fall", Billable: true},
+			diagType: domain.DiagnosisTypePrincipal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, m := newDiagnosisService()
+			m.encounterRepo.On("GetByID", uint(12), "tenant_test").Return(openEncounter(), nil)
+			m.icd10Repo.On("GetByCode", tt.icd10.Code).Return(tt.icd10, nil)
+
+			_, err := service.Create(12, &domain.DiagnosisRequest{Code: tt.icd10.Code, Type: tt.diagType}, testActor, "tenant_test")
+
+			assert.True(t, errors.Is(err, domain.ErrInvalidInput))
+			m.diagnosisRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestDiagnosisService_Create_UnknownCode(t *testing.T) {
+	service, m := newDiagnosisService()
+	m.encounterRepo.On("GetByID", uint(12), "tenant_test").Return(openEncounter(), nil)
+	m.icd10Repo.On("GetByCode", "J99.9").Return(nil, gorm.ErrRecordNotFound)
+
+	_, err := service.Create(12, &domain.DiagnosisRequest{Code: "J99.9", Type: domain.DiagnosisTypeComorbidity}, testActor, "tenant_test")
+
+	assert.True(t, errors.Is(err, domain.ErrInvalidInput))
+	assert.Contains(t, err.Error(), "J99.9 does not exist")
+}
+
+func TestDiagnosisService_Create_Duplicates(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing domain.Diagnosis
+		req      *domain.DiagnosisRequest
+	}{
+		{
+			name:     "second principal",
+			existing: domain.Diagnosis{Code: "A09.9", Type: domain.DiagnosisTypePrincipal},
+			req:      &domain.DiagnosisRequest{Code: "J18.9", Type: domain.DiagnosisTypePrincipal},
+		},
+		{
+			name:     "same code twice",
+			existing: domain.Diagnosis{Code: "J18.9", Type: domain.DiagnosisTypeComorbidity},
+			req:      &domain.DiagnosisRequest{Code: "J18.9", Type: domain.DiagnosisTypeComplication},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, m := newDiagnosisService()
+			m.encounterRepo.On("GetByID", uint(12), "tenant_test").Return(openEncounter(), nil)
+			m.icd10Repo.On("GetByCode", "J18.9").Return(&domain.ICD10Code{Code: "J18.9", Billable: true}, nil)
+			m.diagnosisRepo.On("ListByEncounter", uint(12), "tenant_test").Return([]domain.Diagnosis{tt.existing}, nil)
+
+			_, err := service.Create(12, tt.req, testActor, "tenant_test")
+
+			assert.True(t, errors.Is(err, domain.ErrDuplicateEntry))
+			m.diagnosisRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestDiagnosisService_ListByPatient(t *testing.T) {
+	service, m := newDiagnosisService()
+	m.patientRepo.On("GetByID", uint(1), "tenant_test").Return(adultPatient(), nil)
+	m.diagnosisRepo.On("ListByPatient", uint(1), "tenant_test").Return([]domain.Diagnosis{{Code: "J18.9"}}, nil)
+	m.auditRepo.On("Append", mock.MatchedBy(func(events []*domain.AuditEvent) bool {
+		return len(events) == 1 && events[0].Action == domain.AuditActionPatientDiagnosisView
+	}), "tenant_test").Return(nil)
+
+	diagnoses, err := service.ListByPatient(1, testActor, "tenant_test")
+
+	assert.NoError(t, err)
+	assert.Len(t, diagnoses, 1)
+	m.auditRepo.AssertExpectations(t)
+}
+
+func TestDiagnosisService_Delete(t *testing.T) {
+	service, m := newDiagnosisService()
+	diagnosis := &domain.Diagnosis{EncounterID: 12, PatientID: 1, Code: "J18.9", Type: domain.DiagnosisTypePrincipal}
+	diagnosis.ID = 3
+	m.encounterRepo.On("GetByID", uint(12), "tenant_test").Return(openEncounter(), nil)
+	m.diagnosisRepo.On("GetByID", uint(12), uint(3), "tenant_test").Return(diagnosis, nil)
+	m.diagnosisRepo.On("Delete", diagnosis, mock.MatchedBy(func(e *domain.AuditEvent) bool {
+		return e.Action == domain.AuditActionPatientDiagnosisDelete
+	}), "tenant_test").Return(nil)
+
+	err := service.Delete(12, 3, testActor, "tenant_test")
+
+	assert.NoError(t, err)
+	m.diagnosisRepo.AssertExpectations(t)
+}
+
+func TestDiagnosisService_Delete_NotFound(t *testing.T) {
+	service, m := newDiagnosisService()
+	m.encounterRepo.On("GetByID", uint(12), "tenant_test").Return(openEncounter(), nil)
+	m.diagnosisRepo.On("GetByID", uint(12), uint(3), "tenant_test").Return(nil, gorm.ErrRecordNotFound)
+
+	err := service.Delete(12, 3, testActor, "tenant_test")
+
+	assert.True(t, errors.Is(err, domain.ErrNotFound))
+}
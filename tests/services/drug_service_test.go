@@ -0,0 +1,103 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/mocks"
+	"github.com/wichai2002/his_v1/internal/services"
+	"gorm.io/gorm"
+)
+
+func drugWithID(id uint, code string, class string) *domain.Drug {
+	drug := &domain.Drug{Code: code, GenericName: code, DispensingUnit: "tab", TherapeuticClass: class, IsActive: true}
+	drug.ID = id
+	return drug
+}
+
+func TestDrugService_Create(t *testing.T) {
+	drugRepo := mocks.NewMockDrugRepository()
+	drugRepo.On("Create", mock.MatchedBy(func(d *domain.Drug) bool {
+		return d.Code == "AMX500" && d.GenericName == "Amoxicillin" && d.TherapeuticClass == "penicillin" && d.IsActive
+	}), "tenant_test").Return(nil)
+	service := services.NewDrugService(drugRepo)
+
+	req := &domain.DrugRequest{
+		Code:             "amx500",
+		GenericName:      " Amoxicillin ",
+		Strength:         "500 mg",
+		DosageForm:       "capsule",
+		DispensingUnit:   "cap",
+		TherapeuticClass: " Penicillin",
+	}
+	drug, err := service.Create(req, "tenant_test")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Amoxicillin 500 mg", drug.DisplayName())
+	drugRepo.AssertExpectations(t)
+}
+
+func TestDrugService_Create_DuplicateCode(t *testing.T) {
+	drugRepo := mocks.NewMockDrugRepository()
+	drugRepo.On("Create", mock.Anything, "tenant_test").Return(errors.New(`duplicate key value violates unique constraint "idx_tenant_test_drugs_code_live"`))
+	service := services.NewDrugService(drugRepo)
+
+	_, err := service.Create(&domain.DrugRequest{Code: "AMX500", GenericName: "Amoxicillin", DosageForm: "capsule", DispensingUnit: "cap", TherapeuticClass: "penicillin"}, "tenant_test")
+
+	assert.True(t, errors.Is(err, domain.ErrDuplicateEntry))
+}
+
+func TestDrugService_CreateInteraction(t *testing.T) {
+	drugRepo := mocks.NewMockDrugRepository()
+	drugRepo.On("GetByID", uint(9), "tenant_test").Return(drugWithID(9, "WARF", "anticoagulant"), nil)
+	drugRepo.On("GetByID", uint(4), "tenant_test").Return(drugWithID(4, "IBU", "nsaid"), nil)
+	drugRepo.On("CreateInteraction", mock.MatchedBy(func(i *domain.DrugInteraction) bool {
+		// The pair is stored with the lower drug ID first
+		return i.DrugID == 4 && i.InteractingDrugID == 9 && i.Severity == domain.InteractionSeverityMajor
+	}), "tenant_test").Return(nil)
+	service := services.NewDrugService(drugRepo)
+
+	req := &domain.DrugInteractionRequest{InteractingDrugID: 4, Severity: domain.InteractionSeverityMajor, Description: "Increased bleeding risk"}
+	_, err := service.CreateInteraction(9, req, "tenant_test")
+
+	assert.NoError(t, err)
+	drugRepo.AssertExpectations(t)
+}
+
+func TestDrugService_CreateInteraction_InvalidInput(t *testing.T) {
+	tests := []struct {
+		name  string
+		req   *domain.DrugInteractionRequest
+		setup func(m *mocks.MockDrugRepository)
+	}{
+		{
+			name:  "drug with itself",
+			req:   &domain.DrugInteractionRequest{InteractingDrugID: 9, Severity: domain.InteractionSeverityMinor, Description: "-"},
+			setup: func(m *mocks.MockDrugRepository) {},
+		},
+		{
+			name: "unknown interacting drug",
+			req:  &domain.DrugInteractionRequest{InteractingDrugID: 77, Severity: domain.InteractionSeverityMinor, Description: "-"},
+			setup: func(m *mocks.MockDrugRepository) {
+				m.On("GetByID", uint(9), "tenant_test").Return(drugWithID(9, "WARF", "anticoagulant"), nil)
+				m.On("GetByID", uint(77), "tenant_test").Return(nil, gorm.ErrRecordNotFound)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			drugRepo := mocks.NewMockDrugRepository()
+			tt.setup(drugRepo)
+			service := services.NewDrugService(drugRepo)
+
+			_, err := service.CreateInteraction(9, tt.req, "tenant_test")
+
+			assert.True(t, errors.Is(err, domain.ErrInvalidInput))
+			drugRepo.AssertNotCalled(t, "CreateInteraction", mock.Anything, mock.Anything)
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package services_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/mocks"
+	"github.com/wichai2002/his_v1/internal/services"
+	"gorm.io/gorm"
+)
+
+type encounterMocks struct {
+	encounterRepo  *mocks.MockEncounterRepository
+	patientRepo    *mocks.MockPatientRepository
+	departmentRepo *mocks.MockDepartmentRepository
+	auditRepo      *mocks.MockAuditRepository
+}
+
+func newEncounterService() (domain.EncounterService, *encounterMocks) {
+	m := &encounterMocks{
+		encounterRepo:  mocks.NewMockEncounterRepository(),
+		patientRepo:    mocks.NewMockPatientRepository(),
+		departmentRepo: mocks.NewMockDepartmentRepository(),
+		auditRepo:      mocks.NewMockAuditRepository(),
+	}
+	return services.NewEncounterService(m.encounterRepo, m.patientRepo, m.departmentRepo, m.auditRepo), m
+}
+
+func TestEncounterService_Create(t *testing.T) {
+	service, m := newEncounterService()
+
+	m.patientRepo.On("GetByID", uint(1), "tenant_test").Return(&domain.Patient{}, nil)
+	m.departmentRepo.On("GetByID", uint(2), "tenant_test").
+		Return(&domain.Department{Model: gorm.Model{ID: 2}, Code: "OPD", IsActive: true}, nil)
+	m.encounterRepo.On("Create", mock.MatchedBy(func(e *domain.Encounter) bool {
+		return e.PatientID == 1 && e.DepartmentID == 2 && e.ChiefComplaint == "fever" && e.RegisteredBy == testActor.StaffID
+	}), mock.MatchedBy(func(issuer *domain.HNIssuer) bool {
+		return issuer.Template.String() == domain.DefaultVNTemplate
+	}), mock.MatchedBy(func(e *domain.AuditEvent) bool {
+		return e.Action == domain.AuditActionPatientEncounterCreate && *e.PatientID == 1
+	}), "tenant_test").Return(nil)
+
+	req := &domain.EncounterCreateRequest{PatientID: 1, DepartmentID: 2, ChiefComplaint: " fever "}
+	encounter, err := service.Create(req, testActor, "tenant_test")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "fever", encounter.ChiefComplaint)
+	m.encounterRepo.AssertExpectations(t)
+}
+
+func TestEncounterService_Create_Errors(t *testing.T) {
+	tests := []struct {
+		name          string
+		patientErr    error
+		department    *domain.Department
+		departmentErr error
+		expectedError error
+	}{
+		{
+			name:          "patient not found",
+			patientErr:    gorm.ErrRecordNotFound,
+			expectedError: domain.ErrNotFound,
+		},
+		{
+			name:          "department not found",
+			departmentErr: gorm.ErrRecordNotFound,
+			expectedError: domain.ErrInvalidInput,
+		},
+		{
+			name:          "inactive department",
+			department:    &domain.Department{Model: gorm.Model{ID: 2}, Code: "DENT"},
+			expectedError: domain.ErrInvalidInput,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, m := newEncounterService()
+
+			if tt.patientErr != nil {
+				m.patientRepo.On("GetByID", uint(1), "tenant_test").Return(nil, tt.patientErr)
+			} else {
+				m.patientRepo.On("GetByID", uint(1), "tenant_test").Return(&domain.Patient{}, nil)
+			}
+			if tt.departmentErr != nil {
+				m.departmentRepo.On("GetByID", uint(2), "tenant_test").Return(nil, tt.departmentErr)
+			} else {
+				m.departmentRepo.On("GetByID", uint(2), "tenant_test").Return(tt.department, nil)
+			}
+
+			req := &domain.EncounterCreateRequest{PatientID: 1, DepartmentID: 2}
+			_, err := service.Create(req, testActor, "tenant_test")
+
+			assert.ErrorIs(t, err, tt.expectedError)
+			m.encounterRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestEncounterService_UpdateStatus(t *testing.T) {
+	service, m := newEncounterService()
+
+	m.encounterRepo.On("GetByID", uint(9), "tenant_test").Return(&domain.Encounter{
+		Model: gorm.Model{ID: 9}, VN: "69031500001", PatientID: 1, Status: domain.EncounterStatusRegistered,
+	}, nil)
+	m.encounterRepo.On("UpdateStatus", mock.MatchedBy(func(e *domain.Encounter) bool {
+		return e.Status == domain.EncounterStatusTriaged && e.TriagedAt != nil
+	}), domain.EncounterStatusRegistered, mock.MatchedBy(func(e *domain.AuditEvent) bool {
+		var changes map[string]domain.FieldChange
+		if json.Unmarshal([]byte(e.Changes), &changes) != nil {
+			return false
+		}
+		return e.Action == domain.AuditActionPatientEncounterStatus &&
+			changes["status"].Before == domain.EncounterStatusRegistered && changes["status"].After == domain.EncounterStatusTriaged
+	}), "tenant_test").Return(nil)
+
+	encounter, err := service.UpdateStatus(9, &domain.EncounterStatusRequest{Status: domain.EncounterStatusTriaged}, testActor, "tenant_test")
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.EncounterStatusTriaged, encounter.Status)
+	m.encounterRepo.AssertExpectations(t)
+}
+
+func TestEncounterService_UpdateStatus_InvalidTransition(t *testing.T) {
+	tests := []struct {
+		name   string
+		from   string
+		status string
+	}{
+		{name: "skips triage", from: domain.EncounterStatusRegistered, status: domain.EncounterStatusInConsultation},
+		{name: "goes back", from: domain.EncounterStatusInConsultation, status: domain.EncounterStatusTriaged},
+		{name: "repeats", from: domain.EncounterStatusTriaged, status: domain.EncounterStatusTriaged},
+		{name: "after done", from: domain.EncounterStatusDone, status: domain.EncounterStatusDone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, m := newEncounterService()
+
+			m.encounterRepo.On("GetByID", uint(9), "tenant_test").Return(&domain.Encounter{VN: "69031500001", Status: tt.from}, nil)
+
+			_, err := service.UpdateStatus(9, &domain.EncounterStatusRequest{Status: tt.status}, testActor, "tenant_test")
+
+			assert.ErrorIs(t, err, domain.ErrInvalidStatusTransition)
+			m.encounterRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestEncounterService_UpdateStatus_ConcurrentChange(t *testing.T) {
+	service, m := newEncounterService()
+
+	m.encounterRepo.On("GetByID", uint(9), "tenant_test").Return(&domain.Encounter{Status: domain.EncounterStatusTriaged}, nil)
+	m.encounterRepo.On("UpdateStatus", mock.Anything, domain.EncounterStatusTriaged, mock.Anything, "tenant_test").
+		Return(domain.ErrPreconditionFailed)
+
+	_, err := service.UpdateStatus(9, &domain.EncounterStatusRequest{Status: domain.EncounterStatusInConsultation}, testActor, "tenant_test")
+
+	assert.ErrorIs(t, err, domain.ErrPreconditionFailed)
+}
+
+func TestEncounterService_Queue(t *testing.T) {
+	service, m := newEncounterService()
+
+	m.encounterRepo.On("Queue", mock.MatchedBy(func(f *domain.EncounterQueueFilter) bool {
+		return f.DepartmentID == 2 && len(f.Statuses) == 3 &&
+			f.To.Sub(f.From) == 24*time.Hour && f.From.Location() == domain.HNTimeZone &&
+			!time.Now().Before(f.From) && time.Now().Before(f.To)
+	}), "tenant_test").Return([]domain.EncounterQueueEntry{
+		{Encounter: domain.Encounter{PatientID: 1}},
+		{Encounter: domain.Encounter{PatientID: 4}},
+	}, nil)
+	m.auditRepo.On("Append", mock.MatchedBy(func(events []*domain.AuditEvent) bool {
+		return len(events) == 2 && *events[0].PatientID == 1 && *events[1].PatientID == 4 &&
+			events[0].Action == domain.AuditActionPatientEncounterView
+	}), "tenant_test").Return(nil)
+
+	entries, err := service.Queue(&domain.EncounterQueueRequest{DepartmentID: 2}, testActor, "tenant_test")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, entries[0].Position)
+	assert.Equal(t, 2, entries[1].Position)
+	m.auditRepo.AssertExpectations(t)
+}
+
+func TestEncounterService_Queue_StatusFilter(t *testing.T) {
+	service, m := newEncounterService()
+
+	m.encounterRepo.On("Queue", mock.MatchedBy(func(f *domain.EncounterQueueFilter) bool {
+		return len(f.Statuses) == 1 && f.Statuses[0] == domain.EncounterStatusTriaged
+	}), "tenant_test").Return([]domain.EncounterQueueEntry{}, nil)
+
+	entries, err := service.Queue(&domain.EncounterQueueRequest{DepartmentID: 2, Status: domain.EncounterStatusTriaged}, testActor, "tenant_test")
+
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+	m.auditRepo.AssertNotCalled(t, "Append", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,137 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/mocks"
+	"github.com/wichai2002/his_v1/internal/services"
+)
+
+func newHNTenant(template string, hnRunning uint64) *domain.Tenant {
+	return &domain.Tenant{SchemaName: "tenant_test", HospitalCode: "HOSP0001", HNTemplate: template, HNRunning: hnRunning}
+}
+
+func TestHNService_GetSettings_ContinuesLegacyRunningNumber(t *testing.T) {
+	mockTenantRepo := mocks.NewMockTenantRepository()
+	mockCounterRepo := mocks.NewMockHNCounterRepository()
+	mockTenantRepo.On("GetBySchemaName", "tenant_test").Return(newHNTenant(domain.DefaultHNTemplate, 41), nil)
+	mockCounterRepo.On("Current", "", "tenant_test").Return(uint64(0), nil)
+
+	service := services.NewHNService(mockTenantRepo, mockCounterRepo)
+	settings, err := service.GetSettings("tenant_test")
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.HNResetNever, settings.Reset)
+	assert.Equal(t, uint64(41), settings.LastSequence)
+	assert.Equal(t, "HOSP0001-00000042", settings.NextHN)
+}
+
+func TestHNService_Preview(t *testing.T) {
+	template, err := domain.ParseHNTemplate("{BE_YY}-{SEQ:6}{CHECK}")
+	require.NoError(t, err)
+	now := time.Now()
+	period := template.Period(now)
+
+	mockTenantRepo := mocks.NewMockTenantRepository()
+	mockCounterRepo := mocks.NewMockHNCounterRepository()
+	mockTenantRepo.On("GetBySchemaName", "tenant_test").Return(newHNTenant(domain.DefaultHNTemplate, 41), nil)
+	mockCounterRepo.On("Current", period, "tenant_test").Return(uint64(0), nil)
+	mockCounterRepo.On("FindHNs", template.Pattern("HOSP0001"), "tenant_test").Return([]string{}, nil)
+
+	service := services.NewHNService(mockTenantRepo, mockCounterRepo)
+	preview, err := service.Preview(&domain.HNPreviewRequest{Template: template.String(), Count: 2}, "tenant_test")
+
+	require.NoError(t, err)
+	first, _ := template.Format("HOSP0001", now, 1)
+	second, _ := template.Format("HOSP0001", now, 2)
+	assert.Equal(t, []string{first, second}, preview.Next)
+	assert.Equal(t, period, preview.Period)
+	assert.Empty(t, preview.Collisions)
+	mockTenantRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestHNService_UpdateTemplate(t *testing.T) {
+	template, err := domain.ParseHNTemplate("{BE_YY}-{SEQ:6}")
+	require.NoError(t, err)
+	now := time.Now()
+	period := template.Period(now)
+	lastYear, _ := template.Format("HOSP0001", now.AddDate(-1, 0, 0), 900)
+	issued, _ := template.Format("HOSP0001", now, 10)
+	ahead, _ := template.Format("HOSP0001", now, 11)
+
+	t.Run("saves when existing HNs were issued already", func(t *testing.T) {
+		mockTenantRepo := mocks.NewMockTenantRepository()
+		mockCounterRepo := mocks.NewMockHNCounterRepository()
+		mockTenantRepo.On("GetBySchemaName", "tenant_test").Return(newHNTenant(domain.DefaultHNTemplate, 0), nil)
+		mockTenantRepo.On("Update", mock.MatchedBy(func(tenant *domain.Tenant) bool {
+			return tenant.HNTemplate == "{BE_YY}-{SEQ:6}"
+		})).Return(nil)
+		mockCounterRepo.On("Current", period, "tenant_test").Return(uint64(10), nil)
+		mockCounterRepo.On("FindHNs", template.Pattern("HOSP0001"), "tenant_test").Return([]string{lastYear, issued}, nil)
+
+		service := services.NewHNService(mockTenantRepo, mockCounterRepo)
+		settings, err := service.UpdateTemplate(&domain.HNTemplateRequest{Template: " {BE_YY}-{SEQ:6} "}, "tenant_test")
+
+		assert.NoError(t, err)
+		assert.Equal(t, ahead, settings.NextHN)
+		mockTenantRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects a template that would reissue an HN", func(t *testing.T) {
+		mockTenantRepo := mocks.NewMockTenantRepository()
+		mockCounterRepo := mocks.NewMockHNCounterRepository()
+		mockTenantRepo.On("GetBySchemaName", "tenant_test").Return(newHNTenant(domain.DefaultHNTemplate, 0), nil)
+		mockCounterRepo.On("Current", period, "tenant_test").Return(uint64(10), nil)
+		mockCounterRepo.On("FindHNs", template.Pattern("HOSP0001"), "tenant_test").Return([]string{issued, ahead}, nil)
+
+		service := services.NewHNService(mockTenantRepo, mockCounterRepo)
+		_, err := service.UpdateTemplate(&domain.HNTemplateRequest{Template: "{BE_YY}-{SEQ:6}"}, "tenant_test")
+
+		var collisionErr *domain.HNCollisionError
+		require.True(t, errors.As(err, &collisionErr))
+		assert.Equal(t, []string{ahead}, collisionErr.Collisions)
+		mockTenantRepo.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("invalid template", func(t *testing.T) {
+		mockTenantRepo := mocks.NewMockTenantRepository()
+		mockTenantRepo.On("GetBySchemaName", "tenant_test").Return(newHNTenant(domain.DefaultHNTemplate, 0), nil)
+
+		service := services.NewHNService(mockTenantRepo, mocks.NewMockHNCounterRepository())
+		_, err := service.UpdateTemplate(&domain.HNTemplateRequest{Template: "{BE_YY}-"}, "tenant_test")
+
+		assert.True(t, errors.Is(err, domain.ErrInvalidInput))
+	})
+}
+
+func TestTenantService_GetHNIssuer(t *testing.T) {
+	t.Run("issuer continues the legacy running number", func(t *testing.T) {
+		mockTenantRepo := mocks.NewMockTenantRepository()
+		mockTenantRepo.On("GetBySchemaName", "tenant_test").Return(newHNTenant("{BE_YY}-{SEQ:6}{CHECK}", 41), nil)
+
+		service := services.NewTenantService(mockTenantRepo, nil, nil)
+		issuer, err := service.GetHNIssuer("tenant_test")
+
+		require.NoError(t, err)
+		assert.Equal(t, "HOSP0001", issuer.HospitalCode)
+		assert.Equal(t, domain.HNResetYearly, issuer.Template.Reset())
+		assert.Equal(t, uint64(41), issuer.Seed(""))
+		assert.Equal(t, uint64(0), issuer.Seed("2567"))
+	})
+
+	t.Run("stored template is invalid", func(t *testing.T) {
+		mockTenantRepo := mocks.NewMockTenantRepository()
+		mockTenantRepo.On("GetBySchemaName", "tenant_test").Return(newHNTenant("{HOSP}", 0), nil)
+
+		service := services.NewTenantService(mockTenantRepo, nil, nil)
+		_, err := service.GetHNIssuer("tenant_test")
+
+		assert.True(t, errors.Is(err, domain.ErrInvalidInput))
+	})
+}
@@ -0,0 +1,87 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/mocks"
+	"github.com/wichai2002/his_v1/internal/services"
+	"gorm.io/gorm"
+)
+
+func TestICD10Service_Search(t *testing.T) {
+	tests := []struct {
+		name     string
+		req      *domain.ICD10SearchRequest
+		expected *domain.ICD10SearchFilter
+	}{
+		{
+			name:     "code prefix with dot",
+			req:      &domain.ICD10SearchRequest{Query: "j18.", BillableOnly: true},
+			expected: &domain.ICD10SearchFilter{CodePrefix: "J18", BillableOnly: true, Limit: domain.DefaultICD10SearchLimit},
+		},
+		{
+			name:     "category",
+			req:      &domain.ICD10SearchRequest{Query: "E11", Limit: 5},
+			expected: &domain.ICD10SearchFilter{CodePrefix: "E11", Limit: 5},
+		},
+		{
+			name:     "keywords",
+			req:      &domain.ICD10SearchRequest{Query: "  type 2 diabetes "},
+			expected: &domain.ICD10SearchFilter{Keywords: []string{"type", "2", "diabetes"}, Limit: domain.DefaultICD10SearchLimit},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			icd10Repo := mocks.NewMockICD10Repository()
+			icd10Repo.On("Search", tt.expected).Return([]domain.ICD10Code{{Code: "J18.9"}}, nil)
+			service := services.NewICD10Service(icd10Repo)
+
+			codes, err := service.Search(tt.req)
+
+			assert.NoError(t, err)
+			assert.Len(t, codes, 1)
+			icd10Repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestICD10Service_Search_InvalidInput(t *testing.T) {
+	tests := []struct {
+		name string
+		req  *domain.ICD10SearchRequest
+	}{
+		{name: "query too short", req: &domain.ICD10SearchRequest{Query: " J "}},
+		{name: "limit above maximum", req: &domain.ICD10SearchRequest{Query: "J18", Limit: domain.MaxICD10SearchLimit + 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			icd10Repo := mocks.NewMockICD10Repository()
+			service := services.NewICD10Service(icd10Repo)
+
+			_, err := service.Search(tt.req)
+
+			assert.True(t, errors.Is(err, domain.ErrInvalidInput))
+			icd10Repo.AssertNotCalled(t, "Search", mock.Anything)
+		})
+	}
+}
+
+func TestICD10Service_GetByCode(t *testing.T) {
+	icd10Repo := mocks.NewMockICD10Repository()
+	icd10Repo.On("GetByCode", "J18.9").Return(&domain.ICD10Code{Code: "J18.9", Billable: true}, nil)
+	icd10Repo.On("GetByCode", "J99.9").Return(nil, gorm.ErrRecordNotFound)
+	service := services.NewICD10Service(icd10Repo)
+
+	icd10, err := service.GetByCode("j189")
+	assert.NoError(t, err)
+	assert.Equal(t, "J18.9", icd10.Code)
+
+	_, err = service.GetByCode("J99.9")
+	assert.True(t, errors.Is(err, domain.ErrNotFound))
+}
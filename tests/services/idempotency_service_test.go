@@ -0,0 +1,90 @@
+package services_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/mocks"
+	"github.com/wichai2002/his_v1/internal/services"
+)
+
+func TestIdempotencyService_Begin(t *testing.T) {
+	completed := &domain.IdempotencyKey{Key: "key-1", StaffID: 1, RequestHash: "hash-a", StatusCode: 201, ResponseBody: `{"success":true}`}
+
+	tests := []struct {
+		name        string
+		record      *domain.IdempotencyKey
+		existing    *domain.IdempotencyKey
+		expectReuse *domain.IdempotencyKey
+		expectError error
+	}{
+		{
+			name:   "new key is reserved",
+			record: &domain.IdempotencyKey{Key: "key-1", StaffID: 1, RequestHash: "hash-a"},
+		},
+		{
+			name:        "retry replays the stored response",
+			record:      &domain.IdempotencyKey{Key: "key-1", StaffID: 1, RequestHash: "hash-a"},
+			existing:    completed,
+			expectReuse: completed,
+		},
+		{
+			name:        "different payload",
+			record:      &domain.IdempotencyKey{Key: "key-1", StaffID: 1, RequestHash: "hash-b"},
+			existing:    completed,
+			expectError: domain.ErrIdempotencyKeyReused,
+		},
+		{
+			name:        "same payload from another staff member",
+			record:      &domain.IdempotencyKey{Key: "key-1", StaffID: 2, RequestHash: "hash-a"},
+			existing:    completed,
+			expectError: domain.ErrIdempotencyKeyReused,
+		},
+		{
+			name:        "first request still running",
+			record:      &domain.IdempotencyKey{Key: "key-1", StaffID: 1, RequestHash: "hash-a"},
+			existing:    &domain.IdempotencyKey{Key: "key-1", StaffID: 1, RequestHash: "hash-a"},
+			expectError: domain.ErrIdempotencyKeyInUse,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockIdempotencyRepository()
+			var existing interface{}
+			if tt.existing != nil {
+				existing = tt.existing
+			}
+			mockRepo.On("Reserve", tt.record, mock.MatchedBy(func(expiredBefore time.Time) bool {
+				return time.Since(expiredBefore) >= domain.IdempotencyKeyTTL
+			}), "tenant_test").Return(existing, nil)
+
+			service := services.NewIdempotencyService(mockRepo)
+			stored, err := service.Begin(tt.record, "tenant_test")
+
+			if tt.expectError != nil {
+				assert.ErrorIs(t, err, tt.expectError)
+				assert.Nil(t, stored)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectReuse, stored)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestIdempotencyService_Begin_InvalidKey(t *testing.T) {
+	mockRepo := mocks.NewMockIdempotencyRepository()
+	service := services.NewIdempotencyService(mockRepo)
+
+	for _, key := range []string{"  ", strings.Repeat("k", domain.MaxIdempotencyKeyLength+1)} {
+		_, err := service.Begin(&domain.IdempotencyKey{Key: key, StaffID: 1}, "tenant_test")
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	}
+	mockRepo.AssertNotCalled(t, "Reserve", mock.Anything, mock.Anything, mock.Anything)
+}
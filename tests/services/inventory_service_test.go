@@ -0,0 +1,193 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/mocks"
+	"github.com/wichai2002/his_v1/internal/services"
+)
+
+func mainPharmacy() *domain.StockLocation {
+	location := &domain.StockLocation{Code: "OPDRX", Name: "OPD pharmacy", IsActive: true}
+	location.ID = 1
+	return location
+}
+
+// thaiToday is the current calendar day in Thailand at UTC midnight, as the services compute it
+func thaiToday() time.Time {
+	now := time.Now().In(domain.HNTimeZone)
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func TestInventoryService_Receive(t *testing.T) {
+	inventoryRepo := mocks.NewMockInventoryRepository()
+	drugRepo := mocks.NewMockDrugRepository()
+	service := services.NewInventoryService(inventoryRepo, drugRepo)
+
+	expiry := thaiToday().AddDate(1, 0, 0)
+	inventoryRepo.On("GetLocation", uint(1), "tenant_test").Return(mainPharmacy(), nil)
+	drugRepo.On("GetByID", uint(5), "tenant_test").Return(drugWithID(5, "NAP250", "nsaid"), nil)
+	inventoryRepo.On("Receive", mock.MatchedBy(func(r *domain.GoodsReceipt) bool {
+		return r.LocationID == 1 && r.Supplier == "Siam Pharma" && r.ReceivedBy == testActor.StaffID &&
+			len(r.Items) == 1 && r.Items[0].LotNumber == "N2401" && r.Items[0].ExpiryDate.Equal(expiry)
+	}), "tenant_test").Return(nil)
+
+	receipt, err := service.Receive(&domain.GoodsReceiptRequest{
+		LocationID:      1,
+		Supplier:        " Siam Pharma ",
+		ReferenceNumber: "INV-2025-0113",
+		Items: []domain.GoodsReceiptItemRequest{
+			{DrugID: 5, LotNumber: " N2401 ", ExpiryDate: expiry.Format(domain.DateFormat), Quantity: 100},
+		},
+	}, testActor, "tenant_test")
+
+	assert.NoError(t, err)
+	assert.Len(t, receipt.Items, 1)
+	inventoryRepo.AssertExpectations(t)
+}
+
+func TestInventoryService_Receive_InvalidInput(t *testing.T) {
+	nextYear := thaiToday().AddDate(1, 0, 0).Format(domain.DateFormat)
+	yesterday := thaiToday().AddDate(0, 0, -1).Format(domain.DateFormat)
+
+	tests := []struct {
+		name     string
+		location *domain.StockLocation
+		items    []domain.GoodsReceiptItemRequest
+	}{
+		{
+			name:     "inactive location",
+			location: &domain.StockLocation{Code: "WARD3"},
+			items:    []domain.GoodsReceiptItemRequest{{DrugID: 5, LotNumber: "N2401", ExpiryDate: nextYear, Quantity: 10}},
+		},
+		{
+			name:     "expired lot",
+			location: mainPharmacy(),
+			items:    []domain.GoodsReceiptItemRequest{{DrugID: 5, LotNumber: "N2401", ExpiryDate: yesterday, Quantity: 10}},
+		},
+		{
+			name:     "lot listed twice",
+			location: mainPharmacy(),
+			items: []domain.GoodsReceiptItemRequest{
+				{DrugID: 5, LotNumber: "N2401", ExpiryDate: nextYear, Quantity: 10},
+				{DrugID: 5, LotNumber: "N2401 ", ExpiryDate: nextYear, Quantity: 5},
+			},
+		},
+		{
+			name:     "bad expiry date",
+			location: mainPharmacy(),
+			items:    []domain.GoodsReceiptItemRequest{{DrugID: 5, LotNumber: "N2401", ExpiryDate: "31/12/2030", Quantity: 10}},
+		},
+		{
+			name:     "unknown drug",
+			location: mainPharmacy(),
+			items:    []domain.GoodsReceiptItemRequest{{DrugID: 99, LotNumber: "X1", ExpiryDate: nextYear, Quantity: 10}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inventoryRepo := mocks.NewMockInventoryRepository()
+			drugRepo := mocks.NewMockDrugRepository()
+			service := services.NewInventoryService(inventoryRepo, drugRepo)
+			inventoryRepo.On("GetLocation", uint(1), "tenant_test").Return(tt.location, nil)
+			drugRepo.On("GetByID", uint(5), "tenant_test").Return(drugWithID(5, "NAP250", "nsaid"), nil).Maybe()
+			drugRepo.On("GetByID", uint(99), "tenant_test").Return(nil, domain.ErrNotFound).Maybe()
+
+			receipt, err := service.Receive(&domain.GoodsReceiptRequest{LocationID: 1, Items: tt.items}, testActor, "tenant_test")
+
+			assert.True(t, errors.Is(err, domain.ErrInvalidInput))
+			assert.Nil(t, receipt)
+			inventoryRepo.AssertNotCalled(t, "Receive", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestInventoryService_Adjust(t *testing.T) {
+	tests := []struct {
+		name      string
+		getErr    error
+		adjustErr error
+		wantErr   error
+	}{
+		{name: "write off"},
+		{name: "unknown lot", getErr: domain.ErrNotFound, wantErr: domain.ErrInvalidInput},
+		{name: "below zero", adjustErr: domain.ErrInvalidInput, wantErr: domain.ErrInvalidInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inventoryRepo := mocks.NewMockInventoryRepository()
+			service := services.NewInventoryService(inventoryRepo, mocks.NewMockDrugRepository())
+			var lot *domain.StockLot
+			if tt.getErr == nil {
+				lot = &domain.StockLot{ID: 7, LocationID: 1, DrugID: 5, LotNumber: "N2401", Quantity: 12}
+			}
+			inventoryRepo.On("GetLot", uint(7), "tenant_test").Return(lot, tt.getErr)
+			inventoryRepo.On("Adjust", mock.MatchedBy(func(m *domain.StockMovement) bool {
+				return m.LotID == 7 && m.Type == domain.StockMovementAdjustment && m.Quantity == -2 &&
+					m.Reason == "broken bottles" && m.PerformedBy == testActor.StaffID
+			}), "tenant_test").Return(tt.adjustErr).Maybe()
+
+			movement, err := service.Adjust(&domain.StockAdjustmentRequest{LotID: 7, Quantity: -2, Reason: " broken bottles "}, testActor, "tenant_test")
+
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr))
+				assert.Nil(t, movement)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, float64(-2), movement.Quantity)
+		})
+	}
+}
+
+func TestInventoryService_StockCard_OpeningBalance(t *testing.T) {
+	inventoryRepo := mocks.NewMockInventoryRepository()
+	drugRepo := mocks.NewMockDrugRepository()
+	service := services.NewInventoryService(inventoryRepo, drugRepo)
+
+	inventoryRepo.On("GetLocation", uint(1), "tenant_test").Return(mainPharmacy(), nil)
+	drugRepo.On("GetByID", uint(5), "tenant_test").Return(drugWithID(5, "NAP250", "nsaid"), nil)
+	inventoryRepo.On("Balance", uint(1), uint(5), mock.MatchedBy(func(before time.Time) bool {
+		return before.Equal(time.Date(2025, 5, 31, 17, 0, 0, 0, time.UTC))
+	}), "tenant_test").Return(float64(40), nil)
+	inventoryRepo.On("ListMovements", mock.MatchedBy(func(f *domain.StockMovementFilter) bool {
+		return f.LocationID == 1 && f.DrugID == 5 && f.From != nil && f.To != nil
+	}), "tenant_test").Return([]domain.StockMovement{{Type: domain.StockMovementDispense, Quantity: -15}}, nil)
+
+	card, err := service.StockCard(&domain.StockCardRequest{LocationID: 1, DrugID: 5, From: "2025-06-01", To: "2025-06-30"}, "tenant_test")
+
+	assert.NoError(t, err)
+	assert.Equal(t, float64(40), card.OpeningBalance)
+	assert.Equal(t, float64(25), card.ClosingBalance)
+}
+
+func TestInventoryService_Alerts(t *testing.T) {
+	inventoryRepo := mocks.NewMockInventoryRepository()
+	service := services.NewInventoryService(inventoryRepo, mocks.NewMockDrugRepository())
+
+	today := thaiToday()
+	inventoryRepo.On("LowStock", (*uint)(nil), today, "tenant_test").Return(nil, nil)
+	inventoryRepo.On("NearExpiry", (*uint)(nil), today.AddDate(0, 0, 31), "tenant_test").Return([]domain.NearExpiryAlert{
+		{LotNumber: "OLD", ExpiryDate: today.AddDate(0, 0, -3)},
+		{LotNumber: "SOON", ExpiryDate: today.AddDate(0, 0, 30)},
+	}, nil)
+
+	alerts, err := service.Alerts(&domain.StockAlertRequest{WithinDays: 30}, "tenant_test")
+
+	assert.NoError(t, err)
+	assert.NotNil(t, alerts.LowStock)
+	assert.Empty(t, alerts.LowStock)
+	if assert.Len(t, alerts.NearExpiry, 2) {
+		assert.Equal(t, -3, alerts.NearExpiry[0].DaysToExpiry)
+		assert.True(t, alerts.NearExpiry[0].Expired)
+		assert.Equal(t, 30, alerts.NearExpiry[1].DaysToExpiry)
+		assert.False(t, alerts.NearExpiry[1].Expired)
+	}
+}
@@ -0,0 +1,374 @@
+package services_test
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/infrastructure/analyzer"
+	"github.com/wichai2002/his_v1/internal/mocks"
+	"github.com/wichai2002/his_v1/internal/services"
+)
+
+type labMocks struct {
+	labRepo        *mocks.MockLabRepository
+	encounterRepo  *mocks.MockEncounterRepository
+	patientRepo    *mocks.MockPatientRepository
+	auditRepo      *mocks.MockAuditRepository
+	analyzerSource *mocks.MockAnalyzerSource
+}
+
+func newLabService() (domain.LabService, *labMocks) {
+	m := &labMocks{
+		labRepo:        mocks.NewMockLabRepository(),
+		encounterRepo:  mocks.NewMockEncounterRepository(),
+		patientRepo:    mocks.NewMockPatientRepository(),
+		auditRepo:      mocks.NewMockAuditRepository(),
+		analyzerSource: mocks.NewMockAnalyzerSource(),
+	}
+	return services.NewLabService(m.labRepo, m.encounterRepo, m.patientRepo, m.auditRepo, m.analyzerSource), m
+}
+
+// labOrderVersion is the UpdatedAt of orders returned by labOrder
+var labOrderVersion = time.Date(2025, 6, 1, 2, 30, 0, 0, time.UTC)
+
+func labTest(id uint, code string, specimenType string, unit string, low float64, high float64) domain.LabTest {
+	test := domain.LabTest{
+		Code:            code,
+		Name:            code,
+		SpecimenType:    specimenType,
+		ResultType:      domain.LabResultTypeNumeric,
+		Unit:            unit,
+		ReferenceRanges: domain.LabReferenceRanges{{Low: &low, High: &high}},
+		IsActive:        true,
+	}
+	test.ID = id
+	return test
+}
+
+// labOrder is an order of patient 1 for HB and GLU on one blood specimen with the given status
+func labOrder(specimenStatus string) *domain.LabOrder {
+	order := &domain.LabOrder{
+		EncounterID: 12,
+		PatientID:   1,
+		Priority:    domain.LabPriorityRoutine,
+		Status:      domain.LabOrderStatusOrdered,
+		Specimens: []domain.LabSpecimen{
+			{ID: 1, OrderID: 30, Barcode: "25060100001", SpecimenType: domain.LabSpecimenBlood, Status: specimenStatus},
+		},
+		Items: []domain.LabOrderItem{
+			{ID: 101, OrderID: 30, SpecimenID: 1, TestID: 3, TestCode: "HB", ResultType: domain.LabResultTypeNumeric, Unit: "g/dL", Status: domain.LabItemStatusPending},
+			{ID: 102, OrderID: 30, SpecimenID: 1, TestID: 4, TestCode: "GLU", ResultType: domain.LabResultTypeNumeric, Unit: "mg/dL", Status: domain.LabItemStatusPending},
+		},
+	}
+	order.ID = 30
+	order.UpdatedAt = labOrderVersion
+	order.RefreshStatus(labOrderVersion)
+	return order
+}
+
+func TestLabService_Create(t *testing.T) {
+	service, m := newLabService()
+
+	m.encounterRepo.On("GetByID", uint(12), "tenant_test").Return(openEncounter(), nil)
+	m.labRepo.On("ListTestsByIDs", []uint{3, 7, 4}, "tenant_test").Return([]domain.LabTest{
+		labTest(4, "GLU", domain.LabSpecimenBlood, "mg/dL", 70, 99),
+		labTest(3, "HB", domain.LabSpecimenBlood, "g/dL", 12, 17),
+		labTest(7, "UPROT", domain.LabSpecimenUrine, "mg/dL", 0, 15),
+	}, nil)
+	m.labRepo.On("CreateOrder", mock.MatchedBy(func(o *domain.LabOrder) bool {
+		return o.EncounterID == 12 && o.PatientID == 1 && o.Priority == domain.LabPriorityRoutine &&
+			o.Status == domain.LabOrderStatusOrdered && o.OrderedBy == testActor.StaffID &&
+			len(o.Specimens) == 2 && o.Specimens[0].SpecimenType == domain.LabSpecimenBlood && o.Specimens[1].SpecimenType == domain.LabSpecimenUrine &&
+			len(o.Items) == 3 && o.Items[0].TestCode == "HB" && o.Items[1].SpecimenType == domain.LabSpecimenUrine && o.Items[2].Unit == "mg/dL"
+	}), mock.MatchedBy(func(e *domain.AuditEvent) bool {
+		return e.Action == domain.AuditActionPatientLabOrder && *e.PatientID == 1
+	}), "tenant_test").Return(nil)
+
+	order, err := service.Create(12, &domain.LabOrderRequest{TestIDs: []uint{3, 7, 4}}, testActor, "tenant_test")
+
+	assert.NoError(t, err)
+	assert.Len(t, order.Specimens, 2)
+	m.labRepo.AssertExpectations(t)
+}
+
+func TestLabService_Create_InvalidInput(t *testing.T) {
+	inactive := labTest(5, "ESR", domain.LabSpecimenBlood, "mm/hr", 0, 20)
+	inactive.IsActive = false
+	doneEncounter := openEncounter()
+	doneEncounter.Status = domain.EncounterStatusDone
+
+	tests := []struct {
+		name      string
+		encounter *domain.Encounter
+		testIDs   []uint
+	}{
+		{name: "encounter done", encounter: doneEncounter, testIDs: []uint{3}},
+		{name: "test listed twice", encounter: openEncounter(), testIDs: []uint{3, 3}},
+		{name: "unknown test", encounter: openEncounter(), testIDs: []uint{3, 99}},
+		{name: "inactive test", encounter: openEncounter(), testIDs: []uint{5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, m := newLabService()
+			m.encounterRepo.On("GetByID", uint(12), "tenant_test").Return(tt.encounter, nil)
+			m.labRepo.On("ListTestsByIDs", mock.Anything, "tenant_test").Return([]domain.LabTest{
+				labTest(3, "HB", domain.LabSpecimenBlood, "g/dL", 12, 17), inactive,
+			}, nil).Maybe()
+
+			order, err := service.Create(12, &domain.LabOrderRequest{TestIDs: tt.testIDs}, testActor, "tenant_test")
+
+			assert.True(t, errors.Is(err, domain.ErrInvalidInput))
+			assert.Nil(t, order)
+			m.labRepo.AssertNotCalled(t, "CreateOrder", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestLabService_MoveSpecimen(t *testing.T) {
+	resulted := labOrder(domain.LabSpecimenStatusReceived)
+	resulted.Items[0].Status = domain.LabItemStatusResulted
+	cancelled := labOrder(domain.LabSpecimenStatusPending)
+	cancelled.Status = domain.LabOrderStatusCancelled
+
+	tests := []struct {
+		name           string
+		order          *domain.LabOrder
+		move           func(s domain.LabService) (*domain.LabOrder, error)
+		expectedStatus string
+		wantErr        error
+	}{
+		{
+			name:  "collect",
+			order: labOrder(domain.LabSpecimenStatusPending),
+			move: func(s domain.LabService) (*domain.LabOrder, error) {
+				return s.Collect("25060100001", testActor, "tenant_test")
+			},
+			expectedStatus: domain.LabOrderStatusCollected,
+		},
+		{
+			name:  "receive",
+			order: labOrder(domain.LabSpecimenStatusCollected),
+			move: func(s domain.LabService) (*domain.LabOrder, error) {
+				return s.Receive("25060100001", testActor, "tenant_test")
+			},
+			expectedStatus: domain.LabOrderStatusReceived,
+		},
+		{
+			name:  "reject",
+			order: labOrder(domain.LabSpecimenStatusReceived),
+			move: func(s domain.LabService) (*domain.LabOrder, error) {
+				return s.Reject("25060100001", &domain.LabSpecimenRejectRequest{Reason: "hemolysed"}, testActor, "tenant_test")
+			},
+			expectedStatus: domain.LabOrderStatusOrdered,
+		},
+		{
+			name:  "receive before collection",
+			order: labOrder(domain.LabSpecimenStatusPending),
+			move: func(s domain.LabService) (*domain.LabOrder, error) {
+				return s.Receive("25060100001", testActor, "tenant_test")
+			},
+			wantErr: domain.ErrInvalidStatusTransition,
+		},
+		{
+			name:  "reject after results",
+			order: resulted,
+			move: func(s domain.LabService) (*domain.LabOrder, error) {
+				return s.Reject("25060100001", &domain.LabSpecimenRejectRequest{Reason: "clotted"}, testActor, "tenant_test")
+			},
+			wantErr: domain.ErrInvalidStatusTransition,
+		},
+		{
+			name:  "cancelled order",
+			order: cancelled,
+			move: func(s domain.LabService) (*domain.LabOrder, error) {
+				return s.Collect("25060100001", testActor, "tenant_test")
+			},
+			wantErr: domain.ErrInvalidStatusTransition,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, m := newLabService()
+			m.labRepo.On("GetSpecimenByBarcode", "25060100001", "tenant_test").Return(&tt.order.Specimens[0], nil)
+			m.labRepo.On("GetOrder", uint(30), "tenant_test").Return(tt.order, nil)
+			m.labRepo.On("SaveOrder", mock.AnythingOfType("*domain.LabOrder"), labOrderVersion, mock.MatchedBy(func(s []*domain.LabSpecimen) bool {
+				return len(s) == 1 && s[0].Barcode == "25060100001"
+			}), ([]*domain.LabOrderItem)(nil), mock.MatchedBy(func(e *domain.AuditEvent) bool {
+				return e.Action == domain.AuditActionPatientLabSpecimen
+			}), "tenant_test").Return(nil).Maybe()
+
+			order, err := tt.move(service)
+
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr))
+				m.labRepo.AssertNotCalled(t, "SaveOrder", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, order.Status)
+			m.labRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLabService_Cancel_AfterReceipt(t *testing.T) {
+	service, m := newLabService()
+	m.labRepo.On("GetOrder", uint(30), "tenant_test").Return(labOrder(domain.LabSpecimenStatusReceived), nil)
+
+	order, err := service.Cancel(30, &domain.LabCancelRequest{Reason: "ordered in error"}, testActor, "tenant_test")
+
+	assert.True(t, errors.Is(err, domain.ErrInvalidStatusTransition))
+	assert.Nil(t, order)
+}
+
+func TestLabService_RecordResults(t *testing.T) {
+	tests := []struct {
+		name           string
+		specimenStatus string
+		results        []domain.LabResultEntry
+		expectedStatus string
+		wantErr        error
+	}{
+		{
+			name:           "all resulted",
+			specimenStatus: domain.LabSpecimenStatusReceived,
+			results:        []domain.LabResultEntry{{ItemID: 101, Value: "11.2", Unit: "g/dL"}, {ItemID: 102, Value: "85"}},
+			expectedStatus: domain.LabOrderStatusCompleted,
+		},
+		{
+			name:           "one of two",
+			specimenStatus: domain.LabSpecimenStatusReceived,
+			results:        []domain.LabResultEntry{{ItemID: 102, Value: "85"}},
+			expectedStatus: domain.LabOrderStatusReceived,
+		},
+		{
+			name:           "specimen not received",
+			specimenStatus: domain.LabSpecimenStatusCollected,
+			results:        []domain.LabResultEntry{{ItemID: 101, Value: "11.2"}},
+			wantErr:        domain.ErrInvalidStatusTransition,
+		},
+		{
+			name:           "wrong unit",
+			specimenStatus: domain.LabSpecimenStatusReceived,
+			results:        []domain.LabResultEntry{{ItemID: 102, Value: "4.7", Unit: "mmol/L"}},
+			wantErr:        domain.ErrInvalidInput,
+		},
+		{
+			name:           "item of another order",
+			specimenStatus: domain.LabSpecimenStatusReceived,
+			results:        []domain.LabResultEntry{{ItemID: 999, Value: "1"}},
+			wantErr:        domain.ErrInvalidInput,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, m := newLabService()
+			m.labRepo.On("GetOrder", uint(30), "tenant_test").Return(labOrder(tt.specimenStatus), nil)
+			m.patientRepo.On("GetByID", uint(1), "tenant_test").Return(adultPatient(), nil)
+			hb := labTest(3, "HB", domain.LabSpecimenBlood, "g/dL", 12, 17)
+			glu := labTest(4, "GLU", domain.LabSpecimenBlood, "mg/dL", 70, 99)
+			m.labRepo.On("GetTest", uint(3), "tenant_test").Return(&hb, nil).Maybe()
+			m.labRepo.On("GetTest", uint(4), "tenant_test").Return(&glu, nil).Maybe()
+			m.labRepo.On("SaveOrder", mock.AnythingOfType("*domain.LabOrder"), labOrderVersion, ([]*domain.LabSpecimen)(nil),
+				mock.MatchedBy(func(items []*domain.LabOrderItem) bool { return len(items) == len(tt.results) }),
+				mock.MatchedBy(func(e *domain.AuditEvent) bool { return e.Action == domain.AuditActionPatientLabResult }),
+				"tenant_test").Return(nil).Maybe()
+
+			order, err := service.RecordResults(30, &domain.LabResultRequest{Results: tt.results}, testActor, "tenant_test")
+
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr))
+				m.labRepo.AssertNotCalled(t, "SaveOrder", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, order.Status)
+			glucose := order.Item(102)
+			assert.Equal(t, domain.LabFlagNormal, glucose.Flag)
+			assert.Equal(t, domain.LabResultSourceManual, glucose.ResultSource)
+			if tt.expectedStatus == domain.LabOrderStatusCompleted {
+				assert.Equal(t, domain.LabFlagLow, order.Item(101).Flag)
+				assert.Equal(t, "12-17", order.Item(101).ReferenceRange)
+			}
+		})
+	}
+}
+
+func TestLabService_ImportFromAnalyzer_FileDrop(t *testing.T) {
+	dir := t.TempDir()
+	inbox := filepath.Join(dir, "tenant_test", "inbox")
+	assert.NoError(t, os.MkdirAll(inbox, 0o750))
+	assert.NoError(t, os.WriteFile(filepath.Join(inbox, "cobas_20250601_0930.csv"), []byte(
+		"barcode,test_code,value,unit,resulted_at\n"+
+			"25060100001,GLU,182,mg/dL,2025-06-01T09:28:00+07:00\n"+
+			"25060100001,CREA,1.1,mg/dL,\n"+
+			"25060199999,GLU,90,mg/dL,\n"), 0o640))
+	assert.NoError(t, os.WriteFile(filepath.Join(inbox, "sysmex_20250601_0931.csv"), []byte("barcode;test;value\n"), 0o640))
+
+	m := &labMocks{
+		labRepo:     mocks.NewMockLabRepository(),
+		patientRepo: mocks.NewMockPatientRepository(),
+	}
+	service := services.NewLabService(m.labRepo, mocks.NewMockEncounterRepository(), m.patientRepo, mocks.NewMockAuditRepository(), analyzer.NewFileDropSource(dir))
+
+	m.labRepo.On("GetSpecimenByBarcode", "25060100001", "tenant_test").Return(&domain.LabSpecimen{ID: 1, OrderID: 30, Barcode: "25060100001"}, nil)
+	m.labRepo.On("GetSpecimenByBarcode", "25060199999", "tenant_test").Return(nil, domain.ErrNotFound)
+	m.labRepo.On("GetOrder", uint(30), "tenant_test").Return(labOrder(domain.LabSpecimenStatusReceived), nil)
+	m.patientRepo.On("GetByID", uint(1), "tenant_test").Return(adultPatient(), nil)
+	glu := labTest(4, "GLU", domain.LabSpecimenBlood, "mg/dL", 70, 99)
+	m.labRepo.On("GetTest", uint(4), "tenant_test").Return(&glu, nil)
+	m.labRepo.On("SaveOrder", mock.AnythingOfType("*domain.LabOrder"), labOrderVersion, ([]*domain.LabSpecimen)(nil),
+		mock.MatchedBy(func(items []*domain.LabOrderItem) bool {
+			return len(items) == 1 && items[0].Value == "182" && items[0].Flag == domain.LabFlagHigh &&
+				items[0].ResultSource == domain.LabResultSourceAnalyzer && items[0].Instrument == "cobas" &&
+				items[0].ResultedAt.Equal(time.Date(2025, 6, 1, 2, 28, 0, 0, time.UTC))
+		}), mock.AnythingOfType("*domain.AuditEvent"), "tenant_test").Return(nil).Once()
+
+	summary, err := service.ImportFromAnalyzer(testActor, "tenant_test")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, summary.Imported)
+	assert.Equal(t, 2, summary.Rejected)
+	if assert.Len(t, summary.Batches, 2) {
+		cobas := summary.Batches[0]
+		if assert.Len(t, cobas.Rejected, 2) {
+			assert.Equal(t, 3, cobas.Rejected[0].Line)
+			assert.Contains(t, cobas.Rejected[0].Reason, "test CREA was not ordered")
+			assert.Contains(t, cobas.Rejected[1].Reason, "specimen 25060199999 does not exist")
+		}
+		assert.NotEmpty(t, summary.Batches[1].Error)
+	}
+	m.labRepo.AssertExpectations(t)
+
+	remaining, _ := os.ReadDir(inbox)
+	assert.Empty(t, remaining)
+	assert.FileExists(t, filepath.Join(dir, "tenant_test", "processed", "cobas_20250601_0930.csv"))
+	assert.FileExists(t, filepath.Join(dir, "tenant_test", "failed", "sysmex_20250601_0931.csv"))
+	raw, err := os.ReadFile(filepath.Join(dir, "tenant_test", "processed", "cobas_20250601_0930.csv.report.json"))
+	if assert.NoError(t, err) {
+		var report domain.LabImportBatchReport
+		assert.NoError(t, json.Unmarshal(raw, &report))
+		assert.Equal(t, 1, report.Imported)
+	}
+}
+
+func TestLabService_ImportFromAnalyzer_Unavailable(t *testing.T) {
+	service, m := newLabService()
+	m.analyzerSource.On("Fetch", "tenant_test").Return(nil, errors.New("permission denied"))
+
+	summary, err := service.ImportFromAnalyzer(testActor, "tenant_test")
+
+	assert.True(t, errors.Is(err, domain.ErrAnalyzerUnavailable))
+	assert.Nil(t, summary)
+	m.analyzerSource.AssertNotCalled(t, "Complete", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,314 @@
+package services_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/mocks"
+	"github.com/wichai2002/his_v1/internal/services"
+)
+
+type medicationMocks struct {
+	orderRepo     *mocks.MockMedicationOrderRepository
+	drugRepo      *mocks.MockDrugRepository
+	encounterRepo *mocks.MockEncounterRepository
+	patientRepo   *mocks.MockPatientRepository
+	allergyRepo   *mocks.MockPatientAllergyRepository
+	inventoryRepo *mocks.MockInventoryRepository
+	auditRepo     *mocks.MockAuditRepository
+}
+
+func newMedicationOrderService() (domain.MedicationOrderService, *medicationMocks) {
+	m := &medicationMocks{
+		orderRepo:     mocks.NewMockMedicationOrderRepository(),
+		drugRepo:      mocks.NewMockDrugRepository(),
+		encounterRepo: mocks.NewMockEncounterRepository(),
+		patientRepo:   mocks.NewMockPatientRepository(),
+		allergyRepo:   mocks.NewMockPatientAllergyRepository(),
+		inventoryRepo: mocks.NewMockInventoryRepository(),
+		auditRepo:     mocks.NewMockAuditRepository(),
+	}
+	return services.NewMedicationOrderService(m.orderRepo, m.drugRepo, m.encounterRepo, m.patientRepo, m.allergyRepo, m.inventoryRepo, m.auditRepo), m
+}
+
+func ibuprofenOrderRequest() *domain.MedicationOrderRequest {
+	return &domain.MedicationOrderRequest{
+		DrugID:       4,
+		Dose:         1,
+		DoseUnit:     "tab",
+		Route:        domain.MedicationRouteOral,
+		Frequency:    "tid",
+		DurationDays: 5,
+	}
+}
+
+func activeOrder(id uint, drugID uint, drugName string, class string) domain.MedicationOrder {
+	order := domain.MedicationOrder{PatientID: 1, DrugID: drugID, DrugName: drugName, TherapeuticClass: class, Status: domain.MedicationStatusDispensed}
+	order.ID = id
+	return order
+}
+
+func TestMedicationOrderService_Create(t *testing.T) {
+	service, m := newMedicationOrderService()
+
+	m.encounterRepo.On("GetByID", uint(12), "tenant_test").Return(openEncounter(), nil)
+	m.drugRepo.On("GetByID", uint(4), "tenant_test").Return(drugWithID(4, "IBU400", "nsaid"), nil)
+	m.allergyRepo.On("ListByPatient", uint(1), "tenant_test").Return([]domain.PatientAllergy{{AllergenType: domain.AllergenTypeFood, Allergen: "Shrimp"}}, nil)
+	m.orderRepo.On("ListByPatient", uint(1), mock.AnythingOfType("*time.Time"), "tenant_test").Return([]domain.MedicationOrder{}, nil)
+	m.orderRepo.On("Create", mock.MatchedBy(func(o *domain.MedicationOrder) bool {
+		return o.EncounterID == 12 && o.PatientID == 1 && o.DrugID == 4 && o.Status == domain.MedicationStatusOrdered &&
+			o.Quantity == 15 && o.DispensingUnit == "tab" && o.OrderedBy == testActor.StaffID &&
+			o.EndsAt.Sub(o.OrderedAt) == 5*24*time.Hour && len(o.Alerts) == 0
+	}), mock.MatchedBy(func(events []*domain.AuditEvent) bool {
+		return len(events) == 1 && events[0].Action == domain.AuditActionPatientMedicationOrder
+	}), "tenant_test").Return(nil)
+
+	order, err := service.Create(12, ibuprofenOrderRequest(), testActor, "tenant_test")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "nsaid", order.TherapeuticClass)
+	m.orderRepo.AssertExpectations(t)
+}
+
+func TestMedicationOrderService_Create_Alerts(t *testing.T) {
+	service, m := newMedicationOrderService()
+
+	m.encounterRepo.On("GetByID", uint(12), "tenant_test").Return(openEncounter(), nil)
+	m.drugRepo.On("GetByID", uint(4), "tenant_test").Return(drugWithID(4, "IBU400", "nsaid"), nil)
+	allergy := domain.PatientAllergy{AllergenType: domain.AllergenTypeDrug, Allergen: "NSAIDs", Severity: domain.AllergySeveritySevere, Certainty: domain.AllergyCertaintyConfirmed}
+	allergy.ID = 3
+	m.allergyRepo.On("ListByPatient", uint(1), "tenant_test").Return([]domain.PatientAllergy{allergy}, nil)
+	m.orderRepo.On("ListByPatient", uint(1), mock.AnythingOfType("*time.Time"), "tenant_test").Return([]domain.MedicationOrder{
+		activeOrder(20, 5, "Naproxen 250 mg", "nsaid"),
+		activeOrder(21, 9, "Warfarin 3 mg", "anticoagulant"),
+	}, nil)
+	interaction := domain.DrugInteraction{DrugID: 4, InteractingDrugID: 9, Severity: domain.InteractionSeverityMajor, Description: "increased bleeding risk"}
+	m.drugRepo.On("ListInteractions", uint(4), []uint{5, 9}, "tenant_test").Return([]domain.DrugInteraction{interaction}, nil)
+
+	_, err := service.Create(12, ibuprofenOrderRequest(), testActor, "tenant_test")
+
+	var alertErr *domain.MedicationAlertError
+	assert.True(t, errors.As(err, &alertErr))
+	assert.True(t, errors.Is(err, domain.ErrUnacknowledgedAlerts))
+	assert.Len(t, alertErr.Alerts, 3)
+	assert.Equal(t, domain.MedicationAlertAllergy, alertErr.Alerts[0].Type)
+	assert.Equal(t, uint(3), *alertErr.Alerts[0].AllergyID)
+	assert.Equal(t, domain.MedicationAlertDuplicateClass, alertErr.Alerts[1].Type)
+	assert.Equal(t, uint(20), *alertErr.Alerts[1].RelatedOrderID)
+	assert.Equal(t, domain.MedicationAlertInteraction, alertErr.Alerts[2].Type)
+	assert.Equal(t, domain.InteractionSeverityMajor, alertErr.Alerts[2].Severity)
+	assert.Equal(t, uint(21), *alertErr.Alerts[2].RelatedOrderID)
+	m.orderRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMedicationOrderService_Create_AcknowledgedAlerts(t *testing.T) {
+	setup := func() (domain.MedicationOrderService, *medicationMocks) {
+		service, m := newMedicationOrderService()
+		m.encounterRepo.On("GetByID", uint(12), "tenant_test").Return(openEncounter(), nil)
+		m.drugRepo.On("GetByID", uint(4), "tenant_test").Return(drugWithID(4, "IBU400", "nsaid"), nil)
+		m.allergyRepo.On("ListByPatient", uint(1), "tenant_test").Return([]domain.PatientAllergy{}, nil)
+		m.orderRepo.On("ListByPatient", uint(1), mock.AnythingOfType("*time.Time"), "tenant_test").
+			Return([]domain.MedicationOrder{activeOrder(20, 5, "Naproxen 250 mg", "nsaid")}, nil)
+		m.drugRepo.On("ListInteractions", uint(4), []uint{5}, "tenant_test").Return([]domain.DrugInteraction{}, nil)
+		return service, m
+	}
+
+	t.Run("reason required", func(t *testing.T) {
+		service, m := setup()
+		req := ibuprofenOrderRequest()
+		req.AcknowledgeAlerts = true
+
+		_, err := service.Create(12, req, testActor, "tenant_test")
+
+		assert.True(t, errors.Is(err, domain.ErrInvalidInput))
+		m.orderRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("override audited", func(t *testing.T) {
+		service, m := setup()
+		m.orderRepo.On("Create", mock.MatchedBy(func(o *domain.MedicationOrder) bool {
+			return len(o.Alerts) == 1 && o.OverrideReason == "naproxen stopped today"
+		}), mock.MatchedBy(func(events []*domain.AuditEvent) bool {
+			return len(events) == 2 && events[1].Action == domain.AuditActionPatientMedicationAlertOverride
+		}), "tenant_test").Return(nil)
+		req := ibuprofenOrderRequest()
+		req.AcknowledgeAlerts = true
+		req.OverrideReason = " naproxen stopped today "
+
+		_, err := service.Create(12, req, testActor, "tenant_test")
+
+		assert.NoError(t, err)
+		m.orderRepo.AssertExpectations(t)
+	})
+}
+
+func TestMedicationOrderService_Create_InvalidInput(t *testing.T) {
+	tests := []struct {
+		name  string
+		req   func() *domain.MedicationOrderRequest
+		setup func(m *medicationMocks)
+	}{
+		{
+			name: "encounter done",
+			req:  ibuprofenOrderRequest,
+			setup: func(m *medicationMocks) {
+				encounter := openEncounter()
+				encounter.Status = domain.EncounterStatusDone
+				m.encounterRepo.On("GetByID", uint(12), "tenant_test").Return(encounter, nil)
+			},
+		},
+		{
+			name: "inactive drug",
+			req:  ibuprofenOrderRequest,
+			setup: func(m *medicationMocks) {
+				drug := drugWithID(4, "IBU400", "nsaid")
+				drug.IsActive = false
+				m.encounterRepo.On("GetByID", uint(12), "tenant_test").Return(openEncounter(), nil)
+				m.drugRepo.On("GetByID", uint(4), "tenant_test").Return(drug, nil)
+			},
+		},
+		{
+			name: "quantity cannot be computed",
+			req: func() *domain.MedicationOrderRequest {
+				req := ibuprofenOrderRequest()
+				req.Dose, req.DoseUnit = 400, "mg"
+				return req
+			},
+			setup: func(m *medicationMocks) {
+				m.encounterRepo.On("GetByID", uint(12), "tenant_test").Return(openEncounter(), nil)
+				m.drugRepo.On("GetByID", uint(4), "tenant_test").Return(drugWithID(4, "IBU400", "nsaid"), nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, m := newMedicationOrderService()
+			tt.setup(m)
+
+			_, err := service.Create(12, tt.req(), testActor, "tenant_test")
+
+			assert.True(t, errors.Is(err, domain.ErrInvalidInput))
+			m.orderRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestMedicationOrderService_Verify(t *testing.T) {
+	service, m := newMedicationOrderService()
+	order := activeOrder(20, 5, "Naproxen 250 mg", "nsaid")
+	order.Status = domain.MedicationStatusOrdered
+	m.orderRepo.On("GetByID", uint(20), "tenant_test").Return(&order, nil)
+	m.orderRepo.On("UpdateStatus", mock.MatchedBy(func(o *domain.MedicationOrder) bool {
+		return o.Status == domain.MedicationStatusVerified && *o.VerifiedBy == testActor.StaffID && o.VerifiedAt != nil
+	}), domain.MedicationStatusOrdered, mock.MatchedBy(func(e *domain.AuditEvent) bool {
+		return e.Action == domain.AuditActionPatientMedicationStatus
+	}), "tenant_test").Return(nil)
+
+	verified, err := service.Verify(20, testActor, "tenant_test")
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.MedicationStatusVerified, verified.Status)
+	m.orderRepo.AssertExpectations(t)
+}
+
+func TestMedicationOrderService_Dispense(t *testing.T) {
+	tests := []struct {
+		name        string
+		location    *domain.StockLocation
+		dispenseErr error
+		wantErr     error
+	}{
+		{name: "takes stock", location: mainPharmacy()},
+		{name: "insufficient stock", location: mainPharmacy(), dispenseErr: fmt.Errorf("%w: 4 available, 15 needed", domain.ErrInsufficientStock), wantErr: domain.ErrInsufficientStock},
+		{name: "inactive location", location: &domain.StockLocation{Code: "WARD3", IsActive: false}, wantErr: domain.ErrInvalidInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, m := newMedicationOrderService()
+			order := activeOrder(20, 5, "Naproxen 250 mg", "nsaid")
+			order.Status = domain.MedicationStatusVerified
+			m.inventoryRepo.On("GetLocation", uint(1), "tenant_test").Return(tt.location, nil)
+			m.orderRepo.On("GetByID", uint(20), "tenant_test").Return(&order, nil).Maybe()
+			m.inventoryRepo.On("DispenseOrder", mock.MatchedBy(func(o *domain.MedicationOrder) bool {
+				return o.Status == domain.MedicationStatusDispensed && o.DispensedAt != nil
+			}), domain.MedicationStatusVerified, uint(1), mock.AnythingOfType("time.Time"), mock.MatchedBy(func(e *domain.AuditEvent) bool {
+				return e.Action == domain.AuditActionPatientMedicationStatus
+			}), "tenant_test").Return([]domain.StockMovement{{DrugID: 5, LotNumber: "N2401", Type: domain.StockMovementDispense, Quantity: -15}}, tt.dispenseErr).Maybe()
+
+			dispensing, err := service.Dispense(20, &domain.MedicationDispenseRequest{LocationID: 1}, testActor, "tenant_test")
+
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr))
+				assert.Nil(t, dispensing)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, domain.MedicationStatusDispensed, dispensing.Order.Status)
+			assert.Len(t, dispensing.Movements, 1)
+		})
+	}
+}
+
+func TestMedicationOrderService_InvalidTransitions(t *testing.T) {
+	tests := []struct {
+		name   string
+		status string
+		move   func(s domain.MedicationOrderService) error
+	}{
+		{
+			name:   "dispense before verification",
+			status: domain.MedicationStatusOrdered,
+			move: func(s domain.MedicationOrderService) error {
+				_, err := s.Dispense(20, &domain.MedicationDispenseRequest{LocationID: 1}, testActor, "tenant_test")
+				return err
+			},
+		},
+		{
+			name:   "cancel after dispensing",
+			status: domain.MedicationStatusDispensed,
+			move: func(s domain.MedicationOrderService) error {
+				_, err := s.Cancel(20, &domain.MedicationCancelRequest{Reason: "wrong drug"}, testActor, "tenant_test")
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, m := newMedicationOrderService()
+			order := activeOrder(20, 5, "Naproxen 250 mg", "nsaid")
+			order.Status = tt.status
+			m.orderRepo.On("GetByID", uint(20), "tenant_test").Return(&order, nil)
+			m.inventoryRepo.On("GetLocation", uint(1), "tenant_test").Return(mainPharmacy(), nil).Maybe()
+
+			err := tt.move(service)
+
+			assert.True(t, errors.Is(err, domain.ErrInvalidStatusTransition))
+			m.orderRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			m.inventoryRepo.AssertNotCalled(t, "DispenseOrder", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestMedicationOrderService_ListByPatient_ActiveOnly(t *testing.T) {
+	service, m := newMedicationOrderService()
+	m.patientRepo.On("GetByID", uint(1), "tenant_test").Return(adultPatient(), nil)
+	m.orderRepo.On("ListByPatient", uint(1), mock.MatchedBy(func(at *time.Time) bool { return at != nil }), "tenant_test").
+		Return([]domain.MedicationOrder{activeOrder(20, 5, "Naproxen 250 mg", "nsaid")}, nil)
+	m.auditRepo.On("Append", mock.MatchedBy(func(events []*domain.AuditEvent) bool {
+		return len(events) == 1 && events[0].Action == domain.AuditActionPatientMedicationView
+	}), "tenant_test").Return(nil)
+
+	orders, err := service.ListByPatient(1, &domain.MedicationOrderListRequest{ActiveOnly: true}, testActor, "tenant_test")
+
+	assert.NoError(t, err)
+	assert.Len(t, orders, 1)
+	m.orderRepo.AssertExpectations(t)
+}
@@ -0,0 +1,200 @@
+package services_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/mocks"
+	"github.com/wichai2002/his_v1/internal/services"
+	"gorm.io/gorm"
+)
+
+var testLumphini = &domain.AddressLocation{
+	SubdistrictCode: "100704",
+	TambonTH:        "ลุมพินี",
+	AmphoeTH:        "ปทุมวัน",
+	ChangwatTH:      "กรุงเทพมหานคร",
+	PostalCode:      "10330",
+}
+
+func newTestAddressRequest(postalCode string) *domain.PatientAddressRequest {
+	return &domain.PatientAddressRequest{
+		HouseNo:         "99/1",
+		Soi:             "Soi Ruamrudee",
+		Road:            "Witthayu",
+		SubdistrictCode: "100704",
+		PostalCode:      postalCode,
+	}
+}
+
+func TestPatientAddressService_Save(t *testing.T) {
+	tests := []struct {
+		name          string
+		addressType   string
+		postalCode    string
+		location      *domain.AddressLocation
+		locationErr   error
+		existing      *domain.PatientAddress
+		expectedError error
+		expectSave    bool
+	}{
+		{
+			name:        "new registered address",
+			addressType: domain.AddressTypeRegistered,
+			postalCode:  "10330",
+			location:    testLumphini,
+			expectSave:  true,
+		},
+		{
+			name:        "replaces the current address",
+			addressType: domain.AddressTypeCurrent,
+			postalCode:  "10330",
+			location:    testLumphini,
+			existing: &domain.PatientAddress{
+				Model: gorm.Model{ID: 4}, PatientID: 1, AddressType: domain.AddressTypeCurrent,
+				HouseNo: "1", SubdistrictCode: "100101", PostalCode: "10200",
+			},
+			expectSave: true,
+		},
+		{
+			name:          "postal code of another subdistrict",
+			addressType:   domain.AddressTypeRegistered,
+			postalCode:    "10110",
+			location:      testLumphini,
+			expectedError: domain.ErrInvalidInput,
+		},
+		{
+			name:          "unknown subdistrict",
+			addressType:   domain.AddressTypeRegistered,
+			postalCode:    "10330",
+			locationErr:   gorm.ErrRecordNotFound,
+			expectedError: domain.ErrInvalidInput,
+		},
+		{
+			name:          "unknown address type",
+			addressType:   "holiday",
+			postalCode:    "10330",
+			expectedError: domain.ErrInvalidInput,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAddressRepo := mocks.NewMockPatientAddressRepository()
+			mockReferenceRepo := mocks.NewMockAddressReferenceRepository()
+			mockPatientRepo := mocks.NewMockPatientRepository()
+			mockAuditRepo := mocks.NewMockAuditRepository()
+
+			mockReferenceRepo.On("GetLocation", "100704").Return(tt.location, tt.locationErr)
+			mockPatientRepo.On("GetByID", uint(1), "tenant_test").Return(&domain.Patient{}, nil)
+			mockAddressRepo.On("GetByType", uint(1), tt.addressType, "tenant_test").Return(tt.existing, nil)
+			mockAddressRepo.On("Save", mock.MatchedBy(func(a *domain.PatientAddress) bool {
+				// Names and postal code are copied from the reference dataset
+				return a.PatientID == 1 && a.AddressType == tt.addressType &&
+					a.Tambon == "ลุมพินี" && a.Changwat == "กรุงเทพมหานคร" && a.PostalCode == "10330"
+			}), mock.MatchedBy(func(e *domain.AuditEvent) bool {
+				var changes map[string]domain.FieldChange
+				if err := json.Unmarshal([]byte(e.Changes), &changes); err != nil {
+					return false
+				}
+				_, hasType := changes["address_type"]
+				return e.Action == domain.AuditActionPatientAddressSave && hasType
+			}), "tenant_test").Return(nil)
+
+			service := services.NewPatientAddressService(mockAddressRepo, mockReferenceRepo, mockPatientRepo, mockAuditRepo)
+			address, err := service.Save(1, tt.addressType, newTestAddressRequest(tt.postalCode), testActor, "tenant_test")
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				mockAddressRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "99/1", address.HouseNo)
+			if tt.existing != nil {
+				assert.Equal(t, uint(4), address.ID)
+			}
+			mockAddressRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPatientAddressService_Delete_NoAddressOfType(t *testing.T) {
+	mockAddressRepo := mocks.NewMockPatientAddressRepository()
+	mockReferenceRepo := mocks.NewMockAddressReferenceRepository()
+	mockPatientRepo := mocks.NewMockPatientRepository()
+	mockAuditRepo := mocks.NewMockAuditRepository()
+
+	mockAddressRepo.On("GetByType", uint(1), domain.AddressTypeWork, "tenant_test").Return(nil, nil)
+
+	service := services.NewPatientAddressService(mockAddressRepo, mockReferenceRepo, mockPatientRepo, mockAuditRepo)
+	err := service.Delete(1, domain.AddressTypeWork, testActor, "tenant_test")
+
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	mockAddressRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAddressService_Autocomplete(t *testing.T) {
+	tests := []struct {
+		name          string
+		req           *domain.AddressSearchRequest
+		expectedQuery string
+		expectedLimit int
+		expectedError error
+	}{
+		{
+			name:          "default limit",
+			req:           &domain.AddressSearchRequest{Query: " ลุม "},
+			expectedQuery: "ลุม",
+			expectedLimit: domain.DefaultAddressSearchLimit,
+		},
+		{
+			name:          "postal code prefix with limit",
+			req:           &domain.AddressSearchRequest{Query: "103", Limit: 5},
+			expectedQuery: "103",
+			expectedLimit: 5,
+		},
+		{
+			name:          "single Thai character is too short",
+			req:           &domain.AddressSearchRequest{Query: "ล"},
+			expectedError: domain.ErrInvalidInput,
+		},
+		{
+			name:          "limit too large",
+			req:           &domain.AddressSearchRequest{Query: "Bang", Limit: domain.MaxAddressSearchLimit + 1},
+			expectedError: domain.ErrInvalidInput,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockReferenceRepo := mocks.NewMockAddressReferenceRepository()
+			mockReferenceRepo.On("Search", tt.expectedQuery, tt.expectedLimit).Return([]domain.AddressLocation{*testLumphini}, nil)
+
+			service := services.NewAddressService(mockReferenceRepo)
+			locations, err := service.Autocomplete(tt.req)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				mockReferenceRepo.AssertNotCalled(t, "Search", mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, locations, 1)
+			mockReferenceRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAddressService_ListDistricts_UnknownProvince(t *testing.T) {
+	mockReferenceRepo := mocks.NewMockAddressReferenceRepository()
+	mockReferenceRepo.On("ListDistricts", "99").Return([]domain.District{}, nil)
+
+	service := services.NewAddressService(mockReferenceRepo)
+	_, err := service.ListDistricts("99")
+
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
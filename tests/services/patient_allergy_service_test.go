@@ -0,0 +1,124 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/mocks"
+	"github.com/wichai2002/his_v1/internal/services"
+	"gorm.io/gorm"
+)
+
+func newTestAllergyRequest() *domain.PatientAllergyRequest {
+	return &domain.PatientAllergyRequest{
+		AllergenType: domain.AllergenTypeDrug,
+		Allergen:     " Amoxicillin ",
+		Reaction:     "Urticaria",
+		Severity:     domain.AllergySeveritySevere,
+		Certainty:    domain.AllergyCertaintyConfirmed,
+	}
+}
+
+func TestPatientAllergyService_Create_RecordsStaff(t *testing.T) {
+	mockAllergyRepo := mocks.NewMockPatientAllergyRepository()
+	mockPatientRepo := mocks.NewMockPatientRepository()
+	mockAuditRepo := mocks.NewMockAuditRepository()
+
+	mockPatientRepo.On("GetByID", uint(1), "tenant_test").Return(&domain.Patient{}, nil)
+	mockAllergyRepo.On("Create", mock.MatchedBy(func(a *domain.PatientAllergy) bool {
+		return a.PatientID == 1 && a.Allergen == "Amoxicillin" &&
+			a.RecordedBy == testActor.StaffID && a.RecordedByUsername == testActor.Username
+	}), mock.MatchedBy(func(e *domain.AuditEvent) bool {
+		return e.Action == domain.AuditActionPatientAllergyCreate && *e.PatientID == 1
+	}), "tenant_test").Return(nil)
+
+	service := services.NewPatientAllergyService(mockAllergyRepo, mockPatientRepo, mockAuditRepo)
+	allergy, err := service.Create(1, newTestAllergyRequest(), testActor, "tenant_test")
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.AllergySeveritySevere, allergy.Severity)
+	mockAllergyRepo.AssertExpectations(t)
+}
+
+func TestPatientAllergyService_Create_Errors(t *testing.T) {
+	tests := []struct {
+		name          string
+		allergen      string
+		patientErr    error
+		createErr     error
+		expectedError error
+	}{
+		{
+			name:          "patient not found",
+			allergen:      "Amoxicillin",
+			patientErr:    gorm.ErrRecordNotFound,
+			expectedError: domain.ErrNotFound,
+		},
+		{
+			name:          "blank allergen",
+			allergen:      "   ",
+			expectedError: domain.ErrInvalidInput,
+		},
+		{
+			name:          "allergen already recorded",
+			allergen:      "Amoxicillin",
+			createErr:     errors.New("duplicate key value violates unique constraint"),
+			expectedError: domain.ErrDuplicateEntry,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAllergyRepo := mocks.NewMockPatientAllergyRepository()
+			mockPatientRepo := mocks.NewMockPatientRepository()
+			mockAuditRepo := mocks.NewMockAuditRepository()
+
+			if tt.patientErr != nil {
+				mockPatientRepo.On("GetByID", uint(1), "tenant_test").Return(nil, tt.patientErr)
+			} else {
+				mockPatientRepo.On("GetByID", uint(1), "tenant_test").Return(&domain.Patient{}, nil)
+			}
+			mockAllergyRepo.On("Create", mock.Anything, mock.Anything, "tenant_test").Return(tt.createErr)
+
+			req := newTestAllergyRequest()
+			req.Allergen = tt.allergen
+
+			service := services.NewPatientAllergyService(mockAllergyRepo, mockPatientRepo, mockAuditRepo)
+			_, err := service.Create(1, req, testActor, "tenant_test")
+
+			assert.ErrorIs(t, err, tt.expectedError)
+		})
+	}
+}
+
+func TestPatientAllergyService_Update_KeepsRecorder(t *testing.T) {
+	mockAllergyRepo := mocks.NewMockPatientAllergyRepository()
+	mockPatientRepo := mocks.NewMockPatientRepository()
+	mockAuditRepo := mocks.NewMockAuditRepository()
+
+	existing := &domain.PatientAllergy{
+		Model:              gorm.Model{ID: 3},
+		PatientID:          1,
+		AllergenType:       domain.AllergenTypeDrug,
+		Allergen:           "Amoxicillin",
+		Severity:           domain.AllergySeverityModerate,
+		Certainty:          domain.AllergyCertaintySuspected,
+		RecordedBy:         7,
+		RecordedByUsername: "nurse01",
+	}
+	mockAllergyRepo.On("GetByID", uint(1), uint(3), "tenant_test").Return(existing, nil)
+	mockAllergyRepo.On("Update", existing, mock.MatchedBy(func(e *domain.AuditEvent) bool {
+		return e.Action == domain.AuditActionPatientAllergyUpdate && e.ActorID == testActor.StaffID
+	}), "tenant_test").Return(nil)
+
+	service := services.NewPatientAllergyService(mockAllergyRepo, mockPatientRepo, mockAuditRepo)
+	allergy, err := service.Update(1, 3, newTestAllergyRequest(), testActor, "tenant_test")
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.AllergyCertaintyConfirmed, allergy.Certainty)
+	assert.Equal(t, uint(7), allergy.RecordedBy)
+	mockAllergyRepo.AssertExpectations(t)
+}
@@ -0,0 +1,151 @@
+package services_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/mocks"
+	"github.com/wichai2002/his_v1/internal/services"
+	"gorm.io/gorm"
+)
+
+func newTestContactRequest(isPrimary bool) *domain.PatientContactRequest {
+	return &domain.PatientContactRequest{
+		Name:         " Somsri Jaidee ",
+		Relationship: domain.RelationshipSpouse,
+		PhoneNumber:  "0812345678",
+		IsPrimary:    isPrimary,
+	}
+}
+
+func TestPatientContactService_Create(t *testing.T) {
+	tests := []struct {
+		name            string
+		isPrimary       bool
+		currentPrimary  *domain.PatientContact
+		expectedPrimary bool
+	}{
+		{
+			name:            "first contact becomes primary",
+			currentPrimary:  nil,
+			expectedPrimary: true,
+		},
+		{
+			name:            "additional contact is not primary",
+			currentPrimary:  &domain.PatientContact{Model: gorm.Model{ID: 3}, PatientID: 1, IsPrimary: true},
+			expectedPrimary: false,
+		},
+		{
+			name:            "explicit primary replaces the current one",
+			isPrimary:       true,
+			expectedPrimary: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContactRepo := mocks.NewMockPatientContactRepository()
+			mockPatientRepo := mocks.NewMockPatientRepository()
+			mockAuditRepo := mocks.NewMockAuditRepository()
+
+			mockPatientRepo.On("GetByID", uint(1), "tenant_test").Return(&domain.Patient{}, nil)
+			if !tt.isPrimary {
+				mockContactRepo.On("GetPrimary", uint(1), "tenant_test").Return(tt.currentPrimary, nil)
+			}
+			mockContactRepo.On("Create", mock.MatchedBy(func(c *domain.PatientContact) bool {
+				return c.PatientID == 1 && c.Name == "Somsri Jaidee" && c.IsPrimary == tt.expectedPrimary
+			}), mock.MatchedBy(func(e *domain.AuditEvent) bool {
+				return e.Action == domain.AuditActionPatientContactCreate && *e.PatientID == 1
+			}), "tenant_test").Return(nil)
+
+			service := services.NewPatientContactService(mockContactRepo, mockPatientRepo, mockAuditRepo)
+			contact, err := service.Create(1, newTestContactRequest(tt.isPrimary), testActor, "tenant_test")
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedPrimary, contact.IsPrimary)
+			mockContactRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPatientContactService_Create_PatientNotFound(t *testing.T) {
+	mockContactRepo := mocks.NewMockPatientContactRepository()
+	mockPatientRepo := mocks.NewMockPatientRepository()
+	mockAuditRepo := mocks.NewMockAuditRepository()
+
+	mockPatientRepo.On("GetByID", uint(99), "tenant_test").Return(nil, gorm.ErrRecordNotFound)
+
+	service := services.NewPatientContactService(mockContactRepo, mockPatientRepo, mockAuditRepo)
+	_, err := service.Create(99, newTestContactRequest(false), testActor, "tenant_test")
+
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	mockContactRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPatientContactService_Update(t *testing.T) {
+	mockContactRepo := mocks.NewMockPatientContactRepository()
+	mockPatientRepo := mocks.NewMockPatientRepository()
+	mockAuditRepo := mocks.NewMockAuditRepository()
+
+	existing := &domain.PatientContact{
+		Model:        gorm.Model{ID: 5},
+		PatientID:    1,
+		Name:         "Somsri Jaidee",
+		Relationship: domain.RelationshipSpouse,
+		PhoneNumber:  "0800000000",
+		IsPrimary:    true,
+	}
+	mockContactRepo.On("GetByID", uint(1), uint(5), "tenant_test").Return(existing, nil)
+	mockContactRepo.On("Update", existing, mock.MatchedBy(func(e *domain.AuditEvent) bool {
+		// Only the changed phone number is recorded; is_primary stays set
+		var changes map[string]domain.FieldChange
+		if err := json.Unmarshal([]byte(e.Changes), &changes); err != nil {
+			return false
+		}
+		_, phoneChanged := changes["phone_number"]
+		return e.Action == domain.AuditActionPatientContactUpdate && phoneChanged && len(changes) == 1
+	}), "tenant_test").Return(nil)
+
+	service := services.NewPatientContactService(mockContactRepo, mockPatientRepo, mockAuditRepo)
+	contact, err := service.Update(1, 5, newTestContactRequest(true), testActor, "tenant_test")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "0812345678", contact.PhoneNumber)
+	mockContactRepo.AssertExpectations(t)
+}
+
+func TestPatientContactService_Delete_NotFound(t *testing.T) {
+	mockContactRepo := mocks.NewMockPatientContactRepository()
+	mockPatientRepo := mocks.NewMockPatientRepository()
+	mockAuditRepo := mocks.NewMockAuditRepository()
+
+	mockContactRepo.On("GetByID", uint(1), uint(7), "tenant_test").Return(nil, gorm.ErrRecordNotFound)
+
+	service := services.NewPatientContactService(mockContactRepo, mockPatientRepo, mockAuditRepo)
+	err := service.Delete(1, 7, testActor, "tenant_test")
+
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	mockContactRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPatientContactService_List_RecordsView(t *testing.T) {
+	mockContactRepo := mocks.NewMockPatientContactRepository()
+	mockPatientRepo := mocks.NewMockPatientRepository()
+	mockAuditRepo := mocks.NewMockAuditRepository()
+
+	mockPatientRepo.On("GetByID", uint(1), "tenant_test").Return(&domain.Patient{}, nil)
+	mockContactRepo.On("ListByPatient", uint(1), "tenant_test").Return([]domain.PatientContact{{PatientID: 1, IsPrimary: true}}, nil)
+	mockAuditRepo.On("Append", mock.MatchedBy(func(events []*domain.AuditEvent) bool {
+		return len(events) == 1 && events[0].Action == domain.AuditActionPatientContactView
+	}), "tenant_test").Return(nil)
+
+	service := services.NewPatientContactService(mockContactRepo, mockPatientRepo, mockAuditRepo)
+	contacts, err := service.List(1, testActor, "tenant_test")
+
+	assert.NoError(t, err)
+	assert.Len(t, contacts, 1)
+	mockAuditRepo.AssertExpectations(t)
+}
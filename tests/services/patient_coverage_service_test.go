@@ -0,0 +1,279 @@
+package services_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/infrastructure/eligibility"
+	"github.com/wichai2002/his_v1/internal/mocks"
+	"github.com/wichai2002/his_v1/internal/services"
+	"gorm.io/gorm"
+)
+
+func newTestCoverageRequest() *domain.PatientCoverageRequest {
+	return &domain.PatientCoverageRequest{
+		Scheme:           domain.CoverageSchemeUC,
+		MainHospitalCode: "10669",
+		MainHospitalName: "Test Hospital",
+		ValidFrom:        "2026-01-01",
+		ValidTo:          "2026-12-31",
+	}
+}
+
+func testDate(value string) time.Time {
+	parsed, _ := time.Parse(domain.DateFormat, value)
+	return parsed
+}
+
+func TestPatientCoverageService_Create(t *testing.T) {
+	mockCoverageRepo := mocks.NewMockPatientCoverageRepository()
+	mockPatientRepo := mocks.NewMockPatientRepository()
+	mockAuditRepo := mocks.NewMockAuditRepository()
+
+	mockPatientRepo.On("GetByID", uint(1), "tenant_test").Return(&domain.Patient{}, nil)
+	mockCoverageRepo.On("ListByPatient", uint(1), "tenant_test").Return([]domain.PatientCoverage{}, nil)
+	mockCoverageRepo.On("Create", mock.MatchedBy(func(c *domain.PatientCoverage) bool {
+		return c.PatientID == 1 && c.Scheme == domain.CoverageSchemeUC && c.MainHospitalCode == "10669" &&
+			c.ValidFrom.Equal(testDate("2026-01-01")) && c.ValidTo != nil && c.ValidTo.Equal(testDate("2026-12-31"))
+	}), mock.MatchedBy(func(e *domain.AuditEvent) bool {
+		return e.Action == domain.AuditActionPatientCoverageCreate && *e.PatientID == 1
+	}), "tenant_test").Return(nil)
+
+	service := services.NewPatientCoverageService(mockCoverageRepo, mockPatientRepo, mockAuditRepo, eligibility.NewFakeChecker())
+	coverage, err := service.Create(1, newTestCoverageRequest(), testActor, "tenant_test")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "", coverage.EligibilityStatus)
+	mockCoverageRepo.AssertExpectations(t)
+}
+
+func TestPatientCoverageService_Create_Errors(t *testing.T) {
+	existingTo := testDate("2025-12-31")
+
+	tests := []struct {
+		name          string
+		modify        func(req *domain.PatientCoverageRequest)
+		existing      []domain.PatientCoverage
+		createErr     error
+		expectedError error
+	}{
+		{
+			name:          "invalid valid_from",
+			modify:        func(req *domain.PatientCoverageRequest) { req.ValidFrom = "01/01/2026" },
+			expectedError: domain.ErrInvalidInput,
+		},
+		{
+			name:          "valid_to before valid_from",
+			modify:        func(req *domain.PatientCoverageRequest) { req.ValidTo = "2025-06-30" },
+			expectedError: domain.ErrInvalidInput,
+		},
+		{
+			name:          "uc without main hospital",
+			modify:        func(req *domain.PatientCoverageRequest) { req.MainHospitalCode = "" },
+			expectedError: domain.ErrInvalidInput,
+		},
+		{
+			name: "private insurance without policy number",
+			modify: func(req *domain.PatientCoverageRequest) {
+				req.Scheme = domain.CoverageSchemePrivate
+				req.PolicyNumber = " "
+			},
+			expectedError: domain.ErrInvalidInput,
+		},
+		{
+			name:          "overlaps open-ended coverage of the same scheme",
+			modify:        func(req *domain.PatientCoverageRequest) {},
+			existing:      []domain.PatientCoverage{{Model: gorm.Model{ID: 5}, Scheme: domain.CoverageSchemeUC, ValidFrom: testDate("2026-06-01")}},
+			expectedError: domain.ErrDuplicateEntry,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCoverageRepo := mocks.NewMockPatientCoverageRepository()
+			mockPatientRepo := mocks.NewMockPatientRepository()
+			mockAuditRepo := mocks.NewMockAuditRepository()
+
+			mockPatientRepo.On("GetByID", uint(1), "tenant_test").Return(&domain.Patient{}, nil)
+			mockCoverageRepo.On("ListByPatient", uint(1), "tenant_test").Return(tt.existing, nil)
+
+			req := newTestCoverageRequest()
+			tt.modify(req)
+
+			service := services.NewPatientCoverageService(mockCoverageRepo, mockPatientRepo, mockAuditRepo, eligibility.NewFakeChecker())
+			_, err := service.Create(1, req, testActor, "tenant_test")
+
+			assert.ErrorIs(t, err, tt.expectedError)
+			mockCoverageRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+
+	t.Run("adjacent periods do not overlap", func(t *testing.T) {
+		mockCoverageRepo := mocks.NewMockPatientCoverageRepository()
+		mockPatientRepo := mocks.NewMockPatientRepository()
+		mockAuditRepo := mocks.NewMockAuditRepository()
+
+		mockPatientRepo.On("GetByID", uint(1), "tenant_test").Return(&domain.Patient{}, nil)
+		mockCoverageRepo.On("ListByPatient", uint(1), "tenant_test").Return([]domain.PatientCoverage{
+			{Model: gorm.Model{ID: 5}, Scheme: domain.CoverageSchemeUC, ValidFrom: testDate("2025-01-01"), ValidTo: &existingTo},
+			{Model: gorm.Model{ID: 6}, Scheme: domain.CoverageSchemePrivate, ValidFrom: testDate("2026-03-01")},
+		}, nil)
+		mockCoverageRepo.On("Create", mock.Anything, mock.Anything, "tenant_test").Return(nil)
+
+		service := services.NewPatientCoverageService(mockCoverageRepo, mockPatientRepo, mockAuditRepo, eligibility.NewFakeChecker())
+		_, err := service.Create(1, newTestCoverageRequest(), testActor, "tenant_test")
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestPatientCoverageService_Active(t *testing.T) {
+	mockCoverageRepo := mocks.NewMockPatientCoverageRepository()
+	mockPatientRepo := mocks.NewMockPatientRepository()
+	mockAuditRepo := mocks.NewMockAuditRepository()
+
+	active := []domain.PatientCoverage{{PatientID: 1, Scheme: domain.CoverageSchemeSSS}}
+	mockPatientRepo.On("GetByID", uint(1), "tenant_test").Return(&domain.Patient{}, nil)
+	mockCoverageRepo.On("ListActive", uint(1), testDate("2026-03-15"), "tenant_test").Return(active, nil)
+	mockAuditRepo.On("Append", mock.MatchedBy(func(events []*domain.AuditEvent) bool {
+		var changes map[string]domain.FieldChange
+		if err := json.Unmarshal([]byte(events[0].Changes), &changes); err != nil {
+			return false
+		}
+		return events[0].Action == domain.AuditActionPatientCoverageView && changes["active_on"].After == "2026-03-15"
+	}), "tenant_test").Return(nil)
+
+	service := services.NewPatientCoverageService(mockCoverageRepo, mockPatientRepo, mockAuditRepo, eligibility.NewFakeChecker())
+	coverages, err := service.Active(1, "2026-03-15", testActor, "tenant_test")
+
+	assert.NoError(t, err)
+	assert.Equal(t, active, coverages)
+	mockAuditRepo.AssertExpectations(t)
+
+	_, err = service.Active(1, "15-03-2026", testActor, "tenant_test")
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+}
+
+func TestPatientCoverageService_Update_ClearsEligibility(t *testing.T) {
+	mockCoverageRepo := mocks.NewMockPatientCoverageRepository()
+	mockPatientRepo := mocks.NewMockPatientRepository()
+	mockAuditRepo := mocks.NewMockAuditRepository()
+
+	checkedAt := time.Now()
+	validTo := testDate("2026-12-31")
+	existing := &domain.PatientCoverage{
+		Model:                gorm.Model{ID: 3},
+		PatientID:            1,
+		Scheme:               domain.CoverageSchemeUC,
+		MainHospitalCode:     "10669",
+		MainHospitalName:     "Test Hospital",
+		ValidFrom:            testDate("2026-01-01"),
+		ValidTo:              &validTo,
+		EligibilityStatus:    domain.EligibilityStatusEligible,
+		EligibilityCheckedAt: &checkedAt,
+	}
+	mockCoverageRepo.On("GetByID", uint(1), uint(3), "tenant_test").Return(existing, nil)
+	mockCoverageRepo.On("ListByPatient", uint(1), "tenant_test").Return([]domain.PatientCoverage{*existing}, nil)
+	mockCoverageRepo.On("Update", mock.MatchedBy(func(c *domain.PatientCoverage) bool {
+		return c.MainHospitalCode == "10670" && c.EligibilityStatus == "" && c.EligibilityCheckedAt == nil
+	}), mock.MatchedBy(func(e *domain.AuditEvent) bool {
+		return e.Action == domain.AuditActionPatientCoverageUpdate
+	}), "tenant_test").Return(nil)
+
+	req := newTestCoverageRequest()
+	req.MainHospitalCode = "10670"
+
+	service := services.NewPatientCoverageService(mockCoverageRepo, mockPatientRepo, mockAuditRepo, eligibility.NewFakeChecker())
+	_, err := service.Update(1, 3, req, testActor, "tenant_test")
+
+	assert.NoError(t, err)
+	mockCoverageRepo.AssertExpectations(t)
+}
+
+func TestPatientCoverageService_CheckEligibility(t *testing.T) {
+	tests := []struct {
+		name           string
+		nationalID     domain.NullableString
+		scheme         string
+		setupChecker   func(f *eligibility.FakeChecker)
+		expectedStatus string
+		expectedError  error
+	}{
+		{
+			name:           "eligible by default",
+			nationalID:     "1101700230705",
+			scheme:         domain.CoverageSchemeUC,
+			setupChecker:   func(f *eligibility.FakeChecker) {},
+			expectedStatus: domain.EligibilityStatusEligible,
+		},
+		{
+			name:       "not eligible",
+			nationalID: "1101700230705",
+			scheme:     domain.CoverageSchemeUC,
+			setupChecker: func(f *eligibility.FakeChecker) {
+				f.SetResult("1101700230705", domain.EligibilityResult{Eligible: false, Message: "registered under SSS"})
+			},
+			expectedStatus: domain.EligibilityStatusNotEligible,
+		},
+		{
+			name:       "checker unavailable",
+			nationalID: "1101700230705",
+			scheme:     domain.CoverageSchemeUC,
+			setupChecker: func(f *eligibility.FakeChecker) {
+				f.SetError("1101700230705", errors.New("connection refused"))
+			},
+			expectedError: domain.ErrEligibilityUnavailable,
+		},
+		{
+			name:          "patient without national ID",
+			scheme:        domain.CoverageSchemeUC,
+			setupChecker:  func(f *eligibility.FakeChecker) {},
+			expectedError: domain.ErrInvalidInput,
+		},
+		{
+			name:          "self-pay has no payer",
+			nationalID:    "1101700230705",
+			scheme:        domain.CoverageSchemeSelfPay,
+			setupChecker:  func(f *eligibility.FakeChecker) {},
+			expectedError: domain.ErrInvalidInput,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCoverageRepo := mocks.NewMockPatientCoverageRepository()
+			mockPatientRepo := mocks.NewMockPatientRepository()
+			mockAuditRepo := mocks.NewMockAuditRepository()
+			checker := eligibility.NewFakeChecker()
+			tt.setupChecker(checker)
+
+			mockPatientRepo.On("GetByID", uint(1), "tenant_test").Return(&domain.Patient{NationalID: tt.nationalID}, nil)
+			mockCoverageRepo.On("GetByID", uint(1), uint(3), "tenant_test").
+				Return(&domain.PatientCoverage{Model: gorm.Model{ID: 3}, PatientID: 1, Scheme: tt.scheme, ValidFrom: testDate("2026-01-01")}, nil)
+			if tt.expectedError == nil {
+				mockCoverageRepo.On("Update", mock.MatchedBy(func(c *domain.PatientCoverage) bool {
+					return c.EligibilityStatus == tt.expectedStatus && c.EligibilityCheckedAt != nil
+				}), mock.MatchedBy(func(e *domain.AuditEvent) bool {
+					return e.Action == domain.AuditActionPatientCoverageEligibility
+				}), "tenant_test").Return(nil)
+			}
+
+			service := services.NewPatientCoverageService(mockCoverageRepo, mockPatientRepo, mockAuditRepo, checker)
+			result, err := service.CheckEligibility(1, 3, "2026-03-15", testActor, "tenant_test")
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				mockCoverageRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, eligibility.FakeSource, result.Source)
+			mockCoverageRepo.AssertExpectations(t)
+		})
+	}
+}
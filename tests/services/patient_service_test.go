@@ -10,8 +10,13 @@ import (
 	"github.com/wichai2002/his_v1/internal/domain"
 	"github.com/wichai2002/his_v1/internal/mocks"
 	"github.com/wichai2002/his_v1/internal/services"
+	"gorm.io/gorm"
 )
 
+var testActor = &domain.Actor{StaffID: 1, Username: "admin", ClientIP: "127.0.0.1", RequestID: "req-1"}
+
+var testHNIssuer, _ = domain.NewHNIssuer(&domain.Tenant{HospitalCode: "HOSP0001", HNTemplate: domain.DefaultHNTemplate})
+
 func TestPatientService_Search(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -44,7 +49,7 @@ func TestPatientService_Search(t *testing.T) {
 			expectError:   false,
 		},
 		{
-			name:          "search with empty query returns all",
+			name:          "search with empty query returns first page",
 			query:         "",
 			schemaName:    "tenant_test",
 			mockPatients:  []domain.Patient{{FirstNameEN: "John"}},
@@ -67,18 +72,32 @@ func TestPatientService_Search(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := mocks.NewMockPatientRepository()
 			mockTenantService := mocks.NewMockTenantService()
+			mockAuditRepo := mocks.NewMockAuditRepository()
 
-			mockRepo.On("Search", tt.query, tt.schemaName).Return(tt.mockPatients, tt.mockError)
+			mockRepo.On("Search", mock.MatchedBy(func(filter *domain.PatientSearchFilter) bool {
+				// Default page size plus one row to detect the next page, newest first
+				return filter.Query == tt.query &&
+					filter.Limit == domain.DefaultPatientSearchLimit+1 &&
+					filter.SortColumn == "created_at" && filter.SortDesc
+			}), tt.schemaName).Return(tt.mockPatients, int64(len(tt.mockPatients)), tt.mockError)
+			if tt.mockError == nil {
+				// Every returned patient is recorded as accessed
+				mockAuditRepo.On("Append", mock.MatchedBy(func(events []*domain.AuditEvent) bool {
+					return len(events) == tt.expectedCount
+				}), tt.schemaName).Return(nil)
+			}
 
-			service := services.NewPatientService(mockRepo, mockTenantService)
-			result, err := service.Search(tt.query, tt.schemaName)
+			service := services.NewPatientService(mockRepo, mockAuditRepo, mockTenantService)
+			result, err := service.Search(&domain.PatientSearchRequest{Query: tt.query}, testActor, tt.schemaName)
 
 			if tt.expectError {
 				assert.Error(t, err)
 				assert.Nil(t, result)
 			} else {
 				assert.NoError(t, err)
-				assert.Len(t, result, tt.expectedCount)
+				assert.Len(t, result.Patients, tt.expectedCount)
+				assert.Equal(t, int64(tt.expectedCount), result.Total)
+				assert.Empty(t, result.NextCursor)
 			}
 
 			mockRepo.AssertExpectations(t)
@@ -86,6 +105,194 @@ func TestPatientService_Search(t *testing.T) {
 	}
 }
 
+func TestPatientService_Search_Pagination(t *testing.T) {
+	mockRepo := mocks.NewMockPatientRepository()
+	mockTenantService := mocks.NewMockTenantService()
+	mockAuditRepo := mocks.NewMockAuditRepository()
+
+	page := []domain.Patient{
+		{FirstNameEN: "Anna"}, {FirstNameEN: "Ben"}, {FirstNameEN: "Carl"},
+	}
+	for i := range page {
+		page[i].ID = uint(i + 1)
+	}
+
+	// limit=2 asks the repository for 3 rows; the third only signals a next page
+	mockRepo.On("Search", mock.MatchedBy(func(filter *domain.PatientSearchFilter) bool {
+		return filter.Limit == 3 && filter.SortColumn == "first_name_en" && !filter.SortDesc && filter.AfterValue == nil
+	}), "tenant_test").Return(page, int64(10), nil)
+	mockAuditRepo.On("Append", mock.MatchedBy(func(events []*domain.AuditEvent) bool {
+		return len(events) == 2
+	}), "tenant_test").Return(nil)
+
+	service := services.NewPatientService(mockRepo, mockAuditRepo, mockTenantService)
+	result, err := service.Search(&domain.PatientSearchRequest{Limit: 2, Sort: "first_name_en"}, testActor, "tenant_test")
+
+	assert.NoError(t, err)
+	assert.Len(t, result.Patients, 2)
+	assert.Equal(t, int64(10), result.Total)
+	assert.Equal(t, 2, result.Limit)
+
+	cursor, err := domain.DecodePatientCursor(result.NextCursor)
+	assert.NoError(t, err)
+	assert.Equal(t, "Ben", cursor.Value)
+	assert.Equal(t, uint(2), cursor.ID)
+
+	// The cursor resumes after the last row of the page
+	mockRepo.On("Search", mock.MatchedBy(func(filter *domain.PatientSearchFilter) bool {
+		return filter.AfterValue == "Ben" && filter.AfterID == 2
+	}), "tenant_test").Return([]domain.Patient{page[2]}, int64(10), nil)
+	mockAuditRepo.On("Append", mock.Anything, "tenant_test").Return(nil)
+
+	next, err := service.Search(&domain.PatientSearchRequest{Limit: 2, Sort: "first_name_en", Cursor: result.NextCursor}, testActor, "tenant_test")
+
+	assert.NoError(t, err)
+	assert.Len(t, next.Patients, 1)
+	assert.Empty(t, next.NextCursor)
+}
+
+func TestPatientService_Search_Filters(t *testing.T) {
+	mockRepo := mocks.NewMockPatientRepository()
+	mockTenantService := mocks.NewMockTenantService()
+	mockAuditRepo := mocks.NewMockAuditRepository()
+
+	mockRepo.On("Search", mock.MatchedBy(func(filter *domain.PatientSearchFilter) bool {
+		// Date ranges are inclusive, so the end is moved to the start of the following day
+		return filter.Gender == "F" && filter.BloodGrp == "AB" && filter.Nationality == "Thai" &&
+			filter.DOBFrom.Format(domain.DateFormat) == "1980-01-01" &&
+			filter.DOBTo.Format(domain.DateFormat) == "1991-01-01" &&
+			filter.CreatedFrom == nil && filter.CreatedTo.Format(domain.DateFormat) == "2024-07-01" &&
+			filter.SortColumn == "date_of_birth" && filter.SortDesc
+	}), "tenant_test").Return([]domain.Patient{}, int64(0), nil)
+	mockAuditRepo.On("Append", mock.Anything, "tenant_test").Return(nil)
+
+	service := services.NewPatientService(mockRepo, mockAuditRepo, mockTenantService)
+	_, err := service.Search(&domain.PatientSearchRequest{
+		Gender:      "F",
+		BloodGrp:    "AB",
+		Nationality: " Thai ",
+		DOBFrom:     "1980-01-01",
+		DOBTo:       "1990-12-31",
+		CreatedTo:   "2024-06-30",
+		Sort:        "-date_of_birth",
+	}, testActor, "tenant_test")
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPatientService_Search_Fuzzy(t *testing.T) {
+	mockRepo := mocks.NewMockPatientRepository()
+	mockTenantService := mocks.NewMockTenantService()
+	mockAuditRepo := mocks.NewMockAuditRepository()
+
+	high, low := 0.8, 0.45
+	items := []domain.PatientSearchItem{
+		{Patient: domain.Patient{FirstNameEN: "Somchai"}, Score: &high},
+		{Patient: domain.Patient{FirstNameEN: "Somchat"}, Score: &low},
+	}
+	items[0].ID, items[1].ID = 3, 9
+
+	mockRepo.On("FuzzySearch", mock.MatchedBy(func(filter *domain.PatientSearchFilter) bool {
+		return filter.Fuzzy && filter.Query == "Somchay" && filter.Limit == 2 && filter.AfterValue == nil
+	}), "tenant_test").Return(items, int64(4), nil)
+	mockAuditRepo.On("Append", mock.Anything, "tenant_test").Return(nil)
+
+	service := services.NewPatientService(mockRepo, mockAuditRepo, mockTenantService)
+	result, err := service.Search(&domain.PatientSearchRequest{Query: "Somchay", Mode: domain.PatientSearchFuzzy, Limit: 1}, testActor, "tenant_test")
+
+	assert.NoError(t, err)
+	assert.Len(t, result.Patients, 1)
+	assert.Equal(t, 0.8, *result.Patients[0].Score)
+	mockRepo.AssertNotCalled(t, "Search", mock.Anything, mock.Anything)
+
+	// The next page resumes after the score and ID of the last match
+	cursor, err := domain.DecodePatientCursor(result.NextCursor)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.FuzzySortKey, cursor.Sort)
+	assert.Equal(t, "0.8", cursor.Value)
+
+	mockRepo.On("FuzzySearch", mock.MatchedBy(func(filter *domain.PatientSearchFilter) bool {
+		return filter.AfterValue == 0.8 && filter.AfterID == 3
+	}), "tenant_test").Return(items[1:], int64(4), nil)
+
+	_, err = service.Search(&domain.PatientSearchRequest{Query: "Somchay", Mode: domain.PatientSearchFuzzy, Limit: 1, Cursor: result.NextCursor}, testActor, "tenant_test")
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPatientService_Search_InvalidRequest(t *testing.T) {
+	createdAtCursor := (&domain.PatientCursor{Sort: "-created_at", Value: time.Now().Format(time.RFC3339Nano), ID: 5}).Encode()
+
+	tests := []struct {
+		name        string
+		request     *domain.PatientSearchRequest
+		expectError error
+	}{
+		{
+			name:        "unsupported sort",
+			request:     &domain.PatientSearchRequest{Sort: "email"},
+			expectError: domain.ErrInvalidInput,
+		},
+		{
+			name:        "limit above maximum",
+			request:     &domain.PatientSearchRequest{Limit: domain.MaxPatientSearchLimit + 1},
+			expectError: domain.ErrInvalidInput,
+		},
+		{
+			name:        "malformed cursor",
+			request:     &domain.PatientSearchRequest{Cursor: "not-a-cursor"},
+			expectError: domain.ErrInvalidInput,
+		},
+		{
+			name:        "cursor from another sort order",
+			request:     &domain.PatientSearchRequest{Cursor: createdAtCursor, Sort: "hn"},
+			expectError: domain.ErrInvalidInput,
+		},
+		{
+			name:        "fuzzy search without query",
+			request:     &domain.PatientSearchRequest{Mode: domain.PatientSearchFuzzy},
+			expectError: domain.ErrInvalidInput,
+		},
+		{
+			name:        "fuzzy search with sort",
+			request:     &domain.PatientSearchRequest{Query: "som", Mode: domain.PatientSearchFuzzy, Sort: "hn"},
+			expectError: domain.ErrInvalidInput,
+		},
+		{
+			name:        "sorted cursor used in fuzzy mode",
+			request:     &domain.PatientSearchRequest{Query: "som", Mode: domain.PatientSearchFuzzy, Cursor: createdAtCursor},
+			expectError: domain.ErrInvalidInput,
+		},
+		{
+			name:        "invalid date",
+			request:     &domain.PatientSearchRequest{DOBFrom: "15-01-1990"},
+			expectError: domain.ErrInvalidDateFormat,
+		},
+		{
+			name:        "reversed date range",
+			request:     &domain.PatientSearchRequest{CreatedFrom: "2024-02-01", CreatedTo: "2024-01-01"},
+			expectError: domain.ErrInvalidInput,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockPatientRepository()
+			mockTenantService := mocks.NewMockTenantService()
+			mockAuditRepo := mocks.NewMockAuditRepository()
+
+			service := services.NewPatientService(mockRepo, mockAuditRepo, mockTenantService)
+			result, err := service.Search(tt.request, testActor, "tenant_test")
+
+			assert.ErrorIs(t, err, tt.expectError)
+			assert.Nil(t, result)
+			mockRepo.AssertNotCalled(t, "Search", mock.Anything, mock.Anything)
+			mockRepo.AssertNotCalled(t, "FuzzySearch", mock.Anything, mock.Anything)
+		})
+	}
+}
+
 func TestPatientService_SearchByID(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -121,11 +328,15 @@ func TestPatientService_SearchByID(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := mocks.NewMockPatientRepository()
 			mockTenantService := mocks.NewMockTenantService()
+			mockAuditRepo := mocks.NewMockAuditRepository()
 
 			mockRepo.On("SearchByID", tt.id, tt.schemaName).Return(tt.mockPatient, tt.mockError)
+			if tt.mockError == nil {
+				mockAuditRepo.On("Append", mock.Anything, tt.schemaName).Return(nil)
+			}
 
-			service := services.NewPatientService(mockRepo, mockTenantService)
-			result, err := service.SearchByID(tt.id, tt.schemaName)
+			service := services.NewPatientService(mockRepo, mockAuditRepo, mockTenantService)
+			result, err := service.SearchByID(tt.id, testActor, tt.schemaName)
 
 			if tt.expectError {
 				assert.Error(t, err)
@@ -160,7 +371,7 @@ func TestPatientService_Create(t *testing.T) {
 				FirstNameEN:  "Somchai",
 				LastNameEN:   "Jaidee",
 				DateOfBirth:  "1990-01-15",
-				NationalID:   "1234567890123",
+				NationalID:   "1234567890121",
 				PhoneNumber:  "0812345678",
 				Email:        "somchai@example.com",
 				Gender:       domain.Male,
@@ -174,21 +385,22 @@ func TestPatientService_Create(t *testing.T) {
 			expectError: false,
 		},
 		{
-			name: "failed HN generation",
+			name: "failed to load HN template",
 			request: &domain.PatientCreateRequest{
 				FirstNameEN: "John",
 				LastNameEN:  "Doe",
 				DateOfBirth: "1990-01-15",
+				PassportID:  "AB1234567",
 				Gender:      domain.Male,
 				Nationality: "Thai",
 				BloodGrp:    "A",
 			},
 			schemaName:   "tenant_test",
 			generatedHN:  "",
-			hnError:      errors.New("failed to generate HN"),
+			hnError:      errors.New("tenant not found"),
 			createError:  nil,
 			expectError:  true,
-			errorMessage: "failed to generate HN",
+			errorMessage: "failed to load HN template",
 		},
 		{
 			name: "invalid date of birth format",
@@ -196,6 +408,7 @@ func TestPatientService_Create(t *testing.T) {
 				FirstNameEN: "John",
 				LastNameEN:  "Doe",
 				DateOfBirth: "invalid-date",
+				PassportID:  "AB1234567",
 				Gender:      domain.Male,
 				Nationality: "Thai",
 				BloodGrp:    "A",
@@ -212,6 +425,7 @@ func TestPatientService_Create(t *testing.T) {
 				FirstNameEN: "John",
 				LastNameEN:  "Doe",
 				DateOfBirth: "1990-01-15",
+				PassportID:  "AB1234567",
 				Gender:      domain.Male,
 				Nationality: "Thai",
 				BloodGrp:    "A",
@@ -229,18 +443,27 @@ func TestPatientService_Create(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := mocks.NewMockPatientRepository()
 			mockTenantService := mocks.NewMockTenantService()
+			mockAuditRepo := mocks.NewMockAuditRepository()
 
-			// Date validation happens first, so GenerateHN is only called for valid dates
+			// Date validation happens first, so the HN template is only loaded for valid dates
 			if tt.request.DateOfBirth != "invalid-date" {
-				mockTenantService.On("GenerateHN", tt.schemaName).Return(tt.generatedHN, tt.hnError)
+				mockRepo.On("FindDuplicateCandidates", mock.AnythingOfType("*domain.Patient"), domain.DuplicateCandidateScanLimit, tt.schemaName).Return([]domain.PatientSearchItem{}, nil)
 
-				if tt.hnError == nil {
-					mockRepo.On("Create", mock.AnythingOfType("*domain.Patient"), tt.schemaName).Return(tt.createError)
+				if tt.hnError != nil {
+					mockTenantService.On("GetHNIssuer", tt.schemaName).Return(nil, tt.hnError)
+				} else {
+					mockTenantService.On("GetHNIssuer", tt.schemaName).Return(testHNIssuer, nil)
+					// The repository issues the HN in the insert transaction
+					mockRepo.On("Create", mock.AnythingOfType("*domain.Patient"), testHNIssuer, mock.AnythingOfType("[]*domain.AuditEvent"), tt.schemaName).
+						Run(func(args mock.Arguments) {
+							args.Get(0).(*domain.Patient).PatientHN = tt.generatedHN
+						}).
+						Return(tt.createError)
 				}
 			}
 
-			service := services.NewPatientService(mockRepo, mockTenantService)
-			result, err := service.Create(tt.request, tt.schemaName)
+			service := services.NewPatientService(mockRepo, mockAuditRepo, mockTenantService)
+			result, err := service.Create(tt.request, testActor, tt.schemaName)
 
 			if tt.expectError {
 				assert.Error(t, err)
@@ -269,6 +492,7 @@ func TestPatientService_Update(t *testing.T) {
 		DateOfBirth: time.Date(1990, 1, 15, 0, 0, 0, 0, time.UTC),
 	}
 	existingPatient.ID = 1
+	existingPatient.Version = 2
 
 	tests := []struct {
 		name        string
@@ -289,6 +513,7 @@ func TestPatientService_Update(t *testing.T) {
 				FirstNameEN: "Jane",
 				LastNameEN:  "Doe",
 				DateOfBirth: "1990-01-15",
+				NationalID:  "1234567890121",
 				Gender:      "F",
 				Nationality: "Thai",
 				BloodGrp:    "B",
@@ -306,6 +531,7 @@ func TestPatientService_Update(t *testing.T) {
 				FirstNameEN: "Jane",
 				LastNameEN:  "Doe",
 				DateOfBirth: "1990-01-15",
+				NationalID:  "1234567890121",
 				Gender:      "F",
 				Nationality: "Thai",
 				BloodGrp:    "B",
@@ -323,6 +549,7 @@ func TestPatientService_Update(t *testing.T) {
 				FirstNameEN: "Jane",
 				LastNameEN:  "Doe",
 				DateOfBirth: "invalid-date",
+				NationalID:  "1234567890121",
 				Gender:      "F",
 				Nationality: "Thai",
 				BloodGrp:    "B",
@@ -339,15 +566,16 @@ func TestPatientService_Update(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := mocks.NewMockPatientRepository()
 			mockTenantService := mocks.NewMockTenantService()
+			mockAuditRepo := mocks.NewMockAuditRepository()
 
 			mockRepo.On("GetByID", tt.id, tt.schemaName).Return(tt.mockPatient, tt.getError)
 
 			if tt.getError == nil && tt.request.DateOfBirth != "invalid-date" {
-				mockRepo.On("Update", mock.AnythingOfType("*domain.Patient"), tt.schemaName).Return(tt.updateError)
+				mockRepo.On("Update", mock.AnythingOfType("*domain.Patient"), mock.AnythingOfType("*domain.AuditEvent"), tt.schemaName).Return(tt.updateError)
 			}
 
-			service := services.NewPatientService(mockRepo, mockTenantService)
-			result, err := service.Update(tt.id, tt.request, tt.schemaName)
+			service := services.NewPatientService(mockRepo, mockAuditRepo, mockTenantService)
+			result, err := service.Update(tt.id, tt.request, 2, testActor, tt.schemaName)
 
 			if tt.expectError {
 				assert.Error(t, err)
@@ -368,8 +596,10 @@ func TestPatientService_PartialUpdate(t *testing.T) {
 		FirstNameEN: "John",
 		LastNameEN:  "Doe",
 		PatientHN:   "HOSP0001-00000001",
+		NationalID:  "1234567890121",
 	}
 	existingPatient.ID = 1
+	existingPatient.Version = 2
 
 	newFirstName := "Jane"
 
@@ -416,20 +646,22 @@ func TestPatientService_PartialUpdate(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := mocks.NewMockPatientRepository()
 			mockTenantService := mocks.NewMockTenantService()
+			mockAuditRepo := mocks.NewMockAuditRepository()
 
-			// New logic: PartialUpdate is called first, then GetByID is called to fetch the updated record
+			// GetByID loads the before values for the audit event, PartialUpdate writes,
+			// then GetByID is called again to fetch the updated record
 			if tt.getError != nil {
-				// Simulate not found error from PartialUpdate (repository returns error)
-				mockRepo.On("PartialUpdate", tt.id, mock.Anything, tt.schemaName).Return(tt.getError)
+				mockRepo.On("GetByID", tt.id, tt.schemaName).Return(nil, tt.getError)
 			} else {
-				mockRepo.On("PartialUpdate", tt.id, mock.Anything, tt.schemaName).Return(tt.updateError)
+				mockRepo.On("GetByID", tt.id, tt.schemaName).Return(tt.getPatient, nil).Once()
+				mockRepo.On("PartialUpdate", tt.id, uint(2), mock.Anything, mock.AnythingOfType("*domain.AuditEvent"), tt.schemaName).Return(tt.updateError)
 				if tt.updateError == nil {
-					mockRepo.On("GetByID", tt.id, tt.schemaName).Return(tt.getAfterUpdate, tt.getAfterError)
+					mockRepo.On("GetByID", tt.id, tt.schemaName).Return(tt.getAfterUpdate, tt.getAfterError).Once()
 				}
 			}
 
-			service := services.NewPatientService(mockRepo, mockTenantService)
-			result, err := service.PartialUpdate(tt.id, tt.request, tt.schemaName)
+			service := services.NewPatientService(mockRepo, mockAuditRepo, mockTenantService)
+			result, err := service.PartialUpdate(tt.id, tt.request, 2, testActor, tt.schemaName)
 
 			if tt.expectError {
 				assert.Error(t, err)
@@ -472,6 +704,7 @@ func TestPatientService_Delete(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := mocks.NewMockPatientRepository()
 			mockTenantService := mocks.NewMockTenantService()
+			mockAuditRepo := mocks.NewMockAuditRepository()
 
 			// New logic: GetByID is called first to check if patient exists
 			if tt.deleteError != nil {
@@ -479,11 +712,11 @@ func TestPatientService_Delete(t *testing.T) {
 				mockRepo.On("GetByID", tt.id, tt.schemaName).Return(nil, tt.deleteError)
 			} else {
 				mockRepo.On("GetByID", tt.id, tt.schemaName).Return(&domain.Patient{}, nil)
-				mockRepo.On("Delete", tt.id, tt.schemaName).Return(tt.deleteError)
+				mockRepo.On("Delete", tt.id, mock.AnythingOfType("*domain.AuditEvent"), tt.schemaName).Return(tt.deleteError)
 			}
 
-			service := services.NewPatientService(mockRepo, mockTenantService)
-			err := service.Delete(tt.id, tt.schemaName)
+			service := services.NewPatientService(mockRepo, mockAuditRepo, mockTenantService)
+			err := service.Delete(tt.id, testActor, tt.schemaName)
 
 			if tt.expectError {
 				assert.Error(t, err)
@@ -495,3 +728,402 @@ func TestPatientService_Delete(t *testing.T) {
 		})
 	}
 }
+
+func TestPatientService_Update_RecordsChanges(t *testing.T) {
+	mockRepo := mocks.NewMockPatientRepository()
+	mockTenantService := mocks.NewMockTenantService()
+	mockAuditRepo := mocks.NewMockAuditRepository()
+
+	existing := &domain.Patient{
+		FirstNameEN: "John",
+		LastNameEN:  "Doe",
+		PhoneNumber: "0811111111",
+		DateOfBirth: time.Date(1990, 1, 15, 0, 0, 0, 0, time.UTC),
+		NationalID:  "1234567890121",
+		Gender:      domain.Male,
+		Nationality: "Thai",
+		BloodGrp:    domain.O,
+	}
+	existing.ID = 1
+	existing.Version = 1
+
+	request := &domain.PatientUpdateRequest{
+		FirstNameEN: "John",
+		LastNameEN:  "Doe",
+		PhoneNumber: "0822222222",
+		DateOfBirth: "1990-01-15",
+		NationalID:  "1234567890121",
+		Gender:      "M",
+		Nationality: "Thai",
+		BloodGrp:    "O",
+	}
+
+	var recorded *domain.AuditEvent
+	mockRepo.On("GetByID", uint(1), "tenant_test").Return(existing, nil)
+	mockRepo.On("Update", mock.AnythingOfType("*domain.Patient"), mock.AnythingOfType("*domain.AuditEvent"), "tenant_test").
+		Run(func(args mock.Arguments) {
+			recorded = args.Get(1).(*domain.AuditEvent)
+		}).
+		Return(nil)
+
+	service := services.NewPatientService(mockRepo, mockAuditRepo, mockTenantService)
+	_, err := service.Update(1, request, 1, testActor, "tenant_test")
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.AuditActionPatientUpdate, recorded.Action)
+	assert.Equal(t, testActor.StaffID, recorded.ActorID)
+	assert.Equal(t, testActor.RequestID, recorded.RequestID)
+	assert.Equal(t, uint(1), *recorded.PatientID)
+	assert.JSONEq(t, `{"phone_number":{"before":"0811111111","after":"0822222222"}}`, recorded.Changes)
+}
+
+func TestPatientService_Search_AuditFailure(t *testing.T) {
+	mockRepo := mocks.NewMockPatientRepository()
+	mockTenantService := mocks.NewMockTenantService()
+	mockAuditRepo := mocks.NewMockAuditRepository()
+
+	mockRepo.On("Search", mock.AnythingOfType("*domain.PatientSearchFilter"), "tenant_test").Return([]domain.Patient{{FirstNameEN: "John"}}, int64(1), nil)
+	mockAuditRepo.On("Append", mock.Anything, "tenant_test").Return(errors.New("database error"))
+
+	service := services.NewPatientService(mockRepo, mockAuditRepo, mockTenantService)
+	result, err := service.Search(&domain.PatientSearchRequest{Query: "John"}, testActor, "tenant_test")
+
+	// Records are not returned when the access cannot be audited
+	assert.Error(t, err)
+	assert.Nil(t, result)
+}
+
+func TestPatientService_Create_RequiresIdentifier(t *testing.T) {
+	mockRepo := mocks.NewMockPatientRepository()
+	mockTenantService := mocks.NewMockTenantService()
+	mockAuditRepo := mocks.NewMockAuditRepository()
+
+	request := &domain.PatientCreateRequest{
+		FirstNameEN: "John",
+		LastNameEN:  "Doe",
+		DateOfBirth: "1990-01-15",
+		NationalID:  " ",
+		Gender:      domain.Male,
+		Nationality: "Thai",
+		BloodGrp:    "A",
+	}
+
+	service := services.NewPatientService(mockRepo, mockAuditRepo, mockTenantService)
+	result, err := service.Create(request, testActor, "tenant_test")
+
+	// No HN is allocated for a patient that cannot be identified
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	assert.Nil(t, result)
+	mockTenantService.AssertNotCalled(t, "GetHNIssuer", mock.Anything)
+}
+
+func TestPatientService_Create_PossibleDuplicate(t *testing.T) {
+	similarity := 0.9
+	existing := domain.PatientSearchItem{
+		Patient: domain.Patient{
+			FirstNameEN: "John",
+			LastNameEN:  "Doe",
+			PatientHN:   "HOSP0001-00000001",
+			DateOfBirth: time.Date(1990, 1, 15, 0, 0, 0, 0, time.UTC),
+			PhoneNumber: "0812345678",
+		},
+		Score: &similarity,
+	}
+	existing.ID = 7
+	unrelated := domain.PatientSearchItem{Patient: domain.Patient{FirstNameEN: "Jane", PatientHN: "HOSP0001-00000002"}}
+
+	newRequest := func(force bool) *domain.PatientCreateRequest {
+		return &domain.PatientCreateRequest{
+			FirstNameEN: "John",
+			LastNameEN:  "Doe",
+			DateOfBirth: "1990-01-15",
+			PassportID:  "AB1234567",
+			PhoneNumber: "0812345678",
+			Gender:      domain.Male,
+			Nationality: "Thai",
+			BloodGrp:    "A",
+			Force:       force,
+		}
+	}
+
+	t.Run("registration is blocked and the lookup audited", func(t *testing.T) {
+		mockRepo := mocks.NewMockPatientRepository()
+		mockTenantService := mocks.NewMockTenantService()
+		mockAuditRepo := mocks.NewMockAuditRepository()
+
+		mockRepo.On("FindDuplicateCandidates", mock.AnythingOfType("*domain.Patient"), domain.DuplicateCandidateScanLimit, "tenant_test").
+			Return([]domain.PatientSearchItem{unrelated, existing}, nil)
+		mockAuditRepo.On("Append", mock.MatchedBy(func(events []*domain.AuditEvent) bool {
+			return len(events) == 1 && events[0].Action == domain.AuditActionPatientDuplicateCheck && *events[0].PatientID == 7
+		}), "tenant_test").Return(nil)
+
+		service := services.NewPatientService(mockRepo, mockAuditRepo, mockTenantService)
+		result, err := service.Create(newRequest(false), testActor, "tenant_test")
+
+		var dupErr *domain.DuplicatePatientError
+		assert.ErrorIs(t, err, domain.ErrPossibleDuplicate)
+		assert.True(t, errors.As(err, &dupErr))
+		assert.Len(t, dupErr.Candidates, 1)
+		assert.Equal(t, "HOSP0001-00000001", dupErr.Candidates[0].Patient.PatientHN)
+		assert.Nil(t, result)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockAuditRepo.AssertExpectations(t)
+	})
+
+	t.Run("force registers the patient and audits the override", func(t *testing.T) {
+		mockRepo := mocks.NewMockPatientRepository()
+		mockTenantService := mocks.NewMockTenantService()
+		mockAuditRepo := mocks.NewMockAuditRepository()
+
+		mockRepo.On("FindDuplicateCandidates", mock.AnythingOfType("*domain.Patient"), domain.DuplicateCandidateScanLimit, "tenant_test").
+			Return([]domain.PatientSearchItem{existing}, nil)
+		mockTenantService.On("GetHNIssuer", "tenant_test").Return(testHNIssuer, nil)
+		mockRepo.On("Create", mock.AnythingOfType("*domain.Patient"), testHNIssuer, mock.MatchedBy(func(events []*domain.AuditEvent) bool {
+			return len(events) == 2 &&
+				events[0].Action == domain.AuditActionPatientCreate &&
+				events[1].Action == domain.AuditActionPatientDuplicateOverride
+		}), "tenant_test").Run(func(args mock.Arguments) {
+			args.Get(0).(*domain.Patient).PatientHN = "HOSP0001-00000003"
+		}).Return(nil)
+
+		service := services.NewPatientService(mockRepo, mockAuditRepo, mockTenantService)
+		result, err := service.Create(newRequest(true), testActor, "tenant_test")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "HOSP0001-00000003", result.PatientHN)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestPatientService_PartialUpdate_KeepsIdentifier(t *testing.T) {
+	existing := &domain.Patient{FirstNameEN: "John", NationalID: "1234567890121"}
+	existing.ID = 1
+	existing.Version = 1
+
+	blank := ""
+	passportID := "AB1234567"
+
+	t.Run("clearing the only identifier is rejected", func(t *testing.T) {
+		mockRepo := mocks.NewMockPatientRepository()
+		mockRepo.On("GetByID", uint(1), "tenant_test").Return(existing, nil)
+
+		service := services.NewPatientService(mockRepo, mocks.NewMockAuditRepository(), mocks.NewMockTenantService())
+		result, err := service.PartialUpdate(1, &domain.PatientPartialUpdateRequest{NationalID: &blank}, 1, testActor, "tenant_test")
+
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+		assert.Nil(t, result)
+		mockRepo.AssertNotCalled(t, "PartialUpdate", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("swapping national ID for passport is allowed", func(t *testing.T) {
+		mockRepo := mocks.NewMockPatientRepository()
+		mockRepo.On("GetByID", uint(1), "tenant_test").Return(existing, nil)
+		mockRepo.On("PartialUpdate", uint(1), uint(1), mock.Anything, mock.AnythingOfType("*domain.AuditEvent"), "tenant_test").Return(nil)
+
+		service := services.NewPatientService(mockRepo, mocks.NewMockAuditRepository(), mocks.NewMockTenantService())
+		request := &domain.PatientPartialUpdateRequest{NationalID: &blank, PassportID: &passportID}
+		_, err := service.PartialUpdate(1, request, 1, testActor, "tenant_test")
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("legacy record without identifiers can change other fields", func(t *testing.T) {
+		legacy := &domain.Patient{FirstNameEN: "John"}
+		legacy.ID = 1
+		legacy.Version = 1
+		phone := "0812345678"
+
+		mockRepo := mocks.NewMockPatientRepository()
+		mockRepo.On("GetByID", uint(1), "tenant_test").Return(legacy, nil)
+		mockRepo.On("PartialUpdate", uint(1), uint(1), mock.Anything, mock.AnythingOfType("*domain.AuditEvent"), "tenant_test").Return(nil)
+
+		service := services.NewPatientService(mockRepo, mocks.NewMockAuditRepository(), mocks.NewMockTenantService())
+		_, err := service.PartialUpdate(1, &domain.PatientPartialUpdateRequest{PhoneNumber: &phone}, 1, testActor, "tenant_test")
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestPatientService_Merge(t *testing.T) {
+	survivor := &domain.Patient{PatientHN: "HOSP0001-00000001"}
+	survivor.ID = 1
+	retired := &domain.Patient{PatientHN: "HOSP0001-00000002"}
+	retired.ID = 2
+
+	t.Run("retires the duplicate and audits both records", func(t *testing.T) {
+		mockRepo := mocks.NewMockPatientRepository()
+		mockRepo.On("GetByID", uint(1), "tenant_test").Return(survivor, nil)
+		mockRepo.On("GetByID", uint(2), "tenant_test").Return(retired, nil)
+		mockRepo.On("Merge", mock.MatchedBy(func(merge *domain.PatientMerge) bool {
+			return merge.SurvivorID == 1 && merge.RetiredHN == "HOSP0001-00000002" && merge.MergedBy == testActor.StaffID
+		}), mock.MatchedBy(func(events []*domain.AuditEvent) bool {
+			return len(events) == 2 &&
+				events[0].Action == domain.AuditActionPatientMerge && *events[0].PatientID == 1 &&
+				events[1].Action == domain.AuditActionPatientMerge && *events[1].PatientID == 2
+		}), "tenant_test").Return(nil)
+
+		service := services.NewPatientService(mockRepo, mocks.NewMockAuditRepository(), mocks.NewMockTenantService())
+		merge, err := service.Merge(&domain.PatientMergeRequest{SurvivorID: 1, RetiredID: 2, Reason: " registered twice "}, testActor, "tenant_test")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "registered twice", merge.Reason)
+		assert.True(t, merge.IsActive())
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("a patient cannot be merged into itself", func(t *testing.T) {
+		mockRepo := mocks.NewMockPatientRepository()
+
+		service := services.NewPatientService(mockRepo, mocks.NewMockAuditRepository(), mocks.NewMockTenantService())
+		merge, err := service.Merge(&domain.PatientMergeRequest{SurvivorID: 1, RetiredID: 1, Reason: "x"}, testActor, "tenant_test")
+
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+		assert.Nil(t, merge)
+		mockRepo.AssertNotCalled(t, "Merge", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("retired patient not found", func(t *testing.T) {
+		mockRepo := mocks.NewMockPatientRepository()
+		mockRepo.On("GetByID", uint(1), "tenant_test").Return(survivor, nil)
+		mockRepo.On("GetByID", uint(3), "tenant_test").Return(nil, gorm.ErrRecordNotFound)
+
+		service := services.NewPatientService(mockRepo, mocks.NewMockAuditRepository(), mocks.NewMockTenantService())
+		merge, err := service.Merge(&domain.PatientMergeRequest{SurvivorID: 1, RetiredID: 3, Reason: "x"}, testActor, "tenant_test")
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		assert.Nil(t, merge)
+	})
+}
+
+func TestPatientService_Unmerge(t *testing.T) {
+	t.Run("restores the retired patient", func(t *testing.T) {
+		active := &domain.PatientMerge{ID: 5, SurvivorID: 1, RetiredID: 2, SurvivorHN: "HOSP0001-00000001", RetiredHN: "HOSP0001-00000002"}
+
+		mockRepo := mocks.NewMockPatientRepository()
+		mockRepo.On("GetMerge", uint(5), "tenant_test").Return(active, nil)
+		mockRepo.On("Unmerge", mock.MatchedBy(func(merge *domain.PatientMerge) bool {
+			return merge.UnmergedAt != nil && *merge.UnmergedBy == testActor.StaffID
+		}), mock.MatchedBy(func(events []*domain.AuditEvent) bool {
+			return len(events) == 2 && events[0].Action == domain.AuditActionPatientUnmerge
+		}), "tenant_test").Return(nil)
+
+		service := services.NewPatientService(mockRepo, mocks.NewMockAuditRepository(), mocks.NewMockTenantService())
+		merge, err := service.Unmerge(5, testActor, "tenant_test")
+
+		assert.NoError(t, err)
+		assert.False(t, merge.IsActive())
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("an already reversed merge is rejected", func(t *testing.T) {
+		unmergedAt := time.Now()
+		reversed := &domain.PatientMerge{ID: 5, SurvivorID: 1, RetiredID: 2, UnmergedAt: &unmergedAt}
+
+		mockRepo := mocks.NewMockPatientRepository()
+		mockRepo.On("GetMerge", uint(5), "tenant_test").Return(reversed, nil)
+
+		service := services.NewPatientService(mockRepo, mocks.NewMockAuditRepository(), mocks.NewMockTenantService())
+		merge, err := service.Unmerge(5, testActor, "tenant_test")
+
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+		assert.Nil(t, merge)
+		mockRepo.AssertNotCalled(t, "Unmerge", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestPatientService_History(t *testing.T) {
+	patient := &domain.Patient{PatientHN: "HOSP0001-00000001"}
+	patient.ID = 1
+	versions := []domain.PatientVersion{
+		{PatientID: 1, Version: 1, Action: domain.PatientVersionCreate, Snapshot: domain.PatientSnapshot{"first_name_en": "Somchai", "blood_grp": "A"}},
+		{PatientID: 1, Version: 2, Action: domain.PatientVersionPartialUpdate, Snapshot: domain.PatientSnapshot{"first_name_en": "Somchai", "blood_grp": "B"}},
+		{PatientID: 1, Version: 3, Action: domain.PatientVersionUpdate, Snapshot: domain.PatientSnapshot{"first_name_en": "Somchay", "blood_grp": "B"}},
+	}
+
+	t.Run("each version carries its changes", func(t *testing.T) {
+		mockRepo := mocks.NewMockPatientRepository()
+		mockAudit := mocks.NewMockAuditRepository()
+		mockRepo.On("GetByID", uint(1), "tenant_test").Return(patient, nil)
+		mockRepo.On("ListVersions", uint(1), "tenant_test").Return(versions, nil)
+		mockAudit.On("Append", mock.MatchedBy(func(events []*domain.AuditEvent) bool {
+			return len(events) == 1 && events[0].Action == domain.AuditActionPatientHistory
+		}), "tenant_test").Return(nil)
+
+		service := services.NewPatientService(mockRepo, mockAudit, mocks.NewMockTenantService())
+		history, err := service.History(1, testActor, "tenant_test")
+
+		assert.NoError(t, err)
+		assert.Len(t, history, 3)
+		assert.Empty(t, history[0].Changes)
+		assert.Equal(t, map[string]domain.FieldChange{"blood_grp": {Before: "A", After: "B"}}, history[1].Changes)
+		assert.Equal(t, map[string]domain.FieldChange{"first_name_en": {Before: "Somchai", After: "Somchay"}}, history[2].Changes)
+		mockAudit.AssertExpectations(t)
+	})
+
+	t.Run("diff of two versions", func(t *testing.T) {
+		mockRepo := mocks.NewMockPatientRepository()
+		mockAudit := mocks.NewMockAuditRepository()
+		mockRepo.On("GetByID", uint(1), "tenant_test").Return(patient, nil)
+		mockRepo.On("ListVersions", uint(1), "tenant_test").Return(versions, nil)
+		mockAudit.On("Append", mock.Anything, "tenant_test").Return(nil)
+
+		service := services.NewPatientService(mockRepo, mockAudit, mocks.NewMockTenantService())
+		diff, err := service.DiffVersions(1, 1, 3, testActor, "tenant_test")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, diff.From)
+		assert.Equal(t, 3, diff.To)
+		assert.Equal(t, map[string]domain.FieldChange{
+			"first_name_en": {Before: "Somchai", After: "Somchay"},
+			"blood_grp":     {Before: "A", After: "B"},
+		}, diff.Changes)
+	})
+
+	t.Run("unknown version", func(t *testing.T) {
+		mockRepo := mocks.NewMockPatientRepository()
+		mockAudit := mocks.NewMockAuditRepository()
+		mockRepo.On("GetByID", uint(1), "tenant_test").Return(patient, nil)
+		mockRepo.On("ListVersions", uint(1), "tenant_test").Return(versions, nil)
+
+		service := services.NewPatientService(mockRepo, mockAudit, mocks.NewMockTenantService())
+		diff, err := service.DiffVersions(1, 1, 4, testActor, "tenant_test")
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		assert.Nil(t, diff)
+		mockAudit.AssertNotCalled(t, "Append", mock.Anything, mock.Anything)
+	})
+}
+
+func TestPatientService_Update_StaleVersion(t *testing.T) {
+	current := &domain.Patient{FirstNameEN: "John", NationalID: "1234567890121"}
+	current.ID = 1
+	current.Version = 4
+
+	t.Run("full update", func(t *testing.T) {
+		mockRepo := mocks.NewMockPatientRepository()
+		mockRepo.On("GetByID", uint(1), "tenant_test").Return(current, nil)
+
+		service := services.NewPatientService(mockRepo, mocks.NewMockAuditRepository(), mocks.NewMockTenantService())
+		request := &domain.PatientUpdateRequest{FirstNameEN: "Jon", DateOfBirth: "1990-01-15", NationalID: "1234567890121", Gender: "M", Nationality: "Thai", BloodGrp: "O"}
+		result, err := service.Update(1, request, 3, testActor, "tenant_test")
+
+		assert.ErrorIs(t, err, domain.ErrPreconditionFailed)
+		assert.Nil(t, result)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("a concurrent write between read and update", func(t *testing.T) {
+		firstName := "Jon"
+		mockRepo := mocks.NewMockPatientRepository()
+		mockRepo.On("GetByID", uint(1), "tenant_test").Return(current, nil)
+		mockRepo.On("PartialUpdate", uint(1), uint(4), mock.Anything, mock.AnythingOfType("*domain.AuditEvent"), "tenant_test").Return(domain.ErrPreconditionFailed)
+
+		service := services.NewPatientService(mockRepo, mocks.NewMockAuditRepository(), mocks.NewMockTenantService())
+		result, err := service.PartialUpdate(1, &domain.PatientPartialUpdateRequest{FirstNameEN: &firstName}, 4, testActor, "tenant_test")
+
+		assert.ErrorIs(t, err, domain.ErrPreconditionFailed)
+		assert.Nil(t, result)
+	})
+}
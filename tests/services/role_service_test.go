@@ -0,0 +1,223 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/mocks"
+	"github.com/wichai2002/his_v1/internal/services"
+)
+
+func TestRoleService_Create(t *testing.T) {
+	tests := []struct {
+		name          string
+		request       *domain.RoleCreateRequest
+		found         []domain.Permission
+		expectCreate  bool
+		expectedError error
+	}{
+		{
+			name: "successful role creation",
+			request: &domain.RoleCreateRequest{
+				Code:        "Lab_Tech",
+				Name:        "Lab Technician",
+				Permissions: []string{domain.PermPatientRead},
+			},
+			found:        []domain.Permission{{ID: 1, Code: domain.PermPatientRead}},
+			expectCreate: true,
+		},
+		{
+			name: "unknown permission code",
+			request: &domain.RoleCreateRequest{
+				Code:        "lab_tech",
+				Name:        "Lab Technician",
+				Permissions: []string{domain.PermPatientRead, "lab:fly"},
+			},
+			found:         []domain.Permission{{ID: 1, Code: domain.PermPatientRead}},
+			expectedError: domain.ErrInvalidInput,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockRoleRepository()
+			mockRepo.On("GetPermissionsByCodes", tt.request.Permissions, "tenant_test").Return(tt.found, nil)
+			if tt.expectCreate {
+				mockRepo.On("Create", mock.MatchedBy(func(role *domain.Role) bool {
+					return role.Code == "lab_tech" && len(role.Permissions) == 1
+				}), "tenant_test").Return(nil)
+			}
+
+			service := services.NewRoleService(mockRepo)
+			result, err := service.Create(tt.request, "tenant_test")
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "lab_tech", result.Code)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRoleService_Update(t *testing.T) {
+	read := domain.Permission{ID: 1, Code: domain.PermPatientRead}
+	manage := domain.Permission{ID: 6, Code: domain.PermRoleManage}
+
+	tests := []struct {
+		name          string
+		role          *domain.Role
+		request       *domain.RoleUpdateRequest
+		found         []domain.Permission
+		expectUpdate  bool
+		expectedError error
+	}{
+		{
+			name:         "custom role permissions can change",
+			role:         &domain.Role{Code: "lab_tech", Permissions: []domain.Permission{read}},
+			request:      &domain.RoleUpdateRequest{Name: "Lab", Permissions: []string{domain.PermPatientRead, domain.PermRoleManage}},
+			found:        []domain.Permission{read, manage},
+			expectUpdate: true,
+		},
+		{
+			name:         "system role can be renamed",
+			role:         &domain.Role{Code: domain.RoleAdmin, IsSystem: true, Permissions: []domain.Permission{read, manage}},
+			request:      &domain.RoleUpdateRequest{Name: "Admins", Permissions: []string{domain.PermRoleManage, domain.PermPatientRead}},
+			found:        []domain.Permission{read, manage},
+			expectUpdate: true,
+		},
+		{
+			name:          "admin role cannot lose role:manage",
+			role:          &domain.Role{Code: domain.RoleAdmin, IsSystem: true, Permissions: []domain.Permission{read, manage}},
+			request:       &domain.RoleUpdateRequest{Name: "Administrator", Permissions: []string{domain.PermPatientRead}},
+			found:         []domain.Permission{read},
+			expectedError: domain.ErrInvalidInput,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockRoleRepository()
+			mockRepo.On("GetByID", uint(5), "tenant_test").Return(tt.role, nil)
+			mockRepo.On("GetPermissionsByCodes", tt.request.Permissions, "tenant_test").Return(tt.found, nil)
+			if tt.expectUpdate {
+				mockRepo.On("Update", mock.AnythingOfType("*domain.Role"), "tenant_test").Return(nil)
+			}
+
+			service := services.NewRoleService(mockRepo)
+			result, err := service.Update(5, tt.request, "tenant_test")
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.request.Name, result.Name)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRoleService_Delete(t *testing.T) {
+	tests := []struct {
+		name          string
+		role          *domain.Role
+		expectDelete  bool
+		expectedError error
+	}{
+		{
+			name:         "custom role can be deleted",
+			role:         &domain.Role{Code: "lab_tech"},
+			expectDelete: true,
+		},
+		{
+			name:          "system role cannot be deleted",
+			role:          &domain.Role{Code: domain.RoleAdmin, IsSystem: true},
+			expectedError: domain.ErrInvalidInput,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockRoleRepository()
+			mockRepo.On("GetByID", uint(5), "tenant_test").Return(tt.role, nil)
+			if tt.expectDelete {
+				mockRepo.On("Delete", uint(5), "tenant_test").Return(nil)
+			}
+
+			service := services.NewRoleService(mockRepo)
+			err := service.Delete(5, "tenant_test")
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRoleService_AssignStaffRoles(t *testing.T) {
+	tests := []struct {
+		name          string
+		codes         []string
+		found         []domain.Role
+		expectReplace bool
+		expectedError error
+	}{
+		{
+			name:          "assign known roles",
+			codes:         []string{domain.RoleDoctor, domain.RoleNurse},
+			found:         []domain.Role{{Code: domain.RoleDoctor}, {Code: domain.RoleNurse}},
+			expectReplace: true,
+		},
+		{
+			name:          "unknown role code",
+			codes:         []string{domain.RoleDoctor, "janitor"},
+			found:         []domain.Role{{Code: domain.RoleDoctor}},
+			expectedError: domain.ErrInvalidInput,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockRoleRepository()
+			mockRepo.On("GetByCodes", tt.codes, "tenant_test").Return(tt.found, nil)
+			if tt.expectReplace {
+				mockRepo.On("ReplaceStaffRoles", uint(2), tt.found, "tenant_test").Return(nil)
+			}
+
+			service := services.NewRoleService(mockRepo)
+			err := service.AssignStaffRoles(2, &domain.StaffRolesRequest{Roles: tt.codes}, "tenant_test")
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRoleService_AssignStaffRoles_ClearAll(t *testing.T) {
+	mockRepo := mocks.NewMockRoleRepository()
+	mockRepo.On("ReplaceStaffRoles", uint(2), []domain.Role{}, "tenant_test").Return(nil)
+
+	service := services.NewRoleService(mockRepo)
+	err := service.AssignStaffRoles(2, &domain.StaffRolesRequest{Roles: []string{}}, "tenant_test")
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
@@ -3,12 +3,14 @@ package services_test
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/wichai2002/his_v1/internal/domain"
 	"github.com/wichai2002/his_v1/internal/mocks"
 	"github.com/wichai2002/his_v1/internal/services"
+	"github.com/wichai2002/his_v1/pkg/jwt"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -41,7 +43,6 @@ func TestStaffService_Login(t *testing.T) {
 				Email:     "admin@test.com",
 				FirstName: "Admin",
 				LastName:  "User",
-				IsAdmin:   true,
 			},
 			mockError:   nil,
 			mockToken:   "jwt-token-here",
@@ -99,6 +100,7 @@ func TestStaffService_Login(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := mocks.NewMockStaffRepository()
+			mockTokenRepo := mocks.NewMockTokenRepository()
 			mockJWT := mocks.NewMockJWTService()
 
 			mockRepo.On("GetByUsername", tt.request.Username, tt.schemaName).Return(tt.mockStaff, tt.mockError)
@@ -107,11 +109,20 @@ func TestStaffService_Login(t *testing.T) {
 				// Only expect token generation if password verification will pass
 				err := bcrypt.CompareHashAndPassword([]byte(tt.mockStaff.Password), []byte(tt.request.Password))
 				if err == nil {
-					mockJWT.On("GenerateToken", tt.mockStaff.ID, tt.mockStaff.Username, tt.mockStaff.IsAdmin, tt.schemaName).Return(tt.mockToken, tt.tokenError)
+					if tt.tokenError != nil {
+						mockJWT.On("GenerateToken", tt.mockStaff.ID, tt.mockStaff.Username, tt.mockStaff.HasRole(domain.RoleAdmin), tt.mockStaff.RoleCodes(), tt.mockStaff.PermissionCodes(), tt.schemaName).Return(nil, tt.tokenError)
+					} else {
+						accessToken := &jwt.AccessToken{Token: tt.mockToken, ID: "jti-1", ExpiresAt: time.Now().Add(15 * time.Minute)}
+						mockJWT.On("GenerateToken", tt.mockStaff.ID, tt.mockStaff.Username, tt.mockStaff.HasRole(domain.RoleAdmin), tt.mockStaff.RoleCodes(), tt.mockStaff.PermissionCodes(), tt.schemaName).Return(accessToken, nil)
+						mockJWT.On("GenerateRefreshToken").Return("refresh-token", time.Now().Add(time.Hour), nil)
+						mockTokenRepo.On("CreateRefreshToken", mock.MatchedBy(func(token *domain.RefreshToken) bool {
+							return token.AccessTokenID == "jti-1" && token.TokenHash == jwt.HashToken("refresh-token")
+						}), tt.schemaName).Return(nil)
+					}
 				}
 			}
 
-			service := services.NewStaffService(mockRepo, mockJWT)
+			service := services.NewStaffService(mockRepo, mockTokenRepo, mockJWT)
 			result, err := service.Login(tt.request, tt.schemaName)
 
 			if tt.expectError {
@@ -124,10 +135,12 @@ func TestStaffService_Login(t *testing.T) {
 				assert.NoError(t, err)
 				assert.NotNil(t, result)
 				assert.Equal(t, tt.mockToken, result.Token)
+				assert.Equal(t, "refresh-token", result.RefreshToken)
 				assert.Equal(t, tt.mockStaff.Username, result.Staff.Username)
 			}
 
 			mockRepo.AssertExpectations(t)
+			mockTokenRepo.AssertExpectations(t)
 			mockJWT.AssertExpectations(t)
 		})
 	}
@@ -178,7 +191,7 @@ func TestStaffService_GetAll(t *testing.T) {
 
 			mockRepo.On("GetAll", tt.schemaName).Return(tt.mockStaffs, tt.mockError)
 
-			service := services.NewStaffService(mockRepo, mockJWT)
+			service := services.NewStaffService(mockRepo, mocks.NewMockTokenRepository(), mockJWT)
 			result, err := service.GetAll(tt.schemaName)
 
 			if tt.expectError {
@@ -232,7 +245,7 @@ func TestStaffService_GetByID(t *testing.T) {
 
 			mockRepo.On("GetByID", tt.id, tt.schemaName).Return(tt.mockStaff, tt.mockError)
 
-			service := services.NewStaffService(mockRepo, mockJWT)
+			service := services.NewStaffService(mockRepo, mocks.NewMockTokenRepository(), mockJWT)
 			result, err := service.GetByID(tt.id, tt.schemaName)
 
 			if tt.expectError {
@@ -266,7 +279,6 @@ func TestStaffService_Create(t *testing.T) {
 				Email:       "newuser@test.com",
 				FirstName:   "New",
 				LastName:    "User",
-				IsAdmin:     false,
 			},
 			schemaName:  "tenant_test",
 			createError: nil,
@@ -295,7 +307,7 @@ func TestStaffService_Create(t *testing.T) {
 
 			mockRepo.On("Create", mock.AnythingOfType("*domain.Staff"), tt.schemaName).Return(tt.createError)
 
-			service := services.NewStaffService(mockRepo, mockJWT)
+			service := services.NewStaffService(mockRepo, mocks.NewMockTokenRepository(), mockJWT)
 			result, err := service.Create(tt.request, tt.schemaName)
 
 			if tt.expectError {
@@ -348,7 +360,6 @@ func TestStaffService_Update(t *testing.T) {
 				Email:       "updated@test.com",
 				FirstName:   "Updated",
 				LastName:    "Name",
-				IsAdmin:     true,
 			},
 			schemaName:  "tenant_test",
 			mockStaff:   existingStaff,
@@ -401,7 +412,7 @@ func TestStaffService_Update(t *testing.T) {
 				mockRepo.On("Update", mock.AnythingOfType("*domain.Staff"), tt.schemaName).Return(tt.updateError)
 			}
 
-			service := services.NewStaffService(mockRepo, mockJWT)
+			service := services.NewStaffService(mockRepo, mocks.NewMockTokenRepository(), mockJWT)
 			result, err := service.Update(tt.id, tt.request, tt.schemaName)
 
 			if tt.expectError {
@@ -446,11 +457,13 @@ func TestStaffService_Delete(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := mocks.NewMockStaffRepository()
+			mockTokenRepo := mocks.NewMockTokenRepository()
 			mockJWT := mocks.NewMockJWTService()
 
+			// Token revocation happens inside the repository's delete transaction
 			mockRepo.On("Delete", tt.id, tt.schemaName).Return(tt.deleteError)
 
-			service := services.NewStaffService(mockRepo, mockJWT)
+			service := services.NewStaffService(mockRepo, mockTokenRepo, mockJWT)
 			err := service.Delete(tt.id, tt.schemaName)
 
 			if tt.expectError {
@@ -460,6 +473,159 @@ func TestStaffService_Delete(t *testing.T) {
 			}
 
 			mockRepo.AssertExpectations(t)
+			mockTokenRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestStaffService_RefreshToken(t *testing.T) {
+	revokedAt := time.Now().Add(-time.Minute)
+	staff := &domain.Staff{Username: "admin", Roles: []domain.Role{{Code: domain.RoleAdmin}}}
+	staff.ID = 7
+
+	tests := []struct {
+		name          string
+		storedToken   *domain.RefreshToken
+		lookupError   error
+		rotateError   error
+		expectReuse   bool
+		expectRotate  bool
+		expectedError error
+	}{
+		{
+			name: "successful rotation",
+			storedToken: &domain.RefreshToken{
+				ID: 1, StaffID: 7, ExpiresAt: time.Now().Add(time.Hour),
+			},
+			expectRotate: true,
+		},
+		{
+			name:          "unknown refresh token",
+			lookupError:   errors.New("record not found"),
+			expectedError: domain.ErrInvalidToken,
+		},
+		{
+			name: "expired refresh token",
+			storedToken: &domain.RefreshToken{
+				ID: 1, StaffID: 7, ExpiresAt: time.Now().Add(-time.Hour),
+			},
+			expectedError: domain.ErrInvalidToken,
+		},
+		{
+			name: "reused refresh token revokes all sessions",
+			storedToken: &domain.RefreshToken{
+				ID: 1, StaffID: 7, ExpiresAt: time.Now().Add(time.Hour), RevokedAt: &revokedAt,
+			},
+			expectReuse:   true,
+			expectedError: domain.ErrInvalidToken,
+		},
+		{
+			name: "concurrent rotation loses the race",
+			storedToken: &domain.RefreshToken{
+				ID: 1, StaffID: 7, ExpiresAt: time.Now().Add(time.Hour),
+			},
+			rotateError:   domain.ErrInvalidToken,
+			expectRotate:  true,
+			expectedError: domain.ErrInvalidToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockStaffRepository()
+			mockTokenRepo := mocks.NewMockTokenRepository()
+			mockJWT := mocks.NewMockJWTService()
+
+			mockTokenRepo.On("GetRefreshTokenByHash", jwt.HashToken("old-refresh"), "tenant_test").Return(tt.storedToken, tt.lookupError)
+			if tt.expectReuse {
+				mockTokenRepo.On("RevokeAllForStaff", uint(7), domain.RevokeReasonTokenReuse, "tenant_test").Return(nil)
+			}
+			if tt.expectRotate {
+				mockRepo.On("GetByID", uint(7), "tenant_test").Return(staff, nil)
+				mockJWT.On("GenerateToken", uint(7), "admin", true, staff.RoleCodes(), staff.PermissionCodes(), "tenant_test").
+					Return(&jwt.AccessToken{Token: "new-access", ID: "jti-2", ExpiresAt: time.Now().Add(15 * time.Minute)}, nil)
+				mockJWT.On("GenerateRefreshToken").Return("new-refresh", time.Now().Add(time.Hour), nil)
+				mockTokenRepo.On("RotateRefreshToken", uint(1), mock.AnythingOfType("*domain.RefreshToken"), "tenant_test").Return(tt.rotateError)
+			}
+
+			service := services.NewStaffService(mockRepo, mockTokenRepo, mockJWT)
+			result, err := service.RefreshToken(&domain.TokenRefreshRequest{RefreshToken: "old-refresh"}, "tenant_test")
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "new-access", result.Token)
+				assert.Equal(t, "new-refresh", result.RefreshToken)
+			}
+
+			mockRepo.AssertExpectations(t)
+			mockTokenRepo.AssertExpectations(t)
+			mockJWT.AssertExpectations(t)
+		})
+	}
+}
+
+func TestStaffService_Logout(t *testing.T) {
+	mockTokenRepo := mocks.NewMockTokenRepository()
+	mockTokenRepo.On("RevokeAllForStaff", uint(3), domain.RevokeReasonLogout, "tenant_test").Return(nil)
+
+	service := services.NewStaffService(mocks.NewMockStaffRepository(), mockTokenRepo, mocks.NewMockJWTService())
+	err := service.Logout(3, "tenant_test")
+
+	assert.NoError(t, err)
+	mockTokenRepo.AssertExpectations(t)
+}
+
+func TestStaffService_ChangePassword(t *testing.T) {
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
+
+	tests := []struct {
+		name            string
+		currentPassword string
+		expectedError   error
+	}{
+		{
+			name:            "successful password change revokes tokens",
+			currentPassword: "password123",
+		},
+		{
+			name:            "wrong current password",
+			currentPassword: "wrongpassword",
+			expectedError:   domain.ErrInvalidCredentials,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockStaffRepository()
+			mockTokenRepo := mocks.NewMockTokenRepository()
+
+			staff := &domain.Staff{Username: "admin", Password: string(hashedPassword)}
+			staff.ID = 3
+			mockRepo.On("GetByID", uint(3), "tenant_test").Return(staff, nil)
+			if tt.expectedError == nil {
+				// The repository revokes every session in the same transaction as the password update
+				mockRepo.On("UpdatePassword", uint(3), mock.MatchedBy(func(hash string) bool {
+					return bcrypt.CompareHashAndPassword([]byte(hash), []byte("newpassword")) == nil
+				}), "tenant_test").Return(nil)
+			}
+
+			service := services.NewStaffService(mockRepo, mockTokenRepo, mocks.NewMockJWTService())
+			err := service.ChangePassword(3, &domain.StaffChangePasswordRequest{
+				CurrentPassword: tt.currentPassword,
+				NewPassword:     "newpassword",
+			}, "tenant_test")
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			mockRepo.AssertExpectations(t)
+			mockTokenRepo.AssertExpectations(t)
 		})
 	}
 }
@@ -0,0 +1,143 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/mocks"
+	"github.com/wichai2002/his_v1/internal/services"
+)
+
+const testRetention = 30 * 24 * time.Hour
+
+func TestTrashService_RestorePatient(t *testing.T) {
+	deleted := &domain.Patient{PatientHN: "HOSP0001-00000001", NationalID: "1234567890121", Email: "a@example.com"}
+	deleted.ID = 1
+
+	t.Run("restores and audits", func(t *testing.T) {
+		mockPatientRepo := mocks.NewMockPatientRepository()
+		mockPatientRepo.On("GetDeleted", uint(1), "tenant_test").Return(deleted, nil)
+		mockPatientRepo.On("FindLiveByUniqueFields", deleted, "tenant_test").Return([]domain.Patient{}, nil)
+		mockPatientRepo.On("Restore", uint(1), mock.MatchedBy(func(event *domain.AuditEvent) bool {
+			return event.Action == domain.AuditActionPatientRestore && *event.PatientID == 1
+		}), "tenant_test").Return(nil)
+
+		service := services.NewTrashService(mockPatientRepo, mocks.NewMockStaffRepository(), testRetention)
+		patient, err := service.RestorePatient(1, testActor, "tenant_test")
+
+		assert.NoError(t, err)
+		assert.False(t, patient.DeletedAt.Valid)
+		mockPatientRepo.AssertExpectations(t)
+	})
+
+	t.Run("a national ID taken by a new patient blocks the restore", func(t *testing.T) {
+		newcomer := domain.Patient{NationalID: "1234567890121", Email: "b@example.com"}
+		newcomer.ID = 9
+
+		mockPatientRepo := mocks.NewMockPatientRepository()
+		mockPatientRepo.On("GetDeleted", uint(1), "tenant_test").Return(deleted, nil)
+		mockPatientRepo.On("FindLiveByUniqueFields", deleted, "tenant_test").Return([]domain.Patient{newcomer}, nil)
+
+		service := services.NewTrashService(mockPatientRepo, mocks.NewMockStaffRepository(), testRetention)
+		patient, err := service.RestorePatient(1, testActor, "tenant_test")
+
+		var conflictErr *domain.RestoreConflictError
+		assert.True(t, errors.As(err, &conflictErr))
+		assert.ErrorIs(t, err, domain.ErrDuplicateEntry)
+		assert.Equal(t, []domain.RestoreConflict{{Field: "national_id", Value: "1234567890121", ExistingID: 9}}, conflictErr.Conflicts)
+		assert.Nil(t, patient)
+		mockPatientRepo.AssertNotCalled(t, "Restore", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestTrashService_PurgePatients(t *testing.T) {
+	expired := []domain.Patient{{PatientHN: "HOSP0001-00000001"}, {PatientHN: "HOSP0001-00000002"}}
+	expired[0].ID = 1
+	expired[1].ID = 2
+
+	mockPatientRepo := mocks.NewMockPatientRepository()
+	mockPatientRepo.On("ListDeleted", mock.MatchedBy(func(before *time.Time) bool {
+		// Only records deleted longer ago than the retention period are purged
+		return before != nil && time.Since(*before) >= testRetention
+	}), "tenant_test").Return(expired, nil)
+	mockPatientRepo.On("Purge", []uint{1, 2}, mock.AnythingOfType("time.Time"), mock.MatchedBy(func(events []*domain.AuditEvent) bool {
+		return len(events) == 2 && events[0].Action == domain.AuditActionPatientPurge && *events[1].PatientID == 2
+	}), "tenant_test").Return(1, []domain.PurgeRetention{{ID: 2, Reason: domain.PurgeRetainedHistory}}, nil)
+
+	service := services.NewTrashService(mockPatientRepo, mocks.NewMockStaffRepository(), testRetention)
+	result, err := service.PurgePatients(testActor, "tenant_test")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Purged)
+	// Patients with clinical history are kept and reported instead of failing the purge
+	assert.Equal(t, []domain.PurgeRetention{{ID: 2, Reason: domain.PurgeRetainedHistory}}, result.Retained)
+	mockPatientRepo.AssertExpectations(t)
+}
+
+func TestTrashService_RestoreStaff(t *testing.T) {
+	deleted := &domain.Staff{Username: "nurse01", StaffCode: "STF1", PhoneNumber: "0811111111", Email: "n@example.com"}
+	deleted.ID = 3
+
+	t.Run("restores", func(t *testing.T) {
+		mockStaffRepo := mocks.NewMockStaffRepository()
+		mockStaffRepo.On("GetDeleted", uint(3), "tenant_test").Return(deleted, nil)
+		mockStaffRepo.On("FindLiveByUniqueFields", deleted, "tenant_test").Return([]domain.Staff{}, nil)
+		mockStaffRepo.On("Restore", uint(3), "tenant_test").Return(nil)
+
+		service := services.NewTrashService(mocks.NewMockPatientRepository(), mockStaffRepo, testRetention)
+		staff, err := service.RestoreStaff(3, "tenant_test")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "nurse01", staff.Username)
+		mockStaffRepo.AssertExpectations(t)
+	})
+
+	t.Run("a username taken since blocks the restore", func(t *testing.T) {
+		replacement := domain.Staff{Username: "nurse01", StaffCode: "STF2"}
+		replacement.ID = 4
+
+		mockStaffRepo := mocks.NewMockStaffRepository()
+		mockStaffRepo.On("GetDeleted", uint(3), "tenant_test").Return(deleted, nil)
+		mockStaffRepo.On("FindLiveByUniqueFields", deleted, "tenant_test").Return([]domain.Staff{replacement}, nil)
+
+		service := services.NewTrashService(mocks.NewMockPatientRepository(), mockStaffRepo, testRetention)
+		staff, err := service.RestoreStaff(3, "tenant_test")
+
+		assert.ErrorIs(t, err, domain.ErrDuplicateEntry)
+		assert.Nil(t, staff)
+		mockStaffRepo.AssertNotCalled(t, "Restore", mock.Anything, mock.Anything)
+	})
+}
+
+func TestTrashService_PurgeStaff_NothingExpired(t *testing.T) {
+	mockStaffRepo := mocks.NewMockStaffRepository()
+	mockStaffRepo.On("ListDeleted", mock.AnythingOfType("*time.Time"), "tenant_test").Return([]domain.Staff{}, nil)
+	mockStaffRepo.On("Purge", []uint{}, mock.AnythingOfType("time.Time"), "tenant_test").Return(0, nil, nil)
+
+	service := services.NewTrashService(mocks.NewMockPatientRepository(), mockStaffRepo, testRetention)
+	result, err := service.PurgeStaff("tenant_test")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, result.Purged)
+	assert.Empty(t, result.Retained)
+}
+
+func TestTrashService_PurgeStaff_RetainsDoctorWithAppointments(t *testing.T) {
+	mockStaffRepo := mocks.NewMockStaffRepository()
+	expired := make([]domain.Staff, 2)
+	expired[0].ID, expired[1].ID = 4, 5
+	mockStaffRepo.On("ListDeleted", mock.AnythingOfType("*time.Time"), "tenant_test").Return(expired, nil)
+	mockStaffRepo.On("Purge", []uint{4, 5}, mock.AnythingOfType("time.Time"), "tenant_test").
+		Return(1, []domain.PurgeRetention{{ID: 5, Reason: domain.PurgeRetainedAppointments}}, nil)
+
+	service := services.NewTrashService(mocks.NewMockPatientRepository(), mockStaffRepo, testRetention)
+	result, err := service.PurgeStaff("tenant_test")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Purged)
+	assert.Equal(t, []domain.PurgeRetention{{ID: 5, Reason: domain.PurgeRetainedAppointments}}, result.Retained)
+}
@@ -0,0 +1,185 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wichai2002/his_v1/internal/domain"
+	"github.com/wichai2002/his_v1/internal/mocks"
+	"github.com/wichai2002/his_v1/internal/services"
+	"gorm.io/gorm"
+)
+
+type vitalSignMocks struct {
+	vitalRepo     *mocks.MockVitalSignRepository
+	encounterRepo *mocks.MockEncounterRepository
+	patientRepo   *mocks.MockPatientRepository
+	auditRepo     *mocks.MockAuditRepository
+}
+
+func newVitalSignService() (domain.VitalSignService, *vitalSignMocks) {
+	m := &vitalSignMocks{
+		vitalRepo:     mocks.NewMockVitalSignRepository(),
+		encounterRepo: mocks.NewMockEncounterRepository(),
+		patientRepo:   mocks.NewMockPatientRepository(),
+		auditRepo:     mocks.NewMockAuditRepository(),
+	}
+	return services.NewVitalSignService(m.vitalRepo, m.encounterRepo, m.patientRepo, m.auditRepo), m
+}
+
+func adultPatient() *domain.Patient {
+	patient := &domain.Patient{DateOfBirth: testDate("1980-06-01")}
+	patient.ID = 1
+	return patient
+}
+
+func openEncounter() *domain.Encounter {
+	encounter := &domain.Encounter{VN: "69031500001", PatientID: 1, Status: domain.EncounterStatusRegistered}
+	encounter.ID = 12
+	return encounter
+}
+
+func intValue(v int) *int           { return &v }
+func floatValue(v float64) *float64 { return &v }
+
+func TestVitalSignService_Record(t *testing.T) {
+	service, m := newVitalSignService()
+
+	m.encounterRepo.On("GetByID", uint(12), "tenant_test").Return(openEncounter(), nil)
+	m.patientRepo.On("GetByID", uint(1), "tenant_test").Return(adultPatient(), nil)
+	m.vitalRepo.On("LatestHeight", uint(1), "tenant_test").Return(floatValue(175), nil)
+	m.vitalRepo.On("Create", mock.MatchedBy(func(v *domain.VitalSign) bool {
+		return v.EncounterID == 12 && v.PatientID == 1 && v.RecordedBy == testActor.StaffID && !v.RecordedAt.IsZero()
+	}), mock.MatchedBy(func(e *domain.AuditEvent) bool {
+		return e.Action == domain.AuditActionPatientVitalsRecord && *e.PatientID == 1
+	}), "tenant_test").Return(nil)
+
+	req := &domain.VitalSignRequest{
+		SystolicBP:  intValue(120),
+		DiastolicBP: intValue(80),
+		Pulse:       intValue(110),
+		Weight:      floatValue(80),
+		TriageLevel: intValue(domain.TriageLevelUrgent),
+	}
+	vital, err := service.Record(12, req, testActor, "tenant_test")
+
+	assert.NoError(t, err)
+	// BMI uses the last height on record when the reading has none
+	assert.Equal(t, 26.1, *vital.BMI)
+	assert.Equal(t, []domain.VitalFlag{
+		{Measure: domain.VitalMeasurePulse, Value: 110, Flag: domain.VitalFlagHigh, RangeLow: 60, RangeHigh: 100},
+		{Measure: domain.VitalMeasureBMI, Value: 26.1, Flag: domain.VitalFlagHigh, RangeLow: 18.5, RangeHigh: 22.9},
+	}, vital.Flags)
+	m.vitalRepo.AssertExpectations(t)
+}
+
+func TestVitalSignService_Record_HeightInReading(t *testing.T) {
+	service, m := newVitalSignService()
+
+	m.encounterRepo.On("GetByID", uint(12), "tenant_test").Return(openEncounter(), nil)
+	m.patientRepo.On("GetByID", uint(1), "tenant_test").Return(adultPatient(), nil)
+	m.vitalRepo.On("Create", mock.Anything, mock.Anything, "tenant_test").Return(nil)
+
+	vital, err := service.Record(12, &domain.VitalSignRequest{Weight: floatValue(60), Height: floatValue(165)}, testActor, "tenant_test")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 22.0, *vital.BMI)
+	m.vitalRepo.AssertNotCalled(t, "LatestHeight", mock.Anything, mock.Anything)
+}
+
+func TestVitalSignService_Record_Errors(t *testing.T) {
+	done := openEncounter()
+	done.Status = domain.EncounterStatusDone
+
+	tests := []struct {
+		name          string
+		req           *domain.VitalSignRequest
+		encounter     *domain.Encounter
+		encounterErr  error
+		expectedError error
+	}{
+		{name: "no measures", req: &domain.VitalSignRequest{Note: "refused"}, expectedError: domain.ErrInvalidInput},
+		{name: "systolic without diastolic", req: &domain.VitalSignRequest{SystolicBP: intValue(120)}, expectedError: domain.ErrInvalidInput},
+		{name: "systolic below diastolic", req: &domain.VitalSignRequest{SystolicBP: intValue(70), DiastolicBP: intValue(80)}, expectedError: domain.ErrInvalidInput},
+		{name: "encounter not found", req: &domain.VitalSignRequest{Pulse: intValue(80)}, encounterErr: gorm.ErrRecordNotFound, expectedError: domain.ErrNotFound},
+		{name: "encounter done", req: &domain.VitalSignRequest{Pulse: intValue(80)}, encounter: done, expectedError: domain.ErrInvalidInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, m := newVitalSignService()
+
+			if tt.encounterErr != nil {
+				m.encounterRepo.On("GetByID", uint(12), "tenant_test").Return(nil, tt.encounterErr)
+			} else {
+				m.encounterRepo.On("GetByID", uint(12), "tenant_test").Return(tt.encounter, nil)
+			}
+
+			_, err := service.Record(12, tt.req, testActor, "tenant_test")
+
+			assert.ErrorIs(t, err, tt.expectedError)
+			m.vitalRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestVitalSignService_ListByPatient_Range(t *testing.T) {
+	service, m := newVitalSignService()
+
+	m.patientRepo.On("GetByID", uint(1), "tenant_test").Return(adultPatient(), nil)
+	m.vitalRepo.On("ListByPatient", mock.MatchedBy(func(f *domain.VitalSignFilter) bool {
+		// to is inclusive, so the range runs to midnight after it in Thai time
+		return f.PatientID == 1 &&
+			f.From.Equal(time.Date(2026, 3, 1, 0, 0, 0, 0, domain.HNTimeZone)) &&
+			f.To.Equal(time.Date(2026, 3, 16, 0, 0, 0, 0, domain.HNTimeZone))
+	}), "tenant_test").Return([]domain.VitalSign{{PatientID: 1, Temperature: floatValue(38.2), RecordedAt: time.Now()}}, nil)
+	m.auditRepo.On("Append", mock.MatchedBy(func(events []*domain.AuditEvent) bool {
+		return len(events) == 1 && events[0].Action == domain.AuditActionPatientVitalsView
+	}), "tenant_test").Return(nil)
+
+	vitals, err := service.ListByPatient(1, &domain.VitalSignListRequest{From: "2026-03-01", To: "2026-03-15"}, testActor, "tenant_test")
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.VitalFlagHigh, vitals[0].Flags[0].Flag)
+	m.vitalRepo.AssertExpectations(t)
+	m.auditRepo.AssertExpectations(t)
+}
+
+func TestVitalSignService_ListByPatient_InvalidRange(t *testing.T) {
+	service, m := newVitalSignService()
+
+	m.patientRepo.On("GetByID", uint(1), "tenant_test").Return(adultPatient(), nil)
+
+	_, err := service.ListByPatient(1, &domain.VitalSignListRequest{From: "2026-03-15", To: "2026-03-01"}, testActor, "tenant_test")
+
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	m.vitalRepo.AssertNotCalled(t, "ListByPatient", mock.Anything, mock.Anything)
+}
+
+func TestVitalSignService_Trend(t *testing.T) {
+	service, m := newVitalSignService()
+
+	recorded := time.Date(2026, 3, 15, 9, 0, 0, 0, domain.HNTimeZone)
+	m.patientRepo.On("GetByID", uint(1), "tenant_test").Return(adultPatient(), nil)
+	m.vitalRepo.On("Trend", uint(1), domain.DefaultVitalTrendLimit, "tenant_test").Return([]domain.VitalTrendPoint{
+		{Measure: domain.VitalMeasurePulse, EncounterID: 11, RecordedAt: recorded.AddDate(0, 0, -7), Value: 88},
+		{Measure: domain.VitalMeasurePulse, EncounterID: 12, RecordedAt: recorded, Value: 104},
+		{Measure: domain.VitalMeasureSystolicBP, EncounterID: 12, RecordedAt: recorded, Value: 128},
+	}, nil)
+	m.auditRepo.On("Append", mock.Anything, "tenant_test").Return(nil)
+
+	trends, err := service.Trend(1, &domain.VitalTrendRequest{}, testActor, "tenant_test")
+
+	assert.NoError(t, err)
+	// Trends follow the order of domain.VitalMeasures, not the order of the rows
+	assert.Len(t, trends, 2)
+	assert.Equal(t, domain.VitalMeasureSystolicBP, trends[0].Measure)
+	assert.Equal(t, "mmHg", trends[0].Unit)
+	assert.Equal(t, domain.VitalMeasurePulse, trends[1].Measure)
+	assert.Len(t, trends[1].Points, 2)
+	assert.Empty(t, trends[1].Points[0].Flag)
+	assert.Equal(t, domain.VitalFlagHigh, trends[1].Points[1].Flag)
+	m.vitalRepo.AssertExpectations(t)
+}